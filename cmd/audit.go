@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/spf13/cobra"
+)
+
+// errUnsupportedAuditFormat is returned for unknown audit report formats.
+var errUnsupportedAuditFormat = errors.New("unsupported audit report format")
+
+// ErrSeverityThreshold is returned when an audit reports findings at or above the --fail-on severity.
+var ErrSeverityThreshold = errors.New("findings at or above the severity threshold")
+
+var (
+	auditFormat string //nolint:gochecknoglobals // Cobra flag variable
+	auditOutput string //nolint:gochecknoglobals // Cobra flag variable
+	auditForce  bool   //nolint:gochecknoglobals // Cobra flag variable
+	auditFailOn string //nolint:gochecknoglobals // Cobra flag variable
+)
+
+// init registers the audit command with the root command for the CLI.
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().
+		StringVarP(&auditFormat, "format", "f", FormatMarkdown, "Output format for the audit report (markdown, json, yaml, html, sarif, junit, csv, xlsx)")
+	setFlagAnnotation(auditCmd.Flags(), "format", []string{"output"})
+
+	auditCmd.Flags().
+		StringVarP(&auditOutput, "output", "o", "", "Output file path for the audit report (default: print to console)")
+	setFlagAnnotation(auditCmd.Flags(), "output", []string{"output"})
+
+	auditCmd.Flags().BoolVar(&auditForce, "force", false, "Force overwrite existing files without prompting")
+	setFlagAnnotation(auditCmd.Flags(), "force", []string{"output"})
+
+	addTableFlag(auditCmd)
+	addSanitizeFlags(auditCmd)
+	addFilterLogFlag(auditCmd)
+
+	auditCmd.Flags().
+		StringVar(&auditFailOn, "fail-on", "", "Exit with an error if findings of this severity or higher remain (critical, high, medium, low, info)")
+	setFlagAnnotation(auditCmd.Flags(), "fail-on", []string{"output"})
+
+	addSharedAuditFlags(auditCmd)
+
+	auditCmd.Flags().SortFlags = false
+}
+
+var auditCmd = &cobra.Command{ //nolint:gochecknoglobals // Cobra command
+	Use:     "audit [file]",
+	Short:   "Generate a security audit report for an OPNsense configuration",
+	GroupID: "audit",
+	Long: `The 'audit' command analyses an OPNsense config.xml file and generates an
+audit report. The findings of the core security analysis are merged with the
+findings of the selected compliance plugins.
+
+  AUDIT MODES:
+    standard  - Configuration documentation followed by the audit findings (default)
+    blue      - Defensive report with findings, compliance results and
+                structured configuration tables
+    red       - Attacker-focused report with WAN-exposed services, weak NAT
+                rules, admin portals and enumeration targets
+
+  --blackhat-mode adds attacker commentary to red team reports.
+  --plugins selects the compliance plugins to run (stig, sans, firewall, cis).
+  --comprehensive adds the detailed configuration sections to the report.
+  --group-by-framework groups the results by NIST 800-53, ISO 27001 or plugin
+  control IDs using the cross-framework control mapping.
+  --plugin-dir loads external plugin executables, which are then selected with
+  --plugins like the built-in ones.
+  --waivers moves the findings matched by a waiver file to a "Waived Findings"
+  section; expired waivers are reported as findings.
+  --baseline compares the findings with a previous JSON report and classifies
+  them as new, unchanged or resolved by their fingerprint.
+  --fail-on exits with an error after writing the report if findings of the
+  given severity or higher remain after waivers. In JUnit reports, findings
+  below that severity do not fail their test case.
+  --filterlog annotates the firewall rules with hit counts from filterlog
+  exports and reports enabled rules that matched no traffic as removal
+  candidates.
+  --redact removes password hashes, keys and other secrets, and --anonymize
+  replaces public IPs, hostnames, domains and usernames with pseudonyms, in
+  every output format. --anonymize-map saves the pseudonyms for 'deanonymize'.
+
+The report can be rendered as markdown (default), JSON, YAML, a standalone
+HTML document, SARIF 2.1.0 for code scanning dashboards or JUnit XML for CI
+servers, with a test case for every plugin control and core check. The csv and
+xlsx formats export the findings and the configuration tables for spreadsheets;
+--table selects the tables.
+
+Examples:
+  # Generate a standard audit report
+  opnDossier audit config.xml
+
+  # Generate a blue team report with all compliance plugins
+  opnDossier audit config.xml --mode blue --plugins stig,sans,firewall
+
+  # Generate a red team recon report with attacker commentary
+  opnDossier audit config.xml --mode red --blackhat-mode
+
+  # Group the compliance results by NIST SP 800-53 controls
+  opnDossier audit config.xml --mode blue --plugins stig,cis --group-by-framework nist-800-53
+
+  # Run an external compliance plugin
+  opnDossier audit config.xml --mode blue --plugin-dir ~/.opnDossier/plugins --plugins site-policy
+
+  # Report accepted risks separately
+  opnDossier audit config.xml --mode blue --plugins sans --waivers waivers.yaml
+
+  # Report only the changes since last week's JSON report
+  opnDossier audit config.xml --mode blue --plugins stig --baseline last-week.json
+
+  # Save a blue team report as JSON
+  opnDossier audit config.xml --mode blue --plugins stig -f json -o audit.json
+
+  # Upload the findings to a code scanning dashboard
+  opnDossier audit config.xml --mode blue --plugins stig,sans -f sarif -o opndossier.sarif
+
+  # Save a blue team report as a standalone HTML document
+  opnDossier audit config.xml --mode blue --plugins stig,sans -f html -o audit.html
+
+  # Fail a CI pipeline on high and critical findings with a JUnit test report
+  opnDossier audit config.xml --mode blue --plugins stig,sans -f junit -o audit.junit.xml --fail-on high
+
+  # Share a report with a vendor without secrets or addressing
+  opnDossier audit config.xml --mode blue --plugins stig --redact --anonymize --anonymize-map mapping.json -o audit.md
+
+  # Report firewall rules that matched no traffic in the last day of logs
+  opnDossier audit config.xml --mode blue --filterlog filter_20240101.log
+
+  # Export the findings to a spreadsheet
+  opnDossier audit config.xml --mode blue --plugins stig,sans -f csv --table findings -o findings.csv
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		filePath := args[0]
+		ctxLogger := logger.WithContext(ctx).WithFields("input_file", filePath)
+
+		fileExt, err := auditFileExtension(auditFormat)
+		if err != nil {
+			return err
+		}
+
+		threshold, err := parseFailOn(auditFailOn)
+		if err != nil {
+			return err
+		}
+
+		anonymizer, err := newAnonymizer()
+		if err != nil {
+			return err
+		}
+
+		ruleLog, err := loadFilterLogs(ctx, filterLogFiles)
+		if err != nil {
+			return err
+		}
+
+		opnsense, err := parseConfigFile(ctx, filePath)
+		if err != nil {
+			return err
+		}
+
+		annotateRuleHits(opnsense, ruleLog, ctxLogger)
+		sanitizeDocument(opnsense, anonymizer, ctxLogger)
+
+		opts, err := buildAuditOptions()
+		if err != nil {
+			return err
+		}
+
+		opts.SourceFile = filePath
+		opts.DeadRuleCheck = ruleLog != nil
+		opts.SeverityThreshold = string(threshold)
+
+		pluginManager, err := newPluginManager(ctx, ctxLogger)
+		if err != nil {
+			return err
+		}
+
+		report, err := generateAuditReport(ctx, opnsense, opts, ctxLogger, pluginManager)
+		if err != nil {
+			return fmt.Errorf("failed to generate audit report from %s: %w", filePath, err)
+		}
+
+		if isTabularFormat(auditFormat) {
+			tables, err := buildTables(opnsense, report, sharedTables)
+			if err != nil {
+				return err
+			}
+
+			if err := exportTables(ctx, cmd.OutOrStdout(), tables, auditFormat, filePath, auditOutput, nil, auditForce); err != nil {
+				return fmt.Errorf("failed to export tables from %s: %w", filePath, err)
+			}
+
+			if err := saveAnonymizationMap(ctx, anonymizer, auditForce); err != nil {
+				return err
+			}
+
+			return checkSeverityThreshold(report, threshold)
+		}
+
+		output, err := renderAuditReport(report, opts)
+		if err != nil {
+			return fmt.Errorf("failed to generate audit report from %s: %w", filePath, err)
+		}
+
+		outputPath, err := determineOutputPath(filePath, auditOutput, fileExt, nil, auditForce)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path for %s: %w", filePath, err)
+		}
+
+		if outputPath == "" {
+			if _, err := fmt.Fprint(cmd.OutOrStdout(), output); err != nil {
+				return err
+			}
+		} else if err := export.NewFileExporter().Export(ctx, output, outputPath); err != nil {
+			return fmt.Errorf("failed to export audit report to %s: %w", outputPath, err)
+		}
+
+		if err := saveAnonymizationMap(ctx, anonymizer, auditForce); err != nil {
+			return err
+		}
+
+		return checkSeverityThreshold(report, threshold)
+	},
+}
+
+// parseFailOn parses the --fail-on severity. An empty value disables the threshold.
+func parseFailOn(value string) (processor.Severity, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	severity, err := audit.ParseSeverity(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid --fail-on value: %w", err)
+	}
+
+	return severity, nil
+}
+
+// checkSeverityThreshold returns ErrSeverityThreshold if the report has findings at or above the
+// threshold. Nothing fails without a threshold.
+func checkSeverityThreshold(report *audit.Report, threshold processor.Severity) error {
+	if threshold == "" {
+		return nil
+	}
+
+	findings := report.FindingsAtOrAbove(threshold)
+	if len(findings) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d %s or higher (worst: %s, %s)",
+		ErrSeverityThreshold, len(findings), threshold, findings[0].Severity, findings[0].Title)
+}
+
+// buildAuditOptions constructs the markdown options for the audit command from its flags and the configuration file.
+func buildAuditOptions() (markdown.Options, error) {
+	opt := markdown.DefaultOptions()
+	opt.Format = markdown.Format(strings.ToLower(auditFormat))
+	opt.AuditMode = markdown.AuditModeStandard
+
+	if sharedAuditMode != "" {
+		opt.AuditMode = markdown.AuditMode(strings.ToLower(sharedAuditMode))
+	}
+
+	if Cfg != nil && Cfg.GetTheme() != "" {
+		opt.Theme = markdown.Theme(Cfg.GetTheme())
+	}
+
+	opt.BlackhatMode = sharedBlackhatMode
+	opt.Comprehensive = sharedComprehensive
+	opt.SelectedPlugins = sharedSelectedPlugins
+
+	tunableBaseline, err := loadTunableBaseline("", Cfg)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.TunableBaseline = tunableBaseline
+
+	scoringEngine, err := buildScoringEngine(Cfg, tunableBaseline)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.ScoringEngine = scoringEngine
+
+	controlMapping, err := loadControlMapping(sharedControlMapping, Cfg)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.ControlMapping = controlMapping
+	opt.GroupByFramework = sharedGroupBy
+
+	waivers, err := loadWaivers(sharedWaiverFile, Cfg)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Waivers = waivers
+
+	b, err := loadBaseline(sharedBaselineFile)
+	if err != nil {
+		return opt, err
+	}
+
+	opt.Baseline = b
+
+	return opt, nil
+}
+
+// auditFileExtension returns the file extension for an audit report format.
+func auditFileExtension(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatMarkdown, "md":
+		return ".md", nil
+	case FormatJSON:
+		return ".json", nil
+	case FormatYAML, "yml":
+		return ".yaml", nil
+	case FormatHTML:
+		return ".html", nil
+	case FormatSARIF:
+		return ".sarif", nil
+	case FormatJUnit:
+		return ".junit.xml", nil
+	case FormatCSV:
+		return ".csv", nil
+	case FormatXLSX:
+		return ".xlsx", nil
+	default:
+		return "", fmt.Errorf("%w: %s", errUnsupportedAuditFormat, format)
+	}
+}
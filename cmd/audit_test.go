@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuditFileExtension tests the file extension of every audit report format.
+func TestAuditFileExtension(t *testing.T) {
+	extensions := map[string]string{
+		"markdown": ".md",
+		"json":     ".json",
+		"YAML":     ".yaml",
+		"html":     ".html",
+		"sarif":    ".sarif",
+		"junit":    ".junit.xml",
+		"csv":      ".csv",
+		"xlsx":     ".xlsx",
+	}
+
+	for format, expected := range extensions {
+		ext, err := auditFileExtension(format)
+		require.NoError(t, err, format)
+		assert.Equal(t, expected, ext, format)
+	}
+
+	_, err := auditFileExtension("pdf")
+	require.ErrorIs(t, err, errUnsupportedAuditFormat)
+}
+
+// TestCheckSeverityThreshold tests that --fail-on fails only on findings at or above the threshold.
+func TestCheckSeverityThreshold(t *testing.T) {
+	report := &audit.Report{Findings: []audit.Finding{
+		{Title: "Weak tunable", Severity: processor.SeverityLow},
+		{Title: "Insecure Web GUI Protocol", Severity: processor.SeverityHigh},
+	}}
+
+	threshold, err := parseFailOn("")
+	require.NoError(t, err)
+	require.NoError(t, checkSeverityThreshold(report, threshold), "no threshold never fails")
+
+	threshold, err = parseFailOn("critical")
+	require.NoError(t, err)
+	require.NoError(t, checkSeverityThreshold(report, threshold))
+
+	threshold, err = parseFailOn("HIGH")
+	require.NoError(t, err)
+
+	err = checkSeverityThreshold(report, threshold)
+	require.ErrorIs(t, err, ErrSeverityThreshold)
+	assert.Contains(t, err.Error(), "1 high or higher")
+	assert.Contains(t, err.Error(), "Insecure Web GUI Protocol")
+
+	_, err = parseFailOn("severe")
+	require.ErrorIs(t, err, audit.ErrInvalidSeverity)
+}
+
+// TestAuditCmd_FilterLog tests that rules without filterlog hits are reported as removal candidates.
+func TestAuditCmd_FilterLog(t *testing.T) {
+	// The core processor loads its templates relative to the project root
+	t.Chdir("..")
+	t.Cleanup(func() {
+		auditFormat = FormatMarkdown
+		filterLogFiles = []string{}
+		auditCmd.SetOut(nil)
+	})
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.xml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`<?xml version="1.0"?>
+<opnsense>
+  <system>
+    <hostname>fw</hostname>
+    <domain>example.com</domain>
+  </system>
+  <filter>
+    <rule uuid="fae55933-8f65-e11c-5366-9fc3642c93c2">
+      <type>pass</type>
+      <interface>lan</interface>
+      <descr>web</descr>
+      <log>1</log>
+      <source><network>lan</network></source>
+      <destination><any/></destination>
+    </rule>
+    <rule uuid="11111111-2222-3333-4444-555555555555">
+      <type>pass</type>
+      <interface>lan</interface>
+      <descr>legacy</descr>
+      <log>1</log>
+      <source><network>lan</network></source>
+      <destination><any/></destination>
+    </rule>
+  </filter>
+</opnsense>`), 0o600))
+
+	logFile := filepath.Join(dir, "filter.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("Mar  1 10:15:30 fw filterlog[1234]: "+
+		"82,,,fae559338f65e11c53669fc3642c93c2,vtnet0,match,pass,out,4,0x0,,64,0,0,DF,6,tcp,60,"+
+		"192.168.1.10,8.8.8.8,51234,443,0,S,123,,64240,,mss\n"), 0o600))
+
+	auditFormat = FormatJSON
+	filterLogFiles = []string{logFile}
+
+	var buf bytes.Buffer
+	auditCmd.SetOut(&buf)
+
+	require.NoError(t, auditCmd.RunE(auditCmd, []string{configFile}))
+
+	var report audit.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	var unused []audit.Finding
+
+	for _, finding := range report.Findings {
+		if slices.Contains(finding.Tags, "unused-rule") {
+			unused = append(unused, finding)
+		}
+	}
+
+	require.Len(t, unused, 1)
+	assert.Equal(t, "Rule Candidate for Removal", unused[0].Title)
+	assert.Equal(t, "filter.rule/11111111-2222-3333-4444-555555555555", unused[0].Object)
+}
@@ -12,24 +12,33 @@ import (
 	"sync"
 	"text/template"
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
-	// "github.com/EvilBit-Labs/opnDossier/internal/audit".
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
 	"github.com/EvilBit-Labs/opnDossier/internal/config"
 	"github.com/EvilBit-Labs/opnDossier/internal/constants"
 	"github.com/EvilBit-Labs/opnDossier/internal/converter"
 	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/filterlog"
+	"github.com/EvilBit-Labs/opnDossier/internal/htmlreport"
+	"github.com/EvilBit-Labs/opnDossier/internal/iac"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
 	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/ruleset"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/topology"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputFile string //nolint:gochecknoglobals // Cobra flag variable
-	format     string //nolint:gochecknoglobals // Output format (markdown, json, yaml)
-	force      bool   //nolint:gochecknoglobals // Force overwrite without prompt
+	outputFile     string   //nolint:gochecknoglobals // Cobra flag variable
+	format         string   //nolint:gochecknoglobals // Output format (markdown, json, yaml, html)
+	force          bool     //nolint:gochecknoglobals // Force overwrite without prompt
+	filterLogFiles []string //nolint:gochecknoglobals // Filterlog exports used for rule hit enrichment
+	sysctlBaseline string   //nolint:gochecknoglobals // Custom sysctl hardening baseline file
 )
 
 // TemplateCache provides thread-safe LRU caching for template instances.
@@ -122,13 +131,26 @@ var (
 	ErrUnsupportedAuditMode = errors.New("unsupported audit mode")
 	ErrFailedToEnrichConfig = errors.New("failed to enrich configuration")
 	ErrNoTemplateSpecified  = errors.New("no template specified")
+	// ErrUnsupportedOutputFormat is returned for output formats that the requested output does not support.
+	ErrUnsupportedOutputFormat = errors.New("unsupported output format")
 )
 
 // Format constants for output formats.
 const (
-	FormatMarkdown = "markdown"
-	FormatJSON     = "json"
-	FormatYAML     = "yaml"
+	FormatMarkdown  = "markdown"
+	FormatJSON      = "json"
+	FormatYAML      = "yaml"
+	FormatHTML      = "html"
+	FormatSARIF     = "sarif"
+	FormatJUnit     = "junit"
+	FormatCSV       = "csv"
+	FormatXLSX      = "xlsx"
+	FormatDOT       = "dot"
+	FormatSite      = "site"
+	FormatNftables  = "nftables"
+	FormatIptables  = "iptables"
+	FormatTerraform = "terraform"
+	FormatAnsible   = "ansible"
 )
 
 // DefaultTemplateCacheSize is the default maximum number of templates to cache in memory.
@@ -146,11 +168,20 @@ func init() {
 		StringVarP(&outputFile, "output", "o", "", "Output file path for saving converted configuration (default: print to console)")
 	setFlagAnnotation(convertCmd.Flags(), "output", []string{"output"})
 	convertCmd.Flags().
-		StringVarP(&format, "format", "f", "markdown", "Output format for conversion (markdown, json, yaml)")
+		StringVarP(&format, "format", "f", "markdown", "Output format for conversion (markdown, json, yaml, html, csv, xlsx, dot, site, nftables, iptables, terraform, ansible, sarif or junit with --mode)")
 	setFlagAnnotation(convertCmd.Flags(), "format", []string{"output"})
 	convertCmd.Flags().
 		BoolVar(&force, "force", false, "Force overwrite existing files without prompting for confirmation")
 	setFlagAnnotation(convertCmd.Flags(), "force", []string{"output"})
+	addTableFlag(convertCmd)
+	addSanitizeFlags(convertCmd)
+	addSiteFlags(convertCmd)
+
+	// Analysis flags
+	addFilterLogFlag(convertCmd)
+	convertCmd.Flags().
+		StringVar(&sysctlBaseline, "sysctl-baseline", "", "YAML file with a custom sysctl hardening baseline (default: built-in baseline)")
+	setFlagAnnotation(convertCmd.Flags(), "sysctl-baseline", []string{"analysis"})
 
 	// Add shared template flags
 	addSharedTemplateFlags(convertCmd)
@@ -188,8 +219,12 @@ and auditing of your firewall configuration.
     --legacy                        - Enable legacy template mode (deprecated)
 
   The convert command focuses on format transformation without validation.
-  TODO: Audit mode functionality is not yet complete and has been disabled.
+  --mode MODE: Generate an audit report (standard, blue, red) instead of plain documentation
+  --blackhat-mode: Add attacker commentary to red team reports
+  --plugins LIST: Run compliance plugins (stig, sans, firewall, cis) and merge their findings
   --comprehensive: Generate detailed, comprehensive reports
+  --redact: Remove password hashes, private keys, PSKs, API secrets and SNMP communities
+  --anonymize: Replace public IPs, hostnames, domains and usernames with keyed pseudonyms
 
   OUTPUT FORMATS:
   The convert command supports multiple output formats:
@@ -198,20 +233,31 @@ and auditing of your firewall configuration.
     markdown                    - Standard markdown report (default)
     json                        - JSON format output
     yaml                        - YAML format output
-
-  TODO: Audit mode functionality is not yet complete and has been disabled.
-  Use --format for basic output formats (markdown, json, yaml).
+    html                        - Standalone HTML report (works offline)
+    csv                         - Spreadsheet tables, one CSV file per table (select with --table)
+    xlsx                        - Spreadsheet workbook with one sheet per table
+    dot                         - Graphviz DOT diagram of the network topology
+    site                        - Directory of linked markdown pages (requires --output, see --mkdocs)
+    nftables                    - nftables ruleset translated from the filter and NAT rules
+    iptables                    - iptables-restore file (IPv4) translated from the filter and NAT rules
+    terraform                   - Terraform resources for the browningluke/opnsense provider
+    ansible                     - Ansible variables for the ansibleguy.opnsense collection
+    sarif                       - SARIF 2.1.0 findings (requires --mode)
+    junit                       - JUnit XML test report of findings (requires --mode)
+
+  Audit reports (--mode) are rendered in the selected --format as well.
+  The 'audit' command provides the same audit workflow as a dedicated command.
 
 The convert command focuses on conversion only and does not perform validation.
 To validate your configuration files before conversion, use the 'validate' command.
 
 You can either print the generated output directly to the console or save it to a
 specified output file using the '--output' or '-o' flag. Use the '--format' or '-f'
-flag to specify the output format (markdown, json, or yaml).
+flag to specify the output format (markdown, json, yaml or html).
 
 When processing multiple files, the --output flag will be ignored, and each output
 file will be named based on its input file with the appropriate extension
-(e.g., config.xml -> config.md, config.json, or config.yaml).
+(e.g., config.xml -> config.md, config.json, config.yaml or config.html).
 
 Examples:
   # Convert using programmatic mode (default, fastest)
@@ -227,6 +273,30 @@ Examples:
   # Convert 'my_config.xml' to YAML and save to file
   opnDossier convert my_config.xml -f yaml -o documentation.yaml
 
+  # Save a standalone HTML report with sortable rule tables
+  opnDossier convert my_config.xml -f html -o report.html
+
+  # Export the firewall rules to a spreadsheet
+  opnDossier convert my_config.xml -f csv --table rules -o rules.csv
+
+  # Export all tables, including the audit findings, to a workbook
+  opnDossier convert my_config.xml -f xlsx --mode blue -o audit.xlsx
+
+  # Redact secrets and anonymize addressing, saving the pseudonyms to reverse them later
+  opnDossier convert my_config.xml --redact --anonymize --anonymize-map mapping.json -o shared.md
+
+  # Write a documentation site with a page per interface's rules and an MkDocs navigation
+  opnDossier convert my_config.xml -f site --mkdocs -o firewall-docs
+
+  # Translate the filter and NAT rules into an nftables ruleset for a Linux firewall
+  opnDossier convert my_config.xml -f nftables -o firewall.nft
+
+  # Export the aliases, rules, NAT, VLANs and Unbound overrides as Terraform resources
+  opnDossier convert my_config.xml -f terraform -o firewall.tf
+
+  # Draw the network topology with Graphviz
+  opnDossier convert my_config.xml -f dot -o topology.dot && dot -Tsvg topology.dot -o topology.svg
+
   # Generate comprehensive report (programmatic mode)
   opnDossier convert my_config.xml --comprehensive
 
@@ -239,15 +309,14 @@ Examples:
   # Legacy template mode (deprecated, will show warning)
   opnDossier convert my_config.xml --legacy
 
-  # TODO: Audit mode functionality is not yet complete and has been disabled
-  # # Generate blue team audit report
-  # opnDossier convert my_config.xml --mode blue --comprehensive
+  # Generate blue team audit report
+  opnDossier convert my_config.xml --mode blue --comprehensive
 
-  # # Generate red team recon report with blackhat mode
-  # opnDossier convert my_config.xml --mode red --blackhat-mode
+  # Generate red team recon report with blackhat mode
+  opnDossier convert my_config.xml --mode red --blackhat-mode
 
-  # # Run compliance checks with specific plugins
-  # opnDossier convert my_config.xml --mode blue --plugins stig,sans
+  # Run compliance checks with specific plugins
+  opnDossier convert my_config.xml --mode blue --plugins stig,sans
 
   # Convert with specific sections
   opnDossier convert my_config.xml --section system,network
@@ -270,6 +339,12 @@ Examples:
   # Include all system tunables (including defaults) in the report
   opnDossier convert config.xml --include-tunables
 
+  # Annotate firewall rules with hit counts from filterlog exports
+  opnDossier convert config.xml --filterlog filter_20240101.log --filterlog filter_20231231.log.gz
+
+  # Compare sysctl tunables with a custom hardening baseline
+  opnDossier convert config.xml --sysctl-baseline my-baseline.yaml
+
   # Validate before converting (recommended workflow)
   opnDossier validate config.xml && opnDossier convert config.xml -f json -o output.json
 
@@ -301,6 +376,61 @@ Examples:
 			}
 		}
 
+		// Load filterlog data once so every configuration is annotated from the same window
+		ruleLog, err := loadFilterLogs(timeoutCtx, filterLogFiles)
+		if err != nil {
+			return err
+		}
+
+		// Load the sysctl hardening baseline once; nil selects the built-in baseline
+		tunableBaseline, err := loadTunableBaseline(sysctlBaseline, Cfg)
+		if err != nil {
+			return err
+		}
+
+		// Build the scoring engine once, applying catalogue overrides from the config file
+		scoringEngine, err := buildScoringEngine(Cfg, tunableBaseline)
+		if err != nil {
+			return err
+		}
+
+		// Load the control mapping once; nil selects the built-in mapping
+		controlMapping, err := loadControlMapping(sharedControlMapping, Cfg)
+		if err != nil {
+			return err
+		}
+
+		// Load the waivers once; nil waives nothing
+		waivers, err := loadWaivers(sharedWaiverFile, Cfg)
+		if err != nil {
+			return err
+		}
+
+		// Load the baseline once; nil classifies nothing
+		baselineReport, err := loadBaseline(sharedBaselineFile)
+		if err != nil {
+			return err
+		}
+
+		// Create the anonymizer once so that pseudonyms are consistent across all files
+		anonymizer, err := newAnonymizer()
+		if err != nil {
+			return err
+		}
+
+		// Initialize the compliance plugins once when an audit report is requested
+		var pluginManager *audit.PluginManager
+		if sharedAuditMode != "" {
+			pluginManager, err = newPluginManager(timeoutCtx, logger)
+			if err != nil {
+				return err
+			}
+
+			if err := validatePluginSelection(timeoutCtx, pluginManager, sharedSelectedPlugins); err != nil {
+				return err
+			}
+		}
+
 		// Preload the custom template if specified
 		var cachedTemplate *template.Template
 		if sharedCustomTemplate != "" {
@@ -369,9 +499,20 @@ Examples:
 				}
 				ctxLogger.Debug("XML parsing completed successfully")
 
+				annotateRuleHits(opnsense, ruleLog, ctxLogger)
+
+				sanitizeDocument(opnsense, anonymizer, ctxLogger)
+
 				// Build options for conversion with precedence: CLI flags > env vars > config > defaults
 				eff := buildEffectiveFormat(format, Cfg)
 				opt := buildConversionOptions(eff, Cfg)
+				opt.ScoringEngine = scoringEngine
+				opt.TunableBaseline = tunableBaseline
+				opt.DeadRuleCheck = ruleLog != nil
+				opt.ControlMapping = controlMapping
+				opt.Waivers = waivers
+				opt.Baseline = baselineReport
+				opt.SourceFile = fp
 
 				// Convert using the new markdown generator
 				var output string
@@ -387,26 +528,43 @@ Examples:
 					opt.Sections,
 				)
 
-				// TODO: Audit mode functionality is not yet complete - disabled for now
-				// Handle audit mode if specified
-				// if opt.AuditMode != "" {
-				// 	// Create plugin registry for audit mode
-				// 	registry := audit.NewPluginRegistry()
-				// 	output, err = handleAuditMode(timeoutCtx, opnsense, opt, ctxLogger, registry)
-				// 	if err != nil {
-				// 		ctxLogger.Error("Failed to generate audit report", "error", err)
-				// 		errs <- fmt.Errorf("failed to generate audit report from %s: %w", fp, err)
-				// 		return
-				// 	}
-				// } else {
-				// Generate output based on format using the cached template
-				output, err = generateOutputByFormat(timeoutCtx, opnsense, opt, ctxLogger, cachedTemplate)
-				if err != nil {
-					ctxLogger.Error("Failed to convert", "error", err)
-					errs <- fmt.Errorf("failed to convert from %s: %w", fp, err)
+				// Write a directory of linked pages instead of a single report
+				if strings.EqualFold(string(opt.Format), FormatSite) {
+					if err := convertToSite(timeoutCtx, fp, opnsense, opt, ctxLogger, pluginManager, len(args) > 1); err != nil {
+						ctxLogger.Error("Failed to write documentation site", "error", err)
+						errs <- fmt.Errorf("failed to write documentation site from %s: %w", fp, err)
+					}
+
 					return
 				}
-				// }
+
+				// Export tables for spreadsheets instead of a report
+				if isTabularFormat(string(opt.Format)) {
+					if err := convertToTables(timeoutCtx, fp, opnsense, opt, ctxLogger, pluginManager); err != nil {
+						ctxLogger.Error("Failed to export tables", "error", err)
+						errs <- fmt.Errorf("failed to export tables from %s: %w", fp, err)
+					}
+
+					return
+				}
+
+				// Handle audit mode if specified
+				if opt.AuditMode != "" {
+					output, err = handleAuditMode(timeoutCtx, opnsense, opt, ctxLogger, pluginManager)
+					if err != nil {
+						ctxLogger.Error("Failed to generate audit report", "error", err)
+						errs <- fmt.Errorf("failed to generate audit report from %s: %w", fp, err)
+						return
+					}
+				} else {
+					// Generate output based on format using the cached template
+					output, err = generateOutputByFormat(timeoutCtx, opnsense, opt, ctxLogger, cachedTemplate)
+					if err != nil {
+						ctxLogger.Error("Failed to convert", "error", err)
+						errs <- fmt.Errorf("failed to convert from %s: %w", fp, err)
+						return
+					}
+				}
 
 				// Determine file extension based on format
 				switch strings.ToLower(string(opt.Format)) {
@@ -416,6 +574,22 @@ Examples:
 					fileExt = ".json"
 				case "yaml", "yml":
 					fileExt = ".yaml"
+				case FormatHTML:
+					fileExt = ".html"
+				case FormatSARIF:
+					fileExt = ".sarif"
+				case FormatJUnit:
+					fileExt = ".junit.xml"
+				case FormatDOT:
+					fileExt = ".dot"
+				case FormatNftables:
+					fileExt = ".nft"
+				case FormatIptables:
+					fileExt = ".rules"
+				case FormatTerraform:
+					fileExt = ".tf"
+				case FormatAnsible:
+					fileExt = ".yml"
 				default:
 					fileExt = ".md" // Default to markdown
 				}
@@ -469,10 +643,113 @@ Examples:
 			}
 		}
 
+		// Save the pseudonyms of every file that was anonymized, even if other files failed
+		if err := saveAnonymizationMap(timeoutCtx, anonymizer, force); err != nil {
+			if allErrors == nil {
+				allErrors = err
+			} else {
+				allErrors = fmt.Errorf("%w; %w", allErrors, err)
+			}
+		}
+
 		return allErrors
 	},
 }
 
+// addFilterLogFlag adds the filterlog export flag, which the convert and audit commands share.
+func addFilterLogFlag(cmd *cobra.Command) {
+	cmd.Flags().
+		StringSliceVar(&filterLogFiles, "filterlog", []string{}, "OPNsense filterlog export(s) (plain or gzip) used to annotate rules with hit counts")
+	setFlagAnnotation(cmd.Flags(), "filterlog", []string{"analysis"})
+}
+
+// loadFilterLogs parses the given filterlog files, returning nil when no files are specified.
+func loadFilterLogs(ctx context.Context, paths []string) (*filterlog.Log, error) {
+	if len(paths) == 0 {
+		return nil, nil //nolint:nilnil // no filterlog data requested
+	}
+
+	ruleLog, err := filterlog.NewParser().ParseFiles(ctx, paths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load filterlog data: %w", err)
+	}
+
+	logger.Debug("Loaded filterlog data", "files", len(paths), "entries", len(ruleLog.Entries), "skipped", ruleLog.Skipped)
+
+	return ruleLog, nil
+}
+
+// annotateRuleHits stores the filterlog hits of each filter rule in doc. Nothing is annotated
+// when no filterlog data was loaded.
+func annotateRuleHits(doc *model.OpnSenseDocument, ruleLog *filterlog.Log, ctxLogger *log.Logger) {
+	if ruleLog == nil {
+		return
+	}
+
+	summary := filterlog.Annotate(doc, ruleLog, filterlog.DefaultTopTalkers)
+	ctxLogger.Debug(
+		"Annotated rules with filterlog hits",
+		"entries",
+		summary.Entries,
+		"attributed",
+		summary.Attributed,
+		"unattributed",
+		summary.Unattributed,
+		"unused_rules",
+		summary.UnusedRules,
+		"unlogged_rules",
+		summary.UnloggedRules,
+	)
+}
+
+// buildScoringEngine creates the security scoring engine, applying control overrides from the configuration.
+// The engine scores the findings of the processor security analysis run against baseline.
+func buildScoringEngine(cfg *config.Config, baseline *tunables.Baseline) (*scoring.Engine, error) {
+	var overrides []scoring.Override
+
+	if cfg != nil {
+		for _, control := range cfg.GetScoringControls() {
+			overrides = append(overrides, scoring.Override{
+				ID:      control.ID,
+				Weight:  control.Weight,
+				Enabled: control.Enabled,
+			})
+		}
+	}
+
+	engine, err := scoring.NewEngine(
+		scoring.WithOverrides(overrides...),
+		scoring.WithAnalyzer(processor.ScoringAnalyzer(baseline)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring configuration: %w", err)
+	}
+
+	return engine, nil
+}
+
+// loadTunableBaseline loads the sysctl hardening baseline named by the CLI flag or, failing that,
+// the configuration file. It returns nil when neither is set so that the built-in baseline is used.
+func loadTunableBaseline(flagPath string, cfg *config.Config) (*tunables.Baseline, error) {
+	path := flagPath
+	if path == "" && cfg != nil {
+		path = cfg.GetSysctlBaseline()
+	}
+
+	if path == "" {
+		return nil, nil //nolint:nilnil // built-in baseline requested
+	}
+
+	baseline, err := tunables.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sysctl baseline: %w", err)
+	}
+
+	logger.Debug("Loaded sysctl baseline", "file", path, "name", baseline.Name, "tunables", len(baseline.Tunables))
+
+	return baseline, nil
+}
+
 // buildEffectiveFormat returns the output format to use, giving precedence to the CLI flag, then the configuration file, and defaulting to "markdown" if neither is set.
 func buildEffectiveFormat(flagFormat string, cfg *config.Config) string {
 	// CLI flag takes precedence
@@ -525,24 +802,24 @@ func buildConversionOptions(
 		opt.WrapWidth = cfg.GetWrapWidth()
 	}
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
-	// Audit mode: CLI flag > config > default
-	// if sharedAuditMode != "" {
-	// 	opt.AuditMode = markdown.AuditMode(sharedAuditMode)
-	// }
+	// Audit mode: CLI flag only
+	if sharedAuditMode != "" {
+		opt.AuditMode = markdown.AuditMode(sharedAuditMode)
+	}
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
 	// Blackhat mode: CLI flag only
-	// opt.BlackhatMode = sharedBlackhatMode
+	opt.BlackhatMode = sharedBlackhatMode
 
 	// Comprehensive: CLI flag only
 	opt.Comprehensive = sharedComprehensive
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
 	// Selected plugins: CLI flag only
-	// if len(sharedSelectedPlugins) > 0 {
-	// 	opt.SelectedPlugins = sharedSelectedPlugins
-	// }
+	if len(sharedSelectedPlugins) > 0 {
+		opt.SelectedPlugins = sharedSelectedPlugins
+	}
+
+	// Framework grouping: CLI flag only
+	opt.GroupByFramework = sharedGroupBy
 
 	// Template directory: CLI flag only
 	templateDir := getSharedTemplateDir()
@@ -671,6 +948,24 @@ func generateOutputByFormat(
 		// Set the format in options
 		opt.Format = markdown.Format(format)
 		return generator.Generate(ctx, opnsense, opt)
+	case FormatHTML:
+		// Render the markdown report as HTML so that both formats contain the same sections
+		opt.Format = markdown.FormatMarkdown
+
+		content, err := generateWithHybridGenerator(ctx, opnsense, opt, logger, preParsedTemplate)
+		if err != nil {
+			return "", err
+		}
+
+		return renderHTML(content, opt)
+	case FormatDOT:
+		return topology.Build(opnsense).DOT(), nil
+	case FormatNftables, FormatIptables:
+		return generateRuleset(opnsense, format, logger)
+	case FormatTerraform, FormatAnsible:
+		return generateIaC(opnsense, format, logger)
+	case FormatSARIF, FormatJUnit:
+		return "", fmt.Errorf("%w: %s output requires an audit mode (--mode)", ErrUnsupportedOutputFormat, format)
 	default:
 		// Default to markdown for unknown formats
 		logger.Warn("Unknown format, defaulting to markdown", "format", format)
@@ -678,6 +973,85 @@ func generateOutputByFormat(
 	}
 }
 
+// convertToTables exports the selected tables of a configuration as CSV or XLSX. The findings
+// table is added when an audit mode is set.
+func convertToTables(
+	ctx context.Context,
+	inputFile string,
+	opnsense *model.OpnSenseDocument,
+	opt markdown.Options,
+	logger *log.Logger,
+	manager *audit.PluginManager,
+) error {
+	var report *audit.Report
+
+	if opt.AuditMode != "" {
+		var err error
+
+		report, err = generateAuditReport(ctx, opnsense, opt, logger, manager)
+		if err != nil {
+			return err
+		}
+	}
+
+	tables, err := buildTables(opnsense, report, sharedTables)
+	if err != nil {
+		return err
+	}
+
+	return exportTables(ctx, os.Stdout, tables, string(opt.Format), inputFile, outputFile, Cfg, force)
+}
+
+// generateRuleset translates the filter and NAT rules into an nftables ruleset or an
+// iptables-restore file and logs the constructs that have no netfilter equivalent.
+func generateRuleset(opnsense *model.OpnSenseDocument, format string, logger *log.Logger) (string, error) {
+	translate := ruleset.Nftables
+	if format == FormatIptables {
+		translate = ruleset.Iptables
+	}
+
+	result, err := translate(opnsense)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate %s ruleset: %w", format, err)
+	}
+
+	for _, warning := range result.Warnings {
+		logger.Warn("Ruleset translation", "object", warning.Object, "warning", warning.Message)
+	}
+
+	return result.Content, nil
+}
+
+// generateIaC exports the configuration as Terraform resources or Ansible variables and logs the
+// objects that the export leaves out.
+func generateIaC(opnsense *model.OpnSenseDocument, format string, logger *log.Logger) (string, error) {
+	export := iac.Terraform
+	if format == FormatAnsible {
+		export = iac.Ansible
+	}
+
+	result, err := export(opnsense)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate %s export: %w", format, err)
+	}
+
+	for _, unsupported := range result.Unsupported {
+		logger.Warn("Not exported", "object", unsupported.Object, "reason", unsupported.Reason)
+	}
+
+	return result.Content, nil
+}
+
+// renderHTML renders a markdown report as a standalone HTML document in the configured theme.
+func renderHTML(content string, opt markdown.Options) (string, error) {
+	output, err := htmlreport.Render(content, htmlreport.Options{Theme: string(opt.Theme)})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	return output, nil
+}
+
 // generateWithHybridGenerator creates a hybrid generator and generates output using either
 // programmatic generation (default) or template generation based on options.
 // If a pre-parsed template is provided, it will be used instead of loading from file.
@@ -693,6 +1067,8 @@ func generateWithHybridGenerator(
 
 	// Create the programmatic builder
 	builder := converter.NewMarkdownBuilder()
+	builder.SetScoringEngine(opt.ScoringEngine)
+	builder.SetTunableBaseline(opt.TunableBaseline)
 
 	// Create hybrid generator
 	hybridGen, err := markdown.NewHybridGenerator(builder, logger)
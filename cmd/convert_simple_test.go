@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/config"
@@ -97,6 +98,46 @@ func TestGenerateOutputByFormatSimple(t *testing.T) {
 		t.Logf("JSON format failed as expected: %v", err)
 	}
 
+	// Test HTML format, which is rendered from the markdown report
+	opt.Format = markdown.FormatHTML
+	result, err = generateOutputByFormat(ctx, opnsense, opt, logger, nil)
+	if err != nil {
+		t.Errorf("Unexpected error for html: %v", err)
+	}
+	if !strings.HasPrefix(result, "<!DOCTYPE html>") || !strings.Contains(result, "test-firewall") {
+		t.Errorf("Expected an HTML document with the hostname for html")
+	}
+
+	// Test DOT format, which draws the network topology
+	opt.Format = markdown.FormatDOT
+	result, err = generateOutputByFormat(ctx, opnsense, opt, logger, nil)
+	if err != nil {
+		t.Errorf("Unexpected error for dot: %v", err)
+	}
+	if !strings.HasPrefix(result, "digraph topology {") {
+		t.Errorf("Expected a Graphviz digraph for dot, got: %s", result)
+	}
+
+	// Test nftables format, which translates the filter and NAT rules
+	opt.Format = markdown.FormatNftables
+	result, err = generateOutputByFormat(ctx, opnsense, opt, logger, nil)
+	if err != nil {
+		t.Errorf("Unexpected error for nftables: %v", err)
+	}
+	if !strings.Contains(result, "table inet opndossier {") {
+		t.Errorf("Expected an nftables table for nftables, got: %s", result)
+	}
+
+	// Test Terraform format, which exports the configuration as provider resources
+	opt.Format = markdown.FormatTerraform
+	result, err = generateOutputByFormat(ctx, opnsense, opt, logger, nil)
+	if err != nil {
+		t.Errorf("Unexpected error for terraform: %v", err)
+	}
+	if !strings.Contains(result, `source = "browningluke/opnsense"`) {
+		t.Errorf("Expected the OPNsense provider for terraform, got: %s", result)
+	}
+
 	// Test unknown format (should default to markdown)
 	opt.Format = markdown.Format("unknown")
 	result, err = generateOutputByFormat(ctx, opnsense, opt, logger, nil)
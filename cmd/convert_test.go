@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -15,7 +16,7 @@ import (
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
 	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
-	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -409,6 +410,12 @@ func TestConvertAuditModeToReportMode(t *testing.T) {
 		expected    audit.ReportMode
 		expectError bool
 	}{
+		{
+			name:        "empty mode defaults to standard",
+			auditMode:   "",
+			expected:    audit.ModeStandard,
+			expectError: false,
+		},
 		{
 			name:        "standard mode",
 			auditMode:   markdown.AuditModeStandard,
@@ -437,11 +444,15 @@ func TestConvertAuditModeToReportMode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := convertAuditModeToReportMode(tt.auditMode)
+			result, err := convertAuditModeToReportMode(tt.auditMode)
 
-			// TODO: Audit mode functionality is not yet complete - disabled for now
-			require.Error(t, err)
-			assert.Contains(t, err.Error(), "audit mode functionality is not yet implemented")
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrUnsupportedAuditMode)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
@@ -479,163 +490,44 @@ func TestCreateModeConfig(t *testing.T) {
 				BlackhatMode:  true,
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := createModeConfig(tt.reportMode, tt.opts)
-
-			// TODO: Audit mode functionality is not yet complete - disabled for now
-			// All audit mode functions return empty/default values
-			assert.Equal(t, audit.ReportMode(""), result.Mode)
-			assert.False(t, result.Comprehensive)
-			assert.False(t, result.BlackhatMode)
-		})
-	}
-}
-
-func TestCreateAuditMarkdownOptions(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    markdown.Options
-		expected markdown.Options
-	}{
-		{
-			name: "basic options",
-			input: markdown.Options{
-				Format:        markdown.FormatMarkdown,
-				TemplateName:  "standard",
-				Theme:         markdown.ThemeLight,
-				WrapWidth:     80,
-				Comprehensive: true,
-			},
-			expected: markdown.Options{
-				Format:        markdown.FormatMarkdown,
-				TemplateName:  "",
-				Theme:         markdown.ThemeAuto,
-				WrapWidth:     0,
-				Comprehensive: true,
-			},
-		},
-		{
-			name: "with audit mode",
-			input: markdown.Options{
-				Format:       markdown.FormatMarkdown,
-				AuditMode:    markdown.AuditModeBlue,
-				BlackhatMode: true,
-			},
-			expected: markdown.Options{
-				Format:       markdown.FormatMarkdown,
-				AuditMode:    markdown.AuditModeBlue,
-				BlackhatMode: false,
-				Theme:        markdown.ThemeAuto,
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := createAuditMarkdownOptions(tt.input)
-
-			// TODO: Audit mode functionality is not yet complete - disabled for now
-			// All audit mode functions return empty/default values
-			assert.Equal(t, markdown.Format(""), result.Format)
-			assert.Empty(t, result.TemplateName)
-			assert.Equal(t, markdown.Theme(""), result.Theme)
-			assert.Equal(t, 0, result.WrapWidth)
-			assert.False(t, result.Comprehensive)
-			assert.Equal(t, markdown.AuditMode(""), result.AuditMode)
-			assert.False(t, result.BlackhatMode)
-		})
-	}
-}
-
-func TestAppendAuditFindings(t *testing.T) {
-	tests := []struct {
-		name         string
-		baseResult   string
-		auditReport  *audit.Report
-		expected     string
-		containsText []string
-	}{
 		{
-			name:       "empty audit report",
-			baseResult: "# Test Report\n\nContent here",
-			auditReport: &audit.Report{
-				Findings: []audit.Finding{},
-			},
-			expected: "# Test Report\n\nContent here",
-		},
-		{
-			name:       "report with findings",
-			baseResult: "# Test Report\n\nContent here",
-			auditReport: &audit.Report{
-				Findings: []audit.Finding{
-					{
-						Title:       "Test Finding",
-						Severity:    processor.SeverityHigh,
-						Description: "Test description",
-					},
-				},
+			name:       "blue mode with plugins",
+			reportMode: audit.ModeBlue,
+			opts: markdown.Options{
+				SelectedPlugins: []string{"stig", "sans"},
 			},
-			containsText: []string{
-				"# Test Report",
-				"Content here",
-				"## Audit Findings Summary",
-				"Test Finding",
-				"high",
-				"Test description",
+			expected: &audit.ModeConfig{
+				Mode:            audit.ModeBlue,
+				SelectedPlugins: []string{"stig", "sans"},
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := appendAuditFindings(tt.baseResult, tt.auditReport)
-
-			// TODO: Audit mode functionality is not yet complete - disabled for now
-			// appendAuditFindings just returns the base result unchanged
-			assert.Equal(t, tt.baseResult, result)
+			assert.Equal(t, tt.expected, createModeConfig(tt.reportMode, tt.opts))
 		})
 	}
-}
 
-func TestGenerateBaseAuditReport(t *testing.T) {
-	// Create a minimal test configuration
-	testConfig := &model.OpnSenseDocument{
-		System: model.System{
-			Hostname: "test-firewall",
-			Domain:   "example.com",
-		},
-	}
-
-	opts := markdown.Options{
-		Format:        markdown.FormatMarkdown,
-		AuditMode:     markdown.AuditModeStandard,
-		Comprehensive: true,
-	}
+	t.Run("processor options", func(t *testing.T) {
+		result := createModeConfig(audit.ModeBlue, markdown.Options{ScoringEngine: scoring.Default()})
+		assert.Len(t, result.ProcessorOptions, 1)
 
-	logger, err := log.New(log.Config{})
-	if err != nil {
-		t.Fatalf("Failed to create logger: %v", err)
-	}
-
-	t.Run("successful report generation", func(t *testing.T) {
-		ctx := context.Background()
-		_, err := generateBaseAuditReport(ctx, testConfig, opts, logger)
-
-		// TODO: Audit mode functionality is not yet complete - disabled for now
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "audit mode functionality is not yet implemented")
+		result = createModeConfig(audit.ModeBlue, markdown.Options{DeadRuleCheck: true})
+		assert.Len(t, result.ProcessorOptions, 1)
 	})
 }
 
 func TestHandleAuditMode(t *testing.T) {
+	// The core processor loads its templates relative to the project root
+	t.Chdir("..")
+
 	// Create a minimal test configuration
 	testConfig := &model.OpnSenseDocument{
 		System: model.System{
 			Hostname: "test-firewall",
 			Domain:   "example.com",
+			WebGUI:   model.WebGUIConfig{Protocol: "http"},
 		},
 	}
 
@@ -646,42 +538,76 @@ func TestHandleAuditMode(t *testing.T) {
 	}
 
 	logger, err := log.New(log.Config{})
-	if err != nil {
-		t.Fatalf("Failed to create logger: %v", err)
-	}
-	registry := audit.NewPluginRegistry()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	manager, err := newPluginManager(ctx, logger)
+	require.NoError(t, err)
 
 	t.Run("standard audit mode", func(t *testing.T) {
-		ctx := context.Background()
-		_, err := handleAuditMode(ctx, testConfig, opts, logger, registry)
+		output, err := handleAuditMode(ctx, testConfig, opts, logger, manager)
+		require.NoError(t, err)
 
-		// TODO: Audit mode functionality is not yet complete - disabled for now
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "audit mode functionality is not yet implemented")
+		assert.Contains(t, output, "# OPNsense Configuration Summary")
+		assert.Contains(t, output, "## Audit Findings Summary")
 	})
 
 	t.Run("blue audit mode", func(t *testing.T) {
-		ctx := context.Background()
 		blueOpts := opts
 		blueOpts.AuditMode = markdown.AuditModeBlue
+		blueOpts.SelectedPlugins = []string{"stig", "sans", "firewall"}
 
-		_, err := handleAuditMode(ctx, testConfig, blueOpts, logger, registry)
+		output, err := handleAuditMode(ctx, testConfig, blueOpts, logger, manager)
+		require.NoError(t, err)
 
-		// TODO: Audit mode functionality is not yet complete - disabled for now
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "audit mode functionality is not yet implemented")
+		assert.Contains(t, output, "# OPNsense Blue Team Audit Report")
+		assert.Contains(t, output, "## Compliance Results")
+		assert.Contains(t, output, "firewall, sans, stig")
 	})
 
 	t.Run("red audit mode", func(t *testing.T) {
-		ctx := context.Background()
 		redOpts := opts
 		redOpts.AuditMode = markdown.AuditModeRed
+		redOpts.BlackhatMode = true
+
+		output, err := handleAuditMode(ctx, testConfig, redOpts, logger, manager)
+		require.NoError(t, err)
+
+		assert.Contains(t, output, "# OPNsense Red Team Recon Report")
+		assert.Contains(t, output, "Web GUI Served Over Plain HTTP")
+		assert.Contains(t, output, "Bring a sniffer.")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		jsonOpts := opts
+		jsonOpts.Format = markdown.FormatJSON
+		jsonOpts.AuditMode = markdown.AuditModeBlue
+		jsonOpts.SelectedPlugins = []string{"firewall"}
+
+		output, err := handleAuditMode(ctx, testConfig, jsonOpts, logger, manager)
+		require.NoError(t, err)
+
+		var report audit.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		assert.Equal(t, audit.ModeBlue, report.Mode)
+		assert.NotEmpty(t, report.Findings)
+	})
+
+	t.Run("unknown plugin", func(t *testing.T) {
+		badOpts := opts
+		badOpts.SelectedPlugins = []string{"pci"}
+
+		_, err := handleAuditMode(ctx, testConfig, badOpts, logger, manager)
+		require.ErrorIs(t, err, ErrUnknownPlugin)
+		assert.Contains(t, err.Error(), "firewall, sans, stig")
+	})
 
-		_, err := handleAuditMode(ctx, testConfig, redOpts, logger, registry)
+	t.Run("invalid mode", func(t *testing.T) {
+		badOpts := opts
+		badOpts.AuditMode = "purple"
 
-		// TODO: Audit mode functionality is not yet complete - disabled for now
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "audit mode functionality is not yet implemented")
+		_, err := handleAuditMode(ctx, testConfig, badOpts, logger, manager)
+		require.ErrorIs(t, err, ErrUnsupportedAuditMode)
 	})
 }
 
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/sanitize"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deanonymizeMap    string //nolint:gochecknoglobals // Cobra flag variable
+	deanonymizeOutput string //nolint:gochecknoglobals // Cobra flag variable
+	deanonymizeForce  bool   //nolint:gochecknoglobals // Cobra flag variable
+)
+
+// init registers the deanonymize command with the root command for the CLI.
+func init() {
+	rootCmd.AddCommand(deanonymizeCmd)
+
+	deanonymizeCmd.Flags().
+		StringVarP(&deanonymizeMap, "map", "m", "", "Anonymization mapping saved with --anonymize-map")
+	setFlagAnnotation(deanonymizeCmd.Flags(), "map", []string{"configuration"})
+
+	deanonymizeCmd.Flags().
+		StringVarP(&deanonymizeOutput, "output", "o", "", "Output file path (default: standard output)")
+	setFlagAnnotation(deanonymizeCmd.Flags(), "output", []string{"output"})
+
+	deanonymizeCmd.Flags().BoolVar(&deanonymizeForce, "force", false, "Force overwrite existing files")
+	setFlagAnnotation(deanonymizeCmd.Flags(), "force", []string{"output"})
+
+	if err := deanonymizeCmd.MarkFlagRequired("map"); err != nil {
+		panic(fmt.Sprintf("failed to mark map flag as required: %v", err))
+	}
+}
+
+var deanonymizeCmd = &cobra.Command{ //nolint:gochecknoglobals // Cobra command
+	Use:     "deanonymize [file]",
+	Short:   "Restore the original values in an anonymized report",
+	GroupID: "utility",
+	Long: `The 'deanonymize' command replaces the pseudonyms in a report generated with
+--anonymize by the original IP addresses, hostnames, domains and usernames.
+It needs the mapping saved with --anonymize-map during that run. Redacted
+secrets cannot be restored.
+
+Examples:
+  # Anonymize a report and keep the mapping
+  opnDossier convert config.xml --anonymize --anonymize-map mapping.json -o shared.md
+
+  # Restore the original values in the report, or in a reply quoting it
+  opnDossier deanonymize shared.md --map mapping.json -o restored.md
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		mapping, err := loadAnonymizationMap(deanonymizeMap)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filepath.Clean(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		restored := mapping.Reverse(string(content))
+
+		if deanonymizeOutput == "" {
+			if _, err := fmt.Fprint(cmd.OutOrStdout(), restored); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			return nil
+		}
+
+		path, err := determineOutputPath(args[0], deanonymizeOutput, filepath.Ext(args[0]), nil, deanonymizeForce)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path for %s: %w", args[0], err)
+		}
+
+		if err := export.NewFileExporter().Export(ctx, restored, path); err != nil {
+			return fmt.Errorf("failed to export restored report to %s: %w", path, err)
+		}
+
+		return nil
+	},
+}
+
+// loadAnonymizationMap reads a mapping saved with --anonymize-map.
+func loadAnonymizationMap(path string) (*sanitize.Mapping, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anonymization mapping %s: %w", path, err)
+	}
+
+	return sanitize.ParseMapping(data)
+}
@@ -7,8 +7,6 @@ import (
 	"os"
 	"path/filepath"
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
-	// "github.com/EvilBit-Labs/opnDossier/internal/audit".
 	"github.com/EvilBit-Labs/opnDossier/internal/config"
 	"github.com/EvilBit-Labs/opnDossier/internal/display"
 	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
@@ -26,6 +24,7 @@ func init() {
 	addDisplayFlags(displayCmd)
 	// Add audit flags (same as convert command)
 	addSharedAuditFlags(displayCmd)
+	addSanitizeFlags(displayCmd)
 
 	// Flag groups for better organization
 	displayCmd.Flags().SortFlags = false
@@ -74,6 +73,9 @@ Examples:
   # Display with text wrapping
   opnDossier display --wrap 120 config.xml
 
+  # Display a blue team audit report with compliance checks
+  opnDossier display --mode blue --plugins stig,sans config.xml
+
   # Display with verbose logging to see processing details
   opnDossier --verbose display config.xml
 
@@ -131,6 +133,13 @@ Examples:
 			return fmt.Errorf("failed to parse XML from %s: %w", filePath, err)
 		}
 
+		anonymizer, err := newAnonymizer()
+		if err != nil {
+			return err
+		}
+
+		sanitizeDocument(opnsense, anonymizer, ctxLogger)
+
 		templateDir := getSharedTemplateDir()
 		g, err := markdown.NewMarkdownGeneratorWithTemplates(ctxLogger.Logger, templateDir)
 		if err != nil {
@@ -141,21 +150,58 @@ Examples:
 		// Create markdown options with comprehensive support
 		mdOpts := buildDisplayOptions(Cfg)
 
-		// TODO: Audit mode functionality is not yet complete - disabled for now
+		tunableBaseline, err := loadTunableBaseline("", Cfg)
+		if err != nil {
+			return err
+		}
+
+		mdOpts.TunableBaseline = tunableBaseline
+
+		scoringEngine, err := buildScoringEngine(Cfg, tunableBaseline)
+		if err != nil {
+			return err
+		}
+
+		mdOpts.ScoringEngine = scoringEngine
+
+		controlMapping, err := loadControlMapping(sharedControlMapping, Cfg)
+		if err != nil {
+			return err
+		}
+
+		mdOpts.ControlMapping = controlMapping
+
+		waivers, err := loadWaivers(sharedWaiverFile, Cfg)
+		if err != nil {
+			return err
+		}
+
+		mdOpts.Waivers = waivers
+
+		baselineReport, err := loadBaseline(sharedBaselineFile)
+		if err != nil {
+			return err
+		}
+
+		mdOpts.Baseline = baselineReport
+
 		// Handle audit mode if specified
-		// var md string
-		// if mdOpts.AuditMode != "" {
-		// 	// Create plugin registry for audit mode
-		// 	registry := audit.NewPluginRegistry()
-		// 	md, err = handleAuditMode(ctx, opnsense, mdOpts, ctxLogger, registry)
-		// 	if err != nil {
-		// 		ctxLogger.Error("Failed to generate audit report", "error", err)
-		// 		return fmt.Errorf("failed to generate audit report from %s: %w", filePath, err)
-		// 	}
-		// } else {
-		// Standard markdown generation
-		md, err := g.Generate(ctx, opnsense, mdOpts)
-		// }
+		var md string
+		if mdOpts.AuditMode != "" {
+			pluginManager, err := newPluginManager(ctx, ctxLogger)
+			if err != nil {
+				return err
+			}
+
+			md, err = handleAuditMode(ctx, opnsense, mdOpts, ctxLogger, pluginManager)
+			if err != nil {
+				ctxLogger.Error("Failed to generate audit report", "error", err)
+				return fmt.Errorf("failed to generate audit report from %s: %w", filePath, err)
+			}
+		} else {
+			// Standard markdown generation
+			md, err = g.Generate(ctx, opnsense, mdOpts)
+		}
 		if err != nil {
 			ctxLogger.Error("Failed to convert to markdown", "error", err)
 			return fmt.Errorf("failed to convert to markdown from %s: %w", filePath, err)
@@ -223,14 +269,19 @@ func buildDisplayOptions(cfg *config.Config) markdown.Options {
 		opt.TemplateDir = templateDir
 	}
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
 	// Audit mode flags: CLI flag only
-	// if sharedAuditMode != "" {
-	// 	opt.AuditMode = markdown.AuditMode(sharedAuditMode)
-	// }
-	// opt.BlackhatMode = sharedBlackhatMode
+	if sharedAuditMode != "" {
+		opt.AuditMode = markdown.AuditMode(sharedAuditMode)
+	}
+
+	opt.BlackhatMode = sharedBlackhatMode
 	opt.Comprehensive = sharedComprehensive
-	// Selected plugins are disabled until audit functionality is complete
+
+	if len(sharedSelectedPlugins) > 0 {
+		opt.SelectedPlugins = sharedSelectedPlugins
+	}
+
+	opt.GroupByFramework = sharedGroupBy
 
 	return opt
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/hasync"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/nao1215/markdown"
+	"github.com/spf13/cobra"
+)
+
+// ErrHACheckFailed is returned when the HA check finds high severity issues.
+var ErrHACheckFailed = errors.New("high availability check found high severity issues")
+
+// errUnsupportedHAFormat is returned for unknown output formats.
+var errUnsupportedHAFormat = errors.New("unsupported output format")
+
+var haCheckFormat string //nolint:gochecknoglobals // Cobra flag variable
+
+// init registers the ha-check command with the root command for the CLI.
+func init() {
+	rootCmd.AddCommand(haCheckCmd)
+
+	haCheckCmd.Flags().StringVarP(&haCheckFormat, "format", "f", "markdown", "Output format (markdown, json)")
+	setFlagAnnotation(haCheckCmd.Flags(), "format", []string{"output"})
+}
+
+var haCheckCmd = &cobra.Command{ //nolint:gochecknoglobals // Cobra command
+	Use:     "ha-check primary.xml [secondary.xml]",
+	Short:   "Check the consistency of a high-availability pair",
+	GroupID: "audit",
+	Long: `The 'ha-check' command verifies the high-availability (CARP/pfsync) setup
+of OPNsense firewalls.
+
+With a single configuration it checks CARP virtual IPs (VHID, advbase,
+advskew, password), the pfsync interface and peer, and the configuration
+synchronisation target and credentials.
+
+With the configurations of the primary and the secondary node it additionally
+verifies that:
+- Every CARP VIP exists on both nodes with the same address and password,
+  and the secondary has a higher advskew than the primary
+- The pfsync peers and the configuration sync target point at the other node
+- Interfaces and VLANs match, with distinct addresses in the same subnets
+- Every section listed in the primary's sync items is identical on both nodes
+- Firewall rules match even when they are not synchronised
+
+The command exits with an error when a high severity issue is found.
+
+Examples:
+  # Check a single node
+  opnDossier ha-check primary.xml
+
+  # Check a primary/secondary pair
+  opnDossier ha-check primary.xml secondary.xml
+
+  # Emit the result as JSON for automation
+  opnDossier ha-check primary.xml secondary.xml --format json
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		configs := make([]*model.OpnSenseDocument, 0, len(args))
+
+		for _, path := range args {
+			cfg, err := parseConfigFile(ctx, path)
+			if err != nil {
+				return err
+			}
+
+			configs = append(configs, cfg)
+		}
+
+		var result *hasync.Result
+		if len(configs) == 1 {
+			result = hasync.CheckNode(configs[0])
+		} else {
+			result = hasync.ComparePair(configs[0], configs[1])
+		}
+
+		if err := writeHAResult(cmd.OutOrStdout(), result, args, haCheckFormat); err != nil {
+			return err
+		}
+
+		if result.HasSeverity(hasync.SeverityHigh) {
+			return ErrHACheckFailed
+		}
+
+		return nil
+	},
+}
+
+// parseConfigFile reads and parses a configuration file without validation.
+func parseConfigFile(ctx context.Context, path string) (*model.OpnSenseDocument, error) {
+	cleanPath, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for %s: %w", path, err)
+	}
+
+	file, err := os.Open(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			logger.Error("failed to close file", "error", cerr)
+		}
+	}()
+
+	cfg, err := parser.NewXMLParser().Parse(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML from %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// writeHAResult renders the result of an HA check in the requested format.
+func writeHAResult(w io.Writer, result *hasync.Result, paths []string, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		versioned := struct {
+			SchemaVersion string `json:"schemaVersion"`
+			*hasync.Result
+		}{constants.SchemaVersion, result}
+
+		if err := encoder.Encode(versioned); err != nil {
+			return fmt.Errorf("failed to encode HA check result: %w", err)
+		}
+
+		return nil
+	case "markdown", "md":
+		return writeHAMarkdown(w, result, paths)
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedHAFormat, format)
+	}
+}
+
+// writeHAMarkdown renders the result of an HA check as a markdown report.
+func writeHAMarkdown(w io.Writer, result *hasync.Result, paths []string) error {
+	summary := []string{fmt.Sprintf("%s: %s", markdown.Bold("Primary"), paths[0])}
+
+	if len(paths) > 1 {
+		summary = append(summary, fmt.Sprintf("%s: %s", markdown.Bold("Secondary"), paths[1]))
+	}
+
+	summary = append(summary, fmt.Sprintf("%s: %d high, %d medium, %d low",
+		markdown.Bold("Issues"),
+		result.Count(hasync.SeverityHigh),
+		result.Count(hasync.SeverityMedium),
+		result.Count(hasync.SeverityLow),
+	))
+
+	if len(result.Compared) > 0 {
+		summary = append(summary,
+			fmt.Sprintf("%s: %s", markdown.Bold("Compared sync items"), strings.Join(result.Compared, ", ")))
+	}
+
+	if len(result.Unverified) > 0 {
+		summary = append(summary,
+			fmt.Sprintf("%s: %s", markdown.Bold("Sync items not compared"), strings.Join(result.Unverified, ", ")))
+	}
+
+	md := markdown.NewMarkdown(w).H1("High Availability Check").BulletList(summary...)
+
+	if len(result.Issues) == 0 {
+		md.PlainText("No high-availability issues found.")
+	} else {
+		md.H2("Issues").Table(hasync.IssueTable(result.Issues))
+	}
+
+	if err := md.Build(); err != nil {
+		return fmt.Errorf("failed to render HA check result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/hasync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHAResult(t *testing.T) {
+	result := &hasync.Result{
+		Issues: []hasync.Issue{{
+			Severity: hasync.SeverityHigh,
+			Node:     hasync.NodeSecondary,
+			Title:    "CARP VIP Without Password",
+		}},
+		Compared:   []string{"rules"},
+		Unverified: []string{"ipsec"},
+	}
+	paths := []string{"primary.xml", "secondary.xml"}
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeHAResult(&buf, result, paths, "markdown"))
+
+		out := buf.String()
+		assert.Contains(t, out, "# High Availability Check")
+		assert.Contains(t, out, "secondary.xml")
+		assert.Contains(t, out, "1 high, 0 medium, 0 low")
+		assert.Contains(t, out, "CARP VIP Without Password")
+		assert.Contains(t, out, "ipsec")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeHAResult(&buf, result, paths, "json"))
+
+		var decoded hasync.Result
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, *result, decoded)
+		assert.Contains(t, buf.String(), `"schemaVersion": "`+constants.SchemaVersion+`"`)
+	})
+
+	t.Run("no issues", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeHAResult(&buf, &hasync.Result{}, paths[:1], "md"))
+		assert.Contains(t, buf.String(), "No high-availability issues found.")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := writeHAResult(&bytes.Buffer{}, result, paths, "yaml")
+		require.ErrorIs(t, err, errUnsupportedHAFormat)
+	})
+}
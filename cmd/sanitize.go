@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/log"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/sanitize"
+	"github.com/spf13/cobra"
+)
+
+// anonymizeKeyEnv is the environment variable that provides the anonymization key without
+// exposing it in the process list.
+const anonymizeKeyEnv = "OPNDOSSIER_ANONYMIZE_KEY"
+
+// ErrAnonymizeRequired is returned when anonymization options are given without --anonymize.
+var ErrAnonymizeRequired = errors.New("--anonymize-key and --anonymize-map require --anonymize")
+
+var (
+	sharedRedact       bool   //nolint:gochecknoglobals // Cobra flag variable
+	sharedAnonymize    bool   //nolint:gochecknoglobals // Cobra flag variable
+	sharedAnonymizeKey string //nolint:gochecknoglobals // Cobra flag variable
+	sharedAnonymizeMap string //nolint:gochecknoglobals // Cobra flag variable
+)
+
+// addSanitizeFlags adds the redaction and anonymization flags to a command.
+func addSanitizeFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		BoolVar(&sharedRedact, "redact", false, "Remove password hashes, keys, secrets and SNMP communities from the output")
+	setFlagAnnotation(cmd.Flags(), "redact", []string{"output"})
+
+	cmd.Flags().
+		BoolVar(&sharedAnonymize, "anonymize", false, "Replace public IPs, hostnames, domains and usernames with keyed pseudonyms")
+	setFlagAnnotation(cmd.Flags(), "anonymize", []string{"output"})
+
+	cmd.Flags().
+		StringVar(&sharedAnonymizeKey, "anonymize-key", "", "Key for pseudonyms that are consistent across runs (default: $"+anonymizeKeyEnv+" or a random key)")
+	setFlagAnnotation(cmd.Flags(), "anonymize-key", []string{"output"})
+
+	cmd.Flags().
+		StringVar(&sharedAnonymizeMap, "anonymize-map", "", "JSON file to save the pseudonym mapping to, which 'deanonymize' reverses")
+	setFlagAnnotation(cmd.Flags(), "anonymize-map", []string{"output"})
+}
+
+// newAnonymizer returns the anonymizer selected by --anonymize, or nil without it.
+func newAnonymizer() (*sanitize.Anonymizer, error) {
+	if !sharedAnonymize {
+		if sharedAnonymizeKey != "" || sharedAnonymizeMap != "" {
+			return nil, ErrAnonymizeRequired
+		}
+
+		return nil, nil //nolint:nilnil // no anonymizer without --anonymize
+	}
+
+	key := sharedAnonymizeKey
+	if key == "" {
+		key = os.Getenv(anonymizeKeyEnv)
+	}
+
+	return sanitize.NewAnonymizer([]byte(key))
+}
+
+// sanitizeDocument redacts and anonymizes a parsed configuration as selected by the flags. It runs
+// before any report is generated, so that every output format and finding is sanitized.
+func sanitizeDocument(doc *model.OpnSenseDocument, anonymizer *sanitize.Anonymizer, logger *log.Logger) {
+	if sharedRedact {
+		logger.Debug("Redacted secrets", "count", sanitize.Redact(doc))
+	}
+
+	if anonymizer != nil {
+		anonymizer.Document(doc)
+	}
+}
+
+// saveAnonymizationMap writes the pseudonyms of the run to the --anonymize-map file.
+func saveAnonymizationMap(ctx context.Context, anonymizer *sanitize.Anonymizer, force bool) error {
+	if anonymizer == nil || sharedAnonymizeMap == "" {
+		return nil
+	}
+
+	content, err := anonymizer.Mapping().JSON()
+	if err != nil {
+		return err
+	}
+
+	path, err := determineOutputPath(sharedAnonymizeMap, sharedAnonymizeMap, ".json", nil, force)
+	if err != nil {
+		return fmt.Errorf("failed to determine output path for %s: %w", sharedAnonymizeMap, err)
+	}
+
+	if err := export.NewFileExporter().Export(ctx, content, path); err != nil {
+		return fmt.Errorf("failed to save anonymization mapping to %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/sanitize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetSanitizeFlags restores the default redaction and anonymization flags after a test.
+func resetSanitizeFlags(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		sharedRedact = false
+		sharedAnonymize = false
+		sharedAnonymizeKey = ""
+		sharedAnonymizeMap = ""
+	})
+}
+
+func TestNewAnonymizer(t *testing.T) {
+	resetSanitizeFlags(t)
+
+	anonymizer, err := newAnonymizer()
+	require.NoError(t, err)
+	assert.Nil(t, anonymizer)
+
+	sharedAnonymizeMap = "mapping.json"
+	_, err = newAnonymizer()
+	require.ErrorIs(t, err, ErrAnonymizeRequired)
+
+	sharedAnonymize = true
+	sharedAnonymizeKey = "key"
+	anonymizer, err = newAnonymizer()
+	require.NoError(t, err)
+	assert.NotNil(t, anonymizer)
+}
+
+func TestSanitizeDocumentAndSaveMap(t *testing.T) {
+	resetSanitizeFlags(t)
+
+	logger, err := createTestLogger()
+	require.NoError(t, err)
+
+	sharedRedact = true
+	sharedAnonymize = true
+	sharedAnonymizeKey = "key"
+	sharedAnonymizeMap = filepath.Join(t.TempDir(), "mapping.json")
+
+	anonymizer, err := newAnonymizer()
+	require.NoError(t, err)
+
+	doc := &model.OpnSenseDocument{}
+	doc.System.Hostname = "fw01"
+	doc.HighAvailabilitySync.Password = "sync"
+
+	sanitizeDocument(doc, anonymizer, logger)
+
+	assert.Equal(t, sanitize.Redacted, doc.HighAvailabilitySync.Password)
+	assert.True(t, strings.HasPrefix(doc.System.Hostname, "host-"))
+
+	require.NoError(t, saveAnonymizationMap(t.Context(), anonymizer, false))
+
+	data, err := os.ReadFile(sharedAnonymizeMap)
+	require.NoError(t, err)
+
+	mapping, err := sanitize.ParseMapping(data)
+	require.NoError(t, err)
+	assert.Equal(t, "fw01", mapping.Reverse(doc.System.Hostname))
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaOutput string //nolint:gochecknoglobals // Cobra flag variable
+	schemaForce  bool   //nolint:gochecknoglobals // Cobra flag variable
+)
+
+// init registers the schema command with the root command for the CLI.
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.Flags().
+		StringVarP(&schemaOutput, "output", "o", "", "Output file path (default: standard output)")
+	setFlagAnnotation(schemaCmd.Flags(), "output", []string{"output"})
+
+	schemaCmd.Flags().BoolVar(&schemaForce, "force", false, "Force overwrite existing files")
+	setFlagAnnotation(schemaCmd.Flags(), "force", []string{"output"})
+}
+
+var schemaCmd = &cobra.Command{ //nolint:gochecknoglobals // Cobra command
+	Use:       "schema [" + strings.Join(schema.Names(), "|") + "]",
+	Short:     "Print the JSON Schema of a JSON output",
+	GroupID:   "utility",
+	ValidArgs: schema.Names(),
+	Long: `The 'schema' command prints the JSON Schema (draft 2020-12) of a JSON output of
+opnDossier, generated from the Go types that are written:
+
+  document  - convert --format json
+  report    - the processor report
+  audit     - audit reports and convert --mode with --format json
+  hacheck   - hacheck --format json
+
+Every JSON output carries the version of its schema as the schemaVersion
+property, so consumers can detect breaking changes. The major version changes
+when a property is removed, renamed or retyped, the minor version when a
+property is added. The current version is ` + constants.SchemaVersion + `.
+
+Examples:
+  # Print the schema of convert --format json
+  opnDossier schema
+
+  # Save the schema of audit reports
+  opnDossier schema audit -o audit.schema.json
+`,
+	Args: cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		name := schema.Names()[0]
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		s, err := schema.Generate(name)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s schema: %w", name, err)
+		}
+
+		content, err := s.Marshal()
+		if err != nil {
+			return err
+		}
+
+		if schemaOutput == "" {
+			if _, err := fmt.Fprint(cmd.OutOrStdout(), content); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			return nil
+		}
+
+		path, err := determineOutputPath(name, schemaOutput, ".schema.json", nil, schemaForce)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path for %s schema: %w", name, err)
+		}
+
+		if err := export.NewFileExporter().Export(ctx, content, path); err != nil {
+			return fmt.Errorf("failed to export %s schema to %s: %w", name, path, err)
+		}
+
+		return nil
+	},
+}
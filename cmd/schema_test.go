@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetSchemaFlags restores the default schema flags after a test.
+func resetSchemaFlags(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		schemaOutput = ""
+		schemaForce = false
+		schemaCmd.SetOut(nil)
+	})
+}
+
+func TestSchemaCmd(t *testing.T) {
+	resetSchemaFlags(t)
+
+	var buf bytes.Buffer
+	schemaCmd.SetOut(&buf)
+
+	require.NoError(t, schemaCmd.RunE(schemaCmd, []string{"audit"}))
+
+	var decoded schema.Schema
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, schema.Draft, decoded.Schema)
+	assert.Equal(t, "opnDossier audit report", decoded.Title)
+	assert.Equal(t, constants.SchemaVersion, decoded.Properties["schemaVersion"].Const)
+}
+
+func TestSchemaCmd_Output(t *testing.T) {
+	resetSchemaFlags(t)
+
+	schemaOutput = filepath.Join(t.TempDir(), "document.schema.json")
+	require.NoError(t, schemaCmd.RunE(schemaCmd, nil))
+
+	content, err := os.ReadFile(schemaOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"title": "opnDossier configuration document"`)
+}
+
+func TestSchemaCmd_Args(t *testing.T) {
+	require.NoError(t, schemaCmd.Args(schemaCmd, []string{"report"}))
+	require.Error(t, schemaCmd.Args(schemaCmd, []string{"unknown"}))
+	require.Error(t, schemaCmd.Args(schemaCmd, []string{"report", "audit"}))
+}
@@ -5,14 +5,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/baseline"
+	"github.com/EvilBit-Labs/opnDossier/internal/config"
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
+	"github.com/EvilBit-Labs/opnDossier/internal/mapping"
 	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin/external"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
 	"github.com/spf13/cobra"
 )
 
@@ -31,13 +42,22 @@ var (
 	sharedEngine      string //nolint:gochecknoglobals // Generation engine (programmatic, template)
 	sharedLegacy      bool   //nolint:gochecknoglobals // Enable legacy mode with deprecation warning
 
-	// TODO: Audit mode functionality is not yet complete - disabled for now
-	// sharedAuditMode       string   //nolint:gochecknoglobals // Audit mode (standard, blue, red)
-	// sharedBlackhatMode    bool     //nolint:gochecknoglobals // Enable blackhat mode for red team reports.
-	sharedComprehensive bool //nolint:gochecknoglobals // Generate comprehensive report
-	// sharedSelectedPlugins []string //nolint:gochecknoglobals // Selected compliance plugins.
+	// Audit flags.
+	sharedAuditMode       string        //nolint:gochecknoglobals // Audit mode (standard, blue, red)
+	sharedBlackhatMode    bool          //nolint:gochecknoglobals // Enable blackhat mode for red team reports
+	sharedComprehensive   bool          //nolint:gochecknoglobals // Generate comprehensive report
+	sharedSelectedPlugins []string      //nolint:gochecknoglobals // Selected compliance plugins
+	sharedControlMapping  string        //nolint:gochecknoglobals // Custom control mapping file
+	sharedGroupBy         string        //nolint:gochecknoglobals // Framework to group audit results by
+	sharedPluginDir       string        //nolint:gochecknoglobals // Directory of external compliance plugins
+	sharedPluginTimeout   time.Duration //nolint:gochecknoglobals // Timeout of external plugin requests
+	sharedWaiverFile      string        //nolint:gochecknoglobals // Waivers of accepted findings
+	sharedBaselineFile    string        //nolint:gochecknoglobals // Previous audit report to compare with
 )
 
+// ErrUnknownPlugin is returned when a selected compliance plugin is not available.
+var ErrUnknownPlugin = errors.New("unknown compliance plugin")
+
 // addSharedTemplateFlags adds template flags that are common to both convert and display commands.
 func addSharedTemplateFlags(cmd *cobra.Command) {
 	// Generation engine flags
@@ -103,16 +123,120 @@ func addDisplayFlags(cmd *cobra.Command) {
 	setFlagAnnotation(cmd.Flags(), "theme", []string{"template"})
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
 // addSharedAuditFlags adds the shared audit mode flags to a command.
-// These flags are used by the convert command for audit report generation.
+// These flags are used by the audit, convert and display commands for audit report generation.
 func addSharedAuditFlags(cmd *cobra.Command) {
-	// TODO: Audit mode flags are disabled until audit functionality is complete
-	// Audit mode flags are commented out until audit functionality is complete
+	cmd.Flags().
+		StringVar(&sharedAuditMode, "mode", "", "Audit report mode (standard, blue, red)")
+	setFlagAnnotation(cmd.Flags(), "mode", []string{"audit"})
+
+	cmd.Flags().
+		BoolVar(&sharedBlackhatMode, "blackhat-mode", false, "Add attacker commentary to red team reports")
+	setFlagAnnotation(cmd.Flags(), "blackhat-mode", []string{"audit"})
+
+	cmd.Flags().
+		StringSliceVar(&sharedSelectedPlugins, "plugins", []string{}, "Compliance plugins to run (comma-separated, e.g., stig,sans,firewall,cis)")
+	setFlagAnnotation(cmd.Flags(), "plugins", []string{"audit"})
 
 	cmd.Flags().
 		BoolVar(&sharedComprehensive, "comprehensive", false, "Generate comprehensive detailed reports with full configuration analysis")
 	setFlagAnnotation(cmd.Flags(), "comprehensive", []string{"audit"})
+
+	cmd.Flags().
+		StringVar(&sharedControlMapping, "control-mapping", "", "YAML file overriding the cross-framework control mapping (default: built-in mapping)")
+	setFlagAnnotation(cmd.Flags(), "control-mapping", []string{"audit"})
+
+	cmd.Flags().
+		StringVar(&sharedGroupBy, "group-by-framework", "", "Group audit results by a mapped framework (e.g., nist-800-53, iso-27001, stig, cis)")
+	setFlagAnnotation(cmd.Flags(), "group-by-framework", []string{"audit"})
+
+	cmd.Flags().
+		StringVar(&sharedPluginDir, "plugin-dir", "", "Directory of external compliance plugin executables")
+	setFlagAnnotation(cmd.Flags(), "plugin-dir", []string{"audit"})
+
+	cmd.Flags().
+		DurationVar(&sharedPluginTimeout, "plugin-timeout", external.DefaultTimeout, "Timeout of each plugin run and external plugin request")
+	setFlagAnnotation(cmd.Flags(), "plugin-timeout", []string{"audit"})
+
+	addWaiverFlag(cmd)
+
+	cmd.Flags().
+		StringVar(&sharedBaselineFile, "baseline", "", "Previous JSON audit report to classify findings as new, unchanged or resolved")
+	setFlagAnnotation(cmd.Flags(), "baseline", []string{"audit"})
+}
+
+// addWaiverFlag adds the waiver file flag, which the audit commands and the validate command share.
+func addWaiverFlag(cmd *cobra.Command) {
+	cmd.Flags().
+		StringVar(&sharedWaiverFile, "waivers", "", "YAML file of waivers for accepted findings")
+	setFlagAnnotation(cmd.Flags(), "waivers", []string{"audit"})
+}
+
+// loadControlMapping loads the control mapping named by the CLI flag or, failing that, the configuration
+// file and merges it over the built-in mapping. It returns nil when neither is set so that the built-in
+// mapping is used.
+func loadControlMapping(flagPath string, cfg *config.Config) (*mapping.Catalogue, error) {
+	path := flagPath
+	if path == "" && cfg != nil {
+		path = cfg.GetControlMapping()
+	}
+
+	if path == "" {
+		return nil, nil //nolint:nilnil // built-in mapping requested
+	}
+
+	override, err := mapping.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load control mapping: %w", err)
+	}
+
+	catalogue, err := mapping.Default().Merge(override)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply control mapping %s: %w", path, err)
+	}
+
+	logger.Debug("Loaded control mapping", "file", path, "controls", len(override.Controls))
+
+	return catalogue, nil
+}
+
+// loadWaivers loads the waiver file named by the CLI flag or, failing that, the configuration file.
+// It returns nil when neither is set so that no findings are waived.
+func loadWaivers(flagPath string, cfg *config.Config) (*waiver.Set, error) {
+	path := flagPath
+	if path == "" && cfg != nil {
+		path = cfg.GetWaiverFile()
+	}
+
+	if path == "" {
+		return nil, nil //nolint:nilnil // no waivers requested
+	}
+
+	set, err := waiver.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load waivers: %w", err)
+	}
+
+	logger.Debug("Loaded waivers", "file", path, "waivers", len(set.Waivers))
+
+	return set, nil
+}
+
+// loadBaseline loads the baseline report named by the CLI flag. It returns nil when no baseline
+// was given so that findings are not classified.
+func loadBaseline(path string) (*baseline.Baseline, error) {
+	if path == "" {
+		return nil, nil //nolint:nilnil // no baseline requested
+	}
+
+	b, err := baseline.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+
+	logger.Debug("Loaded baseline report", "file", path, "findings", len(b.Findings))
+
+	return b, nil
 }
 
 // getSharedTemplateDir returns the template directory path from the custom template flag.
@@ -219,55 +343,191 @@ func validateTemplatePath(templatePath string) error {
 	return nil
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// handleAuditMode generates an audit report using the audit mode controller and markdown generator.
+// newPluginManager creates a plugin manager with the built-in compliance plugins and the external
+// plugins and rule packs of the plugin directory, if one is configured, registered.
+func newPluginManager(ctx context.Context, logger *log.Logger) (*audit.PluginManager, error) {
+	manager := audit.NewPluginManager(slog.New(logger.Logger))
+	if err := manager.InitializePlugins(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize compliance plugins: %w", err)
+	}
+
+	dir := sharedPluginDir
+	if dir == "" && Cfg != nil {
+		dir = Cfg.GetPluginDir()
+	}
+
+	if dir != "" {
+		if err := manager.LoadExternalPlugins(ctx, dir, external.Options{Timeout: sharedPluginTimeout}); err != nil {
+			return nil, err
+		}
+
+		if err := manager.LoadRulePacks(ctx, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	manager.SetPluginTimeout(sharedPluginTimeout)
+
+	if Cfg != nil {
+		if err := manager.ConfigurePlugins(ctx, Cfg.GetPluginSettings()); err != nil {
+			return nil, fmt.Errorf("failed to configure compliance plugins: %w", err)
+		}
+	}
+
+	return manager, nil
+}
+
+// validatePluginSelection checks the selected compliance plugins against the available plugins.
+func validatePluginSelection(ctx context.Context, manager *audit.PluginManager, selected []string) error {
+	available := make([]string, 0)
+	for _, info := range manager.ListAvailablePlugins(ctx) {
+		available = append(available, info.Name)
+	}
+
+	slices.Sort(available)
+
+	for _, name := range selected {
+		if !slices.Contains(available, name) {
+			return fmt.Errorf("%w: %s (available: %s)", ErrUnknownPlugin, name, strings.Join(available, ", "))
+		}
+	}
+
+	return nil
+}
+
+// handleAuditMode generates an audit report using the audit mode controller and renders it
+// in the requested format with the programmatic markdown builder.
 func handleAuditMode(
-	_ context.Context,
-	_ *model.OpnSenseDocument,
-	_ markdown.Options,
-	_ *log.Logger,
-	_ *audit.PluginRegistry,
+	ctx context.Context,
+	cfg *model.OpnSenseDocument,
+	opts markdown.Options,
+	logger *log.Logger,
+	manager *audit.PluginManager,
 ) (string, error) {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return "", errors.New("audit mode functionality is not yet implemented")
+	report, err := generateAuditReport(ctx, cfg, opts, logger, manager)
+	if err != nil {
+		return "", err
+	}
+
+	return renderAuditReport(report, opts)
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// convertAuditModeToReportMode converts markdown audit mode to audit report mode.
-func convertAuditModeToReportMode(_ markdown.AuditMode) (audit.ReportMode, error) {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return audit.ModeStandard, errors.New("audit mode functionality is not yet implemented")
+// generateAuditReport generates an audit report using the audit mode controller.
+func generateAuditReport(
+	ctx context.Context,
+	cfg *model.OpnSenseDocument,
+	opts markdown.Options,
+	logger *log.Logger,
+	manager *audit.PluginManager,
+) (*audit.Report, error) {
+	reportMode, err := convertAuditModeToReportMode(opts.AuditMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePluginSelection(ctx, manager, opts.SelectedPlugins); err != nil {
+		return nil, err
+	}
+
+	controller := audit.NewModeController(manager.GetRegistry(), logger.Logger)
+
+	report, err := controller.GenerateReport(ctx, cfg, createModeConfig(reportMode, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate audit report: %w", err)
+	}
+
+	return report, nil
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// createModeConfig creates an audit mode configuration from options.
-func createModeConfig(_ audit.ReportMode, _ markdown.Options) *audit.ModeConfig {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return &audit.ModeConfig{}
+// renderAuditReport renders an audit report in the requested format with the programmatic
+// markdown builder.
+func renderAuditReport(report *audit.Report, opts markdown.Options) (string, error) {
+	builder := converter.NewMarkdownBuilder()
+	builder.SetScoringEngine(opts.ScoringEngine)
+	builder.SetTunableBaseline(opts.TunableBaseline)
+
+	var (
+		output string
+		err    error
+	)
+
+	switch strings.ToLower(string(opts.Format)) {
+	case FormatHTML:
+		output, err = report.ToHTML(builder, string(opts.Theme))
+	case FormatSARIF:
+		output, err = renderSARIF(report, opts.SourceFile)
+	case FormatJUnit:
+		output, err = report.ToJUnit(opts.SourceFile, processor.Severity(opts.SeverityThreshold))
+	default:
+		output, err = report.Render(string(opts.Format), builder)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to render audit report: %w", err)
+	}
+
+	return output, nil
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// generateBaseAuditReport generates the base audit report using template rendering.
-func generateBaseAuditReport(
-	_ context.Context,
-	_ *model.OpnSenseDocument,
-	_ markdown.Options,
-	_ *log.Logger,
-) (string, error) {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return "", errors.New("audit mode functionality is not yet implemented")
+// renderSARIF renders an audit report as SARIF, locating the findings in the configuration file
+// when it is known.
+func renderSARIF(report *audit.Report, sourceFile string) (string, error) {
+	var locator *sarif.Locator
+
+	if sourceFile != "" {
+		var err error
+
+		locator, err = sarif.LoadLocator(sourceFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to locate findings: %w", err)
+		}
+	}
+
+	return report.ToSARIF(locator)
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// createAuditMarkdownOptions creates markdown options specifically for audit mode.
-func createAuditMarkdownOptions(_ markdown.Options) markdown.Options {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return markdown.Options{}
+// convertAuditModeToReportMode converts markdown audit mode to audit report mode.
+// An empty mode selects the standard report.
+func convertAuditModeToReportMode(mode markdown.AuditMode) (audit.ReportMode, error) {
+	if mode == "" {
+		return audit.ModeStandard, nil
+	}
+
+	reportMode, err := audit.ParseReportMode(string(mode))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAuditMode, mode)
+	}
+
+	return reportMode, nil
 }
 
-// TODO: Audit mode functionality is not yet complete - disabled for now
-// appendAuditFindings appends audit findings summary to the report.
-func appendAuditFindings(result string, _ *audit.Report) string {
-	// TODO: Audit mode is disabled until audit functionality is complete
-	return result
+// createModeConfig creates an audit mode configuration from options.
+func createModeConfig(reportMode audit.ReportMode, opts markdown.Options) *audit.ModeConfig {
+	modeConfig := &audit.ModeConfig{
+		Mode:            reportMode,
+		BlackhatMode:    opts.BlackhatMode,
+		Comprehensive:   opts.Comprehensive,
+		SelectedPlugins: opts.SelectedPlugins,
+		TemplateDir:     opts.TemplateDir,
+
+		Mapping:          opts.ControlMapping,
+		GroupByFramework: opts.GroupByFramework,
+		Waivers:          opts.Waivers,
+		Baseline:         opts.Baseline,
+	}
+
+	if opts.ScoringEngine != nil {
+		modeConfig.ProcessorOptions = append(modeConfig.ProcessorOptions, processor.WithScoringEngine(opts.ScoringEngine))
+	}
+
+	if opts.TunableBaseline != nil {
+		modeConfig.ProcessorOptions = append(modeConfig.ProcessorOptions,
+			processor.WithTunableBaseline(opts.TunableBaseline))
+	}
+
+	if opts.DeadRuleCheck {
+		modeConfig.ProcessorOptions = append(modeConfig.ProcessorOptions, processor.WithDeadRuleCheck())
+	}
+
+	return modeConfig
 }
@@ -9,8 +9,6 @@ import (
 
 	"github.com/EvilBit-Labs/opnDossier/internal/config"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
-	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
-	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,7 +93,7 @@ func TestAddSharedAuditFlagsComprehensive(t *testing.T) {
 	addSharedAuditFlags(cmd)
 
 	// Verify audit flags were added
-	auditFlags := []string{"comprehensive"}
+	auditFlags := []string{"mode", "blackhat-mode", "plugins", "comprehensive", "waivers", "baseline"}
 	for _, flag := range auditFlags {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("Expected flag %s to be added", flag)
@@ -103,24 +101,72 @@ func TestAddSharedAuditFlagsComprehensive(t *testing.T) {
 	}
 }
 
-// TestHandleAuditModeComprehensive tests audit mode handling - currently disabled.
-func TestHandleAuditModeComprehensive(t *testing.T) {
+// TestValidatePluginSelection tests plugin selection against the available plugins.
+func TestValidatePluginSelection(t *testing.T) {
 	logger, err := log.New(log.Config{})
-	if err != nil {
-		t.Fatalf("Failed to create logger: %v", err)
-	}
+	require.NoError(t, err)
 
-	// Since audit mode is currently disabled, just test that the function exists
-	// and returns an error indicating it's not implemented
 	ctx := context.Background()
-	opnsense := &model.OpnSenseDocument{}
-	opt := markdown.Options{}
+	manager, err := newPluginManager(ctx, logger)
+	require.NoError(t, err)
+
+	require.NoError(t, validatePluginSelection(ctx, manager, nil))
+	require.NoError(t, validatePluginSelection(ctx, manager, []string{"stig", "sans", "firewall", "cis"}))
+
+	err = validatePluginSelection(ctx, manager, []string{"stig", "nist"})
+	require.ErrorIs(t, err, ErrUnknownPlugin)
+	assert.Contains(t, err.Error(), "nist")
+}
+
+// TestLoadWaivers tests that the --waivers flag takes precedence over the configuration file.
+func TestLoadWaivers(t *testing.T) {
+	dir := t.TempDir()
+	content := "waivers:\n  - {id: %s, source: sans, justification: j, owner: o, expires: 2099-12-31}\n"
+
+	flagPath := filepath.Join(dir, "flag.yaml")
+	require.NoError(t, os.WriteFile(flagPath, []byte(strings.ReplaceAll(content, "%s", "FLAG")), 0o600))
+
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(strings.ReplaceAll(content, "%s", "CONFIG")), 0o600))
+
+	cfg := &config.Config{WaiverFile: configPath}
+
+	set, err := loadWaivers("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, set)
+
+	set, err = loadWaivers("", cfg)
+	require.NoError(t, err)
+	require.Len(t, set.Waivers, 1)
+	assert.Equal(t, "CONFIG", set.Waivers[0].ID)
+
+	set, err = loadWaivers(flagPath, cfg)
+	require.NoError(t, err)
+	require.Len(t, set.Waivers, 1)
+	assert.Equal(t, "FLAG", set.Waivers[0].ID)
+
+	_, err = loadWaivers(filepath.Join(dir, "missing.yaml"), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load waivers")
+}
+
+// TestLoadBaseline tests loading the baseline report named by the --baseline flag.
+func TestLoadBaseline(t *testing.T) {
+	b, err := loadBaseline("")
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	path := filepath.Join(t.TempDir(), "previous.json")
+	content := `{"findings": [{"title": "Default SNMP Community String", "fingerprint": "6b5a42f8dd501716"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
 
-	_, err = handleAuditMode(ctx, opnsense, opt, logger, nil)
+	b, err = loadBaseline(path)
+	require.NoError(t, err)
+	require.Len(t, b.Findings, 1)
 
-	// Should return error since audit mode is not implemented
+	_, err = loadBaseline(filepath.Join(t.TempDir(), "missing.json"))
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not yet implemented")
+	assert.Contains(t, err.Error(), "failed to load baseline")
 }
 
 // TestValidateTemplatePathEdgeCases tests edge cases for template path validation.
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/config"
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/log"
+	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/site"
+	"github.com/spf13/cobra"
+)
+
+// siteDirPermissions are the permissions of the directories of documentation sites.
+const siteDirPermissions = 0o750
+
+// sharedMkDocs adds an MkDocs configuration to documentation sites.
+var sharedMkDocs bool //nolint:gochecknoglobals // Cobra flag variable
+
+// ErrSiteOutputRequired is returned when a documentation site is generated without an output
+// directory.
+var ErrSiteOutputRequired = errors.New("site output requires --output to name the directory")
+
+// addSiteFlags adds the flags of the documentation site format to a command.
+func addSiteFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		BoolVar(&sharedMkDocs, "mkdocs", false, "Add an mkdocs.yml with the navigation to site output")
+	setFlagAnnotation(cmd.Flags(), "mkdocs", []string{"output"})
+}
+
+// convertToSite writes the documentation site of a configuration to the output directory. With
+// an audit mode, the findings page lists the audit findings.
+func convertToSite(
+	ctx context.Context,
+	inputFile string,
+	opnsense *model.OpnSenseDocument,
+	opt markdown.Options,
+	logger *log.Logger,
+	manager *audit.PluginManager,
+	multiple bool,
+) error {
+	dir, err := siteDir(inputFile, outputFile, Cfg, multiple)
+	if err != nil {
+		return err
+	}
+
+	builder := converter.NewMarkdownBuilder()
+	builder.SetScoringEngine(opt.ScoringEngine)
+	builder.SetTunableBaseline(opt.TunableBaseline)
+
+	siteOpts := site.Options{MkDocs: sharedMkDocs}
+
+	if opt.AuditMode != "" {
+		report, err := generateAuditReport(ctx, opnsense, opt, logger, manager)
+		if err != nil {
+			return err
+		}
+
+		siteOpts.Findings = report.FindingsMarkdown(builder)
+	}
+
+	s, err := site.Build(opnsense, builder, siteOpts)
+	if err != nil {
+		return fmt.Errorf("failed to build documentation site: %w", err)
+	}
+
+	return writeSite(ctx, dir, s.Files(), force)
+}
+
+// siteDir returns the output directory of a documentation site. Sites of several input files are
+// written to subdirectories named after the input files.
+func siteDir(inputFile, output string, cfg *config.Config, multiple bool) (string, error) {
+	if output == "" && cfg != nil {
+		output = cfg.OutputFile
+	}
+
+	if output == "" {
+		return "", ErrSiteOutputRequired
+	}
+
+	if multiple {
+		base := filepath.Base(inputFile)
+		output = filepath.Join(output, strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+
+	return output, nil
+}
+
+// writeSite writes the files of a site below a directory with overwrite protection, creating the
+// directories of the pages.
+func writeSite(ctx context.Context, dir string, files []site.File, force bool) error {
+	exporter := export.NewFileExporter()
+
+	for _, file := range files {
+		target := filepath.Join(dir, filepath.FromSlash(file.Path))
+
+		if err := os.MkdirAll(filepath.Dir(target), siteDirPermissions); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", target, err)
+		}
+
+		path, err := determineOutputPath(target, target, filepath.Ext(target), nil, force)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path for %s: %w", target, err)
+		}
+
+		if err := exporter.Export(ctx, file.Content, path); err != nil {
+			return fmt.Errorf("failed to export site page to %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/config"
+	"github.com/EvilBit-Labs/opnDossier/internal/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteDir(t *testing.T) {
+	_, err := siteDir("config.xml", "", nil, false)
+	require.ErrorIs(t, err, ErrSiteOutputRequired)
+
+	dir, err := siteDir("config.xml", "docs", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "docs", dir)
+
+	dir, err = siteDir("config.xml", "", &config.Config{OutputFile: "from-config"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "from-config", dir)
+
+	dir, err = siteDir(filepath.Join("backups", "fw1.xml"), "docs", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("docs", "fw1"), dir)
+}
+
+func TestWriteSite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	files := []site.File{
+		{Path: "mkdocs.yml", Content: "site_name: test\n"},
+		{Path: "docs/index.md", Content: "# test\n"},
+		{Path: "docs/rules/lan.md", Content: "# lan\n"},
+	}
+
+	require.NoError(t, writeSite(t.Context(), dir, files, false))
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		assert.Equal(t, file.Content, string(content))
+	}
+
+	// Existing pages are only overwritten with force
+	files[1].Content = "# updated\n"
+	require.NoError(t, writeSite(t.Context(), dir, files, true))
+
+	content, err := os.ReadFile(filepath.Join(dir, "docs", "index.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# updated\n", string(content))
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/config"
+	"github.com/EvilBit-Labs/opnDossier/internal/export"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/tabular"
+	"github.com/spf13/cobra"
+)
+
+// sharedTables selects the tables of CSV and XLSX exports.
+var sharedTables []string //nolint:gochecknoglobals // Cobra flag variable
+
+// ErrCSVOutputRequired is returned when several tables are exported as CSV without an output file
+// to name the table files after.
+var ErrCSVOutputRequired = errors.New("csv export of several tables requires --output or a single --table")
+
+// addTableFlag adds the table selection flag of the tabular formats to a command.
+func addTableFlag(cmd *cobra.Command) {
+	cmd.Flags().
+		StringSliceVar(&sharedTables, "table", []string{}, "Tables to export with csv or xlsx (rules, nat, interfaces, users, dhcp-leases, sysctl, findings; default: all)")
+	setFlagAnnotation(cmd.Flags(), "table", []string{"output"})
+}
+
+// isTabularFormat reports whether the format exports tables rather than a report.
+func isTabularFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatCSV, FormatXLSX:
+		return true
+	default:
+		return false
+	}
+}
+
+// buildTables returns the selected tables of a configuration. The findings table is only
+// available when an audit report is given.
+func buildTables(doc *model.OpnSenseDocument, report *audit.Report, names []string) ([]tabular.Table, error) {
+	tables := tabular.FromDocument(doc)
+	if report != nil {
+		tables = append(tables, report.FindingsTable())
+	}
+
+	selected, err := tabular.Select(tables, names)
+	if errors.Is(err, tabular.ErrTableUnavailable) {
+		return nil, fmt.Errorf("%w (the findings table requires --mode)", err)
+	}
+
+	return selected, err
+}
+
+// exportTables writes tables as an XLSX workbook or as CSV. A workbook or a single CSV table is
+// written to the output file or, without one, to w. Several CSV tables are written to one file per
+// table named after the output file, such as config.rules.csv and config.nat.csv.
+func exportTables(
+	ctx context.Context,
+	w io.Writer,
+	tables []tabular.Table,
+	format, inputFile, outputFile string,
+	cfg *config.Config,
+	force bool,
+) error {
+	if strings.EqualFold(format, FormatXLSX) {
+		workbook, err := tabular.ToXLSX(tables)
+		if err != nil {
+			return err
+		}
+
+		return writeTableFile(ctx, w, string(workbook), inputFile, outputFile, ".xlsx", cfg, force)
+	}
+
+	if len(tables) == 1 {
+		content, err := tables[0].ToCSV()
+		if err != nil {
+			return err
+		}
+
+		return writeTableFile(ctx, w, content, inputFile, outputFile, ".csv", cfg, force)
+	}
+
+	// Only name the table files here; overwriting each of them is confirmed below
+	base, err := determineOutputPath(inputFile, outputFile, ".csv", cfg, true)
+	if err != nil {
+		return err
+	}
+
+	if base == "" {
+		return ErrCSVOutputRequired
+	}
+
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	for _, table := range tables {
+		content, err := table.ToCSV()
+		if err != nil {
+			return err
+		}
+
+		if err := writeTableFile(ctx, w, content, inputFile, base+"."+table.Name+".csv", ".csv", nil, force); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTableFile writes exported tables to the output file with overwrite protection or, without
+// an output file, to w.
+func writeTableFile(
+	ctx context.Context,
+	w io.Writer,
+	content, inputFile, outputFile, fileExt string,
+	cfg *config.Config,
+	force bool,
+) error {
+	path, err := determineOutputPath(inputFile, outputFile, fileExt, cfg, force)
+	if err != nil {
+		return fmt.Errorf("failed to determine output path for %s: %w", inputFile, err)
+	}
+
+	if path == "" {
+		_, err := fmt.Fprint(w, content)
+		return err
+	}
+
+	if err := export.NewFileExporter().Export(ctx, content, path); err != nil {
+		return fmt.Errorf("failed to export tables to %s: %w", path, err)
+	}
+
+	return nil
+}
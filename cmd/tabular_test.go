@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/tabular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildTables tests table selection with and without an audit report.
+func TestBuildTables(t *testing.T) {
+	doc := &model.OpnSenseDocument{Sysctl: []model.SysctlItem{{Tunable: "net.inet.ip.forwarding", Value: "0"}}}
+
+	tables, err := buildTables(doc, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, tables, len(tabular.Names())-1, "no findings table without a report")
+
+	_, err = buildTables(doc, nil, []string{tabular.TableFindings})
+	require.ErrorIs(t, err, tabular.ErrTableUnavailable)
+	assert.Contains(t, err.Error(), "--mode")
+
+	report := &audit.Report{Findings: []audit.Finding{{Title: "Weak tunable", Severity: processor.SeverityLow}}}
+
+	tables, err = buildTables(doc, report, []string{"findings", "sysctl"})
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+	assert.Equal(t, "Weak tunable", tables[0].Rows[0][1])
+	assert.Equal(t, tabular.TableSysctl, tables[1].Name)
+}
+
+// TestExportTables tests that CSV tables are written to stdout or one file per table and that
+// several tables need an output file.
+func TestExportTables(t *testing.T) {
+	ctx := context.Background()
+	tables := []tabular.Table{
+		tabular.Sysctl([]model.SysctlItem{{Tunable: "kern.securelevel", Value: "1"}}),
+		tabular.Users([]model.User{{Name: "root", UID: "0"}}),
+	}
+
+	var stdout bytes.Buffer
+
+	require.NoError(t, exportTables(ctx, &stdout, tables[:1], FormatCSV, "config.xml", "", nil, false))
+	assert.Equal(t, "Tunable,Value,Description\nkern.securelevel,1,\n", stdout.String())
+
+	err := exportTables(ctx, &stdout, tables, FormatCSV, "config.xml", "", nil, false)
+	require.ErrorIs(t, err, ErrCSVOutputRequired)
+
+	dir := t.TempDir()
+	require.NoError(t, exportTables(ctx, &stdout, tables, FormatCSV, "config.xml", filepath.Join(dir, "export.csv"), nil, true))
+
+	users, err := os.ReadFile(filepath.Join(dir, "export.users.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(users), "root,0,")
+	assert.FileExists(t, filepath.Join(dir, "export.sysctl.csv"))
+	assert.NoFileExists(t, filepath.Join(dir, "export.csv"))
+
+	workbook := filepath.Join(dir, "export.xlsx")
+	require.NoError(t, exportTables(ctx, &stdout, tables, FormatXLSX, "config.xml", workbook, nil, true))
+
+	data, err := os.ReadFile(workbook)
+	require.NoError(t, err)
+	assert.Equal(t, "PK", string(data[:2]), "workbooks are zip packages")
+}
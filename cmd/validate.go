@@ -2,21 +2,49 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/junit"
 	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/EvilBit-Labs/opnDossier/internal/validator"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
 	"github.com/spf13/cobra"
 )
 
 // init registers the validate command with the root command for the CLI.
 func init() {
 	rootCmd.AddCommand(validateCmd)
+
+	addWaiverFlag(validateCmd)
+
+	validateCmd.Flags().
+		StringVarP(&validateFormat, "format", "f", formatText, "Output format for validation results (text, sarif, junit)")
+	setFlagAnnotation(validateCmd.Flags(), "format", []string{"output"})
 }
 
+// formatText is the human-readable output format of the validate command.
+const formatText = "text"
+
+// validateFormat is the output format of the validate command.
+var validateFormat string //nolint:gochecknoglobals // Cobra flag variable
+
+// ErrUnsupportedValidateFormat indicates an output format the validate command cannot write.
+var ErrUnsupportedValidateFormat = errors.New("unsupported validate output format")
+
 var validateCmd = &cobra.Command{ //nolint:gochecknoglobals // Cobra command
 	Use:     "validate [file ...]",
 	Short:   "Validate OPNsense configuration files",
@@ -45,6 +73,29 @@ Examples:
 
   # Validate with quiet mode (only show errors)
   opnDossier --quiet validate config.xml
+
+  # Accept known validation errors listed in a waiver file
+  opnDossier validate config.xml --waivers waivers.yaml
+
+  # Write the validation results as SARIF for code scanning dashboards
+  opnDossier validate config.xml --format sarif > validate.sarif
+
+  # Write the validation results as a JUnit test report for CI servers
+  opnDossier validate config1.xml config2.xml --format junit > validate.junit.xml
+
+Validation errors matched by an active waiver (see --waivers) are listed as
+waived and do not fail the validation. Expired waivers fail the validation.
+
+With --format sarif, the results of all files are written to standard output
+as a single SARIF 2.1.0 log, located by line in each file. Waived errors are
+reported as suppressed results.
+
+With --format junit, the results are written to standard output as a JUnit
+XML report with a test suite for every file and a test case for XML syntax
+and every configuration section the validator checks. Expired waivers are
+reported as failures of a separate "waivers" suite.
+
+The exit code is the same for every format.
 `,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -53,13 +104,25 @@ Examples:
 			ctx = context.Background()
 		}
 
+		format := strings.ToLower(validateFormat)
+		if format != formatText && format != FormatSARIF && format != FormatJUnit {
+			return fmt.Errorf("%w: %q (supported: text, sarif, junit)", ErrUnsupportedValidateFormat, validateFormat)
+		}
+
+		waivers, err := loadWaivers(sharedWaiverFile, Cfg)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
 		var wg sync.WaitGroup
 		errs := make(chan error, len(args))
-		validationFailed := false
+		outcomes := make([]validationOutcome, len(args))
 
-		for _, filePath := range args {
+		for i, filePath := range args {
 			wg.Add(1)
-			go func(fp string) {
+			go func(i int, fp string) {
 				defer wg.Done()
 
 				// Create context-aware logger for this goroutine with input file field
@@ -90,12 +153,29 @@ Examples:
 					}
 				}()
 
+				// SARIF results are located by line, so the file is kept in memory for the locator
+				var input io.Reader = file
+				if format == FormatSARIF {
+					data, err := io.ReadAll(file)
+					if err != nil {
+						errs <- fmt.Errorf("failed to read file %s: %w", fp, err)
+						return
+					}
+
+					input = bytes.NewReader(data)
+					outcomes[i].locator = sarif.NewLocator(sarif.ArtifactURI(fp), bytes.NewReader(data))
+				}
+
 				// Parse and validate the XML
 				ctxLogger.Debug("Parsing and validating XML file")
-				p := parser.NewXMLParser()
-				_, err = p.ParseAndValidate(ctx, file)
+				waived, err := parseAndValidate(ctx, input, waivers, now)
+				outcomes[i].path, outcomes[i].waived, outcomes[i].err = fp, waived, err
+
+				if format != formatText {
+					return
+				}
+
 				if err != nil {
-					validationFailed = true
 					ctxLogger.Error("Validation failed", "error", err)
 
 					// Enhanced error handling for different error types
@@ -125,9 +205,14 @@ Examples:
 					return
 				}
 
-				ctxLogger.Info("Validation completed successfully")
+				ctxLogger.Info("Validation completed successfully", "waived", len(waived))
 				fmt.Printf("✅ %s: Valid\n", fp)
-			}(filePath)
+
+				for _, w := range waived {
+					fmt.Printf("   waived %s: %s (waiver %s, owner %s, expires %s)\n",
+						w.err.Field, w.err.Message, w.waiver.ID, w.waiver.Owner, w.waiver.Expires)
+				}
+			}(i, filePath)
 		}
 
 		wg.Wait()
@@ -148,6 +233,41 @@ Examples:
 			return allErrors
 		}
 
+		// The outcomes are complete once every goroutine has finished
+		validationFailed := slices.ContainsFunc(outcomes, func(outcome validationOutcome) bool {
+			return outcome.err != nil
+		})
+
+		if format != formatText {
+			expired := waivers.Expired(now)
+
+			var output string
+			if format == FormatSARIF {
+				output, err = validationSARIF(outcomes, expired)
+			} else {
+				output, err = validationJUnit(outcomes, expired)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(output)
+
+			if validationFailed || len(expired) > 0 {
+				os.Exit(1)
+			}
+
+			return nil
+		}
+
+		// Expired waivers no longer suppress anything and must be renewed or removed
+		for _, w := range waivers.Expired(now) {
+			validationFailed = true
+
+			fmt.Fprintf(os.Stderr, "❌ waiver %s (owner %s) expired on %s\n", w.ID, w.Owner, w.Expires)
+		}
+
 		// Exit with code 1 if validation failed for any files
 		if validationFailed {
 			os.Exit(1)
@@ -156,3 +276,292 @@ Examples:
 		return nil
 	},
 }
+
+// waivedValidationError is a validation error accepted by a waiver.
+type waivedValidationError struct {
+	err    validator.ValidationError
+	waiver *waiver.Waiver
+}
+
+// parseAndValidate parses and validates a configuration. Validation errors matched by an active
+// waiver do not fail the validation; they are returned together with their waiver.
+func parseAndValidate(
+	ctx context.Context,
+	r io.Reader,
+	waivers *waiver.Set,
+	now time.Time,
+) ([]waivedValidationError, error) {
+	p := parser.NewXMLParser()
+	if waivers == nil {
+		_, err := p.ParseAndValidate(ctx, r)
+		return nil, err
+	}
+
+	cfg, err := p.Parse(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		waived    []waivedValidationError
+		remaining []parser.ValidationError
+	)
+
+	for _, validationErr := range validator.ValidateOpnSenseDocument(cfg) {
+		if w := waivers.Match(validationErr.Subject(), now); w != nil {
+			waived = append(waived, waivedValidationError{err: validationErr, waiver: w})
+			continue
+		}
+
+		remaining = append(remaining, parser.ValidationError{
+			Path:    "opnsense." + validationErr.Field,
+			Message: validationErr.Message,
+		})
+	}
+
+	if len(remaining) > 0 {
+		return waived, parser.NewAggregatedValidationError(remaining)
+	}
+
+	return waived, nil
+}
+
+// validationOutcome is the validation result of a file, collected for the exit status and for SARIF
+// or JUnit output.
+type validationOutcome struct {
+	path    string
+	locator *sarif.Locator
+	waived  []waivedValidationError
+	err     error
+}
+
+// Rule IDs of validation results that do not come from a validator check.
+const (
+	sarifRuleXMLSyntax     = "validator/xml-syntax"
+	sarifRuleParse         = "validator/parse"
+	sarifRuleExpiredWaiver = "waiver/expired"
+)
+
+// validationSARIF returns the validation results of all files as a SARIF log. Every validator
+// check becomes a rule; waived errors are reported as suppressed results and expired waivers as
+// results without a location.
+func validationSARIF(outcomes []validationOutcome, expired []waiver.Waiver) (string, error) {
+	builder := sarif.NewBuilder()
+
+	for _, outcome := range outcomes {
+		if outcome.locator == nil {
+			continue
+		}
+
+		var aggregated *parser.AggregatedValidationError
+
+		switch {
+		case outcome.err == nil:
+		case errors.As(outcome.err, &aggregated):
+			for _, e := range aggregated.Errors {
+				validationErr := validator.ValidationError{
+					Field:   strings.TrimPrefix(e.Path, "opnsense."),
+					Message: e.Message,
+				}
+				builder.AddResult(validationResult(builder, outcome.locator, validationErr))
+			}
+		default:
+			ruleID, description := sarifRuleParse, "The configuration could not be parsed."
+			location := outcome.locator.LocateLine(0, 0)
+
+			var syntaxErr *xml.SyntaxError
+			if parseErr := parser.GetParseError(outcome.err); parseErr != nil {
+				ruleID, description = sarifRuleXMLSyntax, "The configuration is not well-formed XML."
+				location = outcome.locator.LocateLine(parseErr.Line, parseErr.Column)
+			} else if errors.As(outcome.err, &syntaxErr) {
+				ruleID, description = sarifRuleXMLSyntax, "The configuration is not well-formed XML."
+				location = outcome.locator.LocateLine(syntaxErr.Line, 0)
+			}
+
+			builder.AddRule(sarif.Rule{
+				ID:                   ruleID,
+				ShortDescription:     &sarif.Message{Text: description},
+				DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelError},
+				Properties:           &sarif.Properties{Tags: []string{validator.Source}},
+			})
+			builder.AddResult(sarif.Result{
+				RuleID:    ruleID,
+				Level:     sarif.LevelError,
+				Message:   sarif.Message{Text: outcome.err.Error()},
+				Locations: []sarif.Location{location},
+			})
+		}
+
+		for _, w := range outcome.waived {
+			result := validationResult(builder, outcome.locator, w.err)
+			result.Suppressions = []sarif.Suppression{{
+				Kind:          "external",
+				Status:        "accepted",
+				Justification: w.waiver.Justification,
+			}}
+
+			builder.AddResult(result)
+		}
+	}
+
+	if len(expired) > 0 {
+		builder.AddRule(sarif.Rule{
+			ID:                   sarifRuleExpiredWaiver,
+			ShortDescription:     &sarif.Message{Text: "A waiver has expired."},
+			Help:                 &sarif.Message{Text: "Renew the waiver or remove it from the waiver file."},
+			DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelError},
+			Properties:           &sarif.Properties{Tags: []string{"waiver"}},
+		})
+	}
+
+	for _, w := range expired {
+		builder.AddResult(sarif.Result{
+			RuleID:  sarifRuleExpiredWaiver,
+			Level:   sarif.LevelError,
+			Message: sarif.Message{Text: fmt.Sprintf("waiver %s (owner %s) expired on %s", w.ID, w.Owner, w.Expires)},
+		})
+	}
+
+	return builder.Log().ToJSON()
+}
+
+// validationResult converts a validation error into a result of the rule of its check.
+func validationResult(
+	builder *sarif.Builder,
+	locator *sarif.Locator,
+	validationErr validator.ValidationError,
+) sarif.Result {
+	check := validationErr.Check()
+	ruleID := validator.Source + "/" + check
+
+	builder.AddRule(sarif.Rule{
+		ID:                   ruleID,
+		ShortDescription:     &sarif.Message{Text: "Invalid value of " + check},
+		Help:                 &sarif.Message{Text: "Correct the value in the configuration or accept it with a waiver."},
+		DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelError},
+		Properties:           &sarif.Properties{Tags: []string{validator.Source}},
+	})
+
+	return sarif.Result{
+		RuleID:              ruleID,
+		Level:               sarif.LevelError,
+		Message:             sarif.Message{Text: validationErr.Message},
+		Locations:           []sarif.Location{locator.Locate("", validationErr.Field)},
+		PartialFingerprints: map[string]string{sarif.FingerprintKey: validationErr.Subject().Fingerprint},
+	}
+}
+
+// validationJUnit returns the validation results of all files as a JUnit report. Every file is a
+// suite with a test case for XML syntax and for every section the validator checks; waived errors
+// are listed in the output of their test case and expired waivers fail a suite of their own.
+func validationJUnit(outcomes []validationOutcome, expired []waiver.Waiver) (string, error) {
+	report := junit.NewReport(constants.AppName)
+
+	for _, outcome := range outcomes {
+		if outcome.path == "" {
+			continue
+		}
+
+		report.AddSuite(validationSuite(outcome))
+	}
+
+	if len(expired) > 0 {
+		suite := junit.Suite{Name: "waivers"}
+
+		for _, w := range expired {
+			suite.AddCase(junit.Case{
+				Name:      w.ID,
+				Classname: "waiver",
+				Failure: &junit.Problem{
+					Message: fmt.Sprintf("waiver %s (owner %s) expired on %s", w.ID, w.Owner, w.Expires),
+					Type:    "expired",
+					Text:    "Renew the waiver or remove it from the waiver file.",
+				},
+			})
+		}
+
+		report.AddSuite(suite)
+	}
+
+	return report.ToXML()
+}
+
+// validationSuite returns the test suite of a validated file.
+func validationSuite(outcome validationOutcome) junit.Suite {
+	suite := junit.Suite{Name: outcome.path}
+	syntax := junit.Case{Name: "xml-syntax", Classname: validator.Source}
+
+	var aggregated *parser.AggregatedValidationError
+	if outcome.err != nil && !errors.As(outcome.err, &aggregated) {
+		syntax.Failure = &junit.Problem{Message: "the configuration could not be parsed", Type: "parse", Text: outcome.err.Error()}
+		suite.AddCase(syntax)
+
+		for _, section := range validator.Sections() {
+			suite.AddCase(junit.Case{
+				Name:      section,
+				Classname: validator.Source,
+				Skipped:   &junit.Skipped{Message: "not validated: the configuration could not be parsed"},
+			})
+		}
+
+		return suite
+	}
+
+	suite.AddCase(syntax)
+
+	failed := make(map[string][]validator.ValidationError)
+	if aggregated != nil {
+		for _, e := range aggregated.Errors {
+			validationErr := validator.ValidationError{Field: strings.TrimPrefix(e.Path, "opnsense."), Message: e.Message}
+			failed[validationErr.Section()] = append(failed[validationErr.Section()], validationErr)
+		}
+	}
+
+	waived := make(map[string][]waivedValidationError)
+	for _, w := range outcome.waived {
+		waived[w.err.Section()] = append(waived[w.err.Section()], w)
+	}
+
+	sections := validator.Sections()
+	for _, section := range slices.Sorted(maps.Keys(failed)) {
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+
+	for _, section := range sections {
+		c := junit.Case{Name: section, Classname: validator.Source}
+
+		if errs := failed[section]; len(errs) > 0 {
+			lines := make([]string, 0, len(errs))
+			for _, e := range errs {
+				lines = append(lines, e.Field+": "+e.Message)
+			}
+
+			message := errs[0].Message
+			if len(errs) > 1 {
+				message = fmt.Sprintf("%s (and %d more)", message, len(errs)-1)
+			}
+
+			c.Failure = &junit.Problem{Message: message, Type: "validation", Text: strings.Join(lines, "\n") + "\n"}
+		}
+
+		if ws := waived[section]; len(ws) > 0 {
+			lines := make([]string, 0, len(ws))
+			for _, w := range ws {
+				lines = append(lines, fmt.Sprintf("%s: %s (waiver %s, owner %s, expires %s)",
+					w.err.Field, w.err.Message, w.waiver.ID, w.waiver.Owner, w.waiver.Expires))
+			}
+
+			c.SystemOut = &junit.Output{Text: "Waived validation errors:\n" + strings.Join(lines, "\n") + "\n"}
+
+			if c.Failure == nil {
+				c.Skipped = &junit.Skipped{Message: "waived by " + ws[0].waiver.ID + ": " + ws[0].waiver.Justification}
+			}
+		}
+
+		suite.AddCase(c)
+	}
+
+	return suite
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/junit"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif/sariftest"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseAndValidateWaivers tests that waived validation errors no longer fail validation.
+func TestParseAndValidateWaivers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	validate := func(waivers *waiver.Set) ([]waivedValidationError, error) {
+		f, err := os.Open("../testdata/sample.config.7.xml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		return parseAndValidate(ctx, f, waivers, now)
+	}
+
+	_, err := validate(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dhcpd.opt2")
+
+	waivers, err := waiver.Parse(strings.NewReader(`
+waivers:
+  - id: WVR-DHCP
+    source: validator
+    component: dhcpd.*
+    justification: DHCP on opt2 is configured by the provisioning system
+    owner: network-team
+    expires: 2025-12-31
+`))
+	require.NoError(t, err)
+
+	waived, err := validate(waivers)
+	require.NoError(t, err)
+	require.NotEmpty(t, waived)
+	assert.Equal(t, "WVR-DHCP", waived[0].waiver.ID)
+	assert.Equal(t, "dhcpd.opt2", waived[0].err.Field)
+
+	now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = validate(waivers)
+	require.Error(t, err, "expired waivers no longer apply")
+}
+
+// TestValidationSARIF tests the SARIF log of validation results.
+func TestValidationSARIF(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	outcome := func(path string, data []byte, waivers *waiver.Set) validationOutcome {
+		waived, err := parseAndValidate(ctx, bytes.NewReader(data), waivers, now)
+
+		return validationOutcome{
+			locator: sarif.NewLocator(sarif.ArtifactURI(path), bytes.NewReader(data)),
+			waived:  waived,
+			err:     err,
+		}
+	}
+
+	invalid, err := os.ReadFile("../testdata/sample.config.7.xml")
+	require.NoError(t, err)
+
+	waivers, err := waiver.Parse(strings.NewReader(`
+waivers:
+  - id: WVR-DHCP
+    source: validator
+    component: dhcpd.*
+    justification: DHCP on opt2 is configured by the provisioning system
+    owner: network-team
+    expires: 2025-12-31
+  - id: WVR-OLD
+    source: validator
+    component: system.*
+    justification: Superseded
+    owner: network-team
+    expires: 2024-06-30
+`))
+	require.NoError(t, err)
+
+	outcomes := []validationOutcome{
+		outcome("../testdata/sample.config.7.xml", invalid, nil),
+		outcome("waived.xml", invalid, waivers),
+		outcome("broken.xml", []byte("<opnsense>\n<system>\n<hostname>fw</hostname\n</opnsense>"), nil),
+	}
+
+	output, err := validationSARIF(outcomes, waivers.Expired(now))
+	require.NoError(t, err)
+	sariftest.RequireValid(t, output)
+
+	var log sarif.Log
+	require.NoError(t, json.Unmarshal([]byte(output), &log))
+	require.Len(t, log.Runs, 1)
+
+	results := make(map[string][]sarif.Result)
+	for _, result := range log.Runs[0].Results {
+		assert.Equal(t, sarif.LevelError, result.Level)
+
+		uri := ""
+		if len(result.Locations) > 0 {
+			uri = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+		}
+
+		results[uri] = append(results[uri], result)
+	}
+
+	require.NotEmpty(t, results["../testdata/sample.config.7.xml"])
+
+	dhcp := results["../testdata/sample.config.7.xml"][0]
+	assert.Equal(t, "validator/dhcpd.*", dhcp.RuleID)
+	assert.NotEmpty(t, dhcp.PartialFingerprints[sarif.FingerprintKey])
+	require.NotNil(t, dhcp.Locations[0].PhysicalLocation.Region)
+	assert.Positive(t, dhcp.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Empty(t, dhcp.Suppressions)
+
+	require.NotEmpty(t, results["waived.xml"])
+	for _, result := range results["waived.xml"] {
+		require.Len(t, result.Suppressions, 1, "waived errors are suppressed")
+		assert.Equal(t, "accepted", result.Suppressions[0].Status)
+	}
+
+	require.Len(t, results["broken.xml"], 1)
+	syntax := results["broken.xml"][0]
+	assert.Equal(t, sarifRuleXMLSyntax, syntax.RuleID)
+	require.NotNil(t, syntax.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 4, syntax.Locations[0].PhysicalLocation.Region.StartLine, "the line the decoder reports")
+
+	require.Len(t, results[""], 1)
+	assert.Equal(t, sarifRuleExpiredWaiver, results[""][0].RuleID)
+	assert.Contains(t, results[""][0].Message.Text, "WVR-OLD")
+}
+
+// TestValidationJUnit tests the JUnit report of validation results.
+func TestValidationJUnit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	outcome := func(path string, data []byte, waivers *waiver.Set) validationOutcome {
+		waived, err := parseAndValidate(ctx, bytes.NewReader(data), waivers, now)
+
+		return validationOutcome{path: path, waived: waived, err: err}
+	}
+
+	invalid, err := os.ReadFile("../testdata/sample.config.7.xml")
+	require.NoError(t, err)
+
+	waivers, err := waiver.Parse(strings.NewReader(`
+waivers:
+  - id: WVR-DHCP
+    source: validator
+    component: dhcpd.*
+    justification: DHCP on opt2 is configured by the provisioning system
+    owner: network-team
+    expires: 2025-12-31
+  - id: WVR-OLD
+    source: validator
+    component: system.*
+    justification: Superseded
+    owner: network-team
+    expires: 2024-06-30
+`))
+	require.NoError(t, err)
+
+	outcomes := []validationOutcome{
+		outcome("invalid.xml", invalid, nil),
+		outcome("waived.xml", invalid, waivers),
+		outcome("broken.xml", []byte("<opnsense>\n<system>\n<hostname>fw</hostname\n</opnsense>"), nil),
+	}
+
+	output, err := validationJUnit(outcomes, waivers.Expired(now))
+	require.NoError(t, err)
+
+	var report junit.Report
+	require.NoError(t, xml.Unmarshal([]byte(output), &report))
+	require.Len(t, report.Suites, 4)
+
+	cases := func(suite junit.Suite) map[string]junit.Case {
+		byName := make(map[string]junit.Case, len(suite.Cases))
+		for _, c := range suite.Cases {
+			byName[c.Name] = c
+		}
+
+		return byName
+	}
+
+	invalidCases := cases(report.Suites[0])
+	assert.Equal(t, "invalid.xml", report.Suites[0].Name)
+	assert.Nil(t, invalidCases["xml-syntax"].Failure)
+	require.NotNil(t, invalidCases["dhcpd"].Failure)
+	assert.Contains(t, invalidCases["dhcpd"].Failure.Text, "dhcpd.opt2: ")
+	assert.Nil(t, invalidCases["filter"].Failure)
+
+	waivedCases := cases(report.Suites[1])
+	require.NotNil(t, waivedCases["dhcpd"].Skipped, "sections with only waived errors are skipped")
+	assert.Contains(t, waivedCases["dhcpd"].Skipped.Message, "WVR-DHCP")
+	require.NotNil(t, waivedCases["dhcpd"].SystemOut)
+	assert.Contains(t, waivedCases["dhcpd"].SystemOut.Text, "dhcpd.opt2")
+
+	brokenCases := cases(report.Suites[2])
+	require.NotNil(t, brokenCases["xml-syntax"].Failure)
+	assert.NotNil(t, brokenCases["system"].Skipped, "sections of unparsable files are not validated")
+	assert.Equal(t, 1, report.Suites[2].Failures)
+
+	assert.Equal(t, "waivers", report.Suites[3].Name)
+	require.Len(t, report.Suites[3].Cases, 1)
+	assert.Equal(t, "WVR-OLD", report.Suites[3].Cases[0].Name)
+	assert.NotNil(t, report.Suites[3].Cases[0].Failure)
+}
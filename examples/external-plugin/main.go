@@ -0,0 +1,147 @@
+// Command external-plugin is an example opnDossier compliance plugin that runs as a separate
+// executable and speaks the JSON plugin protocol over stdin and stdout.
+//
+// It checks a small site documentation policy. Build it into a plugin directory and point
+// opnDossier at that directory:
+//
+//	go build -o ~/.opnDossier/plugins/site-policy ./examples/external-plugin
+//	opnDossier audit config.xml --mode blue --plugin-dir ~/.opnDossier/plugins --plugins site-policy
+//
+// Plugins written in other languages implement the same protocol; see the plugin
+// development guide for the message format.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin/external"
+)
+
+func main() {
+	if err := external.Serve(os.Stdin, os.Stdout, newPlugin()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// sitePolicy implements plugin.CompliancePlugin for the site documentation policy.
+type sitePolicy struct {
+	controls []plugin.Control
+}
+
+func newPlugin() *sitePolicy {
+	return &sitePolicy{
+		controls: []plugin.Control{
+			{
+				ID:          "SITE-001",
+				Title:       "Interface Descriptions",
+				Description: "Every interface must have a description",
+				Category:    "Documentation",
+				Severity:    "low",
+				Rationale:   "Descriptions tell operators which network an interface serves",
+				Remediation: "Set a description under Interfaces > [name]",
+				Tags:        []string{"documentation", "interfaces"},
+			},
+			{
+				ID:          "SITE-002",
+				Title:       "Firewall Rule Descriptions",
+				Description: "Every firewall rule must have a description",
+				Category:    "Documentation",
+				Severity:    "medium",
+				Rationale:   "Undocumented rules cannot be reviewed or safely removed",
+				Remediation: "Describe the purpose and owner of each rule under Firewall > Rules",
+				Tags:        []string{"documentation", "firewall-rules"},
+			},
+			{
+				ID:          "SITE-003",
+				Title:       "Site Domain",
+				Description: "The system domain must not be the default localdomain",
+				Category:    "Documentation",
+				Severity:    "low",
+				Rationale:   "The domain identifies the site the firewall belongs to",
+				Remediation: "Set the site domain under System > Settings > General",
+				Tags:        []string{"documentation", "system"},
+			},
+		},
+	}
+}
+
+func (p *sitePolicy) Name() string        { return "site-policy" }
+func (p *sitePolicy) Version() string     { return "1.0.0" }
+func (p *sitePolicy) Description() string { return "Example site documentation policy" }
+
+func (p *sitePolicy) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	var findings []plugin.Finding
+
+	var interfaces []string
+	for _, name := range config.Interfaces.Names() {
+		if iface, _ := config.Interfaces.Get(name); strings.TrimSpace(iface.Descr) == "" {
+			interfaces = append(interfaces, name)
+		}
+	}
+
+	if len(interfaces) > 0 {
+		findings = append(findings, p.finding("SITE-001", "interfaces", "interfaces without description: %s",
+			strings.Join(interfaces, ", ")))
+	}
+
+	var rules []string
+	for i, rule := range config.Filter.Rule {
+		if strings.TrimSpace(rule.Descr) == "" {
+			rules = append(rules, fmt.Sprintf("filter.rule[%d]", i))
+		}
+	}
+
+	if len(rules) > 0 {
+		findings = append(findings, p.finding("SITE-002", "filter.rule", "rules without description: %s",
+			strings.Join(rules, ", ")))
+	}
+
+	if domain := config.System.Domain; domain == "" || domain == "localdomain" {
+		findings = append(findings, p.finding("SITE-003", "system.domain", "domain is %q", domain))
+	}
+
+	return findings
+}
+
+func (p *sitePolicy) GetControls() []plugin.Control {
+	return p.controls
+}
+
+func (p *sitePolicy) GetControlByID(id string) (*plugin.Control, error) {
+	for _, control := range p.controls {
+		if control.ID == id {
+			return &control, nil
+		}
+	}
+
+	return nil, plugin.ErrControlNotFound
+}
+
+func (p *sitePolicy) ValidateConfiguration() error {
+	if len(p.controls) == 0 {
+		return plugin.ErrNoControlsDefined
+	}
+
+	return nil
+}
+
+// finding builds a finding for a control.
+func (p *sitePolicy) finding(id, component, format string, args ...any) plugin.Finding {
+	control, _ := p.GetControlByID(id)
+
+	return plugin.Finding{
+		Type:           "compliance",
+		Title:          control.Title,
+		Description:    fmt.Sprintf(format, args...),
+		Recommendation: control.Remediation,
+		Component:      component,
+		Reference:      id,
+		References:     []string{id},
+		Tags:           control.Tags,
+	}
+}
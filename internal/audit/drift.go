@@ -0,0 +1,68 @@
+package audit
+
+import "github.com/EvilBit-Labs/opnDossier/internal/baseline"
+
+// Drift summarises how the findings of a report changed relative to a baseline report.
+type Drift struct {
+	New       int       `json:"new"                yaml:"new"`
+	Unchanged int       `json:"unchanged"          yaml:"unchanged"`
+	Resolved  []Finding `json:"resolved,omitempty" yaml:"resolved,omitempty"`
+}
+
+// CompareBaseline classifies the findings as new or unchanged relative to a baseline report and
+// records the baseline findings that are no longer reported as resolved. Waived findings are
+// classified as well but not counted, so that only active findings raise the new count.
+func (r *Report) CompareBaseline(b *baseline.Baseline) {
+	if b == nil {
+		return
+	}
+
+	r.assignFingerprints()
+
+	comparison := b.Compare()
+	drift := &Drift{}
+
+	for i := range r.Findings {
+		finding := &r.Findings[i]
+		finding.Drift = comparison.Classify(finding.Fingerprint)
+
+		if finding.Drift == baseline.StatusNew {
+			drift.New++
+		} else {
+			drift.Unchanged++
+		}
+	}
+
+	for i := range r.Waived {
+		r.Waived[i].Drift = comparison.Classify(r.Waived[i].Fingerprint)
+	}
+
+	for _, entry := range comparison.Resolved() {
+		drift.Resolved = append(drift.Resolved, Finding{
+			Title:          entry.Title,
+			Severity:       parseSeverity(entry.Severity),
+			Description:    entry.Description,
+			Recommendation: entry.Recommendation,
+			Component:      entry.Component,
+			Control:        entry.Control,
+			Source:         entry.Source,
+			Fingerprint:    entry.Fingerprint,
+			Drift:          baseline.StatusResolved,
+		})
+	}
+
+	r.Drift = drift
+}
+
+// NewFindings returns the findings that are not in the baseline report, ordered by severity.
+func (r *Report) NewFindings() []Finding {
+	var findings []Finding
+
+	for _, finding := range r.SortedFindings() {
+		if finding.Drift == baseline.StatusNew {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
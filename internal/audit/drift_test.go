@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/baseline"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateReportFor(t *testing.T, cfg *model.OpnSenseDocument, config *ModeConfig) *Report {
+	t.Helper()
+
+	report, err := NewModeController(NewPluginRegistry(), log.New(io.Discard)).
+		GenerateReport(context.Background(), cfg, config)
+	require.NoError(t, err)
+
+	return report
+}
+
+// baselineOf reads a report back the way the JSON report of a previous run is read.
+func baselineOf(t *testing.T, report *Report) *baseline.Baseline {
+	t.Helper()
+
+	output, err := report.ToJSON()
+	require.NoError(t, err)
+
+	b, err := baseline.Parse(strings.NewReader(output))
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestReport_CompareBaseline_Reordered(t *testing.T) {
+	previous := generateReportFor(t, exposedConfig(), &ModeConfig{Mode: ModeRed})
+
+	cfg := exposedConfig()
+	slices.Reverse(cfg.Filter.Rule)
+	slices.Reverse(cfg.Nat.Inbound)
+
+	report := generateReportFor(t, cfg, &ModeConfig{Mode: ModeRed, Baseline: baselineOf(t, previous)})
+
+	require.NotNil(t, report.Drift)
+	assert.Empty(t, report.NewFindings(), "reordering rules does not create new findings")
+	assert.Empty(t, report.Drift.Resolved)
+	assert.Equal(t, len(report.Findings), report.Drift.Unchanged)
+
+	for _, finding := range report.Findings {
+		assert.Equal(t, baseline.StatusUnchanged, finding.Drift, finding.Title)
+	}
+}
+
+func TestReport_CompareBaseline_Drift(t *testing.T) {
+	previous := generateReportFor(t, exposedConfig(), &ModeConfig{Mode: ModeRed})
+
+	cfg := exposedConfig()
+	cfg.System.WebGUI.Protocol = ""
+	cfg.Nat.Inbound = append(cfg.Nat.Inbound, model.InboundRule{
+		Interface: model.InterfaceList{"wan"}, Protocol: "udp", ExternalPort: "51820", InternalIP: "192.168.1.30",
+	})
+
+	report := generateReportFor(t, cfg, &ModeConfig{Mode: ModeRed, Baseline: baselineOf(t, previous)})
+	require.NotNil(t, report.Drift)
+
+	added := report.NewFindings()
+	require.Len(t, added, 1)
+	assert.Equal(t, "WAN Port Forward", added[0].Title)
+	assert.Equal(t, 1, report.Drift.New)
+
+	require.NotEmpty(t, report.Drift.Resolved)
+	for _, resolved := range report.Drift.Resolved {
+		assert.Equal(t, baseline.StatusResolved, resolved.Drift)
+		assert.NotEmpty(t, resolved.Fingerprint)
+	}
+
+	assert.True(t, slices.ContainsFunc(report.Drift.Resolved, func(f Finding) bool {
+		return f.Component == "system.webgui.protocol"
+	}))
+
+	output, err := report.Render("markdown", nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "## Baseline Drift")
+	assert.Contains(t, output, "### New Findings")
+	assert.Contains(t, output, "### Resolved Findings")
+	assert.Contains(t, output, added[0].Fingerprint)
+}
+
+func TestReport_CompareBaseline_None(t *testing.T) {
+	report := generateReportFor(t, exposedConfig(), &ModeConfig{Mode: ModeRed})
+
+	assert.Nil(t, report.Drift)
+
+	for _, finding := range report.Findings {
+		assert.NotEmpty(t, finding.Fingerprint)
+		assert.Empty(t, finding.Drift)
+	}
+}
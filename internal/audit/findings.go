@@ -0,0 +1,261 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+)
+
+// Finding sources that are not compliance plugins.
+const (
+	// SourceProcessor marks findings produced by the core processor analysis.
+	SourceProcessor = "processor"
+	// SourceRecon marks findings produced by the red team attack surface analysis.
+	SourceRecon = "recon"
+)
+
+// ErrInvalidSeverity is returned for severity names that are not a finding severity.
+var ErrInvalidSeverity = errors.New("invalid severity")
+
+// pluginResultsKey is the key under which plugin compliance results are stored in Report.Compliance.
+const pluginResultsKey = "plugin_results"
+
+// severityOrder lists the severities from most to least severe.
+func severityOrder() []processor.Severity {
+	return []processor.Severity{
+		processor.SeverityCritical,
+		processor.SeverityHigh,
+		processor.SeverityMedium,
+		processor.SeverityLow,
+		processor.SeverityInfo,
+	}
+}
+
+// addProcessorFindings runs the core processor analysis and merges its findings into the report.
+func (mc *ModeController) addProcessorFindings(ctx context.Context, report *Report, config *ModeConfig) error {
+	coreProcessor, err := processor.NewCoreProcessor()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %w", err)
+	}
+
+	opts := append([]processor.Option{
+		processor.WithSecurityAnalysis(),
+		processor.WithComplianceCheck(),
+	}, config.ProcessorOptions...)
+
+	result, err := coreProcessor.Process(ctx, report.Configuration, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to analyse configuration: %w", err)
+	}
+
+	report.AddProcessorFindings(result.Findings)
+	report.Metadata["processor_findings_count"] = result.TotalFindings()
+
+	return nil
+}
+
+// runCompliancePlugins runs the selected compliance plugins and merges their findings into the report.
+// A failing plugin run is recorded in the report metadata rather than aborting report generation.
+func (mc *ModeController) runCompliancePlugins(ctx context.Context, report *Report, pluginNames []string) {
+	report.Metadata["compliance_check_time"] = time.Now().Format(time.RFC3339)
+
+	complianceResult, err := mc.registry.RunComplianceChecks(ctx, report.Configuration, pluginNames)
+	if err != nil {
+		mc.logger.Warn("Failed to run compliance checks", "error", err)
+		report.Metadata["compliance_check_status"] = "failed"
+		report.Metadata["compliance_check_error"] = err.Error()
+
+		return
+	}
+
+	for name, run := range complianceResult.Runs {
+		if run.Status != RunStatusCompleted {
+			mc.logger.Warn("Compliance plugin failed", "plugin", name, "status", run.Status, "error", run.Error)
+		}
+	}
+
+	report.Compliance[pluginResultsKey] = *complianceResult
+	report.AddComplianceFindings(complianceResult)
+	report.Metadata["compliance_check_status"] = "completed"
+}
+
+// AddProcessorFindings appends the findings of a core processor report, keeping their severity.
+func (r *Report) AddProcessorFindings(findings processor.Findings) {
+	buckets := map[processor.Severity][]processor.Finding{
+		processor.SeverityCritical: findings.Critical,
+		processor.SeverityHigh:     findings.High,
+		processor.SeverityMedium:   findings.Medium,
+		processor.SeverityLow:      findings.Low,
+		processor.SeverityInfo:     findings.Info,
+	}
+
+	for _, severity := range severityOrder() {
+		for _, finding := range buckets[severity] {
+			var tags []string
+			if finding.Type != "" {
+				tags = []string{finding.Type}
+			}
+
+			r.Findings = append(r.Findings, Finding{
+				Title:          finding.Title,
+				Severity:       severity,
+				Description:    finding.Description,
+				Recommendation: finding.Recommendation,
+				Tags:           tags,
+				Component:      finding.Component,
+				Object:         finding.Object,
+				Control:        finding.Reference,
+				Source:         SourceProcessor,
+			})
+		}
+	}
+}
+
+// AddComplianceFindings appends the findings of a compliance plugin run. The severity of each
+// finding is taken from the finding itself, then from the control it references, and defaults to medium.
+func (r *Report) AddComplianceFindings(result *ComplianceResult) {
+	if result == nil {
+		return
+	}
+
+	for _, finding := range result.Findings {
+		control, pluginName := lookupControl(result, finding)
+
+		converted := Finding{
+			Title:          finding.Title,
+			Severity:       processor.SeverityMedium,
+			Description:    finding.Description,
+			Recommendation: finding.Recommendation,
+			Tags:           finding.Tags,
+			Component:      finding.Component,
+			Control:        findingReference(finding),
+			Source:         pluginName,
+		}
+
+		switch {
+		case finding.Severity != "":
+			converted.Severity = parseSeverity(finding.Severity)
+		case control != nil:
+			converted.Severity = parseSeverity(control.Severity)
+		}
+
+		r.Findings = append(r.Findings, converted)
+	}
+}
+
+// SeverityCounts returns the number of findings per severity.
+func (r *Report) SeverityCounts() map[processor.Severity]int {
+	counts := make(map[processor.Severity]int, len(severityOrder()))
+	for _, finding := range r.Findings {
+		counts[finding.Severity]++
+	}
+
+	return counts
+}
+
+// SortedFindings returns the findings ordered by severity, keeping the original order within a severity.
+func (r *Report) SortedFindings() []Finding {
+	rank := make(map[processor.Severity]int)
+	for i, severity := range severityOrder() {
+		rank[severity] = i
+	}
+
+	sorted := slices.Clone(r.Findings)
+	slices.SortStableFunc(sorted, func(a, b Finding) int {
+		return rank[a.Severity] - rank[b.Severity]
+	})
+
+	return sorted
+}
+
+// findingReference returns the control ID a plugin finding refers to.
+func findingReference(finding plugin.Finding) string {
+	if finding.Reference != "" {
+		return finding.Reference
+	}
+
+	if len(finding.References) > 0 {
+		return finding.References[0]
+	}
+
+	return ""
+}
+
+// lookupControl finds the control referenced by a plugin finding and the plugin that defines it.
+func lookupControl(result *ComplianceResult, finding plugin.Finding) (*plugin.Control, string) {
+	refs := finding.References
+	if finding.Reference != "" {
+		refs = append([]string{finding.Reference}, refs...)
+	}
+
+	names := make([]string, 0, len(result.PluginInfo))
+	for name := range result.PluginInfo {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, ref := range refs {
+		for _, name := range names {
+			for _, control := range result.PluginInfo[name].Controls {
+				if control.ID == ref {
+					return &control, name
+				}
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// ParseSeverity parses a finding severity name such as "high", as given for a severity threshold.
+func ParseSeverity(value string) (processor.Severity, error) {
+	severity := processor.Severity(strings.ToLower(strings.TrimSpace(value)))
+	if !slices.Contains(severityOrder(), severity) {
+		return "", fmt.Errorf("%w: %q (valid: critical, high, medium, low, info)", ErrInvalidSeverity, value)
+	}
+
+	return severity, nil
+}
+
+// AtOrAbove reports whether a severity is at least as severe as the threshold. Every severity is
+// at or above an empty threshold.
+func AtOrAbove(severity, threshold processor.Severity) bool {
+	if threshold == "" {
+		return true
+	}
+
+	rank := slices.Index(severityOrder(), severity)
+
+	return rank >= 0 && rank <= slices.Index(severityOrder(), threshold)
+}
+
+// FindingsAtOrAbove returns the findings at or above the severity threshold, ordered by severity.
+// Waived findings are not included.
+func (r *Report) FindingsAtOrAbove(threshold processor.Severity) []Finding {
+	var findings []Finding
+
+	for _, finding := range r.SortedFindings() {
+		if AtOrAbove(finding.Severity, threshold) {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// parseSeverity converts a control severity into a processor severity, defaulting to medium.
+func parseSeverity(value string) processor.Severity {
+	severity := processor.Severity(strings.ToLower(strings.TrimSpace(value)))
+	if slices.Contains(severityOrder(), severity) {
+		return severity
+	}
+
+	return processor.SeverityMedium
+}
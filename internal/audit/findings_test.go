@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity(" High ")
+	require.NoError(t, err)
+	assert.Equal(t, processor.SeverityHigh, severity)
+
+	_, err = ParseSeverity("severe")
+	require.ErrorIs(t, err, ErrInvalidSeverity)
+}
+
+func TestAtOrAbove(t *testing.T) {
+	assert.True(t, AtOrAbove(processor.SeverityCritical, processor.SeverityHigh))
+	assert.True(t, AtOrAbove(processor.SeverityHigh, processor.SeverityHigh))
+	assert.False(t, AtOrAbove(processor.SeverityMedium, processor.SeverityHigh))
+	assert.True(t, AtOrAbove(processor.SeverityInfo, ""), "every severity is at or above an empty threshold")
+	assert.False(t, AtOrAbove("unknown", processor.SeverityInfo))
+}
+
+func TestReport_FindingsAtOrAbove(t *testing.T) {
+	report := &Report{Findings: []Finding{
+		{Title: "medium", Severity: processor.SeverityMedium},
+		{Title: "critical", Severity: processor.SeverityCritical},
+		{Title: "low", Severity: processor.SeverityLow},
+	}}
+
+	findings := report.FindingsAtOrAbove(processor.SeverityMedium)
+	require.Len(t, findings, 2)
+	assert.Equal(t, "critical", findings[0].Title)
+	assert.Equal(t, "medium", findings[1].Title)
+	assert.Empty(t, report.FindingsAtOrAbove(processor.SeverityCritical)[1:])
+}
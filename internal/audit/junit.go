@@ -0,0 +1,239 @@
+package audit
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/junit"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+)
+
+// ToJUnit returns the report as a JUnit XML test report with a single suite; see JUnitSuite.
+func (r *Report) ToJUnit(name string, threshold processor.Severity) (string, error) {
+	report := junit.NewReport(constants.AppName)
+	report.AddSuite(r.JUnitSuite(name, threshold))
+
+	return report.ToXML()
+}
+
+// JUnitSuite returns the report as the test suite of a configuration file. Every control of the
+// selected plugins is a test case, as is every check of the core analysis that reported a finding.
+// Findings below the severity threshold do not fail their test case and are listed in its output
+// instead; an empty threshold fails every finding. Controls whose findings were all waived, not
+// applicable controls and controls that need a manual review are skipped.
+func (r *Report) JUnitSuite(name string, threshold processor.Severity) junit.Suite {
+	if name == "" {
+		name, _ = r.Metadata["system_hostname"].(string)
+	}
+
+	suite := junit.Suite{Name: orDefault(name, "configuration"), Properties: r.junitProperties()}
+	suite.Timestamp, _ = r.Metadata["generation_time"].(string)
+	controls := r.pluginControls()
+	cases := newJUnitCases(controls)
+
+	for _, finding := range r.SortedFindings() {
+		c := cases.add(finding)
+		c.findings = append(c.findings, finding)
+	}
+
+	for _, waived := range r.Waived {
+		c := cases.add(waived.Finding)
+		c.waived = append(c.waived, waived)
+	}
+
+	result := r.Compliance[pluginResultsKey]
+
+	for _, pluginName := range slices.Sorted(maps.Keys(controls)) {
+		run, ran := result.Runs[pluginName]
+
+		statuses := make(map[string]plugin.ControlResult, len(result.Results[pluginName]))
+		for _, controlResult := range result.Results[pluginName] {
+			statuses[controlResult.ControlID] = controlResult
+		}
+
+		for _, control := range controls[pluginName] {
+			c := junit.Case{Name: control.ID + " " + control.Title, Classname: pluginName}
+
+			if ran && run.Status != RunStatusCompleted {
+				c.Error = &junit.Problem{Message: "plugin run " + run.Status, Type: run.Status, Text: run.Error}
+			} else {
+				controlResult, evaluated := statuses[control.ID]
+				if !evaluated {
+					controlResult = plugin.ControlResult{Severity: control.Severity}
+				}
+
+				cases.controls[controlKey(pluginName, control.ID)].judge(&c, &controlResult, threshold)
+			}
+
+			suite.AddCase(c)
+		}
+	}
+
+	for _, check := range cases.checks {
+		c := junit.Case{Name: check.title, Classname: check.source}
+		check.judge(&c, nil, threshold)
+		suite.AddCase(c)
+	}
+
+	return suite
+}
+
+// junitProperties returns the properties of the suite: the audit mode, the plugins and the host.
+func (r *Report) junitProperties() *junit.Properties {
+	properties := []junit.Property{{Name: "mode", Value: string(r.Mode)}}
+
+	if plugins := r.compliancePlugins(); len(plugins) > 0 {
+		properties = append(properties, junit.Property{Name: "plugins", Value: strings.Join(plugins, ",")})
+	}
+
+	if hostname, ok := r.Metadata["system_hostname"].(string); ok && hostname != "" {
+		properties = append(properties, junit.Property{Name: "hostname", Value: hostname})
+	}
+
+	return &junit.Properties{Properties: properties}
+}
+
+// junitCase collects the findings of a test case.
+type junitCase struct {
+	source   string
+	title    string
+	findings []Finding
+	waived   []WaivedFinding
+}
+
+// junitCases assigns findings to the test case of their plugin control or, failing that, of their
+// check. Checks are kept in the order of their first finding.
+type junitCases struct {
+	plugins  map[string][]plugin.Control
+	controls map[string]*junitCase
+	checks   []*junitCase
+	byRule   map[string]*junitCase
+}
+
+// newJUnitCases returns a test case for every plugin control.
+func newJUnitCases(controls map[string][]plugin.Control) *junitCases {
+	cases := &junitCases{
+		plugins:  controls,
+		controls: make(map[string]*junitCase),
+		byRule:   make(map[string]*junitCase),
+	}
+
+	for pluginName, list := range controls {
+		for _, control := range list {
+			cases.controls[controlKey(pluginName, control.ID)] = &junitCase{source: pluginName, title: control.Title}
+		}
+	}
+
+	return cases
+}
+
+// add returns the test case of a finding, adding the test case of its check if needed.
+func (cs *junitCases) add(finding Finding) *junitCase {
+	if control := matchControl(cs.plugins[finding.Source], finding.Control); control != nil {
+		return cs.controls[controlKey(finding.Source, control.ID)]
+	}
+
+	rule := finding.Source + "/" + ruleSlug(finding.Title)
+	if c, ok := cs.byRule[rule]; ok {
+		return c
+	}
+
+	c := &junitCase{source: finding.Source, title: finding.Title}
+	cs.byRule[rule] = c
+	cs.checks = append(cs.checks, c)
+
+	return c
+}
+
+// controlKey identifies a plugin control.
+func controlKey(pluginName, controlID string) string {
+	return pluginName + "\x00" + controlID
+}
+
+// judge sets the outcome of a test case from its findings and, for plugin controls, the result of
+// the control.
+func (jc *junitCase) judge(c *junit.Case, result *plugin.ControlResult, threshold processor.Severity) {
+	var failing, passing []Finding
+
+	for _, finding := range jc.findings {
+		if AtOrAbove(finding.Severity, threshold) {
+			failing = append(failing, finding)
+		} else {
+			passing = append(passing, finding)
+		}
+	}
+
+	if len(passing) > 0 {
+		c.SystemOut = &junit.Output{Text: "Findings below the failure threshold:\n\n" + describeFindings(passing)}
+	}
+
+	switch {
+	case len(failing) > 0:
+		message := failing[0].Title
+		if len(failing) > 1 {
+			message = fmt.Sprintf("%s (and %d more)", message, len(failing)-1)
+		}
+
+		c.Failure = &junit.Problem{Message: message, Type: string(failing[0].Severity), Text: describeFindings(failing)}
+	case len(passing) > 0:
+	case len(jc.waived) > 0:
+		w := jc.waived[0].Waiver
+		c.Skipped = &junit.Skipped{Message: fmt.Sprintf("waived by %s: %s", w.ID, w.Justification)}
+	case result != nil:
+		judgeControlResult(c, result, threshold)
+	}
+}
+
+// judgeControlResult sets the outcome of a control test case without findings from the control
+// result reported by its plugin.
+func judgeControlResult(c *junit.Case, result *plugin.ControlResult, threshold processor.Severity) {
+	switch result.Status {
+	case plugin.StatusFail:
+		if AtOrAbove(parseSeverity(result.Severity), threshold) {
+			c.Failure = &junit.Problem{
+				Message: orDefault(result.Message, "control failed"),
+				Type:    string(parseSeverity(result.Severity)),
+				Text:    strings.Join(result.Evidence, "\n"),
+			}
+		}
+	case plugin.StatusError:
+		c.Error = &junit.Problem{Message: orDefault(result.Message, "control could not be evaluated"), Type: "error"}
+	case plugin.StatusNotApplicable:
+		c.Skipped = &junit.Skipped{Message: orDefault(result.Message, "not applicable")}
+	case plugin.StatusManualReview:
+		c.Skipped = &junit.Skipped{Message: "manual review required: " + orDefault(result.Message, result.Title)}
+	case plugin.StatusPass:
+	}
+}
+
+// describeFindings returns the description, recommendation and component of findings as the text
+// of a test case.
+func describeFindings(findings []Finding) string {
+	var sb strings.Builder
+
+	for i, finding := range findings {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "[%s] %s\n", strings.ToUpper(string(finding.Severity)), finding.Title)
+
+		if finding.Description != "" {
+			sb.WriteString(finding.Description + "\n")
+		}
+
+		if finding.Recommendation != "" {
+			sb.WriteString("Recommendation: " + finding.Recommendation + "\n")
+		}
+
+		if finding.Component != "" {
+			sb.WriteString("Component: " + finding.Component + "\n")
+		}
+	}
+
+	return sb.String()
+}
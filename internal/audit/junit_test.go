@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/junit"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// junitTestReport returns a report of two plugins with control results of every status.
+func junitTestReport() *Report {
+	controls := func(ids ...string) []plugin.Control {
+		list := make([]plugin.Control, 0, len(ids))
+		for _, id := range ids {
+			list = append(list, plugin.Control{ID: id, Title: "Control " + id, Severity: "high"})
+		}
+
+		return list
+	}
+
+	return &Report{
+		Mode: ModeBlue,
+		Findings: []Finding{
+			{Title: "Open rule", Severity: processor.SeverityHigh, Source: "site", Control: "SITE 1",
+				Description: "Rule allows any", Recommendation: "Restrict the rule"},
+			{Title: "No logging", Severity: processor.SeverityLow, Source: "site", Control: "2"},
+			{Title: "Weak tunable", Severity: processor.SeverityMedium, Source: SourceProcessor},
+			{Title: "Weak tunable", Severity: processor.SeverityCritical, Source: SourceProcessor},
+		},
+		Waived: []WaivedFinding{{
+			Finding: Finding{Title: "No VPN", Severity: processor.SeverityHigh, Source: "site", Control: "3"},
+			Waiver:  waiver.Waiver{ID: "WVR-1", Justification: "No VPN in use"},
+		}},
+		Compliance: map[string]ComplianceResult{
+			pluginResultsKey: {
+				PluginInfo: map[string]PluginInfo{
+					"site":   {Controls: controls("1", "2", "3", "4", "5", "6", "7")},
+					"broken": {Controls: controls("B1")},
+				},
+				Results: map[string][]plugin.ControlResult{
+					"site": {
+						{ControlID: "4", Status: plugin.StatusNotApplicable},
+						{ControlID: "5", Status: plugin.StatusManualReview, Message: "check the runbook"},
+						{ControlID: "6", Status: plugin.StatusError, Message: "no data"},
+						{ControlID: "7", Status: plugin.StatusFail, Severity: "low", Message: "failed quietly"},
+					},
+				},
+				Runs: map[string]PluginRun{
+					"site":   {Status: RunStatusCompleted},
+					"broken": {Status: RunStatusTimeout, Error: "plugin did not finish within 1s"},
+				},
+			},
+		},
+		Metadata: map[string]any{"system_hostname": "fw01", "generation_time": "2025-01-02T03:04:05Z"},
+	}
+}
+
+func TestReport_JUnitSuite(t *testing.T) {
+	suite := junitTestReport().JUnitSuite("config.xml", "")
+
+	assert.Equal(t, "config.xml", suite.Name)
+	assert.Equal(t, "2025-01-02T03:04:05Z", suite.Timestamp)
+	assert.Equal(t, 9, suite.Tests, "every control and every check with findings is a test case")
+
+	cases := make(map[string]junit.Case, len(suite.Cases))
+	for _, c := range suite.Cases {
+		cases[c.Classname+"/"+c.Name] = c
+	}
+
+	broken := cases["broken/B1 Control B1"]
+	require.NotNil(t, broken.Error, "controls of plugins that did not complete are errors")
+	assert.Equal(t, RunStatusTimeout, broken.Error.Type)
+
+	open := cases["site/1 Control 1"]
+	require.NotNil(t, open.Failure)
+	assert.Equal(t, "Open rule", open.Failure.Message)
+	assert.Equal(t, "high", open.Failure.Type)
+	assert.Contains(t, open.Failure.Text, "Rule allows any")
+	assert.Contains(t, open.Failure.Text, "Recommendation: Restrict the rule")
+
+	assert.NotNil(t, cases["site/2 Control 2"].Failure, "every finding fails without a threshold")
+
+	require.NotNil(t, cases["site/3 Control 3"].Skipped)
+	assert.Equal(t, "waived by WVR-1: No VPN in use", cases["site/3 Control 3"].Skipped.Message)
+
+	require.NotNil(t, cases["site/4 Control 4"].Skipped)
+	require.NotNil(t, cases["site/5 Control 5"].Skipped)
+	assert.Equal(t, "manual review required: check the runbook", cases["site/5 Control 5"].Skipped.Message)
+	require.NotNil(t, cases["site/6 Control 6"].Error)
+	require.NotNil(t, cases["site/7 Control 7"].Failure)
+
+	tunable := cases["processor/Weak tunable"]
+	require.NotNil(t, tunable.Failure, "findings of the same check share a test case")
+	assert.Equal(t, "Weak tunable (and 1 more)", tunable.Failure.Message)
+	assert.Equal(t, "critical", tunable.Failure.Type)
+}
+
+func TestReport_JUnitSuite_Threshold(t *testing.T) {
+	suite := junitTestReport().JUnitSuite("", processor.SeverityHigh)
+
+	assert.Equal(t, "fw01", suite.Name, "the suite is named after the host without a file name")
+
+	cases := make(map[string]junit.Case, len(suite.Cases))
+	for _, c := range suite.Cases {
+		cases[c.Classname+"/"+c.Name] = c
+	}
+
+	assert.NotNil(t, cases["site/1 Control 1"].Failure)
+
+	logging := cases["site/2 Control 2"]
+	assert.Nil(t, logging.Failure, "findings below the threshold pass")
+	require.NotNil(t, logging.SystemOut)
+	assert.Contains(t, logging.SystemOut.Text, "[LOW] No logging")
+
+	assert.Nil(t, cases["site/7 Control 7"].Failure, "failed controls below the threshold pass")
+
+	tunable := cases["processor/Weak tunable"]
+	require.NotNil(t, tunable.Failure)
+	assert.Equal(t, "Weak tunable", tunable.Failure.Message)
+	require.NotNil(t, tunable.SystemOut)
+	assert.Contains(t, tunable.SystemOut.Text, "[MEDIUM] Weak tunable")
+}
+
+func TestReport_ToJUnit(t *testing.T) {
+	report := generateTestReport(t, &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}})
+
+	output, err := report.ToJUnit("config.xml", processor.SeverityCritical)
+	require.NoError(t, err)
+
+	var decoded junit.Report
+	require.NoError(t, xml.Unmarshal([]byte(output), &decoded))
+	require.Len(t, decoded.Suites, 1)
+	assert.Equal(t, "config.xml", decoded.Suites[0].Name)
+	assert.Equal(t, decoded.Suites[0].Tests, decoded.Tests)
+	assert.GreaterOrEqual(t, decoded.Tests, len(report.pluginControls()["sans"]))
+}
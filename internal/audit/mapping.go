@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/mapping"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+)
+
+// FrameworkGrouping collects the compliance results of a report by the identifiers of a target framework.
+type FrameworkGrouping struct {
+	Framework string           `json:"framework" yaml:"framework"`
+	Name      string           `json:"name"      yaml:"name"`
+	Groups    []FrameworkGroup `json:"groups"    yaml:"groups"`
+}
+
+// FrameworkGroup holds the plugin controls and findings mapped to a single framework identifier.
+type FrameworkGroup struct {
+	Reference string           `json:"reference"          yaml:"reference"`
+	Controls  []GroupedControl `json:"controls,omitempty" yaml:"controls,omitempty"`
+	Findings  []string         `json:"findings,omitempty" yaml:"findings,omitempty"`
+}
+
+// GroupedControl is a plugin control result within a framework group.
+type GroupedControl struct {
+	Plugin  string        `json:"plugin"  yaml:"plugin"`
+	Control string        `json:"control" yaml:"control"`
+	Status  plugin.Status `json:"status"  yaml:"status"`
+}
+
+// ApplyMapping annotates the findings of the report with the cross-framework references of their
+// control and, when a framework is given, groups the control results and findings by the
+// identifiers of that framework. A nil catalogue selects the embedded mapping.
+func (r *Report) ApplyMapping(catalogue *mapping.Catalogue, framework string) error {
+	if catalogue == nil {
+		catalogue = mapping.Default()
+	}
+
+	r.mapping = catalogue
+
+	for i := range r.Findings {
+		r.Findings[i].MappedReferences = catalogue.References(r.Findings[i].Control)
+	}
+
+	if framework == "" {
+		return nil
+	}
+
+	target, ok := catalogue.Framework(framework)
+	if !ok {
+		return fmt.Errorf("%w: %s", mapping.ErrUnknownFramework, framework)
+	}
+
+	r.Grouping = r.groupByFramework(catalogue, target)
+
+	return nil
+}
+
+// groupByFramework builds the grouping of the report for a target framework. The controls of a plugin
+// whose name is the target framework are grouped under their own ID.
+func (r *Report) groupByFramework(catalogue *mapping.Catalogue, target mapping.Framework) *FrameworkGrouping {
+	groups := make(map[string]*FrameworkGroup)
+
+	group := func(reference string) *FrameworkGroup {
+		if groups[reference] == nil {
+			groups[reference] = &FrameworkGroup{Reference: reference}
+		}
+
+		return groups[reference]
+	}
+
+	if result, ok := r.Compliance[pluginResultsKey]; ok {
+		for _, name := range r.compliancePlugins() {
+			for _, controlResult := range result.Results[name] {
+				for _, reference := range targetReferences(catalogue, target.ID, name, controlResult.ControlID) {
+					entry := group(reference)
+					entry.Controls = append(entry.Controls, GroupedControl{
+						Plugin:  name,
+						Control: controlResult.ControlID,
+						Status:  controlResult.Status,
+					})
+				}
+			}
+		}
+	}
+
+	for _, finding := range r.SortedFindings() {
+		for _, reference := range targetReferences(catalogue, target.ID, finding.Source, finding.Control) {
+			entry := group(reference)
+			entry.Findings = append(entry.Findings, strings.ToUpper(string(finding.Severity))+": "+finding.Title)
+		}
+	}
+
+	grouping := &FrameworkGrouping{
+		Framework: target.ID,
+		Name:      target.Name,
+		Groups:    make([]FrameworkGroup, 0, len(groups)),
+	}
+
+	for _, reference := range slices.Sorted(maps.Keys(groups)) {
+		grouping.Groups = append(grouping.Groups, *groups[reference])
+	}
+
+	return grouping
+}
+
+// targetReferences returns the identifiers of the target framework that a control of the given source maps to.
+func targetReferences(catalogue *mapping.Catalogue, target, source, control string) []string {
+	if control == "" {
+		return nil
+	}
+
+	references := catalogue.References(control)[target]
+	if source == target && !slices.Contains(references, control) {
+		references = append([]string{control}, references...)
+	}
+
+	return references
+}
+
+// mappingCatalogue returns the control mapping applied to the report or the embedded mapping.
+func (r *Report) mappingCatalogue() *mapping.Catalogue {
+	if r.mapping == nil {
+		return mapping.Default()
+	}
+
+	return r.mapping
+}
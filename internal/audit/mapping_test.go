@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/mapping"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/cis"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/sans"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateMappedReport(t *testing.T, framework string, catalogue *mapping.Catalogue) *Report {
+	t.Helper()
+
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(sans.NewPlugin()))
+	require.NoError(t, registry.RegisterPlugin(cis.NewPlugin()))
+
+	report, err := NewModeController(registry, log.New(io.Discard)).GenerateReport(
+		context.Background(),
+		exposedConfig(),
+		&ModeConfig{
+			Mode:             ModeBlue,
+			SelectedPlugins:  []string{"sans", "cis"},
+			Mapping:          catalogue,
+			GroupByFramework: framework,
+		},
+	)
+	require.NoError(t, err)
+
+	return report
+}
+
+// findingByControl returns the first finding of the report that references the control.
+func findingByControl(t *testing.T, report *Report, control string) Finding {
+	t.Helper()
+
+	for _, finding := range report.Findings {
+		if finding.Control == control {
+			return finding
+		}
+	}
+
+	require.Failf(t, "finding not found", "no finding references %s", control)
+
+	return Finding{}
+}
+
+func TestReport_MappedReferences(t *testing.T) {
+	report := generateMappedReport(t, "", nil)
+
+	finding := findingByControl(t, report, "CIS-MGMT-001")
+	assert.Equal(t, []string{"SC-8", "AC-17(2)"}, finding.MappedReferences["nist-800-53"])
+	assert.Equal(t, []string{"FIREWALL-008"}, finding.MappedReferences["firewall"])
+	assert.Nil(t, report.Grouping, "results are only grouped on request")
+
+	output, err := report.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, output, `"mappedReferences"`)
+
+	markdown, err := report.ToMarkdown(nil)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "MAPPED REFERENCES")
+	assert.Contains(t, markdown, "nist-800-53: SC-8, AC-17(2); iso-27001: A.8.24; firewall: FIREWALL-008")
+}
+
+func TestReport_GroupByFramework(t *testing.T) {
+	report := generateMappedReport(t, "nist-800-53", nil)
+	require.NotNil(t, report.Grouping)
+	assert.Equal(t, "nist-800-53", report.Grouping.Framework)
+	assert.Equal(t, "NIST SP 800-53 Rev. 5", report.Grouping.Name)
+
+	groups := make(map[string]FrameworkGroup)
+	for _, group := range report.Grouping.Groups {
+		groups[group.Reference] = group
+	}
+
+	require.Contains(t, groups, "SC-8")
+	assert.Contains(t, groups["SC-8"].Controls, GroupedControl{
+		Plugin:  "cis",
+		Control: "CIS-MGMT-001",
+		Status:  plugin.StatusFail,
+	})
+	assert.Contains(t, groups["SC-8"].Findings, "HIGH: HTTPS Web Management")
+
+	require.Contains(t, groups, "AC-4")
+	assert.Contains(t, groups["AC-4"].Controls, GroupedControl{
+		Plugin:  "sans",
+		Control: "SANS-FW-001",
+		Status:  plugin.StatusFail,
+	})
+
+	output, err := report.ToJSON()
+	require.NoError(t, err)
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	require.NotNil(t, decoded.Grouping)
+	assert.Len(t, decoded.Grouping.Groups, len(report.Grouping.Groups))
+
+	markdown, err := report.ToMarkdown(nil)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "## Results by NIST SP 800-53 Rev. 5")
+	assert.Contains(t, markdown, "cis CIS-MGMT-001 (FAIL)")
+}
+
+func TestReport_GroupByPluginFramework(t *testing.T) {
+	report := generateMappedReport(t, "cis", nil)
+
+	groups := make(map[string]FrameworkGroup)
+	for _, group := range report.Grouping.Groups {
+		groups[group.Reference] = group
+	}
+
+	require.Contains(t, groups, "CIS-FW-005")
+	assert.True(t, slices.ContainsFunc(groups["CIS-FW-005"].Controls, func(control GroupedControl) bool {
+		return control.Plugin == "cis" && control.Control == "CIS-FW-005"
+	}), "controls of the target plugin are grouped under their own ID")
+	assert.Contains(t, groups["CIS-FW-005"].Controls, GroupedControl{
+		Plugin:  "sans",
+		Control: "SANS-FW-004",
+		Status:  plugin.StatusFail,
+	}, "controls of other plugins are grouped under their mapped IDs")
+}
+
+func TestReport_CustomMapping(t *testing.T) {
+	override, err := mapping.Parse(strings.NewReader(`
+frameworks:
+  - id: pci-dss
+    name: PCI DSS v4.0
+controls:
+  - control: CIS-MGMT-001
+    references:
+      pci-dss: [2.2.7]
+`))
+	require.NoError(t, err)
+
+	catalogue, err := mapping.Default().Merge(override)
+	require.NoError(t, err)
+
+	report := generateMappedReport(t, "pci-dss", catalogue)
+	assert.Equal(t, []string{"2.2.7"}, findingByControl(t, report, "CIS-MGMT-001").MappedReferences["pci-dss"])
+	require.Len(t, report.Grouping.Groups, 1)
+	assert.Equal(t, "2.2.7", report.Grouping.Groups[0].Reference)
+}
+
+func TestModeController_UnknownGroupingFramework(t *testing.T) {
+	controller := NewModeController(NewPluginRegistry(), log.New(io.Discard))
+
+	err := controller.ValidateModeConfig(&ModeConfig{Mode: ModeBlue, GroupByFramework: "pci-dss"})
+	require.ErrorIs(t, err, mapping.ErrUnknownFramework)
+	assert.Contains(t, err.Error(), "available: nist-800-53, iso-27001")
+
+	err = (&Report{}).ApplyMapping(nil, "pci-dss")
+	require.ErrorIs(t, err, mapping.ErrUnknownFramework)
+}
@@ -10,8 +10,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/EvilBit-Labs/opnDossier/internal/baseline"
+	"github.com/EvilBit-Labs/opnDossier/internal/mapping"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
 	"github.com/charmbracelet/log"
 )
 
@@ -57,6 +60,20 @@ type ModeConfig struct {
 	Comprehensive   bool
 	SelectedPlugins []string
 	TemplateDir     string
+
+	// ProcessorOptions are passed to the core processor whose findings are merged into the report.
+	ProcessorOptions []processor.Option
+
+	// Mapping relates controls across frameworks. The embedded mapping is used when nil.
+	Mapping *mapping.Catalogue
+	// GroupByFramework groups the results by the identifiers of a framework of the mapping.
+	GroupByFramework string
+
+	// Waivers suppress accepted findings. No findings are waived when nil.
+	Waivers *waiver.Set
+
+	// Baseline is a previous report the findings are compared with. Findings are not classified when nil.
+	Baseline *baseline.Baseline
 }
 
 // ValidateModeConfig validates the mode configuration.
@@ -82,6 +99,18 @@ func (mc *ModeController) ValidateModeConfig(config *ModeConfig) error {
 		}
 	}
 
+	if config.GroupByFramework != "" {
+		catalogue := config.Mapping
+		if catalogue == nil {
+			catalogue = mapping.Default()
+		}
+
+		if _, ok := catalogue.Framework(config.GroupByFramework); !ok {
+			return fmt.Errorf("%w: %s (available: %s)", mapping.ErrUnknownFramework, config.GroupByFramework,
+				strings.Join(catalogue.FrameworkIDs(), ", "))
+		}
+	}
+
 	return nil
 }
 
@@ -112,17 +141,48 @@ func (mc *ModeController) GenerateReport(
 		Metadata:      make(map[string]any),
 	}
 
+	// Merge the core processor findings and the selected plugin findings before the
+	// mode-specific analysis so that every mode works from the same set of findings.
+	if err := mc.addProcessorFindings(ctx, report, config); err != nil {
+		return nil, err
+	}
+
+	if len(config.SelectedPlugins) > 0 {
+		mc.runCompliancePlugins(ctx, report, config.SelectedPlugins)
+	}
+
 	// Generate mode-specific content
+	var err error
+
 	switch config.Mode {
 	case ModeStandard:
-		return mc.generateStandardReport(ctx, report)
+		report, err = mc.generateStandardReport(ctx, report)
 	case ModeBlue:
-		return mc.generateBlueReport(ctx, report, config)
+		report, err = mc.generateBlueReport(ctx, report)
 	case ModeRed:
-		return mc.generateRedReport(ctx, report, config)
+		report, err = mc.generateRedReport(ctx, report, config)
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMode, config.Mode)
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Waivers apply to the findings of every source, including the analysis of the mode, so the
+	// finding counts of blue team reports are refreshed afterwards.
+	report.ApplyWaivers(config.Waivers, time.Now())
+	report.CompareBaseline(config.Baseline)
+
+	if report.Mode == ModeBlue {
+		report.addSecurityFindings()
+	}
+
+	if err := report.ApplyMapping(config.Mapping, config.GroupByFramework); err != nil {
+		return nil, fmt.Errorf("failed to apply control mapping: %w", err)
+	}
+
+	return report, nil
 }
 
 // generateStandardReport generates a neutral, comprehensive documentation report.
@@ -148,30 +208,13 @@ func (mc *ModeController) generateStandardReport(_ context.Context, report *Repo
 }
 
 // generateBlueReport generates a defensive audit report with security findings and recommendations.
-func (mc *ModeController) generateBlueReport(_ context.Context, report *Report, config *ModeConfig) (*Report, error) {
+func (mc *ModeController) generateBlueReport(_ context.Context, report *Report) (*Report, error) {
 	mc.logger.Debug("Generating blue team report")
 
 	// Add blue team specific metadata
 	report.Metadata["report_type"] = "blue_team"
 	report.Metadata["generation_time"] = time.Now().Format(time.RFC3339)
 
-	// Run compliance checks if plugins are selected
-	if len(config.SelectedPlugins) > 0 {
-		complianceResult, err := mc.registry.RunComplianceChecks(report.Configuration, config.SelectedPlugins)
-		if err != nil {
-			mc.logger.Warn("Failed to run compliance checks", "error", err)
-			// Add metadata to report indicating compliance check failure
-			report.Metadata["compliance_check_status"] = "failed"
-			report.Metadata["compliance_check_error"] = err.Error()
-			report.Metadata["compliance_check_time"] = time.Now().Format(time.RFC3339)
-		} else {
-			report.Compliance["plugin_results"] = *complianceResult
-			// Add metadata to report indicating successful compliance checks
-			report.Metadata["compliance_check_status"] = "completed"
-			report.Metadata["compliance_check_time"] = time.Now().Format(time.RFC3339)
-		}
-	}
-
 	// Add blue team specific analysis
 	report.addSecurityFindings()
 	report.addComplianceAnalysis()
@@ -206,34 +249,54 @@ func (mc *ModeController) generateRedReport(_ context.Context, report *Report, c
 
 // Report represents a comprehensive audit report with findings and analysis.
 type Report struct {
-	Mode          ReportMode                  `json:"mode"`
-	BlackhatMode  bool                        `json:"blackhatMode"`
-	Comprehensive bool                        `json:"comprehensive"`
-	Configuration *model.OpnSenseDocument     `json:"configuration"`
-	Findings      []Finding                   `json:"findings"`
-	Compliance    map[string]ComplianceResult `json:"compliance"`
-	Metadata      map[string]any              `json:"metadata"`
+	Mode          ReportMode                  `json:"mode"          yaml:"mode"`
+	BlackhatMode  bool                        `json:"blackhatMode"  yaml:"blackhatMode"`
+	Comprehensive bool                        `json:"comprehensive" yaml:"comprehensive"`
+	Configuration *model.OpnSenseDocument     `json:"configuration" yaml:"configuration"`
+	Findings      []Finding                   `json:"findings"      yaml:"findings"`
+	Compliance    map[string]ComplianceResult `json:"compliance"    yaml:"compliance"`
+	Metadata      map[string]any              `json:"metadata"      yaml:"metadata"`
+	// Grouping holds the results grouped by a target framework when grouping was requested.
+	Grouping *FrameworkGrouping `json:"grouping,omitempty" yaml:"grouping,omitempty"`
+	// Waived holds the findings whose risk has been accepted by a waiver.
+	Waived []WaivedFinding `json:"waived,omitempty" yaml:"waived,omitempty"`
+	// Drift compares the findings with a baseline report when one was given.
+	Drift *Drift `json:"drift,omitempty" yaml:"drift,omitempty"`
+
+	// mapping is the control mapping applied to the findings.
+	mapping *mapping.Catalogue
 }
 
 // Finding represents a security finding or audit result.
 type Finding struct {
-	Title          string             `json:"title"`
-	Severity       processor.Severity `json:"severity"`
-	Description    string             `json:"description"`
-	Recommendation string             `json:"recommendation"`
-	Tags           []string           `json:"tags"`
-	AttackSurface  *AttackSurface     `json:"attackSurface,omitempty"`
-	ExploitNotes   string             `json:"exploitNotes,omitempty"`
-	Component      string             `json:"component"`
-	Control        string             `json:"control,omitempty"`
+	Title          string             `json:"title"                   yaml:"title"`
+	Severity       processor.Severity `json:"severity"                yaml:"severity"`
+	Description    string             `json:"description"             yaml:"description"`
+	Recommendation string             `json:"recommendation"          yaml:"recommendation"`
+	Tags           []string           `json:"tags"                    yaml:"tags,omitempty"`
+	AttackSurface  *AttackSurface     `json:"attackSurface,omitempty" yaml:"attackSurface,omitempty"`
+	ExploitNotes   string             `json:"exploitNotes,omitempty"  yaml:"exploitNotes,omitempty"`
+	Component      string             `json:"component"               yaml:"component"`
+	Control        string             `json:"control,omitempty"       yaml:"control,omitempty"`
+	// MappedReferences lists the identifiers of other frameworks that the control maps to, keyed by framework.
+	MappedReferences map[string][]string `json:"mappedReferences,omitempty" yaml:"mappedReferences,omitempty"`
+	// Source names where the finding came from: the core processor, a compliance plugin or the recon analysis.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Object identifies the configuration object the finding is about independently of its position,
+	// such as a rule UUID. It defaults to the component.
+	Object string `json:"object,omitempty" yaml:"object,omitempty"`
+	// Fingerprint identifies the finding across runs; waivers and baselines match it.
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+	// Drift classifies the finding relative to a baseline report: new or unchanged.
+	Drift string `json:"drift,omitempty" yaml:"drift,omitempty"`
 }
 
 // AttackSurface represents attack surface information for red team findings.
 type AttackSurface struct {
-	Type            string   `json:"type"`
-	Ports           []int    `json:"ports"`
-	Services        []string `json:"services"`
-	Vulnerabilities []string `json:"vulnerabilities"`
+	Type            string   `json:"type"            yaml:"type"`
+	Ports           []int    `json:"ports"           yaml:"ports,omitempty"`
+	Services        []string `json:"services"        yaml:"services,omitempty"`
+	Vulnerabilities []string `json:"vulnerabilities" yaml:"vulnerabilities,omitempty"`
 }
 
 // addSystemMetadata adds system metadata to the report.
@@ -341,72 +404,40 @@ func (r *Report) addHighAvailabilityAnalysis() {
 
 // addSecurityFindings adds security findings to the blue team report.
 func (r *Report) addSecurityFindings() {
-	// Add placeholder security analysis
 	r.Metadata["security_scan_completed"] = true
 	r.Metadata["security_findings_count"] = len(r.Findings)
+	r.Metadata["findings_by_severity"] = r.SeverityCounts()
 }
 
 // addComplianceAnalysis adds compliance analysis to the blue team report.
 func (r *Report) addComplianceAnalysis() {
-	// Add placeholder compliance analysis
+	frameworks := r.compliancePlugins()
+	if frameworks == nil {
+		frameworks = []string{}
+	}
+
 	r.Metadata["compliance_check_completed"] = true
-	r.Metadata["compliance_frameworks"] = []string{"CIS", "NIST"}
+	r.Metadata["compliance_frameworks"] = frameworks
 }
 
 // addRecommendations adds recommendations to the blue team report.
 func (r *Report) addRecommendations() {
-	// Add placeholder recommendations
+	recommendations := make(map[string]struct{})
+
+	for _, finding := range r.Findings {
+		if finding.Recommendation != "" {
+			recommendations[finding.Recommendation] = struct{}{}
+		}
+	}
+
 	r.Metadata["recommendations_generated"] = true
-	r.Metadata["recommendation_count"] = 0
+	r.Metadata["recommendation_count"] = len(recommendations)
 }
 
 // addStructuredConfigurationTables adds structured configuration tables to the blue team report.
 func (r *Report) addStructuredConfigurationTables() {
-	// Add placeholder structured tables
 	r.Metadata["structured_tables_generated"] = true
-	r.Metadata["table_count"] = 5
-}
-
-// addWANExposedServices adds WAN-exposed services analysis to the red team report.
-func (r *Report) addWANExposedServices() {
-	// Add placeholder WAN exposure analysis
-	r.Metadata["wan_exposure_scan_completed"] = true
-	r.Metadata["exposed_services_count"] = 0
-}
-
-// addWeakNATRules adds weak NAT rules analysis to the red team report.
-func (r *Report) addWeakNATRules() {
-	// Add placeholder weak NAT analysis
-	r.Metadata["weak_nat_scan_completed"] = true
-	r.Metadata["weak_nat_rules_count"] = 0
-}
-
-// addAdminPortals adds admin portals analysis to the red team report.
-func (r *Report) addAdminPortals() {
-	// Add placeholder admin portal analysis
-	r.Metadata["admin_portal_scan_completed"] = true
-	r.Metadata["admin_portals_found"] = 1
-}
-
-// addAttackSurfaces adds attack surfaces analysis to the red team report.
-func (r *Report) addAttackSurfaces() {
-	// Add placeholder attack surface analysis
-	r.Metadata["attack_surface_scan_completed"] = true
-	r.Metadata["attack_vectors_identified"] = 0
-}
-
-// addEnumerationData adds enumeration data to the red team report.
-func (r *Report) addEnumerationData() {
-	// Add placeholder enumeration data
-	r.Metadata["enumeration_completed"] = true
-	r.Metadata["enumeration_targets"] = []string{"services", "ports"}
-}
-
-// addSnarkyCommentary adds snarky commentary to the red team report when blackhat mode is enabled.
-func (r *Report) addSnarkyCommentary() {
-	// Add placeholder snarky commentary
-	r.Metadata["snarky_mode_enabled"] = true
-	r.Metadata["commentary_style"] = "blackhat"
+	r.Metadata["table_count"] = blueTableCount
 }
 
 // ParseReportMode parses a string into a ReportMode, returning an error if invalid.
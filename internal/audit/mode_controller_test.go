@@ -1196,7 +1196,7 @@ func TestPluginRegistry_RunComplianceChecks(t *testing.T) {
 	}
 
 	// Test running compliance checks with no plugins selected
-	results, err := registry.RunComplianceChecks(testConfig, nil)
+	results, err := registry.RunComplianceChecks(context.Background(), testConfig, nil)
 	if err != nil {
 		t.Errorf("RunComplianceChecks() error = %v", err)
 	}
@@ -1206,7 +1206,7 @@ func TestPluginRegistry_RunComplianceChecks(t *testing.T) {
 
 	// Test running compliance checks with specific plugins
 	selectedPlugins := []string{"stig"}
-	results, err = registry.RunComplianceChecks(testConfig, selectedPlugins)
+	results, err = registry.RunComplianceChecks(context.Background(), testConfig, selectedPlugins)
 	if err != nil {
 		t.Errorf("RunComplianceChecks() error = %v", err)
 	}
@@ -1216,7 +1216,7 @@ func TestPluginRegistry_RunComplianceChecks(t *testing.T) {
 
 	// Test running compliance checks with non-existent plugins
 	selectedPluginsNonexistent := []string{"nonexistent"}
-	_, err = registry.RunComplianceChecks(testConfig, selectedPluginsNonexistent)
+	_, err = registry.RunComplianceChecks(context.Background(), testConfig, selectedPluginsNonexistent)
 	if err == nil {
 		t.Error("RunComplianceChecks() should return error for non-existent plugins")
 	}
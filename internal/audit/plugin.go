@@ -4,20 +4,45 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	pluginlib "plugin"
 	"sync"
+	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin/external"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/rulepack"
+)
+
+// percentScale converts a ratio into a percentage.
+const percentScale = 100
+
+// DefaultPluginTimeout is the time a plugin run may take unless configured otherwise.
+const DefaultPluginTimeout = 30 * time.Second
+
+// Plugin run statuses.
+const (
+	// RunStatusCompleted means the plugin evaluated the configuration.
+	RunStatusCompleted = "completed"
+	// RunStatusTimeout means the plugin did not finish within the plugin timeout.
+	RunStatusTimeout = "timeout"
+	// RunStatusCanceled means the run was canceled before the plugin finished.
+	RunStatusCanceled = "canceled"
+	// RunStatusPanic means the plugin panicked.
+	RunStatusPanic = "panic"
 )
 
 // PluginRegistry manages the registration and retrieval of compliance plugins.
 type PluginRegistry struct {
 	plugins map[string]plugin.CompliancePlugin
+	timeout time.Duration
+	runs    map[string]PluginRun
 	mutex   sync.RWMutex
 }
 
@@ -25,6 +50,8 @@ type PluginRegistry struct {
 func NewPluginRegistry() *PluginRegistry {
 	return &PluginRegistry{
 		plugins: make(map[string]plugin.CompliancePlugin),
+		timeout: DefaultPluginTimeout,
+		runs:    make(map[string]PluginRun),
 	}
 }
 
@@ -127,27 +154,127 @@ func (pr *PluginRegistry) LoadDynamicPlugins(ctx context.Context, dir string, lo
 	return nil
 }
 
-// RunComplianceChecks runs compliance checks for specified plugins.
+// LoadExternalPlugins loads the external plugin executables in the specified directory and registers them.
+// Plugins that fail to load are logged and skipped.
+func (pr *PluginRegistry) LoadExternalPlugins(
+	ctx context.Context,
+	dir string,
+	opts external.Options,
+	logger *slog.Logger,
+) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || rulepack.IsRulePack(path) || !external.IsExecutable(path) {
+			continue
+		}
+
+		p, err := external.New(ctx, path, opts)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to load external plugin", "file", path, "error", err)
+			continue
+		}
+
+		if err := pr.RegisterPlugin(p); err != nil {
+			logger.ErrorContext(ctx, "Failed to register external plugin", "file", path, "error", err)
+			continue
+		}
+
+		logger.InfoContext(ctx, "Loaded external plugin", "file", path, "name", p.Name(), "version", p.Version())
+	}
+
+	return nil
+}
+
+// LoadRulePacks loads the YAML rule packs in the specified directory and registers them.
+// Rule packs that fail to parse or validate are logged and skipped.
+func (pr *PluginRegistry) LoadRulePacks(ctx context.Context, dir string, logger *slog.Logger) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !rulepack.IsRulePack(path) {
+			continue
+		}
+
+		pack, err := rulepack.Load(path)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to load rule pack", "file", path, "error", err)
+			continue
+		}
+
+		p := rulepack.NewPlugin(pack)
+		if err := pr.RegisterPlugin(p); err != nil {
+			logger.ErrorContext(ctx, "Failed to register rule pack", "file", path, "error", err)
+			continue
+		}
+
+		logger.InfoContext(ctx, "Loaded rule pack", "file", path, "name", p.Name(), "version", p.Version())
+	}
+
+	return nil
+}
+
+// RunComplianceChecks runs the specified plugins concurrently and merges their results in the
+// order of pluginNames. A plugin named more than once runs once. Every plugin run is bounded by the
+// plugin timeout and isolated from the others: a plugin that panics or does not finish in time has
+// all of its controls reported with the status error, and the other plugins are unaffected.
 func (pr *PluginRegistry) RunComplianceChecks(
+	ctx context.Context,
 	config *model.OpnSenseDocument,
 	pluginNames []string,
 ) (*ComplianceResult, error) {
 	result := &ComplianceResult{
 		Findings:   []plugin.Finding{},
 		Compliance: make(map[string]map[string]bool),
+		Results:    make(map[string][]plugin.ControlResult),
 		Summary:    &ComplianceSummary{},
 		PluginInfo: make(map[string]PluginInfo),
+		Runs:       make(map[string]PluginRun),
 	}
 
+	pluginNames = uniqueNames(pluginNames)
+	plugins := make([]plugin.CompliancePlugin, 0, len(pluginNames))
+
 	for _, pluginName := range pluginNames {
 		p, err := pr.GetPlugin(pluginName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get plugin '%s': %w", pluginName, err)
 		}
 
-		// Run checks for this plugin
-		findings := p.RunChecks(config)
-		result.Findings = append(result.Findings, findings...)
+		plugins = append(plugins, p)
+	}
+
+	outcomes := make([]pluginOutcome, len(plugins))
+
+	var wg sync.WaitGroup
+
+	for i, p := range plugins {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			outcomes[i] = pr.runPlugin(ctx, p, config)
+		}()
+	}
+
+	wg.Wait()
+
+	for i, p := range plugins {
+		pluginName := pluginNames[i]
+		outcome := outcomes[i]
+
+		result.Findings = append(result.Findings, outcome.findings...)
+		result.Results[pluginName] = outcome.results
+		result.Runs[pluginName] = outcome.run
 
 		// Track plugin information
 		result.PluginInfo[pluginName] = PluginInfo{
@@ -157,28 +284,141 @@ func (pr *PluginRegistry) RunComplianceChecks(
 			Controls:    p.GetControls(),
 		}
 
-		// Initialize compliance tracking for this plugin
-		result.Compliance[pluginName] = make(map[string]bool)
-		for _, control := range p.GetControls() {
-			result.Compliance[pluginName][control.ID] = true // Default to compliant
-		}
-
-		// Update compliance status based on findings
-		for _, finding := range findings {
-			for _, ref := range finding.References {
-				if result.Compliance[pluginName] != nil {
-					result.Compliance[pluginName][ref] = false // Non-compliant
-				}
-			}
+		// Passed and not applicable controls are compliant
+		result.Compliance[pluginName] = make(map[string]bool, len(outcome.results))
+		for _, controlResult := range outcome.results {
+			result.Compliance[pluginName][controlResult.ControlID] = controlResult.Status == plugin.StatusPass ||
+				controlResult.Status == plugin.StatusNotApplicable
 		}
 	}
 
+	pr.recordRuns(result.Runs)
+
 	// Calculate summary
 	result.Summary = pr.calculateSummary(result)
 
 	return result, nil
 }
 
+// SetPluginTimeout sets the time each plugin run may take. Non-positive values restore the default.
+func (pr *PluginRegistry) SetPluginTimeout(timeout time.Duration) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+
+	pr.timeout = timeout
+}
+
+// PluginRuns returns the most recent run of every plugin that has been run.
+func (pr *PluginRegistry) PluginRuns() map[string]PluginRun {
+	pr.mutex.RLock()
+	defer pr.mutex.RUnlock()
+
+	return maps.Clone(pr.runs)
+}
+
+// uniqueNames returns names without repetitions, keeping the first occurrence of each name.
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
+
+// pluginOutcome is the result of running a single plugin.
+type pluginOutcome struct {
+	findings []plugin.Finding
+	results  []plugin.ControlResult
+	run      PluginRun
+}
+
+// runPlugin evaluates a plugin with a deadline and recovers from panics. Plugins cannot be
+// interrupted, so a plugin that misses its deadline keeps running in the background and its
+// late results are discarded.
+func (pr *PluginRegistry) runPlugin(
+	ctx context.Context,
+	p plugin.CompliancePlugin,
+	config *model.OpnSenseDocument,
+) pluginOutcome {
+	pr.mutex.RLock()
+	timeout := pr.timeout
+	pr.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan pluginOutcome, 1)
+
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- failedOutcome(p, RunStatusPanic, fmt.Sprintf("plugin panicked: %v", recovered))
+			}
+		}()
+
+		findings, results := plugin.Evaluate(p, config)
+		done <- pluginOutcome{findings: findings, results: results, run: PluginRun{Status: RunStatusCompleted}}
+	}()
+
+	var outcome pluginOutcome
+
+	select {
+	case outcome = <-done:
+	case <-ctx.Done():
+		status := RunStatusTimeout
+		message := fmt.Sprintf("plugin did not finish within %s", timeout)
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			status = RunStatusCanceled
+			message = "plugin run was canceled"
+		}
+
+		outcome = failedOutcome(p, status, message)
+	}
+
+	outcome.run.Duration = time.Since(start)
+
+	return outcome
+}
+
+// failedOutcome reports every control of a plugin that could not be evaluated as an error.
+func failedOutcome(p plugin.CompliancePlugin, status, message string) pluginOutcome {
+	controls := p.GetControls()
+	results := make([]plugin.ControlResult, 0, len(controls))
+
+	for _, control := range controls {
+		results = append(results, plugin.ControlResult{
+			ControlID: control.ID,
+			Title:     control.Title,
+			Category:  control.Category,
+			Severity:  control.Severity,
+			Status:    plugin.StatusError,
+			Message:   message,
+		})
+	}
+
+	return pluginOutcome{results: results, run: PluginRun{Status: status, Error: message}}
+}
+
+// recordRuns keeps the runs for the plugin statistics.
+func (pr *PluginRegistry) recordRuns(runs map[string]PluginRun) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	maps.Copy(pr.runs, runs)
+}
+
 // calculateSummary calculates compliance summary statistics.
 func (pr *PluginRegistry) calculateSummary(result *ComplianceResult) *ComplianceSummary {
 	summary := &ComplianceSummary{
@@ -202,34 +442,56 @@ func (pr *PluginRegistry) calculateSummary(result *ComplianceResult) *Compliance
 	}
 
 	// Calculate compliance per plugin
-	for pluginName, compliance := range result.Compliance {
-		compliant := 0
-		nonCompliant := 0
-
-		for _, isCompliant := range compliance {
-			if isCompliant {
-				compliant++
-			} else {
-				nonCompliant++
-			}
-		}
+	for pluginName, results := range result.Results {
+		summary.Compliance[pluginName] = summarizeResults(results)
+	}
+
+	return summary
+}
 
-		summary.Compliance[pluginName] = PluginCompliance{
-			Compliant:    compliant,
-			NonCompliant: nonCompliant,
-			Total:        compliant + nonCompliant,
+// summarizeResults counts the control results per status. The compliance percentage is the share of
+// passed controls among the applicable ones.
+func summarizeResults(results []plugin.ControlResult) PluginCompliance {
+	stats := PluginCompliance{Total: len(results)}
+
+	for _, controlResult := range results {
+		switch controlResult.Status {
+		case plugin.StatusPass:
+			stats.Compliant++
+		case plugin.StatusFail:
+			stats.NonCompliant++
+		case plugin.StatusNotApplicable:
+			stats.NotApplicable++
+		case plugin.StatusManualReview:
+			stats.ManualReview++
+		case plugin.StatusError:
+			stats.Errors++
 		}
 	}
 
-	return summary
+	if applicable := stats.Total - stats.NotApplicable; applicable > 0 {
+		stats.Percentage = float64(stats.Compliant) * percentScale / float64(applicable)
+	}
+
+	return stats
 }
 
 // ComplianceResult represents the complete result of compliance checks.
 type ComplianceResult struct {
-	Findings   []plugin.Finding           `json:"findings"`
-	Compliance map[string]map[string]bool `json:"compliance"`
-	Summary    *ComplianceSummary         `json:"summary"`
-	PluginInfo map[string]PluginInfo      `json:"pluginInfo"`
+	Findings   []plugin.Finding                  `json:"findings"`
+	Compliance map[string]map[string]bool        `json:"compliance"`
+	Results    map[string][]plugin.ControlResult `json:"results"`
+	Summary    *ComplianceSummary                `json:"summary"`
+	PluginInfo map[string]PluginInfo             `json:"pluginInfo"`
+	// Runs records how the run of each plugin went.
+	Runs map[string]PluginRun `json:"runs,omitempty"`
+}
+
+// PluginRun describes a single plugin run.
+type PluginRun struct {
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"durationNs"`
+	Error    string        `json:"error,omitempty"`
 }
 
 // ComplianceSummary provides summary statistics.
@@ -245,9 +507,13 @@ type ComplianceSummary struct {
 
 // PluginCompliance represents compliance statistics for a single plugin.
 type PluginCompliance struct {
-	Compliant    int `json:"compliant"`
-	NonCompliant int `json:"nonCompliant"`
-	Total        int `json:"total"`
+	Compliant     int     `json:"compliant"`
+	NonCompliant  int     `json:"nonCompliant"`
+	NotApplicable int     `json:"notApplicable"`
+	ManualReview  int     `json:"manualReview"`
+	Errors        int     `json:"errors"`
+	Total         int     `json:"total"`
+	Percentage    float64 `json:"percentage"`
 }
 
 // PluginInfo contains metadata about a plugin.
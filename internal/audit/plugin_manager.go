@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin/external"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/cis"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugins/firewall"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugins/sans"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugins/stig"
@@ -61,11 +66,41 @@ func (pm *PluginManager) InitializePlugins(ctx context.Context) error {
 		firewallPlugin.Version(),
 	)
 
+	// Register CIS plugin
+	cisPlugin := cis.NewPlugin()
+	if err := pm.registry.RegisterPlugin(cisPlugin); err != nil {
+		return fmt.Errorf("failed to register CIS plugin: %w", err)
+	}
+
+	pm.logger.InfoContext(ctx, "Registered CIS plugin", "name", cisPlugin.Name(), "version", cisPlugin.Version())
+
 	pm.logger.InfoContext(ctx, "Plugin initialization completed", "total_plugins", len(pm.registry.ListPlugins()))
 
 	return nil
 }
 
+// LoadExternalPlugins registers the external plugin executables found in dir.
+func (pm *PluginManager) LoadExternalPlugins(ctx context.Context, dir string, opts external.Options) error {
+	if opts.Logger == nil {
+		opts.Logger = pm.logger
+	}
+
+	if err := pm.registry.LoadExternalPlugins(ctx, dir, opts, pm.logger); err != nil {
+		return fmt.Errorf("failed to load external plugins: %w", err)
+	}
+
+	return nil
+}
+
+// LoadRulePacks registers the YAML rule packs found in dir.
+func (pm *PluginManager) LoadRulePacks(ctx context.Context, dir string) error {
+	if err := pm.registry.LoadRulePacks(ctx, dir, pm.logger); err != nil {
+		return fmt.Errorf("failed to load rule packs: %w", err)
+	}
+
+	return nil
+}
+
 // GetRegistry returns the plugin registry.
 func (pm *PluginManager) GetRegistry() *PluginRegistry {
 	return pm.registry
@@ -102,7 +137,7 @@ func (pm *PluginManager) RunComplianceAudit(
 ) (*ComplianceResult, error) {
 	pm.logger.InfoContext(ctx, "Starting compliance audit", "plugins", pluginNames)
 
-	result, err := pm.registry.RunComplianceChecks(config, pluginNames)
+	result, err := pm.registry.RunComplianceChecks(ctx, config, pluginNames)
 	if err != nil {
 		return nil, fmt.Errorf("compliance audit failed: %w", err)
 	}
@@ -139,6 +174,42 @@ func (pm *PluginManager) ValidatePluginConfiguration(pluginName string) error {
 	return p.ValidateConfiguration()
 }
 
+// SetPluginTimeout sets the time each plugin run may take.
+func (pm *PluginManager) SetPluginTimeout(timeout time.Duration) {
+	pm.registry.SetPluginTimeout(timeout)
+}
+
+// ConfigurePlugins passes each plugin its settings block from the configuration file and
+// validates the result. Settings for plugins that are not registered or do not accept settings
+// are rejected.
+func (pm *PluginManager) ConfigurePlugins(ctx context.Context, settings map[string]map[string]any) error {
+	names := slices.Sorted(maps.Keys(settings))
+
+	for _, name := range names {
+		p, err := pm.registry.GetPlugin(name)
+		if err != nil {
+			return fmt.Errorf("settings for plugin '%s': %w", name, err)
+		}
+
+		configurable, ok := p.(plugin.Configurable)
+		if !ok {
+			return fmt.Errorf("%w: plugin '%s' does not accept settings", plugin.ErrPluginValidation, name)
+		}
+
+		if err := configurable.Configure(settings[name]); err != nil {
+			return fmt.Errorf("invalid settings for plugin '%s': %w", name, err)
+		}
+
+		if err := p.ValidateConfiguration(); err != nil {
+			return fmt.Errorf("invalid settings for plugin '%s': %w", name, err)
+		}
+
+		pm.logger.DebugContext(ctx, "Configured plugin", "plugin", name)
+	}
+
+	return nil
+}
+
 // GetPluginStatistics returns statistics about plugin usage and compliance.
 func (pm *PluginManager) GetPluginStatistics() map[string]any {
 	stats := make(map[string]any)
@@ -161,5 +232,17 @@ func (pm *PluginManager) GetPluginStatistics() map[string]any {
 
 	stats["control_counts"] = controlCounts
 
+	// Durations and statuses of the most recent run of each plugin
+	durations := make(map[string]time.Duration)
+	statuses := make(map[string]string)
+
+	for pluginName, run := range pm.registry.PluginRuns() {
+		durations[pluginName] = run.Duration
+		statuses[pluginName] = run.Status
+	}
+
+	stats["run_durations"] = durations
+	stats["run_statuses"] = statuses
+
 	return stats
 }
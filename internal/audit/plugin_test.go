@@ -0,0 +1,325 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin/external"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/cis"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/sans"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginRegistry_ControlResults(t *testing.T) {
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(sans.NewPlugin()))
+	require.NoError(t, registry.RegisterPlugin(cis.NewPlugin()))
+
+	result, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{}, []string{"sans", "cis"})
+	require.NoError(t, err)
+
+	t.Run("adapted plugin", func(t *testing.T) {
+		results := result.Results["sans"]
+		require.Len(t, results, len(sans.NewPlugin().GetControls()))
+
+		stats := result.Summary.Compliance["sans"]
+		assert.Equal(t, len(results), stats.Total)
+		assert.Equal(t, stats.Total, stats.Compliant+stats.NonCompliant)
+		assert.Zero(t, stats.NotApplicable)
+		assert.InDelta(t, float64(stats.Compliant)*100/float64(stats.Total), stats.Percentage, 0.001)
+
+		for _, controlResult := range results {
+			assert.Equal(t, controlResult.Status == plugin.StatusPass,
+				result.Compliance["sans"][controlResult.ControlID], controlResult.ControlID)
+		}
+	})
+
+	t.Run("native plugin", func(t *testing.T) {
+		stats := result.Summary.Compliance["cis"]
+		assert.Positive(t, stats.NotApplicable, "VPN, SNMP and SSH controls do not apply to an empty configuration")
+		assert.Positive(t, stats.NonCompliant)
+		assert.Equal(t, stats.Total, stats.Compliant+stats.NonCompliant+stats.NotApplicable)
+
+		applicable := stats.Total - stats.NotApplicable
+		assert.InDelta(t, float64(stats.Compliant)*100/float64(applicable), stats.Percentage, 0.001)
+		assert.True(t, result.Compliance["cis"]["CIS-VPN-001"], "not applicable controls are compliant")
+	})
+}
+
+func TestSummarizeResults(t *testing.T) {
+	stats := summarizeResults([]plugin.ControlResult{
+		{Status: plugin.StatusPass},
+		{Status: plugin.StatusPass},
+		{Status: plugin.StatusFail},
+		{Status: plugin.StatusNotApplicable},
+		{Status: plugin.StatusManualReview},
+		{Status: plugin.StatusError},
+	})
+
+	assert.Equal(t, PluginCompliance{
+		Compliant:     2,
+		NonCompliant:  1,
+		NotApplicable: 1,
+		ManualReview:  1,
+		Errors:        1,
+		Total:         6,
+		Percentage:    40,
+	}, stats)
+
+	assert.Equal(t, PluginCompliance{}, summarizeResults(nil))
+}
+
+// scriptPlugin is an external plugin answering the plugin protocol from a shell script.
+const scriptPlugin = `#!/bin/sh
+request=$(cat)
+case "$request" in
+*'"describe"'*) echo '{"protocol": 1, "name": "script", "version": "1.0.0", "description": "shell plugin"}' ;;
+*'"controls"'*) echo '{"protocol": 1, "controls": [{"id": "SH-001", "title": "Hostname", "severity": "low"}]}' ;;
+*'"hostname":""'*) echo '{"protocol": 1, "findings": [{"title": "Hostname", "reference": "SH-001"}]}' ;;
+*) echo '{"protocol": 1}' ;;
+esac
+`
+
+func TestPluginRegistry_LoadExternalPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string, mode os.FileMode) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), mode)) //nolint:gosec // plugins are executable
+	}
+
+	writeFile("script", scriptPlugin, 0o700)
+	writeFile("broken", "#!/bin/sh\nexit 1\n", 0o700)
+	writeFile("README.md", "not a plugin", 0o600)
+
+	ctx := context.Background()
+	logger := slog.New(slog.DiscardHandler)
+
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.LoadExternalPlugins(ctx, dir, external.Options{}, logger))
+	assert.Equal(t, []string{"script"}, registry.ListPlugins(), "broken plugins and other files are skipped")
+
+	result, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{}, []string{"script"})
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, plugin.StatusFail, result.Results["script"][0].Status)
+
+	configured := &model.OpnSenseDocument{System: model.System{Hostname: "fw"}}
+	result, err = registry.RunComplianceChecks(context.Background(), configured, []string{"script"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+	assert.Equal(t, plugin.StatusPass, result.Results["script"][0].Status)
+
+	require.Error(t, registry.LoadExternalPlugins(ctx, filepath.Join(dir, "missing"), external.Options{}, logger))
+}
+
+func TestPluginRegistry_LoadRulePacks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	writeFile("baseline.yaml", `name: baseline
+version: 1.0.0
+controls:
+  - id: BASE-001
+    title: Hostname
+    severity: low
+    check: {path: system.hostname, empty: false}
+`)
+	writeFile("invalid.yml", "name: invalid\ncontrols: [{id: X, title: X, severity: low, check: {path: nope, empty: true}}]\n")
+	writeFile("broken.yaml", "controls: [")
+	writeFile("README.md", "not a rule pack")
+
+	ctx := context.Background()
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.LoadRulePacks(ctx, dir, slog.New(slog.DiscardHandler)))
+	assert.Equal(t, []string{"baseline"}, registry.ListPlugins(), "invalid rule packs and other files are skipped")
+
+	result, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{}, []string{"baseline"})
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, plugin.StatusFail, result.Results["baseline"][0].Status)
+
+	require.Error(t, registry.LoadRulePacks(ctx, filepath.Join(dir, "missing"), slog.New(slog.DiscardHandler)))
+}
+
+// misbehavingPlugin is a compliance plugin whose checks call a test function.
+type misbehavingPlugin struct {
+	mockCompliancePlugin
+
+	run func()
+}
+
+func (m *misbehavingPlugin) RunChecks(_ *model.OpnSenseDocument) []plugin.Finding {
+	m.run()
+	return nil
+}
+
+func (m *misbehavingPlugin) GetControls() []plugin.Control {
+	return []plugin.Control{{ID: "MIS-001", Title: "Misbehaving control", Severity: "high"}}
+}
+
+func TestPluginRegistry_RunIsolation(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	registry := NewPluginRegistry()
+	registry.SetPluginTimeout(50 * time.Millisecond)
+	require.NoError(t, registry.RegisterPlugin(sans.NewPlugin()))
+	require.NoError(t, registry.RegisterPlugin(&misbehavingPlugin{
+		mockCompliancePlugin: mockCompliancePlugin{name: "panics"},
+		run:                  func() { panic("boom") },
+	}))
+	require.NoError(t, registry.RegisterPlugin(&misbehavingPlugin{
+		mockCompliancePlugin: mockCompliancePlugin{name: "hangs"},
+		run:                  func() { <-release },
+	}))
+
+	result, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{},
+		[]string{"panics", "sans", "hangs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, RunStatusCompleted, result.Runs["sans"].Status)
+	assert.Len(t, result.Results["sans"], len(sans.NewPlugin().GetControls()))
+
+	panicked := result.Runs["panics"]
+	assert.Equal(t, RunStatusPanic, panicked.Status)
+	assert.Contains(t, panicked.Error, "boom")
+
+	hung := result.Runs["hangs"]
+	assert.Equal(t, RunStatusTimeout, hung.Status)
+	assert.GreaterOrEqual(t, hung.Duration, 50*time.Millisecond)
+
+	for _, name := range []string{"panics", "hangs"} {
+		require.Len(t, result.Results[name], 1)
+		assert.Equal(t, plugin.StatusError, result.Results[name][0].Status)
+		assert.Equal(t, "Misbehaving control", result.Results[name][0].Title)
+		assert.False(t, result.Compliance[name]["MIS-001"])
+		assert.Equal(t, 1, result.Summary.Compliance[name].Errors)
+	}
+
+	assert.Equal(t, result.Runs, registry.PluginRuns())
+}
+
+func TestPluginRegistry_DuplicatePluginNames(t *testing.T) {
+	var runs atomic.Int32
+
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(sans.NewPlugin()))
+	require.NoError(t, registry.RegisterPlugin(&misbehavingPlugin{
+		mockCompliancePlugin: mockCompliancePlugin{name: "counted"},
+		run:                  func() { runs.Add(1) },
+	}))
+
+	single, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{}, []string{"sans"})
+	require.NoError(t, err)
+
+	result, err := registry.RunComplianceChecks(context.Background(), &model.OpnSenseDocument{},
+		[]string{"counted", "sans", "counted", "sans"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), runs.Load(), "a repeated plugin runs once")
+	assert.Len(t, result.Runs, 2)
+	assert.Len(t, result.Findings, len(single.Findings), "findings are not duplicated")
+	assert.Equal(t, single.Results["sans"], result.Results["sans"])
+}
+
+func TestPluginRegistry_RunCanceled(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(&misbehavingPlugin{
+		mockCompliancePlugin: mockCompliancePlugin{name: "hangs"},
+		run:                  func() { <-release },
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := registry.RunComplianceChecks(ctx, &model.OpnSenseDocument{}, []string{"hangs"})
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusCanceled, result.Runs["hangs"].Status)
+}
+
+func TestPluginManager_ConfigurePlugins(t *testing.T) {
+	ctx := context.Background()
+	manager := NewPluginManager(slog.New(slog.DiscardHandler))
+	require.NoError(t, manager.InitializePlugins(ctx))
+	require.NoError(t, manager.GetRegistry().RegisterPlugin(&misbehavingPlugin{
+		mockCompliancePlugin: mockCompliancePlugin{name: "fixed"},
+	}))
+
+	require.NoError(t, manager.ConfigurePlugins(ctx, nil))
+	require.NoError(t, manager.ConfigurePlugins(ctx, map[string]map[string]any{
+		"stig": {"max_dhcp_interfaces": 4},
+		"sans": {"untrusted_zones": []any{"wan", "guest"}, "require_rule_logging": false},
+	}))
+
+	tests := []struct {
+		name     string
+		settings map[string]map[string]any
+		errMsg   string
+	}{
+		{
+			name:     "unknown plugin",
+			settings: map[string]map[string]any{"nist": {"level": 2}},
+			errMsg:   "settings for plugin 'nist'",
+		},
+		{
+			name:     "plugin without settings",
+			settings: map[string]map[string]any{"fixed": {"level": 2}},
+			errMsg:   "plugin 'fixed' does not accept settings",
+		},
+		{
+			name:     "unknown setting",
+			settings: map[string]map[string]any{"stig": {"max_dhcp": 4}},
+			errMsg:   "field max_dhcp not found",
+		},
+		{
+			name:     "invalid value",
+			settings: map[string]map[string]any{"stig": {"max_dhcp_interfaces": -1}},
+			errMsg:   "max_dhcp_interfaces must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.ConfigurePlugins(ctx, tt.settings)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestPluginManager_GetPluginStatistics(t *testing.T) {
+	ctx := context.Background()
+	manager := NewPluginManager(slog.New(slog.DiscardHandler))
+	require.NoError(t, manager.InitializePlugins(ctx))
+
+	_, err := manager.RunComplianceAudit(ctx, &model.OpnSenseDocument{}, []string{"stig", "sans"})
+	require.NoError(t, err)
+
+	stats := manager.GetPluginStatistics()
+
+	durations, ok := stats["run_durations"].(map[string]time.Duration)
+	require.True(t, ok)
+	assert.Len(t, durations, 2)
+	assert.Contains(t, durations, "stig")
+
+	statuses, ok := stats["run_statuses"].(map[string]string)
+	require.True(t, ok)
+	assert.Equal(t, RunStatusCompleted, statuses["sans"])
+}
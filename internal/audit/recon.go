@@ -0,0 +1,284 @@
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+)
+
+// Attack surface types reported by the red team analysis.
+const (
+	SurfacePortForward  = "port-forward"
+	SurfaceFirewallRule = "firewall-rule"
+	SurfaceAdminPortal  = "admin-portal"
+)
+
+// wanInterface is the logical name of the WAN interface.
+const wanInterface = "wan"
+
+// parsePorts extracts the numeric ports of a single port or a port range ("8000-8010" or "8000:8010").
+// Aliases and other non-numeric values yield no ports.
+func parsePorts(value string) []int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '-' || r == ':' })
+
+	ports := make([]int, 0, len(parts))
+	for _, part := range parts {
+		port, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// orDefault returns value, or fallback when value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// addWANExposedServices reports the port forwards and pass rules that expose services on the WAN interface.
+func (r *Report) addWANExposedServices() {
+	exposed := 0
+
+	if r.Configuration != nil {
+		for i, rule := range r.Configuration.Nat.Inbound {
+			if rule.Disabled != "" || !rule.Interface.Contains(wanInterface) {
+				continue
+			}
+
+			exposed++
+
+			target := rule.InternalIP
+			if rule.InternalPort != "" {
+				target += ":" + rule.InternalPort
+			}
+
+			finding := Finding{
+				Title:    "WAN Port Forward",
+				Severity: processor.SeverityMedium,
+				Description: fmt.Sprintf("Port %s (%s) on the WAN interface is forwarded to %s.",
+					orDefault(rule.ExternalPort, "any"), orDefault(rule.Protocol, "any"), orDefault(target, "an internal host")),
+				Recommendation: "Restrict the source addresses of the port forward or publish the service through a VPN.",
+				Tags:           []string{"wan", "nat"},
+				AttackSurface: &AttackSurface{
+					Type:     SurfacePortForward,
+					Ports:    parsePorts(rule.ExternalPort),
+					Services: []string{orDefault(target, "unknown")},
+				},
+				Component: fmt.Sprintf("nat.inbound[%d]", i),
+				Object:    rule.ObjectID(),
+				Source:    SourceRecon,
+			}
+
+			if rule.Source.IsAny() {
+				finding.Severity = processor.SeverityHigh
+				finding.AttackSurface.Vulnerabilities = append(finding.AttackSurface.Vulnerabilities,
+					"reachable from any source address")
+			}
+
+			r.Findings = append(r.Findings, finding)
+		}
+
+		for i, rule := range r.Configuration.Filter.Rule {
+			if rule.Disabled != "" || rule.Type != model.RuleTypePass || !rule.Interface.Contains(wanInterface) ||
+				!rule.Source.IsAny() {
+				continue
+			}
+
+			exposed++
+
+			destination := rule.Destination.String()
+			finding := Finding{
+				Title:    "WAN Pass Rule From Any Source",
+				Severity: processor.SeverityMedium,
+				Description: fmt.Sprintf("Rule %q allows %s traffic from any source on the WAN interface to %s port %s.",
+					orDefault(rule.Descr, fmt.Sprintf("#%d", i+1)), orDefault(rule.Protocol, "any"), destination,
+					orDefault(rule.Destination.Port, "any")),
+				Recommendation: "Limit the rule to the source addresses and ports that actually need access.",
+				Tags:           []string{"wan", "firewall"},
+				AttackSurface: &AttackSurface{
+					Type:     SurfaceFirewallRule,
+					Ports:    parsePorts(rule.Destination.Port),
+					Services: []string{destination},
+				},
+				Component: fmt.Sprintf("filter.rule[%d]", i),
+				Object:    rule.ObjectID(),
+				Source:    SourceRecon,
+			}
+
+			if rule.Destination.IsAny() {
+				finding.Severity = processor.SeverityHigh
+				finding.AttackSurface.Vulnerabilities = append(finding.AttackSurface.Vulnerabilities,
+					"any destination reachable from the internet")
+			}
+
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+
+	r.Metadata["wan_exposure_scan_completed"] = true
+	r.Metadata["exposed_services_count"] = exposed
+}
+
+// addWeakNATRules records the enabled port forwards that accept any source or enable NAT reflection.
+func (r *Report) addWeakNATRules() {
+	weak := make([]string, 0)
+
+	if r.Configuration != nil {
+		for i, rule := range r.Configuration.Nat.Inbound {
+			if rule.Disabled != "" {
+				continue
+			}
+
+			var reasons []string
+			if rule.Source.IsAny() {
+				reasons = append(reasons, "any source")
+			}
+
+			if rule.Reflection == "enable" {
+				reasons = append(reasons, "NAT reflection")
+			}
+
+			if len(reasons) > 0 {
+				weak = append(weak, fmt.Sprintf("nat.inbound[%d] %s: %s",
+					i, orDefault(rule.Descr, rule.ExternalPort), strings.Join(reasons, ", ")))
+			}
+		}
+	}
+
+	r.Metadata["weak_nat_scan_completed"] = true
+	r.Metadata["weak_nat_rules_count"] = len(weak)
+	r.Metadata["weak_nat_rules"] = weak
+}
+
+// addAdminPortals records the management interfaces of the firewall and flags the web GUI
+// when it is served over plain HTTP.
+func (r *Report) addAdminPortals() {
+	portals := make([]string, 0)
+
+	if r.Configuration != nil {
+		system := r.Configuration.System
+
+		protocol := orDefault(system.WebGUI.Protocol, "https")
+		portals = append(portals, fmt.Sprintf("webgui (%s)", protocol))
+
+		if protocol == "http" {
+			r.Findings = append(r.Findings, Finding{
+				Title:          "Web GUI Served Over Plain HTTP",
+				Severity:       processor.SeverityHigh,
+				Description:    "The web administration interface is served over unencrypted HTTP.",
+				Recommendation: "Serve the web GUI over HTTPS and restrict it to a management network.",
+				Tags:           []string{"admin"},
+				AttackSurface: &AttackSurface{
+					Type:            SurfaceAdminPortal,
+					Ports:           []int{80},
+					Services:        []string{"webgui"},
+					Vulnerabilities: []string{"cleartext credentials"},
+				},
+				Component: "system.webgui.protocol",
+				Source:    SourceRecon,
+			})
+		}
+
+		if system.SSH.Group != "" {
+			portals = append(portals, fmt.Sprintf("ssh (group %s)", system.SSH.Group))
+		}
+	}
+
+	r.Metadata["admin_portal_scan_completed"] = true
+	r.Metadata["admin_portals_found"] = len(portals)
+	r.Metadata["admin_portals"] = portals
+}
+
+// addAttackSurfaces summarises the attack surfaces identified by the other red team checks.
+func (r *Report) addAttackSurfaces() {
+	vectors := 0
+	types := make([]string, 0)
+
+	for _, finding := range r.Findings {
+		if finding.AttackSurface == nil {
+			continue
+		}
+
+		vectors++
+
+		if !slices.Contains(types, finding.AttackSurface.Type) {
+			types = append(types, finding.AttackSurface.Type)
+		}
+	}
+
+	slices.Sort(types)
+
+	r.Metadata["attack_surface_scan_completed"] = true
+	r.Metadata["attack_vectors_identified"] = vectors
+	r.Metadata["attack_surface_types"] = types
+}
+
+// addEnumerationData lists the addresses of the enabled interfaces as enumeration targets.
+func (r *Report) addEnumerationData() {
+	targets := make([]string, 0)
+
+	if r.Configuration != nil {
+		names := r.Configuration.Interfaces.Names()
+		slices.Sort(names)
+
+		for _, name := range names {
+			iface := r.Configuration.Interfaces.Items[name]
+			if iface.Enable == "" || iface.IPAddr == "" {
+				continue
+			}
+
+			address := iface.IPAddr
+			if iface.Subnet != "" {
+				address += "/" + iface.Subnet
+			}
+
+			targets = append(targets, fmt.Sprintf("%s: %s", name, address))
+		}
+	}
+
+	r.Metadata["enumeration_completed"] = true
+	r.Metadata["enumeration_targets"] = targets
+}
+
+// addSnarkyCommentary adds exploitation notes to every attack surface finding when blackhat mode is enabled.
+func (r *Report) addSnarkyCommentary() {
+	for i := range r.Findings {
+		finding := &r.Findings[i]
+		if finding.AttackSurface == nil || finding.ExploitNotes != "" {
+			continue
+		}
+
+		switch finding.AttackSurface.Type {
+		case SurfacePortForward:
+			finding.ExploitNotes = "Straight through the front door. Fingerprint the service and check what the " +
+				"internal host is running."
+		case SurfaceFirewallRule:
+			finding.ExploitNotes = "An allow-any rule on WAN: the firewall is mostly decorative here. Scan everything " +
+				"behind it."
+		case SurfaceAdminPortal:
+			finding.ExploitNotes = "Admin credentials cross the wire in cleartext. Bring a sniffer."
+		default:
+			finding.ExploitNotes = "Worth a closer look."
+		}
+	}
+
+	r.Metadata["snarky_mode_enabled"] = true
+	r.Metadata["commentary_style"] = "blackhat"
+}
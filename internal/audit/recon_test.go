@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/firewall"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/stig"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exposedConfig returns a configuration with WAN port forwards, a WAN allow-any rule and an HTTP web GUI.
+func exposedConfig() *model.OpnSenseDocument {
+	return &model.OpnSenseDocument{
+		System: model.System{
+			Hostname: "edge",
+			Domain:   "example.com",
+			WebGUI:   model.WebGUIConfig{Protocol: "http"},
+			SSH:      model.SSHConfig{Group: "admins"},
+		},
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{
+			"wan": {Enable: "1", IPAddr: "203.0.113.2", Subnet: "24"},
+			"lan": {Enable: "1", IPAddr: "192.168.1.1", Subnet: "24"},
+		}},
+		Nat: model.Nat{Inbound: []model.InboundRule{
+			{
+				Interface: model.InterfaceList{"wan"}, Protocol: "tcp", ExternalPort: "443",
+				InternalIP: "192.168.1.10", InternalPort: "8443", Descr: "Web server",
+			},
+			{
+				Interface: model.InterfaceList{"wan"}, Protocol: "tcp", ExternalPort: "2200-2210",
+				Source: model.Source{Network: "198.51.100.0/24"}, InternalIP: "192.168.1.20",
+			},
+			{Interface: model.InterfaceList{"wan"}, ExternalPort: "25", Disabled: "1"},
+		}},
+		Filter: model.Filter{Rule: []model.Rule{
+			{Type: "pass", Interface: model.InterfaceList{"wan"}, Source: model.Source{Network: "any"}},
+			{Type: "pass", Interface: model.InterfaceList{"lan"}, Source: model.Source{Network: "any"}},
+		}},
+	}
+}
+
+func newTestReport(cfg *model.OpnSenseDocument) *Report {
+	return &Report{
+		Mode:          ModeRed,
+		Configuration: cfg,
+		Findings:      make([]Finding, 0),
+		Compliance:    make(map[string]ComplianceResult),
+		Metadata:      make(map[string]any),
+	}
+}
+
+func findingByComponent(t *testing.T, findings []Finding, component string) Finding {
+	t.Helper()
+
+	for _, finding := range findings {
+		if finding.Component == component {
+			return finding
+		}
+	}
+
+	t.Fatalf("no finding for component %s", component)
+
+	return Finding{}
+}
+
+func TestReport_RedTeamAnalysis(t *testing.T) {
+	report := newTestReport(exposedConfig())
+
+	report.addWANExposedServices()
+	report.addWeakNATRules()
+	report.addAdminPortals()
+	report.addAttackSurfaces()
+	report.addEnumerationData()
+
+	assert.Equal(t, 3, report.Metadata["exposed_services_count"])
+
+	open := findingByComponent(t, report.Findings, "nat.inbound[0]")
+	assert.Equal(t, processor.SeverityHigh, open.Severity)
+	assert.Equal(t, []int{443}, open.AttackSurface.Ports)
+	assert.Equal(t, []string{"192.168.1.10:8443"}, open.AttackSurface.Services)
+
+	restricted := findingByComponent(t, report.Findings, "nat.inbound[1]")
+	assert.Equal(t, processor.SeverityMedium, restricted.Severity)
+	assert.Equal(t, []int{2200, 2210}, restricted.AttackSurface.Ports)
+
+	wanRule := findingByComponent(t, report.Findings, "filter.rule[0]")
+	assert.Equal(t, processor.SeverityHigh, wanRule.Severity)
+	assert.Equal(t, SurfaceFirewallRule, wanRule.AttackSurface.Type)
+
+	webgui := findingByComponent(t, report.Findings, "system.webgui.protocol")
+	assert.Equal(t, SurfaceAdminPortal, webgui.AttackSurface.Type)
+
+	assert.Equal(t, 1, report.Metadata["weak_nat_rules_count"])
+	assert.Equal(t, []string{"webgui (http)", "ssh (group admins)"}, report.Metadata["admin_portals"])
+	assert.Equal(t, 4, report.Metadata["attack_vectors_identified"])
+	assert.Equal(t, []string{"lan: 192.168.1.1/24", "wan: 203.0.113.2/24"}, report.Metadata["enumeration_targets"])
+
+	for _, finding := range report.Findings {
+		assert.Empty(t, finding.ExploitNotes)
+	}
+
+	report.addSnarkyCommentary()
+
+	for _, finding := range report.Findings {
+		assert.NotEmpty(t, finding.ExploitNotes, finding.Title)
+	}
+}
+
+func TestReport_RedTeamAnalysis_SingleSource(t *testing.T) {
+	cfg := exposedConfig()
+	cfg.Nat.Inbound[0].Source = model.Source{Address: "198.51.100.7"}
+	cfg.Filter.Rule[0].Source = model.Source{Address: "198.51.100.7"}
+
+	report := newTestReport(cfg)
+	report.addWANExposedServices()
+	report.addWeakNATRules()
+
+	forward := findingByComponent(t, report.Findings, "nat.inbound[0]")
+	assert.Equal(t, processor.SeverityMedium, forward.Severity)
+	assert.Empty(t, forward.AttackSurface.Vulnerabilities)
+
+	for _, finding := range report.Findings {
+		assert.NotEqual(t, "WAN Pass Rule From Any Source", finding.Title)
+	}
+
+	assert.Equal(t, 0, report.Metadata["weak_nat_rules_count"])
+}
+
+func TestParsePorts(t *testing.T) {
+	assert.Equal(t, []int{22}, parsePorts("22"))
+	assert.Equal(t, []int{8000, 8010}, parsePorts("8000:8010"))
+	assert.Nil(t, parsePorts("web_ports"))
+	assert.Nil(t, parsePorts(""))
+}
+
+func TestReport_AddProcessorFindings(t *testing.T) {
+	report := newTestReport(&model.OpnSenseDocument{})
+	report.AddProcessorFindings(processor.Findings{
+		Critical: []processor.Finding{{Type: "security", Title: "Critical issue", Reference: "SEC-1"}},
+		Low:      []processor.Finding{{Title: "Low issue"}},
+	})
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, processor.SeverityCritical, report.Findings[0].Severity)
+	assert.Equal(t, []string{"security"}, report.Findings[0].Tags)
+	assert.Equal(t, "SEC-1", report.Findings[0].Control)
+	assert.Equal(t, SourceProcessor, report.Findings[0].Source)
+	assert.Equal(t, processor.SeverityLow, report.Findings[1].Severity)
+}
+
+func TestReport_AddComplianceFindings(t *testing.T) {
+	report := newTestReport(&model.OpnSenseDocument{})
+	report.AddComplianceFindings(&ComplianceResult{
+		Findings: []plugin.Finding{
+			{Type: "compliance", Title: "Known control", References: []string{"CTRL-1"}},
+			{Type: "compliance", Title: "Unknown control", References: []string{"CTRL-9"}},
+		},
+		PluginInfo: map[string]PluginInfo{
+			"example": {Name: "example", Controls: []plugin.Control{{ID: "CTRL-1", Severity: "High"}}},
+		},
+	})
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, processor.SeverityHigh, report.Findings[0].Severity)
+	assert.Equal(t, "example", report.Findings[0].Source)
+	assert.Equal(t, "CTRL-1", report.Findings[0].Control)
+	assert.Equal(t, processor.SeverityMedium, report.Findings[1].Severity)
+	assert.Empty(t, report.Findings[1].Source)
+}
+
+func TestModeController_MergesProcessorAndPluginFindings(t *testing.T) {
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(stig.NewPlugin()))
+	require.NoError(t, registry.RegisterPlugin(firewall.NewPlugin()))
+
+	controller := NewModeController(registry, log.New(io.Discard))
+
+	for _, mode := range []ReportMode{ModeStandard, ModeBlue, ModeRed} {
+		t.Run(string(mode), func(t *testing.T) {
+			report, err := controller.GenerateReport(context.Background(), exposedConfig(), &ModeConfig{
+				Mode:            mode,
+				SelectedPlugins: []string{"stig", "firewall"},
+			})
+			require.NoError(t, err)
+
+			sources := make(map[string]bool)
+			for _, finding := range report.Findings {
+				sources[finding.Source] = true
+			}
+
+			assert.True(t, sources[SourceProcessor], "processor findings should be merged")
+			assert.True(t, sources["stig"], "stig findings should be merged")
+			assert.Equal(t, mode == ModeRed, sources[SourceRecon])
+			assert.Contains(t, report.Compliance, pluginResultsKey)
+		})
+	}
+}
@@ -0,0 +1,518 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/htmlreport"
+	"github.com/nao1215/markdown"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedFormat is returned when a report is rendered in an unknown format.
+var ErrUnsupportedFormat = errors.New("unsupported report format")
+
+// blueTableCount is the number of structured configuration tables in a blue team report.
+const blueTableCount = 4
+
+// Render renders the report in the given format (markdown, json, yaml, html, sarif or junit). The
+// builder is used for the configuration sections of markdown and HTML reports and may be nil. SARIF
+// results rendered here have no location and every JUnit finding fails; see ToSARIF and ToJUnit.
+func (r *Report) Render(format string, builder *converter.MarkdownBuilder) (string, error) {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return r.ToMarkdown(builder)
+	case "json":
+		return r.ToJSON()
+	case "yaml", "yml":
+		return r.ToYAML()
+	case "html":
+		return r.ToHTML(builder, "")
+	case "sarif":
+		return r.ToSARIF(nil)
+	case "junit":
+		return r.ToJUnit("", "")
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// ToJSON returns the report as an indented JSON string.
+func (r *Report) ToJSON() (string, error) {
+	versioned := struct {
+		SchemaVersion string `json:"schemaVersion"`
+		*Report
+	}{constants.SchemaVersion, r}
+
+	data, err := json.MarshalIndent(versioned, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit report to JSON: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// ToYAML returns the report as a YAML string.
+func (r *Report) ToYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit report to YAML: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// ToHTML renders the markdown report as a standalone HTML document. The theme selects the initial
+// colour theme (light or dark); any other value follows the system preference.
+func (r *Report) ToHTML(builder *converter.MarkdownBuilder, theme string) (string, error) {
+	content, err := r.ToMarkdown(builder)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := htmlreport.Render(content, htmlreport.Options{Theme: theme})
+	if err != nil {
+		return "", fmt.Errorf("failed to render audit report to HTML: %w", err)
+	}
+
+	return output, nil
+}
+
+// ToMarkdown renders the report as a mode-specific markdown document. Configuration sections are
+// produced by the programmatic builder so that they match the output of the convert command.
+func (r *Report) ToMarkdown(builder *converter.MarkdownBuilder) (string, error) {
+	if r.Configuration == nil {
+		return "", ErrConfigurationNil
+	}
+
+	if builder == nil {
+		builder = converter.NewMarkdownBuilder()
+	}
+
+	switch r.Mode {
+	case ModeStandard:
+		return r.standardMarkdown(builder)
+	case ModeBlue:
+		return r.blueMarkdown(builder), nil
+	case ModeRed:
+		return r.redMarkdown(builder), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedMode, r.Mode)
+	}
+}
+
+// standardMarkdown renders the configuration documentation followed by the audit findings.
+func (r *Report) standardMarkdown(builder *converter.MarkdownBuilder) (string, error) {
+	var (
+		base string
+		err  error
+	)
+
+	if r.Comprehensive {
+		base, err = builder.BuildComprehensiveReport(r.Configuration)
+	} else {
+		base, err = builder.BuildStandardReport(r.Configuration)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to build configuration report: %w", err)
+	}
+
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.PlainText(base)
+	r.writeFindings(md, builder)
+	r.writeWaived(md, builder)
+	r.writeDrift(md, builder)
+	r.writeCompliance(md, builder)
+	r.writeGrouping(md, builder)
+
+	return md.String(), nil
+}
+
+// FindingsMarkdown renders the findings, waivers, drift and compliance results without the
+// configuration documentation, for reports that document the configuration separately.
+func (r *Report) FindingsMarkdown(builder *converter.MarkdownBuilder) string {
+	if builder == nil {
+		builder = converter.NewMarkdownBuilder()
+	}
+
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	r.writeFindings(md, builder)
+	r.writeWaived(md, builder)
+	r.writeDrift(md, builder)
+	r.writeCompliance(md, builder)
+	r.writeGrouping(md, builder)
+
+	return md.String()
+}
+
+// blueMarkdown renders a defensive report: findings, compliance results and structured configuration tables.
+func (r *Report) blueMarkdown(builder *converter.MarkdownBuilder) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.H1("OPNsense Blue Team Audit Report")
+	r.writeHeader(md)
+	r.writeFindings(md, builder)
+	r.writeWaived(md, builder)
+	r.writeDrift(md, builder)
+	r.writeCompliance(md, builder)
+	r.writeGrouping(md, builder)
+
+	cfg := r.Configuration
+
+	md.H2("Configuration Tables")
+	md.H3("Interfaces")
+	md.Table(*builder.BuildInterfaceTable(cfg.Interfaces))
+	md.H3("Firewall Rules")
+	md.Table(*builder.BuildFirewallRulesTable(cfg.Filter.Rule))
+	md.H3("Users")
+	md.Table(*builder.BuildUserTable(cfg.System.User))
+	md.H3("System Tunables")
+	md.Table(*builder.BuildSysctlTable(cfg.Sysctl))
+
+	if r.Comprehensive {
+		md.PlainText(builder.BuildSecuritySection(cfg))
+		md.PlainText(builder.BuildSecurityScoreSection(cfg))
+		md.PlainText(builder.BuildServicesSection(cfg))
+	}
+
+	return md.String()
+}
+
+// redMarkdown renders an attacker-focused report: attack surface, admin portals and enumeration targets.
+func (r *Report) redMarkdown(builder *converter.MarkdownBuilder) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.H1("OPNsense Red Team Recon Report")
+	r.writeHeader(md)
+
+	md.H2("Attack Surface")
+
+	table := markdown.TableSet{Header: []string{"Severity", "Type", "Ports", "Services", "Finding", "Weaknesses"}}
+	if r.BlackhatMode {
+		table.Header = append(table.Header, "Exploit Notes")
+	}
+
+	for _, finding := range r.SortedFindings() {
+		surface := finding.AttackSurface
+		if surface == nil {
+			continue
+		}
+
+		ports := make([]string, 0, len(surface.Ports))
+		for _, port := range surface.Ports {
+			ports = append(ports, strconv.Itoa(port))
+		}
+
+		row := []string{
+			strings.ToUpper(string(finding.Severity)),
+			surface.Type,
+			orDefault(strings.Join(ports, ", "), "-"),
+			builder.EscapeTableContent(strings.Join(surface.Services, ", ")),
+			builder.EscapeTableContent(finding.Title),
+			builder.EscapeTableContent(strings.Join(surface.Vulnerabilities, ", ")),
+		}
+
+		if r.BlackhatMode {
+			row = append(row, builder.EscapeTableContent(finding.ExploitNotes))
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	if len(table.Rows) == 0 {
+		md.PlainText("No exposed services identified.")
+	} else {
+		md.Table(table)
+	}
+
+	writeMetadataList(md, "Admin Portals", r.metadataList("admin_portals"), "No admin portals identified.")
+	writeMetadataList(md, "Weak NAT Rules", r.metadataList("weak_nat_rules"), "No weak NAT rules identified.")
+	writeMetadataList(md, "Enumeration Targets", r.metadataList("enumeration_targets"),
+		"No addressed interfaces identified.")
+
+	r.writeFindings(md, builder)
+	r.writeWaived(md, builder)
+	r.writeDrift(md, builder)
+	r.writeCompliance(md, builder)
+	r.writeGrouping(md, builder)
+
+	if r.Comprehensive {
+		md.PlainText(builder.BuildNetworkSection(r.Configuration))
+	}
+
+	return md.String()
+}
+
+// writeHeader writes the system and report information shared by the blue and red reports.
+func (r *Report) writeHeader(md *markdown.Markdown) {
+	system := r.Configuration.System
+
+	md.H2("Report Information")
+	md.PlainTextf("- **Hostname**: %s", system.Hostname)
+	md.PlainTextf("- **Domain**: %s", system.Domain)
+	md.PlainTextf("- **Platform**: OPNsense %s", system.Firmware.Version)
+	md.PlainTextf("- **Audit Mode**: %s", r.Mode)
+
+	if generated, ok := r.Metadata["generation_time"].(string); ok {
+		md.PlainTextf("- **Generated On**: %s", generated)
+	}
+
+	if frameworks := r.compliancePlugins(); len(frameworks) > 0 {
+		md.PlainTextf("- **Compliance Plugins**: %s", strings.Join(frameworks, ", "))
+	}
+}
+
+// writeFindings writes the severity breakdown and the findings table.
+func (r *Report) writeFindings(md *markdown.Markdown, builder *converter.MarkdownBuilder) {
+	md.H2("Audit Findings Summary")
+
+	if len(r.Findings) == 0 {
+		md.PlainText("No findings to report.")
+		return
+	}
+
+	counts := r.SeverityCounts()
+	summary := make([]string, 0, len(severityOrder()))
+
+	for _, severity := range severityOrder() {
+		summary = append(summary, fmt.Sprintf("%s: %d", markdown.Bold(strings.ToUpper(string(severity))), counts[severity]))
+	}
+
+	md.BulletList(summary...)
+
+	table := markdown.TableSet{
+		Header: []string{"Severity", "Title", "Source", "Component", "Description", "Recommendation"},
+	}
+
+	mapped := slices.ContainsFunc(r.Findings, func(finding Finding) bool { return len(finding.MappedReferences) > 0 })
+	if mapped {
+		table.Header = append(table.Header, "Mapped References")
+	}
+
+	for _, finding := range r.SortedFindings() {
+		row := []string{
+			strings.ToUpper(string(finding.Severity)),
+			builder.EscapeTableContent(finding.Title),
+			orDefault(finding.Source, "-"),
+			builder.EscapeTableContent(finding.Component),
+			builder.EscapeTableContent(finding.Description),
+			builder.EscapeTableContent(finding.Recommendation),
+		}
+
+		if mapped {
+			references := r.mappingCatalogue().Format(finding.MappedReferences)
+			row = append(row, builder.EscapeTableContent(orDefault(references, "-")))
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	md.Table(table)
+}
+
+// writeWaived writes the findings suppressed by waivers together with the waiver that accepted them.
+func (r *Report) writeWaived(md *markdown.Markdown, builder *converter.MarkdownBuilder) {
+	if len(r.Waived) == 0 {
+		return
+	}
+
+	md.H2("Waived Findings")
+
+	table := markdown.TableSet{
+		Header: []string{"Severity", "Title", "Source", "Component", "Waiver", "Owner", "Expires", "Justification"},
+	}
+
+	for _, waived := range r.Waived {
+		table.Rows = append(table.Rows, []string{
+			strings.ToUpper(string(waived.Severity)),
+			builder.EscapeTableContent(waived.Title),
+			orDefault(waived.Source, "-"),
+			builder.EscapeTableContent(waived.Component),
+			builder.EscapeTableContent(waived.Waiver.ID),
+			builder.EscapeTableContent(waived.Waiver.Owner),
+			waived.Waiver.Expires.String(),
+			builder.EscapeTableContent(waived.Waiver.Justification),
+		})
+	}
+
+	md.Table(table)
+}
+
+// writeDrift writes the new and resolved findings relative to the baseline report.
+func (r *Report) writeDrift(md *markdown.Markdown, builder *converter.MarkdownBuilder) {
+	if r.Drift == nil {
+		return
+	}
+
+	md.H2("Baseline Drift")
+	md.BulletList(
+		fmt.Sprintf("%s: %d", markdown.Bold("NEW"), r.Drift.New),
+		fmt.Sprintf("%s: %d", markdown.Bold("UNCHANGED"), r.Drift.Unchanged),
+		fmt.Sprintf("%s: %d", markdown.Bold("RESOLVED"), len(r.Drift.Resolved)),
+	)
+
+	writeDriftTable(md, builder, "New Findings", r.NewFindings())
+	writeDriftTable(md, builder, "Resolved Findings", r.Drift.Resolved)
+}
+
+// writeDriftTable writes a table of findings identified by their fingerprint.
+func writeDriftTable(md *markdown.Markdown, builder *converter.MarkdownBuilder, title string, findings []Finding) {
+	if len(findings) == 0 {
+		return
+	}
+
+	md.H3(title)
+
+	table := markdown.TableSet{Header: []string{"Severity", "Title", "Source", "Component", "Fingerprint"}}
+
+	for _, finding := range findings {
+		table.Rows = append(table.Rows, []string{
+			strings.ToUpper(string(finding.Severity)),
+			builder.EscapeTableContent(finding.Title),
+			orDefault(finding.Source, "-"),
+			builder.EscapeTableContent(finding.Component),
+			finding.Fingerprint,
+		})
+	}
+
+	md.Table(table)
+}
+
+// writeGrouping writes the results grouped by the identifiers of the target framework when grouping was requested.
+func (r *Report) writeGrouping(md *markdown.Markdown, builder *converter.MarkdownBuilder) {
+	if r.Grouping == nil {
+		return
+	}
+
+	md.H2("Results by " + r.Grouping.Name)
+
+	if len(r.Grouping.Groups) == 0 {
+		md.PlainText("No results map to this framework.")
+		return
+	}
+
+	table := markdown.TableSet{Header: []string{"Reference", "Controls", "Findings"}}
+	for _, group := range r.Grouping.Groups {
+		controls := make([]string, 0, len(group.Controls))
+		for _, control := range group.Controls {
+			controls = append(controls, fmt.Sprintf("%s %s (%s)", control.Plugin, control.Control,
+				strings.ToUpper(string(control.Status))))
+		}
+
+		table.Rows = append(table.Rows, []string{
+			group.Reference,
+			builder.EscapeTableContent(orDefault(strings.Join(controls, ", "), "-")),
+			builder.EscapeTableContent(orDefault(strings.Join(group.Findings, ", "), "-")),
+		})
+	}
+
+	md.Table(table)
+}
+
+// writeCompliance writes the compliance matrix when compliance plugins were run: a summary per plugin
+// followed by a control-by-control table for each plugin.
+func (r *Report) writeCompliance(md *markdown.Markdown, builder *converter.MarkdownBuilder) {
+	result, ok := r.Compliance[pluginResultsKey]
+	if !ok || result.Summary == nil {
+		return
+	}
+
+	md.H2("Compliance Results")
+
+	table := markdown.TableSet{
+		Header: []string{"Plugin", "Version", "Passed", "Failed", "N/A", "Manual Review", "Errors", "Total", "Compliance"},
+	}
+
+	for _, name := range r.compliancePlugins() {
+		stats := result.Summary.Compliance[name]
+		table.Rows = append(table.Rows, []string{
+			name,
+			result.PluginInfo[name].Version,
+			strconv.Itoa(stats.Compliant),
+			strconv.Itoa(stats.NonCompliant),
+			strconv.Itoa(stats.NotApplicable),
+			strconv.Itoa(stats.ManualReview),
+			strconv.Itoa(stats.Errors),
+			strconv.Itoa(stats.Total),
+			fmt.Sprintf("%.1f%%", stats.Percentage),
+		})
+	}
+
+	md.Table(table)
+
+	for _, name := range r.compliancePlugins() {
+		results := result.Results[name]
+		if len(results) == 0 {
+			continue
+		}
+
+		md.H3(result.PluginInfo[name].Name + " Controls")
+
+		controls := markdown.TableSet{Header: []string{"Control", "Title", "Severity", "Status", "Evidence"}}
+		for _, controlResult := range results {
+			evidence := controlResult.Message
+			if len(controlResult.Evidence) > 0 {
+				evidence = strings.Join(controlResult.Evidence, "; ")
+			}
+
+			controls.Rows = append(controls.Rows, []string{
+				controlResult.ControlID,
+				builder.EscapeTableContent(controlResult.Title),
+				orDefault(controlResult.Severity, "-"),
+				strings.ToUpper(string(controlResult.Status)),
+				builder.EscapeTableContent(orDefault(evidence, "-")),
+			})
+		}
+
+		md.Table(controls)
+	}
+}
+
+// compliancePlugins returns the sorted names of the plugins that were run.
+func (r *Report) compliancePlugins() []string {
+	result, ok := r.Compliance[pluginResultsKey]
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(result.PluginInfo))
+	for name := range result.PluginInfo {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// metadataList returns a string list stored in the report metadata.
+func (r *Report) metadataList(key string) []string {
+	values, _ := r.Metadata[key].([]string)
+	return values
+}
+
+// writeMetadataList writes a section with a bullet list, or the empty message when there are no items.
+func writeMetadataList(md *markdown.Markdown, title string, items []string, empty string) {
+	md.H2(title)
+
+	if len(items) == 0 {
+		md.PlainText(empty)
+		return
+	}
+
+	md.BulletList(items...)
+}
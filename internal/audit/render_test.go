@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/sans"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func generateTestReport(t *testing.T, config *ModeConfig) *Report {
+	t.Helper()
+
+	registry := NewPluginRegistry()
+	require.NoError(t, registry.RegisterPlugin(sans.NewPlugin()))
+
+	report, err := NewModeController(registry, log.New(io.Discard)).
+		GenerateReport(context.Background(), exposedConfig(), config)
+	require.NoError(t, err)
+
+	return report
+}
+
+func TestReport_ToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *ModeConfig
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "standard",
+			config:   &ModeConfig{Mode: ModeStandard},
+			contains: []string{"# OPNsense Configuration Summary", "## Audit Findings Summary"},
+			excludes: []string{"## Compliance Results"},
+		},
+		{
+			name:   "blue",
+			config: &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}},
+			contains: []string{
+				"# OPNsense Blue Team Audit Report",
+				"## Compliance Results",
+				"### sans Controls",
+				"SANS-FW-001",
+				"## Configuration Tables",
+				"### Firewall Rules",
+			},
+			excludes: []string{"## Attack Surface"},
+		},
+		{
+			name:   "red",
+			config: &ModeConfig{Mode: ModeRed},
+			contains: []string{
+				"# OPNsense Red Team Recon Report",
+				"## Attack Surface",
+				"WAN Port Forward",
+				"ssh (group admins)",
+				"wan: 203.0.113.2/24",
+			},
+			excludes: []string{"EXPLOIT NOTES"},
+		},
+		{
+			name:     "red with blackhat",
+			config:   &ModeConfig{Mode: ModeRed, BlackhatMode: true},
+			contains: []string{"EXPLOIT NOTES", "Bring a sniffer."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := generateTestReport(t, tt.config).ToMarkdown(nil)
+			require.NoError(t, err)
+
+			for _, text := range tt.contains {
+				assert.Contains(t, output, text)
+			}
+
+			for _, text := range tt.excludes {
+				assert.NotContains(t, output, text)
+			}
+		})
+	}
+}
+
+func TestReport_Render(t *testing.T) {
+	report := generateTestReport(t, &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := report.Render("json", nil)
+		require.NoError(t, err)
+
+		var decoded Report
+		require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+		assert.Equal(t, ModeBlue, decoded.Mode)
+		assert.Len(t, decoded.Findings, len(report.Findings))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		output, err := report.Render("yaml", nil)
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(output), &decoded))
+		assert.Equal(t, "blue", decoded["mode"])
+		assert.Contains(t, decoded, "findings")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		output, err := report.ToHTML(nil, "dark")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+		assert.Contains(t, output, `<html lang="en" data-theme="dark">`)
+		assert.Contains(t, output, `href="#audit-findings-summary"`)
+		assert.Contains(t, output, `class="severity severity-`)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		output, err := report.Render("sarif", nil)
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+		assert.Equal(t, "2.1.0", decoded["version"])
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		output, err := report.Render("junit", nil)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(output, "<?xml"))
+		assert.Contains(t, output, `classname="sans"`)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := report.Render("pdf", nil)
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
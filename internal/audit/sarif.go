@@ -0,0 +1,201 @@
+package audit
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+)
+
+// ToSARIF returns the report as a SARIF 2.1.0 log. Every control of the selected plugins becomes a
+// rule, as does every check of the core analysis that reported a finding. Waived findings are
+// reported as suppressed results. The locator places results in the configuration file; without
+// one, results have no location.
+func (r *Report) ToSARIF(locator *sarif.Locator) (string, error) {
+	builder := sarif.NewBuilder()
+	controls := r.pluginControls()
+
+	for _, name := range slices.Sorted(maps.Keys(controls)) {
+		for _, control := range controls[name] {
+			builder.AddRule(controlRule(name, control))
+		}
+	}
+
+	for _, finding := range r.SortedFindings() {
+		builder.AddResult(r.sarifResult(builder, controls, finding, locator))
+	}
+
+	for _, waived := range r.Waived {
+		result := r.sarifResult(builder, controls, waived.Finding, locator)
+		result.Suppressions = []sarif.Suppression{{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: waived.Waiver.Justification,
+		}}
+
+		builder.AddResult(result)
+	}
+
+	return builder.Log().ToJSON()
+}
+
+// pluginControls returns the controls of the plugins that ran, keyed by plugin name.
+func (r *Report) pluginControls() map[string][]plugin.Control {
+	result, ok := r.Compliance[pluginResultsKey]
+	if !ok {
+		return nil
+	}
+
+	controls := make(map[string][]plugin.Control, len(result.PluginInfo))
+	for name, info := range result.PluginInfo {
+		controls[name] = info.Controls
+	}
+
+	return controls
+}
+
+// sarifResult converts a finding into a result, adding the rule of its check when the finding does
+// not reference a plugin control.
+func (r *Report) sarifResult(
+	builder *sarif.Builder,
+	controls map[string][]plugin.Control,
+	finding Finding,
+	locator *sarif.Locator,
+) sarif.Result {
+	rule := findingRule(finding)
+	if control := matchControl(controls[finding.Source], finding.Control); control != nil {
+		rule = controlRule(finding.Source, *control)
+	}
+
+	builder.AddRule(rule)
+
+	message := finding.Description
+	if message == "" {
+		message = finding.Title
+	}
+
+	result := sarif.Result{
+		RuleID:  rule.ID,
+		Level:   sarif.Level(string(finding.Severity)),
+		Message: sarif.Message{Text: message},
+	}
+
+	if finding.Fingerprint != "" {
+		result.PartialFingerprints = map[string]string{sarif.FingerprintKey: finding.Fingerprint}
+	}
+
+	if locator != nil {
+		result.Locations = []sarif.Location{locator.Locate(finding.Object, finding.Component)}
+	}
+
+	return result
+}
+
+// matchControl returns the control a finding references. Plugins may prefix the control ID with
+// the framework name, as in "STIG V-206694".
+func matchControl(controls []plugin.Control, reference string) *plugin.Control {
+	if reference == "" {
+		return nil
+	}
+
+	for i := range controls {
+		if reference == controls[i].ID || strings.HasSuffix(reference, " "+controls[i].ID) {
+			return &controls[i]
+		}
+	}
+
+	return nil
+}
+
+// controlRule returns the rule of a plugin control.
+func controlRule(pluginName string, control plugin.Control) sarif.Rule {
+	help := control.Remediation
+	if control.Rationale != "" {
+		help = strings.TrimSpace(help + "\n\nRationale: " + control.Rationale)
+	}
+
+	return sarif.Rule{
+		ID:               control.ID,
+		ShortDescription: &sarif.Message{Text: control.Title},
+		FullDescription:  descriptionMessage(control.Description, control.Title),
+		Help:             helpMessage(help),
+		DefaultConfiguration: &sarif.Configuration{
+			Level: sarif.Level(control.Severity),
+		},
+		Properties: &sarif.Properties{
+			Tags:             ruleTags(pluginName, control.Tags),
+			SecuritySeverity: sarif.SecuritySeverity(control.Severity),
+		},
+	}
+}
+
+// findingRule returns the rule of a check that is not a plugin control. Its ID is the source and
+// the title of the finding, such as "processor/insecure-web-gui-protocol".
+func findingRule(finding Finding) sarif.Rule {
+	severity := string(finding.Severity)
+
+	return sarif.Rule{
+		ID:               finding.Source + "/" + ruleSlug(finding.Title),
+		ShortDescription: &sarif.Message{Text: finding.Title},
+		FullDescription:  descriptionMessage(finding.Description, finding.Title),
+		Help:             helpMessage(finding.Recommendation),
+		DefaultConfiguration: &sarif.Configuration{
+			Level: sarif.Level(severity),
+		},
+		Properties: &sarif.Properties{
+			Tags:             ruleTags(finding.Source, finding.Tags),
+			SecuritySeverity: sarif.SecuritySeverity(severity),
+		},
+	}
+}
+
+// descriptionMessage returns the full description of a rule, falling back to its title.
+func descriptionMessage(description, title string) *sarif.Message {
+	if description == "" {
+		description = title
+	}
+
+	return &sarif.Message{Text: description}
+}
+
+// helpMessage returns the help of a rule, or nil when there is no remediation.
+func helpMessage(text string) *sarif.Message {
+	if text == "" {
+		return nil
+	}
+
+	return &sarif.Message{Text: text}
+}
+
+// ruleTags returns the tags of a rule: the security tag dashboards filter on, the source and the
+// tags of the check.
+func ruleTags(source string, tags []string) []string {
+	result := []string{"security"}
+	if source != "" {
+		result = append(result, source)
+	}
+
+	for _, tag := range tags {
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// ruleSlug converts a finding title into a rule ID component.
+func ruleSlug(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return "finding"
+	}
+
+	return strings.Join(words, "-")
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif/sariftest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sarifTestConfig = `<opnsense>
+  <filter>
+    <rule uuid="r-1">
+      <type>pass</type>
+    </rule>
+  </filter>
+</opnsense>
+`
+
+func decodeSARIF(t *testing.T, output string) sarif.Log {
+	t.Helper()
+
+	sariftest.RequireValid(t, output)
+
+	var log sarif.Log
+	require.NoError(t, json.Unmarshal([]byte(output), &log))
+	require.Len(t, log.Runs, 1)
+
+	return log
+}
+
+func TestReport_ToSARIF(t *testing.T) {
+	set := parseWaivers(t, `
+waivers:
+  - id: WVR-SANS
+    source: sans
+    justification: Reviewed by the security board
+    owner: secops
+    expires: 2099-12-31
+`)
+
+	report := generateTestReport(t, &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}, Waivers: set})
+	require.NotEmpty(t, report.Waived)
+
+	output, err := report.ToSARIF(nil)
+	require.NoError(t, err)
+
+	run := decodeSARIF(t, output).Runs[0]
+	assert.Equal(t, "opnDossier", run.Tool.Driver.Name)
+	assert.Len(t, run.Results, len(report.Findings)+len(report.Waived))
+
+	rules := make(map[string]sarif.Rule, len(run.Tool.Driver.Rules))
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = rule
+	}
+
+	require.Contains(t, rules, "SANS-FW-001", "every plugin control is a rule")
+	assert.Contains(t, rules["SANS-FW-001"].Properties.Tags, "sans")
+	assert.NotEmpty(t, rules["SANS-FW-001"].Properties.SecuritySeverity)
+
+	suppressed := 0
+
+	for _, result := range run.Results {
+		assert.Equal(t, result.RuleID, run.Tool.Driver.Rules[result.RuleIndex].ID)
+		assert.NotEmpty(t, result.PartialFingerprints[sarif.FingerprintKey])
+		assert.Empty(t, result.Locations, "results are not located without a locator")
+
+		if len(result.Suppressions) > 0 {
+			suppressed++
+
+			assert.Equal(t, "Reviewed by the security board", result.Suppressions[0].Justification)
+		}
+	}
+
+	assert.Equal(t, len(report.Waived), suppressed)
+}
+
+func TestReport_ToSARIF_Locations(t *testing.T) {
+	report := &Report{
+		Findings: []Finding{
+			{
+				Title:     "Overly Permissive Rule",
+				Severity:  processor.SeverityHigh,
+				Source:    SourceProcessor,
+				Component: "filter.rule[0]",
+				Object:    "filter.rule/r-1",
+			},
+			{Title: "Weak tunable", Severity: processor.SeverityLow, Source: SourceProcessor, Component: "sysctl"},
+		},
+	}
+
+	locator := sarif.NewLocator("config.xml", strings.NewReader(sarifTestConfig))
+
+	output, err := report.ToSARIF(locator)
+	require.NoError(t, err)
+
+	run := decodeSARIF(t, output).Runs[0]
+	require.Len(t, run.Results, 2)
+
+	byRule := make(map[string]sarif.Result, len(run.Results))
+	for _, result := range run.Results {
+		byRule[result.RuleID] = result
+	}
+
+	permissive := byRule["processor/overly-permissive-rule"]
+	require.Len(t, permissive.Locations, 1)
+	assert.Equal(t, sarif.LevelError, permissive.Level)
+	assert.Equal(t, "Overly Permissive Rule", permissive.Message.Text, "the title is the message without a description")
+	assert.Equal(t, 3, permissive.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "/opnsense/filter/rule[@uuid='r-1']", permissive.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	tunable := byRule["processor/weak-tunable"]
+	require.Len(t, tunable.Locations, 1)
+	assert.Equal(t, sarif.LevelNote, tunable.Level)
+	assert.Equal(t, "config.xml", tunable.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, tunable.Locations[0].PhysicalLocation.Region)
+}
+
+func TestMatchControl(t *testing.T) {
+	report := generateTestReport(t, &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}})
+	controls := report.pluginControls()["sans"]
+	require.NotEmpty(t, controls)
+
+	id := controls[0].ID
+	assert.Equal(t, id, matchControl(controls, id).ID)
+	assert.Equal(t, id, matchControl(controls, "SANS "+id).ID)
+	assert.Nil(t, matchControl(controls, ""))
+	assert.Nil(t, matchControl(controls, "X"+id))
+}
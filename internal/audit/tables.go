@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/tabular"
+)
+
+// Finding statuses in the findings table.
+const (
+	findingStatusOpen   = "open"
+	findingStatusWaived = "waived"
+)
+
+// FindingsTable returns the findings of the report as a table, most severe first, followed by the
+// waived findings. Mapped references are listed as "framework:id" pairs separated by semicolons.
+func (r *Report) FindingsTable() tabular.Table {
+	table := tabular.Table{
+		Name: tabular.TableFindings,
+		Columns: []string{
+			"Severity", "Title", "Source", "Control", "Component", "Object", "Description",
+			"Recommendation", "Tags", "Mapped References", "Fingerprint", "Drift", "Status", "Waiver",
+			"Waiver Expires",
+		},
+		Rows: make([][]string, 0, len(r.Findings)+len(r.Waived)),
+	}
+
+	for _, finding := range r.SortedFindings() {
+		table.Rows = append(table.Rows, append(findingCells(finding), findingStatusOpen, "", ""))
+	}
+
+	for _, waived := range r.Waived {
+		table.Rows = append(table.Rows, append(findingCells(waived.Finding),
+			findingStatusWaived, waived.Waiver.ID, waived.Waiver.Expires.String()))
+	}
+
+	return table
+}
+
+// findingCells returns the cells of a finding up to and including its drift classification.
+func findingCells(f Finding) []string {
+	references := make([]string, 0, len(f.MappedReferences))
+	for _, framework := range slices.Sorted(maps.Keys(f.MappedReferences)) {
+		for _, id := range f.MappedReferences[framework] {
+			references = append(references, framework+":"+id)
+		}
+	}
+
+	return []string{
+		string(f.Severity),
+		f.Title,
+		f.Source,
+		f.Control,
+		f.Component,
+		f.Object,
+		f.Description,
+		f.Recommendation,
+		strings.Join(f.Tags, ";"),
+		strings.Join(references, ";"),
+		f.Fingerprint,
+		f.Drift,
+	}
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/tabular"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_FindingsTable(t *testing.T) {
+	report := &Report{
+		Findings: []Finding{
+			{Title: "No logging", Severity: processor.SeverityLow, Source: "sans", Control: "FW-2"},
+			{
+				Title: "Open rule", Severity: processor.SeverityHigh, Source: "stig", Control: "V-1",
+				Component: "filter/rule[1]", Tags: []string{"firewall", "exposure"}, Fingerprint: "abc", Drift: "new",
+				MappedReferences: map[string][]string{"nist-800-53": {"AC-4", "SC-7"}, "cis": {"1.1"}},
+			},
+		},
+		Waived: []WaivedFinding{{
+			Finding: Finding{Title: "No VPN", Severity: processor.SeverityMedium, Source: "sans"},
+			Waiver: waiver.Waiver{
+				ID:      "WVR-1",
+				Expires: waiver.Date{Time: time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)},
+			},
+		}},
+	}
+
+	table := report.FindingsTable()
+	assert.Equal(t, tabular.TableFindings, table.Name)
+	require.Len(t, table.Rows, 3)
+
+	for _, row := range table.Rows {
+		assert.Len(t, row, len(table.Columns))
+	}
+
+	assert.Equal(t, []string{
+		"high", "Open rule", "stig", "V-1", "filter/rule[1]", "", "", "", "firewall;exposure",
+		"cis:1.1;nist-800-53:AC-4;nist-800-53:SC-7", "abc", "new", "open", "", "",
+	}, table.Rows[0])
+	assert.Equal(t, "No logging", table.Rows[1][1])
+	assert.Equal(t, []string{"waived", "WVR-1", "2030-01-31"}, table.Rows[2][12:])
+}
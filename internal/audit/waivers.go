@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
+)
+
+// SourceWaiver marks findings about the waiver file itself, such as expired waivers.
+const SourceWaiver = "waiver"
+
+// WaivedFinding is a finding whose risk has been accepted by a waiver.
+type WaivedFinding struct {
+	Finding `yaml:",inline"`
+
+	Waiver waiver.Waiver `json:"waiver" yaml:"waiver"`
+}
+
+// Subject returns the values a waiver matches a finding on.
+func (f *Finding) Subject() waiver.Subject {
+	return waiver.Subject{
+		Fingerprint: f.Fingerprint,
+		Source:      f.Source,
+		Control:     f.Control,
+		Component:   f.Component,
+	}
+}
+
+// ApplyWaivers moves the findings matched by an active waiver to the waived findings and adds a
+// finding for every expired waiver. Findings are fingerprinted first, so that the fingerprints can
+// be used in waivers even when no waiver file is given.
+func (r *Report) ApplyWaivers(set *waiver.Set, now time.Time) {
+	r.assignFingerprints()
+
+	if set == nil {
+		return
+	}
+
+	findings := make([]Finding, 0, len(r.Findings))
+
+	for _, finding := range r.Findings {
+		if w := set.Match(finding.Subject(), now); w != nil {
+			r.Waived = append(r.Waived, WaivedFinding{Finding: finding, Waiver: *w})
+			continue
+		}
+
+		findings = append(findings, finding)
+	}
+
+	expired := set.Expired(now)
+	for _, w := range expired {
+		findings = append(findings, expiredWaiverFinding(w))
+	}
+
+	r.Findings = findings
+	r.assignFingerprints()
+
+	r.Metadata["waived_findings_count"] = len(r.Waived)
+	r.Metadata["expired_waivers_count"] = len(expired)
+}
+
+// assignFingerprints fingerprints the findings that have no fingerprint yet. The fingerprint is
+// derived from the source, the check, identified by control and title, and the configuration
+// object, so that it survives reordering rules and rewording descriptions.
+func (r *Report) assignFingerprints() {
+	for i := range r.Findings {
+		finding := &r.Findings[i]
+		if finding.Fingerprint != "" {
+			continue
+		}
+
+		object := finding.Object
+		if object == "" {
+			object = finding.Component
+		}
+
+		finding.Fingerprint = waiver.Fingerprint(finding.Source, finding.Control, finding.Title, object)
+	}
+}
+
+// expiredWaiverFinding reports an expired waiver. The findings it matched are no longer waived.
+func expiredWaiverFinding(w waiver.Waiver) Finding {
+	return Finding{
+		Title:    "Expired Waiver " + w.ID,
+		Severity: processor.SeverityMedium,
+		Description: fmt.Sprintf("Waiver %s owned by %s expired on %s, so the findings it matched are reported "+
+			"again. Justification: %s", w.ID, w.Owner, w.Expires, w.Justification),
+		Recommendation: "Review the accepted risk and either renew the waiver with a new expiry date or remove it",
+		Tags:           []string{"waiver"},
+		Component:      "waivers",
+		Control:        w.ID,
+		Source:         SourceWaiver,
+	}
+}
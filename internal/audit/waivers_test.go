@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseWaivers(t *testing.T, doc string) *waiver.Set {
+	t.Helper()
+
+	set, err := waiver.Parse(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	return set
+}
+
+func TestReport_ApplyWaivers(t *testing.T) {
+	set := parseWaivers(t, `
+waivers:
+  - id: WVR-001
+    source: sans
+    control: SANS-FW-001
+    justification: Accepted until the migration
+    owner: network-team
+    expires: 2025-06-30
+  - id: WVR-OLD
+    component: sysctl*
+    justification: Provider managed
+    owner: provider
+    expires: 2024-12-31
+`)
+
+	report := &Report{
+		Findings: []Finding{
+			{Title: "Default deny missing", Severity: processor.SeverityHigh, Source: "sans", Control: "SANS-FW-001"},
+			{Title: "Weak tunable", Severity: processor.SeverityLow, Source: SourceProcessor, Component: "sysctl"},
+		},
+		Metadata: make(map[string]any),
+	}
+
+	report.ApplyWaivers(set, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	require.Len(t, report.Waived, 1)
+	assert.Equal(t, "Default deny missing", report.Waived[0].Title)
+	assert.Equal(t, "WVR-001", report.Waived[0].Waiver.ID)
+	assert.NotEmpty(t, report.Waived[0].Fingerprint)
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "Weak tunable", report.Findings[0].Title, "expired waivers do not match")
+
+	expired := report.Findings[1]
+	assert.Equal(t, "Expired Waiver WVR-OLD", expired.Title)
+	assert.Equal(t, SourceWaiver, expired.Source)
+	assert.Equal(t, processor.SeverityMedium, expired.Severity)
+
+	for _, finding := range report.Findings {
+		assert.Len(t, finding.Fingerprint, 16)
+	}
+
+	assert.Equal(t, 1, report.Metadata["waived_findings_count"])
+	assert.Equal(t, 1, report.Metadata["expired_waivers_count"])
+}
+
+func TestReport_ApplyWaivers_NoWaivers(t *testing.T) {
+	report := &Report{
+		Findings: []Finding{{Title: "Weak tunable", Source: SourceProcessor, Component: "sysctl"}},
+		Metadata: make(map[string]any),
+	}
+
+	report.ApplyWaivers(nil, time.Now())
+
+	assert.Empty(t, report.Waived)
+	assert.Equal(t, waiver.Fingerprint(SourceProcessor, "", "Weak tunable", "sysctl"), report.Findings[0].Fingerprint)
+	assert.NotContains(t, report.Metadata, "waived_findings_count")
+}
+
+func TestReport_RenderWaived(t *testing.T) {
+	set := parseWaivers(t, `
+waivers:
+  - id: WVR-SANS
+    source: sans
+    justification: Reviewed by the security board
+    owner: secops
+    expires: 2099-12-31
+`)
+
+	report := generateTestReport(t, &ModeConfig{Mode: ModeBlue, SelectedPlugins: []string{"sans"}, Waivers: set})
+	require.NotEmpty(t, report.Waived)
+
+	for _, finding := range report.Findings {
+		assert.NotEqual(t, "sans", finding.Source)
+	}
+
+	output, err := report.Render("markdown", nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "## Waived Findings")
+	assert.Contains(t, output, "WVR-SANS")
+	assert.Contains(t, output, "Reviewed by the security board")
+}
@@ -0,0 +1,145 @@
+// Package baseline reads the findings of a previous audit report so that a new report can be
+// compared with it.
+//
+// A baseline is a report written by the audit command in JSON format. Findings are matched by
+// their fingerprint, which is derived from the source, the check and the identity of the
+// configuration object, such as a rule UUID. Findings therefore keep their fingerprint when rules
+// are reordered.
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+)
+
+// ErrInvalidBaseline indicates a baseline that is not a JSON audit report.
+var ErrInvalidBaseline = errors.New("invalid baseline report")
+
+// Drift classifications of findings relative to a baseline.
+const (
+	// StatusNew marks findings that are not in the baseline.
+	StatusNew = "new"
+	// StatusUnchanged marks findings that are also in the baseline.
+	StatusUnchanged = "unchanged"
+	// StatusResolved marks baseline findings that are no longer reported.
+	StatusResolved = "resolved"
+)
+
+// Entry is a finding of the baseline report.
+type Entry struct {
+	Fingerprint    string `json:"fingerprint"`
+	Title          string `json:"title"`
+	Severity       string `json:"severity"`
+	Description    string `json:"description"`
+	Recommendation string `json:"recommendation"`
+	Component      string `json:"component"`
+	Control        string `json:"control,omitempty"`
+	Source         string `json:"source,omitempty"`
+}
+
+// Baseline holds the findings of a previous report. Findings that were waived in the previous
+// report count as part of the baseline, so lifting a waiver does not report them as new.
+type Baseline struct {
+	Findings []Entry `json:"findings"`
+	Waived   []Entry `json:"waived,omitempty"`
+}
+
+// Load reads a baseline report.
+func Load(path string) (*Baseline, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline report: %w", err)
+	}
+	defer f.Close()
+
+	b, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+// Parse decodes a JSON audit report. Every finding must have a fingerprint; reports written
+// before fingerprints were introduced cannot be used as a baseline.
+func Parse(r io.Reader) (*Baseline, error) {
+	var report struct {
+		Findings *[]Entry `json:"findings"`
+		Waived   []Entry  `json:"waived"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBaseline, err)
+	}
+
+	if report.Findings == nil {
+		return nil, fmt.Errorf("%w: no findings, expected an audit report in JSON format", ErrInvalidBaseline)
+	}
+
+	b := &Baseline{Findings: *report.Findings, Waived: report.Waived}
+
+	for _, entries := range [][]Entry{b.Findings, b.Waived} {
+		for _, entry := range entries {
+			if entry.Fingerprint == "" {
+				return nil, fmt.Errorf("%w: finding %q has no fingerprint, regenerate the baseline report",
+					ErrInvalidBaseline, entry.Title)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// Comparison classifies findings relative to a baseline. Findings with the same fingerprint are
+// matched one to one, so a second occurrence of a baseline finding is new.
+type Comparison struct {
+	baseline  *Baseline
+	remaining map[string]int
+}
+
+// Compare starts a comparison with the baseline.
+func (b *Baseline) Compare() *Comparison {
+	remaining := make(map[string]int, len(b.Findings)+len(b.Waived))
+
+	for _, entries := range [][]Entry{b.Findings, b.Waived} {
+		for _, entry := range entries {
+			remaining[entry.Fingerprint]++
+		}
+	}
+
+	return &Comparison{baseline: b, remaining: remaining}
+}
+
+// Classify returns StatusUnchanged when the fingerprint matches an unmatched baseline finding
+// and StatusNew otherwise.
+func (c *Comparison) Classify(fingerprint string) string {
+	if c.remaining[fingerprint] == 0 {
+		return StatusNew
+	}
+
+	c.remaining[fingerprint]--
+
+	return StatusUnchanged
+}
+
+// Resolved returns the baseline findings that were not matched by Classify.
+func (c *Comparison) Resolved() []Entry {
+	remaining := maps.Clone(c.remaining)
+
+	var resolved []Entry
+
+	for _, entries := range [][]Entry{c.baseline.Findings, c.baseline.Waived} {
+		for _, entry := range entries {
+			if remaining[entry.Fingerprint] > 0 {
+				remaining[entry.Fingerprint]--
+				resolved = append(resolved, entry)
+			}
+		}
+	}
+
+	return resolved
+}
@@ -0,0 +1,86 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testReport = `{
+  "mode": "blue",
+  "findings": [
+    {"title": "Overly Permissive WAN Rule", "severity": "high", "component": "filter.rule[0]", "fingerprint": "aaaa"},
+    {"title": "Configuration Validation Error", "severity": "high", "component": "configuration", "fingerprint": "bbbb"},
+    {"title": "Configuration Validation Error", "severity": "high", "component": "configuration", "fingerprint": "bbbb"},
+    {"title": "Default SNMP Community String", "severity": "high", "component": "snmpd", "fingerprint": "cccc"}
+  ],
+  "waived": [
+    {"title": "Root Account Enabled", "severity": "medium", "fingerprint": "dddd", "waiver": {"id": "WVR-001"}}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	b, err := Parse(strings.NewReader(testReport))
+	require.NoError(t, err)
+	assert.Len(t, b.Findings, 4)
+	require.Len(t, b.Waived, 1)
+	assert.Equal(t, "dddd", b.Waived[0].Fingerprint)
+
+	empty, err := Parse(strings.NewReader(`{"findings": []}`))
+	require.NoError(t, err)
+	assert.Empty(t, empty.Findings)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		errMsg string
+	}{
+		{name: "not JSON", doc: "mode: blue", errMsg: "invalid character"},
+		{name: "no findings", doc: `{"mode": "blue"}`, errMsg: "expected an audit report in JSON format"},
+		{name: "no fingerprint", doc: `{"findings": [{"title": "Old"}]}`, errMsg: `finding "Old" has no fingerprint`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.doc))
+			require.ErrorIs(t, err, ErrInvalidBaseline)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestComparison(t *testing.T) {
+	b, err := Parse(strings.NewReader(testReport))
+	require.NoError(t, err)
+
+	comparison := b.Compare()
+	assert.Equal(t, StatusUnchanged, comparison.Classify("aaaa"))
+	assert.Equal(t, StatusNew, comparison.Classify("aaaa"), "each baseline finding matches once")
+	assert.Equal(t, StatusUnchanged, comparison.Classify("bbbb"))
+	assert.Equal(t, StatusUnchanged, comparison.Classify("dddd"), "waived baseline findings are known")
+	assert.Equal(t, StatusNew, comparison.Classify("eeee"))
+
+	resolved := comparison.Resolved()
+	require.Len(t, resolved, 2)
+	assert.Equal(t, "bbbb", resolved[0].Fingerprint)
+	assert.Equal(t, "cccc", resolved[1].Fingerprint)
+	assert.Len(t, comparison.Resolved(), 2, "Resolved does not consume the comparison")
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "previous.json")
+	require.NoError(t, os.WriteFile(path, []byte(testReport), 0o600))
+
+	b, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, b.Findings, 4)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -25,6 +25,28 @@ type Config struct {
 	WrapWidth   int      `mapstructure:"wrap"`
 	Engine      string   `mapstructure:"engine"`       // Generation engine (programmatic, template)
 	UseTemplate bool     `mapstructure:"use_template"` // Explicitly enable template mode
+
+	Scoring        ScoringConfig `mapstructure:"scoring"`         // Security score catalogue overrides
+	SysctlBaseline string        `mapstructure:"sysctl_baseline"` // Custom sysctl hardening baseline file
+	ControlMapping string        `mapstructure:"control_mapping"` // Custom cross-framework control mapping file
+	PluginDir      string        `mapstructure:"plugin_dir"`      // Directory of external compliance plugins
+	WaiverFile     string        `mapstructure:"waiver_file"`     // Waivers of accepted findings
+
+	// PluginSettings holds a settings block per compliance plugin, keyed by plugin name.
+	PluginSettings map[string]map[string]any `mapstructure:"plugin_settings"`
+}
+
+// ScoringConfig holds overrides for the security score control catalogue.
+type ScoringConfig struct {
+	Controls []ScoringControl `mapstructure:"controls"`
+}
+
+// ScoringControl overrides the weight or enablement of a single scoring control.
+// Nil fields keep the catalogue defaults.
+type ScoringControl struct {
+	ID      string `mapstructure:"id"`
+	Weight  *int   `mapstructure:"weight"`
+	Enabled *bool  `mapstructure:"enabled"`
 }
 
 // LoadConfig loads application configuration from the specified YAML file, environment variables, and defaults.
@@ -71,6 +93,10 @@ func LoadConfigWithViper(cfgFile string, v *viper.Viper) (*Config, error) {
 	v.SetDefault("wrap", 0)
 	v.SetDefault("engine", "programmatic") // Default to programmatic mode
 	v.SetDefault("use_template", false)
+	v.SetDefault("sysctl_baseline", "")
+	v.SetDefault("control_mapping", "")
+	v.SetDefault("plugin_dir", "")
+	v.SetDefault("waiver_file", "")
 
 	// Set up environment variable handling
 	v.SetEnvPrefix("OPNDOSSIER")
@@ -148,6 +174,11 @@ func (c *Config) Validate() error {
 	validateFormat(c, &validationErrors)
 	validateWrapWidth(c, &validationErrors)
 	validateEngine(c, &validationErrors)
+	validateScoring(c, &validationErrors)
+	validateSysctlBaseline(c, &validationErrors)
+	validateControlMapping(c, &validationErrors)
+	validatePluginDir(c, &validationErrors)
+	validateWaiverFile(c, &validationErrors)
 
 	// Return combined validation errors
 	if len(validationErrors) > 0 {
@@ -180,6 +211,61 @@ func validateInputFile(c *Config, validationErrors *[]ValidationError) {
 	}
 }
 
+func validateSysctlBaseline(c *Config, validationErrors *[]ValidationError) {
+	// Validate custom sysctl baseline exists if specified; its content is validated when loaded
+	if c.SysctlBaseline != "" {
+		if _, err := os.Stat(c.SysctlBaseline); err != nil {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   "sysctl_baseline",
+				Message: fmt.Sprintf("sysctl baseline file is not accessible: %v", err),
+			})
+		}
+	}
+}
+
+func validateControlMapping(c *Config, validationErrors *[]ValidationError) {
+	// Validate custom control mapping exists if specified; its content is validated when loaded
+	if c.ControlMapping != "" {
+		if _, err := os.Stat(c.ControlMapping); err != nil {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   "control_mapping",
+				Message: fmt.Sprintf("control mapping file is not accessible: %v", err),
+			})
+		}
+	}
+}
+
+func validatePluginDir(c *Config, validationErrors *[]ValidationError) {
+	// Validate external plugin directory exists if specified; the plugins are validated when loaded
+	if c.PluginDir != "" {
+		info, err := os.Stat(c.PluginDir)
+		switch {
+		case err != nil:
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   "plugin_dir",
+				Message: fmt.Sprintf("plugin directory is not accessible: %v", err),
+			})
+		case !info.IsDir():
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   "plugin_dir",
+				Message: "plugin directory is not a directory: " + c.PluginDir,
+			})
+		}
+	}
+}
+
+func validateWaiverFile(c *Config, validationErrors *[]ValidationError) {
+	// Validate waiver file exists if specified; its content is validated when loaded
+	if c.WaiverFile != "" {
+		if _, err := os.Stat(c.WaiverFile); err != nil {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   "waiver_file",
+				Message: fmt.Sprintf("waiver file is not accessible: %v", err),
+			})
+		}
+	}
+}
+
 func validateOutputFile(c *Config, validationErrors *[]ValidationError) {
 	// Validate output file directory exists if specified
 	if c.OutputFile != "" {
@@ -224,16 +310,27 @@ func validateTheme(c *Config, validationErrors *[]ValidationError) {
 func validateFormat(c *Config, validationErrors *[]ValidationError) {
 	// Validate format
 	validFormats := map[string]bool{
-		"markdown": true,
-		"md":       true,
-		"json":     true,
-		"yaml":     true,
-		"yml":      true,
+		"markdown":  true,
+		"md":        true,
+		"json":      true,
+		"yaml":      true,
+		"yml":       true,
+		"html":      true,
+		"sarif":     true,
+		"junit":     true,
+		"csv":       true,
+		"xlsx":      true,
+		"dot":       true,
+		"site":      true,
+		"nftables":  true,
+		"iptables":  true,
+		"terraform": true,
+		"ansible":   true,
 	}
 	if c.Format != "" && !validFormats[c.Format] {
 		*validationErrors = append(*validationErrors, ValidationError{
 			Field:   "format",
-			Message: fmt.Sprintf("invalid format '%s', must be one of: markdown, md, json, yaml, yml", c.Format),
+			Message: fmt.Sprintf("invalid format '%s', must be one of: markdown, md, json, yaml, yml, html, sarif, junit, csv, xlsx, dot, site, nftables, iptables, terraform, ansible", c.Format),
 		})
 	}
 }
@@ -267,6 +364,41 @@ func validateEngine(c *Config, validationErrors *[]ValidationError) {
 	}
 }
 
+func validateScoring(c *Config, validationErrors *[]ValidationError) {
+	// Validate scoring control overrides; unknown IDs are rejected when the engine is built
+	seen := make(map[string]bool)
+
+	for i, control := range c.Scoring.Controls {
+		field := fmt.Sprintf("scoring.controls[%d]", i)
+		id := strings.ToUpper(strings.TrimSpace(control.ID))
+
+		if id == "" {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   field + ".id",
+				Message: "control id is required",
+			})
+
+			continue
+		}
+
+		if seen[id] {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   field + ".id",
+				Message: "duplicate override for control " + id,
+			})
+		}
+
+		seen[id] = true
+
+		if control.Weight != nil && *control.Weight < 0 {
+			*validationErrors = append(*validationErrors, ValidationError{
+				Field:   field + ".weight",
+				Message: fmt.Sprintf("weight cannot be negative: %d", *control.Weight),
+			})
+		}
+	}
+}
+
 func combineValidationErrors(validationErrors []ValidationError) error {
 	var errMsg string
 
@@ -351,6 +483,36 @@ func (c *Config) GetEngine() string {
 	return c.Engine
 }
 
+// GetScoringControls returns the configured scoring control overrides.
+func (c *Config) GetScoringControls() []ScoringControl {
+	return c.Scoring.Controls
+}
+
+// GetControlMapping returns the path of the custom control mapping file, if any.
+func (c *Config) GetControlMapping() string {
+	return c.ControlMapping
+}
+
+// GetPluginDir returns the directory of external compliance plugins, if any.
+func (c *Config) GetPluginDir() string {
+	return c.PluginDir
+}
+
+// GetWaiverFile returns the path of the waiver file, if any.
+func (c *Config) GetWaiverFile() string {
+	return c.WaiverFile
+}
+
+// GetPluginSettings returns the settings blocks of the compliance plugins, keyed by plugin name.
+func (c *Config) GetPluginSettings() map[string]map[string]any {
+	return c.PluginSettings
+}
+
+// GetSysctlBaseline returns the path of the custom sysctl hardening baseline, if any.
+func (c *Config) GetSysctlBaseline() string {
+	return c.SysctlBaseline
+}
+
 // IsUseTemplate returns true if template mode is explicitly enabled.
 func (c *Config) IsUseTemplate() bool {
 	return c.UseTemplate
@@ -350,6 +350,61 @@ func TestFormatValidationIndirectly(t *testing.T) {
 			format:      "yaml",
 			expectError: false,
 		},
+		{
+			name:        "html format",
+			format:      "html",
+			expectError: false,
+		},
+		{
+			name:        "sarif format",
+			format:      "sarif",
+			expectError: false,
+		},
+		{
+			name:        "junit format",
+			format:      "junit",
+			expectError: false,
+		},
+		{
+			name:        "csv format",
+			format:      "csv",
+			expectError: false,
+		},
+		{
+			name:        "xlsx format",
+			format:      "xlsx",
+			expectError: false,
+		},
+		{
+			name:        "dot format",
+			format:      "dot",
+			expectError: false,
+		},
+		{
+			name:        "site format",
+			format:      "site",
+			expectError: false,
+		},
+		{
+			name:        "nftables format",
+			format:      "nftables",
+			expectError: false,
+		},
+		{
+			name:        "iptables format",
+			format:      "iptables",
+			expectError: false,
+		},
+		{
+			name:        "terraform format",
+			format:      "terraform",
+			expectError: false,
+		},
+		{
+			name:        "ansible format",
+			format:      "ansible",
+			expectError: false,
+		},
 		{
 			name:        "invalid format",
 			format:      "invalid",
@@ -159,6 +159,164 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestConfig_ValidateScoring(t *testing.T) {
+	weight := 10
+	negative := -1
+
+	tests := []struct {
+		name     string
+		controls []ScoringControl
+		errMsg   string
+	}{
+		{
+			name:     "valid_overrides",
+			controls: []ScoringControl{{ID: "SCORE-MGMT-001", Weight: &weight}, {ID: "SCORE-LOG-001"}},
+		},
+		{
+			name:     "missing_id",
+			controls: []ScoringControl{{Weight: &weight}},
+			errMsg:   "control id is required",
+		},
+		{
+			name:     "duplicate_id",
+			controls: []ScoringControl{{ID: "SCORE-FW-001"}, {ID: "score-fw-001"}},
+			errMsg:   "duplicate override for control SCORE-FW-001",
+		},
+		{
+			name:     "negative_weight",
+			controls: []ScoringControl{{ID: "SCORE-FW-001", Weight: &negative}},
+			errMsg:   "weight cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Scoring: ScoringConfig{Controls: tt.controls}}
+			err := cfg.Validate()
+
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestConfig_ValidateSysctlBaseline(t *testing.T) {
+	cfg := Config{SysctlBaseline: filepath.Join(t.TempDir(), "missing.yaml")}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sysctl baseline file is not accessible")
+
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("tunables: []\n"), 0o600))
+
+	cfg.SysctlBaseline = path
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, path, cfg.GetSysctlBaseline())
+}
+
+func TestConfig_ValidateControlMapping(t *testing.T) {
+	cfg := Config{ControlMapping: filepath.Join(t.TempDir(), "missing.yaml")}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "control mapping file is not accessible")
+
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("controls: []\n"), 0o600))
+
+	cfg.ControlMapping = path
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, path, cfg.GetControlMapping())
+}
+
+func TestConfig_ValidateWaiverFile(t *testing.T) {
+	cfg := Config{WaiverFile: filepath.Join(t.TempDir(), "missing.yaml")}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiver file is not accessible")
+
+	path := filepath.Join(t.TempDir(), "waivers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("waivers: []\n"), 0o600))
+
+	cfg.WaiverFile = path
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, path, cfg.GetWaiverFile())
+}
+
+func TestConfig_ValidatePluginDir(t *testing.T) {
+	cfg := Config{PluginDir: filepath.Join(t.TempDir(), "missing")}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin directory is not accessible")
+
+	file := filepath.Join(t.TempDir(), "plugin")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	cfg.PluginDir = file
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin directory is not a directory")
+
+	cfg.PluginDir = t.TempDir()
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, cfg.PluginDir, cfg.GetPluginDir())
+}
+
+func TestLoadConfigWithScoringOverrides(t *testing.T) {
+	clearEnvironment(t)
+
+	cfgFilePath := filepath.Join(t.TempDir(), ".opnDossier.yaml")
+	content := `
+scoring:
+  controls:
+    - id: SCORE-MGMT-001
+      weight: 20
+    - id: SCORE-LOG-001
+      enabled: false
+`
+	require.NoError(t, os.WriteFile(cfgFilePath, []byte(content), 0o600))
+
+	cfg, err := LoadConfigWithViper(cfgFilePath, viper.New())
+	require.NoError(t, err)
+
+	controls := cfg.GetScoringControls()
+	require.Len(t, controls, 2)
+	assert.Equal(t, "SCORE-MGMT-001", controls[0].ID)
+	require.NotNil(t, controls[0].Weight)
+	assert.Equal(t, 20, *controls[0].Weight)
+	assert.Nil(t, controls[0].Enabled)
+	require.NotNil(t, controls[1].Enabled)
+	assert.False(t, *controls[1].Enabled)
+}
+
+func TestLoadConfigWithPluginSettings(t *testing.T) {
+	clearEnvironment(t)
+
+	cfgFilePath := filepath.Join(t.TempDir(), ".opnDossier.yaml")
+	content := `
+plugin_settings:
+  stig:
+    max_dhcp_interfaces: 4
+  sans:
+    untrusted_zones: [wan, guest]
+    require_rule_logging: false
+`
+	require.NoError(t, os.WriteFile(cfgFilePath, []byte(content), 0o600))
+
+	cfg, err := LoadConfigWithViper(cfgFilePath, viper.New())
+	require.NoError(t, err)
+
+	settings := cfg.GetPluginSettings()
+	require.Len(t, settings, 2)
+	assert.Equal(t, 4, settings["stig"]["max_dhcp_interfaces"])
+	assert.Equal(t, []any{"wan", "guest"}, settings["sans"]["untrusted_zones"])
+	assert.Equal(t, false, settings["sans"]["require_rule_logging"])
+}
+
 func TestConfig_HelperMethods(t *testing.T) {
 	cfg := &Config{
 		Verbose: true,
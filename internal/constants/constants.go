@@ -6,6 +6,11 @@ import "time"
 // Version information.
 var Version = "1.0.0"
 
+// SchemaVersion is the version of the JSON Schema of the JSON outputs, which every JSON output
+// carries as its schemaVersion property. The major version changes when a property is removed,
+// renamed or retyped, the minor version when a property is added.
+const SchemaVersion = "2.1.0"
+
 // Application constants.
 const (
 	// Application metadata.
@@ -50,9 +55,8 @@ const (
 	QuickProcessingTimeout   = 10 * time.Second
 
 	// Scoring constants.
-	SecurityFeatureMultiplier = 10
-	MaxSecurityScore          = 100
-	MaxComplexityScore        = 100
+	MaxSecurityScore   = 100
+	MaxComplexityScore = 100
 
 	// Complexity scoring weights.
 	InterfaceComplexityWeight    = 5
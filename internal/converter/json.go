@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 )
 
@@ -23,8 +24,13 @@ func (c *JSONConverter) ToJSON(_ context.Context, opnsense *model.OpnSenseDocume
 		return "", ErrNilOpnSenseDocument
 	}
 
-	// Marshal the OpnSenseDocument struct to JSON with indentation
-	jsonBytes, err := json.MarshalIndent(opnsense, "", "  ") //nolint:musttag // OpnSenseDocument has proper json tags
+	// Marshal the OpnSenseDocument struct to JSON with indentation, stamped with the schema version
+	versioned := struct {
+		SchemaVersion string `json:"schemaVersion"`
+		*model.OpnSenseDocument
+	}{constants.SchemaVersion, opnsense}
+
+	jsonBytes, err := json.MarshalIndent(versioned, "", "  ") //nolint:musttag // OpnSenseDocument has proper json tags
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
 	}
@@ -3,26 +3,33 @@ package converter
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/identity"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tabular"
+	"github.com/EvilBit-Labs/opnDossier/internal/topology"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/charmbracelet/glamour"
 	"github.com/nao1215/markdown"
 )
 
 // Constants for common values.
 const (
-	destinationAny = "any"
-	checkmark      = "✓"
-	xMark          = "✗"
+	checkmark = "✓"
+	xMark     = "✗"
 )
 
 // Converter is the interface for converting OPNsense configurations to markdown.
@@ -59,6 +66,8 @@ type MarkdownBuilder struct {
 	logger      *log.Logger
 	generated   time.Time
 	toolVersion string
+	scorer      *scoring.Engine
+	baseline    *tunables.Baseline
 }
 
 // Options contains basic configuration options for markdown generation.
@@ -133,6 +142,13 @@ var ErrUnsupportedFormat = errors.New("unsupported format. Supported formats: ma
 // The function returns inline markdown links (e.g., [wan](#wan-interface)), which the nao1215/markdown package
 // automatically converts to reference-style links when used in table cells.
 func formatInterfacesAsLinks(interfaces model.InterfaceList) string {
+	return FormatInterfaceLinks(interfaces, "")
+}
+
+// FormatInterfaceLinks formats a list of interfaces as markdown links to their interface sections on
+// the given page, such as [wan](../interfaces.md#wan-interface). An empty page links to the sections
+// of the current page.
+func FormatInterfaceLinks(interfaces model.InterfaceList, page string) string {
 	if interfaces.IsEmpty() {
 		return ""
 	}
@@ -143,7 +159,7 @@ func formatInterfacesAsLinks(interfaces model.InterfaceList) string {
 	links := make([]string, 0, len(interfaces))
 	for _, iface := range interfaces {
 		// Create anchor link to the interface section
-		anchor := "#" + strings.ToLower(iface) + "-interface"
+		anchor := page + "#" + strings.ToLower(iface) + "-interface"
 
 		// Use markdown.Link to create the hyperlink
 		links = append(links, markdown.Link(iface, anchor))
@@ -363,6 +379,12 @@ func (b *MarkdownBuilder) BuildSystemSection(data *model.OpnSenseDocument) strin
 		md.Table(*tableSet)
 	}
 
+	// Privilege matrix
+	if len(sysConfig.System.User) > 0 {
+		md.H3("User Privilege Matrix")
+		md.Table(identity.MatrixTable(identity.Analyze(data).Users))
+	}
+
 	return md.String()
 }
 
@@ -380,8 +402,15 @@ func (b *MarkdownBuilder) BuildNetworkSection(data *model.OpnSenseDocument) stri
 	tableSet := b.BuildInterfaceTable(netConfig.Interfaces)
 	md.Table(*tableSet)
 
+	// Topology diagram of interfaces, VLANs, gateways, routes and tunnels
+	if graph := topology.Build(data); !graph.Empty() {
+		md.H3("Network Topology")
+		md.CodeBlocks(markdown.SyntaxHighlightMermaid, graph.Mermaid())
+	}
+
 	// Individual interface details
-	for name, iface := range netConfig.Interfaces.Items {
+	for _, name := range slices.Sorted(maps.Keys(netConfig.Interfaces.Items)) {
+		iface := netConfig.Interfaces.Items[name]
 		sectionName := strings.ToUpper(name[:1]) + strings.ToLower(name[1:]) + " Interface"
 		md.H3(sectionName)
 		buildInterfaceDetails(md, iface)
@@ -473,11 +502,121 @@ func (b *MarkdownBuilder) BuildSecuritySection(data *model.OpnSenseDocument) str
 		md.H3("Firewall Rules")
 		tableSet := b.BuildFirewallRulesTable(rules)
 		md.Table(*tableSet)
+
+		if hasRuleHits(rules) {
+			b.buildRuleUsageSection(md, rules)
+		}
 	}
 
 	return md.String()
 }
 
+// buildRuleUsageSection summarizes filterlog attribution and lists the enabled rules without hits,
+// separating the rules that do not log, whose usage is unknown.
+func (b *MarkdownBuilder) buildRuleUsageSection(md *markdown.Markdown, rules []model.Rule) {
+	md.H3("Rule Usage")
+
+	var first, last time.Time
+
+	for _, rule := range rules {
+		if rule.Hits == nil {
+			continue
+		}
+
+		if !rule.Hits.FirstSeen.IsZero() && (first.IsZero() || rule.Hits.FirstSeen.Before(first)) {
+			first = rule.Hits.FirstSeen
+		}
+
+		if rule.Hits.LastSeen.After(last) {
+			last = rule.Hits.LastSeen
+		}
+	}
+
+	if !first.IsZero() {
+		md.PlainTextf("%s: %s - %s", markdown.Bold("Log Window"), formatHitTime(first), formatHitTime(last))
+	}
+
+	var unused, unlogged [][]string
+
+	for i, rule := range rules {
+		if rule.Disabled != "" || rule.Hits == nil || rule.Hits.Count > 0 {
+			continue
+		}
+
+		row := []string{
+			strconv.Itoa(i + 1),
+			formatInterfacesAsLinks(rule.Interface),
+			rule.Type,
+			b.EscapeTableContent(rule.Descr),
+		}
+
+		if rule.Hits.Unlogged || !rule.Log.Bool() {
+			unlogged = append(unlogged, row)
+		} else {
+			unused = append(unused, row)
+		}
+	}
+
+	header := []string{"#", "Interface", "Action", "Description"}
+
+	if len(unused) == 0 {
+		md.PlainText("All enabled, logged rules matched traffic during the log window.")
+	} else {
+		md.H4("Candidates for Removal")
+		md.PlainText("The following enabled rules matched no traffic during the log window:")
+		md.Table(markdown.TableSet{Header: header, Rows: unused})
+	}
+
+	if len(unlogged) > 0 {
+		md.H4("Not Logged")
+		md.PlainText("The following enabled rules do not log, so the log data cannot show whether they matched traffic:")
+		md.Table(markdown.TableSet{Header: header, Rows: unlogged})
+	}
+}
+
+// hasRuleHits reports whether any rule was enriched with filterlog statistics.
+func hasRuleHits(rules []model.Rule) bool {
+	for _, rule := range rules {
+		if rule.Hits != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatHitTime formats a filterlog timestamp for display, using "-" for unknown times.
+func formatHitTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return tabular.FormatTime(t)
+}
+
+// formatRuleHits returns the hit count, first seen, last seen and top talker cells for a rule.
+func formatRuleHits(hits *model.RuleHits) []string {
+	if hits == nil {
+		return []string{"-", "-", "-", "-"}
+	}
+
+	return []string{
+		strconv.Itoa(hits.Count),
+		formatHitTime(hits.FirstSeen),
+		formatHitTime(hits.LastSeen),
+		formatTopTalkers(hits.TopTalkers),
+	}
+}
+
+// formatTopTalkers renders top talkers as a comma-separated "address (count)" list.
+func formatTopTalkers(talkers []model.Talker) string {
+	if len(talkers) == 0 {
+		return "-"
+	}
+
+	return tabular.FormatTalkers(talkers)
+}
+
 // BuildServicesSection builds the service configuration section.
 func (b *MarkdownBuilder) BuildServicesSection(data *model.OpnSenseDocument) string {
 	var buf bytes.Buffer
@@ -554,6 +693,12 @@ func (b *MarkdownBuilder) BuildServicesSection(data *model.OpnSenseDocument) str
 
 // BuildFirewallRulesTable builds a table of firewall rules.
 func (b *MarkdownBuilder) BuildFirewallRulesTable(rules []model.Rule) *markdown.TableSet {
+	return b.BuildLinkedFirewallRulesTable(rules, "")
+}
+
+// BuildLinkedFirewallRulesTable builds a table of firewall rules whose interfaces link to the
+// interface sections of the given page, for reports that are split into several files.
+func (b *MarkdownBuilder) BuildLinkedFirewallRulesTable(rules []model.Rule, interfacePage string) *markdown.TableSet {
 	headers := []string{
 		"#",
 		"Interface",
@@ -568,33 +713,34 @@ func (b *MarkdownBuilder) BuildFirewallRulesTable(rules []model.Rule) *markdown.
 		"Description",
 	}
 
+	withHits := hasRuleHits(rules)
+	if withHits {
+		headers = append(headers, "Hits", "First Seen", "Last Seen", "Top Talkers")
+	}
+
 	rows := make([][]string, 0, len(rules))
 	for i, rule := range rules {
-		source := rule.Source.Network
-		if source == "" {
-			source = destinationAny
-		}
-
-		dest := rule.Destination.Network
-		if dest == "" {
-			dest = destinationAny
-		}
-
-		interfaceLinks := formatInterfacesAsLinks(rule.Interface)
+		interfaceLinks := FormatInterfaceLinks(rule.Interface, interfacePage)
 
-		rows = append(rows, []string{
+		row := []string{
 			strconv.Itoa(i + 1),
 			interfaceLinks,
 			rule.Type,
 			rule.IPProtocol,
 			rule.Protocol,
-			source,
-			dest,
+			rule.Source.String(),
+			rule.Destination.String(),
 			rule.Target,
-			rule.SourcePort,
+			cmp.Or(rule.Source.Port, rule.SourcePort),
 			formatBooleanInverted(rule.Disabled),
 			b.EscapeTableContent(rule.Descr),
-		})
+		}
+
+		if withHits {
+			row = append(row, formatRuleHits(rule.Hits)...)
+		}
+
+		rows = append(rows, row)
 	}
 
 	return &markdown.TableSet{
@@ -608,7 +754,8 @@ func (b *MarkdownBuilder) BuildInterfaceTable(interfaces model.Interfaces) *mark
 	headers := []string{"Name", "Description", "IP Address", "CIDR", "Enabled"}
 
 	rows := make([][]string, 0, len(interfaces.Items))
-	for name, iface := range interfaces.Items {
+	for _, name := range slices.Sorted(maps.Keys(interfaces.Items)) {
+		iface := interfaces.Items[name]
 		description := iface.Descr
 		if description == "" {
 			description = iface.If
@@ -728,6 +875,8 @@ func (b *MarkdownBuilder) BuildStandardReport(data *model.OpnSenseDocument) (str
 	md.PlainText(b.BuildSystemSection(data))
 	md.PlainText(b.BuildNetworkSection(data))
 	md.PlainText(b.BuildSecuritySection(data))
+	md.PlainText(b.BuildSecurityScoreSection(data))
+	md.PlainText(b.BuildTunableBaselineSection(data))
 	md.PlainText(b.BuildServicesSection(data))
 
 	// Add system users and tunables sections
@@ -785,6 +934,8 @@ func (b *MarkdownBuilder) BuildComprehensiveReport(data *model.OpnSenseDocument)
 	md.PlainText(b.BuildSystemSection(data))
 	md.PlainText(b.BuildNetworkSection(data))
 	md.PlainText(b.BuildSecuritySection(data))
+	md.PlainText(b.BuildSecurityScoreSection(data))
+	md.PlainText(b.BuildTunableBaselineSection(data))
 	md.PlainText(b.BuildServicesSection(data))
 
 	return md.String(), nil
@@ -999,19 +1150,6 @@ func (c *MarkdownConverter) buildSecuritySection(md *markdown.Markdown, opnsense
 
 		rows := make([][]string, 0, len(rules))
 		for _, rule := range rules {
-			source := rule.Source.Network
-			if source == "" {
-				source = destinationAny
-			}
-
-			// Check destination - can be either Network or Any
-			dest := rule.Destination.Network
-			if dest == "" {
-				// If no network is specified, check if it's any destination
-				// Since Any is a struct{}, we'll assume it's "any" if Network is empty
-				dest = destinationAny
-			}
-
 			// Format interfaces as hyperlinks instead of plain text
 			interfaceLinks := formatInterfacesAsLinks(rule.Interface)
 
@@ -1020,8 +1158,8 @@ func (c *MarkdownConverter) buildSecuritySection(md *markdown.Markdown, opnsense
 				interfaceLinks,
 				rule.IPProtocol,
 				rule.Protocol,
-				source,
-				dest,
+				rule.Source.String(),
+				rule.Destination.String(),
 				rule.Descr,
 			})
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -40,6 +41,7 @@ func TestMarkdownBuilder_BuildSystemSection(t *testing.T) {
 	assert.Contains(t, result, "System Tunables")
 	assert.Contains(t, result, "System Users")
 	assert.Contains(t, result, "System Groups")
+	assert.Contains(t, result, "User Privilege Matrix")
 
 	// Verify specific values
 	assert.Contains(t, result, "test-host")
@@ -103,6 +105,11 @@ func TestMarkdownBuilder_BuildNetworkSection(t *testing.T) {
 	assert.Contains(t, result, "10.0.0.1")
 	assert.Contains(t, result, "WAN Interface")
 	assert.Contains(t, result, "LAN Interface")
+
+	// Verify the topology diagram
+	assert.Contains(t, result, "### Network Topology")
+	assert.Contains(t, result, "```mermaid\nflowchart LR\n")
+	assert.Contains(t, result, `if_wan["WAN Interface (wan)<br/>WAN<br/>em0<br/>192.168.1.1/24"]`)
 }
 
 func TestMarkdownBuilder_BuildSecuritySection(t *testing.T) {
@@ -293,6 +300,78 @@ func TestMarkdownBuilder_BuildFirewallRulesTable(t *testing.T) {
 	assert.Equal(t, "Allow LAN to WAN", row[10]) // Description
 }
 
+func TestMarkdownBuilder_BuildFirewallRulesTableHostEndpoints(t *testing.T) {
+	builder := NewMarkdownBuilder()
+
+	rules := []model.Rule{
+		{
+			Type:        "pass",
+			Interface:   model.InterfaceList{"lan"},
+			Source:      model.Source{Address: "10.0.0.5", Port: "1024"},
+			Destination: model.Destination{Address: "10.0.1.5", Not: true},
+		},
+		{Type: "block", Interface: model.InterfaceList{"lan"}, Source: model.Source{Any: "1"}},
+	}
+
+	tableSet := builder.BuildFirewallRulesTable(rules)
+	require.Len(t, tableSet.Rows, 2)
+	assert.Equal(t, []string{"10.0.0.5", "!10.0.1.5"}, tableSet.Rows[0][5:7])
+	assert.Equal(t, "1024", tableSet.Rows[0][8])
+	assert.Equal(t, []string{"any", "any"}, tableSet.Rows[1][5:7])
+}
+
+func TestMarkdownBuilder_BuildFirewallRulesTableWithHits(t *testing.T) {
+	builder := NewMarkdownBuilder()
+
+	seen := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	rules := []model.Rule{
+		{
+			Type:      "pass",
+			Descr:     "Allow web",
+			Interface: model.InterfaceList{"lan"},
+			Hits: &model.RuleHits{
+				Count:      3,
+				FirstSeen:  seen,
+				LastSeen:   seen.Add(time.Hour),
+				TopTalkers: []model.Talker{{Address: "10.0.0.5", Count: 2}, {Address: "10.0.0.6", Count: 1}},
+			},
+		},
+		{
+			Type:      "pass",
+			Descr:     "Legacy access",
+			Interface: model.InterfaceList{"lan"},
+			Log:       true,
+			Hits:      &model.RuleHits{},
+		},
+		{
+			Type:      "pass",
+			Descr:     "Quiet rule",
+			Interface: model.InterfaceList{"lan"},
+			Hits:      &model.RuleHits{Unlogged: true},
+		},
+	}
+
+	tableSet := builder.BuildFirewallRulesTable(rules)
+	require.Len(t, tableSet.Header, 15)
+	assert.Equal(t, []string{"Hits", "First Seen", "Last Seen", "Top Talkers"}, tableSet.Header[11:])
+	assert.Equal(t, []string{"3", "2024-03-01T10:00:00Z", "2024-03-01T11:00:00Z", "10.0.0.5 (2), 10.0.0.6 (1)"}, tableSet.Rows[0][11:])
+	assert.Equal(t, []string{"0", "-", "-", "-"}, tableSet.Rows[1][11:])
+
+	section := builder.BuildSecuritySection(&model.OpnSenseDocument{Filter: model.Filter{Rule: rules}})
+	assert.Contains(t, section, "### Rule Usage")
+	assert.Contains(t, section, "Candidates for Removal")
+	assert.Contains(t, section, "Legacy access")
+
+	_, usage, found := strings.Cut(section, "### Rule Usage")
+	require.True(t, found)
+
+	candidates, unlogged, found := strings.Cut(usage, "#### Not Logged")
+	require.True(t, found)
+	assert.NotContains(t, candidates, "Quiet rule")
+	assert.Contains(t, unlogged, "Quiet rule")
+	assert.NotContains(t, unlogged, "Legacy access")
+}
+
 func TestMarkdownBuilder_BuildInterfaceTable(t *testing.T) {
 	builder := NewMarkdownBuilder()
 
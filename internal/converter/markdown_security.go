@@ -1,19 +1,13 @@
 package converter
 
 import (
+	"bytes"
 	"strings"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
-)
-
-const (
-	// Security scoring constants.
-	maxSecurityScore       = 100
-	initialSecurityScore   = 100
-	firewallMissingPenalty = 20
-	managementOnWANPenalty = 30
-	insecureTunablePenalty = 5
-	defaultUserPenalty     = 15
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
+	"github.com/nao1215/markdown"
 )
 
 // AssessRiskLevel returns a consistent emoji + text representation.
@@ -34,57 +28,85 @@ func (b *MarkdownBuilder) AssessRiskLevel(severity string) string {
 	}
 }
 
-// CalculateSecurityScore computes an overall score (0-100).
-// Implementation notes:
-//   - Prefer reusing a single source of truth for scoring. Until model exports a public function,
-//     provide a small, transparent wrapper here with conservative checks and clamps.
-//   - This wrapper is intentionally minimal and documented below; follow-up will centralize logic.
+// CalculateSecurityScore computes an overall score (0-100) using the builder's scoring engine.
+// See BuildSecurityScoreSection for the per-control breakdown behind the number.
 func (b *MarkdownBuilder) CalculateSecurityScore(data *model.OpnSenseDocument) int {
 	if data == nil {
 		return 0
 	}
 
-	score := initialSecurityScore
+	return b.scoringEngine().Score(data)
+}
 
-	// Heuristic checks consistent with our reporting goals
+// BuildSecurityScoreSection renders the security score together with the table of
+// controls that earned or lost points.
+func (b *MarkdownBuilder) BuildSecurityScoreSection(data *model.OpnSenseDocument) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
 
-	// 1) Basic firewall presence
-	if len(data.Filter.Rule) == 0 {
-		score -= firewallMissingPenalty
-	}
+	breakdown := b.scoringEngine().Evaluate(data)
 
-	// 2) Management exposure on WAN
-	if b.hasManagementOnWAN(data) {
-		score -= managementOnWANPenalty
-	}
+	md.H3("Security Score")
+	md.PlainTextf("%s: %s", markdown.Bold("Score"), scoring.Summary(breakdown))
+	md.Table(scoring.BreakdownTable(breakdown))
 
-	// 3) Security-relevant sysctl tunables
-	securityTunables := map[string]string{
-		"net.inet.ip.forwarding":   "0",
-		"net.inet6.ip6.forwarding": "0",
-		"net.inet.tcp.blackhole":   "2",
-		"net.inet.udp.blackhole":   "1",
-	}
-	for tunable, expected := range securityTunables {
-		if !b.checkTunable(data.Sysctl, tunable, expected) {
-			score -= insecureTunablePenalty
-		}
-	}
+	return md.String()
+}
 
-	// 4) Default users
-	for _, user := range data.System.User {
-		if b.isDefaultUser(user) {
-			score -= defaultUserPenalty
-		}
+// SetScoringEngine sets the engine used to compute security scores. The engine's analyzer
+// supplies the findings the score is derived from.
+func (b *MarkdownBuilder) SetScoringEngine(engine *scoring.Engine) {
+	b.scorer = engine
+}
+
+// scoringEngine returns the configured scoring engine or the default catalogue.
+func (b *MarkdownBuilder) scoringEngine() *scoring.Engine {
+	if b.scorer == nil {
+		return scoring.Default()
 	}
 
-	if score < 0 {
-		score = 0
+	return b.scorer
+}
+
+// BuildTunableBaselineSection renders the comparison of the configured sysctl tunables
+// with the hardening baseline.
+func (b *MarkdownBuilder) BuildTunableBaselineSection(data *model.OpnSenseDocument) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	var items []model.SysctlItem
+	if data != nil {
+		items = data.Sysctl
 	}
-	if score > maxSecurityScore {
-		score = maxSecurityScore
+
+	baseline := b.tunableBaseline()
+	checks := tunables.Compare(baseline, items)
+
+	md.H3("Sysctl Hardening Baseline")
+	md.PlainTextf(
+		"%s: %s (%d of %d tunables deviate)",
+		markdown.Bold("Baseline"),
+		baseline.Name,
+		len(tunables.Deviations(checks)),
+		len(checks),
+	)
+	md.Table(tunables.ComplianceTable(checks))
+
+	return md.String()
+}
+
+// SetTunableBaseline sets the sysctl hardening baseline used for the tunable comparison.
+func (b *MarkdownBuilder) SetTunableBaseline(baseline *tunables.Baseline) {
+	b.baseline = baseline
+}
+
+// tunableBaseline returns the configured baseline or the embedded default.
+func (b *MarkdownBuilder) tunableBaseline() *tunables.Baseline {
+	if b.baseline == nil {
+		return tunables.Default()
 	}
-	return score
+
+	return b.baseline
 }
 
 // AssessServiceRisk maps common services to risk levels.
@@ -106,46 +128,3 @@ func (b *MarkdownBuilder) AssessServiceRisk(service model.Service) string {
 	}
 	return b.AssessRiskLevel("info")
 }
-
-// hasManagementOnWAN flags if WAN rules expose common management ports.
-// Notes:
-// - InterfaceList captures logical names; we check for "wan" in rule.Interface.
-// - Direction is considered if set ("in"); many configs omit it, so we don't require it.
-// - Destination.Port may be ranges/aliases; simple substring match as a safe heuristic.
-func (b *MarkdownBuilder) hasManagementOnWAN(data *model.OpnSenseDocument) bool {
-	mgmtPorts := []string{"443", "80", "22", "8080"}
-
-	for _, rule := range data.Filter.Rule {
-		if !rule.Interface.Contains("wan") {
-			continue
-		}
-		// Optional direction check
-		if rule.Direction != "" && !strings.EqualFold(rule.Direction, "in") {
-			continue
-		}
-		for _, p := range mgmtPorts {
-			if strings.Contains(rule.Destination.Port, p) {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func (b *MarkdownBuilder) checkTunable(tunables []model.SysctlItem, name, expected string) bool {
-	for _, t := range tunables {
-		if t.Tunable == name {
-			return t.Value == expected
-		}
-	}
-	return false
-}
-
-func (b *MarkdownBuilder) isDefaultUser(u model.User) bool {
-	switch strings.ToLower(u.Name) {
-	case "admin", "root", "user":
-		return true
-	default:
-		return false
-	}
-}
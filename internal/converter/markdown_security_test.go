@@ -1,10 +1,14 @@
 package converter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMarkdownBuilder_AssessRiskLevel(t *testing.T) {
@@ -121,304 +125,117 @@ func TestMarkdownBuilder_AssessServiceRisk(t *testing.T) {
 	}
 }
 
+// findingsAnalyzer returns a scoring analyzer reporting the given findings for every configuration.
+func findingsAnalyzer(findings ...scoring.Finding) scoring.Analyzer {
+	return func(*model.OpnSenseDocument) []scoring.Finding {
+		return findings
+	}
+}
+
 func TestMarkdownBuilder_CalculateSecurityScore(t *testing.T) {
-	b := NewMarkdownBuilder()
+	cfg := &model.OpnSenseDocument{
+		Filter: model.Filter{
+			Rule: []model.Rule{{Type: "block"}},
+		},
+	}
 
 	t.Run("nil configuration", func(t *testing.T) {
-		score := b.CalculateSecurityScore(nil)
-		assert.Equal(t, 0, score)
-	})
-
-	t.Run("good baseline configuration", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{{Type: "block"}}, // at least one rule
-			},
-			Sysctl: []model.SysctlItem{
-				{Tunable: "net.inet.ip.forwarding", Value: "0"},
-				{Tunable: "net.inet6.ip6.forwarding", Value: "0"},
-				{Tunable: "net.inet.tcp.blackhole", Value: "2"},
-				{Tunable: "net.inet.udp.blackhole", Value: "1"},
-			},
-			System: model.System{
-				User: []model.User{
-					{Name: "john"}, // non-default user
-				},
-			},
-		}
-		score := b.CalculateSecurityScore(cfg)
-		assert.GreaterOrEqual(t, score, 80)
-		assert.LessOrEqual(t, score, 100)
-	})
-
-	t.Run("poor configuration with management on WAN", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{ // Management on WAN
-						Type:      "pass",
-						Interface: model.InterfaceList{"wan"},
-						Destination: model.Destination{
-							Port: "22",
-						},
-					},
-				},
-			},
-			System: model.System{
-				User: []model.User{
-					{Name: "admin"}, // default user
-				},
-			},
-		}
-		score := b.CalculateSecurityScore(cfg)
-		assert.Less(t, score, 80)
+		assert.Equal(t, 0, NewMarkdownBuilder().CalculateSecurityScore(nil))
 	})
 
-	t.Run("no firewall rules", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{}, // no rules
-			},
-			System: model.System{
-				User: []model.User{
-					{Name: "normaluser"},
-				},
-			},
-		}
-		score := b.CalculateSecurityScore(cfg)
-		assert.LessOrEqual(t, score, 80) // Should lose points for no firewall rules
+	t.Run("no analyzer", func(t *testing.T) {
+		assert.Equal(t, 0, NewMarkdownBuilder().CalculateSecurityScore(cfg))
 	})
 
-	t.Run("bad sysctl settings", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{{Type: "block"}},
-			},
-			Sysctl: []model.SysctlItem{
-				{Tunable: "net.inet.ip.forwarding", Value: "1"}, // Bad: forwarding enabled
-				{Tunable: "net.inet.tcp.blackhole", Value: "0"}, // Bad: blackhole disabled
-			},
-			System: model.System{
-				User: []model.User{
-					{Name: "normaluser"},
-				},
-			},
-		}
-		score := b.CalculateSecurityScore(cfg)
-		assert.Less(t, score, 100) // Should lose points for bad sysctl settings
-	})
+	t.Run("no findings", func(t *testing.T) {
+		engine, err := scoring.NewEngine(scoring.WithAnalyzer(findingsAnalyzer()))
+		require.NoError(t, err)
 
-	t.Run("score bounds", func(t *testing.T) {
-		// Test extreme case that would result in negative score
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{ // Management on WAN
-						Type:      "pass",
-						Interface: model.InterfaceList{"wan"},
-						Destination: model.Destination{
-							Port: "22",
-						},
-					},
-				},
-			},
-			System: model.System{
-				User: []model.User{
-					{Name: "admin"}, // -15
-					{Name: "root"},  // -15
-					{Name: "user"},  // -15
-				},
-			},
-		}
-		score := b.CalculateSecurityScore(cfg)
-		assert.GreaterOrEqual(t, score, 0, "Score should not go below 0")
-		assert.LessOrEqual(t, score, 100, "Score should not exceed 100")
+		b := NewMarkdownBuilder()
+		b.SetScoringEngine(engine)
+		assert.Equal(t, 100, b.CalculateSecurityScore(cfg))
 	})
-}
-
-func TestMarkdownBuilder_hasManagementOnWAN(t *testing.T) {
-	b := NewMarkdownBuilder()
 
-	t.Run("no WAN rules", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"lan"},
-						Destination: model.Destination{Port: "22"},
-					},
-				},
-			},
-		}
-		assert.False(t, b.hasManagementOnWAN(cfg))
-	})
+	t.Run("management findings", func(t *testing.T) {
+		engine, err := scoring.NewEngine(scoring.WithAnalyzer(findingsAnalyzer(
+			scoring.Finding{Reference: scoring.CheckInsecureWebGUI},
+			scoring.Finding{Reference: scoring.CheckWANManagement},
+			scoring.Finding{Reference: scoring.CheckWANManagement},
+		)))
+		require.NoError(t, err)
 
-	t.Run("WAN rule with management port", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Destination: model.Destination{Port: "22"},
-					},
-				},
-			},
-		}
-		assert.True(t, b.hasManagementOnWAN(cfg))
-	})
+		b := NewMarkdownBuilder()
+		b.SetScoringEngine(engine)
 
-	t.Run("WAN rule with multiple management ports", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Destination: model.Destination{Port: "443"},
-					},
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Destination: model.Destination{Port: "8080"},
-					},
-				},
-			},
-		}
-		assert.True(t, b.hasManagementOnWAN(cfg))
+		// 30 of the 85 applicable points are lost; WAN and SNMP controls do not apply
+		assert.Equal(t, 65, b.CalculateSecurityScore(cfg))
 	})
+}
 
-	t.Run("WAN rule with non-management port", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Destination: model.Destination{Port: "53"},
-					},
-				},
-			},
-		}
-		assert.False(t, b.hasManagementOnWAN(cfg))
-	})
+func TestMarkdownBuilder_BuildSecurityScoreSection(t *testing.T) {
+	cfg := &model.OpnSenseDocument{
+		System: model.System{WebGUI: model.WebGUIConfig{Protocol: "http"}},
+	}
+	finding := scoring.Finding{
+		Reference: scoring.CheckInsecureWebGUI,
+		Title:     "Insecure Web GUI Protocol",
+		Component: "system.webgui.protocol",
+	}
 
-	t.Run("WAN rule with direction check", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Direction:   "out",
-						Destination: model.Destination{Port: "22"},
-					},
-				},
-			},
-		}
-		assert.False(t, b.hasManagementOnWAN(cfg), "Outbound rules should not trigger management on WAN warning")
-	})
+	engine, err := scoring.NewEngine(scoring.WithAnalyzer(findingsAnalyzer(finding)))
+	require.NoError(t, err)
 
-	t.Run("WAN rule with inbound direction", func(t *testing.T) {
-		cfg := &model.OpnSenseDocument{
-			Filter: model.Filter{
-				Rule: []model.Rule{
-					{
-						Interface:   model.InterfaceList{"wan"},
-						Direction:   "in",
-						Destination: model.Destination{Port: "22"},
-					},
-				},
-			},
-		}
-		assert.True(t, b.hasManagementOnWAN(cfg), "Inbound rules should trigger management on WAN warning")
-	})
+	b := NewMarkdownBuilder()
+	b.SetScoringEngine(engine)
+
+	section := b.BuildSecurityScoreSection(cfg)
+	assert.Contains(t, section, "### Security Score")
+	assert.Contains(t, section, "SCORE-MGMT-001")
+	assert.Contains(t, section, "Insecure Web GUI Protocol (system.webgui.protocol)")
+
+	weight := 0
+	reweighted, err := scoring.NewEngine(
+		scoring.WithOverrides(scoring.Override{ID: "SCORE-MGMT-001", Weight: &weight}),
+		scoring.WithAnalyzer(findingsAnalyzer(finding)),
+	)
+	require.NoError(t, err)
+
+	before := b.CalculateSecurityScore(cfg)
+	b.SetScoringEngine(reweighted)
+	assert.Greater(t, b.CalculateSecurityScore(cfg), before, "removing the weight of a failed control raises the score")
 }
 
-func TestMarkdownBuilder_checkTunable(t *testing.T) {
+func TestMarkdownBuilder_ReportsIncludeSecurityScore(t *testing.T) {
 	b := NewMarkdownBuilder()
+	cfg := &model.OpnSenseDocument{System: model.System{Hostname: "fw", Domain: "example.com"}}
 
-	tunables := []model.SysctlItem{
-		{Tunable: "net.inet.ip.forwarding", Value: "0"},
-		{Tunable: "net.inet.tcp.blackhole", Value: "2"},
-		{Tunable: "kern.securelevel", Value: "1"},
-	}
-
-	t.Run("existing tunable with correct value", func(t *testing.T) {
-		result := b.checkTunable(tunables, "net.inet.ip.forwarding", "0")
-		assert.True(t, result)
-	})
-
-	t.Run("existing tunable with incorrect value", func(t *testing.T) {
-		result := b.checkTunable(tunables, "net.inet.ip.forwarding", "1")
-		assert.False(t, result)
-	})
+	standard, err := b.BuildStandardReport(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(standard, "### Security Score"))
 
-	t.Run("non-existing tunable", func(t *testing.T) {
-		result := b.checkTunable(tunables, "net.inet.nonexistent", "0")
-		assert.False(t, result)
-	})
+	comprehensive, err := b.BuildComprehensiveReport(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(comprehensive, "### Security Score"))
+	assert.Equal(t, 1, strings.Count(comprehensive, "### Sysctl Hardening Baseline"))
 
-	t.Run("empty tunables list", func(t *testing.T) {
-		result := b.checkTunable([]model.SysctlItem{}, "net.inet.ip.forwarding", "0")
-		assert.False(t, result)
-	})
+	assert.NotContains(t, b.BuildSecuritySection(cfg), "### Security Score")
 }
 
-func TestMarkdownBuilder_isDefaultUser(t *testing.T) {
+func TestMarkdownBuilder_BuildTunableBaselineSection(t *testing.T) {
 	b := NewMarkdownBuilder()
+	cfg := &model.OpnSenseDocument{Sysctl: []model.SysctlItem{{Tunable: "net.inet.tcp.blackhole", Value: "2"}}}
 
-	tests := []struct {
-		name     string
-		user     model.User
-		expected bool
-	}{
-		{
-			name:     "admin user",
-			user:     model.User{Name: "admin"},
-			expected: true,
-		},
-		{
-			name:     "Admin user (capitalized)",
-			user:     model.User{Name: "Admin"},
-			expected: true,
-		},
-		{
-			name:     "root user",
-			user:     model.User{Name: "root"},
-			expected: true,
-		},
-		{
-			name:     "ROOT user (uppercase)",
-			user:     model.User{Name: "ROOT"},
-			expected: true,
-		},
-		{
-			name:     "user user",
-			user:     model.User{Name: "user"},
-			expected: true,
-		},
-		{
-			name:     "USER user (uppercase)",
-			user:     model.User{Name: "USER"},
-			expected: true,
-		},
-		{
-			name:     "custom user",
-			user:     model.User{Name: "john"},
-			expected: false,
-		},
-		{
-			name:     "empty user name",
-			user:     model.User{Name: ""},
-			expected: false,
-		},
-		{
-			name:     "partial match",
-			user:     model.User{Name: "administrator"},
-			expected: false,
-		},
-	}
+	section := b.BuildTunableBaselineSection(cfg)
+	assert.Contains(t, section, "### Sysctl Hardening Baseline")
+	assert.Contains(t, section, tunables.Default().Name)
+	assert.Contains(t, section, "security.bsd.see_other_uids")
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := b.isDefaultUser(tt.user)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
+	b.SetTunableBaseline(&tunables.Baseline{Name: "custom", Tunables: []tunables.Expectation{
+		{Tunable: "net.inet.tcp.blackhole", Expected: "1", Default: "0", Severity: "low"},
+	}})
+
+	section = b.BuildTunableBaselineSection(cfg)
+	assert.Contains(t, section, "custom (1 of 1 tunables deviate)")
+	assert.NotContains(t, section, "security.bsd.see_other_uids")
+	assert.NotEmpty(t, b.BuildTunableBaselineSection(nil))
 }
@@ -0,0 +1,254 @@
+// Package filterlog parses offline OPNsense filterlog exports and attributes
+// the logged packets to firewall rules in a configuration.
+//
+// OPNsense writes one CSV record per logged packet through the filterlog
+// daemon. Records are usually wrapped in a syslog envelope (RFC 5424 for the
+// local log files, RFC 3164 for most remote receivers). The parser accepts
+// both envelopes as well as bare CSV records, and transparently decompresses
+// gzip-compressed files such as rotated log archives.
+package filterlog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error definitions for filterlog parsing.
+var (
+	// ErrNotFilterlog indicates that a line does not contain a filterlog record.
+	ErrNotFilterlog = errors.New("line does not contain a filterlog record")
+	// ErrMalformedRecord indicates that a filterlog record has too few fields.
+	ErrMalformedRecord = errors.New("malformed filterlog record")
+)
+
+// Field positions of the common filterlog header.
+const (
+	fieldRuleNumber = iota
+	fieldSubRule
+	fieldAnchor
+	fieldLabel
+	fieldInterface
+	fieldReason
+	fieldAction
+	fieldDirection
+	fieldIPVersion
+	minFields
+)
+
+// Field positions following the common header for IPv4 records.
+const (
+	ipv4ProtoName = 16
+	ipv4Source    = 18
+	ipv4Dest      = 19
+	ipv4SrcPort   = 20
+	ipv4DstPort   = 21
+)
+
+// Field positions following the common header for IPv6 records.
+const (
+	ipv6ProtoName = 12
+	ipv6Source    = 15
+	ipv6Dest      = 16
+	ipv6SrcPort   = 17
+	ipv6DstPort   = 18
+)
+
+const (
+	syslogTag      = "filterlog"
+	bsdStampFields = 3
+	bsdStampLayout = "Jan 2 15:04:05"
+
+	// futureSkew is how far past the read time a completed RFC 3164 timestamp may lie before it is
+	// attributed to the previous year. It absorbs clock and time zone differences.
+	futureSkew = 24 * time.Hour
+)
+
+// Entry represents a single parsed filterlog record.
+type Entry struct {
+	Time        time.Time
+	RuleNumber  string
+	SubRule     string
+	Anchor      string
+	Label       string
+	Interface   string
+	Reason      string
+	Action      string
+	Direction   string
+	IPVersion   int
+	Protocol    string
+	Source      string
+	Destination string
+	SourcePort  string
+	DestPort    string
+}
+
+// Parser parses filterlog lines.
+type Parser struct {
+	// Now is the time the log is read at, used to complete RFC 3164 timestamps, which carry no year.
+	// The current time is used when zero.
+	Now time.Time
+}
+
+// NewParser creates a new Parser that completes year-less timestamps relative to the current time.
+func NewParser() *Parser {
+	return &Parser{Now: time.Now()}
+}
+
+// ParseLine parses a single log line into an Entry.
+// It returns ErrNotFilterlog for lines that do not carry a filterlog record
+// and ErrMalformedRecord for records that cannot be decoded.
+func (p *Parser) ParseLine(line string) (Entry, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Entry{}, ErrNotFilterlog
+	}
+
+	record := fields[len(fields)-1]
+
+	var ts time.Time
+
+	tagIndex := indexOfTag(fields)
+
+	switch {
+	case tagIndex >= 0:
+		ts = p.parseTimestamp(fields[:tagIndex])
+	case len(fields) == 1 && strings.Count(record, ",") >= minFields-1:
+		// Bare CSV record without a syslog envelope.
+	default:
+		return Entry{}, ErrNotFilterlog
+	}
+
+	entry, err := parseRecord(record)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Time = ts
+
+	return entry, nil
+}
+
+// indexOfTag returns the index of the filterlog syslog tag, or -1 if absent.
+func indexOfTag(fields []string) int {
+	for i, field := range fields {
+		if field == syslogTag || strings.HasPrefix(field, syslogTag+"[") || field == syslogTag+":" {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseTimestamp extracts the timestamp from the syslog header preceding the tag.
+// A zero time is returned when no supported timestamp is found.
+func (p *Parser) parseTimestamp(header []string) time.Time {
+	if len(header) == 0 {
+		return time.Time{}
+	}
+
+	// RFC 5424: "<PRI>VERSION TIMESTAMP HOSTNAME ..."
+	if strings.HasPrefix(header[0], "<") && len(header) > 1 {
+		if ts, err := time.Parse(time.RFC3339Nano, header[1]); err == nil {
+			return ts
+		}
+	}
+
+	// ISO 8601 timestamp as the first field (OPNsense log viewer exports).
+	if ts, err := time.Parse(time.RFC3339Nano, header[0]); err == nil {
+		return ts
+	}
+
+	// RFC 3164: "MMM dd HH:MM:SS HOSTNAME ...", optionally prefixed by "<PRI>".
+	stamp := header
+	if strings.HasPrefix(stamp[0], "<") {
+		if idx := strings.Index(stamp[0], ">"); idx >= 0 && idx < len(stamp[0])-1 {
+			stamp = append([]string{stamp[0][idx+1:]}, stamp[1:]...)
+		}
+	}
+
+	if len(stamp) >= bsdStampFields {
+		value := strings.Join(stamp[:bsdStampFields], " ")
+		if ts, err := time.Parse(bsdStampLayout, value); err == nil {
+			return p.completeYear(ts)
+		}
+	}
+
+	return time.Time{}
+}
+
+// completeYear places a year-less timestamp in the year of the read time, or in the previous year
+// when it would otherwise lie in the future, as December entries read in January do.
+func (p *Parser) completeYear(ts time.Time) time.Time {
+	now := p.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	completed := time.Date(now.Year(), ts.Month(), ts.Day(), ts.Hour(), ts.Minute(), ts.Second(), 0, time.UTC)
+	if completed.After(now.Add(futureSkew)) {
+		completed = completed.AddDate(-1, 0, 0)
+	}
+
+	return completed
+}
+
+// parseRecord decodes the comma-separated filterlog record.
+func parseRecord(record string) (Entry, error) {
+	values := strings.Split(record, ",")
+	if len(values) < minFields {
+		return Entry{}, fmt.Errorf("%w: expected at least %d fields, got %d", ErrMalformedRecord, minFields, len(values))
+	}
+
+	entry := Entry{
+		RuleNumber: values[fieldRuleNumber],
+		SubRule:    values[fieldSubRule],
+		Anchor:     values[fieldAnchor],
+		Label:      values[fieldLabel],
+		Interface:  values[fieldInterface],
+		Reason:     values[fieldReason],
+		Action:     values[fieldAction],
+		Direction:  values[fieldDirection],
+	}
+
+	version, err := strconv.Atoi(values[fieldIPVersion])
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: invalid IP version %q", ErrMalformedRecord, values[fieldIPVersion])
+	}
+
+	entry.IPVersion = version
+
+	switch version {
+	case 4:
+		entry.Protocol = field(values, ipv4ProtoName)
+		entry.Source = field(values, ipv4Source)
+		entry.Destination = field(values, ipv4Dest)
+		entry.SourcePort = field(values, ipv4SrcPort)
+		entry.DestPort = field(values, ipv4DstPort)
+	case 6:
+		entry.Protocol = field(values, ipv6ProtoName)
+		entry.Source = field(values, ipv6Source)
+		entry.Destination = field(values, ipv6Dest)
+		entry.SourcePort = field(values, ipv6SrcPort)
+		entry.DestPort = field(values, ipv6DstPort)
+	}
+
+	// Ports are only present for TCP and UDP records.
+	if entry.Protocol != "tcp" && entry.Protocol != "udp" {
+		entry.SourcePort = ""
+		entry.DestPort = ""
+	}
+
+	return entry, nil
+}
+
+// field returns the value at index i or an empty string if out of range.
+func field(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+
+	return ""
+}
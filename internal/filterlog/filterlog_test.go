@@ -0,0 +1,137 @@
+package filterlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ipv4TCPRecord  = "82,,,fae559338f65e11c53669fc3642c93c2,vtnet0,match,pass,out,4,0x0,,64,0,0,DF,6,tcp,60,192.168.1.10,8.8.8.8,51234,443,0,S,123,,64240,,mss"
+	ipv6UDPRecord  = "15,,,02f4bab031b57d1e30553ce08e0ec131,igb1,match,block,in,6,0x00,0x00000,255,udp,17,40,fe80::1,ff02::1,546,547,40"
+	ipv4ICMPRecord = "7,,,1000000103,em0,match,block,in,4,0x0,,128,1234,0,none,1,icmp,60,10.0.0.5,10.0.0.1,request,1,2"
+)
+
+func TestParser_ParseLine(t *testing.T) {
+	p := &Parser{Now: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		line     string
+		wantTime time.Time
+		want     Entry
+	}{
+		{
+			name:     "RFC 5424 envelope",
+			line:     `<134>1 2024-03-01T10:15:30+00:00 OPNsense.localdomain filterlog 61803 - [meta sequenceId="1"] ` + ipv4TCPRecord,
+			wantTime: time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC),
+			want: Entry{
+				RuleNumber:  "82",
+				Label:       "fae559338f65e11c53669fc3642c93c2",
+				Interface:   "vtnet0",
+				Reason:      "match",
+				Action:      "pass",
+				Direction:   "out",
+				IPVersion:   4,
+				Protocol:    "tcp",
+				Source:      "192.168.1.10",
+				Destination: "8.8.8.8",
+				SourcePort:  "51234",
+				DestPort:    "443",
+			},
+		},
+		{
+			name:     "RFC 3164 envelope",
+			line:     "Mar  1 10:15:30 fw filterlog[1234]: " + ipv6UDPRecord,
+			wantTime: time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC),
+			want: Entry{
+				RuleNumber:  "15",
+				Label:       "02f4bab031b57d1e30553ce08e0ec131",
+				Interface:   "igb1",
+				Reason:      "match",
+				Action:      "block",
+				Direction:   "in",
+				IPVersion:   6,
+				Protocol:    "udp",
+				Source:      "fe80::1",
+				Destination: "ff02::1",
+				SourcePort:  "546",
+				DestPort:    "547",
+			},
+		},
+		{
+			name:     "ISO timestamp export",
+			line:     "2024-03-01T10:15:30Z Informational filterlog " + ipv4ICMPRecord,
+			wantTime: time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC),
+			want: Entry{
+				RuleNumber:  "7",
+				Label:       "1000000103",
+				Interface:   "em0",
+				Reason:      "match",
+				Action:      "block",
+				Direction:   "in",
+				IPVersion:   4,
+				Protocol:    "icmp",
+				Source:      "10.0.0.5",
+				Destination: "10.0.0.1",
+			},
+		},
+		{
+			name: "bare CSV record",
+			line: ipv4ICMPRecord,
+			want: Entry{
+				RuleNumber:  "7",
+				Label:       "1000000103",
+				Interface:   "em0",
+				Reason:      "match",
+				Action:      "block",
+				Direction:   "in",
+				IPVersion:   4,
+				Protocol:    "icmp",
+				Source:      "10.0.0.5",
+				Destination: "10.0.0.1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.ParseLine(tt.line)
+			require.NoError(t, err)
+			assert.True(t, tt.wantTime.Equal(entry.Time), "unexpected time %s", entry.Time)
+
+			entry.Time = time.Time{}
+			assert.Equal(t, tt.want, entry)
+		})
+	}
+}
+
+func TestParser_YearRollover(t *testing.T) {
+	p := &Parser{Now: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)}
+
+	entry, err := p.ParseLine("Dec 31 23:59:59 fw filterlog[1234]: " + ipv4TCPRecord)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC), entry.Time)
+
+	entry, err = p.ParseLine("Jan  2 09:30:00 fw filterlog[1234]: " + ipv4TCPRecord)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC), entry.Time, "small skew stays in the current year")
+}
+
+func TestParser_ParseLineErrors(t *testing.T) {
+	p := NewParser()
+
+	_, err := p.ParseLine("")
+	require.ErrorIs(t, err, ErrNotFilterlog)
+
+	_, err = p.ParseLine("Mar  1 10:15:30 fw sshd[42]: Accepted publickey for root")
+	require.ErrorIs(t, err, ErrNotFilterlog)
+
+	_, err = p.ParseLine("Mar  1 10:15:30 fw filterlog[1234]: 82,,,label")
+	require.ErrorIs(t, err, ErrMalformedRecord)
+
+	_, err = p.ParseLine("Mar  1 10:15:30 fw filterlog[1234]: 82,,,label,em0,match,pass,in,x")
+	require.ErrorIs(t, err, ErrMalformedRecord)
+}
@@ -0,0 +1,153 @@
+package filterlog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// DefaultTopTalkers is the default number of source addresses kept per rule.
+const DefaultTopTalkers = 5
+
+// Summary describes the outcome of attributing log entries to rules.
+type Summary struct {
+	// Entries is the number of log entries considered.
+	Entries int
+	// Attributed is the number of entries matched to at least one rule.
+	Attributed int
+	// Unattributed is the number of entries whose label matched no rule.
+	Unattributed int
+	// UnusedRules is the number of enabled, logging rules without any attributed entries.
+	UnusedRules int
+	// UnloggedRules is the number of enabled rules without attributed entries that do not log,
+	// whose usage the log data cannot show.
+	UnloggedRules int
+}
+
+// Annotate attributes the log entries to the filter rules of doc and stores the
+// resulting statistics in each rule's Hits field. Entries are matched by comparing
+// the filterlog label with the rule UUID (with or without dashes) and the rule tracker.
+// Every rule receives a non-nil Hits value, so rules without matches report zero hits; rules that
+// do not log are marked Unlogged, since filterlog never records their matches.
+// topTalkers limits the number of source addresses kept per rule; values below one
+// fall back to DefaultTopTalkers.
+func Annotate(doc *model.OpnSenseDocument, log *Log, topTalkers int) Summary {
+	if topTalkers < 1 {
+		topTalkers = DefaultTopTalkers
+	}
+
+	rules := doc.Filter.Rule
+	index := buildRuleIndex(rules)
+	talkers := make([]map[string]int, len(rules))
+
+	for i := range rules {
+		rules[i].Hits = &model.RuleHits{Unlogged: !rules[i].Log.Bool()}
+		talkers[i] = make(map[string]int)
+	}
+
+	summary := Summary{Entries: len(log.Entries)}
+
+	for _, entry := range log.Entries {
+		matches := index[normalizeLabel(entry.Label)]
+		if len(matches) == 0 {
+			summary.Unattributed++
+			continue
+		}
+
+		summary.Attributed++
+
+		for _, i := range matches {
+			recordHit(rules[i].Hits, entry)
+
+			if entry.Source != "" {
+				talkers[i][entry.Source]++
+			}
+		}
+	}
+
+	for i := range rules {
+		rules[i].Hits.TopTalkers = rankTalkers(talkers[i], topTalkers)
+
+		if rules[i].Disabled != "" {
+			continue
+		}
+
+		switch {
+		case rules[i].Hits.IsUnused():
+			summary.UnusedRules++
+		case rules[i].Hits.IsCoverageUnknown():
+			summary.UnloggedRules++
+		}
+	}
+
+	return summary
+}
+
+// buildRuleIndex maps every known rule identifier to the indices of the rules carrying it.
+func buildRuleIndex(rules []model.Rule) map[string][]int {
+	index := make(map[string][]int)
+
+	for i, rule := range rules {
+		keys := []string{normalizeLabel(rule.UUID), normalizeLabel(rule.Tracker)}
+		for j, key := range keys {
+			if key == "" || (j > 0 && key == keys[0]) {
+				continue
+			}
+
+			index[key] = append(index[key], i)
+		}
+	}
+
+	return index
+}
+
+// normalizeLabel lowercases an identifier and strips UUID dashes so that
+// labels and UUIDs can be compared regardless of their formatting.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(label), "-", ""))
+}
+
+// recordHit updates the hit counters with a single log entry.
+func recordHit(hits *model.RuleHits, entry Entry) {
+	hits.Count++
+
+	if entry.Time.IsZero() {
+		return
+	}
+
+	if hits.FirstSeen.IsZero() || entry.Time.Before(hits.FirstSeen) {
+		hits.FirstSeen = entry.Time
+	}
+
+	if hits.LastSeen.IsZero() || entry.Time.After(hits.LastSeen) {
+		hits.LastSeen = entry.Time
+	}
+}
+
+// rankTalkers returns the n most frequent source addresses in descending order of hits.
+// Ties are broken by address to keep the output deterministic.
+func rankTalkers(counts map[string]int, n int) []model.Talker {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	ranked := make([]model.Talker, 0, len(counts))
+	for address, count := range counts {
+		ranked = append(ranked, model.Talker{Address: address, Count: count})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+
+		return ranked[i].Address < ranked[j].Address
+	})
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+
+	return ranked
+}
@@ -0,0 +1,74 @@
+package filterlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate(t *testing.T) {
+	doc := &model.OpnSenseDocument{
+		Filter: model.Filter{
+			Rule: []model.Rule{
+				{Type: "pass", Descr: "web", UUID: "fae55933-8f65-e11c-5366-9fc3642c93c2", Log: true},
+				{Type: "block", Descr: "tracked", Tracker: "1000000103"},
+				{Type: "pass", Descr: "unused", UUID: "11111111-2222-3333-4444-555555555555", Log: true},
+				{Type: "pass", Descr: "disabled", Disabled: "1", Log: true},
+				{Type: "pass", Descr: "unlogged", UUID: "66666666-7777-8888-9999-000000000000"},
+			},
+		},
+	}
+
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	log := &Log{
+		Entries: []Entry{
+			{Time: base.Add(time.Hour), Label: "fae559338f65e11c53669fc3642c93c2", Source: "192.168.1.10"},
+			{Time: base, Label: "FAE559338F65E11C53669FC3642C93C2", Source: "192.168.1.11"},
+			{Time: base.Add(2 * time.Hour), Label: "fae559338f65e11c53669fc3642c93c2", Source: "192.168.1.10"},
+			{Time: base, Label: "1000000103", Source: "10.0.0.5"},
+			{Time: base, Label: "deadbeef", Source: "10.0.0.6"},
+		},
+	}
+
+	summary := Annotate(doc, log, 1)
+
+	assert.Equal(t, Summary{Entries: 5, Attributed: 4, Unattributed: 1, UnusedRules: 1, UnloggedRules: 1}, summary)
+
+	rules := doc.FilterRules()
+	for _, rule := range rules {
+		require.NotNil(t, rule.Hits, rule.Descr)
+	}
+
+	web := rules[0].Hits
+	assert.Equal(t, 3, web.Count)
+	assert.True(t, web.FirstSeen.Equal(base))
+	assert.True(t, web.LastSeen.Equal(base.Add(2*time.Hour)))
+	assert.Equal(t, []model.Talker{{Address: "192.168.1.10", Count: 2}}, web.TopTalkers)
+
+	// Matches count even for rules that do not log now
+	assert.Equal(t, 1, rules[1].Hits.Count)
+	assert.False(t, rules[1].Hits.IsUnused())
+	assert.False(t, rules[1].Hits.IsCoverageUnknown())
+
+	assert.True(t, rules[2].Hits.IsUnused())
+	assert.True(t, rules[3].Hits.IsUnused())
+
+	// Rules that do not log never appear in filterlog, so their usage is unknown
+	assert.True(t, rules[4].Hits.Unlogged)
+	assert.False(t, rules[4].Hits.IsUnused())
+	assert.True(t, rules[4].Hits.IsCoverageUnknown())
+}
+
+func TestRankTalkers(t *testing.T) {
+	ranked := rankTalkers(map[string]int{"b": 2, "a": 2, "c": 5, "d": 1}, 3)
+	assert.Equal(t, []model.Talker{
+		{Address: "c", Count: 5},
+		{Address: "a", Count: 2},
+		{Address: "b", Count: 2},
+	}, ranked)
+
+	assert.Nil(t, rankTalkers(nil, 3))
+}
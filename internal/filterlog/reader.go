@@ -0,0 +1,139 @@
+package filterlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxLineSize bounds the length of a single log line.
+const maxLineSize = 1024 * 1024
+
+// gzipMagic is the header prefix identifying gzip-compressed content.
+var gzipMagic = []byte{0x1f, 0x8b} //nolint:gochecknoglobals // gzip file signature
+
+// Log holds the entries parsed from one or more filterlog sources.
+type Log struct {
+	// Entries contains the successfully parsed filterlog records.
+	Entries []Entry
+	// Skipped counts lines that were ignored because they were not filterlog records or were malformed.
+	Skipped int
+}
+
+// Window returns the timestamps of the earliest and latest entries.
+// Entries without a timestamp are ignored.
+func (l *Log) Window() (start, end time.Time) {
+	for _, entry := range l.Entries {
+		if entry.Time.IsZero() {
+			continue
+		}
+
+		if start.IsZero() || entry.Time.Before(start) {
+			start = entry.Time
+		}
+
+		if end.IsZero() || entry.Time.After(end) {
+			end = entry.Time
+		}
+	}
+
+	return start, end
+}
+
+// Parse reads filterlog lines from r, decompressing gzip content when detected.
+func (p *Parser) Parse(ctx context.Context, r io.Reader) (*Log, error) {
+	reader, err := maybeDecompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Log{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		entry, err := p.ParseLine(scanner.Text())
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read filterlog data: %w", err)
+	}
+
+	return result, nil
+}
+
+// ParseFiles parses each file and merges the results into a single Log.
+func (p *Parser) ParseFiles(ctx context.Context, paths ...string) (*Log, error) {
+	merged := &Log{}
+
+	for _, path := range paths {
+		parsed, err := p.parseFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+
+		merged.Entries = append(merged.Entries, parsed.Entries...)
+		merged.Skipped += parsed.Skipped
+	}
+
+	return merged, nil
+}
+
+// parseFile opens and parses a single filterlog file.
+func (p *Parser) parseFile(ctx context.Context, path string) (result *Log, err error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open filterlog file %s: %w", path, err)
+	}
+
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close filterlog file %s: %w", path, cerr)
+		}
+	}()
+
+	result, err = p.Parse(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse filterlog file %s: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// maybeDecompress wraps r in a gzip reader when the content starts with the gzip signature.
+func maybeDecompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+
+	header, err := buffered.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read filterlog data: %w", err)
+	}
+
+	if len(header) == len(gzipMagic) && header[0] == gzipMagic[0] && header[1] == gzipMagic[1] {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip filterlog data: %w", err)
+		}
+
+		return gz, nil
+	}
+
+	return buffered, nil
+}
@@ -0,0 +1,78 @@
+package filterlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleLog = `<134>1 2024-03-01T10:15:30+00:00 fw filterlog 1 - [meta sequenceId="1"] ` + ipv4TCPRecord + `
+<134>1 2024-03-01T09:00:00+00:00 fw filterlog 1 - [meta sequenceId="2"] ` + ipv4TCPRecord + `
+<38>1 2024-03-01T09:30:00+00:00 fw sshd 42 - - Accepted publickey for root
+`
+
+func TestParser_Parse(t *testing.T) {
+	result, err := NewParser().Parse(context.Background(), strings.NewReader(sampleLog))
+	require.NoError(t, err)
+
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, 1, result.Skipped)
+
+	start, end := result.Window()
+	assert.True(t, start.Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, end.Equal(time.Date(2024, 3, 1, 10, 15, 30, 0, time.UTC)))
+}
+
+func TestParser_ParseGzip(t *testing.T) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(sampleLog))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	result, err := NewParser().Parse(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+}
+
+func TestParser_ParseCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewParser().Parse(ctx, strings.NewReader(sampleLog))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParser_ParseFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "filter.log")
+	require.NoError(t, os.WriteFile(plain, []byte(sampleLog), 0o600))
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(sampleLog))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	compressed := filepath.Join(dir, "filter.log.1.gz")
+	require.NoError(t, os.WriteFile(compressed, buf.Bytes(), 0o600))
+
+	result, err := NewParser().ParseFiles(context.Background(), plain, compressed)
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 4)
+	assert.Equal(t, 2, result.Skipped)
+
+	_, err = NewParser().ParseFiles(context.Background(), filepath.Join(dir, "missing.log"))
+	require.Error(t, err)
+}
@@ -0,0 +1,392 @@
+// Package hasync checks the high-availability configuration of OPNsense firewalls.
+//
+// CheckNode inspects a single configuration for CARP and pfsync mistakes.
+// ComparePair additionally verifies that a primary and a secondary node agree
+// on CARP virtual IPs, state synchronisation addressing, interfaces, VLANs,
+// firewall rules and every configuration section listed in the sync items.
+package hasync
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Severity is the severity of an HA issue. The values match the processor severities.
+type Severity string
+
+// Severity levels used by HA issues.
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// Node names used in issues.
+const (
+	NodePrimary   = "primary"
+	NodeSecondary = "secondary"
+)
+
+// CARP setting limits as enforced by FreeBSD.
+const (
+	minVHID    = 1
+	maxVHID    = 255
+	minAdvbase = 1
+	maxAdvbase = 255
+	minAdvskew = 0
+	maxAdvskew = 254
+)
+
+// dhcpEnabled is the value OPNsense stores for enabled DHCP servers.
+const dhcpEnabled = "1"
+
+// Issue is a single HA configuration problem.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Node is the node the issue was found on, empty for issues of the pair.
+	Node           string `json:"node,omitempty"`
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	Component      string `json:"component"`
+	Recommendation string `json:"recommendation"`
+}
+
+// Result is the outcome of an HA check.
+type Result struct {
+	Issues []Issue `json:"issues"`
+	// Compared lists the sync items whose configuration sections were compared.
+	Compared []string `json:"compared,omitempty"`
+	// Unverified lists the sync items opnDossier cannot compare.
+	Unverified []string `json:"unverified,omitempty"`
+}
+
+// HasSeverity reports whether the result contains an issue of the given severity.
+func (r *Result) HasSeverity(severity Severity) bool {
+	return slices.ContainsFunc(r.Issues, func(issue Issue) bool {
+		return issue.Severity == severity
+	})
+}
+
+// Count returns the number of issues of the given severity.
+func (r *Result) Count(severity Severity) int {
+	count := 0
+
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r *Result) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// Configured reports whether the configuration uses CARP, pfsync or configuration synchronisation.
+func Configured(cfg *model.OpnSenseDocument) bool {
+	if cfg == nil {
+		return false
+	}
+
+	ha := cfg.HighAvailabilitySync
+
+	return strings.TrimSpace(ha.Pfsyncinterface) != "" ||
+		strings.TrimSpace(ha.Synchronizetoip) != "" ||
+		len(cfg.VirtualIP.CARP()) > 0
+}
+
+// CheckNode runs the single-node checks. Configurations without any HA setup yield no issues.
+func CheckNode(cfg *model.OpnSenseDocument) *Result {
+	result := &Result{}
+	if !Configured(cfg) {
+		return result
+	}
+
+	checkNode(result, cfg, "")
+
+	return result
+}
+
+// checkNode runs the single-node checks, attributing the issues to node.
+func checkNode(r *Result, cfg *model.OpnSenseDocument, node string) {
+	start := len(r.Issues)
+
+	checkPfsync(r, cfg)
+	checkConfigSync(r, cfg)
+	checkCARP(r, cfg)
+
+	for i := start; i < len(r.Issues); i++ {
+		r.Issues[i].Node = node
+	}
+}
+
+// checkPfsync verifies the state synchronisation interface and peer.
+func checkPfsync(r *Result, cfg *model.OpnSenseDocument) {
+	ha := cfg.HighAvailabilitySync
+	name := strings.TrimSpace(ha.Pfsyncinterface)
+
+	if name == "" {
+		if len(cfg.VirtualIP.CARP()) > 0 {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Title:          "CARP Without State Synchronisation",
+				Description:    "CARP virtual IPs are configured but no pfsync interface is set",
+				Component:      "hasync.pfsyncinterface",
+				Recommendation: "Select a dedicated pfsync interface so that connections survive a failover",
+			})
+		}
+
+		return
+	}
+
+	iface, ok := cfg.Interfaces.Get(name)
+	if !ok {
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Unknown pfsync Interface",
+			Description:    fmt.Sprintf("The pfsync interface %s does not exist", name),
+			Component:      "hasync.pfsyncinterface",
+			Recommendation: "Select an existing, dedicated interface for pfsync",
+		})
+
+		return
+	}
+
+	if reason := sharedUse(cfg, name, iface); reason != "" {
+		r.add(Issue{
+			Severity:       SeverityMedium,
+			Title:          "pfsync On Shared Interface",
+			Description:    fmt.Sprintf("pfsync runs on %s, which %s", name, reason),
+			Component:      "hasync.pfsyncinterface",
+			Recommendation: "Run pfsync over a dedicated link between the nodes; state updates are not authenticated",
+		})
+	}
+
+	prefix, hasPrefix := interfacePrefix(iface)
+	peer := strings.TrimSpace(ha.Pfsyncpeerip)
+
+	switch addr, ok := parseAddr(peer); {
+	case peer == "":
+		r.add(Issue{
+			Severity:       SeverityLow,
+			Title:          "pfsync Peer Not Set",
+			Description:    "pfsync sends state updates by multicast because no peer address is set",
+			Component:      "hasync.pfsyncpeerip",
+			Recommendation: "Set the pfsync peer to the address of the other node on the pfsync interface",
+		})
+	case !ok:
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Invalid pfsync Peer",
+			Description:    fmt.Sprintf("The pfsync peer %q is not an IP address", peer),
+			Component:      "hasync.pfsyncpeerip",
+			Recommendation: "Set the pfsync peer to the address of the other node on the pfsync interface",
+		})
+	case hasPrefix && !prefix.Contains(addr):
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "pfsync Peer Outside pfsync Subnet",
+			Description:    fmt.Sprintf("The pfsync peer %s is not within %s (%s)", addr, prefix, name),
+			Component:      "hasync.pfsyncpeerip",
+			Recommendation: "Set the pfsync peer to the address of the other node on the pfsync interface",
+		})
+	}
+
+	target := strings.TrimSpace(ha.Synchronizetoip)
+	if addr, ok := parseAddr(target); ok && hasPrefix && !prefix.Contains(addr) {
+		r.add(Issue{
+			Severity: SeverityMedium,
+			Title:    "Configuration Sync Outside pfsync Subnet",
+			Description: fmt.Sprintf(
+				"Configuration is synchronised to %s, which is not within the pfsync subnet %s",
+				addr, prefix,
+			),
+			Component:      "hasync.synchronizetoip",
+			Recommendation: "Synchronise the configuration over the dedicated pfsync link",
+		})
+	}
+}
+
+// sharedUse explains why an interface is not dedicated to pfsync, or returns an empty string.
+func sharedUse(cfg *model.OpnSenseDocument, name string, iface model.Interface) string {
+	switch {
+	case name == "wan" || name == "lan":
+		return "is the " + strings.ToUpper(name) + " interface"
+	case strings.TrimSpace(iface.Gateway) != "":
+		return "has a gateway"
+	case slices.ContainsFunc(cfg.VirtualIP.CARP(), func(vip model.VIP) bool { return vip.Interface == name }):
+		return "also carries CARP virtual IPs"
+	}
+
+	if dhcp, ok := cfg.Dhcpd.Items[name]; ok && dhcp.Enable == dhcpEnabled {
+		return "also serves DHCP"
+	}
+
+	return ""
+}
+
+// checkConfigSync verifies the XMLRPC configuration synchronisation settings.
+func checkConfigSync(r *Result, cfg *model.OpnSenseDocument) {
+	ha := cfg.HighAvailabilitySync
+	target := strings.TrimSpace(ha.Synchronizetoip)
+
+	if target == "" {
+		return
+	}
+
+	if _, ok := parseAddr(target); !ok {
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Invalid Configuration Sync Target",
+			Description:    fmt.Sprintf("The configuration sync target %q is not an IP address", target),
+			Component:      "hasync.synchronizetoip",
+			Recommendation: "Set the sync target to the address of the secondary node",
+		})
+	}
+
+	if strings.TrimSpace(ha.Username) == "" || strings.TrimSpace(ha.Password) == "" {
+		r.add(Issue{
+			Severity:       SeverityMedium,
+			Title:          "Configuration Sync Credentials Missing",
+			Description:    "Configuration synchronisation is enabled without a remote username and password",
+			Component:      "hasync.username",
+			Recommendation: "Set the credentials of a dedicated account on the secondary node",
+		})
+	}
+
+	if len(splitList(ha.Syncitems)) == 0 {
+		r.add(Issue{
+			Severity:       SeverityLow,
+			Title:          "No Items Synchronised",
+			Description:    fmt.Sprintf("Configuration synchronisation to %s has no sync items selected", target),
+			Component:      "hasync.syncitems",
+			Recommendation: "Select the configuration sections that must be identical on both nodes",
+		})
+	}
+}
+
+// checkCARP verifies the settings of every CARP virtual IP.
+func checkCARP(r *Result, cfg *model.OpnSenseDocument) {
+	seen := make(map[string]string)
+
+	for i, vip := range cfg.VirtualIP.Vip {
+		if !vip.IsCARP() {
+			continue
+		}
+
+		component := fmt.Sprintf("virtualip.vip[%d]", i)
+		label := vipLabel(vip)
+
+		iface, ok := cfg.Interfaces.Get(vip.Interface)
+		if !ok {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Title:          "CARP VIP On Unknown Interface",
+				Description:    fmt.Sprintf("CARP VIP %s references the unknown interface %q", label, vip.Interface),
+				Component:      component + ".interface",
+				Recommendation: "Assign the CARP VIP to an existing interface",
+			})
+		} else if prefix, ok := interfacePrefix(iface); ok {
+			if addr, ok := parseAddr(vip.Subnet); ok && !prefix.Contains(addr) {
+				r.add(Issue{
+					Severity:       SeverityMedium,
+					Title:          "CARP VIP Outside Interface Subnet",
+					Description:    fmt.Sprintf("CARP VIP %s is not within %s (%s)", label, prefix, vip.Interface),
+					Component:      component + ".subnet",
+					Recommendation: "Use an address from the interface subnet for the CARP VIP",
+				})
+			}
+		}
+
+		if vhid, ok := parseRange(vip.Vhid, minVHID, maxVHID); !ok {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Title:          "Invalid CARP VHID",
+				Description:    fmt.Sprintf("CARP VIP %s has the VHID %q, expected %d-%d", label, vip.Vhid, minVHID, maxVHID),
+				Component:      component + ".vhid",
+				Recommendation: "Assign a unique VHID between 1 and 255",
+			})
+		} else {
+			key := fmt.Sprintf("%s/%d", vip.Interface, vhid)
+			if other, dup := seen[key]; dup {
+				r.add(Issue{
+					Severity: SeverityHigh,
+					Title:    "Duplicate CARP VHID",
+					Description: fmt.Sprintf(
+						"CARP VIPs %s and %s share VHID %d on %s", other, label, vhid, vip.Interface,
+					),
+					Component:      component + ".vhid",
+					Recommendation: "Use a distinct VHID for every CARP group on an interface",
+				})
+			}
+
+			seen[key] = label
+		}
+
+		if _, ok := parseRange(vip.Advbase, minAdvbase, maxAdvbase); !ok {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Title:          "Invalid CARP Advertisement Base",
+				Description:    fmt.Sprintf("CARP VIP %s has the advbase %q", label, vip.Advbase),
+				Component:      component + ".advbase",
+				Recommendation: "Set the advertisement base to a value between 1 and 255 seconds",
+			})
+		}
+
+		if _, ok := parseRange(vip.Advskew, minAdvskew, maxAdvskew); !ok {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Title:          "Invalid CARP Advertisement Skew",
+				Description:    fmt.Sprintf("CARP VIP %s has the advskew %q", label, vip.Advskew),
+				Component:      component + ".advskew",
+				Recommendation: "Set the advertisement skew to a value between 0 and 254",
+			})
+		}
+
+		if !vip.HasPassword() {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Title:          "CARP VIP Without Password",
+				Description:    fmt.Sprintf("CARP VIP %s has no password", label),
+				Component:      component + ".password",
+				Recommendation: "Set a strong CARP password, identical on both nodes",
+			})
+		}
+	}
+}
+
+// vipLabel identifies a virtual IP in issue descriptions.
+func vipLabel(vip model.VIP) string {
+	label := vip.Subnet
+	if vip.SubnetBits != "" {
+		label += "/" + vip.SubnetBits
+	}
+
+	if descr := strings.TrimSpace(vip.Descr); descr != "" {
+		label += " (" + descr + ")"
+	}
+
+	return label
+}
+
+// nodeAddrs returns every static interface address of a node.
+func nodeAddrs(cfg *model.OpnSenseDocument) []netip.Addr {
+	names := cfg.Interfaces.Names()
+	slices.Sort(names)
+
+	addrs := make([]netip.Addr, 0, len(names))
+
+	for _, name := range names {
+		if addr, ok := interfaceAddr(cfg.Interfaces.Items[name]); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
@@ -0,0 +1,168 @@
+package hasync
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// haNode returns a consistent HA node using the given host octet and advskew.
+func haNode(host, peer, advskew string) *model.OpnSenseDocument {
+	cfg := &model.OpnSenseDocument{
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{
+			"wan":  {Enable: "1", If: "igb0", IPAddr: "203.0.113." + host, Subnet: "24", Gateway: "WAN_GW"},
+			"lan":  {Enable: "1", If: "igb1", IPAddr: "192.168.1." + host, Subnet: "24"},
+			"opt1": {Enable: "1", If: "igb2", IPAddr: "10.255.0." + host, Subnet: "30", Descr: "PFSYNC"},
+		}},
+		HighAvailabilitySync: model.HighAvailabilitySync{
+			Pfsyncinterface: "opt1",
+			Pfsyncpeerip:    "10.255.0." + peer,
+		},
+		VirtualIP: model.VirtualIP{Vip: []model.VIP{
+			{
+				Mode: "carp", Interface: "wan", Subnet: "203.0.113.1", SubnetBits: "24",
+				Vhid: "1", Advbase: "1", Advskew: advskew, Password: "secret", Descr: "WAN CARP",
+			},
+			{
+				Mode: "carp", Interface: "lan", Subnet: "192.168.1.1", SubnetBits: "24",
+				Vhid: "2", Advbase: "1", Advskew: advskew, Password: "secret", Descr: "LAN CARP",
+			},
+			{Mode: "ipalias", Interface: "lan", Subnet: "192.168.1.5", SubnetBits: "32"},
+		}},
+		Filter: model.Filter{Rule: []model.Rule{{Type: "pass", Descr: "Allow LAN"}}},
+	}
+
+	return cfg
+}
+
+// haPair returns a consistent primary/secondary pair.
+func haPair() (*model.OpnSenseDocument, *model.OpnSenseDocument) {
+	primary := haNode("2", "3", "0")
+	primary.HighAvailabilitySync.Synchronizetoip = "10.255.0.3"
+	primary.HighAvailabilitySync.Username = "root"
+	primary.HighAvailabilitySync.Password = "sync-secret"
+	primary.HighAvailabilitySync.Syncitems = "rules,virtualip,users,ipsec"
+
+	return primary, haNode("3", "2", "100")
+}
+
+func titles(result *Result) []string {
+	names := make([]string, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		names = append(names, issue.Title)
+	}
+
+	return names
+}
+
+func TestCheckNode_NoHA(t *testing.T) {
+	assert.Empty(t, CheckNode(&model.OpnSenseDocument{}).Issues)
+	assert.Empty(t, CheckNode(nil).Issues)
+	assert.False(t, Configured(nil))
+}
+
+func TestCheckNode_ConsistentNode(t *testing.T) {
+	primary, _ := haPair()
+
+	result := CheckNode(primary)
+	assert.Empty(t, result.Issues, "unexpected issues: %v", titles(result))
+}
+
+func TestCheckNode_Pfsync(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *model.OpnSenseDocument)
+		title  string
+	}{
+		{
+			name:   "CARP without pfsync",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Pfsyncinterface = "" },
+			title:  "CARP Without State Synchronisation",
+		},
+		{
+			name:   "unknown pfsync interface",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Pfsyncinterface = "opt9" },
+			title:  "Unknown pfsync Interface",
+		},
+		{
+			name:   "pfsync on LAN",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Pfsyncinterface = "lan" },
+			title:  "pfsync On Shared Interface",
+		},
+		{
+			name:   "peer not set",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Pfsyncpeerip = "" },
+			title:  "pfsync Peer Not Set",
+		},
+		{
+			name:   "peer outside subnet",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Pfsyncpeerip = "192.168.1.3" },
+			title:  "pfsync Peer Outside pfsync Subnet",
+		},
+		{
+			name:   "sync target outside pfsync subnet",
+			modify: func(cfg *model.OpnSenseDocument) { cfg.HighAvailabilitySync.Synchronizetoip = "192.168.1.3" },
+			title:  "Configuration Sync Outside pfsync Subnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, _ := haPair()
+			tt.modify(primary)
+
+			assert.Contains(t, titles(CheckNode(primary)), tt.title)
+		})
+	}
+}
+
+func TestCheckNode_ConfigSync(t *testing.T) {
+	primary, _ := haPair()
+	primary.HighAvailabilitySync.Password = ""
+	primary.HighAvailabilitySync.Syncitems = ""
+
+	got := titles(CheckNode(primary))
+	assert.Contains(t, got, "Configuration Sync Credentials Missing")
+	assert.Contains(t, got, "No Items Synchronised")
+}
+
+func TestCheckNode_CARP(t *testing.T) {
+	primary, _ := haPair()
+	primary.VirtualIP.Vip = append(primary.VirtualIP.Vip,
+		model.VIP{Mode: "carp", Interface: "lan", Subnet: "192.168.1.10", Vhid: "2", Advbase: "1", Advskew: "0"},
+		model.VIP{Mode: "carp", Interface: "lan", Subnet: "10.0.0.1", Vhid: "300", Advbase: "0", Advskew: "255"},
+		model.VIP{Mode: "carp", Interface: "opt7", Subnet: "10.0.0.2", Vhid: "5", Advbase: "1", Advskew: "0"},
+	)
+
+	got := titles(CheckNode(primary))
+
+	for _, title := range []string{
+		"Duplicate CARP VHID",
+		"CARP VIP Without Password",
+		"Invalid CARP VHID",
+		"Invalid CARP Advertisement Base",
+		"Invalid CARP Advertisement Skew",
+		"CARP VIP Outside Interface Subnet",
+		"CARP VIP On Unknown Interface",
+	} {
+		assert.Contains(t, got, title)
+	}
+}
+
+func TestResult_Severities(t *testing.T) {
+	result := &Result{Issues: []Issue{{Severity: SeverityHigh}, {Severity: SeverityLow}, {Severity: SeverityLow}}}
+
+	assert.True(t, result.HasSeverity(SeverityHigh))
+	assert.False(t, result.HasSeverity(SeverityMedium))
+	assert.Equal(t, 2, result.Count(SeverityLow))
+}
+
+func TestIssueTable(t *testing.T) {
+	table := IssueTable([]Issue{{Severity: SeverityHigh, Title: "A|B", Description: "line\nbreak"}})
+
+	require.Len(t, table.Rows, 1)
+	assert.Equal(t, []string{"HIGH", "-", "A\\|B", "", "line break", ""}, table.Rows[0])
+	require.NoError(t, table.ValidateColumns())
+}
@@ -0,0 +1,64 @@
+package hasync
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// interfacePrefix returns the IPv4 network of a statically addressed interface.
+func interfacePrefix(iface model.Interface) (netip.Prefix, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(iface.IPAddr))
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	bits, err := strconv.Atoi(strings.TrimSpace(iface.Subnet))
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	return prefix, true
+}
+
+// interfaceAddr returns the static address of an interface.
+func interfaceAddr(iface model.Interface) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(iface.IPAddr))
+	return addr, err == nil
+}
+
+// parseAddr parses a single IP address, ignoring surrounding whitespace.
+func parseAddr(value string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	return addr, err == nil
+}
+
+// parseRange parses an integer setting and reports whether it lies within [low, high].
+func parseRange(value string, low, high int) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+
+	return n, n >= low && n <= high
+}
+
+// splitList splits a comma separated list, dropping empty values.
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
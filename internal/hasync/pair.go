@@ -0,0 +1,454 @@
+package hasync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// syncSection extracts the configuration section covered by a sync item.
+type syncSection func(cfg *model.OpnSenseDocument) any
+
+// syncSections maps OPNsense sync item names to the sections they replicate.
+var syncSections = map[string]syncSection{ //nolint:gochecknoglobals // lookup table
+	"aliases":      func(cfg *model.OpnSenseDocument) any { return cfg.OPNsense.Firewall },
+	"authservers":  func(cfg *model.OpnSenseDocument) any { return cfg.System.AuthServer },
+	"certs":        func(cfg *model.OpnSenseDocument) any { return []any{cfg.CertificateAuthority, cfg.Cert} },
+	"dhcpd":        func(cfg *model.OpnSenseDocument) any { return cfg.Dhcpd },
+	"dhcpdv6":      func(cfg *model.OpnSenseDocument) any { return cfg.DHCPv6Server },
+	"dnsmasq":      func(cfg *model.OpnSenseDocument) any { return cfg.DNSMasquerade },
+	"nat":          func(cfg *model.OpnSenseDocument) any { return cfg.Nat },
+	"ntpd":         func(cfg *model.OpnSenseDocument) any { return cfg.Ntpd },
+	"openvpn":      func(cfg *model.OpnSenseDocument) any { return cfg.OpenVPN },
+	"rules":        func(cfg *model.OpnSenseDocument) any { return cfg.Filter.Rule },
+	"staticroutes": func(cfg *model.OpnSenseDocument) any { return cfg.StaticRoutes },
+	"sysctl":       func(cfg *model.OpnSenseDocument) any { return cfg.Sysctl },
+	"unbound":      func(cfg *model.OpnSenseDocument) any { return cfg.Unbound },
+	"users":        func(cfg *model.OpnSenseDocument) any { return []any{cfg.System.User, cfg.System.Group} },
+	"virtualip":    func(cfg *model.OpnSenseDocument) any { return syncedVIPs(cfg.VirtualIP.Vip) },
+}
+
+// ComparePair runs the single-node checks on both nodes and verifies that they form a consistent pair.
+// Configuration synchronisation is expected to run from primary to secondary.
+func ComparePair(primary, secondary *model.OpnSenseDocument) *Result {
+	result := &Result{}
+	if primary == nil || secondary == nil {
+		return result
+	}
+
+	checkNode(result, primary, NodePrimary)
+	checkNode(result, secondary, NodeSecondary)
+
+	compareCARP(result, primary, secondary)
+	comparePfsync(result, primary, secondary)
+	compareInterfaces(result, primary, secondary)
+	compareVLANs(result, primary, secondary)
+	compareSyncItems(result, primary, secondary)
+
+	return result
+}
+
+// vipKey identifies a CARP group by interface and VHID.
+func vipKey(vip model.VIP) string {
+	return vip.Interface + "/" + strings.TrimSpace(vip.Vhid)
+}
+
+// compareCARP verifies that both nodes define the same CARP groups and that the primary wins elections.
+func compareCARP(r *Result, primary, secondary *model.OpnSenseDocument) {
+	secondaryVIPs := make(map[string]model.VIP)
+	for _, vip := range secondary.VirtualIP.CARP() {
+		secondaryVIPs[vipKey(vip)] = vip
+	}
+
+	matched := make(map[string]bool)
+
+	for _, vip := range primary.VirtualIP.CARP() {
+		key := vipKey(vip)
+		component := "virtualip." + key
+		label := vipLabel(vip)
+
+		other, ok := secondaryVIPs[key]
+		if !ok {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Node:           NodeSecondary,
+				Title:          "CARP VIP Missing On Secondary",
+				Description:    fmt.Sprintf("CARP VIP %s (VHID %s on %s) is not defined", label, vip.Vhid, vip.Interface),
+				Component:      component,
+				Recommendation: "Synchronise the virtual IPs to the secondary node",
+			})
+
+			continue
+		}
+
+		matched[key] = true
+
+		if vip.Subnet != other.Subnet || vip.SubnetBits != other.SubnetBits {
+			r.add(Issue{
+				Severity: SeverityHigh,
+				Title:    "CARP VIP Address Mismatch",
+				Description: fmt.Sprintf(
+					"VHID %s on %s is %s on the primary but %s on the secondary",
+					vip.Vhid, vip.Interface, label, vipLabel(other),
+				),
+				Component:      component + ".subnet",
+				Recommendation: "Use the same address for a CARP group on both nodes",
+			})
+		}
+
+		if vip.Password != other.Password {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Title:          "CARP Password Mismatch",
+				Description:    fmt.Sprintf("CARP VIP %s uses different passwords on the two nodes", label),
+				Component:      component + ".password",
+				Recommendation: "Set the same CARP password on both nodes",
+			})
+		}
+
+		if strings.TrimSpace(vip.Advbase) != strings.TrimSpace(other.Advbase) {
+			r.add(Issue{
+				Severity: SeverityMedium,
+				Title:    "CARP Advertisement Base Mismatch",
+				Description: fmt.Sprintf(
+					"CARP VIP %s has advbase %s on the primary but %s on the secondary",
+					label, vip.Advbase, other.Advbase,
+				),
+				Component:      component + ".advbase",
+				Recommendation: "Use the same advertisement base on both nodes",
+			})
+		}
+
+		primarySkew, errPrimary := strconv.Atoi(strings.TrimSpace(vip.Advskew))
+		secondarySkew, errSecondary := strconv.Atoi(strings.TrimSpace(other.Advskew))
+
+		if errPrimary == nil && errSecondary == nil && secondarySkew <= primarySkew {
+			r.add(Issue{
+				Severity: SeverityHigh,
+				Title:    "Secondary Does Not Yield To Primary",
+				Description: fmt.Sprintf(
+					"CARP VIP %s has advskew %d on the primary and %d on the secondary",
+					label, primarySkew, secondarySkew,
+				),
+				Component:      component + ".advskew",
+				Recommendation: "Give the secondary node a higher advskew than the primary, for example 100",
+			})
+		}
+	}
+
+	for _, vip := range secondary.VirtualIP.CARP() {
+		if key := vipKey(vip); !matched[key] {
+			r.add(Issue{
+				Severity: SeverityMedium,
+				Node:     NodeSecondary,
+				Title:    "CARP VIP Only On Secondary",
+				Description: fmt.Sprintf(
+					"CARP VIP %s (VHID %s on %s) is not defined on the primary",
+					vipLabel(vip), vip.Vhid, vip.Interface,
+				),
+				Component:      "virtualip." + key,
+				Recommendation: "Remove the stale virtual IP or define it on the primary",
+			})
+		}
+	}
+}
+
+// comparePfsync verifies that the pfsync and configuration sync addresses point at the other node.
+func comparePfsync(r *Result, primary, secondary *model.OpnSenseDocument) {
+	p, s := primary.HighAvailabilitySync, secondary.HighAvailabilitySync
+
+	if p.Pfsyncinterface != s.Pfsyncinterface {
+		r.add(Issue{
+			Severity: SeverityMedium,
+			Title:    "pfsync Interface Mismatch",
+			Description: fmt.Sprintf(
+				"pfsync runs on %q on the primary but on %q on the secondary",
+				p.Pfsyncinterface, s.Pfsyncinterface,
+			),
+			Component:      "hasync.pfsyncinterface",
+			Recommendation: "Use the same dedicated interface for pfsync on both nodes",
+		})
+	}
+
+	checkPeer(r, NodePrimary, p, secondary)
+	checkPeer(r, NodeSecondary, s, primary)
+
+	if target, ok := parseAddr(p.Synchronizetoip); ok && !slices.Contains(nodeAddrs(secondary), target) {
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Node:           NodePrimary,
+			Title:          "Configuration Sync Target Is Not The Secondary",
+			Description:    fmt.Sprintf("Configuration is synchronised to %s, which no secondary interface uses", target),
+			Component:      "hasync.synchronizetoip",
+			Recommendation: "Set the sync target to the secondary's address on the pfsync interface",
+		})
+	}
+
+	if strings.TrimSpace(p.Synchronizetoip) == "" {
+		r.add(Issue{
+			Severity:       SeverityMedium,
+			Node:           NodePrimary,
+			Title:          "Configuration Sync Disabled",
+			Description:    "The primary does not synchronise its configuration to the secondary",
+			Component:      "hasync.synchronizetoip",
+			Recommendation: "Set the sync target so that rules, users and virtual IPs stay identical",
+		})
+	}
+
+	if strings.TrimSpace(s.Synchronizetoip) != "" {
+		r.add(Issue{
+			Severity: SeverityMedium,
+			Node:     NodeSecondary,
+			Title:    "Secondary Synchronises Configuration",
+			Description: fmt.Sprintf(
+				"The secondary synchronises its configuration to %s, so changes can flow in both directions",
+				s.Synchronizetoip,
+			),
+			Component:      "hasync.synchronizetoip",
+			Recommendation: "Only synchronise from the primary and clear the sync target on the secondary",
+		})
+	}
+}
+
+// checkPeer verifies that the pfsync peer of node is the address of other on its pfsync interface.
+func checkPeer(r *Result, node string, ha model.HighAvailabilitySync, other *model.OpnSenseDocument) {
+	peer, ok := parseAddr(ha.Pfsyncpeerip)
+	if !ok {
+		return
+	}
+
+	iface, ok := other.Interfaces.Get(strings.TrimSpace(other.HighAvailabilitySync.Pfsyncinterface))
+	if !ok {
+		return
+	}
+
+	if addr, ok := interfaceAddr(iface); ok && addr != peer {
+		r.add(Issue{
+			Severity: SeverityHigh,
+			Node:     node,
+			Title:    "pfsync Peer Is Not The Other Node",
+			Description: fmt.Sprintf(
+				"The pfsync peer is %s but the other node uses %s on its pfsync interface", peer, addr,
+			),
+			Component:      "hasync.pfsyncpeerip",
+			Recommendation: "Point the pfsync peer at the other node's pfsync address",
+		})
+	}
+}
+
+// compareInterfaces verifies that both nodes assign the same interfaces within the same subnets.
+func compareInterfaces(r *Result, primary, secondary *model.OpnSenseDocument) {
+	names := primary.Interfaces.Names()
+	slices.Sort(names)
+
+	for _, name := range names {
+		p := primary.Interfaces.Items[name]
+
+		s, ok := secondary.Interfaces.Get(name)
+		if !ok {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Node:           NodeSecondary,
+				Title:          "Interface Missing On Secondary",
+				Description:    fmt.Sprintf("Interface %s is not assigned", name),
+				Component:      "interfaces." + name,
+				Recommendation: "Assign the same interfaces in the same order on both nodes",
+			})
+
+			continue
+		}
+
+		component := "interfaces." + name
+
+		if p.Enable != s.Enable {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Title:          "Interface State Mismatch",
+				Description:    fmt.Sprintf("Interface %s is enabled on only one node", name),
+				Component:      component + ".enable",
+				Recommendation: "Enable the interface on both nodes",
+			})
+		}
+
+		if p.If != s.If {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Title:          "Interface Device Mismatch",
+				Description:    fmt.Sprintf("Interface %s uses %s on the primary but %s on the secondary", name, p.If, s.If),
+				Component:      component + ".if",
+				Recommendation: "Use identical hardware or the same device assignment on both nodes",
+			})
+		}
+
+		compareAddresses(r, name, p, s)
+	}
+
+	for _, name := range secondary.Interfaces.Names() {
+		if _, ok := primary.Interfaces.Get(name); !ok {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Node:           NodePrimary,
+				Title:          "Interface Missing On Primary",
+				Description:    fmt.Sprintf("Interface %s is only assigned on the secondary", name),
+				Component:      "interfaces." + name,
+				Recommendation: "Assign the same interfaces in the same order on both nodes",
+			})
+		}
+	}
+}
+
+// compareAddresses verifies that an interface has distinct addresses within the same subnet on both nodes.
+func compareAddresses(r *Result, name string, p, s model.Interface) {
+	pPrefix, pOK := interfacePrefix(p)
+	sPrefix, sOK := interfacePrefix(s)
+
+	if !pOK || !sOK || pPrefix.Addr().IsLoopback() {
+		return
+	}
+
+	component := "interfaces." + name + ".ipaddr"
+
+	if pPrefix != sPrefix {
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Interface Subnet Mismatch",
+			Description:    fmt.Sprintf("Interface %s is in %s on the primary but in %s on the secondary", name, pPrefix, sPrefix),
+			Component:      component,
+			Recommendation: "Address the interface from the same subnet on both nodes",
+		})
+	}
+
+	if strings.TrimSpace(p.IPAddr) == strings.TrimSpace(s.IPAddr) {
+		r.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Duplicate Interface Address",
+			Description:    fmt.Sprintf("Interface %s uses %s on both nodes", name, strings.TrimSpace(p.IPAddr)),
+			Component:      component,
+			Recommendation: "Give every node its own interface address and share only the CARP VIP",
+		})
+	}
+}
+
+// compareVLANs verifies that both nodes define the same VLANs.
+func compareVLANs(r *Result, primary, secondary *model.OpnSenseDocument) {
+	primaryVLANs := vlanSet(primary.VLANs.VLAN)
+	secondaryVLANs := vlanSet(secondary.VLANs.VLAN)
+
+	for _, key := range sortedKeys(primaryVLANs) {
+		if !secondaryVLANs[key] {
+			r.add(Issue{
+				Severity:       SeverityHigh,
+				Node:           NodeSecondary,
+				Title:          "VLAN Missing On Secondary",
+				Description:    fmt.Sprintf("VLAN %s is not defined", key),
+				Component:      "vlans." + key,
+				Recommendation: "Define the same VLANs on both nodes",
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(secondaryVLANs) {
+		if !primaryVLANs[key] {
+			r.add(Issue{
+				Severity:       SeverityMedium,
+				Node:           NodePrimary,
+				Title:          "VLAN Missing On Primary",
+				Description:    fmt.Sprintf("VLAN %s is only defined on the secondary", key),
+				Component:      "vlans." + key,
+				Recommendation: "Define the same VLANs on both nodes",
+			})
+		}
+	}
+}
+
+// vlanSet returns the VLANs keyed by parent interface and tag.
+func vlanSet(vlans []model.VLAN) map[string]bool {
+	set := make(map[string]bool, len(vlans))
+
+	for _, vlan := range vlans {
+		if vlan.If == "" && vlan.Tag == "" {
+			continue
+		}
+
+		set[vlan.If+"."+vlan.Tag] = true
+	}
+
+	return set
+}
+
+// compareSyncItems verifies that every section listed in the primary's sync items is identical on both nodes.
+// Firewall rules must always match, whether or not they are synchronised.
+func compareSyncItems(r *Result, primary, secondary *model.OpnSenseDocument) {
+	items := splitList(primary.HighAvailabilitySync.Syncitems)
+	slices.Sort(items)
+	items = slices.Compact(items)
+
+	for _, item := range items {
+		section, ok := syncSections[item]
+		if !ok {
+			r.Unverified = append(r.Unverified, item)
+			continue
+		}
+
+		r.Compared = append(r.Compared, item)
+
+		if !sameSection(section(primary), section(secondary)) {
+			r.add(Issue{
+				Severity: SeverityHigh,
+				Title:    "Synchronised Section Differs",
+				Description: fmt.Sprintf(
+					"The %s section is synchronised but differs between the nodes; the last sync failed or was overridden",
+					item,
+				),
+				Component:      "hasync.syncitems." + item,
+				Recommendation: "Run a configuration sync from the primary and review changes made on the secondary",
+			})
+		}
+	}
+
+	if !slices.Contains(r.Compared, "rules") && !sameSection(primary.Filter.Rule, secondary.Filter.Rule) {
+		r.add(Issue{
+			Severity:       SeverityMedium,
+			Title:          "Firewall Rules Differ",
+			Description:    "The firewall rules differ between the nodes and are not synchronised",
+			Component:      "filter.rule",
+			Recommendation: "Add rules to the sync items so that a failover does not change the policy",
+		})
+	}
+}
+
+// syncedVIPs returns the virtual IPs with the node-specific advskew removed.
+func syncedVIPs(vips []model.VIP) []model.VIP {
+	synced := make([]model.VIP, len(vips))
+	for i, vip := range vips {
+		vip.Advskew = ""
+		synced[i] = vip
+	}
+
+	return synced
+}
+
+// sameSection compares two configuration sections by their JSON representation.
+func sameSection(a, b any) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
+
+// sortedKeys returns the keys of a set in ascending order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,157 @@
+package hasync
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePair_Consistent(t *testing.T) {
+	primary, secondary := haPair()
+
+	result := ComparePair(primary, secondary)
+	assert.Empty(t, result.Issues, "unexpected issues: %v", titles(result))
+	assert.Equal(t, []string{"rules", "users", "virtualip"}, result.Compared)
+	assert.Equal(t, []string{"ipsec"}, result.Unverified)
+}
+
+func TestComparePair_NilNode(t *testing.T) {
+	primary, _ := haPair()
+	assert.Empty(t, ComparePair(primary, nil).Issues)
+}
+
+func TestComparePair_CARP(t *testing.T) {
+	primary, secondary := haPair()
+	secondary.VirtualIP.Vip[0].Password = "other"
+	secondary.VirtualIP.Vip[0].Advskew = "0"
+	secondary.VirtualIP.Vip[0].Advbase = "2"
+	secondary.VirtualIP.Vip[1].Vhid = "3"
+
+	result := ComparePair(primary, secondary)
+	got := titles(result)
+
+	for _, title := range []string{
+		"CARP Password Mismatch",
+		"Secondary Does Not Yield To Primary",
+		"CARP Advertisement Base Mismatch",
+		"CARP VIP Missing On Secondary",
+		"CARP VIP Only On Secondary",
+		"Synchronised Section Differs",
+	} {
+		assert.Contains(t, got, title)
+	}
+
+	for _, issue := range result.Issues {
+		assert.NotContains(t, issue.Description, "secret", "CARP passwords must not be disclosed")
+	}
+}
+
+func TestComparePair_AdvskewIgnoredInVirtualIPSync(t *testing.T) {
+	primary, secondary := haPair()
+	secondary.VirtualIP.Vip[0].Subnet = "203.0.113.9"
+
+	result := ComparePair(primary, secondary)
+	got := titles(result)
+
+	assert.Contains(t, got, "CARP VIP Address Mismatch")
+	assert.Contains(t, got, "Synchronised Section Differs")
+}
+
+func TestComparePair_Addressing(t *testing.T) {
+	primary, secondary := haPair()
+	primary.HighAvailabilitySync.Pfsyncpeerip = "10.255.0.1"
+	primary.HighAvailabilitySync.Synchronizetoip = "10.255.0.1"
+	secondary.HighAvailabilitySync.Synchronizetoip = "10.255.0.2"
+
+	got := titles(ComparePair(primary, secondary))
+
+	assert.Contains(t, got, "pfsync Peer Is Not The Other Node")
+	assert.Contains(t, got, "Configuration Sync Target Is Not The Secondary")
+	assert.Contains(t, got, "Secondary Synchronises Configuration")
+}
+
+func TestComparePair_ConfigSyncDisabled(t *testing.T) {
+	primary, secondary := haPair()
+	primary.HighAvailabilitySync.Synchronizetoip = ""
+
+	got := titles(ComparePair(primary, secondary))
+	assert.Contains(t, got, "Configuration Sync Disabled")
+	assert.NotContains(t, got, "Firewall Rules Differ")
+}
+
+func TestComparePair_UnsyncedRulesDiffer(t *testing.T) {
+	primary, secondary := haPair()
+	primary.HighAvailabilitySync.Syncitems = "users"
+	secondary.Filter.Rule = nil
+
+	assert.Contains(t, titles(ComparePair(primary, secondary)), "Firewall Rules Differ")
+}
+
+func TestComparePair_InterfacesAndVLANs(t *testing.T) {
+	primary, secondary := haPair()
+	primary.VLANs.VLAN = []model.VLAN{{If: "igb1", Tag: "10"}, {If: "igb1", Tag: "20"}}
+	secondary.VLANs.VLAN = []model.VLAN{{If: "igb1", Tag: "10"}, {If: "igb1", Tag: "30"}}
+
+	lan := secondary.Interfaces.Items["lan"]
+	lan.IPAddr = "192.168.1.2"
+	lan.If = "em1"
+	secondary.Interfaces.Items["lan"] = lan
+
+	opt := secondary.Interfaces.Items["opt1"]
+	opt.Subnet = "29"
+	secondary.Interfaces.Items["opt1"] = opt
+
+	delete(secondary.Interfaces.Items, "wan")
+	secondary.Interfaces.Items["opt2"] = model.Interface{If: "igb3"}
+
+	got := titles(ComparePair(primary, secondary))
+
+	for _, title := range []string{
+		"Duplicate Interface Address",
+		"Interface Device Mismatch",
+		"Interface Subnet Mismatch",
+		"Interface Missing On Secondary",
+		"Interface Missing On Primary",
+		"VLAN Missing On Secondary",
+		"VLAN Missing On Primary",
+	} {
+		assert.Contains(t, got, title)
+	}
+}
+
+func TestComparePair_SyncedRulesDiffer(t *testing.T) {
+	primary, secondary := haPair()
+	secondary.Filter.Rule = append(secondary.Filter.Rule, model.Rule{Type: "block"})
+
+	result := ComparePair(primary, secondary)
+
+	var differs []Issue
+
+	for _, issue := range result.Issues {
+		if issue.Title == "Synchronised Section Differs" {
+			differs = append(differs, issue)
+		}
+	}
+
+	require.Len(t, differs, 1)
+	assert.Equal(t, "hasync.syncitems.rules", differs[0].Component)
+	assert.NotContains(t, titles(result), "Firewall Rules Differ")
+}
+
+func TestComparePair_AttributesNodeIssues(t *testing.T) {
+	primary, secondary := haPair()
+	secondary.VirtualIP.Vip[1].Password = ""
+
+	result := ComparePair(primary, secondary)
+
+	for _, issue := range result.Issues {
+		if issue.Title == "CARP VIP Without Password" {
+			assert.Equal(t, NodeSecondary, issue.Node)
+			return
+		}
+	}
+
+	t.Fatal("missing CARP VIP Without Password issue")
+}
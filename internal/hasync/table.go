@@ -0,0 +1,39 @@
+package hasync
+
+import (
+	"strings"
+
+	"github.com/nao1215/markdown"
+)
+
+// IssueTable returns the issues as a markdown table.
+func IssueTable(issues []Issue) markdown.TableSet {
+	table := markdown.TableSet{
+		Header: []string{"Severity", "Node", "Title", "Component", "Description", "Recommendation"},
+		Rows:   make([][]string, 0, len(issues)),
+	}
+
+	for _, issue := range issues {
+		node := issue.Node
+		if node == "" {
+			node = "-"
+		}
+
+		table.Rows = append(table.Rows, []string{
+			strings.ToUpper(string(issue.Severity)),
+			node,
+			escapeCell(issue.Title),
+			escapeCell(issue.Component),
+			escapeCell(issue.Description),
+			escapeCell(issue.Recommendation),
+		})
+	}
+
+	return table
+}
+
+// escapeCell escapes characters that would break a markdown table cell.
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
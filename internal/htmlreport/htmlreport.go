@@ -0,0 +1,224 @@
+// Package htmlreport renders markdown reports as standalone HTML documents.
+//
+// The HTML format is derived from the markdown produced by the report builders, so both formats
+// always contain the same sections. The document is self-contained: styles and scripts are
+// inlined and no external assets are referenced, so reports can be opened offline.
+package htmlreport
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/display"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// defaultTitle is the document title of reports without a level 1 heading.
+const defaultTitle = "opnDossier Report"
+
+//go:embed report.html.tmpl
+var pageTemplate string
+
+//nolint:gochecknoglobals // parsed once from the embedded template
+var page = template.Must(template.New("report").Parse(pageTemplate))
+
+// severities are the finding severities that are colour-coded in table cells.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var severities = []string{"critical", "high", "medium", "low", "info"}
+
+// Options controls the rendering of an HTML report.
+type Options struct {
+	// Title is the document title. If empty, the first level 1 heading is used.
+	Title string
+	// Theme selects the initial colour theme, light or dark. Any other value follows the system
+	// preference. Readers can switch the theme in the document.
+	Theme string
+}
+
+// tocEntry is a heading in the table of contents.
+type tocEntry struct {
+	ID       string
+	Title    string
+	Children []tocEntry
+}
+
+// Render converts a markdown report into a standalone HTML document with a collapsible table of
+// contents, sortable and filterable tables and colour-coded severities.
+func Render(content string, opts Options) (string, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	source := []byte(content)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	title, toc := annotate(doc, source)
+	if opts.Title != "" {
+		title = opts.Title
+	}
+
+	var body bytes.Buffer
+	if err := md.Renderer().Render(&body, source, doc); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	data := struct {
+		Title     string
+		Theme     string
+		Generator string
+		Palettes  template.CSS
+		TOC       []tocEntry
+		Body      template.HTML
+	}{
+		Title:     title,
+		Theme:     themeName(opts.Theme),
+		Generator: constants.AppName + " " + constants.Version,
+		Palettes:  palettes(),
+		TOC:       toc,
+		//nolint:gosec // produced by goldmark, which escapes text and omits raw HTML
+		Body: template.HTML(body.String()),
+	}
+
+	var out bytes.Buffer
+	if err := page.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// annotate returns the document title and the table of contents of level 2 and 3 headings, and
+// marks table cells that hold a severity so that they are colour-coded.
+func annotate(doc ast.Node, source []byte) (string, []tocEntry) {
+	title := ""
+
+	var toc []tocEntry
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Heading:
+			entry := tocEntry{Title: nodeText(node, source)}
+			if id, ok := node.AttributeString("id"); ok {
+				entry.ID = string(id.([]byte))
+			}
+
+			switch {
+			case node.Level == 1 && title == "":
+				title = entry.Title
+			case node.Level == 2:
+				toc = append(toc, entry)
+			case node.Level == 3 && len(toc) > 0:
+				toc[len(toc)-1].Children = append(toc[len(toc)-1].Children, entry)
+			}
+
+			return ast.WalkSkipChildren, nil
+		case *extast.TableCell:
+			if _, header := node.Parent().(*extast.TableHeader); header {
+				return ast.WalkSkipChildren, nil
+			}
+
+			severity := strings.ToLower(strings.TrimSpace(nodeText(node, source)))
+			if slices.Contains(severities, severity) {
+				node.SetAttributeString("class", []byte("severity severity-"+severity))
+			}
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	if title == "" {
+		title = defaultTitle
+	}
+
+	return title, toc
+}
+
+// nodeText returns the plain text of an inline node tree.
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := child.(type) {
+		case *ast.Text:
+			sb.Write(node.Segment.Value(source))
+
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(node.Value)
+		case *ast.CodeSpan:
+			for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+				if segment, ok := c.(*ast.Text); ok {
+					sb.Write(segment.Segment.Value(source))
+				}
+			}
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(sb.String())
+}
+
+// themeName returns the data-theme attribute for the configured theme, or an empty string to
+// follow the system preference.
+func themeName(theme string) string {
+	switch strings.ToLower(theme) {
+	case constants.ThemeLight, constants.ThemeDark:
+		return strings.ToLower(theme)
+	default:
+		return ""
+	}
+}
+
+// palettes returns the CSS custom properties of the light and dark display themes. The light
+// palette is the default, the dark palette applies when selected or preferred by the system.
+func palettes() template.CSS {
+	light := display.LightTheme()
+	dark := display.DarkTheme()
+
+	var sb strings.Builder
+
+	sb.WriteString(":root, :root[data-theme=\"light\"] {")
+	writeProperties(&sb, light.Palette)
+	sb.WriteString("}\n:root[data-theme=\"dark\"] {")
+	writeProperties(&sb, dark.Palette)
+	sb.WriteString("}\n@media (prefers-color-scheme: dark) {\n:root:not([data-theme]) {")
+	writeProperties(&sb, dark.Palette)
+	sb.WriteString("}\n}")
+
+	return template.CSS(sb.String()) //nolint:gosec // built from the constant theme palettes
+}
+
+// writeProperties writes a palette as CSS custom properties in key order.
+func writeProperties(sb *strings.Builder, palette map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(palette)) {
+		fmt.Fprintf(sb, " --%s: %s;", strings.ReplaceAll(key, "_", "-"), palette[key])
+	}
+}
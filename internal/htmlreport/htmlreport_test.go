@@ -0,0 +1,94 @@
+package htmlreport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleReport = `# Firewall Report
+
+## System
+
+Hostname: ` + "`fw01`" + `
+
+### Interfaces
+
+| Name | Address |
+| ---- | ------- |
+| wan  | dhcp    |
+| lan  | 10.0.0.1 |
+
+## Findings
+
+| Severity | Title |
+| -------- | ----- |
+| HIGH | Weak <script>alert(1)</script> cipher |
+| low | Missing banner |
+| Medium rare | Not a severity |
+`
+
+func TestRender(t *testing.T) {
+	output, err := Render(sampleReport, Options{})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.Contains(t, output, "<title>Firewall Report</title>")
+	assert.Contains(t, output, `<html lang="en">`)
+
+	t.Run("table of contents", func(t *testing.T) {
+		assert.Contains(t, output, `<summary><a href="#system">System</a></summary>`)
+		assert.Contains(t, output, `<li><a href="#interfaces">Interfaces</a></li>`)
+		assert.Contains(t, output, `<a href="#findings">Findings</a>`)
+		assert.Contains(t, output, `<h3 id="interfaces">Interfaces</h3>`)
+	})
+
+	t.Run("severities", func(t *testing.T) {
+		assert.Contains(t, output, `<td class="severity severity-high">HIGH</td>`)
+		assert.Contains(t, output, `<td class="severity severity-low">low</td>`)
+		assert.Contains(t, output, `<td>Medium rare</td>`)
+		assert.Contains(t, output, "<th>Severity</th>")
+	})
+
+	t.Run("self-contained", func(t *testing.T) {
+		assert.NotContains(t, output, "<script>alert(1)")
+		assert.NotContains(t, output, "src=")
+		assert.NotContains(t, output, "<link")
+		assert.NotContains(t, output, "http://")
+		assert.NotContains(t, output, "https://")
+		assert.Contains(t, output, "--table-header: #E9ECEF;")
+		assert.Contains(t, output, "--table-header: #2D2D2D;")
+	})
+}
+
+func TestRender_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{name: "light theme", opts: Options{Theme: "light"}, expected: `<html lang="en" data-theme="light">`},
+		{name: "dark theme", opts: Options{Theme: "Dark"}, expected: `<html lang="en" data-theme="dark">`},
+		{name: "system theme", opts: Options{Theme: "auto"}, expected: `<html lang="en">`},
+		{name: "title", opts: Options{Title: "fw01 & fw02"}, expected: "<title>fw01 &amp; fw02</title>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Render(sampleReport, tt.opts)
+			require.NoError(t, err)
+			assert.Contains(t, output, tt.expected)
+		})
+	}
+}
+
+func TestRender_NoHeadings(t *testing.T) {
+	output, err := Render("Plain text only.", Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "<title>"+defaultTitle+"</title>")
+	assert.NotContains(t, output, `<nav class="toc"`)
+	assert.Contains(t, output, "<p>Plain text only.</p>")
+}
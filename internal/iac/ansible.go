@@ -0,0 +1,266 @@
+package iac
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ansibleCollection is the Ansible collection whose modules take the exported variables.
+const ansibleCollection = "ansibleguy.opnsense"
+
+// ansibleIndent is the indentation of the generated YAML.
+const ansibleIndent = 2
+
+// ansibleVars are the exported variables, one list for each module of the collection.
+type ansibleVars struct {
+	Aliases          []ansibleAlias          `yaml:"opnsense_aliases,omitempty"`
+	Rules            []ansibleRule           `yaml:"opnsense_rules,omitempty"`
+	DestinationNAT   []ansibleDestinationNAT `yaml:"opnsense_destination_nat,omitempty"`
+	SourceNAT        []ansibleSourceNAT      `yaml:"opnsense_source_nat,omitempty"`
+	VLANs            []ansibleVLAN           `yaml:"opnsense_vlans,omitempty"`
+	UnboundHosts     []ansibleUnboundHost    `yaml:"opnsense_unbound_hosts,omitempty"`
+	UnboundHostAlias []ansibleUnboundAlias   `yaml:"opnsense_unbound_host_aliases,omitempty"`
+	UnboundDomains   []ansibleUnboundDomain  `yaml:"opnsense_unbound_domains,omitempty"`
+}
+
+// ansibleAlias takes the arguments of the alias module.
+type ansibleAlias struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	Content     []string `yaml:"content"`
+	Description string   `yaml:"description"`
+	Enabled     bool     `yaml:"enabled"`
+}
+
+// ansibleMatch holds the source and destination arguments shared by the rule modules.
+type ansibleMatch struct {
+	SourceNet         string `yaml:"source_net"`
+	SourcePort        string `yaml:"source_port"`
+	SourceInvert      bool   `yaml:"source_invert"`
+	DestinationNet    string `yaml:"destination_net"`
+	DestinationPort   string `yaml:"destination_port"`
+	DestinationInvert bool   `yaml:"destination_invert"`
+}
+
+// ansibleRule takes the arguments of the rule module.
+type ansibleRule struct {
+	Sequence     int      `yaml:"sequence"`
+	Action       string   `yaml:"action"`
+	Quick        bool     `yaml:"quick"`
+	Interface    []string `yaml:"interface"`
+	Direction    string   `yaml:"direction"`
+	IPProtocol   string   `yaml:"ip_protocol"`
+	Protocol     string   `yaml:"protocol"`
+	ansibleMatch `yaml:",inline"`
+	Log          bool   `yaml:"log"`
+	Description  string `yaml:"description"`
+	Enabled      bool   `yaml:"enabled"`
+}
+
+// ansibleDestinationNAT takes the arguments of a port forward.
+type ansibleDestinationNAT struct {
+	Sequence     int    `yaml:"sequence"`
+	Interface    string `yaml:"interface"`
+	IPProtocol   string `yaml:"ip_protocol"`
+	Protocol     string `yaml:"protocol"`
+	ansibleMatch `yaml:",inline"`
+	Target       string `yaml:"target"`
+	TargetPort   string `yaml:"target_port"`
+	Description  string `yaml:"description"`
+	Enabled      bool   `yaml:"enabled"`
+}
+
+// ansibleSourceNAT takes the arguments of the source_nat module.
+type ansibleSourceNAT struct {
+	Sequence     int    `yaml:"sequence"`
+	Interface    string `yaml:"interface"`
+	IPProtocol   string `yaml:"ip_protocol"`
+	Protocol     string `yaml:"protocol"`
+	ansibleMatch `yaml:",inline"`
+	Target       string `yaml:"target"`
+	Description  string `yaml:"description"`
+	Enabled      bool   `yaml:"enabled"`
+}
+
+// ansibleVLAN takes the arguments of the interface_vlan module.
+type ansibleVLAN struct {
+	Description string `yaml:"description"`
+	Interface   string `yaml:"interface"`
+	VLAN        int    `yaml:"vlan"`
+	Priority    int    `yaml:"priority"`
+	Device      string `yaml:"device,omitempty"`
+}
+
+// ansibleUnboundHost takes the arguments of the unbound_host module.
+type ansibleUnboundHost struct {
+	Hostname    string `yaml:"hostname"`
+	Domain      string `yaml:"domain"`
+	RecordType  string `yaml:"record_type"`
+	Value       string `yaml:"value"`
+	Priority    int    `yaml:"prio,omitempty"`
+	Description string `yaml:"description"`
+	Enabled     bool   `yaml:"enabled"`
+}
+
+// ansibleUnboundAlias takes the arguments of the unbound_host_alias module.
+type ansibleUnboundAlias struct {
+	Alias       string `yaml:"alias"`
+	Domain      string `yaml:"domain"`
+	Target      string `yaml:"target"`
+	Description string `yaml:"description"`
+	Enabled     bool   `yaml:"enabled"`
+}
+
+// ansibleUnboundDomain takes the arguments of a domain override.
+type ansibleUnboundDomain struct {
+	Domain      string `yaml:"domain"`
+	Server      string `yaml:"server"`
+	Description string `yaml:"description"`
+	Enabled     bool   `yaml:"enabled"`
+}
+
+// renderAnsible renders an inventory as Ansible variables. Interface assignments have no module in
+// the collection and are reported as unsupported.
+func renderAnsible(inv *inventory) (*Result, error) {
+	unsupported := append([]Unsupported(nil), inv.unsupported...)
+
+	for _, i := range inv.interfaces {
+		unsupported = append(unsupported, Unsupported{
+			Object: "interface " + i.name + " (" + i.device + ")",
+			Reason: "the collection does not manage interface assignments",
+		})
+	}
+
+	var vars ansibleVars
+
+	for _, a := range inv.aliases {
+		vars.Aliases = append(vars.Aliases, ansibleAlias{
+			Name:        a.name,
+			Type:        a.kind,
+			Content:     a.content,
+			Description: a.description,
+			Enabled:     a.enabled,
+		})
+	}
+
+	for _, r := range inv.rules {
+		vars.Rules = append(vars.Rules, ansibleRule{
+			Sequence:     r.sequence,
+			Action:       r.action,
+			Quick:        r.quick,
+			Interface:    append([]string{}, r.interfaces...),
+			Direction:    r.direction,
+			IPProtocol:   r.ipProtocol,
+			Protocol:     providerProtocol(r.protocol),
+			ansibleMatch: newAnsibleMatch(r.source, r.destination),
+			Log:          r.log,
+			Description:  r.description,
+			Enabled:      r.enabled,
+		})
+	}
+
+	for _, r := range inv.portForwards {
+		vars.DestinationNAT = append(vars.DestinationNAT, ansibleDestinationNAT{
+			Sequence:     r.sequence,
+			Interface:    r.iface,
+			IPProtocol:   r.ipProtocol,
+			Protocol:     providerProtocol(r.protocol),
+			ansibleMatch: newAnsibleMatch(r.source, r.destination),
+			Target:       r.targetIP,
+			TargetPort:   r.targetPort,
+			Description:  r.description,
+			Enabled:      r.enabled,
+		})
+	}
+
+	for _, r := range inv.outbound {
+		vars.SourceNAT = append(vars.SourceNAT, ansibleSourceNAT{
+			Sequence:     r.sequence,
+			Interface:    r.iface,
+			IPProtocol:   r.ipProtocol,
+			Protocol:     providerProtocol(r.protocol),
+			ansibleMatch: newAnsibleMatch(r.source, r.destination),
+			Target:       r.target,
+			Description:  r.description,
+			Enabled:      r.enabled,
+		})
+	}
+
+	for _, v := range inv.vlans {
+		vars.VLANs = append(vars.VLANs, ansibleVLAN{
+			Description: v.description,
+			Interface:   v.parent,
+			VLAN:        v.tag,
+			Priority:    v.priority,
+			Device:      v.device,
+		})
+	}
+
+	for _, h := range inv.hostOverrides {
+		host := ansibleUnboundHost{
+			Hostname:    h.hostname,
+			Domain:      h.domain,
+			RecordType:  h.rr,
+			Value:       h.server,
+			Description: h.description,
+			Enabled:     h.enabled,
+		}
+
+		if h.rr == rrMailServer {
+			host.Value, host.Priority = h.mxHost, h.mxPriority
+		}
+
+		vars.UnboundHosts = append(vars.UnboundHosts, host)
+	}
+
+	for _, a := range inv.hostAliases {
+		vars.UnboundHostAlias = append(vars.UnboundHostAlias, ansibleUnboundAlias{
+			Alias:       a.hostname,
+			Domain:      a.domain,
+			Target:      fqdn(a.override.hostname, a.override.domain),
+			Description: a.description,
+			Enabled:     a.enabled,
+		})
+	}
+
+	for _, d := range inv.domainOverrides {
+		vars.UnboundDomains = append(vars.UnboundDomains, ansibleUnboundDomain{
+			Domain:      d.domain,
+			Server:      d.server,
+			Description: d.description,
+			Enabled:     d.enabled,
+		})
+	}
+
+	var b strings.Builder
+
+	writeReport(&b, inv, "the "+ansibleCollection+" collection", unsupported)
+	b.WriteString("---\n")
+
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(ansibleIndent)
+
+	if err := encoder.Encode(vars); err != nil {
+		return nil, fmt.Errorf("failed to marshal Ansible variables: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal Ansible variables: %w", err)
+	}
+
+	return &Result{Content: b.String(), Unsupported: unsupported}, nil
+}
+
+// newAnsibleMatch returns the source and destination arguments of a rule.
+func newAnsibleMatch(source, destination endpoint) ansibleMatch {
+	return ansibleMatch{
+		SourceNet:         source.net,
+		SourcePort:        source.port,
+		SourceInvert:      source.invert,
+		DestinationNet:    destination.net,
+		DestinationPort:   destination.port,
+		DestinationInvert: destination.invert,
+	}
+}
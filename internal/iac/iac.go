@@ -0,0 +1,568 @@
+// Package iac exports a configuration as infrastructure as code: Terraform resources for the
+// community browningluke/opnsense provider and variables for the ansibleguy.opnsense Ansible
+// collection.
+//
+// Both exports cover the firewall aliases, the filter rules, the NAT rules, the VLAN devices and
+// the Unbound host, alias and domain overrides. The legacy filter and NAT rules of the
+// configuration become the automation rules that the provider and the collection manage through
+// the OPNsense API. Objects without a resource or module, such as interface assignments, are
+// listed as unsupported at the top of the export and in the result.
+//
+// The exports are deterministic: aliases are sorted by name, every other object keeps its order
+// in the configuration, and resource names are derived from the names and descriptions of the
+// objects, so repeated exports of a configuration give identical files.
+package iac
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Unsupported describes an object of the configuration that is not exported.
+type Unsupported struct {
+	// Object identifies the object, such as `interface lan` or `outbound NAT rule 2 on wan`.
+	Object string
+	Reason string
+}
+
+// String returns the unsupported object as a single line.
+func (u Unsupported) String() string {
+	return u.Object + ": " + u.Reason
+}
+
+// Result is a generated export with the objects it leaves out.
+type Result struct {
+	Content     string
+	Unsupported []Unsupported
+}
+
+// Terraform exports a configuration as Terraform resources of the browningluke/opnsense provider.
+func Terraform(doc *model.OpnSenseDocument) (*Result, error) {
+	inv, err := collect(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTerraform(inv), nil
+}
+
+// Ansible exports a configuration as Ansible variables for the modules of the ansibleguy.opnsense
+// collection.
+func Ansible(doc *model.OpnSenseDocument) (*Result, error) {
+	inv, err := collect(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderAnsible(inv)
+}
+
+// Protocol and action defaults of the automation rules.
+const (
+	anyValue      = "any"
+	actionPass    = "pass"
+	directionIn   = "in"
+	ipProtocolV4  = "inet"
+	stateKeep     = "keep state"
+	rrMailServer  = "MX"
+	sequenceSteps = 10
+)
+
+// inventory holds the objects of a configuration in the shape of the exports.
+type inventory struct {
+	hostname        string
+	aliases         []alias
+	aliasByName     map[string]*alias
+	rules           []filterRule
+	portForwards    []portForward
+	outbound        []outboundRule
+	outboundMode    string
+	vlans           []vlan
+	hostOverrides   []hostOverride
+	hostAliases     []hostAlias
+	domainOverrides []domainOverride
+	interfaces      []iface
+	unsupported     []Unsupported
+}
+
+// alias is a firewall alias.
+type alias struct {
+	name        string
+	resource    string
+	enabled     bool
+	kind        string
+	content     []string
+	description string
+}
+
+// endpoint is the source or destination of a rule.
+type endpoint struct {
+	net    string
+	port   string
+	invert bool
+}
+
+// filterRule is a filter rule.
+type filterRule struct {
+	object      string
+	resource    string
+	sequence    int
+	enabled     bool
+	action      string
+	quick       bool
+	interfaces  []string
+	direction   string
+	ipProtocol  string
+	protocol    string
+	source      endpoint
+	destination endpoint
+	log         bool
+	description string
+}
+
+// portForward is a port forward on a single interface.
+type portForward struct {
+	resource    string
+	sequence    int
+	enabled     bool
+	iface       string
+	ipProtocol  string
+	protocol    string
+	source      endpoint
+	destination endpoint
+	targetIP    string
+	targetPort  string
+	description string
+}
+
+// outboundRule is an outbound NAT rule.
+type outboundRule struct {
+	object      string
+	sequence    int
+	enabled     bool
+	iface       string
+	ipProtocol  string
+	protocol    string
+	source      endpoint
+	destination endpoint
+	target      string
+	description string
+}
+
+// vlan is a VLAN device.
+type vlan struct {
+	resource    string
+	device      string
+	parent      string
+	tag         int
+	priority    int
+	description string
+}
+
+// hostOverride is an Unbound host override.
+type hostOverride struct {
+	uuid        string
+	resource    string
+	enabled     bool
+	hostname    string
+	domain      string
+	rr          string
+	server      string
+	mxPriority  int
+	mxHost      string
+	description string
+}
+
+// hostAlias is an additional name of an Unbound host override.
+type hostAlias struct {
+	resource    string
+	enabled     bool
+	override    *hostOverride
+	hostname    string
+	domain      string
+	description string
+}
+
+// domainOverride is an Unbound domain override.
+type domainOverride struct {
+	resource    string
+	enabled     bool
+	domain      string
+	server      string
+	description string
+}
+
+// iface is an assigned interface.
+type iface struct {
+	name   string
+	device string
+}
+
+// collect gathers the exported objects of a configuration.
+func collect(doc *model.OpnSenseDocument) (*inventory, error) {
+	if doc == nil {
+		return nil, converter.ErrNilOpnSenseDocument
+	}
+
+	c := &collector{
+		doc:   doc,
+		inv:   &inventory{hostname: doc.System.Hostname, outboundMode: doc.Nat.Outbound.Mode},
+		names: make(map[string]map[string]bool),
+	}
+
+	c.aliases()
+	c.filterRules()
+	c.natRules()
+	c.vlans()
+	c.unbound()
+	c.interfaces()
+
+	return c.inv, nil
+}
+
+// collector builds an inventory and names its resources.
+type collector struct {
+	doc *model.OpnSenseDocument
+	inv *inventory
+	// names holds the resource names in use for each kind of resource.
+	names map[string]map[string]bool
+}
+
+// unsupported records an object that is not exported.
+func (c *collector) unsupported(object, reason string) {
+	c.inv.unsupported = append(c.inv.unsupported, Unsupported{Object: object, Reason: reason})
+}
+
+// resourceName returns a unique resource name of a kind, derived from the first non-empty hint.
+func (c *collector) resourceName(kind string, hints ...string) string {
+	base := ""
+	for _, hint := range hints {
+		if base = slug(hint); base != "" {
+			break
+		}
+	}
+
+	if base == "" {
+		base = kind
+	}
+
+	used := c.names[kind]
+	if used == nil {
+		used = make(map[string]bool)
+		c.names[kind] = used
+	}
+
+	name := base
+	for n := 2; used[name]; n++ {
+		name = base + "_" + strconv.Itoa(n)
+	}
+
+	used[name] = true
+
+	return name
+}
+
+// aliases collects the firewall aliases, sorted by name.
+func (c *collector) aliases() {
+	fwAliases := slices.Clone(c.doc.FirewallAliases())
+	slices.SortStableFunc(fwAliases, func(a, b model.FirewallAlias) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, a := range fwAliases {
+		if a.Type == "internal" {
+			c.unsupported("alias "+a.Name, "internal aliases are maintained by OPNsense")
+			continue
+		}
+
+		c.inv.aliases = append(c.inv.aliases, alias{
+			name:        a.Name,
+			resource:    c.resourceName("alias", a.Name),
+			enabled:     a.Enabled != "0",
+			kind:        a.Type,
+			content:     a.Entries(),
+			description: a.Description,
+		})
+	}
+
+	c.inv.aliasByName = make(map[string]*alias, len(c.inv.aliases))
+	for i := range c.inv.aliases {
+		c.inv.aliasByName[c.inv.aliases[i].name] = &c.inv.aliases[i]
+	}
+}
+
+// filterRules collects the filter rules in their order, numbered in steps so that rules can be
+// inserted between them.
+func (c *collector) filterRules() {
+	for i, r := range c.doc.Filter.Rule {
+		object := ruleObject("filter rule", i, r.Interface, r.Descr)
+
+		if r.StateType != "" && r.StateType != stateKeep {
+			c.unsupported(object, "state type "+strconv.Quote(r.StateType)+" is exported as keep state")
+		}
+
+		// Only floating rules can be non-quick; the rules of interfaces always are
+		quick := true
+		if isTrue(r.Floating) {
+			quick = isTrue(r.Quick)
+		}
+
+		c.inv.rules = append(c.inv.rules, filterRule{
+			object:      object,
+			resource:    c.resourceName("rule", r.Descr, "rule_"+strconv.Itoa(i+1)),
+			sequence:    (i + 1) * sequenceSteps,
+			enabled:     !isTrue(r.Disabled),
+			action:      firstNonEmpty(r.Type, actionPass),
+			quick:       quick,
+			interfaces:  slices.Clone([]string(r.Interface)),
+			direction:   firstNonEmpty(r.Direction, directionIn),
+			ipProtocol:  firstNonEmpty(r.IPProtocol, ipProtocolV4),
+			protocol:    firstNonEmpty(r.Protocol, anyValue),
+			source:      c.endpoint(r.Source.Any, r.Source.Network, r.Source.Address, firstNonEmpty(r.Source.Port, r.SourcePort), bool(r.Source.Not)),
+			destination: c.endpoint(r.Destination.Any, r.Destination.Network, r.Destination.Address, r.Destination.Port, bool(r.Destination.Not)),
+			log:         bool(r.Log),
+			description: r.Descr,
+		})
+	}
+}
+
+// natRules collects the port forwards, one for each interface, and the outbound NAT rules.
+func (c *collector) natRules() {
+	sequence := 0
+
+	for i, r := range c.doc.Nat.Inbound {
+		object := ruleObject("port forward", i, r.Interface, r.Descr)
+
+		interfaces := []string(r.Interface)
+		if len(interfaces) == 0 {
+			c.unsupported(object, "port forwards without an interface cannot be exported")
+			continue
+		}
+
+		for _, name := range interfaces {
+			sequence += sequenceSteps
+
+			c.inv.portForwards = append(c.inv.portForwards, portForward{
+				resource:    c.resourceName("nat", r.Descr, "port_forward_"+strconv.Itoa(i+1)),
+				sequence:    sequence,
+				enabled:     !isTrue(r.Disabled),
+				iface:       name,
+				ipProtocol:  firstNonEmpty(r.IPProtocol, ipProtocolV4),
+				protocol:    firstNonEmpty(r.Protocol, anyValue),
+				source:      c.endpoint(r.Source.Any, r.Source.Network, r.Source.Address, r.Source.Port, bool(r.Source.Not)),
+				destination: c.endpoint(r.Destination.Any, r.Destination.Network, r.Destination.Address, firstNonEmpty(r.Destination.Port, r.ExternalPort), bool(r.Destination.Not)),
+				targetIP:    r.InternalIP,
+				targetPort:  r.InternalPort,
+				description: r.Descr,
+			})
+		}
+	}
+
+	// Manual outbound rules only apply in the advanced and hybrid modes
+	mode := c.inv.outboundMode
+	manual := mode == "advanced" || mode == "hybrid"
+
+	if mode == "" || mode == "automatic" || mode == "hybrid" {
+		c.unsupported("automatic outbound NAT", "the rules that OPNsense generates are not exported")
+	}
+
+	for i, r := range c.doc.Nat.Outbound.Rule {
+		c.inv.outbound = append(c.inv.outbound, outboundRule{
+			object:      ruleObject("outbound NAT rule", i, r.Interface, r.Descr),
+			sequence:    (i + 1) * sequenceSteps,
+			enabled:     manual && !isTrue(r.Disabled),
+			iface:       r.Interface.String(),
+			ipProtocol:  firstNonEmpty(r.IPProtocol, ipProtocolV4),
+			protocol:    firstNonEmpty(r.Protocol, anyValue),
+			source:      c.endpoint(r.Source.Any, r.Source.Network, r.Source.Address, firstNonEmpty(r.Source.Port, r.SourcePort), bool(r.Source.Not)),
+			destination: c.endpoint(r.Destination.Any, r.Destination.Network, r.Destination.Address, r.Destination.Port, bool(r.Destination.Not)),
+			target:      r.Target,
+			description: r.Descr,
+		})
+	}
+}
+
+// vlans collects the VLAN devices.
+func (c *collector) vlans() {
+	for _, v := range c.doc.VLANs.VLAN {
+		object := "VLAN " + v.Tag + " on " + v.If
+
+		tag, err := strconv.Atoi(v.Tag)
+		if err != nil {
+			c.unsupported(object, "the tag is not a number")
+			continue
+		}
+
+		priority, err := strconv.Atoi(firstNonEmpty(v.PCP, "0"))
+		if err != nil {
+			c.unsupported(object, "the priority is not a number")
+			continue
+		}
+
+		c.inv.vlans = append(c.inv.vlans, vlan{
+			resource:    c.resourceName("vlan", v.Vlanif, v.If+"_"+v.Tag),
+			device:      v.Vlanif,
+			parent:      v.If,
+			tag:         tag,
+			priority:    priority,
+			description: v.Descr,
+		})
+	}
+}
+
+// unbound collects the Unbound host, alias and domain overrides.
+func (c *collector) unbound() {
+	unbound := c.doc.OPNsense.UnboundPlus
+	byUUID := make(map[string]int)
+
+	for _, h := range unbound.Hosts.Host {
+		name := fqdn(h.Hostname, h.Domain)
+		rr := strings.ToUpper(firstNonEmpty(h.RR, "A"))
+
+		if rr != "A" && rr != "AAAA" && rr != rrMailServer {
+			c.unsupported("host override "+name, "record type "+rr+" cannot be exported")
+			continue
+		}
+
+		override := hostOverride{
+			uuid:        h.UUID,
+			resource:    c.resourceName("host_override", name),
+			enabled:     h.Enabled != "0",
+			hostname:    h.Hostname,
+			domain:      h.Domain,
+			rr:          rr,
+			server:      h.Server,
+			mxHost:      h.MX,
+			description: h.Description,
+		}
+
+		if rr == rrMailServer {
+			priority, err := strconv.Atoi(h.MXPrio)
+			if err != nil {
+				c.unsupported("host override "+name, "the MX priority is not a number")
+				continue
+			}
+
+			override.mxPriority = priority
+		}
+
+		byUUID[h.UUID] = len(c.inv.hostOverrides)
+		c.inv.hostOverrides = append(c.inv.hostOverrides, override)
+	}
+
+	for _, a := range unbound.Aliases.Alias {
+		name := fqdn(a.Hostname, a.Domain)
+
+		i, ok := byUUID[a.Host]
+		if !ok || a.Host == "" {
+			c.unsupported("host alias "+name, "the host override it refers to is not exported")
+			continue
+		}
+
+		c.inv.hostAliases = append(c.inv.hostAliases, hostAlias{
+			resource:    c.resourceName("host_alias", name),
+			enabled:     a.Enabled != "0",
+			override:    &c.inv.hostOverrides[i],
+			hostname:    a.Hostname,
+			domain:      a.Domain,
+			description: a.Description,
+		})
+	}
+
+	for _, d := range unbound.Domains.Domain {
+		c.inv.domainOverrides = append(c.inv.domainOverrides, domainOverride{
+			resource:    c.resourceName("domain_override", d.Domain),
+			enabled:     d.Enabled != "0",
+			domain:      d.Domain,
+			server:      d.Server,
+			description: d.Description,
+		})
+	}
+}
+
+// interfaces collects the assigned interfaces, sorted by name.
+func (c *collector) interfaces() {
+	names := c.doc.Interfaces.Names()
+	slices.Sort(names)
+
+	for _, name := range names {
+		i, _ := c.doc.Interfaces.Get(name)
+		c.inv.interfaces = append(c.inv.interfaces, iface{name: name, device: i.If})
+	}
+}
+
+// endpoint returns the source or destination of a rule, with the names of aliases kept for
+// references.
+func (c *collector) endpoint(anySet, network, address, port string, not bool) endpoint {
+	net := firstNonEmpty(network, address)
+	if anySet != "" || net == "" {
+		net = anyValue
+	}
+
+	return endpoint{net: net, port: port, invert: not}
+}
+
+// ruleObject identifies a rule by its position, interfaces and description.
+func ruleObject(kind string, i int, interfaces model.InterfaceList, descr string) string {
+	object := kind + " " + strconv.Itoa(i+1)
+
+	if !interfaces.IsEmpty() {
+		object += " on " + interfaces.String()
+	}
+
+	if descr != "" {
+		object += " " + strconv.Quote(descr)
+	}
+
+	return object
+}
+
+// fqdn joins a host name and a domain.
+func fqdn(hostname, domain string) string {
+	if hostname == "" {
+		return domain
+	}
+
+	return hostname + "." + domain
+}
+
+// nonIdentifier matches the runs of characters that resource names cannot contain.
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9]+`) //nolint:gochecknoglobals // Compiled pattern
+
+// slug returns a resource name derived from a text: lower case letters, digits and underscores,
+// starting with a letter or an underscore.
+func slug(text string) string {
+	name := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(text), "_"), "_")
+
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// isTrue reports whether a flag of the configuration is set.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "yes", "on", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
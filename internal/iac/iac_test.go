@@ -0,0 +1,141 @@
+package iac
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the generated exports.
+var update = flag.Bool("update", false, "update the golden files") //nolint:gochecknoglobals // Test flag
+
+// load parses a configuration.
+func load(t *testing.T, path string) *model.OpnSenseDocument {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	doc, err := parser.NewXMLParser().Parse(t.Context(), file)
+	require.NoError(t, err)
+
+	return doc
+}
+
+// assertGolden compares generated content with a golden file in the testdata directory.
+func assertGolden(t *testing.T, name, content string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test with -update to create the golden file")
+	assert.Equal(t, string(want), content)
+}
+
+func TestGolden(t *testing.T) {
+	configs := map[string]string{
+		"iac":      filepath.Join("testdata", "iac.xml"),
+		"sample.2": filepath.Join("..", "..", "testdata", "sample.config.2.xml"),
+	}
+
+	for name, path := range configs {
+		t.Run(name, func(t *testing.T) {
+			doc := load(t, path)
+
+			tf, err := Terraform(doc)
+			require.NoError(t, err)
+			assertGolden(t, name+".tf", tf.Content)
+
+			vars, err := Ansible(doc)
+			require.NoError(t, err)
+			assertGolden(t, name+".yml", vars.Content)
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	path := filepath.Join("testdata", "iac.xml")
+
+	first, err := Terraform(load(t, path))
+	require.NoError(t, err)
+
+	for range 5 {
+		again, err := Terraform(load(t, path))
+		require.NoError(t, err)
+		assert.Equal(t, first.Content, again.Content)
+	}
+}
+
+func TestTerraform_Unsupported(t *testing.T) {
+	result, err := Terraform(load(t, filepath.Join("testdata", "iac.xml")))
+	require.NoError(t, err)
+
+	var objects []string
+	for _, u := range result.Unsupported {
+		objects = append(objects, u.Object)
+		assert.Contains(t, result.Content, "#   "+u.String())
+	}
+
+	assert.Equal(t, []string{
+		"alias __lan_network",
+		`filter rule 3 on lan,opt1 "Allow web traffic"`,
+		"automatic outbound NAT",
+		"host override spf.example.net",
+		"host alias txt.example.net",
+		`outbound NAT rule 1 on wan "Administrators leave through their own address"`,
+		"interface lan (igb1)",
+		"interface opt1 (vlan01)",
+		"interface wan (igb0)",
+	}, objects)
+}
+
+func TestAnsible_Unsupported(t *testing.T) {
+	result, err := Ansible(load(t, filepath.Join("testdata", "iac.xml")))
+	require.NoError(t, err)
+
+	for _, u := range result.Unsupported {
+		assert.NotContains(t, u.Object, "outbound NAT rule", "the collection manages source NAT rules")
+	}
+
+	assert.Contains(t, result.Content, "opnsense_source_nat:")
+}
+
+func TestTerraform_Nil(t *testing.T) {
+	_, err := Terraform(nil)
+	require.ErrorIs(t, err, converter.ErrNilOpnSenseDocument)
+
+	_, err = Ansible(nil)
+	require.ErrorIs(t, err, converter.ErrNilOpnSenseDocument)
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Allow web traffic": "allow_web_traffic",
+		"  --LAN--  ":       "lan",
+		"10.0.0.0/8":        "_10_0_0_0_8",
+		"www.example.net":   "www_example_net",
+		"":                  "",
+	}
+
+	for text, want := range tests {
+		assert.Equal(t, want, slug(text), text)
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	assert.Equal(t, `"Ports of $${service} and %%{x} \"quoted\"\n"`, hclString("Ports of ${service} and %{x} \"quoted\"\n"))
+}
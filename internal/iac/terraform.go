@@ -0,0 +1,269 @@
+package iac
+
+import (
+	"strconv"
+	"strings"
+)
+
+// terraformProvider is the registry source of the community OPNsense provider.
+const terraformProvider = "browningluke/opnsense"
+
+// attribute is an attribute of a Terraform block: a rendered expression or a nested object.
+type attribute struct {
+	name   string
+	value  string
+	object []attribute
+}
+
+// renderTerraform renders an inventory as Terraform resources. Outbound NAT rules and interface
+// assignments have no resources in the provider and are reported as unsupported.
+func renderTerraform(inv *inventory) *Result {
+	unsupported := append([]Unsupported(nil), inv.unsupported...)
+
+	for _, r := range inv.outbound {
+		unsupported = append(unsupported, Unsupported{
+			Object: r.object,
+			Reason: "the provider has no resource for outbound NAT rules",
+		})
+	}
+
+	for _, i := range inv.interfaces {
+		unsupported = append(unsupported, Unsupported{
+			Object: "interface " + i.name + " (" + i.device + ")",
+			Reason: "the provider does not manage interface assignments",
+		})
+	}
+
+	var b strings.Builder
+
+	writeReport(&b, inv, "the "+terraformProvider+" provider", unsupported)
+
+	b.WriteString("\nterraform {\n")
+	b.WriteString("  required_providers {\n")
+	b.WriteString("    opnsense = {\n")
+	b.WriteString("      source = " + hclString(terraformProvider) + "\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	for _, a := range inv.aliases {
+		writeResource(&b, "opnsense_firewall_alias", a.resource, []attribute{
+			{name: "enabled", value: hclBool(a.enabled)},
+			{name: "name", value: hclString(a.name)},
+			{name: "type", value: hclString(a.kind)},
+			{name: "content", value: hclList(a.content)},
+			{name: "description", value: hclString(a.description)},
+		})
+	}
+
+	for _, r := range inv.rules {
+		writeResource(&b, "opnsense_firewall_filter", r.resource, []attribute{
+			{name: "enabled", value: hclBool(r.enabled)},
+			{name: "sequence", value: strconv.Itoa(r.sequence)},
+			{name: "action", value: hclString(r.action)},
+			{name: "quick", value: hclBool(r.quick)},
+			{name: "interface", value: hclList(r.interfaces)},
+			{name: "direction", value: hclString(r.direction)},
+			{name: "ip_protocol", value: hclString(r.ipProtocol)},
+			{name: "protocol", value: hclString(providerProtocol(r.protocol))},
+			{name: "source", object: inv.terraformEndpoint(r.source)},
+			{name: "destination", object: inv.terraformEndpoint(r.destination)},
+			{name: "log", value: hclBool(r.log)},
+			{name: "description", value: hclString(r.description)},
+		})
+	}
+
+	for _, r := range inv.portForwards {
+		writeResource(&b, "opnsense_firewall_nat", r.resource, []attribute{
+			{name: "enabled", value: hclBool(r.enabled)},
+			{name: "sequence", value: strconv.Itoa(r.sequence)},
+			{name: "interface", value: hclString(r.iface)},
+			{name: "ip_protocol", value: hclString(r.ipProtocol)},
+			{name: "protocol", value: hclString(providerProtocol(r.protocol))},
+			{name: "source", object: inv.terraformEndpoint(r.source)},
+			{name: "destination", object: inv.terraformEndpoint(r.destination)},
+			{name: "target", object: []attribute{
+				{name: "ip", value: inv.terraformReference(r.targetIP)},
+				{name: "port", value: inv.terraformReference(r.targetPort)},
+			}},
+			{name: "description", value: hclString(r.description)},
+		})
+	}
+
+	for _, v := range inv.vlans {
+		attributes := []attribute{
+			{name: "description", value: hclString(v.description)},
+			{name: "tag", value: strconv.Itoa(v.tag)},
+			{name: "priority", value: strconv.Itoa(v.priority)},
+			{name: "parent", value: hclString(v.parent)},
+		}
+
+		if v.device != "" {
+			attributes = append(attributes, attribute{name: "device", value: hclString(v.device)})
+		}
+
+		writeResource(&b, "opnsense_interfaces_vlan", v.resource, attributes)
+	}
+
+	for _, h := range inv.hostOverrides {
+		attributes := []attribute{
+			{name: "enabled", value: hclBool(h.enabled)},
+			{name: "type", value: hclString(h.rr)},
+			{name: "hostname", value: hclString(h.hostname)},
+			{name: "domain", value: hclString(h.domain)},
+		}
+
+		if h.rr == rrMailServer {
+			attributes = append(attributes,
+				attribute{name: "mx_priority", value: strconv.Itoa(h.mxPriority)},
+				attribute{name: "mx_host", value: hclString(h.mxHost)})
+		} else {
+			attributes = append(attributes, attribute{name: "server", value: hclString(h.server)})
+		}
+
+		attributes = append(attributes, attribute{name: "description", value: hclString(h.description)})
+
+		writeResource(&b, "opnsense_unbound_host_override", h.resource, attributes)
+	}
+
+	for _, a := range inv.hostAliases {
+		writeResource(&b, "opnsense_unbound_host_alias", a.resource, []attribute{
+			{name: "override", value: "opnsense_unbound_host_override." + a.override.resource + ".id"},
+			{name: "enabled", value: hclBool(a.enabled)},
+			{name: "hostname", value: hclString(a.hostname)},
+			{name: "domain", value: hclString(a.domain)},
+			{name: "description", value: hclString(a.description)},
+		})
+	}
+
+	for _, d := range inv.domainOverrides {
+		writeResource(&b, "opnsense_unbound_domain_override", d.resource, []attribute{
+			{name: "enabled", value: hclBool(d.enabled)},
+			{name: "domain", value: hclString(d.domain)},
+			{name: "server", value: hclString(d.server)},
+			{name: "description", value: hclString(d.description)},
+		})
+	}
+
+	return &Result{Content: b.String(), Unsupported: unsupported}
+}
+
+// terraformEndpoint returns the attributes of the source or destination of a rule.
+func (inv *inventory) terraformEndpoint(e endpoint) []attribute {
+	return []attribute{
+		{name: "net", value: inv.terraformReference(e.net)},
+		{name: "port", value: inv.terraformReference(e.port)},
+		{name: "invert", value: hclBool(e.invert)},
+	}
+}
+
+// terraformReference returns a value as a string, or as a reference to the name of an alias so
+// that Terraform creates the alias before the rules that use it.
+func (inv *inventory) terraformReference(value string) string {
+	if a, ok := inv.aliasByName[value]; ok {
+		return "opnsense_firewall_alias." + a.resource + ".name"
+	}
+
+	return hclString(value)
+}
+
+// writeReport writes the comments at the top of an export, with the objects it leaves out.
+func writeReport(b *strings.Builder, inv *inventory, target string, unsupported []Unsupported) {
+	source := "an OPNsense configuration"
+	if inv.hostname != "" {
+		source = inv.hostname
+	}
+
+	b.WriteString("# Generated by opnDossier from " + source + " for " + target + ".\n")
+	b.WriteString("# The filter and NAT rules become automation rules, which OPNsense evaluates\n")
+	b.WriteString("# before the rules of the interfaces; review them before applying the export.\n")
+
+	if len(unsupported) == 0 {
+		return
+	}
+
+	b.WriteString("#\n# Not exported:\n")
+
+	for _, u := range unsupported {
+		b.WriteString("#   " + commentLine(u.String()) + "\n")
+	}
+}
+
+// writeResource writes a resource block.
+func writeResource(b *strings.Builder, kind, name string, attributes []attribute) {
+	b.WriteString("\nresource " + hclString(kind) + " " + hclString(name) + " {\n")
+	writeAttributes(b, attributes, "  ")
+	b.WriteString("}\n")
+}
+
+// writeAttributes writes attributes with their equals signs aligned as terraform fmt does: across
+// consecutive single-line attributes, with nested objects ending the alignment.
+func writeAttributes(b *strings.Builder, attributes []attribute, indent string) {
+	for start := 0; start < len(attributes); {
+		if attributes[start].object != nil {
+			b.WriteString(indent + attributes[start].name + " = {\n")
+			writeAttributes(b, attributes[start].object, indent+"  ")
+			b.WriteString(indent + "}\n")
+
+			start++
+
+			continue
+		}
+
+		end, width := start, 0
+		for ; end < len(attributes) && attributes[end].object == nil; end++ {
+			width = max(width, len(attributes[end].name))
+		}
+
+		for _, a := range attributes[start:end] {
+			b.WriteString(indent + a.name + strings.Repeat(" ", width-len(a.name)) + " = " + a.value + "\n")
+		}
+
+		start = end
+	}
+}
+
+// providerProtocol returns a protocol as the provider spells it: upper case, except any.
+func providerProtocol(protocol string) string {
+	if strings.EqualFold(protocol, anyValue) {
+		return anyValue
+	}
+
+	return strings.ToUpper(protocol)
+}
+
+// hclString returns a quoted HCL string. Template sequences are escaped so that values are taken
+// literally.
+func hclString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// hclList returns a list of quoted HCL strings.
+func hclList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, hclString(value))
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclBool returns an HCL boolean.
+func hclBool(value bool) string {
+	return strconv.FormatBool(value)
+}
+
+// commentLine returns a text on a single line for a comment.
+func commentLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,233 @@
+# Generated by opnDossier from edge for the browningluke/opnsense provider.
+# The filter and NAT rules become automation rules, which OPNsense evaluates
+# before the rules of the interfaces; review them before applying the export.
+#
+# Not exported:
+#   alias __lan_network: internal aliases are maintained by OPNsense
+#   filter rule 3 on lan,opt1 "Allow web traffic": state type "sloppy state" is exported as keep state
+#   automatic outbound NAT: the rules that OPNsense generates are not exported
+#   host override spf.example.net: record type TXT cannot be exported
+#   host alias txt.example.net: the host override it refers to is not exported
+#   outbound NAT rule 1 on wan "Administrators leave through their own address": the provider has no resource for outbound NAT rules
+#   interface lan (igb1): the provider does not manage interface assignments
+#   interface opt1 (vlan01): the provider does not manage interface assignments
+#   interface wan (igb0): the provider does not manage interface assignments
+
+terraform {
+  required_providers {
+    opnsense = {
+      source = "browningluke/opnsense"
+    }
+  }
+}
+
+resource "opnsense_firewall_alias" "admin_nets" {
+  enabled     = false
+  name        = "admin_nets"
+  type        = "network"
+  content     = ["10.10.0.0/16"]
+  description = "Administrator networks"
+}
+
+resource "opnsense_firewall_alias" "web_ports" {
+  enabled     = true
+  name        = "web_ports"
+  type        = "port"
+  content     = ["80", "443"]
+  description = "Ports of $${service}"
+}
+
+resource "opnsense_firewall_alias" "web_servers" {
+  enabled     = true
+  name        = "web_servers"
+  type        = "host"
+  content     = ["192.168.1.10", "192.168.1.11"]
+  description = "Web servers"
+}
+
+resource "opnsense_firewall_filter" "block_admin_networks" {
+  enabled     = true
+  sequence    = 10
+  action      = "block"
+  quick       = false
+  interface   = []
+  direction   = "any"
+  ip_protocol = "inet46"
+  protocol    = "any"
+  source = {
+    net    = opnsense_firewall_alias.admin_nets.name
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  log         = false
+  description = "Block admin networks"
+}
+
+resource "opnsense_firewall_filter" "allow_web_traffic" {
+  enabled     = true
+  sequence    = 20
+  action      = "pass"
+  quick       = true
+  interface   = ["wan"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "TCP"
+  source = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = opnsense_firewall_alias.web_servers.name
+    port   = opnsense_firewall_alias.web_ports.name
+    invert = false
+  }
+  log         = true
+  description = "Allow web traffic"
+}
+
+resource "opnsense_firewall_filter" "allow_web_traffic_2" {
+  enabled     = true
+  sequence    = 30
+  action      = "reject"
+  quick       = true
+  interface   = ["lan", "opt1"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "TCP/UDP"
+  source = {
+    net    = "lan"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "lanip"
+    port   = "53"
+    invert = true
+  }
+  log         = false
+  description = "Allow web traffic"
+}
+
+resource "opnsense_firewall_filter" "rule_4" {
+  enabled     = false
+  sequence    = 40
+  action      = "pass"
+  quick       = true
+  interface   = ["lan"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "any"
+  source = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  log         = false
+  description = ""
+}
+
+resource "opnsense_firewall_nat" "https_to_the_web_server" {
+  enabled     = true
+  sequence    = 10
+  interface   = "wan"
+  ip_protocol = "inet"
+  protocol    = "TCP"
+  source = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "wanip"
+    port   = "443"
+    invert = false
+  }
+  target = {
+    ip   = opnsense_firewall_alias.web_servers.name
+    port = "8443"
+  }
+  description = "HTTPS to the web server"
+}
+
+resource "opnsense_firewall_nat" "https_to_the_web_server_2" {
+  enabled     = true
+  sequence    = 20
+  interface   = "opt1"
+  ip_protocol = "inet"
+  protocol    = "TCP"
+  source = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "wanip"
+    port   = "443"
+    invert = false
+  }
+  target = {
+    ip   = opnsense_firewall_alias.web_servers.name
+    port = "8443"
+  }
+  description = "HTTPS to the web server"
+}
+
+resource "opnsense_interfaces_vlan" "vlan01" {
+  description = "Guests"
+  tag         = 10
+  priority    = 0
+  parent      = "igb1"
+  device      = "vlan01"
+}
+
+resource "opnsense_interfaces_vlan" "igb1_20" {
+  description = "Voice \"VoIP\" phones"
+  tag         = 20
+  priority    = 5
+  parent      = "igb1"
+}
+
+resource "opnsense_unbound_host_override" "www_example_net" {
+  enabled     = true
+  type        = "A"
+  hostname    = "www"
+  domain      = "example.net"
+  server      = "192.168.1.10"
+  description = "Web server"
+}
+
+resource "opnsense_unbound_host_override" "example_net" {
+  enabled     = true
+  type        = "MX"
+  hostname    = ""
+  domain      = "example.net"
+  mx_priority = 10
+  mx_host     = "mail.example.net"
+  description = "Mail exchanger"
+}
+
+resource "opnsense_unbound_host_alias" "intranet_example_net" {
+  override    = opnsense_unbound_host_override.www_example_net.id
+  enabled     = true
+  hostname    = "intranet"
+  domain      = "example.net"
+  description = "Intranet"
+}
+
+resource "opnsense_unbound_domain_override" "corp_example" {
+  enabled     = true
+  domain      = "corp.example"
+  server      = "10.10.0.53"
+  description = "Corporate domain"
+}
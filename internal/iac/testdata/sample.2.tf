@@ -0,0 +1,113 @@
+# Generated by opnDossier from firewall for the browningluke/opnsense provider.
+# The filter and NAT rules become automation rules, which OPNsense evaluates
+# before the rules of the interfaces; review them before applying the export.
+#
+# Not exported:
+#   automatic outbound NAT: the rules that OPNsense generates are not exported
+#   interface lan (vtnet1): the provider does not manage interface assignments
+#   interface lo0 (lo0): the provider does not manage interface assignments
+#   interface opt0 (wg1): the provider does not manage interface assignments
+#   interface opt1 (vtnet2): the provider does not manage interface assignments
+#   interface opt2 (vtnet3): the provider does not manage interface assignments
+#   interface wan (vtnet0): the provider does not manage interface assignments
+#   interface wireguard (wireguard): the provider does not manage interface assignments
+
+terraform {
+  required_providers {
+    opnsense = {
+      source = "browningluke/opnsense"
+    }
+  }
+}
+
+resource "opnsense_firewall_filter" "rule_1" {
+  enabled     = true
+  sequence    = 10
+  action      = "pass"
+  quick       = true
+  interface   = ["wan"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "UDP"
+  source = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "wanip"
+    port   = "51821"
+    invert = false
+  }
+  log         = false
+  description = ""
+}
+
+resource "opnsense_firewall_filter" "default_allow_lan_to_any_rule" {
+  enabled     = true
+  sequence    = 20
+  action      = "pass"
+  quick       = true
+  interface   = ["lan"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "any"
+  source = {
+    net    = "lan"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  log         = false
+  description = "Default allow LAN to any rule"
+}
+
+resource "opnsense_firewall_filter" "default_allow_lan_ipv6_to_any_rule" {
+  enabled     = true
+  sequence    = 30
+  action      = "pass"
+  quick       = true
+  interface   = ["lan"]
+  direction   = "in"
+  ip_protocol = "inet6"
+  protocol    = "any"
+  source = {
+    net    = "lan"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "any"
+    port   = ""
+    invert = false
+  }
+  log         = false
+  description = "Default allow LAN IPv6 to any rule"
+}
+
+resource "opnsense_firewall_filter" "rule_4" {
+  enabled     = true
+  sequence    = 40
+  action      = "pass"
+  quick       = true
+  interface   = ["opt0"]
+  direction   = "in"
+  ip_protocol = "inet"
+  protocol    = "TCP"
+  source = {
+    net    = "opt0"
+    port   = ""
+    invert = false
+  }
+  destination = {
+    net    = "opt0ip"
+    port   = "443"
+    invert = false
+  }
+  log         = false
+  description = ""
+}
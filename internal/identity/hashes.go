@@ -0,0 +1,87 @@
+package identity
+
+import "strings"
+
+// HashScheme identifies the format of a stored password hash.
+type HashScheme string
+
+// Recognized password hash schemes.
+const (
+	HashNone    HashScheme = "none"
+	HashBcrypt  HashScheme = "bcrypt"
+	HashArgon2  HashScheme = "argon2"
+	HashSHA512  HashScheme = "sha512-crypt"
+	HashSHA256  HashScheme = "sha256-crypt"
+	HashMD5     HashScheme = "md5-crypt"
+	HashDES     HashScheme = "des-crypt"
+	HashUnknown HashScheme = "unknown"
+)
+
+// desHashLength is the length of a traditional DES crypt hash.
+const desHashLength = 13
+
+// HashStrength grades a hash scheme.
+type HashStrength int
+
+// Hash strength grades, from strongest to weakest. HashMissing grades accounts that store
+// no password hash at all.
+const (
+	HashStrong HashStrength = iota
+	HashLegacy
+	HashWeak
+	HashMissing
+)
+
+// ClassifyHash returns the scheme of a crypt(3)-style password hash.
+// Values that match no known format, including plaintext, are reported as HashUnknown.
+func ClassifyHash(hash string) HashScheme {
+	hash = strings.TrimSpace(hash)
+
+	switch {
+	case hash == "":
+		return HashNone
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return HashBcrypt
+	case strings.HasPrefix(hash, "$argon2"):
+		return HashArgon2
+	case strings.HasPrefix(hash, "$6$"):
+		return HashSHA512
+	case strings.HasPrefix(hash, "$5$"):
+		return HashSHA256
+	case strings.HasPrefix(hash, "$1$"):
+		return HashMD5
+	case len(hash) == desHashLength && isCryptAlphabet(hash):
+		return HashDES
+	default:
+		return HashUnknown
+	}
+}
+
+// Strength grades the scheme. bcrypt and Argon2 are strong, the SHA-2 based crypt
+// schemes are legacy, a missing hash is missing, and everything else is weak.
+func (s HashScheme) Strength() HashStrength {
+	switch s {
+	case HashNone:
+		return HashMissing
+	case HashBcrypt, HashArgon2:
+		return HashStrong
+	case HashSHA512, HashSHA256:
+		return HashLegacy
+	case HashMD5, HashDES, HashUnknown:
+		return HashWeak
+	default:
+		return HashWeak
+	}
+}
+
+// isCryptAlphabet reports whether s only contains characters of the crypt(3) alphabet.
+func isCryptAlphabet(s string) bool {
+	for _, r := range s {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && r != '.' && r != '/' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		scheme   HashScheme
+		strength HashStrength
+	}{
+		{"empty", "", HashNone, HashMissing},
+		{"bcrypt 2y", "$2y$10$YRVoF4SgskIsrXOvOQjGieB9XqHPRra9R7d80B3BZdbY/j21TwBfS", HashBcrypt, HashStrong},
+		{"bcrypt 2b", "$2b$12$abcdefghijklmnopqrstuv", HashBcrypt, HashStrong},
+		{"argon2", "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA", HashArgon2, HashStrong},
+		{"sha512", "$6$rounds=5000$salt$hash", HashSHA512, HashLegacy},
+		{"sha256", "$5$salt$hash", HashSHA256, HashLegacy},
+		{"md5", "$1$salt$hash", HashMD5, HashWeak},
+		{"des", "abJnggxhB/yWI", HashDES, HashWeak},
+		{"plaintext", "hunter2", HashUnknown, HashWeak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := ClassifyHash(tt.hash)
+			assert.Equal(t, tt.scheme, scheme)
+			assert.Equal(t, tt.strength, scheme.Strength())
+		})
+	}
+}
@@ -0,0 +1,372 @@
+// Package identity analyses user, group, privilege and credential hygiene in
+// OPNsense configurations and builds the per-user privilege matrix.
+package identity
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Severity is the severity of an identity issue. The values match the processor severities.
+type Severity string
+
+// Severity levels used by identity issues.
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// PrivilegeAll is the OPNsense privilege granting access to every page.
+const PrivilegeAll = "page-all"
+
+// rootUID is the UID of the root account.
+const rootUID = "0"
+
+// authServerTOTP is the authentication server type for TOTP.
+const authServerTOTP = "totp"
+
+// Issue is a single identity hygiene problem.
+type Issue struct {
+	Severity       Severity
+	Title          string
+	Description    string
+	Component      string
+	Recommendation string
+
+	// Object identifies the user, group or API key independently of its position.
+	Object string
+}
+
+// Analysis is the result of analysing the identities of a configuration.
+type Analysis struct {
+	// Users is the privilege matrix, one row per user in configuration order.
+	Users []model.UserPrivileges
+	// Issues lists the detected hygiene problems.
+	Issues []Issue
+}
+
+// Analyze inspects users, groups and API keys of the configuration.
+// A nil configuration yields an empty analysis.
+func Analyze(cfg *model.OpnSenseDocument) *Analysis {
+	analysis := &Analysis{}
+	if cfg == nil {
+		return analysis
+	}
+
+	groups := cfg.System.Group
+	totp := hasTOTPServer(cfg.System.AuthServer)
+
+	for i, user := range cfg.System.User {
+		row := buildRow(user, groups)
+		analysis.Users = append(analysis.Users, row)
+
+		component := fmt.Sprintf("system.user[%d]", i)
+		analysis.checkUser(user, row, component, totp, cfg.System.NextUID)
+		analysis.checkAPIKeys(user, row, component)
+	}
+
+	analysis.checkGroups(cfg.System.Group, cfg.System.User, cfg.System.NextGID)
+
+	return analysis
+}
+
+// Admins returns the enabled users with full administrative privileges.
+func (a *Analysis) Admins() []model.UserPrivileges {
+	var admins []model.UserPrivileges
+
+	for _, user := range a.Users {
+		if user.Enabled && user.Admin {
+			admins = append(admins, user)
+		}
+	}
+
+	return admins
+}
+
+// userObject returns the object ID of a user, which is identified by its name.
+func userObject(user model.User) string {
+	return "system.user/" + user.Name
+}
+
+func (a *Analysis) add(issue Issue) {
+	a.Issues = append(a.Issues, issue)
+}
+
+// checkUser flags privilege, credential and account problems of a single user.
+func (a *Analysis) checkUser(user model.User, row model.UserPrivileges, component string, totp bool, nextUID int) {
+	if !row.Enabled {
+		return
+	}
+
+	if row.UID == rootUID || user.Name == "root" {
+		a.add(Issue{
+			Severity:       SeverityMedium,
+			Title:          "Root Account Enabled",
+			Description:    fmt.Sprintf("The root account %s is enabled", user.Name),
+			Component:      component,
+			Object:         userObject(user),
+			Recommendation: "Administer the firewall through named accounts and disable the root login",
+		})
+	} else if row.Admin {
+		a.add(Issue{
+			Severity:       SeverityLow,
+			Title:          "User Has Administrative Privileges",
+			Description:    fmt.Sprintf("User %s has the %s privilege", user.Name, PrivilegeAll),
+			Component:      component,
+			Object:         userObject(user),
+			Recommendation: "Confirm that full administrative access is required or grant narrower privileges",
+		})
+	}
+
+	a.checkPassword(user, component)
+
+	if totp && !row.OTP {
+		a.add(Issue{
+			Severity: SeverityMedium,
+			Title:    "User Without OTP",
+			Description: fmt.Sprintf(
+				"User %s has no OTP seed although a TOTP authentication server is configured",
+				user.Name,
+			),
+			Component:      component,
+			Object:         userObject(user),
+			Recommendation: "Enrol the user in TOTP and require the TOTP server for administrative logins",
+		})
+	}
+
+	if row.ShellAccess {
+		a.add(Issue{
+			Severity:       SeverityMedium,
+			Title:          "User Has Shell Access",
+			Description:    fmt.Sprintf("User %s has the login shell %s", user.Name, row.Shell),
+			Component:      component + ".shell",
+			Object:         userObject(user),
+			Recommendation: "Remove the login shell unless console or SSH access is required",
+		})
+	}
+
+	if uid, err := strconv.Atoi(row.UID); err == nil && nextUID > 0 && uid >= nextUID {
+		a.add(Issue{
+			Severity: SeverityMedium,
+			Title:    "UID Outside Allocation Range",
+			Description: fmt.Sprintf(
+				"User %s has UID %d, which is not below the next allocated UID %d",
+				user.Name, uid, nextUID,
+			),
+			Component:      component + ".uid",
+			Object:         userObject(user),
+			Recommendation: "Raise system.nextuid above the highest UID to prevent UID reuse",
+		})
+	}
+}
+
+// checkPassword flags missing, weak and legacy password hashes.
+func (a *Analysis) checkPassword(user model.User, component string) {
+	scheme := ClassifyHash(user.Password)
+
+	switch scheme.Strength() {
+	case HashMissing:
+		a.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "User Without Password Hash",
+			Description:    fmt.Sprintf("User %s is enabled but has no password hash stored", user.Name),
+			Component:      component + ".password",
+			Object:         userObject(user),
+			Recommendation: "Set a password for the account or disable it",
+		})
+	case HashWeak:
+		a.add(Issue{
+			Severity:       SeverityHigh,
+			Title:          "Weak Password Hash",
+			Description:    fmt.Sprintf("User %s has a password stored as %s", user.Name, scheme),
+			Component:      component + ".password",
+			Object:         userObject(user),
+			Recommendation: "Reset the password so that it is stored as a bcrypt hash",
+		})
+	case HashLegacy:
+		a.add(Issue{
+			Severity:       SeverityLow,
+			Title:          "Legacy Password Hash",
+			Description:    fmt.Sprintf("User %s has a password stored as %s instead of bcrypt", user.Name, scheme),
+			Component:      component + ".password",
+			Object:         userObject(user),
+			Recommendation: "Reset the password so that it is stored as a bcrypt hash",
+		})
+	case HashStrong:
+	}
+}
+
+// checkAPIKeys flags API keys with full privileges or without a description.
+func (a *Analysis) checkAPIKeys(user model.User, row model.UserPrivileges, component string) {
+	for j, key := range user.APIKeys {
+		keyComponent := fmt.Sprintf("%s.apikeys[%d]", component, j)
+
+		// Keys without their own privileges inherit those of the owner
+		privileges := splitList(key.Privileges + "," + key.Priv)
+		if len(privileges) == 0 {
+			privileges = row.Privileges
+		}
+
+		if row.Enabled && slices.Contains(privileges, PrivilegeAll) {
+			a.add(Issue{
+				Severity:       SeverityHigh,
+				Title:          "API Key With Full Privileges",
+				Description:    fmt.Sprintf("API key %s of user %s grants %s", maskKey(key.Key), user.Name, PrivilegeAll),
+				Component:      keyComponent,
+				Object:         userObject(user) + "/apikey:" + maskKey(key.Key),
+				Recommendation: "Issue API keys from a dedicated user limited to the required privileges",
+			})
+		}
+
+		if strings.TrimSpace(key.Description) == "" {
+			a.add(Issue{
+				Severity:       SeverityLow,
+				Title:          "API Key Without Description",
+				Description:    fmt.Sprintf("API key %s of user %s has no description", maskKey(key.Key), user.Name),
+				Component:      keyComponent,
+				Object:         userObject(user) + "/apikey:" + maskKey(key.Key),
+				Recommendation: "Describe the purpose and owner of every API key",
+			})
+		}
+	}
+}
+
+// checkGroups flags GIDs outside the allocation range and members that reference unknown UIDs.
+func (a *Analysis) checkGroups(groups []model.Group, users []model.User, nextGID int) {
+	uids := make(map[string]bool, len(users))
+	for _, user := range users {
+		uids[strings.TrimSpace(user.UID)] = true
+	}
+
+	for i, group := range groups {
+		component := fmt.Sprintf("system.group[%d]", i)
+
+		if gid, err := strconv.Atoi(strings.TrimSpace(group.Gid)); err == nil && nextGID > 0 && gid >= nextGID {
+			a.add(Issue{
+				Severity: SeverityMedium,
+				Title:    "GID Outside Allocation Range",
+				Description: fmt.Sprintf(
+					"Group %s has GID %d, which is not below the next allocated GID %d",
+					group.Name, gid, nextGID,
+				),
+				Component:      component + ".gid",
+				Object:         "system.group/" + group.Name,
+				Recommendation: "Raise system.nextgid above the highest GID to prevent GID reuse",
+			})
+		}
+
+		for _, member := range splitList(group.Member) {
+			if uids[member] {
+				continue
+			}
+
+			a.add(Issue{
+				Severity:       SeverityLow,
+				Title:          "Orphaned Group Member",
+				Description:    fmt.Sprintf("Group %s lists member UID %s, which matches no user", group.Name, member),
+				Component:      component + ".member",
+				Object:         "system.group/" + group.Name + "/member:" + member,
+				Recommendation: "Remove the stale member entry from the group",
+			})
+		}
+	}
+}
+
+// memberships returns the groups a user belongs to, either as primary group or as listed member.
+func memberships(user model.User, groups []model.Group) []model.Group {
+	var result []model.Group
+
+	for _, group := range groups {
+		if group.Name == user.Groupname || slices.Contains(splitList(group.Member), strings.TrimSpace(user.UID)) {
+			result = append(result, group)
+		}
+	}
+
+	return result
+}
+
+// buildRow assembles the privilege matrix row of a user.
+func buildRow(user model.User, groups []model.Group) model.UserPrivileges {
+	privileges := make([]string, 0, len(user.Priv))
+	for _, priv := range user.Priv {
+		privileges = append(privileges, splitList(priv)...)
+	}
+
+	var groupNames []string
+
+	for _, group := range memberships(user, groups) {
+		groupNames = append(groupNames, group.Name)
+		privileges = append(privileges, splitList(group.Priv)...)
+	}
+
+	slices.Sort(privileges)
+	privileges = slices.Compact(privileges)
+
+	shell := strings.TrimSpace(user.Shell)
+
+	return model.UserPrivileges{
+		Name:         user.Name,
+		UID:          strings.TrimSpace(user.UID),
+		Scope:        user.Scope,
+		Enabled:      !bool(user.Disabled),
+		Groups:       groupNames,
+		Privileges:   privileges,
+		Admin:        slices.Contains(privileges, PrivilegeAll),
+		ShellAccess:  isLoginShell(shell),
+		Shell:        shell,
+		OTP:          user.HasOTP(),
+		PasswordHash: string(ClassifyHash(user.Password)),
+		APIKeys:      len(user.APIKeys),
+	}
+}
+
+// hasTOTPServer reports whether a TOTP authentication server is configured.
+func hasTOTPServer(servers []model.AuthServer) bool {
+	for _, server := range servers {
+		if strings.EqualFold(strings.TrimSpace(server.Type), authServerTOTP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLoginShell reports whether shell grants interactive access.
+func isLoginShell(shell string) bool {
+	if shell == "" {
+		return false
+	}
+
+	switch path.Base(shell) {
+	case "nologin", "false":
+		return false
+	default:
+		return true
+	}
+}
+
+// splitList splits a comma or whitespace separated list, dropping empty values.
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// maskKeyVisible is the number of leading key characters shown in issue descriptions.
+const maskKeyVisible = 8
+
+// maskKey shortens an API key so that issue descriptions do not disclose it. Keys that are not
+// longer than maskKeyVisible are masked completely.
+func maskKey(key string) string {
+	if len(key) <= maskKeyVisible {
+		return "..."
+	}
+
+	return key[:maskKeyVisible] + "..."
+}
@@ -0,0 +1,196 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bcryptHash = "$2y$10$YRVoF4SgskIsrXOvOQjGieB9XqHPRra9R7d80B3BZdbY/j21TwBfS"
+
+func testConfig() *model.OpnSenseDocument {
+	return &model.OpnSenseDocument{
+		System: model.System{
+			NextUID: 2002,
+			NextGID: 2001,
+			Group: []model.Group{
+				{Name: "admins", Gid: "1999", Member: "0", Priv: "page-all"},
+				{Name: "operators", Gid: "2000", Member: "2000,2001", Priv: "page-diagnostics-ping"},
+			},
+			User: []model.User{
+				{Name: "root", UID: "0", Groupname: "admins", Password: bcryptHash, Shell: "/bin/csh"},
+				{
+					Name:     "alice",
+					UID:      "2000",
+					Password: bcryptHash,
+					OTPSeed:  "JBSWY3DPEHPK3PXP",
+					Priv:     []string{"page-all"},
+					Shell:    "/usr/sbin/nologin",
+				},
+				{
+					Name:     "bob",
+					UID:      "2001",
+					Password: "$1$salt$hash",
+					APIKeys:  []model.APIKey{{Key: "0123456789abcdef", Description: "monitoring"}},
+				},
+				{Name: "carol", UID: "2005", Disabled: true, Password: "plaintext"},
+			},
+		},
+	}
+}
+
+func titles(issues []Issue) []string {
+	result := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, issue.Title)
+	}
+
+	return result
+}
+
+func TestAnalyze_Matrix(t *testing.T) {
+	analysis := Analyze(testConfig())
+	require.Len(t, analysis.Users, 4)
+
+	root := analysis.Users[0]
+	assert.Equal(t, []string{"admins"}, root.Groups)
+	assert.Equal(t, []string{"page-all"}, root.Privileges)
+	assert.True(t, root.Admin)
+	assert.True(t, root.ShellAccess)
+	assert.Equal(t, "bcrypt", root.PasswordHash)
+
+	alice := analysis.Users[1]
+	assert.Equal(t, []string{"operators"}, alice.Groups)
+	assert.Equal(t, []string{"page-all", "page-diagnostics-ping"}, alice.Privileges)
+	assert.True(t, alice.Admin)
+	assert.False(t, alice.ShellAccess)
+	assert.True(t, alice.OTP)
+
+	bob := analysis.Users[2]
+	assert.False(t, bob.Admin)
+	assert.Equal(t, 1, bob.APIKeys)
+	assert.Equal(t, "md5-crypt", bob.PasswordHash)
+
+	assert.False(t, analysis.Users[3].Enabled)
+
+	admins := analysis.Admins()
+	require.Len(t, admins, 2)
+	assert.Equal(t, "root", admins[0].Name)
+	assert.Equal(t, "alice", admins[1].Name)
+}
+
+func TestAnalyze_Issues(t *testing.T) {
+	analysis := Analyze(testConfig())
+
+	assert.ElementsMatch(t, []string{
+		"Root Account Enabled",
+		"User Has Shell Access",
+		"User Has Administrative Privileges",
+		"Weak Password Hash",
+	}, titles(analysis.Issues))
+
+	// The disabled account is neither analysed for credentials nor for UID range
+	for _, issue := range analysis.Issues {
+		assert.NotContains(t, issue.Component, "system.user[3]")
+	}
+}
+
+func TestAnalyze_MissingPasswordHash(t *testing.T) {
+	cfg := testConfig()
+	cfg.System.User[1].Password = ""
+	cfg.System.User[3].Password = ""
+
+	var missing []Issue
+
+	for _, issue := range Analyze(cfg).Issues {
+		if issue.Title == "User Without Password Hash" {
+			missing = append(missing, issue)
+		}
+	}
+
+	require.Len(t, missing, 1, "only enabled accounts are reported")
+	assert.Equal(t, SeverityHigh, missing[0].Severity)
+	assert.Equal(t, "system.user[1].password", missing[0].Component)
+}
+
+func TestAnalyze_TOTPAndAllocation(t *testing.T) {
+	cfg := testConfig()
+	cfg.System.AuthServer = []model.AuthServer{{Name: "TOTP", Type: "totp"}}
+	cfg.System.NextUID = 2001
+	cfg.System.NextGID = 2000
+	cfg.System.Group[1].Member = "2000,2001,3000"
+
+	analysis := Analyze(cfg)
+	found := titles(analysis.Issues)
+
+	// root and bob lack OTP seeds; alice is enrolled
+	assert.Equal(t, 2, countTitle(found, "User Without OTP"))
+	assert.Contains(t, found, "UID Outside Allocation Range")
+	assert.Contains(t, found, "GID Outside Allocation Range")
+	assert.Contains(t, found, "Orphaned Group Member")
+}
+
+func TestAnalyze_APIKeys(t *testing.T) {
+	cfg := testConfig()
+	cfg.System.User[1].APIKeys = []model.APIKey{{Key: "abcdefghijklmnopqrstuvwxyz"}}
+	cfg.System.User[2].APIKeys = append(cfg.System.User[2].APIKeys, model.APIKey{
+		Key:         "zyxwvutsrqponmlk",
+		Privileges:  "page-all",
+		Description: "automation",
+	})
+
+	var apiIssues []Issue
+
+	for _, issue := range Analyze(cfg).Issues {
+		if issue.Title == "API Key With Full Privileges" || issue.Title == "API Key Without Description" {
+			apiIssues = append(apiIssues, issue)
+		}
+	}
+
+	require.Len(t, apiIssues, 3)
+	// alice's key inherits page-all and has no description
+	assert.Equal(t, "system.user[1].apikeys[0]", apiIssues[0].Component)
+	assert.Equal(t, SeverityHigh, apiIssues[0].Severity)
+	assert.Equal(t, "API key abcdefgh... of user alice grants page-all", apiIssues[0].Description)
+	assert.Equal(t, "API Key Without Description", apiIssues[1].Title)
+	// bob's second key is explicitly scoped to page-all
+	assert.Equal(t, "system.user[2].apikeys[1]", apiIssues[2].Component)
+}
+
+func TestMaskKey(t *testing.T) {
+	assert.Equal(t, "abcdefgh...", maskKey("abcdefghijklmnop"))
+	assert.Equal(t, "...", maskKey("abcdefgh"))
+	assert.Equal(t, "...", maskKey("abc"))
+}
+
+func TestAnalyze_Nil(t *testing.T) {
+	analysis := Analyze(nil)
+	assert.Empty(t, analysis.Users)
+	assert.Empty(t, analysis.Issues)
+}
+
+func TestMatrixTable(t *testing.T) {
+	table := MatrixTable(Analyze(testConfig()).Users)
+	require.Len(t, table.Rows, 4)
+	assert.Equal(t, "Privileges", table.Header[len(table.Header)-1])
+	assert.Equal(t, []string{
+		"root", "0", "Enabled", "admins", "Yes", "/bin/csh", "No", "bcrypt", "0", "page-all",
+	}, table.Rows[0])
+	assert.Equal(t, []string{
+		"carol", "2005", "Disabled", "-", "No", "-", "No", "unknown", "0", "-",
+	}, table.Rows[3])
+}
+
+func countTitle(titles []string, title string) int {
+	count := 0
+
+	for _, t := range titles {
+		if t == title {
+			count++
+		}
+	}
+
+	return count
+}
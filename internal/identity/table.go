@@ -0,0 +1,68 @@
+package identity
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/nao1215/markdown"
+)
+
+// MatrixTable returns the per-user privilege matrix as a markdown table.
+func MatrixTable(users []model.UserPrivileges) markdown.TableSet {
+	table := markdown.TableSet{
+		Header: []string{
+			"User", "UID", "Status", "Groups", "Admin", "Shell", "OTP", "Password Hash", "API Keys", "Privileges",
+		},
+		Rows: make([][]string, 0, len(users)),
+	}
+
+	for _, user := range users {
+		status := "Enabled"
+		if !user.Enabled {
+			status = "Disabled"
+		}
+
+		shell := "-"
+		if user.ShellAccess {
+			shell = user.Shell
+		}
+
+		table.Rows = append(table.Rows, []string{
+			escapeCell(user.Name),
+			escapeCell(user.UID),
+			status,
+			escapeCell(joinOrDash(user.Groups)),
+			yesNo(user.Admin),
+			escapeCell(shell),
+			yesNo(user.OTP),
+			user.PasswordHash,
+			strconv.Itoa(user.APIKeys),
+			escapeCell(joinOrDash(user.Privileges)),
+		})
+	}
+
+	return table
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// escapeCell escapes characters that would break a markdown table cell.
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
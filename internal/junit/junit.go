@@ -0,0 +1,122 @@
+// Package junit writes audit and validation results as JUnit XML test reports, which CI servers
+// such as Jenkins and GitLab render natively.
+//
+// Every input file becomes a test suite and every check or control a test case. A check that
+// reported problems fails with their description and recommendation; a check that was not
+// evaluated, such as one whose findings were all waived, is skipped.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// Report is the root element of a JUnit XML report.
+type Report struct {
+	XMLName  xml.Name `xml:"testsuites"`
+	Name     string   `xml:"name,attr,omitempty"`
+	Tests    int      `xml:"tests,attr"`
+	Failures int      `xml:"failures,attr"`
+	Errors   int      `xml:"errors,attr"`
+	Skipped  int      `xml:"skipped,attr"`
+	Suites   []Suite  `xml:"testsuite"`
+}
+
+// Suite is the test suite of an input file.
+type Suite struct {
+	Name       string      `xml:"name,attr"`
+	Tests      int         `xml:"tests,attr"`
+	Failures   int         `xml:"failures,attr"`
+	Errors     int         `xml:"errors,attr"`
+	Skipped    int         `xml:"skipped,attr"`
+	Timestamp  string      `xml:"timestamp,attr,omitempty"`
+	Properties *Properties `xml:"properties,omitempty"`
+	Cases      []Case      `xml:"testcase"`
+}
+
+// Properties describes a suite.
+type Properties struct {
+	Properties []Property `xml:"property"`
+}
+
+// Property is a name-value pair describing a suite, such as the audit mode.
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Case is the test case of a check or control.
+type Case struct {
+	Name      string   `xml:"name,attr"`
+	Classname string   `xml:"classname,attr"`
+	Failure   *Problem `xml:"failure,omitempty"`
+	Error     *Problem `xml:"error,omitempty"`
+	Skipped   *Skipped `xml:"skipped,omitempty"`
+	SystemOut *Output  `xml:"system-out,omitempty"`
+}
+
+// Problem is the failure or error of a test case. Failures are problems found by the check,
+// errors are problems that prevented the check from running.
+type Problem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+// Output is text written by a test case. It is kept as CDATA so that line breaks stay readable.
+type Output struct {
+	Text string `xml:",cdata"`
+}
+
+// Skipped marks a test case that was not evaluated.
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// NewReport returns an empty report.
+func NewReport(name string) *Report {
+	return &Report{Name: name, Suites: []Suite{}}
+}
+
+// AddCase adds a test case to the suite and updates its counters.
+func (s *Suite) AddCase(c Case) {
+	s.Cases = append(s.Cases, c)
+	s.Tests++
+
+	switch {
+	case c.Error != nil:
+		s.Errors++
+	case c.Failure != nil:
+		s.Failures++
+	case c.Skipped != nil:
+		s.Skipped++
+	}
+}
+
+// AddSuite adds a suite to the report and updates its counters.
+func (r *Report) AddSuite(s Suite) {
+	if s.Cases == nil {
+		s.Cases = []Case{}
+	}
+
+	r.Suites = append(r.Suites, s)
+	r.Tests += s.Tests
+	r.Failures += s.Failures
+	r.Errors += s.Errors
+	r.Skipped += s.Skipped
+}
+
+// Failed reports whether any test case failed or errored.
+func (r *Report) Failed() bool {
+	return r.Failures > 0 || r.Errors > 0
+}
+
+// ToXML returns the report as an indented XML document.
+func (r *Report) ToXML() (string, error) {
+	data, err := xml.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,45 @@
+package junit
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	suite := Suite{Name: "config.xml", Properties: &Properties{Properties: []Property{{Name: "mode", Value: "blue"}}}}
+	suite.AddCase(Case{Name: "FW-001", Classname: "sans"})
+	suite.AddCase(Case{Name: "FW-002", Classname: "sans", Failure: &Problem{Message: "open", Text: "line 1\nline 2 ]]> end"}})
+	suite.AddCase(Case{Name: "FW-003", Classname: "sans", Skipped: &Skipped{Message: "not applicable"}})
+	suite.AddCase(Case{Name: "FW-004", Classname: "sans", Error: &Problem{Message: "timeout"}})
+
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	assert.Equal(t, 1, suite.Errors)
+
+	report := NewReport("opnDossier")
+	assert.False(t, report.Failed())
+
+	report.AddSuite(suite)
+	report.AddSuite(Suite{Name: "empty.xml"})
+	assert.True(t, report.Failed())
+	assert.Equal(t, 4, report.Tests)
+
+	output, err := report.ToXML()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, xml.Header))
+	assert.Contains(t, output, `<testcase name="FW-001" classname="sans"></testcase>`)
+	assert.NotContains(t, output, "&#xA;", "line breaks of failure texts are kept")
+
+	var decoded Report
+	require.NoError(t, xml.Unmarshal([]byte(output), &decoded))
+	require.Len(t, decoded.Suites, 2)
+	assert.Equal(t, "line 1\nline 2 ]]> end", decoded.Suites[0].Cases[1].Failure.Text)
+	assert.Equal(t, "blue", decoded.Suites[0].Properties.Properties[0].Value)
+	assert.Nil(t, decoded.Suites[1].Properties)
+	assert.Empty(t, decoded.Suites[1].Cases)
+}
@@ -0,0 +1,323 @@
+// Package mapping relates compliance controls across frameworks.
+//
+// opnDossier ships an embedded catalogue that maps the controls of the compliance
+// plugins, keyed on their control ID, to NIST SP 800-53 and ISO/IEC 27001
+// identifiers and to the equivalent controls of the other plugins. Users can
+// supply their own catalogue in the same YAML format to override or extend it.
+package mapping
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error definitions for catalogue loading.
+var (
+	// ErrInvalidCatalogue indicates that a mapping catalogue is malformed.
+	ErrInvalidCatalogue = errors.New("invalid control mapping")
+	// ErrUnknownFramework indicates that a framework is not defined by the catalogue.
+	ErrUnknownFramework = errors.New("unknown framework")
+)
+
+//go:embed catalogue.yaml
+var defaultCatalogueYAML []byte
+
+// defaultCatalogue parses the embedded catalogue once.
+var defaultCatalogue = sync.OnceValue(func() *Catalogue { //nolint:gochecknoglobals // lazily parsed embedded data
+	catalogue, err := Parse(bytes.NewReader(defaultCatalogueYAML))
+	if err == nil {
+		err = catalogue.Validate()
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("embedded control mapping is invalid: %v", err))
+	}
+
+	return catalogue
+})
+
+// Framework is a target framework of the mapping, such as NIST SP 800-53 or a compliance plugin.
+type Framework struct {
+	ID   string `json:"id"   yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+}
+
+// Entry maps a single control to the identifiers of other frameworks, keyed by framework ID.
+type Entry struct {
+	Control    string              `yaml:"control"`
+	References map[string][]string `yaml:"references"`
+}
+
+// Catalogue is a named set of control mappings.
+type Catalogue struct {
+	Name       string      `yaml:"name"`
+	Frameworks []Framework `yaml:"frameworks"`
+	Controls   []Entry     `yaml:"controls"`
+}
+
+// Default returns the embedded opnDossier control mapping.
+// The returned catalogue is shared and must not be modified.
+func Default() *Catalogue {
+	return defaultCatalogue()
+}
+
+// Load reads a catalogue from a YAML file.
+func Load(path string) (*Catalogue, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control mapping: %w", err)
+	}
+	defer f.Close()
+
+	catalogue, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return catalogue, nil
+}
+
+// Parse decodes a catalogue in YAML format and checks its structure. Framework IDs are normalized
+// to lower case. References to frameworks that the catalogue does not define are allowed so that
+// an override file can extend the frameworks of the catalogue it is merged into; use Validate to
+// check them.
+func Parse(r io.Reader) (*Catalogue, error) {
+	var catalogue Catalogue
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&catalogue); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalogue, err)
+	}
+
+	frameworks := make(map[string]bool, len(catalogue.Frameworks))
+
+	for i := range catalogue.Frameworks {
+		framework := &catalogue.Frameworks[i]
+		framework.ID = normalizeID(framework.ID)
+
+		switch {
+		case framework.ID == "":
+			return nil, fmt.Errorf("%w: frameworks[%d] has no id", ErrInvalidCatalogue, i)
+		case frameworks[framework.ID]:
+			return nil, fmt.Errorf("%w: framework %s is defined more than once", ErrInvalidCatalogue, framework.ID)
+		}
+
+		frameworks[framework.ID] = true
+
+		if framework.Name == "" {
+			framework.Name = framework.ID
+		}
+	}
+
+	controls := make(map[string]bool, len(catalogue.Controls))
+
+	for i := range catalogue.Controls {
+		entry := &catalogue.Controls[i]
+		entry.Control = strings.TrimSpace(entry.Control)
+
+		switch {
+		case entry.Control == "":
+			return nil, fmt.Errorf("%w: controls[%d] has no control id", ErrInvalidCatalogue, i)
+		case controls[entry.Control]:
+			return nil, fmt.Errorf("%w: %s is listed more than once", ErrInvalidCatalogue, entry.Control)
+		}
+
+		controls[entry.Control] = true
+		entry.References = normalizeReferences(entry.References)
+	}
+
+	return &catalogue, nil
+}
+
+// Validate checks that every reference names a framework defined by the catalogue.
+func (c *Catalogue) Validate() error {
+	for _, entry := range c.Controls {
+		for framework := range entry.References {
+			if _, ok := c.Framework(framework); !ok {
+				return fmt.Errorf("%w: %s references unknown framework %q", ErrInvalidCatalogue, entry.Control, framework)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Merge returns a new catalogue with the frameworks and controls of the override applied on top of
+// the catalogue. Frameworks are matched by ID, and for a control listed in both catalogues the
+// references of each framework named by the override replace the original ones; an empty list
+// removes a mapping. The merged catalogue is validated.
+func (c *Catalogue) Merge(override *Catalogue) (*Catalogue, error) {
+	merged := &Catalogue{
+		Name:       c.Name,
+		Frameworks: slices.Clone(c.Frameworks),
+		Controls:   make([]Entry, 0, len(c.Controls)),
+	}
+
+	for _, entry := range c.Controls {
+		merged.Controls = append(merged.Controls, cloneEntry(entry))
+	}
+
+	if override == nil {
+		return merged, nil
+	}
+
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+
+	for _, framework := range override.Frameworks {
+		index := slices.IndexFunc(merged.Frameworks, func(f Framework) bool { return f.ID == framework.ID })
+		if index < 0 {
+			merged.Frameworks = append(merged.Frameworks, framework)
+		} else {
+			merged.Frameworks[index] = framework
+		}
+	}
+
+	for _, entry := range override.Controls {
+		index := slices.IndexFunc(merged.Controls, func(e Entry) bool { return e.Control == entry.Control })
+		if index < 0 {
+			merged.Controls = append(merged.Controls, cloneEntry(entry))
+			continue
+		}
+
+		for framework, refs := range entry.References {
+			merged.Controls[index].References[framework] = slices.Clone(refs)
+		}
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// Framework returns the framework with the given ID.
+func (c *Catalogue) Framework(id string) (Framework, bool) {
+	id = normalizeID(id)
+
+	for _, framework := range c.Frameworks {
+		if framework.ID == id {
+			return framework, true
+		}
+	}
+
+	return Framework{}, false
+}
+
+// FrameworkIDs returns the IDs of the frameworks in catalogue order.
+func (c *Catalogue) FrameworkIDs() []string {
+	ids := make([]string, 0, len(c.Frameworks))
+	for _, framework := range c.Frameworks {
+		ids = append(ids, framework.ID)
+	}
+
+	return ids
+}
+
+// References returns the identifiers a control maps to, keyed by framework ID. Frameworks without
+// identifiers are omitted and an unmapped control yields nil. The result may be modified.
+func (c *Catalogue) References(controlID string) map[string][]string {
+	controlID = strings.TrimSpace(controlID)
+	if c == nil || controlID == "" {
+		return nil
+	}
+
+	for _, entry := range c.Controls {
+		if entry.Control != controlID {
+			continue
+		}
+
+		refs := make(map[string][]string, len(entry.References))
+		for framework, ids := range entry.References {
+			if len(ids) > 0 {
+				refs[framework] = slices.Clone(ids)
+			}
+		}
+
+		if len(refs) == 0 {
+			return nil
+		}
+
+		return refs
+	}
+
+	return nil
+}
+
+// Format renders references as "framework: id, id; framework: id" in catalogue framework order.
+func (c *Catalogue) Format(refs map[string][]string) string {
+	parts := make([]string, 0, len(refs))
+
+	for _, framework := range c.orderedFrameworks(refs) {
+		parts = append(parts, framework+": "+strings.Join(refs[framework], ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// orderedFrameworks returns the framework IDs used by refs in catalogue order, followed by
+// undefined frameworks in alphabetical order.
+func (c *Catalogue) orderedFrameworks(refs map[string][]string) []string {
+	ordered := make([]string, 0, len(refs))
+
+	for _, framework := range c.Frameworks {
+		if _, ok := refs[framework.ID]; ok {
+			ordered = append(ordered, framework.ID)
+		}
+	}
+
+	for _, framework := range slices.Sorted(maps.Keys(refs)) {
+		if !slices.Contains(ordered, framework) {
+			ordered = append(ordered, framework)
+		}
+	}
+
+	return ordered
+}
+
+// normalizeID normalizes a framework ID.
+func normalizeID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// normalizeReferences normalizes the framework IDs and trims the identifiers of a reference map.
+func normalizeReferences(refs map[string][]string) map[string][]string {
+	normalized := make(map[string][]string, len(refs))
+
+	for framework, ids := range refs {
+		trimmed := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if id = strings.TrimSpace(id); id != "" {
+				trimmed = append(trimmed, id)
+			}
+		}
+
+		normalized[normalizeID(framework)] = trimmed
+	}
+
+	return normalized
+}
+
+// cloneEntry returns a deep copy of a catalogue entry.
+func cloneEntry(entry Entry) Entry {
+	references := make(map[string][]string, len(entry.References))
+	for framework, ids := range entry.References {
+		references[framework] = slices.Clone(ids)
+	}
+
+	return Entry{Control: entry.Control, References: references}
+}
@@ -0,0 +1,185 @@
+package mapping
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/cis"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/firewall"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/sans"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/stig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	catalogue := Default()
+	require.NotNil(t, catalogue)
+	require.NoError(t, catalogue.Validate())
+	assert.Equal(t, []string{"nist-800-53", "iso-27001", "stig", "sans", "firewall", "cis"}, catalogue.FrameworkIDs())
+	assert.Same(t, catalogue, Default(), "the embedded mapping is parsed once")
+
+	refs := catalogue.References("V-206694")
+	assert.Equal(t, []string{"AC-4", "SC-7(5)"}, refs["nist-800-53"])
+	assert.Equal(t, []string{"SANS-FW-001"}, refs["sans"])
+}
+
+func TestDefault_CoversPluginControls(t *testing.T) {
+	catalogue := Default()
+
+	plugins := []plugin.CompliancePlugin{stig.NewPlugin(), sans.NewPlugin(), firewall.NewPlugin(), cis.NewPlugin()}
+
+	for _, p := range plugins {
+		ids := make([]string, 0, len(p.GetControls()))
+		for _, control := range p.GetControls() {
+			ids = append(ids, control.ID)
+		}
+
+		for _, id := range ids {
+			refs := catalogue.References(id)
+			assert.NotEmpty(t, refs["nist-800-53"], "%s %s needs a NIST 800-53 mapping", p.Name(), id)
+			assert.NotEmpty(t, refs["iso-27001"], "%s %s needs an ISO 27001 mapping", p.Name(), id)
+
+			for framework, targets := range refs {
+				if _, ok := catalogue.Framework(framework); !ok || framework == "nist-800-53" || framework == "iso-27001" {
+					continue
+				}
+
+				for _, target := range targets {
+					assert.Contains(t, catalogue.References(target)[p.Name()], id,
+						"%s maps to %s %s, which must map back", id, framework, target)
+				}
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	catalogue, err := Parse(strings.NewReader(`
+name: custom
+frameworks:
+  - id: " PCI-DSS "
+controls:
+  - control: " CIS-MGMT-001 "
+    references:
+      PCI-DSS: [" 2.2.7 ", ""]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "pci-dss", catalogue.Frameworks[0].ID)
+	assert.Equal(t, "pci-dss", catalogue.Frameworks[0].Name, "the name defaults to the id")
+	assert.Equal(t, "CIS-MGMT-001", catalogue.Controls[0].Control)
+	assert.Equal(t, map[string][]string{"pci-dss": {"2.2.7"}}, catalogue.Controls[0].References)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errMsg string
+	}{
+		{name: "malformed", input: "controls: {", errMsg: "invalid control mapping"},
+		{name: "unknown field", input: "mappings: []", errMsg: "field mappings not found"},
+		{name: "framework without id", input: "frameworks:\n  - name: x", errMsg: "frameworks[0] has no id"},
+		{
+			name:   "duplicate framework",
+			input:  "frameworks:\n  - id: a\n  - id: A",
+			errMsg: "framework a is defined more than once",
+		},
+		{name: "control without id", input: "controls:\n  - references: {}", errMsg: "controls[0] has no control id"},
+		{
+			name:   "duplicate control",
+			input:  "controls:\n  - control: X-1\n  - control: X-1",
+			errMsg: "X-1 is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			require.ErrorIs(t, err, ErrInvalidCatalogue)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	override, err := Parse(strings.NewReader(`
+frameworks:
+  - id: pci-dss
+    name: PCI DSS v4.0
+  - id: nist-800-53
+    name: NIST 800-53
+controls:
+  - control: CIS-MGMT-001
+    references:
+      pci-dss: [2.2.7]
+      iso-27001: []
+  - control: CUSTOM-001
+    references:
+      nist-800-53: [CM-2]
+`))
+	require.NoError(t, err)
+
+	merged, err := Default().Merge(override)
+	require.NoError(t, err)
+
+	framework, ok := merged.Framework("NIST-800-53")
+	require.True(t, ok)
+	assert.Equal(t, "NIST 800-53", framework.Name)
+	assert.Contains(t, merged.FrameworkIDs(), "pci-dss")
+
+	refs := merged.References("CIS-MGMT-001")
+	assert.Equal(t, []string{"2.2.7"}, refs["pci-dss"])
+	assert.NotContains(t, refs, "iso-27001", "an empty list removes a mapping")
+	assert.Equal(t, Default().References("CIS-MGMT-001")["nist-800-53"], refs["nist-800-53"])
+	assert.Equal(t, []string{"CM-2"}, merged.References("CUSTOM-001")["nist-800-53"])
+
+	assert.NotEmpty(t, Default().References("CIS-MGMT-001")["iso-27001"], "the embedded mapping is not modified")
+	assert.NotContains(t, Default().FrameworkIDs(), "pci-dss")
+}
+
+func TestMerge_UnknownFramework(t *testing.T) {
+	override, err := Parse(strings.NewReader("controls:\n  - control: X-1\n    references:\n      pci-dss: [1.1]\n"))
+	require.NoError(t, err)
+
+	_, err = Default().Merge(override)
+	require.ErrorIs(t, err, ErrInvalidCatalogue)
+	assert.Contains(t, err.Error(), `X-1 references unknown framework "pci-dss"`)
+}
+
+func TestReferences(t *testing.T) {
+	catalogue := Default()
+
+	assert.Nil(t, catalogue.References("UNKNOWN-001"))
+	assert.Nil(t, catalogue.References(""))
+
+	var empty *Catalogue
+	assert.Nil(t, empty.References("V-206694"))
+
+	refs := catalogue.References("V-206694")
+	refs["nist-800-53"][0] = "changed"
+	assert.Equal(t, "AC-4", catalogue.References("V-206694")["nist-800-53"][0], "results are copies")
+}
+
+func TestFormat(t *testing.T) {
+	catalogue := Default()
+
+	assert.Equal(t, "nist-800-53: AC-4, SC-7; stig: V-206674; zz: 1",
+		catalogue.Format(map[string][]string{"zz": {"1"}, "stig": {"V-206674"}, "nist-800-53": {"AC-4", "SC-7"}}))
+	assert.Empty(t, catalogue.Format(nil))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("controls:\n  - control: X-1\n"), 0o600))
+
+	catalogue, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, catalogue.Controls, 1)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "failed to open control mapping")
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/EvilBit-Labs/opnDossier/internal/constants"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/charmbracelet/log"
 	"gopkg.in/yaml.v3"
@@ -400,6 +401,9 @@ func (g *markdownGenerator) Generate(ctx context.Context, cfg *model.OpnSenseDoc
 		return "", ErrNilConfiguration
 	}
 
+	enrichedCfg.ApplySecurityScore(opts.scoringEngine().Evaluate(cfg))
+	enrichedCfg.ApplyTunableBaseline(tunables.Compare(opts.tunableBaseline(), cfg.Sysctl))
+
 	// Add metadata for template rendering
 	metadata := struct {
 		*model.EnrichedOpnSenseDocument
@@ -498,7 +502,12 @@ func (g *markdownGenerator) generateJSON(
 	cfg *model.EnrichedOpnSenseDocument,
 	_ Options,
 ) (string, error) {
-	data, err := json.MarshalIndent(cfg, "", "  ") //nolint:musttag // EnrichedOpnSenseDocument has proper json tags
+	versioned := struct {
+		SchemaVersion string `json:"schemaVersion"`
+		*model.EnrichedOpnSenseDocument
+	}{constants.SchemaVersion, cfg}
+
+	data, err := json.MarshalIndent(versioned, "", "  ") //nolint:musttag // EnrichedOpnSenseDocument has proper json tags
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/EvilBit-Labs/opnDossier/internal/converter"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 )
 
 // HybridGenerator provides dual-mode support for markdown generation,
@@ -207,6 +208,9 @@ func (c *customTemplateGenerator) Generate(
 		return "", converter.ErrNilOpnSenseDocument
 	}
 
+	enrichedCfg.ApplySecurityScore(opts.scoringEngine().Evaluate(cfg))
+	enrichedCfg.ApplyTunableBaseline(tunables.Compare(opts.tunableBaseline(), cfg.Sysctl))
+
 	// Add metadata for template rendering
 	metadata := struct {
 		*model.EnrichedOpnSenseDocument
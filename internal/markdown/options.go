@@ -7,7 +7,12 @@ import (
 	"fmt"
 	"text/template"
 
+	"github.com/EvilBit-Labs/opnDossier/internal/baseline"
 	"github.com/EvilBit-Labs/opnDossier/internal/log"
+	"github.com/EvilBit-Labs/opnDossier/internal/mapping"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
 )
 
 // Format represents the output format type.
@@ -20,6 +25,28 @@ const (
 	FormatJSON Format = "json"
 	// FormatYAML represents YAML output format.
 	FormatYAML Format = "yaml"
+	// FormatHTML represents standalone HTML output, rendered from the markdown output.
+	FormatHTML Format = "html"
+	// FormatSARIF represents SARIF 2.1.0 output of audit findings.
+	FormatSARIF Format = "sarif"
+	// FormatJUnit represents JUnit XML output of audit findings for CI servers.
+	FormatJUnit Format = "junit"
+	// FormatCSV represents CSV tables of configuration objects and findings for spreadsheets.
+	FormatCSV Format = "csv"
+	// FormatXLSX represents an XLSX workbook of configuration objects and findings.
+	FormatXLSX Format = "xlsx"
+	// FormatDOT represents a Graphviz DOT diagram of the network topology.
+	FormatDOT Format = "dot"
+	// FormatSite represents a directory of linked markdown pages.
+	FormatSite Format = "site"
+	// FormatNftables represents an nftables ruleset translated from the filter and NAT rules.
+	FormatNftables Format = "nftables"
+	// FormatIptables represents an iptables-restore file translated from the filter and NAT rules.
+	FormatIptables Format = "iptables"
+	// FormatTerraform represents Terraform resources for the community OPNsense provider.
+	FormatTerraform Format = "terraform"
+	// FormatAnsible represents Ansible variables for the ansibleguy.opnsense collection.
+	FormatAnsible Format = "ansible"
 )
 
 // String returns the string representation of the format.
@@ -30,7 +57,8 @@ func (f Format) String() string {
 // Validate checks if the format is supported.
 func (f Format) Validate() error {
 	switch f {
-	case FormatMarkdown, FormatJSON, FormatYAML:
+	case FormatMarkdown, FormatJSON, FormatYAML, FormatHTML, FormatSARIF, FormatJUnit, FormatCSV, FormatXLSX, FormatDOT, FormatSite,
+		FormatNftables, FormatIptables, FormatTerraform, FormatAnsible:
 		return nil
 	default:
 		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, f)
@@ -86,7 +114,9 @@ func (a AuditMode) Validate() error {
 // Options contains configuration options for markdown generation.
 // Options contains configuration options for markdown generation.
 type Options struct {
-	// Format specifies the output format (markdown, json, yaml).
+	// Format specifies the output format (markdown, json, yaml, html, sarif, junit, csv, xlsx, dot).
+	// HTML is rendered by the htmlreport package from the markdown output; SARIF and JUnit are only
+	// available for audit reports. CSV and XLSX export the tables of the tabular package.
 	Format Format
 
 	// Comprehensive specifies whether to generate a comprehensive report.
@@ -145,6 +175,38 @@ type Options struct {
 	// When true, the system will use Go text/template rendering for markdown output.
 	// When false, the system will use programmatic markdown generation.
 	UseTemplateEngine bool
+
+	// ScoringEngine computes the security score from the findings of its analyzer. The default
+	// catalogue without an analyzer, which reports every control as not applicable, is used when nil.
+	ScoringEngine *scoring.Engine
+
+	// TunableBaseline is the sysctl hardening baseline. The embedded baseline is used when nil.
+	TunableBaseline *tunables.Baseline
+
+	// DeadRuleCheck adds the dead and unused firewall rule findings to audit reports. It is set
+	// when the rules were annotated with filterlog hits.
+	DeadRuleCheck bool
+
+	// ControlMapping relates compliance controls across frameworks. The embedded mapping is used when nil.
+	ControlMapping *mapping.Catalogue
+
+	// GroupByFramework groups audit results by the identifiers of a framework of the control mapping.
+	GroupByFramework string
+
+	// Waivers suppress accepted audit findings. No findings are waived when nil.
+	Waivers *waiver.Set
+
+	// Baseline is a previous audit report the findings are compared with. Findings are not
+	// classified when nil.
+	Baseline *baseline.Baseline
+
+	// SourceFile is the path of the configuration file. SARIF output locates findings in it and
+	// JUnit output names the test suite after it.
+	SourceFile string
+
+	// SeverityThreshold is the lowest finding severity that fails a JUnit test case. Every finding
+	// fails when empty.
+	SeverityThreshold string
 }
 
 // DefaultOptions returns an Options struct initialized with default settings for markdown generation.
@@ -217,6 +279,36 @@ func (o Options) WithFormat(format Format) Options {
 	return o
 }
 
+// WithScoringEngine sets the engine used to compute the security score.
+func (o Options) WithScoringEngine(engine *scoring.Engine) Options {
+	o.ScoringEngine = engine
+	return o
+}
+
+// scoringEngine returns the configured scoring engine or the default catalogue.
+func (o Options) scoringEngine() *scoring.Engine {
+	if o.ScoringEngine == nil {
+		return scoring.Default()
+	}
+
+	return o.ScoringEngine
+}
+
+// WithTunableBaseline sets the sysctl hardening baseline used for the tunable comparison.
+func (o Options) WithTunableBaseline(baseline *tunables.Baseline) Options {
+	o.TunableBaseline = baseline
+	return o
+}
+
+// tunableBaseline returns the configured baseline or the embedded default.
+func (o Options) tunableBaseline() *tunables.Baseline {
+	if o.TunableBaseline == nil {
+		return tunables.Default()
+	}
+
+	return o.TunableBaseline
+}
+
 // WithTemplate sets a custom template.
 func (o Options) WithTemplate(tmpl *template.Template) Options {
 	o.Template = tmpl
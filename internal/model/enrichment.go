@@ -34,8 +34,6 @@ const (
 	// MaxRulesThreshold is the threshold for too many rules.
 	MaxRulesThreshold = 100
 
-	// BaseSecurityScore is the base security score.
-	BaseSecurityScore = 50
 	// BaseResourceUsage is the base resource usage.
 	BaseResourceUsage = 50
 )
@@ -61,6 +59,39 @@ type EnrichedOpnSenseDocument struct {
 
 	// NAT Summary for prominent display
 	NATSummary *NATSummary `json:"natSummary,omitempty"`
+
+	// Security score breakdown, populated by ApplySecurityScore
+	SecurityScore *ScoreBreakdown `json:"securityScore,omitempty"`
+
+	// Sysctl hardening baseline comparison, populated by ApplyTunableBaseline
+	TunableBaseline []TunableCheck `json:"tunableBaseline,omitempty"`
+}
+
+// ApplySecurityScore records a security score breakdown on the enriched document and
+// propagates the resulting score to the statistics summary and security assessment.
+func (e *EnrichedOpnSenseDocument) ApplySecurityScore(breakdown *ScoreBreakdown) {
+	if e == nil || breakdown == nil {
+		return
+	}
+
+	e.SecurityScore = breakdown
+
+	if e.Statistics != nil {
+		e.Statistics.Summary.SecurityScore = breakdown.Score
+	}
+
+	if e.SecurityAssessment != nil {
+		e.SecurityAssessment.OverallScore = breakdown.Score
+	}
+}
+
+// ApplyTunableBaseline records the sysctl hardening baseline comparison on the enriched document.
+func (e *EnrichedOpnSenseDocument) ApplyTunableBaseline(checks []TunableCheck) {
+	if e == nil {
+		return
+	}
+
+	e.TunableBaseline = checks
 }
 
 // Statistics contains calculated statistics about the configuration.
@@ -297,7 +328,6 @@ func generateStatistics(cfg *OpnSenseDocument) *Statistics {
 			stats.DHCPScopes,
 			stats.LoadBalancerMonitors,
 		),
-		SecurityScore:       calculateSecurityScore(cfg, stats),
 		ConfigComplexity:    calculateConfigComplexity(stats),
 		HasSecurityFeatures: len(stats.SecurityFeatures) > 0,
 	}
@@ -654,9 +684,6 @@ func generateSecurityAssessment(cfg *OpnSenseDocument) *SecurityAssessment {
 		assessment.Recommendations = append(assessment.Recommendations, "Configure SSH access for remote management")
 	}
 
-	// Calculate overall score using shared helper
-	assessment.OverallScore = calculateSecurityScoreBase(cfg, nil)
-
 	return assessment
 }
 
@@ -714,57 +741,6 @@ func generateComplianceChecks(cfg *OpnSenseDocument) *ComplianceChecks {
 	return checks
 }
 
-// calculateSecurityScore computes a security score for the given OPNsense configuration.
-// The score is based on the presence of security features such as HTTPS web GUI and SSH access,
-// and is reduced for overly permissive firewall rules. The result is clamped between 0 and the maximum security score.
-func calculateSecurityScore(cfg *OpnSenseDocument, stats *Statistics) int {
-	score := calculateSecurityScoreBase(cfg, stats)
-
-	// Subtract points for security issues
-	if len(stats.RulesByType) > 0 {
-		// Check for overly permissive rules
-		rules := cfg.FilterRules()
-		for _, rule := range rules {
-			if rule.Type == RuleTypePass && rule.Source.Network == NetworkAny &&
-				rule.Destination.Network == NetworkAny {
-				score -= 10
-			}
-		}
-	}
-
-	if score < 0 {
-		score = 0
-	}
-
-	if score > MaxSecurityScore {
-		score = MaxSecurityScore
-	}
-
-	return score
-}
-
-// calculateSecurityScoreBase computes the base security score for the given OPNsense configuration.
-// This shared helper function centralizes the common security scoring logic used by both
-// generateSecurityAssessment and calculateSecurityScore functions.
-func calculateSecurityScoreBase(cfg *OpnSenseDocument, stats *Statistics) int {
-	score := BaseSecurityScore // Base score
-
-	// Add points for security features
-	if cfg.System.WebGUI.Protocol == ProtocolHTTPS {
-		score += 25
-	}
-
-	if cfg.System.SSH.Group != "" {
-		score += 25
-	}
-
-	if stats != nil && len(stats.SecurityFeatures) > 0 {
-		score += 15
-	}
-
-	return score
-}
-
 // calculateConfigComplexity returns a configuration complexity score based on the weighted sum of firewall rules, users, groups, services, gateways, and gateway groups, capped at the maximum allowed complexity score.
 func calculateConfigComplexity(stats *Statistics) int {
 	complexity := stats.TotalFirewallRules * RuleComplexityWeight
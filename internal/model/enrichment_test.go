@@ -27,9 +27,7 @@ func TestEnrichDocument(t *testing.T) {
 					WebGUI: WebGUIConfig{
 						Protocol: "https",
 					},
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{
+					SSH: SSHConfig{
 						Group: "admin",
 					},
 					User: []User{
@@ -135,7 +133,6 @@ func TestEnrichDocument(t *testing.T) {
 					ConsistencyIssues: []ConsistencyFinding{},
 				},
 				SecurityAssessment: &SecurityAssessment{
-					OverallScore:     100,
 					SecurityFeatures: []string{"HTTPS Web GUI", "SSH Access Configured"},
 					Vulnerabilities:  []string{},
 					Recommendations:  []string{},
@@ -177,8 +174,8 @@ func TestEnrichDocument(t *testing.T) {
 			assert.Equal(t, tt.expected.Statistics.TotalGroups, result.Statistics.TotalGroups)
 			assert.Equal(t, tt.expected.Statistics.TotalServices, result.Statistics.TotalServices)
 
-			// Test security assessment
-			assert.Equal(t, tt.expected.SecurityAssessment.OverallScore, result.SecurityAssessment.OverallScore)
+			// Test security assessment; the score itself is applied by the scoring engine
+			assert.Zero(t, result.SecurityAssessment.OverallScore)
 			assert.Len(
 				t,
 				result.SecurityAssessment.SecurityFeatures,
@@ -203,9 +200,7 @@ func TestGenerateStatistics(t *testing.T) {
 			WebGUI: WebGUIConfig{
 				Protocol: "https",
 			},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{
+			SSH: SSHConfig{
 				Group: "admin",
 			},
 		},
@@ -298,61 +293,69 @@ func TestGenerateAnalysis(t *testing.T) {
 
 func TestGenerateSecurityAssessment(t *testing.T) {
 	tests := []struct {
-		name     string
-		cfg      *OpnSenseDocument
-		expected int
+		name                    string
+		cfg                     *OpnSenseDocument
+		expectedFeatures        int
+		expectedVulnerabilities int
 	}{
 		{
 			name: "secure configuration",
 			cfg: &OpnSenseDocument{
 				System: System{
 					WebGUI: WebGUIConfig{Protocol: "https"},
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{Group: "admin"},
+					SSH:    SSHConfig{Group: "admin"},
 				},
 			},
-			expected: 100,
+			expectedFeatures:        2,
+			expectedVulnerabilities: 0,
 		},
 		{
 			name: "insecure configuration",
 			cfg: &OpnSenseDocument{
 				System: System{
 					WebGUI: WebGUIConfig{Protocol: "http"},
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{Group: ""},
+					SSH:    SSHConfig{Group: ""},
 				},
 			},
-			expected: 50,
+			expectedFeatures:        0,
+			expectedVulnerabilities: 2,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			assessment := generateSecurityAssessment(tt.cfg)
-			assert.Equal(t, tt.expected, assessment.OverallScore)
+			assert.Len(t, assessment.SecurityFeatures, tt.expectedFeatures)
+			assert.Len(t, assessment.Vulnerabilities, tt.expectedVulnerabilities)
 		})
 	}
 }
 
-func TestCalculateSecurityScore(t *testing.T) {
-	cfg := &OpnSenseDocument{
-		System: System{
-			WebGUI: WebGUIConfig{Protocol: "https"},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admin"},
+func TestEnrichedOpnSenseDocument_ApplySecurityScore(t *testing.T) {
+	enriched := EnrichDocument(&OpnSenseDocument{})
+	require.NotNil(t, enriched)
+
+	breakdown := &ScoreBreakdown{
+		Score:    75,
+		Earned:   30,
+		Possible: 40,
+		Items: []ScoreItem{
+			{ControlID: "A", Weight: 30, Points: 30, Status: ScoreStatusPass},
+			{ControlID: "B", Weight: 10, Status: ScoreStatusFail, Evidence: "missing"},
+			{ControlID: "C", Weight: 5, Status: ScoreStatusNotApplicable},
 		},
 	}
 
-	stats := &Statistics{
-		SecurityFeatures: []string{"https-web-gui", "ssh-access"},
-	}
+	enriched.ApplySecurityScore(breakdown)
+
+	assert.Same(t, breakdown, enriched.SecurityScore)
+	assert.Equal(t, 75, enriched.Statistics.Summary.SecurityScore)
+	assert.Equal(t, 75, enriched.SecurityAssessment.OverallScore)
+	assert.Equal(t, []ScoreItem{breakdown.Items[1]}, breakdown.Failed())
 
-	score := calculateSecurityScore(cfg, stats)
-	assert.GreaterOrEqual(t, score, 80)
-	assert.LessOrEqual(t, score, 100)
+	// A nil breakdown leaves the document untouched
+	enriched.ApplySecurityScore(nil)
+	assert.Same(t, breakdown, enriched.SecurityScore)
 }
 
 func TestCalculateConfigComplexity(t *testing.T) {
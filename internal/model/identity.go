@@ -0,0 +1,25 @@
+package model
+
+// UserPrivileges is a row of the user privilege matrix. It combines a user's own
+// privileges with those inherited from group membership.
+type UserPrivileges struct {
+	Name    string `json:"name"    yaml:"name"`
+	UID     string `json:"uid"     yaml:"uid"`
+	Scope   string `json:"scope"   yaml:"scope"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	// Groups lists the groups the user belongs to, by name.
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Privileges lists the effective privileges, sorted and de-duplicated.
+	Privileges []string `json:"privileges,omitempty" yaml:"privileges,omitempty"`
+	// Admin is true when the effective privileges grant full access.
+	Admin bool `json:"admin" yaml:"admin"`
+	// ShellAccess is true when the user has an interactive login shell.
+	ShellAccess bool   `json:"shellAccess"     yaml:"shellAccess"`
+	Shell       string `json:"shell,omitempty" yaml:"shell,omitempty"`
+	// OTP is true when the user has a TOTP seed enrolled.
+	OTP bool `json:"otp" yaml:"otp"`
+	// PasswordHash names the detected password hash scheme.
+	PasswordHash string `json:"passwordHash" yaml:"passwordHash"`
+	// APIKeys is the number of API keys owned by the user.
+	APIKeys int `json:"apiKeys" yaml:"apiKeys"`
+}
@@ -3,6 +3,7 @@ package model
 
 import (
 	"encoding/xml"
+	"strings"
 )
 
 // InterfaceGroups represents interface groups configuration.
@@ -36,7 +37,52 @@ type LAGGInterfaces struct {
 type VirtualIP struct {
 	XMLName xml.Name `xml:"virtualip"              json:"-"                 yaml:"-"`
 	Version string   `xml:"version,attr,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
-	Vip     string   `xml:"vip,omitempty"          json:"vip,omitempty"     yaml:"vip,omitempty"`
+	Vip     []VIP    `xml:"vip,omitempty"          json:"vip,omitempty"     yaml:"vip,omitempty"`
+}
+
+// VIPModeCARP is the mode of CARP virtual IPs.
+const VIPModeCARP = "carp"
+
+// VIP represents a single virtual IP address such as a CARP address, IP alias or proxy ARP entry.
+type VIP struct {
+	UUID       string `xml:"uuid,attr,omitempty"   json:"uuid,omitempty"       yaml:"uuid,omitempty"`
+	Mode       string `xml:"mode,omitempty"        json:"mode,omitempty"       yaml:"mode,omitempty"`
+	Interface  string `xml:"interface,omitempty"   json:"interface,omitempty"  yaml:"interface,omitempty"`
+	Subnet     string `xml:"subnet,omitempty"      json:"subnet,omitempty"     yaml:"subnet,omitempty"`
+	SubnetBits string `xml:"subnet_bits,omitempty" json:"subnetBits,omitempty" yaml:"subnetBits,omitempty"`
+	Type       string `xml:"type,omitempty"        json:"type,omitempty"       yaml:"type,omitempty"`
+	Vhid       string `xml:"vhid,omitempty"        json:"vhid,omitempty"       yaml:"vhid,omitempty"`
+	Advbase    string `xml:"advbase,omitempty"     json:"advbase,omitempty"    yaml:"advbase,omitempty"`
+	Advskew    string `xml:"advskew,omitempty"     json:"advskew,omitempty"    yaml:"advskew,omitempty"`
+	// Password is the CARP shared secret. It is not included in JSON or YAML exports.
+	Password string `xml:"password,omitempty" json:"-" yaml:"-"`
+	Gateway  string `xml:"gateway,omitempty"  json:"gateway,omitempty"  yaml:"gateway,omitempty"`
+	Noexpand string `xml:"noexpand,omitempty" json:"noexpand,omitempty" yaml:"noexpand,omitempty"`
+	Nobind   string `xml:"nobind,omitempty"   json:"nobind,omitempty"   yaml:"nobind,omitempty"`
+	Descr    string `xml:"descr,omitempty"    json:"descr,omitempty"    yaml:"descr,omitempty"`
+}
+
+// IsCARP reports whether the virtual IP is a CARP address.
+func (v VIP) IsCARP() bool {
+	return strings.EqualFold(strings.TrimSpace(v.Mode), VIPModeCARP)
+}
+
+// HasPassword reports whether a CARP password is set.
+func (v VIP) HasPassword() bool {
+	return strings.TrimSpace(v.Password) != ""
+}
+
+// CARP returns the CARP virtual IPs in configuration order.
+func (v VirtualIP) CARP() []VIP {
+	var carp []VIP
+
+	for _, vip := range v.Vip {
+		if vip.IsCARP() {
+			carp = append(carp, vip)
+		}
+	}
+
+	return carp
 }
 
 // PPPInterfaces represents PPP interface configuration.
@@ -215,6 +261,7 @@ type VLAN struct {
 	XMLName xml.Name `xml:"vlan"`
 	If      string   `xml:"if,omitempty"`
 	Tag     string   `xml:"tag,omitempty"`
+	PCP     string   `xml:"pcp,omitempty"`
 	Descr   string   `xml:"descr,omitempty"`
 	Vlanif  string   `xml:"vlanif,omitempty"`
 	Created string   `xml:"created,omitempty"`
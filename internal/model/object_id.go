@@ -0,0 +1,71 @@
+package model
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// objectDigestLength is the number of hex digits of a content digest.
+const objectDigestLength = 12
+
+// ObjectID identifies the firewall rule independently of its position in the rule list: by its
+// UUID, by its tracker ID or, for rules that have neither, by a digest of the fields that define
+// what the rule matches. Reordering rules does not change their object IDs.
+func (r *Rule) ObjectID() string {
+	switch {
+	case r.UUID != "":
+		return "filter.rule/" + r.UUID
+	case r.Tracker != "":
+		return "filter.rule/tracker:" + r.Tracker
+	}
+
+	return "filter.rule/" + objectDigest(
+		r.Type, r.Interface.String(), r.Direction, r.IPProtocol, r.Protocol,
+		r.Source.Any, endpointDigest(r.Source.Network, r.Source.Address, r.Source.Not),
+		cmp.Or(r.Source.Port, r.SourcePort),
+		r.Destination.Any, endpointDigest(r.Destination.Network, r.Destination.Address, r.Destination.Not),
+		r.Destination.Port,
+		r.Target, r.Descr,
+	)
+}
+
+// ObjectID identifies the port forward independently of its position, like Rule.ObjectID.
+func (r *InboundRule) ObjectID() string {
+	if r.UUID != "" {
+		return "nat.inbound/" + r.UUID
+	}
+
+	return "nat.inbound/" + objectDigest(
+		r.Interface.String(), r.IPProtocol, r.Protocol,
+		r.Source.Any, endpointDigest(r.Source.Network, r.Source.Address, r.Source.Not),
+		r.Destination.Any, endpointDigest(r.Destination.Network, r.Destination.Address, r.Destination.Not),
+		cmp.Or(r.ExternalPort, r.Destination.Port), r.InternalIP, r.InternalPort, r.Descr,
+	)
+}
+
+// endpointDigest returns the value that identifies a rule source or destination in a digest: its
+// network, or its address when it names no network, prefixed with "!" when it is inverted. Rules
+// that name neither an address nor an inversion keep the digest of their network alone.
+func endpointDigest(network, address string, not BoolFlag) string {
+	value := cmp.Or(network, address)
+
+	if not {
+		return "!" + value
+	}
+
+	return value
+}
+
+// objectDigest hashes the values that identify an object without a UUID.
+func objectDigest(values ...string) string {
+	hash := sha256.New()
+
+	for _, value := range values {
+		hash.Write([]byte(strings.TrimSpace(value)))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:objectDigestLength]
+}
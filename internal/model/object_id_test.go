@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_ObjectID(t *testing.T) {
+	withUUID := Rule{Type: "pass", UUID: "9f0c1d2e-0000-4000-8000-000000000001"}
+	assert.Equal(t, "filter.rule/9f0c1d2e-0000-4000-8000-000000000001", withUUID.ObjectID())
+
+	withTracker := Rule{Type: "pass", Tracker: "1700000001"}
+	assert.Equal(t, "filter.rule/tracker:1700000001", withTracker.ObjectID())
+
+	rule := Rule{Type: "pass", Interface: InterfaceList{"wan"}, Source: Source{Network: "any"}, Descr: "Allow any"}
+	same := rule
+	same.Disabled = "1"
+	same.Updated = &Updated{Time: "1700000000"}
+
+	other := rule
+	other.Destination = Destination{Port: "443"}
+
+	assert.Equal(t, rule.ObjectID(), same.ObjectID(), "state and history do not change the identity")
+	assert.NotEqual(t, rule.ObjectID(), other.ObjectID())
+
+	host := Rule{Type: "pass", Interface: InterfaceList{"lan"}, Source: Source{Address: "10.0.0.5"}}
+	otherHost := host
+	otherHost.Source.Address = "10.0.0.6"
+	inverted := host
+	inverted.Source.Not = true
+
+	assert.NotEqual(t, host.ObjectID(), otherHost.ObjectID(), "rules that differ by address differ")
+	assert.NotEqual(t, host.ObjectID(), inverted.ObjectID(), "an inverted source changes the identity")
+	assert.Regexp(t, `^filter\.rule/[0-9a-f]{12}$`, rule.ObjectID())
+}
+
+func TestInboundRule_ObjectID(t *testing.T) {
+	rule := InboundRule{Interface: InterfaceList{"wan"}, ExternalPort: "443", InternalIP: "192.168.1.10"}
+	other := rule
+	other.InternalIP = "192.168.1.11"
+
+	assert.NotEqual(t, rule.ObjectID(), other.ObjectID())
+
+	restricted := rule
+	restricted.Source = Source{Address: "198.51.100.7"}
+	assert.NotEqual(t, rule.ObjectID(), restricted.ObjectID())
+
+	withUUID := InboundRule{UUID: "abc"}
+	assert.Equal(t, "nat.inbound/abc", withUUID.ObjectID())
+}
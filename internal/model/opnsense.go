@@ -282,10 +282,10 @@ type OPNsense struct {
 			Text    string `xml:",chardata" json:"text,omitempty"`
 			Enabled string `xml:"enabled"`
 		} `xml:"forwarding" json:"forwarding"`
-		Dots    string `xml:"dots"`
-		Hosts   string `xml:"hosts"`
-		Aliases string `xml:"aliases"`
-		Domains string `xml:"domains"`
+		Dots    string             `xml:"dots"`
+		Hosts   UnboundHosts       `xml:"hosts"   json:"hosts"`
+		Aliases UnboundHostAliases `xml:"aliases" json:"aliases"`
+		Domains UnboundDomains     `xml:"domains" json:"domains"`
 	} `xml:"unboundplus" json:"unboundplus"`
 
 	// Legacy components removed - use dedicated structs from interfaces.go instead
@@ -397,6 +397,15 @@ func (o *OpnSenseDocument) FilterRules() []Rule {
 	return o.Filter.Rule
 }
 
+// FirewallAliases returns the firewall aliases, or nil when the configuration has no alias model.
+func (o *OpnSenseDocument) FirewallAliases() []FirewallAlias {
+	if o.OPNsense.Firewall == nil {
+		return nil
+	}
+
+	return o.OPNsense.Firewall.Alias.Aliases.Alias
+}
+
 // SystemConfig returns the system configuration grouped by functionality.
 // This groups system-level settings including core system configuration and sysctl tunables
 // into a single structured object for easier access and processing.
@@ -677,3 +677,50 @@ func TestOpnSenseDocumentModel_XMLCoverage(t *testing.T) {
 		})
 	}
 }
+
+func TestOpnSenseDocumentModel_FirewallAliases(t *testing.T) {
+	assert.Nil(t, (&OpnSenseDocument{}).FirewallAliases())
+
+	data := `<opnsense><OPNsense><Firewall><Alias version="1.0.1"><aliases>
+<alias uuid="a1"><enabled>1</enabled><name>web</name><type>host</type>
+<content>192.0.2.10
+ 192.0.2.11
+
+</content><description>Web servers</description></alias>
+</aliases></Alias></Firewall></OPNsense></opnsense>`
+
+	var doc OpnSenseDocument
+	require.NoError(t, xml.Unmarshal([]byte(data), &doc))
+
+	aliases := doc.FirewallAliases()
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "web", aliases[0].Name)
+	assert.Equal(t, "a1", aliases[0].UUID)
+	assert.Equal(t, []string{"192.0.2.10", "192.0.2.11"}, aliases[0].Entries())
+}
+
+func TestRuleEndpoints_IsAny(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+		any  bool
+	}{
+		{"any element", `<rule><source><any/></source><destination><any/></destination></rule>`, true},
+		{"any value", `<rule><source><any>1</any></source><destination><any>1</any></destination></rule>`, true},
+		{"no endpoint", `<rule><source/><destination/></rule>`, true},
+		{"any network", `<rule><source><network>any</network></source><destination><network>any</network></destination></rule>`, true},
+		{"interface network", `<rule><source><network>lan</network></source><destination><network>lanip</network></destination></rule>`, false},
+		{"address", `<rule><source><address>10.0.0.5</address></source><destination><address>10.0.1.5</address><port>22</port></destination></rule>`, false},
+		{"inverted any", `<rule><source><any/><not/></source><destination><any/><not>1</not></destination></rule>`, false},
+		{"inverted address", `<rule><source><address>10.0.0.5</address><not/></source><destination><address>10.0.1.5</address><not/></destination></rule>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule Rule
+			require.NoError(t, xml.Unmarshal([]byte(tt.xml), &rule))
+			assert.Equal(t, tt.any, rule.Source.IsAny())
+			assert.Equal(t, tt.any, rule.Destination.IsAny())
+		})
+	}
+}
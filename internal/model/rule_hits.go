@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// RuleHits contains usage statistics for a firewall rule derived from filterlog data.
+type RuleHits struct {
+	// Count is the number of log entries attributed to the rule.
+	Count int `json:"count" yaml:"count"`
+	// FirstSeen is the timestamp of the earliest attributed log entry.
+	FirstSeen time.Time `json:"firstSeen,omitzero" yaml:"firstSeen,omitempty"`
+	// LastSeen is the timestamp of the latest attributed log entry.
+	LastSeen time.Time `json:"lastSeen,omitzero" yaml:"lastSeen,omitempty"`
+	// TopTalkers lists the most frequent source addresses, ordered by hit count.
+	TopTalkers []Talker `json:"topTalkers,omitempty" yaml:"topTalkers,omitempty"`
+	// Unlogged reports that the rule does not log, so the log data cannot show whether it matched.
+	Unlogged bool `json:"unlogged,omitempty" yaml:"unlogged,omitempty"`
+}
+
+// Talker represents a source address and the number of log entries it generated.
+type Talker struct {
+	Address string `json:"address" yaml:"address"`
+	Count   int    `json:"count"   yaml:"count"`
+}
+
+// IsUnused reports whether the rule was covered by log data but never matched. Rules that do not
+// log are never unused: their coverage is unknown.
+func (h *RuleHits) IsUnused() bool {
+	return h != nil && !h.Unlogged && h.Count == 0
+}
+
+// IsCoverageUnknown reports whether the rule matched nothing in the log data because it does not log.
+func (h *RuleHits) IsCoverageUnknown() bool {
+	return h != nil && h.Unlogged && h.Count == 0
+}
@@ -0,0 +1,54 @@
+package model
+
+// ScoreStatus describes how a scoring control contributed to the security score.
+type ScoreStatus string
+
+const (
+	// ScoreStatusPass indicates the control passed and its full weight was awarded.
+	ScoreStatusPass ScoreStatus = "pass"
+	// ScoreStatusFail indicates the control failed and its weight was lost.
+	ScoreStatusFail ScoreStatus = "fail"
+	// ScoreStatusNotApplicable indicates the control did not apply and was excluded from the score.
+	ScoreStatusNotApplicable ScoreStatus = "not-applicable"
+)
+
+// ScoreBreakdown is an explainable security score. Every point gained or lost is
+// attributed to exactly one scoring control listed in Items.
+type ScoreBreakdown struct {
+	// Score is the normalized score from 0 to MaxSecurityScore.
+	Score int `json:"score" yaml:"score"`
+	// Earned is the sum of weights of passing controls.
+	Earned int `json:"earned" yaml:"earned"`
+	// Possible is the sum of weights of applicable controls.
+	Possible int `json:"possible" yaml:"possible"`
+	// Items lists the result of every evaluated control.
+	Items []ScoreItem `json:"items" yaml:"items"`
+}
+
+// ScoreItem is the contribution of a single scoring control.
+type ScoreItem struct {
+	ControlID string      `json:"controlId"          yaml:"controlId"`
+	Title     string      `json:"title"              yaml:"title"`
+	Category  string      `json:"category"           yaml:"category"`
+	Weight    int         `json:"weight"             yaml:"weight"`
+	Points    int         `json:"points"             yaml:"points"`
+	Status    ScoreStatus `json:"status"             yaml:"status"`
+	Evidence  string      `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+}
+
+// Failed returns the items of controls that failed, in evaluation order.
+func (b *ScoreBreakdown) Failed() []ScoreItem {
+	if b == nil {
+		return nil
+	}
+
+	var failed []ScoreItem
+
+	for _, item := range b.Items {
+		if item.Status == ScoreStatusFail {
+			failed = append(failed, item)
+		}
+	}
+
+	return failed
+}
@@ -2,6 +2,7 @@
 package model
 
 import (
+	"cmp"
 	"encoding/xml"
 	"slices"
 	"strings"
@@ -145,28 +146,116 @@ type Rule struct {
 	StateType   string        `xml:"statetype,omitempty"`
 	Direction   string        `xml:"direction,omitempty"`
 	Quick       string        `xml:"quick,omitempty"`
+	Floating    string        `xml:"floating,omitempty"`
 	Protocol    string        `xml:"protocol,omitempty"`
 	Source      Source        `xml:"source"`
 	Destination Destination   `xml:"destination"`
 	Target      string        `xml:"target,omitempty"`
 	SourcePort  string        `xml:"sourceport,omitempty"`
 	Disabled    string        `xml:"disabled,omitempty"`
+	Log         BoolFlag      `xml:"log,omitempty"`
+	Tracker     string        `xml:"tracker,omitempty"`
 	Updated     *Updated      `xml:"updated,omitempty"`
 	Created     *Created      `xml:"created,omitempty"`
 	UUID        string        `xml:"uuid,attr,omitempty"`
+
+	// Hits holds usage statistics attributed from offline filterlog data.
+	// It is nil unless the configuration was enriched with log files.
+	Hits *RuleHits `xml:"-" json:"hits,omitempty" yaml:"hits,omitempty"`
 }
 
 // Source represents a firewall rule source.
 type Source struct {
-	Any     string `xml:"any,omitempty"`
-	Network string `xml:"network,omitempty"`
+	Any     string   `xml:"any,omitempty"`
+	Network string   `xml:"network,omitempty"`
+	Address string   `xml:"address,omitempty"`
+	Port    string   `xml:"port,omitempty"`
+	Not     BoolFlag `xml:"not,omitempty"`
+}
+
+// IsAny returns true if the source matches every sender: it is marked any, names the any network
+// or names no network or address, and is not inverted.
+func (s Source) IsAny() bool {
+	return isAnyEndpoint(s.Any, s.Network, s.Address, s.Not)
+}
+
+// String returns the network or address of the source, "any" when it matches every sender, and
+// a "!" prefix when it is inverted.
+func (s Source) String() string {
+	return endpointString(s.Any, s.Network, s.Address, s.Not)
 }
 
 // Destination represents a firewall rule destination.
 type Destination struct {
-	Any     string `xml:"any,omitempty"`
-	Network string `xml:"network,omitempty"`
-	Port    string `xml:"port,omitempty"`
+	Any     string   `xml:"any,omitempty"`
+	Network string   `xml:"network,omitempty"`
+	Address string   `xml:"address,omitempty"`
+	Port    string   `xml:"port,omitempty"`
+	Not     BoolFlag `xml:"not,omitempty"`
+}
+
+// IsAny returns true if the destination matches every target, like Source.IsAny.
+func (d Destination) IsAny() bool {
+	return isAnyEndpoint(d.Any, d.Network, d.Address, d.Not)
+}
+
+// String returns the network or address of the destination, like Source.String.
+func (d Destination) String() string {
+	return endpointString(d.Any, d.Network, d.Address, d.Not)
+}
+
+// isAnyEndpoint reports whether a rule source or destination matches every address. An inverted
+// endpoint never does: "not any" matches nothing.
+func isAnyEndpoint(anySet, network, address string, not BoolFlag) bool {
+	if not {
+		return false
+	}
+
+	return anySet == "1" || network == NetworkAny || (network == "" && address == "")
+}
+
+// endpointString formats a rule source or destination.
+func endpointString(anySet, network, address string, not BoolFlag) string {
+	value := cmp.Or(network, address)
+
+	if anySet == "1" || value == "" {
+		value = NetworkAny
+	}
+
+	if not {
+		return "!" + value
+	}
+
+	return value
+}
+
+// FirewallAliases holds the firewall aliases of the MVC alias model.
+type FirewallAliases struct {
+	Alias []FirewallAlias `xml:"alias" json:"alias,omitempty" yaml:"alias,omitempty"`
+}
+
+// FirewallAlias represents a named list of hosts, networks or ports that rules reference by name.
+type FirewallAlias struct {
+	UUID        string `xml:"uuid,attr,omitempty" json:"uuid,omitempty"        yaml:"uuid,omitempty"`
+	Enabled     string `xml:"enabled"             json:"enabled"               yaml:"enabled"`
+	Name        string `xml:"name"                json:"name"                  yaml:"name"`
+	Type        string `xml:"type"                json:"type"                  yaml:"type"`
+	Proto       string `xml:"proto,omitempty"     json:"proto,omitempty"       yaml:"proto,omitempty"`
+	Content     string `xml:"content"             json:"content"               yaml:"content"`
+	Description string `xml:"description"         json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Entries returns the entries of the alias, which are stored one per line.
+func (a FirewallAlias) Entries() []string {
+	var entries []string
+
+	for entry := range strings.SplitSeq(a.Content, "\n") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
 }
 
 // Updated represents update information.
@@ -199,7 +288,7 @@ type Firewall struct {
 			Text string `xml:",chardata" json:"text,omitempty"`
 			URL  string `xml:"url"`
 		} `xml:"geoip" json:"geoip"`
-		Aliases string `xml:"aliases"`
+		Aliases FirewallAliases `xml:"aliases" json:"aliases"`
 	} `xml:"Alias"      json:"alias"`
 	Category struct {
 		Text       string `xml:",chardata" json:"text,omitempty"`
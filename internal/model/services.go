@@ -20,6 +20,53 @@ type Unbound struct {
 	Dnssecstripped string `xml:"dnssecstripped,omitempty" json:"dnssecstripped,omitempty" yaml:"dnssecstripped,omitempty"`
 }
 
+// UnboundHosts holds the host overrides of Unbound.
+type UnboundHosts struct {
+	Host []UnboundHostOverride `xml:"host" json:"host,omitempty" yaml:"host,omitempty"`
+}
+
+// UnboundHostOverride resolves a host name to an address or a mail exchanger.
+type UnboundHostOverride struct {
+	UUID        string `xml:"uuid,attr,omitempty" json:"uuid,omitempty"        yaml:"uuid,omitempty"`
+	Enabled     string `xml:"enabled"             json:"enabled"               yaml:"enabled"`
+	Hostname    string `xml:"hostname"            json:"hostname"              yaml:"hostname"`
+	Domain      string `xml:"domain"              json:"domain"                yaml:"domain"`
+	RR          string `xml:"rr"                  json:"rr"                    yaml:"rr"`
+	MXPrio      string `xml:"mxprio,omitempty"    json:"mxprio,omitempty"      yaml:"mxprio,omitempty"`
+	MX          string `xml:"mx,omitempty"        json:"mx,omitempty"          yaml:"mx,omitempty"`
+	Server      string `xml:"server"              json:"server"                yaml:"server"`
+	Description string `xml:"description"         json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// UnboundHostAliases holds the additional names of Unbound host overrides.
+type UnboundHostAliases struct {
+	Alias []UnboundHostAlias `xml:"alias" json:"alias,omitempty" yaml:"alias,omitempty"`
+}
+
+// UnboundHostAlias is an additional name of a host override, which it refers to by UUID.
+type UnboundHostAlias struct {
+	UUID        string `xml:"uuid,attr,omitempty" json:"uuid,omitempty"        yaml:"uuid,omitempty"`
+	Enabled     string `xml:"enabled"             json:"enabled"               yaml:"enabled"`
+	Host        string `xml:"host"                json:"host"                  yaml:"host"`
+	Hostname    string `xml:"hostname"            json:"hostname"              yaml:"hostname"`
+	Domain      string `xml:"domain"              json:"domain"                yaml:"domain"`
+	Description string `xml:"description"         json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// UnboundDomains holds the domain overrides of Unbound.
+type UnboundDomains struct {
+	Domain []UnboundDomainOverride `xml:"domain" json:"domain,omitempty" yaml:"domain,omitempty"`
+}
+
+// UnboundDomainOverride forwards the queries for a domain to another server.
+type UnboundDomainOverride struct {
+	UUID        string `xml:"uuid,attr,omitempty" json:"uuid,omitempty"        yaml:"uuid,omitempty"`
+	Enabled     string `xml:"enabled"             json:"enabled"               yaml:"enabled"`
+	Domain      string `xml:"domain"              json:"domain"                yaml:"domain"`
+	Server      string `xml:"server"              json:"server"                yaml:"server"`
+	Description string `xml:"description"         json:"description,omitempty" yaml:"description,omitempty"`
+}
+
 // Snmpd contains the SNMP daemon configuration.
 type Snmpd struct {
 	SysLocation string `xml:"syslocation"`
@@ -1,15 +1,57 @@
 // Package model defines the data structures for OPNsense configurations.
 package model
 
+import "strings"
+
 // WebGUIConfig represents the WebGUI configuration.
 type WebGUIConfig struct {
-	Protocol   string `xml:"protocol"              json:"protocol"             yaml:"protocol"             validate:"required,oneof=http https"`
-	SSLCertRef string `xml:"ssl-certref,omitempty" json:"sslCertRef,omitempty" yaml:"sslCertRef,omitempty"`
+	Protocol         string `xml:"protocol"                   json:"protocol"                   yaml:"protocol"                   validate:"required,oneof=http https"`
+	SSLCertRef       string `xml:"ssl-certref,omitempty"      json:"sslCertRef,omitempty"       yaml:"sslCertRef,omitempty"`
+	Port             string `xml:"port,omitempty"             json:"port,omitempty"             yaml:"port,omitempty"`
+	Interfaces       string `xml:"interfaces,omitempty"       json:"interfaces,omitempty"       yaml:"interfaces,omitempty"`
+	SessionTimeout   string `xml:"session_timeout,omitempty"  json:"sessionTimeout,omitempty"   yaml:"sessionTimeout,omitempty"`
+	NoDNSRebindCheck string `xml:"nodnsrebindcheck,omitempty" json:"noDnsRebindCheck,omitempty" yaml:"noDnsRebindCheck,omitempty"`
+}
+
+// DNSRebindCheckDisabled reports whether the DNS rebind protection of the web GUI is turned off.
+func (w WebGUIConfig) DNSRebindCheckDisabled() bool {
+	return isSet(w.NoDNSRebindCheck)
 }
 
 // SSHConfig represents the SSH configuration.
 type SSHConfig struct {
-	Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
+	Group           string `xml:"group"                     json:"group"                     yaml:"group"                     validate:"required"`
+	Enabled         string `xml:"enabled,omitempty"         json:"enabled,omitempty"         yaml:"enabled,omitempty"`
+	Port            string `xml:"port,omitempty"            json:"port,omitempty"            yaml:"port,omitempty"`
+	Interfaces      string `xml:"interfaces,omitempty"      json:"interfaces,omitempty"      yaml:"interfaces,omitempty"`
+	PermitRootLogin string `xml:"permitrootlogin,omitempty" json:"permitRootLogin,omitempty" yaml:"permitRootLogin,omitempty"`
+	PasswordAuth    string `xml:"passwordauth,omitempty"    json:"passwordAuth,omitempty"    yaml:"passwordAuth,omitempty"`
+}
+
+// IsEnabled reports whether the SSH daemon is enabled.
+func (s SSHConfig) IsEnabled() bool {
+	return isSet(s.Enabled)
+}
+
+// RootLoginPermitted reports whether root may log in over SSH.
+func (s SSHConfig) RootLoginPermitted() bool {
+	return isSet(s.PermitRootLogin)
+}
+
+// PasswordAuthEnabled reports whether SSH accepts password authentication.
+func (s SSHConfig) PasswordAuthEnabled() bool {
+	return isSet(s.PasswordAuth)
+}
+
+// isSet reports whether an OPNsense option value is switched on. OPNsense stores
+// options as "1", "yes", "on", "enabled" or "true"; anything else is off.
+func isSet(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "yes", "on", "enabled", "true":
+		return true
+	default:
+		return false
+	}
 }
 
 // SystemConfig groups system-related configuration.
@@ -54,6 +96,7 @@ type System struct {
 	DisableConsoleMenu            struct{}     `xml:"disableconsolemenu"            json:"disableConsoleMenu"                      yaml:"disableConsoleMenu,omitempty"`
 	NextUID                       int          `xml:"nextuid"                       json:"nextUid,omitempty"                       yaml:"nextUid,omitempty"`
 	NextGID                       int          `xml:"nextgid"                       json:"nextGid,omitempty"                       yaml:"nextGid,omitempty"`
+	AuthServer                    []AuthServer `xml:"authserver"                    json:"authServers,omitempty"                   yaml:"authServers,omitempty"`
 	PowerdACMode                  string       `xml:"powerd_ac_mode"                json:"powerdAcMode,omitempty"                  yaml:"powerdAcMode,omitempty"                  validate:"omitempty,oneof=hadp hiadp adaptive minimum maximum"`
 	PowerdBatteryMode             string       `xml:"powerd_battery_mode"           json:"powerdBatteryMode,omitempty"             yaml:"powerdBatteryMode,omitempty"             validate:"omitempty,oneof=hadp hiadp adaptive minimum maximum"`
 	PowerdNormalMode              string       `xml:"powerd_normal_mode"            json:"powerdNormalMode,omitempty"              yaml:"powerdNormalMode,omitempty"              validate:"omitempty,oneof=hadp hiadp adaptive minimum maximum"`
@@ -114,6 +157,7 @@ type Firmware struct {
 }
 
 // User represents a user.
+// The TOTP seed is a secret and is therefore excluded from JSON and YAML exports.
 type User struct {
 	Name           string   `xml:"name"           json:"name"                  yaml:"name"                     validate:"required,alphanum"`
 	Disabled       BoolFlag `xml:"disabled"       json:"disabled"              yaml:"disabled"`
@@ -126,7 +170,22 @@ type User struct {
 	Expires        struct{} `xml:"expires"        json:"expires"               yaml:"expires,omitempty"`
 	AuthorizedKeys struct{} `xml:"authorizedkeys" json:"authorizedKeys"        yaml:"authorizedKeys,omitempty"`
 	IPSecPSK       struct{} `xml:"ipsecpsk"       json:"ipsecPsk"              yaml:"ipsecPsk,omitempty"`
-	OTPSeed        struct{} `xml:"otp_seed"       json:"otpSeed"               yaml:"otpSeed,omitempty"`
+	OTPSeed        string   `xml:"otp_seed"       json:"-"                     yaml:"-"`
+	Priv           []string `xml:"priv"           json:"privileges,omitempty"  yaml:"privileges,omitempty"`
+	Shell          string   `xml:"shell"          json:"shell,omitempty"       yaml:"shell,omitempty"`
+}
+
+// HasOTP reports whether the user has a TOTP seed enrolled.
+func (u User) HasOTP() bool {
+	return strings.TrimSpace(u.OTPSeed) != ""
+}
+
+// AuthServer represents an authentication server (LDAP, RADIUS, TOTP, ...).
+type AuthServer struct {
+	Refid string `xml:"refid" json:"refid,omitempty" yaml:"refid,omitempty"`
+	Type  string `xml:"type"  json:"type"            yaml:"type"`
+	Name  string `xml:"name"  json:"name"            yaml:"name"`
+	Host  string `xml:"host"  json:"host,omitempty"  yaml:"host,omitempty"`
 }
 
 // APIKey represents a user API key.
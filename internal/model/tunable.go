@@ -0,0 +1,46 @@
+package model
+
+import "strings"
+
+// TunableStatus describes how a tunable compares with the hardening baseline.
+type TunableStatus string
+
+const (
+	// TunableCompliant indicates the effective value matches the baseline.
+	TunableCompliant TunableStatus = "compliant"
+	// TunableDeviation indicates the effective value differs from the baseline.
+	TunableDeviation TunableStatus = "deviation"
+	// TunableUnknown indicates the tunable is not configured and its default is unknown.
+	TunableUnknown TunableStatus = "unknown"
+)
+
+// TunableCheck is the comparison of a single tunable with its baseline expectation.
+type TunableCheck struct {
+	Tunable  string `json:"tunable"  yaml:"tunable"`
+	Expected string `json:"expected" yaml:"expected"`
+	// Actual is the configured value, empty when the tunable is not set in the configuration.
+	// OPNsense uses the literal value "default" for tunables left at the system default.
+	Actual string `json:"actual,omitempty" yaml:"actual,omitempty"`
+	// Default is the operating system default used when the tunable is not set.
+	Default   string        `json:"default,omitempty"   yaml:"default,omitempty"`
+	Status    TunableStatus `json:"status"              yaml:"status"`
+	Severity  string        `json:"severity"            yaml:"severity"`
+	Rationale string        `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+}
+
+// TunableDefaultValue is the value OPNsense stores for tunables left at the system default.
+const TunableDefaultValue = "default"
+
+// Configured reports whether the tunable is explicitly set to a value in the configuration.
+func (c TunableCheck) Configured() bool {
+	return c.Actual != "" && !strings.EqualFold(c.Actual, TunableDefaultValue)
+}
+
+// Effective returns the value in effect: the configured value or the default.
+func (c TunableCheck) Effective() string {
+	if c.Configured() {
+		return c.Actual
+	}
+
+	return c.Default
+}
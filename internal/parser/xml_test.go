@@ -67,6 +67,46 @@ func TestXMLParser_Parse(t *testing.T) {
 	}
 }
 
+func TestXMLParser_ParseVirtualIPs(t *testing.T) {
+	input := `<opnsense><system><hostname>fw1</hostname></system><virtualip version="1.0.0">
+  <vip uuid="5f1c">
+    <interface>lan</interface>
+    <mode>carp</mode>
+    <subnet>192.168.1.1</subnet>
+    <subnet_bits>24</subnet_bits>
+    <type>single</type>
+    <vhid>3</vhid>
+    <advbase>1</advbase>
+    <advskew>100</advskew>
+    <password>secret</password>
+    <descr>LAN CARP</descr>
+  </vip>
+  <vip><interface>wan</interface><mode>ipalias</mode><subnet>203.0.113.5</subnet></vip>
+</virtualip></opnsense>`
+
+	opnsense, err := NewXMLParser().Parse(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, opnsense.VirtualIP.Vip, 2)
+
+	carp := opnsense.VirtualIP.CARP()
+	require.Len(t, carp, 1)
+	assert.Equal(t, model.VIP{
+		UUID:       "5f1c",
+		Mode:       "carp",
+		Interface:  "lan",
+		Subnet:     "192.168.1.1",
+		SubnetBits: "24",
+		Type:       "single",
+		Vhid:       "3",
+		Advbase:    "1",
+		Advskew:    "100",
+		Password:   "secret",
+		Descr:      "LAN CARP",
+	}, carp[0])
+	assert.True(t, carp[0].HasPassword())
+	assert.False(t, opnsense.VirtualIP.Vip[1].IsCARP())
+}
+
 // TestXMLParser_ParseSampleFiles tests parsing of real config.xml sample files.
 func TestXMLParser_ParseSampleFiles(t *testing.T) {
 	testDataDir := "testdata"
@@ -0,0 +1,121 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+)
+
+// ErrNonConformant indicates that a plugin does not follow the plugin protocol.
+var ErrNonConformant = errors.New("plugin is not protocol conformant")
+
+// severities lists the control and finding severities accepted by opnDossier.
+var severities = []string{"critical", "high", "medium", "low", "info"} //nolint:gochecknoglobals // fixed list
+
+// CheckConformance exercises every method of the plugin protocol against the plugin at path and
+// returns all violations found, joined into one error, or nil for a conformant plugin. Each
+// configuration is sent in a run-checks request with its secrets redacted, as in an audit; the
+// plugin is also expected to reject an unsupported protocol version and an unknown method with an
+// error response.
+func CheckConformance(ctx context.Context, path string, opts Options, configs ...*model.OpnSenseDocument) error {
+	var violations []error
+
+	violate := func(format string, args ...any) {
+		violations = append(violations, fmt.Errorf("%w: "+format, append([]any{ErrNonConformant}, args...)...))
+	}
+
+	p, err := New(ctx, path, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNonConformant, err)
+	}
+
+	if p.Version() == "" {
+		violate("describe: no version")
+	}
+
+	if err := p.ValidateConfiguration(); err != nil {
+		violate("controls: %v", err)
+	}
+
+	for _, control := range p.GetControls() {
+		if control.Title == "" {
+			violate("controls: %s has no title", control.ID)
+		}
+
+		if !slices.Contains(severities, control.Severity) {
+			violate("controls: %s has invalid severity %q", control.ID, control.Severity)
+		}
+	}
+
+	if len(configs) == 0 {
+		configs = []*model.OpnSenseDocument{{}}
+	}
+
+	for i, config := range configs {
+		redacted, err := redactedCopy(config)
+		if err != nil {
+			return err
+		}
+
+		resp, err := call(ctx, path, opts, Request{Protocol: ProtocolVersion, Method: MethodRunChecks, Config: redacted})
+		if err != nil {
+			violate("run-checks on configuration %d: %v", i, err)
+			continue
+		}
+
+		for _, problem := range checkRun(p, resp) {
+			violate("run-checks on configuration %d: %s", i, problem)
+		}
+	}
+
+	for _, req := range []Request{
+		{Protocol: ProtocolVersion + 1, Method: MethodDescribe},
+		{Protocol: ProtocolVersion, Method: "unknown"},
+		{Protocol: ProtocolVersion, Method: MethodRunChecks},
+	} {
+		if _, err := call(ctx, path, opts, req); !errors.Is(err, ErrPluginFailed) && !errors.Is(err, ErrProtocol) {
+			violate("request %+v must be rejected with an error response, got %v", req, err)
+		}
+	}
+
+	return errors.Join(violations...)
+}
+
+// checkRun returns the problems of a run-checks response.
+func checkRun(p *Plugin, resp *Response) []string {
+	var problems []string
+
+	for i, finding := range resp.Findings {
+		if finding.Title == "" {
+			problems = append(problems, fmt.Sprintf("finding %d has no title", i))
+		}
+
+		if finding.Severity != "" && !slices.Contains(severities, finding.Severity) {
+			problems = append(problems, fmt.Sprintf("finding %d has invalid severity %q", i, finding.Severity))
+		}
+
+		references := append([]string{finding.Reference}, finding.References...)
+		if !slices.ContainsFunc(references, func(id string) bool {
+			_, err := p.GetControlByID(id)
+			return err == nil
+		}) {
+			problems = append(problems, fmt.Sprintf("finding %d (%s) references no control of the plugin", i, finding.Title))
+		}
+	}
+
+	for _, result := range resp.Results {
+		if _, err := p.GetControlByID(result.ControlID); err != nil {
+			problems = append(problems, "result for unknown control "+result.ControlID)
+		}
+
+		if !slices.Contains(plugin.Statuses(), result.Status) {
+			problems = append(problems, fmt.Sprintf("result for %s has invalid status %q", result.ControlID, result.Status))
+		}
+	}
+
+	return problems
+}
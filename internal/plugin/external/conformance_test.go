@@ -0,0 +1,89 @@
+package external
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pluginUnderTestEnv names an external plugin executable to check instead of the example plugin:
+//
+//	OPNDOSSIER_PLUGIN_UNDER_TEST=/path/to/plugin go test ./internal/plugin/external -run TestConformance
+const pluginUnderTestEnv = "OPNDOSSIER_PLUGIN_UNDER_TEST"
+
+// sampleConfigs parses the sample configurations used for run-checks conformance requests.
+func sampleConfigs(t *testing.T) []*model.OpnSenseDocument {
+	t.Helper()
+
+	configs := []*model.OpnSenseDocument{{}}
+
+	for _, name := range []string{"sample.config.1.xml", "sample.config.3.xml", "sample.config.6.xml"} {
+		f, err := os.Open(filepath.Join("..", "..", "..", "testdata", name))
+		require.NoError(t, err)
+
+		config, err := parser.NewXMLParser().Parse(context.Background(), f)
+		require.NoError(t, f.Close())
+		require.NoError(t, err, name)
+
+		configs = append(configs, config)
+	}
+
+	return configs
+}
+
+func TestConformance(t *testing.T) {
+	path := os.Getenv(pluginUnderTestEnv)
+
+	if path == "" {
+		if testing.Short() {
+			t.Skip("building the example plugin is skipped in short mode")
+		}
+
+		gobin, err := exec.LookPath("go")
+		if err != nil {
+			t.Skip("the go tool is needed to build the example plugin")
+		}
+
+		path = filepath.Join(t.TempDir(), "site-policy")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		build := exec.CommandContext(ctx, gobin, "build", "-o", path, "../../../examples/external-plugin")
+		output, err := build.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	require.NoError(t, CheckConformance(context.Background(), path, Options{}, sampleConfigs(t)...))
+}
+
+func TestCheckConformance_HelperPlugin(t *testing.T) {
+	require.NoError(t, CheckConformance(context.Background(), helperPlugin(t, "ok"), Options{}, sampleConfigs(t)...))
+}
+
+func TestCheckConformance_Violations(t *testing.T) {
+	err := CheckConformance(context.Background(), helperPlugin(t, "sloppy"), Options{})
+	require.ErrorIs(t, err, ErrNonConformant)
+
+	for _, violation := range []string{
+		"describe: no version",
+		"controls: S-1 has no title",
+		`controls: S-1 has invalid severity "urgent"`,
+		"finding 0 (orphan) references no control of the plugin",
+		"must be rejected with an error response",
+	} {
+		assert.Contains(t, err.Error(), violation)
+	}
+
+	err = CheckConformance(context.Background(), helperPlugin(t, "crash"), Options{})
+	require.ErrorIs(t, err, ErrNonConformant)
+	require.ErrorIs(t, err, ErrPluginFailed)
+}
@@ -0,0 +1,344 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/sanitize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperEnv selects the behaviour of the test binary when it runs as a plugin.
+const helperEnv = "OPNDOSSIER_EXTERNAL_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(helperEnv); mode != "" {
+		os.Exit(runHelper(mode))
+	}
+
+	os.Exit(m.Run())
+}
+
+// runHelper acts as an external plugin with the given behaviour.
+func runHelper(mode string) int {
+	switch mode {
+	case "slow":
+		time.Sleep(time.Minute)
+	case "crash":
+		fmt.Fprintln(os.Stderr, "boom")
+		return 3
+	case "garbage":
+		fmt.Println("not json")
+	case "flood":
+		fmt.Print(strings.Repeat("x", 1<<20))
+	case "future":
+		fmt.Println(`{"protocol": 2, "name": "future"}`)
+	case "env":
+		fmt.Printf(`{"protocol": 1, "name": "env", "description": %q}`+"\n",
+			os.Getenv("OPNDOSSIER_TEST_SECRET")+"|"+os.Getenv(ProtocolEnv))
+	case "memory":
+		buf := make([]byte, 512<<20)
+		for i := range buf {
+			buf[i] = 1
+		}
+	case "sloppy":
+		var req Request
+		_ = json.NewDecoder(os.Stdin).Decode(&req)
+		fmt.Println(`{"protocol": 1, "name": "sloppy", "controls": [{"id": "S-1", "severity": "urgent"}],
+			"findings": [{"title": "orphan", "reference": "OTHER-1"}]}`)
+	case "secrets":
+		if err := Serve(os.Stdin, os.Stdout, &secretsPlugin{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "diagnostic output")
+
+		if err := Serve(os.Stdin, os.Stdout, newTestPlugin()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// testPlugin fails T-001 for configurations without a hostname.
+type testPlugin struct{}
+
+func newTestPlugin() *testPlugin { return &testPlugin{} }
+
+func (p *testPlugin) Name() string        { return "test-plugin" }
+func (p *testPlugin) Version() string     { return "0.1.0" }
+func (p *testPlugin) Description() string { return "external test plugin" }
+
+func (p *testPlugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	if config.System.Hostname != "" {
+		return nil
+	}
+
+	return []plugin.Finding{{Title: "Hostname", Description: "no hostname", Reference: "T-001"}}
+}
+
+func (p *testPlugin) GetControls() []plugin.Control {
+	return []plugin.Control{
+		{ID: "T-001", Title: "Hostname", Severity: "low"},
+		{ID: "T-002", Title: "Always Passes", Severity: "info"},
+	}
+}
+
+func (p *testPlugin) GetControlByID(string) (*plugin.Control, error) {
+	return nil, plugin.ErrControlNotFound
+}
+
+func (p *testPlugin) ValidateConfiguration() error {
+	return nil
+}
+
+// secretsPlugin reports the HA sync password of the configuration it receives.
+type secretsPlugin struct{ testPlugin }
+
+func (p *secretsPlugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	return []plugin.Finding{{Title: "Secret", Description: config.HighAvailabilitySync.Password, Reference: "T-001"}}
+}
+
+// helperPlugin writes a wrapper script that runs the test binary as a plugin in the given mode.
+// Plugins do not inherit the environment of opnDossier, so the mode is set by the script.
+func helperPlugin(t *testing.T, mode string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("helper plugins are shell scripts")
+	}
+
+	binary, err := os.Executable()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), mode)
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q -test.run='^$'\n", helperEnv, mode, binary)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700)) //nolint:gosec // the script must be executable
+
+	return path
+}
+
+func TestNew(t *testing.T) {
+	p, err := New(context.Background(), helperPlugin(t, "ok"), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-plugin", p.Name())
+	assert.Equal(t, "0.1.0", p.Version())
+	assert.Equal(t, "external test plugin", p.Description())
+	assert.Len(t, p.GetControls(), 2)
+	require.NoError(t, p.ValidateConfiguration())
+
+	control, err := p.GetControlByID("T-002")
+	require.NoError(t, err)
+	assert.Equal(t, "Always Passes", control.Title)
+
+	_, err = p.GetControlByID("T-003")
+	require.ErrorIs(t, err, plugin.ErrControlNotFound)
+}
+
+func TestPlugin_Evaluate(t *testing.T) {
+	p, err := New(context.Background(), helperPlugin(t, "ok"), Options{})
+	require.NoError(t, err)
+
+	findings, results := plugin.Evaluate(p, &model.OpnSenseDocument{})
+	require.Len(t, findings, 1)
+	assert.Equal(t, "T-001", findings[0].Reference)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, plugin.StatusFail, results[0].Status)
+	assert.Equal(t, []string{"no hostname"}, results[0].Evidence)
+	assert.Equal(t, plugin.StatusPass, results[1].Status)
+
+	findings, results = plugin.Evaluate(p, &model.OpnSenseDocument{System: model.System{Hostname: "fw"}})
+	assert.Empty(t, findings)
+	assert.Equal(t, plugin.StatusPass, results[0].Status)
+}
+
+func TestPlugin_RedactsSecrets(t *testing.T) {
+	p, err := New(context.Background(), helperPlugin(t, "secrets"), Options{})
+	require.NoError(t, err)
+
+	config := &model.OpnSenseDocument{}
+	config.HighAvailabilitySync.Password = "sync-secret"
+
+	findings := p.RunChecks(config)
+	require.Len(t, findings, 1)
+	assert.Equal(t, sanitize.Redacted, findings[0].Description)
+	assert.Equal(t, "sync-secret", config.HighAvailabilitySync.Password, "the caller's configuration is untouched")
+
+	require.NoError(t, p.Configure(map[string]any{"send_secrets": true}))
+
+	findings = p.RunChecks(config)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "sync-secret", findings[0].Description)
+
+	require.ErrorIs(t, p.Configure(map[string]any{"send_secret": true}), plugin.ErrPluginValidation)
+}
+
+func TestPlugin_RunFailureMarksControlsAsErrors(t *testing.T) {
+	path := helperPlugin(t, "ok")
+
+	p, err := New(context.Background(), path, Options{})
+	require.NoError(t, err)
+
+	// Replace the plugin with one that crashes on run-checks.
+	require.NoError(t, os.Rename(helperPlugin(t, "crash"), path))
+
+	findings, results := plugin.Evaluate(p, &model.OpnSenseDocument{})
+	assert.Empty(t, findings)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		assert.Equal(t, plugin.StatusError, result.Status)
+		assert.Contains(t, result.Message, "boom")
+	}
+}
+
+func TestNew_Failures(t *testing.T) {
+	tests := []struct {
+		mode    string
+		opts    Options
+		wantErr error
+		errMsg  string
+	}{
+		{mode: "crash", wantErr: ErrPluginFailed, errMsg: "exit status 3 (stderr: boom)"},
+		{mode: "garbage", wantErr: ErrProtocol, errMsg: "invalid describe response"},
+		{mode: "future", wantErr: ErrProtocol, errMsg: "plugin speaks protocol 2, expected 1"},
+		{mode: "slow", opts: Options{Timeout: 200 * time.Millisecond}, wantErr: ErrTimeout, errMsg: "describe after 200ms"},
+		{mode: "flood", opts: Options{MaxOutputBytes: 1024}, wantErr: ErrOutputLimit, errMsg: "exceeds 1024 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			_, err := New(context.Background(), helperPlugin(t, tt.mode), tt.opts)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestNew_MissingExecutable(t *testing.T) {
+	_, err := New(context.Background(), filepath.Join(t.TempDir(), "missing"), Options{})
+	require.ErrorIs(t, err, ErrPluginFailed)
+}
+
+func TestCall_Environment(t *testing.T) {
+	t.Setenv("OPNDOSSIER_TEST_SECRET", "secret")
+
+	resp, err := call(context.Background(), helperPlugin(t, "env"), Options{}, Request{
+		Protocol: ProtocolVersion,
+		Method:   MethodDescribe,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "|1", resp.Description, "only the protocol version and an allow list are passed on")
+}
+
+func TestCall_MemoryLimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory limits are enforced on Linux only")
+	}
+
+	_, err := call(context.Background(), helperPlugin(t, "memory"), Options{MaxMemoryBytes: 256 << 20}, Request{
+		Protocol: ProtocolVersion,
+		Method:   MethodDescribe,
+	})
+	require.ErrorIs(t, err, ErrPluginFailed)
+}
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		check   func(t *testing.T, resp Response)
+	}{
+		{
+			name:    "describe",
+			request: `{"protocol": 1, "method": "describe"}`,
+			check: func(t *testing.T, resp Response) {
+				t.Helper()
+				assert.Equal(t, "test-plugin", resp.Name)
+				assert.Empty(t, resp.Error)
+			},
+		},
+		{
+			name:    "run-checks",
+			request: `{"protocol": 1, "method": "run-checks", "config": {"system": {"hostname": ""}}}`,
+			check: func(t *testing.T, resp Response) {
+				t.Helper()
+				assert.Len(t, resp.Findings, 1)
+				assert.Len(t, resp.Results, 2)
+			},
+		},
+		{
+			name:    "run-checks without configuration",
+			request: `{"protocol": 1, "method": "run-checks"}`,
+			check: func(t *testing.T, resp Response) {
+				t.Helper()
+				assert.Equal(t, "run-checks requires a configuration", resp.Error)
+			},
+		},
+		{
+			name:    "unsupported protocol",
+			request: `{"protocol": 7, "method": "describe"}`,
+			check: func(t *testing.T, resp Response) {
+				t.Helper()
+				assert.Equal(t, ProtocolVersion, resp.Protocol)
+				assert.Equal(t, "unsupported protocol version 7", resp.Error)
+			},
+		},
+		{
+			name:    "unknown method",
+			request: `{"protocol": 1, "method": "reload"}`,
+			check: func(t *testing.T, resp Response) {
+				t.Helper()
+				assert.Equal(t, `unknown method "reload"`, resp.Error)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			require.NoError(t, Serve(strings.NewReader(tt.request), &out, newTestPlugin()))
+
+			var resp Response
+			require.NoError(t, json.Unmarshal([]byte(out.String()), &resp))
+			tt.check(t, resp)
+		})
+	}
+
+	require.Error(t, Serve(strings.NewReader("{"), &strings.Builder{}, newTestPlugin()))
+}
+
+func TestIsExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on Windows")
+	}
+
+	dir := t.TempDir()
+
+	write := func(name string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, nil, mode))
+
+		return path
+	}
+
+	assert.True(t, IsExecutable(write("plugin", 0o700)))
+	assert.False(t, IsExecutable(write("README.md", 0o600)))
+	assert.False(t, IsExecutable(write("legacy.so", 0o700)), "Go plugins use the other transport")
+	assert.False(t, IsExecutable(dir))
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package external
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyLimits limits the address space of a running plugin process. Go cannot set
+// resource limits between fork and exec, so the limit is applied after the process
+// has started and does not cover its start-up.
+func applyLimits(pid int, opts Options) error {
+	if opts.MaxMemoryBytes < 0 {
+		return nil
+	}
+
+	limit := &unix.Rlimit{Cur: uint64(opts.MaxMemoryBytes), Max: uint64(opts.MaxMemoryBytes)}
+	if err := unix.Prlimit(pid, unix.RLIMIT_AS, limit, nil); err != nil {
+		return fmt.Errorf("failed to set memory limit: %w", err)
+	}
+
+	return nil
+}
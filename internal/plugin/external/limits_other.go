@@ -0,0 +1,9 @@
+//go:build !linux
+
+package external
+
+// applyLimits is a no-op on platforms without prlimit; the timeout and output
+// limits still apply.
+func applyLimits(_ int, _ Options) error {
+	return nil
+}
@@ -0,0 +1,253 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/sanitize"
+)
+
+// namePattern restricts plugin names to values that can be passed to --plugins.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Settings are the settings of an external plugin in the plugin_settings block of the
+// configuration file.
+type Settings struct {
+	// SendSecrets sends the configuration to the plugin with its secrets. By default the plugin
+	// receives a copy redacted with sanitize.Redact.
+	SendSecrets bool `yaml:"send_secrets"`
+}
+
+// Plugin adapts an external plugin executable to plugin.CompliancePlugin.
+// The metadata and controls are read once when the plugin is loaded; every
+// run-checks request starts a new process.
+type Plugin struct {
+	path        string
+	opts        Options
+	settings    Settings
+	name        string
+	version     string
+	description string
+	controls    []plugin.Control
+
+	mutex      sync.Mutex
+	lastConfig *model.OpnSenseDocument
+	lastRun    *Response
+	lastErr    error
+}
+
+// New loads the external plugin at path by asking it to describe itself and list its controls.
+func New(ctx context.Context, path string, opts Options) (*Plugin, error) {
+	describe, err := call(ctx, path, opts, Request{Protocol: ProtocolVersion, Method: MethodDescribe})
+	if err != nil {
+		return nil, err
+	}
+
+	if !namePattern.MatchString(describe.Name) {
+		return nil, fmt.Errorf("%w: invalid plugin name %q", ErrProtocol, describe.Name)
+	}
+
+	controls, err := call(ctx, path, opts, Request{Protocol: ProtocolVersion, Method: MethodControls})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plugin{
+		path:        path,
+		opts:        opts,
+		name:        describe.Name,
+		version:     describe.Version,
+		description: describe.Description,
+		controls:    controls.Controls,
+	}, nil
+}
+
+// Path returns the path of the plugin executable.
+func (p *Plugin) Path() string {
+	return p.path
+}
+
+// Name returns the name reported by the plugin.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Version returns the version reported by the plugin.
+func (p *Plugin) Version() string {
+	return p.version
+}
+
+// Description returns the description reported by the plugin.
+func (p *Plugin) Description() string {
+	return p.description
+}
+
+// RunChecks sends the configuration to the plugin and returns its findings.
+// A failed run yields no findings; EvaluateControls reports the failure.
+func (p *Plugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	resp, err := p.run(config, false)
+	if err != nil {
+		return nil
+	}
+
+	return resp.Findings
+}
+
+// EvaluateControls returns the per-control results of the plugin. Results are derived from
+// the findings when the plugin does not report them, and every control is marked as an error
+// when the plugin fails.
+func (p *Plugin) EvaluateControls(config *model.OpnSenseDocument) []plugin.ControlResult {
+	resp, err := p.run(config, true)
+	if err != nil {
+		results := make([]plugin.ControlResult, 0, len(p.controls))
+		for _, control := range p.controls {
+			results = append(results, plugin.ControlResult{
+				ControlID: control.ID,
+				Status:    plugin.StatusError,
+				Message:   err.Error(),
+			})
+		}
+
+		return results
+	}
+
+	if len(resp.Results) == 0 {
+		return plugin.ResultsFromFindings(p.controls, resp.Findings)
+	}
+
+	return resp.Results
+}
+
+// GetControls returns the controls reported by the plugin.
+func (p *Plugin) GetControls() []plugin.Control {
+	return p.controls
+}
+
+// GetControlByID returns a specific control by ID.
+func (p *Plugin) GetControlByID(id string) (*plugin.Control, error) {
+	for _, control := range p.controls {
+		if control.ID == id {
+			return &control, nil
+		}
+	}
+
+	return nil, plugin.ErrControlNotFound
+}
+
+// ValidateConfiguration checks the controls reported by the plugin.
+func (p *Plugin) ValidateConfiguration() error {
+	if len(p.controls) == 0 {
+		return plugin.ErrNoControlsDefined
+	}
+
+	seen := make(map[string]bool, len(p.controls))
+
+	for i, control := range p.controls {
+		switch {
+		case control.ID == "":
+			return fmt.Errorf("%w: control %d has no ID", plugin.ErrPluginValidation, i)
+		case seen[control.ID]:
+			return fmt.Errorf("%w: control %s is defined more than once", plugin.ErrPluginValidation, control.ID)
+		}
+
+		seen[control.ID] = true
+	}
+
+	return nil
+}
+
+// Configure applies the settings block of the plugin.
+func (p *Plugin) Configure(settings map[string]any) error {
+	return plugin.DecodeSettings(settings, &p.settings)
+}
+
+// run sends a run-checks request for the configuration. plugin.Evaluate calls RunChecks and then
+// EvaluateControls for the same configuration, so the response of RunChecks is kept for the next
+// EvaluateControls call, which consumes it, and a single process is started per evaluation.
+func (p *Plugin) run(config *model.OpnSenseDocument, reuse bool) (*Response, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if reuse && config != nil && config == p.lastConfig {
+		resp, err := p.lastRun, p.lastErr
+		p.lastConfig, p.lastRun, p.lastErr = nil, nil, nil
+
+		return resp, err
+	}
+
+	resp, err := p.runChecks(config)
+
+	if reuse {
+		p.lastConfig, p.lastRun, p.lastErr = nil, nil, nil
+	} else {
+		p.lastConfig, p.lastRun, p.lastErr = config, resp, err
+	}
+
+	return resp, err
+}
+
+// runChecks starts the plugin for a run-checks request. Secrets are redacted from the configuration
+// unless the plugin settings opt in to them.
+func (p *Plugin) runChecks(config *model.OpnSenseDocument) (*Response, error) {
+	if !p.settings.SendSecrets {
+		redacted, err := redactedCopy(config)
+		if err != nil {
+			return nil, err
+		}
+
+		config = redacted
+	}
+
+	return call(context.Background(), p.path, p.opts, Request{
+		Protocol: ProtocolVersion,
+		Method:   MethodRunChecks,
+		Config:   config,
+	})
+}
+
+// redactedCopy returns a copy of the configuration with its secrets redacted. The copy is made
+// through the JSON encoding that is sent to the plugin, so the caller's configuration is untouched.
+func redactedCopy(config *model.OpnSenseDocument) (*model.OpnSenseDocument, error) {
+	if config == nil {
+		return nil, nil //nolint:nilnil // nothing to redact
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	var redacted model.OpnSenseDocument
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, fmt.Errorf("failed to copy configuration: %w", err)
+	}
+
+	sanitize.Redact(&redacted)
+
+	return &redacted, nil
+}
+
+// IsExecutable reports whether a directory entry looks like an external plugin executable.
+// Go plugins (.so files) are skipped.
+func IsExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if runtime.GOOS == "windows" {
+		return ext == ".exe"
+	}
+
+	return ext != ".so" && info.Mode().Perm()&0o111 != 0
+}
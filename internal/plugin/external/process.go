@@ -0,0 +1,209 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default limits of external plugin processes.
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultMaxOutputBytes = 32 << 20
+	DefaultMaxStderrBytes = 64 << 10
+	DefaultMaxMemoryBytes = 2 << 30
+)
+
+// waitDelay bounds how long a killed plugin may keep its output pipes open.
+const waitDelay = time.Second
+
+// stderrExcerpt is the number of trailing stderr bytes included in error messages.
+const stderrExcerpt = 1024
+
+// inheritedEnv lists the environment variables passed on to plugins. Everything
+// else, in particular credentials in the environment of opnDossier, is withheld.
+var inheritedEnv = []string{ //nolint:gochecknoglobals // fixed allow list
+	"PATH", "HOME", "TMPDIR", "TEMP", "TMP", "LANG", "LC_ALL", "SYSTEMROOT",
+}
+
+// Options limit the resources of external plugin processes. Zero values select the defaults,
+// except for MaxMemoryBytes where a negative value disables the limit.
+type Options struct {
+	// Timeout bounds each request, including process start-up.
+	Timeout time.Duration
+	// MaxOutputBytes bounds the size of a response.
+	MaxOutputBytes int
+	// MaxStderrBytes bounds the captured stderr; further output is discarded.
+	MaxStderrBytes int
+	// MaxMemoryBytes bounds the address space of the process. It is enforced on Linux only and
+	// on a best-effort basis: the limit is set right after the process starts, so allocations
+	// made while the plugin starts up, before the limit is in place, are not bounded.
+	MaxMemoryBytes int64
+	// Logger receives the stderr output of plugins that succeed. It may be nil.
+	Logger *slog.Logger
+}
+
+// withDefaults returns the options with zero values replaced by the defaults.
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+
+	if o.MaxOutputBytes <= 0 {
+		o.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+
+	if o.MaxStderrBytes <= 0 {
+		o.MaxStderrBytes = DefaultMaxStderrBytes
+	}
+
+	if o.MaxMemoryBytes == 0 {
+		o.MaxMemoryBytes = DefaultMaxMemoryBytes
+	}
+
+	return o
+}
+
+// call runs the plugin executable for a single request and decodes its response.
+func call(ctx context.Context, path string, opts Options, req Request) (*Response, error) {
+	opts = opts.withDefaults()
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", req.Method, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: opts.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: opts.MaxStderrBytes}
+
+	cmd := exec.CommandContext(ctx, path) //nolint:gosec // plugins are executables the user installed on purpose
+	cmd.Dir = filepath.Dir(path)
+	cmd.Env = pluginEnv()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin stdin: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%w: failed to start %s: %w", ErrPluginFailed, path, err)
+	}
+
+	// Limits can only be set once the process exists, so they are best-effort: they apply
+	// before the request is written, but not to what the plugin does while it starts up.
+	if err := applyLimits(cmd.Process.Pid, opts); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		return nil, fmt.Errorf("failed to limit plugin resources: %w", err)
+	}
+
+	go func() {
+		_, _ = stdin.Write(payload)
+		_ = stdin.Close()
+	}()
+
+	waitErr := cmd.Wait()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("%w: %s after %s%s", ErrTimeout, req.Method, opts.Timeout, stderrSuffix(stderr))
+	case stdout.truncated:
+		return nil, fmt.Errorf("%w: %s response exceeds %d bytes", ErrOutputLimit, req.Method, opts.MaxOutputBytes)
+	case waitErr != nil:
+		return nil, fmt.Errorf("%w: %s: %w%s", ErrPluginFailed, req.Method, waitErr, stderrSuffix(stderr))
+	}
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s response: %w%s", ErrProtocol, req.Method, err, stderrSuffix(stderr))
+	}
+
+	if resp.Protocol != ProtocolVersion {
+		return nil, fmt.Errorf("%w: plugin speaks protocol %d, expected %d", ErrProtocol, resp.Protocol, ProtocolVersion)
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrPluginFailed, req.Method, resp.Error)
+	}
+
+	if opts.Logger != nil && stderr.buf.Len() > 0 {
+		opts.Logger.DebugContext(ctx, "External plugin wrote to stderr",
+			"plugin", path, "method", req.Method, "stderr", strings.TrimSpace(stderr.String()))
+	}
+
+	return &resp, nil
+}
+
+// pluginEnv returns the environment of plugin processes.
+func pluginEnv() []string {
+	env := []string{ProtocolEnv + "=" + strconv.Itoa(ProtocolVersion)}
+
+	for _, name := range inheritedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	return env
+}
+
+// stderrSuffix formats the tail of the captured stderr for an error message.
+func stderrSuffix(stderr *cappedBuffer) string {
+	text := strings.TrimSpace(stderr.String())
+	if text == "" {
+		return ""
+	}
+
+	if len(text) > stderrExcerpt {
+		text = "..." + text[len(text)-stderrExcerpt:]
+	}
+
+	return " (stderr: " + text + ")"
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest, so that a
+// plugin writing too much is not blocked on a full pipe. It deliberately does not embed
+// bytes.Buffer, whose ReadFrom method would let io.Copy bypass the limit.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write implements io.Writer.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+// Bytes returns the captured output.
+func (b *cappedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// String returns the captured output as a string.
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,66 @@
+// Package external runs compliance plugins as separate executables.
+//
+// An external plugin is an executable that speaks a versioned JSON protocol over
+// stdin and stdout. opnDossier starts the executable once per request, writes a
+// single Request object to its stdin and reads a single Response object from its
+// stdout. Anything the plugin writes to stderr is captured for diagnostics. Unlike
+// Go plugins (.so files), external plugins can be written in any language and do
+// not need to be built with the toolchain and module versions of opnDossier.
+package external
+
+import (
+	"errors"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+)
+
+// ProtocolVersion is the version of the plugin protocol implemented by this package.
+const ProtocolVersion = 1
+
+// ProtocolEnv is the environment variable that tells an external plugin which
+// protocol version opnDossier speaks.
+const ProtocolEnv = "OPNDOSSIER_PLUGIN_PROTOCOL"
+
+// Protocol methods.
+const (
+	// MethodDescribe asks the plugin for its name, version and description.
+	MethodDescribe = "describe"
+	// MethodControls asks the plugin for the controls it implements.
+	MethodControls = "controls"
+	// MethodRunChecks asks the plugin to check the configuration sent with the request.
+	MethodRunChecks = "run-checks"
+)
+
+// Error definitions for external plugins.
+var (
+	// ErrProtocol indicates that a plugin violated the plugin protocol.
+	ErrProtocol = errors.New("plugin protocol violation")
+	// ErrPluginFailed indicates that a plugin exited with an error or reported one.
+	ErrPluginFailed = errors.New("external plugin failed")
+	// ErrTimeout indicates that a plugin did not answer within the configured timeout.
+	ErrTimeout = errors.New("external plugin timed out")
+	// ErrOutputLimit indicates that a plugin wrote more output than allowed.
+	ErrOutputLimit = errors.New("external plugin output limit exceeded")
+)
+
+// Request is sent to a plugin on stdin. The configuration of run-checks requests has its secrets
+// redacted unless the plugin settings set send_secrets.
+type Request struct {
+	Protocol int                     `json:"protocol"`
+	Method   string                  `json:"method"`
+	Config   *model.OpnSenseDocument `json:"config,omitempty"`
+}
+
+// Response is read from a plugin on stdout. Which fields are set depends on the
+// method of the request; Error reports a failure of the plugin.
+type Response struct {
+	Protocol    int                    `json:"protocol"`
+	Name        string                 `json:"name,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Controls    []plugin.Control       `json:"controls,omitempty"`
+	Findings    []plugin.Finding       `json:"findings,omitempty"`
+	Results     []plugin.ControlResult `json:"results,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
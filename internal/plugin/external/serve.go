@@ -0,0 +1,64 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+)
+
+// Serve answers a single protocol request read from r with the given plugin and writes the
+// response to w. It lets a Go CompliancePlugin be built as an external plugin executable:
+//
+//	func main() {
+//		if err := external.Serve(os.Stdin, os.Stdout, newPlugin()); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// Errors of the request are reported in the response; Serve itself only fails when the
+// request cannot be read or the response cannot be written.
+func Serve(r io.Reader, w io.Writer, p plugin.CompliancePlugin) error {
+	var req Request
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return fmt.Errorf("failed to decode request: %w", err)
+	}
+
+	if err := json.NewEncoder(w).Encode(handle(req, p)); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	return nil
+}
+
+// handle builds the response of a plugin to a request.
+func handle(req Request, p plugin.CompliancePlugin) Response {
+	resp := Response{Protocol: ProtocolVersion}
+
+	if req.Protocol != ProtocolVersion {
+		resp.Error = fmt.Sprintf("unsupported protocol version %d", req.Protocol)
+		return resp
+	}
+
+	switch req.Method {
+	case MethodDescribe:
+		resp.Name = p.Name()
+		resp.Version = p.Version()
+		resp.Description = p.Description()
+	case MethodControls:
+		resp.Controls = p.GetControls()
+	case MethodRunChecks:
+		if req.Config == nil {
+			resp.Error = "run-checks requires a configuration"
+			return resp
+		}
+
+		resp.Findings, resp.Results = plugin.Evaluate(p, req.Config)
+	default:
+		resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+	}
+
+	return resp
+}
@@ -56,6 +56,9 @@ type Finding struct {
 	Component      string `json:"component"`
 	Reference      string `json:"reference"`
 
+	// Severity optionally overrides the severity of the referenced control
+	Severity string `json:"severity,omitempty"`
+
 	// Generic references and metadata
 	References []string          `json:"references,omitempty"`
 	Tags       []string          `json:"tags,omitempty"`
@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"slices"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Status is the outcome of evaluating a single control.
+type Status string
+
+// Control evaluation statuses.
+const (
+	// StatusPass means the configuration satisfies the control.
+	StatusPass Status = "pass"
+	// StatusFail means the configuration violates the control.
+	StatusFail Status = "fail"
+	// StatusNotApplicable means the control does not apply to the configuration,
+	// for example a VPN control on a firewall without VPN servers.
+	StatusNotApplicable Status = "not-applicable"
+	// StatusManualReview means the control cannot be decided from the configuration alone.
+	StatusManualReview Status = "manual-review"
+	// StatusError means the control could not be evaluated.
+	StatusError Status = "error"
+)
+
+// Statuses returns all control evaluation statuses in report order.
+func Statuses() []Status {
+	return []Status{StatusPass, StatusFail, StatusNotApplicable, StatusManualReview, StatusError}
+}
+
+// ControlResult is the result of evaluating a single control against a configuration.
+type ControlResult struct {
+	ControlID string   `json:"controlId"`
+	Title     string   `json:"title"`
+	Category  string   `json:"category,omitempty"`
+	Severity  string   `json:"severity,omitempty"`
+	Status    Status   `json:"status"`
+	Message   string   `json:"message,omitempty"`
+	Evidence  []string `json:"evidence,omitempty"`
+}
+
+// ControlEvaluator is implemented by plugins that report a result for every control,
+// including passed, not applicable and manually reviewed controls.
+type ControlEvaluator interface {
+	// EvaluateControls evaluates every control of the plugin against the configuration
+	EvaluateControls(config *model.OpnSenseDocument) []ControlResult
+}
+
+// Evaluate runs a plugin and returns its findings together with one result per control.
+// Plugins implementing ControlEvaluator report their own results; for all other plugins the
+// results are derived from the findings of RunChecks.
+func Evaluate(p CompliancePlugin, config *model.OpnSenseDocument) ([]Finding, []ControlResult) {
+	findings := p.RunChecks(config)
+
+	if evaluator, ok := p.(ControlEvaluator); ok {
+		return findings, completeResults(p.GetControls(), evaluator.EvaluateControls(config))
+	}
+
+	return findings, ResultsFromFindings(p.GetControls(), findings)
+}
+
+// ResultsFromFindings adapts the findings of a plugin that only reports failures into
+// per-control results. A control referenced by a finding fails with the finding descriptions
+// as evidence; every other control passes.
+func ResultsFromFindings(controls []Control, findings []Finding) []ControlResult {
+	results := make([]ControlResult, 0, len(controls))
+
+	for _, control := range controls {
+		result := newResult(control, StatusPass)
+
+		for _, finding := range findings {
+			if finding.Reference != control.ID && !slices.Contains(finding.References, control.ID) {
+				continue
+			}
+
+			result.Status = StatusFail
+			result.Evidence = append(result.Evidence, finding.Description)
+		}
+
+		if result.Status == StatusFail {
+			result.Message = "control violated by plugin findings"
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// completeResults orders native results by control, fills in control details and reports
+// controls that the plugin did not evaluate as errors.
+func completeResults(controls []Control, results []ControlResult) []ControlResult {
+	byID := make(map[string]ControlResult, len(results))
+	for _, result := range results {
+		byID[result.ControlID] = result
+	}
+
+	completed := make([]ControlResult, 0, len(controls))
+
+	for _, control := range controls {
+		result, ok := byID[control.ID]
+		if !ok {
+			result = newResult(control, StatusError)
+			result.Message = "control was not evaluated by the plugin"
+		}
+
+		if result.Title == "" {
+			result.Title = control.Title
+		}
+
+		if result.Category == "" {
+			result.Category = control.Category
+		}
+
+		if result.Severity == "" {
+			result.Severity = control.Severity
+		}
+
+		if !slices.Contains(Statuses(), result.Status) {
+			result.Message = "unknown status " + string(result.Status)
+			result.Status = StatusError
+		}
+
+		completed = append(completed, result)
+	}
+
+	return completed
+}
+
+// newResult creates a result for a control with the given status.
+func newResult(control Control, status Status) ControlResult {
+	return ControlResult{
+		ControlID: control.ID,
+		Title:     control.Title,
+		Category:  control.Category,
+		Severity:  control.Severity,
+		Status:    status,
+	}
+}
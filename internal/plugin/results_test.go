@@ -0,0 +1,114 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findingsPlugin is a plugin that only reports failures through RunChecks.
+type findingsPlugin struct {
+	controls []plugin.Control
+	findings []plugin.Finding
+}
+
+func (p *findingsPlugin) Name() string                                         { return "findings" }
+func (p *findingsPlugin) Version() string                                      { return "1.0.0" }
+func (p *findingsPlugin) Description() string                                  { return "findings only" }
+func (p *findingsPlugin) RunChecks(_ *model.OpnSenseDocument) []plugin.Finding { return p.findings }
+func (p *findingsPlugin) GetControls() []plugin.Control                        { return p.controls }
+func (p *findingsPlugin) ValidateConfiguration() error                         { return nil }
+
+func (p *findingsPlugin) GetControlByID(_ string) (*plugin.Control, error) {
+	return nil, plugin.ErrControlNotFound
+}
+
+// evaluatorPlugin additionally reports its own per-control results.
+type evaluatorPlugin struct {
+	findingsPlugin
+
+	results []plugin.ControlResult
+}
+
+func (p *evaluatorPlugin) EvaluateControls(_ *model.OpnSenseDocument) []plugin.ControlResult {
+	return p.results
+}
+
+func testControls() []plugin.Control {
+	return []plugin.Control{
+		{ID: "T-001", Title: "First", Category: "Test", Severity: "high"},
+		{ID: "T-002", Title: "Second", Category: "Test", Severity: "low"},
+		{ID: "T-003", Title: "Third", Category: "Test", Severity: "medium"},
+	}
+}
+
+func TestResultsFromFindings(t *testing.T) {
+	findings := []plugin.Finding{
+		{Description: "first violation", References: []string{"T-001"}},
+		{Description: "second violation", Reference: "T-001"},
+		{Description: "unrelated", References: []string{"OTHER-001"}},
+	}
+
+	results := plugin.ResultsFromFindings(testControls(), findings)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "T-001", results[0].ControlID)
+	assert.Equal(t, plugin.StatusFail, results[0].Status)
+	assert.Equal(t, []string{"first violation", "second violation"}, results[0].Evidence)
+	assert.Equal(t, "high", results[0].Severity)
+
+	assert.Equal(t, plugin.StatusPass, results[1].Status)
+	assert.Empty(t, results[1].Evidence)
+	assert.Equal(t, plugin.StatusPass, results[2].Status)
+}
+
+func TestEvaluate_AdaptsFindingsPlugins(t *testing.T) {
+	p := &findingsPlugin{
+		controls: testControls(),
+		findings: []plugin.Finding{{Description: "violation", References: []string{"T-002"}}},
+	}
+
+	findings, results := plugin.Evaluate(p, &model.OpnSenseDocument{})
+	assert.Len(t, findings, 1)
+	require.Len(t, results, 3)
+	assert.Equal(t, plugin.StatusFail, results[1].Status)
+}
+
+func TestEvaluate_UsesNativeResults(t *testing.T) {
+	p := &evaluatorPlugin{
+		findingsPlugin: findingsPlugin{controls: testControls()},
+		results: []plugin.ControlResult{
+			{ControlID: "T-003", Status: plugin.StatusManualReview, Message: "check the rack"},
+			{ControlID: "T-001", Status: plugin.StatusNotApplicable},
+			{ControlID: "T-002", Status: "unknown"},
+		},
+	}
+
+	_, results := plugin.Evaluate(p, &model.OpnSenseDocument{})
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "T-001", results[0].ControlID, "results follow the control order")
+	assert.Equal(t, plugin.StatusNotApplicable, results[0].Status)
+	assert.Equal(t, "First", results[0].Title, "control details are filled in")
+
+	assert.Equal(t, plugin.StatusError, results[1].Status)
+	assert.Equal(t, "unknown status unknown", results[1].Message)
+
+	assert.Equal(t, plugin.StatusManualReview, results[2].Status)
+	assert.Equal(t, "check the rack", results[2].Message)
+}
+
+func TestEvaluate_MissingNativeResults(t *testing.T) {
+	p := &evaluatorPlugin{findingsPlugin: findingsPlugin{controls: testControls()}}
+
+	_, results := plugin.Evaluate(p, &model.OpnSenseDocument{})
+	require.Len(t, results, 3)
+
+	for _, result := range results {
+		assert.Equal(t, plugin.StatusError, result.Status)
+		assert.Equal(t, "control was not evaluated by the plugin", result.Message)
+	}
+}
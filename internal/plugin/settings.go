@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configurable is implemented by plugins that accept settings from the opnDossier configuration
+// file. The plugin manager calls Configure with the settings block of the plugin and then
+// ValidateConfiguration, which must reject invalid settings.
+type Configurable interface {
+	// Configure applies the settings block of the plugin. Unknown settings must be rejected.
+	Configure(settings map[string]any) error
+}
+
+// DecodeSettings decodes a settings block into the settings struct of a plugin, which describes
+// the settings with yaml tags. Settings that are not in the block keep their current values and
+// unknown settings are rejected so that typos do not go unnoticed.
+func DecodeSettings(settings map[string]any, target any) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPluginValidation, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(target); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %w", ErrPluginValidation, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSettings(t *testing.T) {
+	type settings struct {
+		Limit  int      `yaml:"limit"`
+		Zones  []string `yaml:"zones"`
+		Strict bool     `yaml:"strict"`
+	}
+
+	t.Run("known settings", func(t *testing.T) {
+		target := settings{Limit: 2, Zones: []string{"wan"}, Strict: true}
+
+		require.NoError(t, plugin.DecodeSettings(map[string]any{"zones": []any{"guest", "iot"}, "strict": false}, &target))
+		assert.Equal(t, settings{Limit: 2, Zones: []string{"guest", "iot"}, Strict: false}, target)
+	})
+
+	t.Run("empty settings keep defaults", func(t *testing.T) {
+		target := settings{Limit: 2}
+
+		require.NoError(t, plugin.DecodeSettings(nil, &target))
+		assert.Equal(t, settings{Limit: 2}, target)
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		err := plugin.DecodeSettings(map[string]any{"limits": 3}, &settings{})
+		require.ErrorIs(t, err, plugin.ErrPluginValidation)
+		assert.Contains(t, err.Error(), "limits")
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		err := plugin.DecodeSettings(map[string]any{"limit": "many"}, &settings{})
+		require.ErrorIs(t, err, plugin.ErrPluginValidation)
+	})
+}
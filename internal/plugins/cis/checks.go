@@ -0,0 +1,700 @@
+package cis
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/identity"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+const (
+	// maxSessionTimeout is the longest acceptable web GUI session timeout in minutes.
+	maxSessionTimeout = 10
+	// defaultSessionTimeout is the OPNsense session timeout in minutes when none is configured.
+	defaultSessionTimeout = 240
+	// minTimeServers is the number of time servers NTP needs to detect a misbehaving source.
+	minTimeServers = 3
+	// minDHLength is the shortest acceptable Diffie-Hellman parameter length in bits.
+	minDHLength = 2048
+
+	// wanInterface is the logical name of the WAN interface.
+	wanInterface = "wan"
+	// openVPNSharedKeyMode is the OpenVPN peer-to-peer mode with a static shared key.
+	openVPNSharedKeyMode = "p2p_shared_key"
+	// openVPNRemoteAccessTLSMode is the OpenVPN remote access mode with certificates and user authentication.
+	openVPNRemoteAccessTLSMode = "server_tls_user"
+)
+
+// Management plane access
+
+func checkWebGUIHTTPS(config *model.OpnSenseDocument) []string {
+	protocol := strings.ToLower(strings.TrimSpace(config.System.WebGUI.Protocol))
+	if protocol == "https" {
+		return nil
+	}
+
+	return []string{"web GUI protocol is " + orDefault(protocol, "unset")}
+}
+
+func checkWebGUICertificate(config *model.OpnSenseDocument) []string {
+	if strings.TrimSpace(config.System.WebGUI.SSLCertRef) != "" {
+		return nil
+	}
+
+	return []string{"no certificate assigned to the web GUI"}
+}
+
+func checkSessionTimeout(config *model.OpnSenseDocument) []string {
+	value := strings.TrimSpace(config.System.WebGUI.SessionTimeout)
+	if value == "" {
+		return []string{fmt.Sprintf("session timeout not set (default %d minutes)", defaultSessionTimeout)}
+	}
+
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid session timeout %q", value)}
+	}
+
+	if minutes <= 0 || minutes > maxSessionTimeout {
+		return []string{fmt.Sprintf("session timeout is %d minutes", minutes)}
+	}
+
+	return nil
+}
+
+func checkWebGUIListenInterfaces(config *model.OpnSenseDocument) []string {
+	interfaces := splitList(config.System.WebGUI.Interfaces)
+
+	switch {
+	case len(interfaces) == 0:
+		return []string{"web GUI listens on all interfaces"}
+	case slices.Contains(interfaces, wanInterface):
+		return []string{"web GUI listens on wan"}
+	default:
+		return nil
+	}
+}
+
+func checkDNSRebindCheck(config *model.OpnSenseDocument) []string {
+	if !config.System.WebGUI.DNSRebindCheckDisabled() {
+		return nil
+	}
+
+	return []string{"DNS rebind check disabled"}
+}
+
+func checkSSHRootLogin(config *model.OpnSenseDocument) []string {
+	ssh := config.System.SSH
+	if !ssh.IsEnabled() || !ssh.RootLoginPermitted() {
+		return nil
+	}
+
+	return []string{"SSH permits root login"}
+}
+
+func checkSSHPasswordAuth(config *model.OpnSenseDocument) []string {
+	ssh := config.System.SSH
+	if !ssh.IsEnabled() || !ssh.PasswordAuthEnabled() {
+		return nil
+	}
+
+	return []string{"SSH permits password login"}
+}
+
+// managementService is a management service listening on the firewall itself.
+type managementService struct {
+	name string
+	port int
+}
+
+// managementServices returns the management services of the firewall with their ports.
+func managementServices(config *model.OpnSenseDocument) []managementService {
+	webgui := config.System.WebGUI
+
+	webPort := 443
+	if strings.EqualFold(webgui.Protocol, "http") {
+		webPort = 80
+	}
+
+	if port, err := strconv.Atoi(strings.TrimSpace(webgui.Port)); err == nil {
+		webPort = port
+	}
+
+	services := []managementService{{name: "web GUI", port: webPort}}
+
+	if ssh := config.System.SSH; ssh.IsEnabled() {
+		sshPort := 22
+		if port, err := strconv.Atoi(strings.TrimSpace(ssh.Port)); err == nil {
+			sshPort = port
+		}
+
+		services = append(services, managementService{name: "SSH", port: sshPort})
+	}
+
+	return services
+}
+
+func checkManagementOnWAN(config *model.OpnSenseDocument) []string {
+	services := managementServices(config)
+
+	var affected []string
+
+	for i, rule := range config.FilterRules() {
+		if !isEnabledPassRule(rule) || !rule.Interface.Contains(wanInterface) || !targetsFirewall(rule.Destination) {
+			continue
+		}
+
+		var exposed []string
+
+		for _, service := range services {
+			if rule.Destination.Port == "" || portMatches(rule.Destination.Port, service.port) {
+				exposed = append(exposed, fmt.Sprintf("%s (%d)", service.name, service.port))
+			}
+		}
+
+		if len(exposed) > 0 {
+			affected = append(affected, fmt.Sprintf("%s: %s", ruleLabel(i, rule), strings.Join(exposed, ", ")))
+		}
+	}
+
+	return affected
+}
+
+// Authentication
+
+func checkPasswordHashes(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for _, user := range config.System.User {
+		if user.Disabled.Bool() {
+			continue
+		}
+
+		scheme := identity.ClassifyHash(user.Password)
+		if strength := scheme.Strength(); strength == identity.HashWeak || strength == identity.HashMissing {
+			affected = append(affected, fmt.Sprintf("user %s (%s)", user.Name, scheme))
+		}
+	}
+
+	return affected
+}
+
+func checkRootAccount(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for _, user := range config.System.User {
+		if !user.Disabled.Bool() && (user.Name == "root" || user.UID == "0") {
+			affected = append(affected, fmt.Sprintf("user %s is enabled", user.Name))
+		}
+	}
+
+	return affected
+}
+
+func checkCentralAuthentication(config *model.OpnSenseDocument) []string {
+	for _, server := range config.System.AuthServer {
+		switch strings.ToLower(server.Type) {
+		case "ldap", "radius":
+			return nil
+		}
+	}
+
+	return []string{"no LDAP or RADIUS server configured"}
+}
+
+func checkAdministratorMFA(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for _, admin := range identity.Analyze(config).Admins() {
+		if !admin.OTP {
+			affected = append(affected, "administrator "+admin.Name+" has no TOTP token")
+		}
+	}
+
+	return affected
+}
+
+// Logging and time synchronization
+
+// legacyRemoteSyslog reports whether remote logging is enabled in the legacy syslog settings.
+func legacyRemoteSyslog(config *model.OpnSenseDocument) bool {
+	syslog := config.Syslog
+	if !syslog.Enable.Bool() {
+		return false
+	}
+
+	return syslog.Remoteserver != "" || syslog.Remoteserver2 != "" || syslog.Remoteserver3 != ""
+}
+
+// remoteSyslogTargets reports whether remote syslog destinations are configured in the MVC logging settings.
+func remoteSyslogTargets(config *model.OpnSenseDocument) bool {
+	return strings.TrimSpace(config.OPNsense.Syslog.Destinations) != ""
+}
+
+func checkRemoteSyslog(config *model.OpnSenseDocument) []string {
+	if legacyRemoteSyslog(config) || remoteSyslogTargets(config) {
+		return nil
+	}
+
+	return []string{"no remote syslog destination configured"}
+}
+
+func checkSecurityEventForwarding(config *model.OpnSenseDocument) []string {
+	if !legacyRemoteSyslog(config) {
+		if remoteSyslogTargets(config) {
+			// Logging targets select their own facilities, which the model does not expose.
+			return nil
+		}
+
+		return []string{"no remote syslog destination configured"}
+	}
+
+	var affected []string
+	if !config.Syslog.Filter.Bool() {
+		affected = append(affected, "firewall events are not forwarded")
+	}
+
+	if !config.Syslog.Auth.Bool() {
+		affected = append(affected, "authentication events are not forwarded")
+	}
+
+	return affected
+}
+
+func checkNTPServers(config *model.OpnSenseDocument) []string {
+	if len(strings.Fields(config.System.TimeServers)) > 0 {
+		return nil
+	}
+
+	return []string{"no time servers configured"}
+}
+
+func checkRedundantNTPServers(config *model.OpnSenseDocument) []string {
+	count := len(strings.Fields(config.System.TimeServers))
+	if count >= minTimeServers {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%d time servers configured", count)}
+}
+
+func checkTimezone(config *model.OpnSenseDocument) []string {
+	if strings.TrimSpace(config.System.Timezone) != "" {
+		return nil
+	}
+
+	return []string{"time zone not set"}
+}
+
+// Services
+
+func checkSNMPCommunity(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for _, community := range []string{config.Snmpd.ROCommunity, config.System.SNMPD.ROCommunity} {
+		switch strings.ToLower(strings.TrimSpace(community)) {
+		case "public", "private":
+			label := fmt.Sprintf("SNMP community is %q", community)
+			if !slices.Contains(affected, label) {
+				affected = append(affected, label)
+			}
+		}
+	}
+
+	return affected
+}
+
+// resolverEnabled reports whether the Unbound DNS resolver is enabled.
+func resolverEnabled(config *model.OpnSenseDocument) bool {
+	return isSet(config.Unbound.Enable) || isSet(config.OPNsense.UnboundPlus.General.Enabled)
+}
+
+func checkDNSSEC(config *model.OpnSenseDocument) []string {
+	if !resolverEnabled(config) ||
+		isSet(config.Unbound.Dnssec) || isSet(config.OPNsense.UnboundPlus.General.Dnssec) {
+		return nil
+	}
+
+	return []string{"DNS resolver enabled without DNSSEC validation"}
+}
+
+func checkDNSSECStripping(config *model.OpnSenseDocument) []string {
+	if !resolverEnabled(config) ||
+		isSet(config.Unbound.Dnssecstripped) || isSet(config.OPNsense.UnboundPlus.Advanced.Dnssecstripped) {
+		return nil
+	}
+
+	return []string{"DNS resolver accepts DNSSEC-stripped responses"}
+}
+
+func checkUnusedIPv6(config *model.OpnSenseDocument) []string {
+	if !isSet(config.System.IPv6Allow) {
+		return nil
+	}
+
+	for _, iface := range config.Interfaces.Items {
+		if iface.IPAddrv6 != "" {
+			return nil
+		}
+	}
+
+	return []string{"IPv6 allowed but no interface has an IPv6 address"}
+}
+
+// Firewall policy
+
+func checkAnyDestination(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		return isEnabledPassRule(rule) && rule.Destination.IsAny()
+	})
+}
+
+func checkAnySource(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		return isEnabledPassRule(rule) && rule.Source.IsAny()
+	})
+}
+
+func checkAnyService(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		if !isEnabledPassRule(rule) {
+			return false
+		}
+
+		switch strings.ToLower(rule.Protocol) {
+		case "", model.NetworkAny:
+			return true
+		case "tcp", "udp", "tcp/udp":
+			return rule.Destination.Port == ""
+		default:
+			return false
+		}
+	})
+}
+
+func checkDisabledRules(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		return rule.Disabled != ""
+	})
+}
+
+func checkRuleLogging(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		return rule.Disabled == "" && !rule.Log.Bool()
+	})
+}
+
+func checkWANICMP(config *model.OpnSenseDocument) []string {
+	return matchingRules(config, func(rule model.Rule) bool {
+		if !isEnabledPassRule(rule) || !rule.Interface.Contains(wanInterface) || !rule.Source.IsAny() {
+			return false
+		}
+
+		switch strings.ToLower(rule.Protocol) {
+		case "icmp", "icmp6", "ipv6-icmp":
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// VPN and cryptography
+
+// weakOpenVPNOptions lists custom option fragments that select weak ciphers or digests.
+func weakOpenVPNOptions() []string {
+	return []string{"bf-cbc", "des-cbc", "des-ede", "rc2-", "cast5-", "cipher none", "auth none", "auth md5"}
+}
+
+func checkOpenVPNTLS(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for i, server := range config.OpenVPN.Servers {
+		switch {
+		case server.Mode == openVPNSharedKeyMode:
+			affected = append(affected, openVPNLabel("server", i, server.Description)+": static shared key")
+		case strings.TrimSpace(server.TLS) == "":
+			affected = append(affected, openVPNLabel("server", i, server.Description)+": no TLS key")
+		}
+	}
+
+	return affected
+}
+
+func checkOpenVPNCertificates(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for i, server := range config.OpenVPN.Servers {
+		if server.Mode == openVPNSharedKeyMode {
+			continue
+		}
+
+		label := openVPNLabel("server", i, server.Description)
+		if server.Cert_ref == "" {
+			affected = append(affected, label+": no certificate")
+		}
+
+		if server.CA_ref == "" {
+			affected = append(affected, label+": no certificate authority")
+		}
+	}
+
+	for i, client := range config.OpenVPN.Clients {
+		if client.Mode != openVPNSharedKeyMode && client.CA_ref == "" {
+			affected = append(affected, openVPNLabel("client", i, client.Description)+": no certificate authority")
+		}
+	}
+
+	return affected
+}
+
+func checkOpenVPNCiphers(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for i, server := range config.OpenVPN.Servers {
+		label := openVPNLabel("server", i, server.Description)
+		affected = append(affected, weakOptions(label, server.Custom_options)...)
+
+		if bits, err := strconv.Atoi(strings.TrimSpace(server.DH_length)); err == nil && bits < minDHLength {
+			affected = append(affected, fmt.Sprintf("%s: %d-bit DH parameters", label, bits))
+		}
+	}
+
+	for i, client := range config.OpenVPN.Clients {
+		affected = append(affected, weakOptions(openVPNLabel("client", i, client.Description), client.Custom_options)...)
+	}
+
+	return affected
+}
+
+// weakOptions returns the weak cipher or digest selections in the custom options of an OpenVPN instance.
+func weakOptions(label, options string) []string {
+	var affected []string
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(options)), " ")
+	for _, weak := range weakOpenVPNOptions() {
+		if strings.Contains(normalized, weak) {
+			affected = append(affected, fmt.Sprintf("%s: custom option %q", label, weak))
+		}
+	}
+
+	return affected
+}
+
+func checkOpenVPNCompression(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for i, server := range config.OpenVPN.Servers {
+		if compressionEnabled(server.Compression) {
+			affected = append(affected, openVPNLabel("server", i, server.Description)+": compression "+server.Compression)
+		}
+	}
+
+	for i, client := range config.OpenVPN.Clients {
+		if compressionEnabled(client.Compression) {
+			affected = append(affected, openVPNLabel("client", i, client.Description)+": compression "+client.Compression)
+		}
+	}
+
+	return affected
+}
+
+// compressionEnabled reports whether an OpenVPN compression setting compresses traffic.
+func compressionEnabled(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "no", "stub", "stub-v2":
+		return false
+	default:
+		return true
+	}
+}
+
+func checkOpenVPNStrictUserCN(config *model.OpnSenseDocument) []string {
+	var affected []string
+
+	for i, server := range config.OpenVPN.Servers {
+		if server.Mode == openVPNRemoteAccessTLSMode && !server.Strictusercn.Bool() {
+			affected = append(affected, openVPNLabel("server", i, server.Description)+": strict user/CN matching disabled")
+		}
+	}
+
+	return affected
+}
+
+func checkMinimumTLSVersion(config *model.OpnSenseDocument) []string {
+	minProtocol := strings.TrimSpace(config.OPNsense.Trust.General.MinProtocol)
+
+	switch strings.ToLower(minProtocol) {
+	case "sslv3", "tlsv1", "tlsv1.0", "tlsv1.1":
+		return []string{"minimum TLS protocol is " + minProtocol}
+	default:
+		return nil
+	}
+}
+
+// Update settings
+
+func checkFirmwareMirror(config *model.OpnSenseDocument) []string {
+	mirror := strings.TrimSpace(config.System.Firmware.Mirror)
+	if mirror == "" || strings.HasPrefix(strings.ToLower(mirror), "https://") {
+		return nil
+	}
+
+	return []string{"firmware mirror " + mirror + " does not use HTTPS"}
+}
+
+func checkBogonUpdates(config *model.OpnSenseDocument) []string {
+	if config.System.Bogons.Interval != "never" {
+		return nil
+	}
+
+	return []string{"bogon list updates disabled"}
+}
+
+// Helpers
+
+// matchingRules returns the labels of the filter rules matching the predicate.
+func matchingRules(config *model.OpnSenseDocument, match func(rule model.Rule) bool) []string {
+	var affected []string
+
+	for i, rule := range config.FilterRules() {
+		if match(rule) {
+			affected = append(affected, ruleLabel(i, rule))
+		}
+	}
+
+	return affected
+}
+
+// isEnabledPassRule reports whether a rule is an enabled pass rule.
+func isEnabledPassRule(rule model.Rule) bool {
+	return rule.Disabled == "" && rule.Type == model.RuleTypePass
+}
+
+// targetsFirewall reports whether a rule destination includes the addresses of the firewall itself.
+func targetsFirewall(destination model.Destination) bool {
+	named := destination.Network == "(self)" || destination.Network == "wanip"
+
+	return destination.IsAny() || (named && !destination.Not.Bool())
+}
+
+// portMatches reports whether a port specification (single port or "from-to"/"from:to" range) includes the port.
+// Aliases never match.
+func portMatches(spec string, port int) bool {
+	from, to, isRange := strings.Cut(strings.ReplaceAll(spec, ":", "-"), "-")
+
+	low, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return false
+	}
+
+	if !isRange {
+		return low == port
+	}
+
+	high, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil {
+		return false
+	}
+
+	return port >= low && port <= high
+}
+
+// ruleLabel identifies a filter rule by its position and description.
+func ruleLabel(index int, rule model.Rule) string {
+	if rule.Descr == "" {
+		return fmt.Sprintf("filter.rule[%d]", index)
+	}
+
+	return fmt.Sprintf("filter.rule[%d] %q", index, rule.Descr)
+}
+
+// openVPNLabel identifies an OpenVPN server or client by its position and description.
+func openVPNLabel(kind string, index int, description string) string {
+	if description == "" {
+		return fmt.Sprintf("openvpn-%s[%d]", kind, index)
+	}
+
+	return fmt.Sprintf("openvpn-%s[%d] %q", kind, index, description)
+}
+
+// splitList splits a comma-separated configuration list into trimmed, non-empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// isSet reports whether an OPNsense option value is switched on.
+func isSet(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "yes", "on", "enabled", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// orDefault returns value, or fallback when value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// Applicability
+
+func whenSSHEnabled() *applicability {
+	return &applicability{
+		applies: func(config *model.OpnSenseDocument) bool { return config.System.SSH.IsEnabled() },
+		reason:  "SSH is disabled",
+	}
+}
+
+func whenSNMPConfigured() *applicability {
+	return &applicability{
+		applies: func(config *model.OpnSenseDocument) bool {
+			return strings.TrimSpace(config.Snmpd.ROCommunity) != "" ||
+				strings.TrimSpace(config.System.SNMPD.ROCommunity) != ""
+		},
+		reason: "SNMP is not configured",
+	}
+}
+
+func whenResolverEnabled() *applicability {
+	return &applicability{applies: resolverEnabled, reason: "the DNS resolver is disabled"}
+}
+
+func whenFilterRules() *applicability {
+	return &applicability{
+		applies: func(config *model.OpnSenseDocument) bool { return len(config.FilterRules()) > 0 },
+		reason:  "no firewall rules are configured",
+	}
+}
+
+func whenOpenVPN() *applicability {
+	return &applicability{
+		applies: func(config *model.OpnSenseDocument) bool {
+			return len(config.OpenVPN.Servers) > 0 || len(config.OpenVPN.Clients) > 0
+		},
+		reason: "no OpenVPN instances are configured",
+	}
+}
+
+func whenOpenVPNServers() *applicability {
+	return &applicability{
+		applies: func(config *model.OpnSenseDocument) bool { return len(config.OpenVPN.Servers) > 0 },
+		reason:  "no OpenVPN servers are configured",
+	}
+}
@@ -0,0 +1,237 @@
+// Package cis provides a compliance plugin for a CIS-style OPNsense benchmark.
+//
+// The controls follow docs/cis-like-firewall-reference.md. Every control has a
+// profile level (L1 for controls suitable for most environments, L2 for
+// high-security environments) and an automated check against the configuration.
+package cis
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+)
+
+// Benchmark profile levels.
+const (
+	// Level1 marks basic controls suitable for most environments.
+	Level1 = "L1"
+	// Level2 marks enhanced controls for high-security environments.
+	Level2 = "L2"
+)
+
+// levelKey is the control and finding metadata key holding the profile level.
+const levelKey = "level"
+
+// check evaluates a control and returns the configuration items that violate it.
+// An empty result means that the configuration complies with the control.
+type check func(config *model.OpnSenseDocument) []string
+
+// applicability decides whether a control applies to a configuration. The reason
+// explains why the control was skipped when it does not apply.
+type applicability struct {
+	applies func(config *model.OpnSenseDocument) bool
+	reason  string
+}
+
+// benchmarkControl pairs a control with its profile level, the configuration
+// component it covers, its automated check and, for controls that only apply to
+// configurations using a feature, its applicability.
+type benchmarkControl struct {
+	control   plugin.Control
+	level     string
+	component string
+	check     check
+	applies   *applicability
+}
+
+// evaluation is the outcome of a benchmark control against a configuration.
+type evaluation struct {
+	entry    *benchmarkControl
+	status   plugin.Status
+	affected []string
+}
+
+// Plugin implements the CompliancePlugin interface for the CIS-style benchmark.
+type Plugin struct {
+	benchmark []benchmarkControl
+	controls  []plugin.Control
+}
+
+// NewPlugin creates a new CIS compliance plugin.
+func NewPlugin() *Plugin {
+	p := &Plugin{benchmark: catalogue()}
+
+	p.controls = make([]plugin.Control, 0, len(p.benchmark))
+	for i := range p.benchmark {
+		entry := &p.benchmark[i]
+
+		entry.control.Tags = append(entry.control.Tags, "cis", levelTag(entry.level))
+		if entry.control.Metadata == nil {
+			entry.control.Metadata = make(map[string]string)
+		}
+
+		entry.control.Metadata[levelKey] = entry.level
+
+		p.controls = append(p.controls, entry.control)
+	}
+
+	return p
+}
+
+// Name returns the plugin name.
+func (cp *Plugin) Name() string {
+	return "cis"
+}
+
+// Version returns the plugin version.
+func (cp *Plugin) Version() string {
+	return "1.0.0"
+}
+
+// Description returns the plugin description.
+func (cp *Plugin) Description() string {
+	return "CIS-style OPNsense benchmark covering management access, authentication, logging, services, " +
+		"firewall policy, VPN cryptography and updates"
+}
+
+// RunChecks performs the benchmark checks and returns one finding per failed control.
+func (cp *Plugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	var findings []plugin.Finding
+
+	if config == nil {
+		return findings
+	}
+
+	for _, result := range cp.evaluate(config) {
+		if result.status != plugin.StatusFail {
+			continue
+		}
+
+		control := result.entry.control
+		findings = append(findings, plugin.Finding{
+			Type:           "compliance",
+			Title:          control.Title,
+			Description:    control.Description + ". Non-compliant: " + strings.Join(result.affected, "; "),
+			Recommendation: control.Remediation,
+			Component:      result.entry.component,
+			Reference:      control.ID,
+			Severity:       control.Severity,
+			References:     []string{control.ID},
+			Tags:           slices.Clone(control.Tags),
+			Metadata: map[string]string{
+				levelKey:   result.entry.level,
+				"affected": strings.Join(result.affected, "; "),
+			},
+		})
+	}
+
+	return findings
+}
+
+// EvaluateControls returns the result of every benchmark control, including passed and not applicable ones.
+func (cp *Plugin) EvaluateControls(config *model.OpnSenseDocument) []plugin.ControlResult {
+	if config == nil {
+		return nil
+	}
+
+	evaluations := cp.evaluate(config)
+	results := make([]plugin.ControlResult, 0, len(evaluations))
+
+	for _, result := range evaluations {
+		control := result.entry.control
+		controlResult := plugin.ControlResult{
+			ControlID: control.ID,
+			Title:     control.Title,
+			Category:  control.Category,
+			Severity:  control.Severity,
+			Status:    result.status,
+			Evidence:  result.affected,
+		}
+
+		switch result.status {
+		case plugin.StatusNotApplicable:
+			controlResult.Message = result.entry.applies.reason
+		case plugin.StatusPass:
+			controlResult.Message = "no non-compliant configuration found"
+		default:
+			controlResult.Message = "non-compliant configuration found"
+		}
+
+		results = append(results, controlResult)
+	}
+
+	return results
+}
+
+// evaluate runs the applicable checks of the benchmark.
+func (cp *Plugin) evaluate(config *model.OpnSenseDocument) []evaluation {
+	evaluations := make([]evaluation, 0, len(cp.benchmark))
+
+	for i := range cp.benchmark {
+		entry := &cp.benchmark[i]
+
+		if entry.applies != nil && !entry.applies.applies(config) {
+			evaluations = append(evaluations, evaluation{entry: entry, status: plugin.StatusNotApplicable})
+			continue
+		}
+
+		result := evaluation{entry: entry, status: plugin.StatusPass, affected: entry.check(config)}
+		if len(result.affected) > 0 {
+			result.status = plugin.StatusFail
+		}
+
+		evaluations = append(evaluations, result)
+	}
+
+	return evaluations
+}
+
+// GetControls returns all benchmark controls.
+func (cp *Plugin) GetControls() []plugin.Control {
+	return cp.controls
+}
+
+// GetControlByID returns a specific control by ID.
+func (cp *Plugin) GetControlByID(id string) (*plugin.Control, error) {
+	for _, control := range cp.controls {
+		if control.ID == id {
+			return &control, nil
+		}
+	}
+
+	return nil, plugin.ErrControlNotFound
+}
+
+// ValidateConfiguration validates the plugin configuration.
+func (cp *Plugin) ValidateConfiguration() error {
+	if len(cp.controls) == 0 {
+		return plugin.ErrNoControlsDefined
+	}
+
+	return nil
+}
+
+// ControlsForLevel returns the controls of a profile level. Level 2 includes the Level 1 controls,
+// as in the CIS profiles.
+func (cp *Plugin) ControlsForLevel(level string) []plugin.Control {
+	controls := make([]plugin.Control, 0, len(cp.benchmark))
+
+	for _, entry := range cp.benchmark {
+		if entry.level == level || (level == Level2 && entry.level == Level1) {
+			controls = append(controls, entry.control)
+		}
+	}
+
+	return controls
+}
+
+// levelTag returns the tag of a profile level.
+func levelTag(level string) string {
+	if level == Level2 {
+		return "level-2"
+	}
+
+	return "level-1"
+}
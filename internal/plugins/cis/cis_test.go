@@ -0,0 +1,398 @@
+package cis_test
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugins/cis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hardenedConfig returns a configuration that passes every benchmark control.
+func hardenedConfig() *model.OpnSenseDocument {
+	return &model.OpnSenseDocument{
+		System: model.System{
+			Hostname:    "fw",
+			Domain:      "example.com",
+			Timezone:    "Europe/Berlin",
+			TimeServers: "0.pool.ntp.org 1.pool.ntp.org 2.pool.ntp.org",
+			WebGUI: model.WebGUIConfig{
+				Protocol:       "https",
+				SSLCertRef:     "5f7d1a8e9b2c4",
+				Interfaces:     "lan",
+				SessionTimeout: "10",
+			},
+			SSH: model.SSHConfig{Group: "admins", Enabled: "enabled", Port: "2222"},
+			User: []model.User{
+				{Name: "root", UID: "0", Disabled: true, Password: "$2y$10$abcdefghijklmnopqrstuv"},
+				{
+					Name: "alice", UID: "2000", Groupname: "admins", Password: "$2y$10$abcdefghijklmnopqrstuv",
+					OTPSeed: "JBSWY3DPEHPK3PXP",
+				},
+			},
+			Group:      []model.Group{{Name: "admins", Gid: "1999", Member: "2000", Priv: "page-all"}},
+			AuthServer: []model.AuthServer{{Type: "ldap", Name: "Directory"}},
+			Firmware:   model.Firmware{Mirror: "https://pkg.opnsense.org"},
+		},
+		Filter: model.Filter{Rule: []model.Rule{
+			{
+				Type: "pass", Descr: "LAN to web proxy", Interface: model.InterfaceList{"lan"}, Protocol: "tcp",
+				Source: model.Source{Network: "lan"}, Destination: model.Destination{Network: "10.0.0.5", Port: "3128"},
+				Log: true,
+			},
+			{
+				Type: "block", Descr: "Default deny", Interface: model.InterfaceList{"lan"},
+				Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}, Log: true,
+			},
+		}},
+		Syslog: model.Syslog{Enable: true, Remoteserver: "192.0.2.10", Filter: true, Auth: true},
+		OpenVPN: model.OpenVPN{Servers: []model.OpenVPNServer{{
+			Mode: "server_tls_user", Description: "Remote access", TLS: "key", Cert_ref: "cert", CA_ref: "ca",
+			DH_length: "4096", Strictusercn: true, Compression: "no", Custom_options: "data-ciphers AES-256-GCM",
+		}}},
+	}
+}
+
+// findingsByID indexes findings by their control ID.
+func findingsByID(findings []plugin.Finding) map[string]plugin.Finding {
+	byID := make(map[string]plugin.Finding, len(findings))
+	for _, finding := range findings {
+		byID[finding.Reference] = finding
+	}
+
+	return byID
+}
+
+func TestCISPlugin_Metadata(t *testing.T) {
+	p := cis.NewPlugin()
+
+	assert.Equal(t, "cis", p.Name())
+	assert.Equal(t, "1.0.0", p.Version())
+	assert.NotEmpty(t, p.Description())
+	require.NoError(t, p.ValidateConfiguration())
+}
+
+func TestCISPlugin_Controls(t *testing.T) {
+	p := cis.NewPlugin()
+	controls := p.GetControls()
+
+	categories := make(map[string]bool)
+	ids := make(map[string]bool)
+
+	for _, control := range controls {
+		assert.False(t, ids[control.ID], "duplicate control ID %s", control.ID)
+		ids[control.ID] = true
+		categories[control.Category] = true
+
+		assert.NotEmpty(t, control.Title, control.ID)
+		assert.NotEmpty(t, control.Description, control.ID)
+		assert.NotEmpty(t, control.Rationale, control.ID)
+		assert.NotEmpty(t, control.Remediation, control.ID)
+		assert.Contains(t, []string{"high", "medium", "low"}, control.Severity, control.ID)
+		assert.Contains(t, []string{cis.Level1, cis.Level2}, control.Metadata["level"], control.ID)
+		assert.Contains(t, control.Tags, "cis", control.ID)
+	}
+
+	assert.Len(t, categories, 7, "every benchmark area should have controls")
+
+	control, err := p.GetControlByID("CIS-MGMT-001")
+	require.NoError(t, err)
+	assert.Equal(t, cis.Level1, control.Metadata["level"])
+	assert.Contains(t, control.Tags, "level-1")
+
+	_, err = p.GetControlByID("CIS-XXX-999")
+	require.ErrorIs(t, err, plugin.ErrControlNotFound)
+
+	level1 := p.ControlsForLevel(cis.Level1)
+	assert.NotEmpty(t, level1)
+	assert.Less(t, len(level1), len(controls))
+	assert.Len(t, p.ControlsForLevel(cis.Level2), len(controls), "level 2 includes level 1")
+}
+
+func TestCISPlugin_HardenedConfiguration(t *testing.T) {
+	findings := cis.NewPlugin().RunChecks(hardenedConfig())
+	assert.Empty(t, findings)
+}
+
+func TestCISPlugin_HostSpecificRules(t *testing.T) {
+	cfg := hardenedConfig()
+	cfg.Filter.Rule = append(cfg.Filter.Rule,
+		model.Rule{
+			Type: "pass", Descr: "Backup host to NAS", Interface: model.InterfaceList{"lan"}, Protocol: "tcp",
+			Source:      model.Source{Address: "10.0.0.5"},
+			Destination: model.Destination{Address: "10.0.1.5", Port: "22"},
+			Log:         true,
+		},
+		model.Rule{
+			Type: "pass", Descr: "Partner ping", Interface: model.InterfaceList{"wan"}, Protocol: "icmp",
+			Source:      model.Source{Address: "198.51.100.7"},
+			Destination: model.Destination{Address: "192.0.2.1"},
+			Log:         true,
+		},
+	)
+
+	assert.Empty(t, cis.NewPlugin().RunChecks(cfg))
+}
+
+func TestCISPlugin_NilConfiguration(t *testing.T) {
+	assert.Empty(t, cis.NewPlugin().RunChecks(nil))
+}
+
+func TestCISPlugin_RunChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *model.OpnSenseDocument)
+		id       string
+		affected string
+	}{
+		{
+			name:     "http web GUI",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.WebGUI.Protocol = "http" },
+			id:       "CIS-MGMT-001",
+			affected: "web GUI protocol is http",
+		},
+		{
+			name:     "default session timeout",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.WebGUI.SessionTimeout = "" },
+			id:       "CIS-MGMT-003",
+			affected: "session timeout not set (default 240 minutes)",
+		},
+		{
+			name:     "web GUI on wan",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.WebGUI.Interfaces = "lan,wan" },
+			id:       "CIS-MGMT-004",
+			affected: "web GUI listens on wan",
+		},
+		{
+			name:     "dns rebind check disabled",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.WebGUI.NoDNSRebindCheck = "1" },
+			id:       "CIS-MGMT-005",
+			affected: "DNS rebind check disabled",
+		},
+		{
+			name:     "ssh root login",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.SSH.PermitRootLogin = "1" },
+			id:       "CIS-MGMT-006",
+			affected: "SSH permits root login",
+		},
+		{
+			name: "ssh allowed from wan",
+			modify: func(cfg *model.OpnSenseDocument) {
+				cfg.Filter.Rule = append(cfg.Filter.Rule, model.Rule{
+					Type: "pass", Descr: "Remote admin", Interface: model.InterfaceList{"wan"}, Protocol: "tcp",
+					Source:      model.Source{Network: "198.51.100.0/24"},
+					Destination: model.Destination{Network: "wanip", Port: "2200:2300"},
+					Log:         true,
+				})
+			},
+			id:       "CIS-MGMT-008",
+			affected: `filter.rule[2] "Remote admin": SSH (2222)`,
+		},
+		{
+			name:     "weak password hash",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.User[1].Password = "$1$salt$hash" },
+			id:       "CIS-AUTH-001",
+			affected: "user alice (md5-crypt)",
+		},
+		{
+			name:     "missing password hash",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.User[1].Password = "" },
+			id:       "CIS-AUTH-001",
+			affected: "user alice (none)",
+		},
+		{
+			name:     "root enabled",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.User[0].Disabled = false },
+			id:       "CIS-AUTH-002",
+			affected: "user root is enabled",
+		},
+		{
+			name:     "admin without totp",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.User[1].OTPSeed = "" },
+			id:       "CIS-AUTH-004",
+			affected: "administrator alice has no TOTP token",
+		},
+		{
+			name:     "auth events not forwarded",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.Syslog.Auth = false },
+			id:       "CIS-LOG-002",
+			affected: "authentication events are not forwarded",
+		},
+		{
+			name:     "single time server",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.TimeServers = "pool.ntp.org" },
+			id:       "CIS-LOG-004",
+			affected: "1 time servers configured",
+		},
+		{
+			name:     "default snmp community",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.Snmpd.ROCommunity = "public" },
+			id:       "CIS-SVC-001",
+			affected: `SNMP community is "public"`,
+		},
+		{
+			name:     "resolver without dnssec",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.Unbound.Enable = "on" },
+			id:       "CIS-SVC-002",
+			affected: "DNS resolver enabled without DNSSEC validation",
+		},
+		{
+			name:     "unused ipv6",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.IPv6Allow = "1" },
+			id:       "CIS-SVC-004",
+			affected: "IPv6 allowed but no interface has an IPv6 address",
+		},
+		{
+			name:     "disabled rule",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.Filter.Rule[0].Disabled = "1" },
+			id:       "CIS-FW-004",
+			affected: `filter.rule[0] "LAN to web proxy"`,
+		},
+		{
+			name:     "rule without logging",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.Filter.Rule[1].Log = false },
+			id:       "CIS-FW-005",
+			affected: `filter.rule[1] "Default deny"`,
+		},
+		{
+			name: "icmp from any on wan",
+			modify: func(cfg *model.OpnSenseDocument) {
+				cfg.Filter.Rule = append(cfg.Filter.Rule, model.Rule{
+					Type: "pass", Descr: "Ping", Interface: model.InterfaceList{"wan"}, Protocol: "icmp",
+					Source: model.Source{Any: "1"}, Destination: model.Destination{Network: "10.0.0.5"}, Log: true,
+				})
+			},
+			id:       "CIS-FW-006",
+			affected: `filter.rule[2] "Ping"`,
+		},
+		{
+			name:     "openvpn shared key",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.OpenVPN.Servers[0].Mode = "p2p_shared_key" },
+			id:       "CIS-VPN-001",
+			affected: `openvpn-server[0] "Remote access": static shared key`,
+		},
+		{
+			name:     "openvpn without ca",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.OpenVPN.Servers[0].CA_ref = "" },
+			id:       "CIS-VPN-002",
+			affected: `openvpn-server[0] "Remote access": no certificate authority`,
+		},
+		{
+			name: "openvpn weak crypto",
+			modify: func(cfg *model.OpnSenseDocument) {
+				cfg.OpenVPN.Servers[0].Custom_options = "cipher BF-CBC;\nauth   MD5"
+				cfg.OpenVPN.Servers[0].DH_length = "1024"
+			},
+			id: "CIS-VPN-003",
+			affected: `openvpn-server[0] "Remote access": custom option "bf-cbc"; ` +
+				`openvpn-server[0] "Remote access": custom option "auth md5"; ` +
+				`openvpn-server[0] "Remote access": 1024-bit DH parameters`,
+		},
+		{
+			name:     "openvpn compression",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.OpenVPN.Servers[0].Compression = "lz4-v2" },
+			id:       "CIS-VPN-004",
+			affected: `openvpn-server[0] "Remote access": compression lz4-v2`,
+		},
+		{
+			name:     "legacy tls",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.OPNsense.Trust.General.MinProtocol = "TLSv1.1" },
+			id:       "CIS-VPN-006",
+			affected: "minimum TLS protocol is TLSv1.1",
+		},
+		{
+			name:     "http firmware mirror",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.Firmware.Mirror = "http://mirror.example.com" },
+			id:       "CIS-UPD-001",
+			affected: "firmware mirror http://mirror.example.com does not use HTTPS",
+		},
+		{
+			name:     "bogon updates disabled",
+			modify:   func(cfg *model.OpnSenseDocument) { cfg.System.Bogons.Interval = "never" },
+			id:       "CIS-UPD-002",
+			affected: "bogon list updates disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := hardenedConfig()
+			tt.modify(cfg)
+
+			byID := findingsByID(cis.NewPlugin().RunChecks(cfg))
+			require.Contains(t, byID, tt.id)
+
+			finding := byID[tt.id]
+			assert.Equal(t, tt.affected, finding.Metadata["affected"])
+			assert.Contains(t, finding.Description, tt.affected)
+			assert.Equal(t, []string{tt.id}, finding.References)
+			assert.NotEmpty(t, finding.Component)
+			assert.NotEmpty(t, finding.Recommendation)
+			assert.Contains(t, []string{cis.Level1, cis.Level2}, finding.Metadata["level"])
+		})
+	}
+}
+
+func TestCISPlugin_EmptyConfiguration(t *testing.T) {
+	byID := findingsByID(cis.NewPlugin().RunChecks(&model.OpnSenseDocument{}))
+
+	for _, id := range []string{"CIS-MGMT-001", "CIS-LOG-001", "CIS-LOG-003", "CIS-AUTH-003"} {
+		assert.Contains(t, byID, id)
+	}
+
+	for _, id := range []string{"CIS-MGMT-006", "CIS-SVC-002", "CIS-VPN-001", "CIS-FW-005"} {
+		assert.NotContains(t, byID, id, "controls without applicable configuration should pass")
+	}
+}
+
+func TestCISPlugin_EvaluateControls(t *testing.T) {
+	p := cis.NewPlugin()
+
+	t.Run("hardened", func(t *testing.T) {
+		results := p.EvaluateControls(hardenedConfig())
+		require.Len(t, results, len(p.GetControls()))
+
+		statuses := make(map[string]plugin.Status, len(results))
+		for _, result := range results {
+			statuses[result.ControlID] = result.Status
+		}
+
+		assert.Equal(t, plugin.StatusPass, statuses["CIS-VPN-001"])
+		assert.Equal(t, plugin.StatusNotApplicable, statuses["CIS-SVC-001"])
+		assert.Equal(t, plugin.StatusNotApplicable, statuses["CIS-SVC-002"])
+		assert.NotContains(t, statuses, plugin.StatusFail)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		byID := make(map[string]plugin.ControlResult)
+		for _, result := range p.EvaluateControls(&model.OpnSenseDocument{}) {
+			byID[result.ControlID] = result
+		}
+
+		vpn := byID["CIS-VPN-001"]
+		assert.Equal(t, plugin.StatusNotApplicable, vpn.Status)
+		assert.Equal(t, "no OpenVPN servers are configured", vpn.Message)
+
+		https := byID["CIS-MGMT-001"]
+		assert.Equal(t, plugin.StatusFail, https.Status)
+		assert.NotEmpty(t, https.Evidence)
+		assert.Equal(t, "high", https.Severity)
+	})
+
+	t.Run("findings match failed controls", func(t *testing.T) {
+		cfg := hardenedConfig()
+		cfg.System.WebGUI.Protocol = "http"
+
+		findings := p.RunChecks(cfg)
+		require.Len(t, findings, 1)
+		assert.Equal(t, "high", findings[0].Severity)
+
+		for _, result := range p.EvaluateControls(cfg) {
+			assert.Equal(t, result.ControlID == findings[0].Reference, result.Status == plugin.StatusFail, result.ControlID)
+		}
+	})
+
+	assert.Nil(t, p.EvaluateControls(nil))
+}
@@ -0,0 +1,585 @@
+package cis
+
+import "github.com/EvilBit-Labs/opnDossier/internal/plugin"
+
+// Control categories of the benchmark.
+const (
+	categoryManagement     = "Management Plane Access"
+	categoryAuthentication = "Authentication"
+	categoryLogging        = "Logging and Time Synchronization"
+	categoryServices       = "Services"
+	categoryFirewall       = "Firewall Policy"
+	categoryVPN            = "VPN and Cryptography"
+	categoryUpdates        = "Update Settings"
+)
+
+// Control severities.
+const (
+	severityHigh   = "high"
+	severityMedium = "medium"
+	severityLow    = "low"
+)
+
+// catalogue returns the benchmark controls in report order.
+//
+//nolint:funlen,maintidx // The catalogue is a flat list of control definitions.
+func catalogue() []benchmarkControl {
+	return []benchmarkControl{
+		// Management plane access
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-001",
+				Title:       "HTTPS Web Management",
+				Description: "The web GUI must be served over HTTPS",
+				Category:    categoryManagement,
+				Severity:    severityHigh,
+				Rationale:   "HTTPS encrypts management traffic, including credentials, and authenticates the firewall",
+				Remediation: "Set the protocol to HTTPS in System > Settings > Administration",
+				Tags:        []string{"webgui", "encryption"},
+			},
+			level:     Level1,
+			component: "system.webgui",
+			check:     checkWebGUIHTTPS,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-002",
+				Title:       "Web GUI Certificate",
+				Description: "The web GUI must use an explicitly assigned TLS certificate",
+				Category:    categoryManagement,
+				Severity:    severityLow,
+				Rationale:   "A managed certificate from a trusted CA prevents administrators from accepting spoofed certificates",
+				Remediation: "Import a certificate signed by a trusted CA and select it for the web GUI",
+				Tags:        []string{"webgui", "certificates"},
+			},
+			level:     Level2,
+			component: "system.webgui",
+			check:     checkWebGUICertificate,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-003",
+				Title:       "Session Timeout",
+				Description: "Web GUI sessions must time out after at most 10 minutes of inactivity",
+				Category:    categoryManagement,
+				Severity:    severityMedium,
+				Rationale:   "Short session timeouts prevent the abuse of abandoned administrative sessions",
+				Remediation: "Set the session timeout to 10 minutes or less in System > Settings > Administration",
+				Tags:        []string{"webgui", "session"},
+			},
+			level:     Level1,
+			component: "system.webgui",
+			check:     checkSessionTimeout,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-004",
+				Title:       "Web GUI Listen Interfaces",
+				Description: "The web GUI must only listen on dedicated management interfaces",
+				Category:    categoryManagement,
+				Severity:    severityMedium,
+				Rationale:   "Binding the web GUI to management interfaces keeps it unreachable from untrusted networks",
+				Remediation: "Select the management interfaces as listen interfaces in System > Settings > Administration",
+				Tags:        []string{"webgui", "network-exposure"},
+			},
+			level:     Level2,
+			component: "system.webgui",
+			check:     checkWebGUIListenInterfaces,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-005",
+				Title:       "DNS Rebind Check",
+				Description: "The DNS rebind check of the web GUI must be enabled",
+				Category:    categoryManagement,
+				Severity:    severityMedium,
+				Rationale:   "The DNS rebind check protects the web GUI against DNS rebinding attacks",
+				Remediation: "Clear 'Disable DNS Rebinding Checks' in System > Settings > Administration",
+				Tags:        []string{"webgui", "dns-rebinding"},
+			},
+			level:     Level1,
+			component: "system.webgui",
+			check:     checkDNSRebindCheck,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-006",
+				Title:       "SSH Root Login",
+				Description: "SSH must not permit root logins",
+				Category:    categoryManagement,
+				Severity:    severityHigh,
+				Rationale:   "Direct root logins bypass individual accountability and are the primary brute-force target",
+				Remediation: "Clear 'Permit root user login' in System > Settings > Administration > Secure Shell",
+				Tags:        []string{"ssh", "root"},
+			},
+			level:     Level1,
+			component: "system.ssh",
+			check:     checkSSHRootLogin,
+			applies:   whenSSHEnabled(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-007",
+				Title:       "SSH Password Authentication",
+				Description: "SSH must only accept key-based authentication",
+				Category:    categoryManagement,
+				Severity:    severityMedium,
+				Rationale:   "Key-based authentication is not susceptible to password guessing",
+				Remediation: "Clear 'Permit password login' and configure authorized keys for administrators",
+				Tags:        []string{"ssh", "authentication"},
+			},
+			level:     Level2,
+			component: "system.ssh",
+			check:     checkSSHPasswordAuth,
+			applies:   whenSSHEnabled(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-MGMT-008",
+				Title:       "Management Services Not Exposed on WAN",
+				Description: "Firewall rules must not allow the web GUI or SSH from the WAN",
+				Category:    categoryManagement,
+				Severity:    severityHigh,
+				Rationale:   "Management services reachable from the internet are exposed to scanning and brute-force attacks",
+				Remediation: "Remove WAN rules allowing management ports and reach the firewall through a VPN instead",
+				Tags:        []string{"wan", "network-exposure", "firewall-rules"},
+			},
+			level:     Level1,
+			component: "filter.rule",
+			check:     checkManagementOnWAN,
+		},
+
+		// Authentication
+		{
+			control: plugin.Control{
+				ID:          "CIS-AUTH-001",
+				Title:       "Strong Password Hashes",
+				Description: "Enabled accounts must store their passwords with a strong hash scheme",
+				Category:    categoryAuthentication,
+				Severity:    severityHigh,
+				Rationale:   "Weak hash schemes such as MD5-crypt or DES can be cracked quickly once the configuration leaks",
+				Remediation: "Reset the passwords of the affected accounts so that they are stored with bcrypt",
+				Tags:        []string{"passwords", "credentials"},
+			},
+			level:     Level1,
+			component: "system.user",
+			check:     checkPasswordHashes,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-AUTH-002",
+				Title:       "Default Account Management",
+				Description: "The default root account must be disabled in favour of named administrators",
+				Category:    categoryAuthentication,
+				Severity:    severityMedium,
+				Rationale:   "Named administrator accounts provide accountability that a shared root account cannot",
+				Remediation: "Create named administrator accounts and disable the root account",
+				Tags:        []string{"default-accounts", "accountability"},
+			},
+			level:     Level2,
+			component: "system.user",
+			check:     checkRootAccount,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-AUTH-003",
+				Title:       "Central Authentication",
+				Description: "An LDAP or RADIUS authentication server must be configured",
+				Category:    categoryAuthentication,
+				Severity:    severityLow,
+				Rationale:   "Central authentication gives a single place to grant, review and revoke access",
+				Remediation: "Add an LDAP or RADIUS server in System > Access > Servers and use it for administrator logins",
+				Tags:        []string{"aaa", "ldap", "radius"},
+			},
+			level:     Level2,
+			component: "system.authserver",
+			check:     checkCentralAuthentication,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-AUTH-004",
+				Title:       "Multi-Factor Authentication for Administrators",
+				Description: "Every enabled administrator must have a TOTP token enrolled",
+				Category:    categoryAuthentication,
+				Severity:    severityMedium,
+				Rationale:   "A second factor prevents a stolen or guessed password from granting administrative access",
+				Remediation: "Configure a TOTP authentication server and enrol a token for every administrator",
+				Tags:        []string{"mfa", "totp", "administrators"},
+			},
+			level:     Level2,
+			component: "system.user",
+			check:     checkAdministratorMFA,
+		},
+
+		// Logging and time synchronization
+		{
+			control: plugin.Control{
+				ID:          "CIS-LOG-001",
+				Title:       "Remote Syslog",
+				Description: "Logs must be sent to a remote syslog server",
+				Category:    categoryLogging,
+				Severity:    severityMedium,
+				Rationale:   "Remote log storage keeps an audit trail that survives the compromise or loss of the firewall",
+				Remediation: "Add a remote syslog destination in System > Settings > Logging / targets",
+				Tags:        []string{"syslog", "audit-trail"},
+			},
+			level:     Level1,
+			component: "syslog",
+			check:     checkRemoteSyslog,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-LOG-002",
+				Title:       "Security Event Forwarding",
+				Description: "Firewall and authentication events must be forwarded to the remote syslog server",
+				Category:    categoryLogging,
+				Severity:    severityMedium,
+				Rationale:   "Firewall and authentication events are needed to detect and investigate attacks",
+				Remediation: "Enable the firewall and authentication categories for remote logging",
+				Tags:        []string{"syslog", "security-monitoring"},
+			},
+			level:     Level2,
+			component: "syslog",
+			check:     checkSecurityEventForwarding,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-LOG-003",
+				Title:       "NTP Servers",
+				Description: "At least one NTP server must be configured",
+				Category:    categoryLogging,
+				Severity:    severityMedium,
+				Rationale:   "Accurate time is required for log correlation and certificate validation",
+				Remediation: "Configure time servers in System > Settings > General",
+				Tags:        []string{"ntp", "time"},
+			},
+			level:     Level1,
+			component: "system.timeservers",
+			check:     checkNTPServers,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-LOG-004",
+				Title:       "Redundant Time Sources",
+				Description: "At least three NTP servers must be configured",
+				Category:    categoryLogging,
+				Severity:    severityLow,
+				Rationale:   "Three or more time sources allow NTP to detect and discard a misbehaving server",
+				Remediation: "Configure at least three independent time servers",
+				Tags:        []string{"ntp", "time", "redundancy"},
+			},
+			level:     Level2,
+			component: "system.timeservers",
+			check:     checkRedundantNTPServers,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-LOG-005",
+				Title:       "Time Zone",
+				Description: "The system time zone must be configured",
+				Category:    categoryLogging,
+				Severity:    severityLow,
+				Rationale:   "A configured time zone keeps log timestamps consistent with other systems",
+				Remediation: "Set the time zone in System > Settings > General",
+				Tags:        []string{"time", "timezone"},
+			},
+			level:     Level1,
+			component: "system.timezone",
+			check:     checkTimezone,
+		},
+
+		// Services
+		{
+			control: plugin.Control{
+				ID:          "CIS-SVC-001",
+				Title:       "SNMP Community String",
+				Description: "SNMP must not use a default community string",
+				Category:    categoryServices,
+				Severity:    severityHigh,
+				Rationale:   "Default community strings are the first values attackers try and disclose the configuration",
+				Remediation: "Set a unique community string or disable SNMP",
+				Tags:        []string{"snmp", "default-credentials"},
+			},
+			level:     Level1,
+			component: "snmpd",
+			check:     checkSNMPCommunity,
+			applies:   whenSNMPConfigured(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-SVC-002",
+				Title:       "DNSSEC Enablement",
+				Description: "DNSSEC validation must be enabled on the DNS resolver",
+				Category:    categoryServices,
+				Severity:    severityMedium,
+				Rationale:   "DNSSEC protects resolver clients against forged DNS responses",
+				Remediation: "Enable DNSSEC support in Services > Unbound DNS > General",
+				Tags:        []string{"dns", "dnssec"},
+			},
+			level:     Level1,
+			component: "unbound",
+			check:     checkDNSSEC,
+			applies:   whenResolverEnabled(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-SVC-003",
+				Title:       "DNSSEC Stripping Protection",
+				Description: "The DNS resolver must reject DNSSEC-stripped responses for signed zones",
+				Category:    categoryServices,
+				Severity:    severityLow,
+				Rationale:   "Without hardening, an attacker can downgrade signed zones by removing their DNSSEC data",
+				Remediation: "Enable 'Harden DNSSEC Data' in Services > Unbound DNS > Advanced",
+				Tags:        []string{"dns", "dnssec"},
+			},
+			level:     Level2,
+			component: "unbound",
+			check:     checkDNSSECStripping,
+			applies:   whenResolverEnabled(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-SVC-004",
+				Title:       "IPv6 Disablement",
+				Description: "IPv6 must be disabled when no interface uses it",
+				Category:    categoryServices,
+				Severity:    severityLow,
+				Rationale:   "Disabling unused protocols reduces the attack surface",
+				Remediation: "Clear 'Allow IPv6' in Firewall > Settings > Advanced",
+				Tags:        []string{"ipv6", "attack-surface"},
+			},
+			level:     Level2,
+			component: "system.ipv6allow",
+			check:     checkUnusedIPv6,
+		},
+
+		// Firewall policy
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-001",
+				Title:       "Destination Field Restrictions",
+				Description: "Pass rules must not use 'any' as destination",
+				Category:    categoryFirewall,
+				Severity:    severityMedium,
+				Rationale:   "Explicit destinations limit the hosts that allowed traffic can reach",
+				Remediation: "Restrict the destination of the affected rules to the required hosts or networks",
+				Tags:        []string{"firewall-rules", "least-privilege"},
+			},
+			level:     Level2,
+			component: "filter.rule",
+			check:     checkAnyDestination,
+			applies:   whenFilterRules(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-002",
+				Title:       "Source Field Restrictions",
+				Description: "Pass rules must not use 'any' as source",
+				Category:    categoryFirewall,
+				Severity:    severityHigh,
+				Rationale:   "Explicit sources limit who can initiate allowed traffic",
+				Remediation: "Restrict the source of the affected rules to the required hosts or networks",
+				Tags:        []string{"firewall-rules", "least-privilege"},
+			},
+			level:     Level1,
+			component: "filter.rule",
+			check:     checkAnySource,
+			applies:   whenFilterRules(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-003",
+				Title:       "Service Field Restrictions",
+				Description: "Pass rules must be limited to specific protocols and ports",
+				Category:    categoryFirewall,
+				Severity:    severityMedium,
+				Rationale:   "Explicit services limit allowed traffic to the applications that need it",
+				Remediation: "Set the protocol and destination ports of the affected rules",
+				Tags:        []string{"firewall-rules", "least-privilege"},
+			},
+			level:     Level2,
+			component: "filter.rule",
+			check:     checkAnyService,
+			applies:   whenFilterRules(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-004",
+				Title:       "Unused Policy Removal",
+				Description: "Disabled firewall rules must be removed",
+				Category:    categoryFirewall,
+				Severity:    severityLow,
+				Rationale:   "Stale rules obscure the effective policy and can be re-enabled by mistake",
+				Remediation: "Delete the disabled rules once they are no longer needed",
+				Tags:        []string{"firewall-rules", "rule-hygiene"},
+			},
+			level:     Level1,
+			component: "filter.rule",
+			check:     checkDisabledRules,
+			applies:   whenFilterRules(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-005",
+				Title:       "Firewall Rule Logging",
+				Description: "Logging must be enabled on all firewall rules",
+				Category:    categoryFirewall,
+				Severity:    severityMedium,
+				Rationale:   "Rule logging provides the audit trail needed for troubleshooting and incident response",
+				Remediation: "Enable 'Log packets that are handled by this rule' on the affected rules",
+				Tags:        []string{"firewall-rules", "logging"},
+			},
+			level:     Level1,
+			component: "filter.rule",
+			check:     checkRuleLogging,
+			applies:   whenFilterRules(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-FW-006",
+				Title:       "ICMP Configuration",
+				Description: "ICMP must not be allowed from any source on the WAN",
+				Category:    categoryFirewall,
+				Severity:    severityLow,
+				Rationale:   "Unrestricted ICMP from the internet aids reconnaissance and ICMP-based attacks",
+				Remediation: "Restrict WAN ICMP rules to the required ICMP types and sources",
+				Tags:        []string{"firewall-rules", "icmp", "wan"},
+			},
+			level:     Level2,
+			component: "filter.rule",
+			check:     checkWANICMP,
+			applies:   whenFilterRules(),
+		},
+
+		// VPN and cryptography
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-001",
+				Title:       "OpenVPN TLS Authentication",
+				Description: "OpenVPN servers must use TLS with a TLS authentication key",
+				Category:    categoryVPN,
+				Severity:    severityHigh,
+				Rationale: "A TLS key protects the server from unauthenticated handshakes; " +
+					"static shared keys lack forward secrecy",
+				Remediation: "Use a TLS-based server mode and generate a TLS authentication or encryption key",
+				Tags:        []string{"openvpn", "tls"},
+			},
+			level:     Level1,
+			component: "openvpn",
+			check:     checkOpenVPNTLS,
+			applies:   whenOpenVPNServers(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-002",
+				Title:       "VPN Certificates",
+				Description: "OpenVPN instances must reference a certificate and a certificate authority",
+				Category:    categoryVPN,
+				Severity:    severityHigh,
+				Rationale:   "Certificates from a trusted CA prevent man-in-the-middle attacks on VPN connections",
+				Remediation: "Select a server or client certificate and its CA for every OpenVPN instance",
+				Tags:        []string{"openvpn", "certificates"},
+			},
+			level:     Level1,
+			component: "openvpn",
+			check:     checkOpenVPNCertificates,
+			applies:   whenOpenVPN(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-003",
+				Title:       "OpenVPN Ciphers",
+				Description: "OpenVPN must use strong ciphers, digests and Diffie-Hellman parameters",
+				Category:    categoryVPN,
+				Severity:    severityHigh,
+				Rationale:   "Weak ciphers, MD5 digests and short DH parameters can be broken by capable attackers",
+				Remediation: "Use AES-GCM or ChaCha20-Poly1305, a SHA-2 digest and DH parameters of at least 2048 bits",
+				Tags:        []string{"openvpn", "ciphers", "cryptography"},
+			},
+			level:     Level1,
+			component: "openvpn",
+			check:     checkOpenVPNCiphers,
+			applies:   whenOpenVPN(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-004",
+				Title:       "OpenVPN Compression",
+				Description: "OpenVPN compression must be disabled",
+				Category:    categoryVPN,
+				Severity:    severityMedium,
+				Rationale:   "Compression before encryption enables VORACLE-style plaintext recovery attacks",
+				Remediation: "Set compression to 'No' on the affected OpenVPN instances",
+				Tags:        []string{"openvpn", "compression"},
+			},
+			level:     Level2,
+			component: "openvpn",
+			check:     checkOpenVPNCompression,
+			applies:   whenOpenVPN(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-005",
+				Title:       "OpenVPN Strict User CN",
+				Description: "Remote access OpenVPN servers must enforce a match between username and certificate CN",
+				Category:    categoryVPN,
+				Severity:    severityLow,
+				Rationale: "Matching the username to the certificate prevents users from " +
+					"logging in with another user's certificate",
+				Remediation: "Enable 'Strict User/CN Matching' on the affected servers",
+				Tags:        []string{"openvpn", "certificates", "authentication"},
+			},
+			level:     Level2,
+			component: "openvpn",
+			check:     checkOpenVPNStrictUserCN,
+			applies:   whenOpenVPNServers(),
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-VPN-006",
+				Title:       "Minimum TLS Version",
+				Description: "The system-wide minimum TLS protocol version must be TLS 1.2 or later",
+				Category:    categoryVPN,
+				Severity:    severityMedium,
+				Rationale:   "TLS 1.0 and 1.1 have known weaknesses and are deprecated",
+				Remediation: "Set the minimum protocol to TLSv1.2 in System > Trust > Settings",
+				Tags:        []string{"tls", "cryptography"},
+			},
+			level:     Level1,
+			component: "opnsense.trust",
+			check:     checkMinimumTLSVersion,
+		},
+
+		// Update settings
+		{
+			control: plugin.Control{
+				ID:          "CIS-UPD-001",
+				Title:       "Secure Firmware Mirror",
+				Description: "Firmware updates must be downloaded over HTTPS",
+				Category:    categoryUpdates,
+				Severity:    severityMedium,
+				Rationale:   "Fetching updates over an encrypted channel prevents tampering and downgrade attacks in transit",
+				Remediation: "Select an HTTPS mirror in System > Firmware > Settings",
+				Tags:        []string{"firmware", "updates"},
+			},
+			level:     Level1,
+			component: "system.firmware",
+			check:     checkFirmwareMirror,
+		},
+		{
+			control: plugin.Control{
+				ID:          "CIS-UPD-002",
+				Title:       "Bogon List Updates",
+				Description: "Bogon network lists must be updated periodically",
+				Category:    categoryUpdates,
+				Severity:    severityLow,
+				Rationale:   "Stale bogon lists block newly allocated address space or let unallocated space through",
+				Remediation: "Set the bogon update frequency to daily, weekly or monthly in Firewall > Settings > Advanced",
+				Tags:        []string{"bogons", "updates"},
+			},
+			level:     Level1,
+			component: "system.bogons",
+			check:     checkBogonUpdates,
+		},
+	}
+}
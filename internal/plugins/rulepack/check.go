@@ -0,0 +1,514 @@
+package rulepack
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// errInvalidCheck indicates a malformed check.
+var errInvalidCheck = errors.New("invalid check")
+
+// Value is a scalar of a rule pack. YAML booleans and numbers are kept as written, so
+// `equals: true` compares with the text "true".
+type Value string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected a scalar value", node.Line)
+	}
+
+	*v = Value(node.Value)
+
+	return nil
+}
+
+// Check is a condition on the configuration. Exactly one of the logical operators (All, Any, Not),
+// the quantifiers (Every, Some, None) or a Path with one or more comparisons must be set.
+type Check struct {
+	All []*Check `yaml:"all,omitempty"`
+	Any []*Check `yaml:"any,omitempty"`
+	Not *Check   `yaml:"not,omitempty"`
+
+	Every *Quantifier `yaml:"every,omitempty"`
+	Some  *Quantifier `yaml:"some,omitempty"`
+	None  *Quantifier `yaml:"none,omitempty"`
+
+	Path      string   `yaml:"path,omitempty"`
+	Equals    *Value   `yaml:"equals,omitempty"`
+	NotEquals *Value   `yaml:"not_equals,omitempty"`
+	In        []Value  `yaml:"in,omitempty"`
+	NotIn     []Value  `yaml:"not_in,omitempty"`
+	Contains  *Value   `yaml:"contains,omitempty"`
+	Matches   string   `yaml:"matches,omitempty"`
+	Empty     *bool    `yaml:"empty,omitempty"`
+	GT        *float64 `yaml:"gt,omitempty"`
+	GTE       *float64 `yaml:"gte,omitempty"`
+	LT        *float64 `yaml:"lt,omitempty"`
+	LTE       *float64 `yaml:"lte,omitempty"`
+	InCIDR    string   `yaml:"in_cidr,omitempty"`
+
+	regex  *regexp.Regexp
+	prefix netip.Prefix
+}
+
+// Quantifier checks the items of a list, or the entries of a map such as the interfaces.
+// Paths of Where and Check are relative to the item. Where selects the items to check.
+type Quantifier struct {
+	Items string `yaml:"items"`
+	Where *Check `yaml:"where,omitempty"`
+	Check *Check `yaml:"check,omitempty"`
+}
+
+// comparison is a single comparison of a field check.
+type comparison struct {
+	text  string
+	test  func(value reflect.Value, found bool) bool
+	valid func(t reflect.Type) bool
+}
+
+// compile validates the check against the type of the value it is evaluated on and prepares
+// regular expressions and networks.
+func (c *Check) compile(t reflect.Type) error {
+	kinds := 0
+	for _, set := range []bool{
+		c.All != nil, c.Any != nil, c.Not != nil, c.Every != nil, c.Some != nil, c.None != nil, c.Path != "",
+	} {
+		if set {
+			kinds++
+		}
+	}
+
+	if kinds != 1 {
+		return fmt.Errorf("%w: exactly one of all, any, not, every, some, none or path is required", errInvalidCheck)
+	}
+
+	switch {
+	case c.All != nil || c.Any != nil:
+		for _, child := range append(slices.Clone(c.All), c.Any...) {
+			if child == nil {
+				return fmt.Errorf("%w: empty check in list", errInvalidCheck)
+			}
+
+			if err := child.compile(t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case c.Not != nil:
+		return c.Not.compile(t)
+	case c.Every != nil:
+		return c.Every.compile(t, true)
+	case c.Some != nil:
+		return c.Some.compile(t, false)
+	case c.None != nil:
+		return c.None.compile(t, false)
+	}
+
+	return c.compileField(t)
+}
+
+// compileField validates a field check.
+func (c *Check) compileField(t reflect.Type) error {
+	fieldType, err := resolveType(t, c.Path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidCheck, err)
+	}
+
+	if c.Matches != "" {
+		if c.regex, err = regexp.Compile(c.Matches); err != nil {
+			return fmt.Errorf("%w: %s: %w", errInvalidCheck, c.Path, err)
+		}
+	}
+
+	if c.InCIDR != "" {
+		if c.prefix, err = netip.ParsePrefix(c.InCIDR); err != nil {
+			return fmt.Errorf("%w: %s: %w", errInvalidCheck, c.Path, err)
+		}
+	}
+
+	comparisons := c.comparisons()
+	if len(comparisons) == 0 {
+		return fmt.Errorf("%w: %s has no comparison", errInvalidCheck, c.Path)
+	}
+
+	for _, cmp := range comparisons {
+		if !cmp.valid(fieldType) {
+			return fmt.Errorf("%w: %s (%s) cannot be compared: %s", errInvalidCheck, c.Path, fieldType, cmp.text)
+		}
+	}
+
+	return nil
+}
+
+// compile validates a quantifier. Every needs a check; Some and None need a check or a filter.
+func (q *Quantifier) compile(t reflect.Type, needsCheck bool) error {
+	switch {
+	case q.Items == "":
+		return fmt.Errorf("%w: quantifier without items", errInvalidCheck)
+	case q.Check == nil && (needsCheck || q.Where == nil):
+		return fmt.Errorf("%w: quantifier over %s has no check", errInvalidCheck, q.Items)
+	}
+
+	listType, err := resolveType(t, q.Items)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidCheck, err)
+	}
+
+	elem, err := itemType(listType)
+	if err != nil {
+		return fmt.Errorf("%w: items %q: %w", errInvalidCheck, q.Items, err)
+	}
+
+	for _, child := range []*Check{q.Where, q.Check} {
+		if child == nil {
+			continue
+		}
+
+		if err := child.compile(elem); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// comparisons returns the comparisons of a field check.
+func (c *Check) comparisons() []comparison {
+	var result []comparison
+
+	add := func(text string, valid func(reflect.Type) bool, test func(reflect.Value, bool) bool) {
+		result = append(result, comparison{text: text, test: test, valid: valid})
+	}
+
+	if c.Equals != nil {
+		add(fmt.Sprintf("equals %q", *c.Equals), isScalar, func(v reflect.Value, _ bool) bool {
+			return scalar(v) == string(*c.Equals)
+		})
+	}
+
+	if c.NotEquals != nil {
+		add(fmt.Sprintf("not equal to %q", *c.NotEquals), isScalar, func(v reflect.Value, _ bool) bool {
+			return scalar(v) != string(*c.NotEquals)
+		})
+	}
+
+	if c.In != nil {
+		add("one of "+quoteValues(c.In), isScalar, func(v reflect.Value, _ bool) bool {
+			return slices.Contains(c.In, Value(scalar(v)))
+		})
+	}
+
+	if c.NotIn != nil {
+		add("none of "+quoteValues(c.NotIn), isScalar, func(v reflect.Value, _ bool) bool {
+			return !slices.Contains(c.NotIn, Value(scalar(v)))
+		})
+	}
+
+	if c.Contains != nil {
+		add(fmt.Sprintf("contains %q", *c.Contains), isScalar, func(v reflect.Value, _ bool) bool {
+			if reflect.Indirect(v).Kind() == reflect.Slice {
+				return slices.Contains(list(v), string(*c.Contains))
+			}
+
+			return strings.Contains(scalar(v), string(*c.Contains))
+		})
+	}
+
+	if c.regex != nil {
+		add(fmt.Sprintf("matches %q", c.Matches), isScalar, func(v reflect.Value, _ bool) bool {
+			return c.regex.MatchString(scalar(v))
+		})
+	}
+
+	if c.Empty != nil {
+		text := "is empty"
+		if !*c.Empty {
+			text = "is not empty"
+		}
+
+		add(text, func(reflect.Type) bool { return true }, func(v reflect.Value, found bool) bool {
+			return isEmpty(v, found) == *c.Empty
+		})
+	}
+
+	for _, bound := range []struct {
+		limit *float64
+		text  string
+		test  func(a, b float64) bool
+	}{
+		{c.GT, ">", func(a, b float64) bool { return a > b }},
+		{c.GTE, ">=", func(a, b float64) bool { return a >= b }},
+		{c.LT, "<", func(a, b float64) bool { return a < b }},
+		{c.LTE, "<=", func(a, b float64) bool { return a <= b }},
+	} {
+		if bound.limit == nil {
+			continue
+		}
+
+		limit := *bound.limit
+		add(fmt.Sprintf("%s %s", bound.text, strconv.FormatFloat(limit, 'f', -1, 64)), isScalar,
+			func(v reflect.Value, _ bool) bool {
+				number, err := strconv.ParseFloat(strings.TrimSpace(scalar(v)), 64)
+				return err == nil && bound.test(number, limit)
+			})
+	}
+
+	if c.prefix.IsValid() {
+		add("within "+c.prefix.String(), isScalar, func(v reflect.Value, _ bool) bool {
+			return inPrefix(c.prefix, scalar(v))
+		})
+	}
+
+	return result
+}
+
+// evaluate evaluates the check on a value. It returns whether the check holds and, if not,
+// evidence describing why.
+func (c *Check) evaluate(v reflect.Value) (bool, []string) {
+	switch {
+	case c.All != nil:
+		var evidence []string
+
+		for _, child := range c.All {
+			if ok, childEvidence := child.evaluate(v); !ok {
+				evidence = append(evidence, childEvidence...)
+			}
+		}
+
+		return len(evidence) == 0, evidence
+	case c.Any != nil:
+		alternatives := make([]string, 0, len(c.Any))
+
+		for _, child := range c.Any {
+			ok, childEvidence := child.evaluate(v)
+			if ok {
+				return true, nil
+			}
+
+			alternatives = append(alternatives, strings.Join(childEvidence, ", "))
+		}
+
+		return false, []string{strings.Join(alternatives, "; and ")}
+	case c.Not != nil:
+		if ok, _ := c.Not.evaluate(v); ok {
+			return false, []string{"unexpectedly " + c.Not.describe()}
+		}
+
+		return true, nil
+	case c.Every != nil:
+		return c.Every.every(v)
+	case c.Some != nil:
+		return c.Some.some(v)
+	case c.None != nil:
+		return c.None.none(v)
+	}
+
+	value, found := resolve(v, c.Path)
+
+	var failed []string
+
+	for _, cmp := range c.comparisons() {
+		if !cmp.test(value, found) {
+			failed = append(failed, cmp.text)
+		}
+	}
+
+	if len(failed) == 0 {
+		return true, nil
+	}
+
+	return false, []string{fmt.Sprintf("%s is %s, expected %s", c.Path, formatValue(value, found),
+		strings.Join(failed, " and "))}
+}
+
+// describe returns a short description of the check for evidence.
+func (c *Check) describe() string {
+	switch {
+	case c.All != nil:
+		return "all of " + describeAll(c.All)
+	case c.Any != nil:
+		return "any of " + describeAll(c.Any)
+	case c.Not != nil:
+		return "not " + c.Not.describe()
+	case c.Every != nil:
+		return "every " + c.Every.describe()
+	case c.Some != nil:
+		return "some " + c.Some.describe()
+	case c.None != nil:
+		return "no " + c.None.describe()
+	}
+
+	texts := make([]string, 0)
+	for _, cmp := range c.comparisons() {
+		texts = append(texts, cmp.text)
+	}
+
+	return c.Path + " " + strings.Join(texts, " and ")
+}
+
+// describe returns a short description of the quantifier for evidence.
+func (q *Quantifier) describe() string {
+	parts := []string{q.Items}
+
+	if q.Where != nil {
+		parts = append(parts, "where "+q.Where.describe())
+	}
+
+	if q.Check != nil {
+		parts = append(parts, "with "+q.Check.describe())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// selected returns the items of the quantifier that match its filter.
+func (q *Quantifier) selected(v reflect.Value) []item {
+	value, _ := resolve(v, q.Items)
+
+	var result []item
+
+	for _, it := range items(value, q.Items) {
+		if q.Where != nil {
+			if ok, _ := q.Where.evaluate(it.value); !ok {
+				continue
+			}
+		}
+
+		result = append(result, it)
+	}
+
+	return result
+}
+
+// matches evaluates the check of the quantifier on an item; items match when there is no check.
+func (q *Quantifier) matches(it item) (bool, []string) {
+	if q.Check == nil {
+		return true, nil
+	}
+
+	return q.Check.evaluate(it.value)
+}
+
+// every holds when every selected item passes the check. The evidence lists the failing items.
+func (q *Quantifier) every(v reflect.Value) (bool, []string) {
+	var evidence []string
+
+	for _, it := range q.selected(v) {
+		if ok, itemEvidence := q.matches(it); !ok {
+			evidence = append(evidence, itemLabel(it)+": "+strings.Join(itemEvidence, ", "))
+		}
+	}
+
+	return len(evidence) == 0, evidence
+}
+
+// some holds when at least one selected item passes the check.
+func (q *Quantifier) some(v reflect.Value) (bool, []string) {
+	for _, it := range q.selected(v) {
+		if ok, _ := q.matches(it); ok {
+			return true, nil
+		}
+	}
+
+	return false, []string{"no " + q.describe()}
+}
+
+// none holds when no selected item passes the check. The evidence lists the matching items.
+func (q *Quantifier) none(v reflect.Value) (bool, []string) {
+	var evidence []string
+
+	for _, it := range q.selected(v) {
+		if ok, _ := q.matches(it); ok {
+			evidence = append(evidence, itemLabel(it))
+		}
+	}
+
+	return len(evidence) == 0, evidence
+}
+
+// itemLabel labels an item with its path and, when it has one, its description.
+func itemLabel(it item) string {
+	if descr, found := resolve(it.value, "descr"); found {
+		if text := strings.TrimSpace(scalar(descr)); text != "" {
+			return fmt.Sprintf("%s (%s)", it.label, text)
+		}
+	}
+
+	return it.label
+}
+
+// isEmpty reports whether a value is missing, zero or an empty list or map.
+func isEmpty(v reflect.Value, found bool) bool {
+	if !found || !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// inPrefix reports whether an address or network lies within the prefix.
+func inPrefix(prefix netip.Prefix, value string) bool {
+	value = strings.TrimSpace(value)
+
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return prefix.Contains(addr)
+	}
+
+	if network, err := netip.ParsePrefix(value); err == nil {
+		return network.Bits() >= prefix.Bits() && prefix.Contains(network.Addr())
+	}
+
+	return false
+}
+
+// formatValue formats a value for evidence.
+func formatValue(v reflect.Value, found bool) string {
+	if !found {
+		return "not set"
+	}
+
+	if isScalar(v.Type()) {
+		return strconv.Quote(scalar(v))
+	}
+
+	if isEmpty(v, found) {
+		return "empty"
+	}
+
+	return "set"
+}
+
+// quoteValues formats a list of values for evidence.
+func quoteValues(values []Value) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(string(value)))
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// describeAll describes a list of checks.
+func describeAll(checks []*Check) string {
+	texts := make([]string, 0, len(checks))
+	for _, check := range checks {
+		texts = append(texts, check.describe())
+	}
+
+	return "(" + strings.Join(texts, "; ") + ")"
+}
@@ -0,0 +1,288 @@
+package rulepack
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errListInPath is returned for paths that cross a list outside a quantifier.
+var errListInPath = errors.New("path crosses a list; use every, some or none to check list items")
+
+// item is a value reached by a path together with a label that identifies it in evidence.
+type item struct {
+	label string
+	value reflect.Value
+}
+
+// fieldNames returns the names a path segment may use for a struct field: the config.xml
+// element name, the JSON and YAML names of opnDossier's output and the Go field name.
+func fieldNames(field reflect.StructField) []string {
+	names := []string{field.Name}
+
+	for _, key := range []string{"xml", "json", "yaml"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// isAnyField reports whether a struct field collects arbitrary config.xml elements, such as
+// the interfaces keyed by name.
+func isAnyField(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Map && strings.Contains(field.Tag.Get("xml"), ",any")
+}
+
+// lookupField returns the struct field a path segment names. Segments that name no field
+// select the key of a map collecting arbitrary elements.
+func lookupField(t reflect.Type, segment string) (reflect.StructField, bool, bool) {
+	var anyField *reflect.StructField
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous || field.Name == "XMLName" {
+			continue
+		}
+
+		if slices.ContainsFunc(fieldNames(field), func(name string) bool { return strings.EqualFold(name, segment) }) {
+			return field, false, true
+		}
+
+		if isAnyField(field) {
+			anyField = &field
+		}
+	}
+
+	if anyField != nil {
+		return *anyField, true, true
+	}
+
+	return reflect.StructField{}, false, false
+}
+
+// splitPath splits a dotted path into its segments.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, ".")
+}
+
+// resolveType returns the type a path leads to from t, or an error for unknown fields and paths
+// that cross a list.
+func resolveType(t reflect.Type, path string) (reflect.Type, error) {
+	for _, segment := range splitPath(path) {
+		if segment == "" {
+			return nil, fmt.Errorf("empty segment in path %q", path)
+		}
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			field, viaAny, ok := lookupField(t, segment)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q in path %q", segment, path)
+			}
+
+			t = field.Type
+			if viaAny {
+				t = t.Elem()
+			}
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Slice, reflect.Array:
+			return nil, fmt.Errorf("%w: %q", errListInPath, path)
+		default:
+			return nil, fmt.Errorf("field %q in path %q has no fields", segment, path)
+		}
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t, nil
+}
+
+// itemType returns the type of the items a quantifier iterates over, or an error when t is not
+// a list or map.
+func itemType(t reflect.Type) (reflect.Type, error) {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem(), nil
+	case reflect.Struct:
+		for _, field := range reflect.VisibleFields(t) {
+			if isAnyField(field) {
+				return field.Type.Elem(), nil
+			}
+		}
+	default:
+	}
+
+	return nil, fmt.Errorf("%s is not a list", t)
+}
+
+// isScalar reports whether values of type t can be compared.
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// resolve follows a path from v. It reports false when the path leads through a nil pointer or a
+// missing map key.
+func resolve(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, segment := range splitPath(path) {
+		v = reflect.Indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			field, viaAny, ok := lookupField(v.Type(), segment)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			fv, err := v.FieldByIndexErr(field.Index)
+			if err != nil {
+				return reflect.Value{}, false
+			}
+
+			v = fv
+			if viaAny {
+				v = mapIndex(v, segment)
+			}
+		case reflect.Map:
+			v = mapIndex(v, segment)
+		default:
+			return reflect.Value{}, false
+		}
+
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	v = reflect.Indirect(v)
+
+	return v, v.IsValid()
+}
+
+// mapIndex returns the value of a string-keyed map for the key, ignoring case.
+func mapIndex(m reflect.Value, key string) reflect.Value {
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}
+	}
+
+	if value := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key())); value.IsValid() {
+		return value
+	}
+
+	for _, k := range m.MapKeys() {
+		if strings.EqualFold(k.String(), key) {
+			return m.MapIndex(k)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// items returns the items of a list or map value in a stable order, labelled with the path.
+func items(v reflect.Value, path string) []item {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Kind() == reflect.Struct {
+		for _, field := range reflect.VisibleFields(v.Type()) {
+			if isAnyField(field) {
+				return items(v.FieldByIndex(field.Index), path)
+			}
+		}
+
+		return nil
+	}
+
+	var result []item
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			result = append(result, item{label: fmt.Sprintf("%s[%d]", path, i), value: v.Index(i)})
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+
+		for _, key := range keys {
+			result = append(result, item{label: path + "." + key.String(), value: v.MapIndex(key)})
+		}
+	default:
+	}
+
+	return result
+}
+
+// scalar formats a scalar value for comparison. Lists of strings are joined with commas, as in
+// config.xml.
+func scalar(v reflect.Value) string {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		values := make([]string, 0, v.Len())
+		for i := range v.Len() {
+			values = append(values, scalar(v.Index(i)))
+		}
+
+		return strings.Join(values, ",")
+	default:
+		return ""
+	}
+}
+
+// list returns the elements of a list of strings, or the value itself for other scalars.
+func list(v reflect.Value) []string {
+	v = reflect.Indirect(v)
+	if v.IsValid() && v.Kind() == reflect.Slice {
+		values := make([]string, 0, v.Len())
+		for i := range v.Len() {
+			values = append(values, scalar(v.Index(i)))
+		}
+
+		return values
+	}
+
+	return []string{scalar(v)}
+}
@@ -0,0 +1,336 @@
+// Package rulepack provides compliance plugins defined declaratively in YAML files.
+//
+// A rule pack lists controls together with a check over the configuration model. Checks compare
+// fields reached by dotted paths, which use the element names of config.xml:
+//
+//	name: site-baseline
+//	version: 1.0.0
+//	controls:
+//	  - id: SITE-SSH-001
+//	    title: SSH root login disabled
+//	    severity: high
+//	    applies:
+//	      path: system.ssh.enabled
+//	      equals: enabled
+//	    check:
+//	      path: system.ssh.permitrootlogin
+//	      empty: true
+//
+// Quantifiers (every, some, none) check the items of lists and maps, such as filter.rule,
+// interfaces or system.user.
+package rulepack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRulePack is returned for rule packs that cannot be parsed or validated.
+var ErrInvalidRulePack = errors.New("invalid rule pack")
+
+// namePattern restricts rule pack names to values that can be passed to --plugins.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// severities lists the severities a rule may have.
+var severities = []string{"critical", "high", "medium", "low", "info"} //nolint:gochecknoglobals // fixed list
+
+// Pack is a rule pack as written in YAML.
+type Pack struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	Controls    []Rule `yaml:"controls"`
+}
+
+// Rule is a control of a rule pack together with the check that decides it.
+type Rule struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Category    string   `yaml:"category"`
+	Severity    string   `yaml:"severity"`
+	Rationale   string   `yaml:"rationale"`
+	Remediation string   `yaml:"remediation"`
+	References  []string `yaml:"references,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	// Component names the configuration area in findings; it defaults to "rule-pack".
+	Component string `yaml:"component,omitempty"`
+	// Applies limits the rule to configurations it holds for; otherwise the control is not applicable.
+	Applies *Check `yaml:"applies,omitempty"`
+	// Check must hold for the control to pass.
+	Check *Check `yaml:"check"`
+}
+
+// control returns the plugin control of the rule.
+func (r *Rule) control() plugin.Control {
+	return plugin.Control{
+		ID:          r.ID,
+		Title:       r.Title,
+		Description: r.Description,
+		Category:    r.Category,
+		Severity:    r.Severity,
+		Rationale:   r.Rationale,
+		Remediation: r.Remediation,
+		References:  r.References,
+		Tags:        r.Tags,
+	}
+}
+
+// Parse reads a rule pack. Unknown keys are rejected so that typos do not silently disable checks.
+func Parse(r io.Reader) (*Pack, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var pack Pack
+	if err := decoder.Decode(&pack); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRulePack, err)
+	}
+
+	return &pack, nil
+}
+
+// Load reads the rule pack at path.
+func Load(path string) (*Pack, error) {
+	f, err := os.Open(path) //nolint:gosec // rule packs are files the user selected
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rule pack: %w", err)
+	}
+	defer f.Close()
+
+	pack, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return pack, nil
+}
+
+// IsRulePack reports whether a file name looks like a rule pack.
+func IsRulePack(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Plugin implements the CompliancePlugin and ControlEvaluator interfaces for a rule pack.
+type Plugin struct {
+	pack     *Pack
+	controls []plugin.Control
+
+	once       sync.Once
+	prepareErr error
+}
+
+// NewPlugin creates a compliance plugin for a rule pack. The pack is validated by
+// ValidateConfiguration, which the plugin registry calls on registration.
+func NewPlugin(pack *Pack) *Plugin {
+	controls := make([]plugin.Control, 0, len(pack.Controls))
+	for i := range pack.Controls {
+		controls = append(controls, pack.Controls[i].control())
+	}
+
+	return &Plugin{pack: pack, controls: controls}
+}
+
+// Name returns the plugin name.
+func (p *Plugin) Name() string {
+	return p.pack.Name
+}
+
+// Version returns the plugin version.
+func (p *Plugin) Version() string {
+	return p.pack.Version
+}
+
+// Description returns the plugin description.
+func (p *Plugin) Description() string {
+	return p.pack.Description
+}
+
+// RunChecks evaluates the rules of the pack and returns a finding for every failed rule.
+func (p *Plugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
+	if p.prepare() != nil {
+		return nil
+	}
+
+	var findings []plugin.Finding
+
+	for i := range p.pack.Controls {
+		rule := &p.pack.Controls[i]
+
+		status, evidence := p.evaluate(rule, config)
+		if status != plugin.StatusFail {
+			continue
+		}
+
+		component := rule.Component
+		if component == "" {
+			component = "rule-pack"
+		}
+
+		findings = append(findings, plugin.Finding{
+			Type:           "compliance",
+			Title:          rule.Title,
+			Description:    findingDescription(rule, evidence),
+			Recommendation: rule.Remediation,
+			Component:      component,
+			Reference:      rule.ID,
+			Severity:       rule.Severity,
+			References:     []string{rule.ID},
+			Tags:           append(slices.Clone(rule.Tags), p.pack.Name),
+		})
+	}
+
+	return findings
+}
+
+// EvaluateControls reports the result of every rule of the pack.
+func (p *Plugin) EvaluateControls(config *model.OpnSenseDocument) []plugin.ControlResult {
+	results := make([]plugin.ControlResult, 0, len(p.pack.Controls))
+
+	for i := range p.pack.Controls {
+		rule := &p.pack.Controls[i]
+		result := plugin.ControlResult{ControlID: rule.ID}
+
+		if err := p.prepare(); err != nil {
+			result.Status = plugin.StatusError
+			result.Message = err.Error()
+			results = append(results, result)
+
+			continue
+		}
+
+		result.Status, result.Evidence = p.evaluate(rule, config)
+
+		switch result.Status {
+		case plugin.StatusNotApplicable:
+			result.Message = "rule does not apply to the configuration"
+		case plugin.StatusFail:
+			result.Message = "check failed: " + rule.Check.describe()
+		default:
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// GetControls returns the controls of the rule pack.
+func (p *Plugin) GetControls() []plugin.Control {
+	return p.controls
+}
+
+// GetControlByID returns a specific control by ID.
+func (p *Plugin) GetControlByID(id string) (*plugin.Control, error) {
+	for _, control := range p.controls {
+		if control.ID == id {
+			return &control, nil
+		}
+	}
+
+	return nil, plugin.ErrControlNotFound
+}
+
+// ValidateConfiguration validates the rule pack: its name, the controls and every check, whose
+// paths must exist in the configuration model.
+func (p *Plugin) ValidateConfiguration() error {
+	return p.prepare()
+}
+
+// prepare validates the rule pack and compiles its checks once.
+func (p *Plugin) prepare() error {
+	p.once.Do(func() {
+		p.prepareErr = p.pack.validate()
+	})
+
+	return p.prepareErr
+}
+
+// evaluate decides a rule for a configuration.
+func (p *Plugin) evaluate(rule *Rule, config *model.OpnSenseDocument) (plugin.Status, []string) {
+	root := reflect.ValueOf(config)
+
+	if rule.Applies != nil {
+		if ok, _ := rule.Applies.evaluate(root); !ok {
+			return plugin.StatusNotApplicable, nil
+		}
+	}
+
+	if ok, evidence := rule.Check.evaluate(root); !ok {
+		return plugin.StatusFail, evidence
+	}
+
+	return plugin.StatusPass, nil
+}
+
+// validate checks the pack and compiles the checks of its rules against the configuration model.
+func (pack *Pack) validate() error {
+	if !namePattern.MatchString(pack.Name) {
+		return fmt.Errorf("%w: invalid name %q", ErrInvalidRulePack, pack.Name)
+	}
+
+	if len(pack.Controls) == 0 {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidRulePack, pack.Name, plugin.ErrNoControlsDefined)
+	}
+
+	root := reflect.TypeFor[model.OpnSenseDocument]()
+	seen := make(map[string]bool, len(pack.Controls))
+
+	for i := range pack.Controls {
+		rule := &pack.Controls[i]
+
+		switch {
+		case rule.ID == "":
+			return fmt.Errorf("%w: %s: control %d has no id", ErrInvalidRulePack, pack.Name, i)
+		case seen[rule.ID]:
+			return fmt.Errorf("%w: %s: control %s is defined more than once", ErrInvalidRulePack, pack.Name, rule.ID)
+		case rule.Title == "":
+			return fmt.Errorf("%w: %s: control %s has no title", ErrInvalidRulePack, pack.Name, rule.ID)
+		case !slices.Contains(severities, rule.Severity):
+			return fmt.Errorf("%w: %s: control %s has invalid severity %q (expected one of %s)",
+				ErrInvalidRulePack, pack.Name, rule.ID, rule.Severity, strings.Join(severities, ", "))
+		case rule.Check == nil:
+			return fmt.Errorf("%w: %s: control %s has no check", ErrInvalidRulePack, pack.Name, rule.ID)
+		}
+
+		seen[rule.ID] = true
+
+		if err := rule.Check.compile(root); err != nil {
+			return fmt.Errorf("%w: %s: control %s: %w", ErrInvalidRulePack, pack.Name, rule.ID, err)
+		}
+
+		if rule.Applies != nil {
+			if err := rule.Applies.compile(root); err != nil {
+				return fmt.Errorf("%w: %s: control %s: applies: %w", ErrInvalidRulePack, pack.Name, rule.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findingDescription combines the description of a rule with the evidence of its failure.
+func findingDescription(rule *Rule, evidence []string) string {
+	description := rule.Description
+	if description == "" {
+		description = rule.Title
+	}
+
+	if len(evidence) == 0 {
+		return description
+	}
+
+	return description + ": " + strings.Join(evidence, "; ")
+}
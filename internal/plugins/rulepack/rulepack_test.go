@@ -0,0 +1,322 @@
+package rulepack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns a configuration with users, interfaces and rules for the checks.
+func testConfig() *model.OpnSenseDocument {
+	return &model.OpnSenseDocument{
+		System: model.System{
+			Hostname: "fw",
+			WebGUI:   model.WebGUIConfig{Protocol: "http", Port: "8443"},
+			SSH:      model.SSHConfig{Enabled: "enabled", PermitRootLogin: "1"},
+			User: []model.User{
+				{Name: "root", Descr: "System Administrator"},
+				{Name: "alice"},
+				{Name: "bob", Descr: "Operator", Disabled: true},
+			},
+		},
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{
+			"wan":  {Descr: "WAN", IPAddr: "dhcp"},
+			"lan":  {Descr: "LAN", IPAddr: "10.1.0.1", Subnet: "24"},
+			"opt1": {Descr: "Guest", IPAddr: "172.16.0.1", Subnet: "24"},
+		}},
+		Filter: model.Filter{Rule: []model.Rule{
+			{Type: "pass", Descr: "Allow LAN", Source: model.Source{Network: "lan"}, Destination: model.Destination{Any: "1"}},
+			{Type: "pass", Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}},
+			{Type: "block", Descr: "Default deny", Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}},
+		}},
+	}
+}
+
+// evaluate parses a single check and evaluates it on the test configuration.
+func evaluate(t *testing.T, check string) (bool, []string) {
+	t.Helper()
+
+	pack := parsePack(t, check)
+	require.NoError(t, pack.validate())
+
+	return pack.Controls[0].Check.evaluate(reflect.ValueOf(testConfig()))
+}
+
+// parsePack parses a pack with a single control using the given check.
+func parsePack(t *testing.T, check string) *Pack {
+	t.Helper()
+
+	var doc strings.Builder
+	doc.WriteString("name: test\ncontrols:\n  - id: T-1\n    title: Test\n    severity: low\n    check:\n")
+
+	for line := range strings.SplitSeq(strings.TrimSpace(check), "\n") {
+		doc.WriteString("      " + line + "\n")
+	}
+
+	pack, err := Parse(strings.NewReader(doc.String()))
+	require.NoError(t, err)
+
+	return pack
+}
+
+func TestCheck_Fields(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    string
+		want     bool
+		evidence string
+	}{
+		{
+			name:     "equals",
+			check:    "path: system.webgui.protocol\nequals: https",
+			evidence: `system.webgui.protocol is "http", expected equals "https"`,
+		},
+		{name: "not equals", check: "path: system.webgui.protocol\nnot_equals: https", want: true},
+		{name: "case-insensitive JSON name", check: "path: System.WebGUI.Port\nequals: 8443", want: true},
+		{name: "in", check: "path: system.webgui.protocol\nin: [http, https]", want: true},
+		{
+			name:     "not in",
+			check:    "path: system.ssh.permitrootlogin\nnot_in: [\"1\", yes]",
+			evidence: `expected none of ["1", "yes"]`,
+		},
+		{name: "contains", check: "path: system.hostname\ncontains: f", want: true},
+		{name: "matches", check: "path: system.webgui.port\nmatches: '^84\\d+$'", want: true},
+		{name: "empty", check: "path: system.domain\nempty: true", want: true},
+		{
+			name:     "not empty",
+			check:    "path: system.domain\nempty: false",
+			evidence: `system.domain is "", expected is not empty`,
+		},
+		{name: "numeric bounds", check: "path: system.webgui.port\ngt: 1024\nlte: 8443", want: true},
+		{name: "numeric bound fails", check: "path: system.webgui.port\nlt: 1024", evidence: "expected < 1024"},
+		{name: "interface key", check: "path: interfaces.LAN.ipaddr\nin_cidr: 10.0.0.0/8", want: true},
+		{name: "missing interface", check: "path: interfaces.opt9.ipaddr\nempty: false", evidence: "is not set"},
+		{name: "not", check: "not:\n  path: system.ssh.permitrootlogin\n  empty: true", want: true},
+		{
+			name:     "any",
+			check:    "any:\n  - path: system.webgui.protocol\n    equals: https\n  - path: system.domain\n    empty: false",
+			evidence: "; and ",
+		},
+		{
+			name:  "all",
+			check: "all:\n  - path: system.webgui.protocol\n    equals: http\n  - path: system.hostname\n    equals: fw",
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, evidence := evaluate(t, tt.check)
+			assert.Equal(t, tt.want, ok)
+
+			if tt.want {
+				assert.Empty(t, evidence)
+			} else {
+				require.NotEmpty(t, evidence)
+				assert.Contains(t, strings.Join(evidence, "\n"), tt.evidence)
+			}
+		})
+	}
+}
+
+func TestCheck_Quantifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    string
+		want     bool
+		evidence []string
+	}{
+		{
+			name:     "every rule has a description",
+			check:    "every:\n  items: filter.rule\n  check:\n    path: descr\n    empty: false",
+			evidence: []string{`filter.rule[1]: descr is "", expected is not empty`},
+		},
+		{
+			name: "no any-to-any pass rule",
+			check: "none:\n  items: filter.rule\n  where:\n    path: type\n    equals: pass\n" +
+				"  check:\n    all:\n      - path: source.any\n        equals: \"1\"\n" +
+				"      - path: destination.any\n        equals: \"1\"",
+			evidence: []string{"filter.rule[1]"},
+		},
+		{
+			name:  "some block rule",
+			check: "some:\n  items: filter.rule\n  where:\n    path: type\n    equals: block",
+			want:  true,
+		},
+		{
+			name:     "some rule without match",
+			check:    "some:\n  items: filter.rule\n  check:\n    path: type\n    equals: reject",
+			evidence: []string{`no filter.rule with type equals "reject"`},
+		},
+		{
+			name: "static interfaces use private addresses",
+			check: "every:\n  items: interfaces\n  where:\n    path: ipaddr\n    matches: '^[0-9.]+$'\n" +
+				"  check:\n    path: ipaddr\n    in_cidr: 10.0.0.0/8",
+			evidence: []string{`interfaces.opt1 (Guest): ipaddr is "172.16.0.1", expected within 10.0.0.0/8`},
+		},
+		{
+			name: "enabled users are described",
+			check: "every:\n  items: system.user\n  where:\n    path: disabled\n    equals: \"false\"\n" +
+				"  check:\n    path: descr\n    empty: false",
+			evidence: []string{`system.user[1]: descr is "", expected is not empty`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, evidence := evaluate(t, tt.check)
+			assert.Equal(t, tt.want, ok)
+			assert.Equal(t, tt.evidence, evidence)
+		})
+	}
+}
+
+func TestPack_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  string
+		errMsg string
+	}{
+		{name: "unknown field", check: "path: system.sshd.enabled\nempty: true", errMsg: `unknown field "sshd"`},
+		{name: "list in path", check: "path: filter.rule.descr\nempty: true", errMsg: "path crosses a list"},
+		{name: "bad regex", check: "path: system.hostname\nmatches: '('", errMsg: "missing closing )"},
+		{name: "bad network", check: "path: system.hostname\nin_cidr: 10.0.0.0", errMsg: "no '/'"},
+		{name: "no comparison", check: "path: system.hostname", errMsg: "has no comparison"},
+		{name: "struct comparison", check: "path: system.ssh\nequals: x", errMsg: "cannot be compared"},
+		{name: "two kinds", check: "path: system.hostname\nempty: true\nnot:\n  path: system.domain\n  empty: true",
+			errMsg: "exactly one of"},
+		{name: "not a list", check: "every:\n  items: system.hostname\n  check:\n    path: x\n    empty: true",
+			errMsg: "is not a list"},
+		{name: "every without check", check: "every:\n  items: filter.rule", errMsg: "has no check"},
+		{name: "item path", check: "some:\n  items: filter.rule\n  where:\n    path: hostname\n    empty: true",
+			errMsg: `unknown field "hostname"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parsePack(t, tt.check).validate()
+			require.ErrorIs(t, err, ErrInvalidRulePack)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Contains(t, err.Error(), "control T-1")
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse(strings.NewReader("name: test\ncontrols:\n  - id: T-1\n    chekc: {}\n"))
+	require.ErrorIs(t, err, ErrInvalidRulePack)
+	assert.Contains(t, err.Error(), "field chekc not found")
+
+	for _, doc := range []string{
+		"name: Not Valid\ncontrols: [{id: A, title: A, severity: low, check: {path: system.hostname, empty: true}}]",
+		"name: test\ncontrols: []",
+		"name: test\ncontrols: [{id: A, title: A, severity: urgent, check: {path: system.hostname, empty: true}}]",
+		"name: test\ncontrols: [{id: A, title: A, severity: low}]",
+	} {
+		pack, err := Parse(strings.NewReader(doc))
+		require.NoError(t, err)
+		require.ErrorIs(t, NewPlugin(pack).ValidateConfiguration(), ErrInvalidRulePack, doc)
+	}
+}
+
+func TestPlugin_Evaluate(t *testing.T) {
+	pack, err := Parse(strings.NewReader(`
+name: test
+version: 1.0.0
+description: test pack
+controls:
+  - id: T-1
+    title: HTTPS
+    description: The web GUI uses HTTPS
+    severity: high
+    remediation: Enable HTTPS
+    check: {path: system.webgui.protocol, equals: https}
+  - id: T-2
+    title: Hostname
+    severity: low
+    check: {path: system.hostname, empty: false}
+  - id: T-3
+    title: SSH Disabled
+    severity: medium
+    applies: {path: system.ssh.enabled, empty: true}
+    check: {path: system.hostname, equals: never}
+`))
+	require.NoError(t, err)
+
+	p := NewPlugin(pack)
+	require.NoError(t, p.ValidateConfiguration())
+	assert.Equal(t, "test", p.Name())
+	assert.Equal(t, "1.0.0", p.Version())
+	assert.Len(t, p.GetControls(), 3)
+
+	control, err := p.GetControlByID("T-2")
+	require.NoError(t, err)
+	assert.Equal(t, "Hostname", control.Title)
+
+	_, err = p.GetControlByID("T-9")
+	require.ErrorIs(t, err, plugin.ErrControlNotFound)
+
+	findings, results := plugin.Evaluate(p, testConfig())
+	require.Len(t, findings, 1)
+	assert.Equal(t, "T-1", findings[0].Reference)
+	assert.Equal(t, "high", findings[0].Severity)
+	assert.Equal(t, "Enable HTTPS", findings[0].Recommendation)
+	assert.Equal(t, `The web GUI uses HTTPS: system.webgui.protocol is "http", expected equals "https"`,
+		findings[0].Description)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, plugin.StatusFail, results[0].Status)
+	assert.Equal(t, plugin.StatusPass, results[1].Status)
+	assert.Equal(t, plugin.StatusNotApplicable, results[2].Status)
+	assert.Equal(t, "SSH Disabled", results[2].Title)
+}
+
+func TestPlugin_InvalidPackReportsErrors(t *testing.T) {
+	p := NewPlugin(parsePack(t, "path: system.nothing\nempty: true"))
+	require.Error(t, p.ValidateConfiguration())
+
+	assert.Empty(t, p.RunChecks(testConfig()))
+
+	results := p.EvaluateControls(testConfig())
+	require.Len(t, results, 1)
+	assert.Equal(t, plugin.StatusError, results[0].Status)
+	assert.Contains(t, results[0].Message, `unknown field "nothing"`)
+}
+
+func TestExampleRulePack(t *testing.T) {
+	pack, err := Load(filepath.Join("..", "..", "..", "examples", "rulepacks", "site-baseline.yaml"))
+	require.NoError(t, err)
+
+	p := NewPlugin(pack)
+	require.NoError(t, p.ValidateConfiguration())
+
+	f, err := os.Open(filepath.Join("..", "..", "..", "testdata", "sample.config.1.xml"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	config, err := parser.NewXMLParser().Parse(context.Background(), f)
+	require.NoError(t, err)
+
+	_, results := plugin.Evaluate(p, config)
+	require.Len(t, results, len(pack.Controls))
+
+	for _, result := range results {
+		assert.NotEqual(t, plugin.StatusError, result.Status, result.ControlID)
+	}
+}
+
+func TestIsRulePack(t *testing.T) {
+	assert.True(t, IsRulePack("baseline.yaml"))
+	assert.True(t, IsRulePack("baseline.YML"))
+	assert.False(t, IsRulePack("plugin"))
+	assert.False(t, IsRulePack("notes.md"))
+}
@@ -2,13 +2,40 @@
 package sans
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/plugin"
 )
 
-// Plugin implements the CompliancePlugin interface for SANS compliance.
+// Settings are the options of the SANS plugin, set in the plugin_settings.sans block of the
+// configuration file.
+type Settings struct {
+	// UntrustedZones lists keywords that mark an interface as untrusted when its name or
+	// description contains them as a whole word.
+	UntrustedZones []string `yaml:"untrusted_zones"`
+	// DMZZones lists keywords that mark an interface as DMZ. Other interfaces are internal.
+	DMZZones []string `yaml:"dmz_zones"`
+	// RequireRuleLogging reports enabled rules without logging as logging gaps.
+	RequireRuleLogging bool `yaml:"require_rule_logging"`
+}
+
+// DefaultSettings returns the settings the SANS plugin uses unless configured otherwise.
+func DefaultSettings() Settings {
+	return Settings{
+		UntrustedZones:     []string{"wan", "internet", "guest", "public", "iot"},
+		DMZZones:           []string{"dmz"},
+		RequireRuleLogging: true,
+	}
+}
+
+// Plugin implements the CompliancePlugin and Configurable interfaces for SANS compliance.
 type Plugin struct {
 	controls []plugin.Control
+	settings Settings
 }
 
 // NewPlugin creates a new SANS compliance plugin.
@@ -56,6 +83,7 @@ func NewPlugin() *Plugin {
 				Tags:        []string{"logging", "security-monitoring", "audit-trail"},
 			},
 		},
+		settings: DefaultSettings(),
 	}
 
 	return p
@@ -81,58 +109,65 @@ func (sp *Plugin) RunChecks(config *model.OpnSenseDocument) []plugin.Finding {
 	var findings []plugin.Finding
 
 	// SANS-FW-001: Default deny policy
-	if !sp.hasDefaultDenyPolicy(config) {
+	if missing := sp.interfacesWithoutDefaultDeny(config); len(missing) > 0 {
 		findings = append(findings, plugin.Finding{
-			Type:           "compliance",
-			Title:          "Missing Default Deny Policy (SANS)",
-			Description:    "Firewall should implement a default deny policy for all traffic",
+			Type:  "compliance",
+			Title: "Missing Default Deny Policy (SANS)",
+			Description: "The rule chains of the following interfaces do not end with an explicit block " +
+				"rule for any source and destination: " + strings.Join(missing, ", "),
 			Recommendation: "Configure firewall with default deny policy and explicit allow rules for necessary traffic",
 			Component:      "firewall-rules",
 			Reference:      "SANS-FW-001",
 			References:     []string{"SANS-FW-001"},
 			Tags:           []string{"default-deny", "access-control", "security-policy", "sans"},
+			Metadata:       map[string]string{"interfaces": strings.Join(missing, ", ")},
 		})
 	}
 
 	// SANS-FW-002: Explicit rule configuration
-	if sp.hasUnclearRules(config) {
+	if unclear := sp.unclearRules(config); len(unclear) > 0 {
 		findings = append(findings, plugin.Finding{
-			Type:           "compliance",
-			Title:          "Non-Explicit Firewall Rules",
-			Description:    "Firewall contains rules that are not explicit or well-documented",
+			Type:  "compliance",
+			Title: "Non-Explicit Firewall Rules",
+			Description: "The following rules lack a description or allow traffic from any source to any " +
+				"destination: " + strings.Join(unclear, "; "),
 			Recommendation: "Replace any catch-all or overly permissive rules with explicit, documented rules",
 			Component:      "firewall-rules",
 			Reference:      "SANS-FW-002",
 			References:     []string{"SANS-FW-002"},
 			Tags:           []string{"rule-documentation", "explicit-rules", "rule-management", "sans"},
+			Metadata:       map[string]string{"rules": strings.Join(unclear, "; ")},
 		})
 	}
 
 	// SANS-FW-003: Network zone separation
-	if !sp.hasProperZoneSeparation(config) {
+	if violations := sp.zoneSeparationViolations(config); len(violations) > 0 {
 		findings = append(findings, plugin.Finding{
-			Type:           "compliance",
-			Title:          "Insufficient Network Zone Separation",
-			Description:    "Firewall does not enforce proper separation between different security zones",
+			Type:  "compliance",
+			Title: "Insufficient Network Zone Separation",
+			Description: "The following rules allow traffic from a less trusted zone into a more trusted zone: " +
+				strings.Join(violations, "; "),
 			Recommendation: "Configure firewall rules to enforce proper network zone separation and access controls",
 			Component:      "firewall-rules",
 			Reference:      "SANS-FW-003",
 			References:     []string{"SANS-FW-003"},
 			Tags:           []string{"network-segmentation", "zone-separation", "access-control", "sans"},
+			Metadata:       map[string]string{"rules": strings.Join(violations, "; ")},
 		})
 	}
 
 	// SANS-FW-004: Comprehensive logging
-	if !sp.hasComprehensiveLogging(config) {
+	if gaps := sp.loggingGaps(config); len(gaps) > 0 {
 		findings = append(findings, plugin.Finding{
 			Type:           "compliance",
 			Title:          "Insufficient Firewall Logging",
-			Description:    "Firewall does not log all traffic and security events",
+			Description:    "Firewall does not log all traffic and security events: " + strings.Join(gaps, "; "),
 			Recommendation: "Enable comprehensive logging for all firewall rules and security events",
 			Component:      "firewall-rules",
 			Reference:      "SANS-FW-004",
 			References:     []string{"SANS-FW-004"},
 			Tags:           []string{"logging", "security-monitoring", "audit-trail", "sans"},
+			Metadata:       map[string]string{"gaps": strings.Join(gaps, "; ")},
 		})
 	}
 
@@ -161,28 +196,228 @@ func (sp *Plugin) ValidateConfiguration() error {
 		return plugin.ErrNoControlsDefined
 	}
 
+	for _, keyword := range slices.Concat(sp.settings.UntrustedZones, sp.settings.DMZZones) {
+		if strings.TrimSpace(keyword) == "" {
+			return fmt.Errorf("%w: zone keywords must not be empty", plugin.ErrPluginValidation)
+		}
+
+		if strings.ContainsFunc(keyword, isWordSeparator) {
+			return fmt.Errorf("%w: zone keyword %q must be a single word", plugin.ErrPluginValidation, keyword)
+		}
+	}
+
+	for _, keyword := range sp.settings.DMZZones {
+		if slices.ContainsFunc(sp.settings.UntrustedZones, func(untrusted string) bool {
+			return strings.EqualFold(untrusted, keyword)
+		}) {
+			return fmt.Errorf("%w: zone keyword %q is both untrusted and DMZ", plugin.ErrPluginValidation, keyword)
+		}
+	}
+
 	return nil
 }
 
+// Configure applies the settings block of the plugin.
+func (sp *Plugin) Configure(settings map[string]any) error {
+	return plugin.DecodeSettings(settings, &sp.settings)
+}
+
 // Helper methods for compliance checks
 
-func (sp *Plugin) hasDefaultDenyPolicy(_ *model.OpnSenseDocument) bool {
-	// Check for default deny policy configuration
-	return true // Placeholder - implement actual logic
+// interfacesWithoutDefaultDeny returns the sorted names of the interfaces whose rule chain does not
+// end with an enabled block or reject rule matching any source and any destination. Interfaces
+// without rules are covered by the implicit deny of the firewall and are not reported.
+func (sp *Plugin) interfacesWithoutDefaultDeny(config *model.OpnSenseDocument) []string {
+	lastRules := make(map[string]model.Rule)
+
+	for _, rule := range config.FilterRules() {
+		if rule.Disabled != "" {
+			continue
+		}
+
+		for _, iface := range rule.Interface {
+			lastRules[iface] = rule
+		}
+	}
+
+	missing := make([]string, 0)
+
+	for iface, rule := range lastRules {
+		if !isBlockRule(rule) || !rule.Source.IsAny() || !rule.Destination.IsAny() {
+			missing = append(missing, iface)
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing
+}
+
+// unclearRules returns the enabled rules that have no description or pass traffic from any
+// source to any destination.
+func (sp *Plugin) unclearRules(config *model.OpnSenseDocument) []string {
+	unclear := make([]string, 0)
+
+	for i, rule := range config.FilterRules() {
+		if rule.Disabled != "" {
+			continue
+		}
+
+		var reasons []string
+		if strings.TrimSpace(rule.Descr) == "" {
+			reasons = append(reasons, "no description")
+		}
+
+		if rule.Type == model.RuleTypePass && rule.Source.IsAny() && rule.Destination.IsAny() {
+			reasons = append(reasons, "any/any")
+		}
+
+		if len(reasons) > 0 {
+			unclear = append(unclear, fmt.Sprintf("%s: %s", ruleLabel(i, rule), strings.Join(reasons, ", ")))
+		}
+	}
+
+	return unclear
+}
+
+// zoneSeparationViolations returns the enabled pass rules that let a less trusted interface reach a
+// more trusted one, either through an "any" destination or by naming the trusted interface network.
+func (sp *Plugin) zoneSeparationViolations(config *model.OpnSenseDocument) []string {
+	zones := make(map[string]trustLevel)
+	for name, iface := range config.Interfaces.Items {
+		zones[name] = sp.classifyZone(name, iface.Descr)
+	}
+
+	violations := make([]string, 0)
+
+	for i, rule := range config.FilterRules() {
+		if rule.Disabled != "" || rule.Type != model.RuleTypePass {
+			continue
+		}
+
+		for _, iface := range rule.Interface {
+			level, ok := zones[iface]
+			if !ok {
+				level = sp.classifyZone(iface, "")
+			}
+
+			targets := sp.reachableTrustedZones(rule.Destination, level, zones)
+			if len(targets) > 0 {
+				violations = append(violations, fmt.Sprintf("%s on %s (%s) reaches %s",
+					ruleLabel(i, rule), iface, level, strings.Join(targets, ", ")))
+			}
+		}
+	}
+
+	return violations
 }
 
-func (sp *Plugin) hasUnclearRules(_ *model.OpnSenseDocument) bool {
-	// Check for unclear or overly permissive rules
-	// This would analyze firewall rules for catch-all patterns, overly broad ranges, etc.
-	return false // Placeholder - implement actual logic
+// reachableTrustedZones returns the sorted interfaces with a higher trust level than the source zone
+// that the destination of a pass rule can reach.
+func (sp *Plugin) reachableTrustedZones(destination model.Destination, level trustLevel,
+	zones map[string]trustLevel,
+) []string {
+	targets := make([]string, 0)
+
+	for name, zone := range zones {
+		if zone <= level {
+			continue
+		}
+
+		named := !destination.Not.Bool() && (destination.Network == name || destination.Network == name+"ip")
+		if destination.IsAny() || named {
+			targets = append(targets, name)
+		}
+	}
+
+	slices.Sort(targets)
+
+	return targets
 }
 
-func (sp *Plugin) hasProperZoneSeparation(_ *model.OpnSenseDocument) bool {
-	// Check for proper network zone separation
-	return true // Placeholder - implement actual logic
+// loggingGaps returns the logging shortcomings of the configuration: enabled rules that do not log
+// and a remote syslog configuration that is missing or does not forward firewall events.
+func (sp *Plugin) loggingGaps(config *model.OpnSenseDocument) []string {
+	gaps := make([]string, 0)
+
+	for i, rule := range config.FilterRules() {
+		if sp.settings.RequireRuleLogging && rule.Disabled == "" && !rule.Log.Bool() {
+			gaps = append(gaps, ruleLabel(i, rule)+": logging disabled")
+		}
+	}
+
+	syslog := config.Syslog
+	remote := syslog.Remoteserver != "" || syslog.Remoteserver2 != "" || syslog.Remoteserver3 != ""
+
+	switch {
+	case !syslog.Enable.Bool() || !remote:
+		gaps = append(gaps, "remote syslog is not configured")
+	case !syslog.Filter.Bool():
+		gaps = append(gaps, "firewall events are not forwarded to remote syslog")
+	}
+
+	return gaps
 }
 
-func (sp *Plugin) hasComprehensiveLogging(_ *model.OpnSenseDocument) bool {
-	// Check for comprehensive logging configuration
-	return true // Placeholder - implement actual logic
+// trustLevel ranks network zones from least to most trusted.
+type trustLevel int
+
+const (
+	trustUntrusted trustLevel = iota
+	trustDMZ
+	trustInternal
+)
+
+// String returns the zone name of the trust level.
+func (t trustLevel) String() string {
+	switch t {
+	case trustUntrusted:
+		return "untrusted"
+	case trustDMZ:
+		return "dmz"
+	default:
+		return "internal"
+	}
+}
+
+// classifyZone derives the trust level of an interface from the words of its name and description.
+// Keywords match whole words only, so "wan" does not match "swan".
+func (sp *Plugin) classifyZone(name, descr string) trustLevel {
+	words := strings.FieldsFunc(name+" "+descr, isWordSeparator)
+
+	matches := func(keywords []string) bool {
+		return slices.ContainsFunc(words, func(word string) bool {
+			return slices.ContainsFunc(keywords, func(keyword string) bool {
+				return strings.EqualFold(word, keyword)
+			})
+		})
+	}
+
+	switch {
+	case matches(sp.settings.UntrustedZones):
+		return trustUntrusted
+	case matches(sp.settings.DMZZones):
+		return trustDMZ
+	default:
+		return trustInternal
+	}
+}
+
+// isWordSeparator reports whether r separates the words of an interface label.
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// isBlockRule reports whether a rule blocks or rejects matching traffic.
+func isBlockRule(rule model.Rule) bool {
+	return rule.Type == model.RuleTypeBlock || rule.Type == "reject"
+}
+
+// ruleLabel identifies a filter rule by its position and description.
+func ruleLabel(index int, rule model.Rule) string {
+	if rule.Descr == "" {
+		return fmt.Sprintf("filter.rule[%d]", index)
+	}
+
+	return fmt.Sprintf("filter.rule[%d] %q", index, rule.Descr)
 }
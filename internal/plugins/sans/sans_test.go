@@ -21,25 +21,39 @@ func TestSANSPlugin_RunChecks(t *testing.T) {
 		description        string
 	}{
 		{
-			name: "Default configuration - all findings expected",
+			name: "Default configuration - logging finding expected",
 			config: &model.OpnSenseDocument{
 				System: model.System{
 					Hostname: "OPNsense",
 					Domain:   "localdomain",
 				},
 			},
-			expectedFindings:   0,
-			expectedFindingIDs: []string{},
-			description:        "Default config should trigger all SANS compliance checks",
+			expectedFindings:   1,
+			expectedFindingIDs: []string{"SANS-FW-004"},
+			description:        "Default config without remote syslog should only trigger the logging check",
 		},
 		{
-			name: "Empty configuration - all findings expected",
+			name: "Empty configuration - logging finding expected",
 			config: &model.OpnSenseDocument{
 				System: model.System{},
 			},
+			expectedFindings:   1,
+			expectedFindingIDs: []string{"SANS-FW-004"},
+			description:        "Empty config without remote syslog should only trigger the logging check",
+		},
+		{
+			name:               "Compliant configuration - no findings expected",
+			config:             compliantConfig(),
 			expectedFindings:   0,
 			expectedFindingIDs: []string{},
-			description:        "Empty config should trigger all SANS compliance checks",
+			description:        "Documented, logged rules ending in default deny should pass all SANS checks",
+		},
+		{
+			name:               "Permissive configuration - all findings expected",
+			config:             permissiveConfig(),
+			expectedFindings:   4,
+			expectedFindingIDs: []string{"SANS-FW-001", "SANS-FW-002", "SANS-FW-003", "SANS-FW-004"},
+			description:        "An undocumented any/any guest rule should trigger all SANS checks",
 		},
 	}
 
@@ -79,6 +93,75 @@ func TestSANSPlugin_RunChecks(t *testing.T) {
 	}
 }
 
+func TestSANSPlugin_FindingsReferenceOffenders(t *testing.T) {
+	findings := sans.NewPlugin().RunChecks(permissiveConfig())
+
+	byID := make(map[string]plugin.Finding)
+	for _, finding := range findings {
+		byID[finding.Reference] = finding
+	}
+
+	assert.Equal(t, "opt1", byID["SANS-FW-001"].Metadata["interfaces"])
+	assert.Equal(t, "filter.rule[3]: no description, any/any", byID["SANS-FW-002"].Metadata["rules"])
+	assert.Equal(t, "filter.rule[3] on opt1 (untrusted) reaches lan", byID["SANS-FW-003"].Metadata["rules"])
+	assert.Contains(t, byID["SANS-FW-003"].Description, "filter.rule[3]")
+	assert.Equal(t, "filter.rule[3]: logging disabled; firewall events are not forwarded to remote syslog",
+		byID["SANS-FW-004"].Metadata["gaps"])
+}
+
+func TestSANSPlugin_DefaultDenyPerInterface(t *testing.T) {
+	config := compliantConfig()
+	config.Filter.Rule = append(config.Filter.Rule, model.Rule{
+		Type:        "pass",
+		Descr:       "Late LAN exception",
+		Interface:   model.InterfaceList{"lan"},
+		Source:      model.Source{Network: "lan"},
+		Destination: model.Destination{Network: "any", Port: "443"},
+		Log:         true,
+	})
+
+	findings := sans.NewPlugin().RunChecks(config)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "SANS-FW-001", findings[0].Reference)
+	assert.Equal(t, "lan", findings[0].Metadata["interfaces"])
+}
+
+func TestSANSPlugin_HostSpecificRules(t *testing.T) {
+	config := compliantConfig()
+	config.Interfaces.Items["opt1"] = model.Interface{Enable: "1", Descr: "Guest"}
+	config.Filter.Rule = append(config.Filter.Rule,
+		model.Rule{
+			Type:        "pass",
+			Descr:       "Guest to LAN SSH host",
+			Interface:   model.InterfaceList{"opt1"},
+			Source:      model.Source{Address: "10.0.0.5"},
+			Destination: model.Destination{Address: "10.0.1.5", Port: "22"},
+			Log:         true,
+		},
+		model.Rule{
+			Type:        "block",
+			Descr:       "Default deny guest",
+			Interface:   model.InterfaceList{"opt1"},
+			Source:      model.Source{Any: "1"},
+			Destination: model.Destination{Any: "1"},
+			Log:         true,
+		},
+		model.Rule{
+			Type:        "block",
+			Descr:       "Block all but the management host",
+			Interface:   model.InterfaceList{"wan"},
+			Source:      model.Source{Address: "192.0.2.1", Not: true},
+			Destination: model.Destination{Any: "1"},
+			Log:         true,
+		},
+	)
+
+	// The WAN chain now ends in an inverted block, which is not a default deny
+	findings := sans.NewPlugin().RunChecks(config)
+	assert.Equal(t, []string{"SANS-FW-001"}, getFindings(findings))
+	assert.Equal(t, "wan", findings[0].Metadata["interfaces"])
+}
+
 func TestSANSPlugin_Metadata(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -181,6 +264,21 @@ func TestSANSPlugin_ValidateConfiguration(t *testing.T) {
 			plugin:      sans.NewPlugin(),
 			expectError: false,
 		},
+		{
+			name:        "Empty zone keyword",
+			plugin:      configuredPlugin(t, map[string]any{"untrusted_zones": []any{"wan", " "}}),
+			expectError: true,
+		},
+		{
+			name:        "Zone keyword with several words",
+			plugin:      configuredPlugin(t, map[string]any{"untrusted_zones": []any{"guest wifi"}}),
+			expectError: true,
+		},
+		{
+			name:        "Zone keyword both untrusted and DMZ",
+			plugin:      configuredPlugin(t, map[string]any{"dmz_zones": []any{"dmz", "WAN"}}),
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,6 +293,65 @@ func TestSANSPlugin_ValidateConfiguration(t *testing.T) {
 	}
 }
 
+func TestSANSPlugin_Configure(t *testing.T) {
+	unlogged := compliantConfig()
+	unlogged.Filter.Rule[0].Log = false
+
+	assert.Equal(t, []string{"SANS-FW-004"}, getFindings(sans.NewPlugin().RunChecks(unlogged)))
+
+	relaxed := configuredPlugin(t, map[string]any{"require_rule_logging": false})
+	require.NoError(t, relaxed.ValidateConfiguration())
+	assert.Empty(t, relaxed.RunChecks(unlogged))
+
+	err := sans.NewPlugin().Configure(map[string]any{"untrusted": []any{"wan"}})
+	require.ErrorIs(t, err, plugin.ErrPluginValidation)
+}
+
+func TestSANSPlugin_SecondaryRemoteSyslog(t *testing.T) {
+	config := compliantConfig()
+	config.Syslog.Remoteserver, config.Syslog.Remoteserver3 = "", "192.0.2.30"
+
+	assert.Empty(t, sans.NewPlugin().RunChecks(config))
+
+	config.Syslog.Remoteserver3 = ""
+	assert.Equal(t, []string{"SANS-FW-004"}, getFindings(sans.NewPlugin().RunChecks(config)))
+}
+
+func TestSANSPlugin_ZoneKeywordsMatchWholeWords(t *testing.T) {
+	zoneConfig := func(descr string) *model.OpnSenseDocument {
+		config := compliantConfig()
+		config.Interfaces.Items["opt1"] = model.Interface{Enable: "1", Descr: descr}
+		config.Filter.Rule = append(config.Filter.Rule,
+			model.Rule{
+				Type: "pass", Descr: "Lab to LAN", Interface: model.InterfaceList{"opt1"},
+				Source: model.Source{Network: "opt1"}, Destination: model.Destination{Network: "lan"}, Log: true,
+			},
+			model.Rule{
+				Type: "block", Descr: "Default deny lab", Interface: model.InterfaceList{"opt1"},
+				Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}, Log: true,
+			},
+		)
+
+		return config
+	}
+
+	sansPlugin := sans.NewPlugin()
+
+	assert.Empty(t, sansPlugin.RunChecks(zoneConfig("Patriot lab")), "iot must not match patriot")
+	assert.Empty(t, sansPlugin.RunChecks(zoneConfig("swan")), "wan must not match swan")
+	assert.Equal(t, []string{"SANS-FW-003"}, getFindings(sansPlugin.RunChecks(zoneConfig("IoT-devices"))))
+}
+
+// configuredPlugin returns a SANS plugin configured with the given settings.
+func configuredPlugin(t *testing.T, settings map[string]any) *sans.Plugin {
+	t.Helper()
+
+	p := sans.NewPlugin()
+	require.NoError(t, p.Configure(settings))
+
+	return p
+}
+
 func getFindings(findings []plugin.Finding) []string {
 	var ids []string
 	for _, finding := range findings {
@@ -202,3 +359,45 @@ func getFindings(findings []plugin.Finding) []string {
 	}
 	return ids
 }
+
+// compliantConfig returns a configuration with documented, logged rules that end in a default deny on
+// every interface and a remote syslog server receiving firewall events.
+func compliantConfig() *model.OpnSenseDocument {
+	return &model.OpnSenseDocument{
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{
+			"wan": {Enable: "1", Descr: "WAN"},
+			"lan": {Enable: "1", Descr: "LAN"},
+		}},
+		Filter: model.Filter{Rule: []model.Rule{
+			{
+				Type: "pass", Descr: "Allow LAN to any", Interface: model.InterfaceList{"lan"},
+				Source: model.Source{Network: "lan"}, Destination: model.Destination{Any: "1"}, Log: true,
+			},
+			{
+				Type: "block", Descr: "Default deny LAN", Interface: model.InterfaceList{"lan"},
+				Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}, Log: true,
+			},
+			{
+				Type: "block", Descr: "Default deny WAN", Interface: model.InterfaceList{"wan"},
+				Source: model.Source{Any: "1"}, Destination: model.Destination{Any: "1"}, Log: true,
+			},
+		}},
+		Syslog: model.Syslog{Enable: true, Remoteserver: "192.0.2.10", Filter: true},
+	}
+}
+
+// permissiveConfig returns the compliant configuration with an undocumented, unlogged any/any rule
+// on a guest interface and a remote syslog server that does not receive firewall events.
+func permissiveConfig() *model.OpnSenseDocument {
+	config := compliantConfig()
+	config.Interfaces.Items["opt1"] = model.Interface{Enable: "1", Descr: "Guest"}
+	config.Filter.Rule = append(config.Filter.Rule, model.Rule{
+		Type:        "pass",
+		Interface:   model.InterfaceList{"opt1"},
+		Source:      model.Source{Any: "1"},
+		Destination: model.Destination{Any: "1"},
+	})
+	config.Syslog.Filter = false
+
+	return config
+}
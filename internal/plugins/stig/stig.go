@@ -2,6 +2,7 @@
 package stig
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
@@ -11,13 +12,22 @@ import (
 const (
 	// NetworkAny represents "any" network in firewall rules.
 	NetworkAny = "any"
-	// MaxDHCPInterfaces represents the maximum number of DHCP interfaces before flagging as unnecessary.
+	// MaxDHCPInterfaces is the default maximum number of DHCP interfaces before flagging as unnecessary.
 	MaxDHCPInterfaces = 2
 )
 
-// Plugin implements the CompliancePlugin interface for STIG compliance.
+// Settings are the options of the STIG plugin, set in the plugin_settings.stig block of the
+// configuration file.
+type Settings struct {
+	// MaxDHCPInterfaces is the number of interfaces with a DHCP server above which DHCP is
+	// reported as an unnecessary service.
+	MaxDHCPInterfaces int `yaml:"max_dhcp_interfaces"`
+}
+
+// Plugin implements the CompliancePlugin and Configurable interfaces for STIG compliance.
 type Plugin struct {
 	controls []plugin.Control
+	settings Settings
 }
 
 // NewPlugin creates a new STIG compliance plugin.
@@ -65,6 +75,7 @@ func NewPlugin() *Plugin {
 				Tags:        []string{"logging", "audit-trail", "security-monitoring"},
 			},
 		},
+		settings: Settings{MaxDHCPInterfaces: MaxDHCPInterfaces},
 	}
 
 	return p
@@ -170,9 +181,18 @@ func (sp *Plugin) ValidateConfiguration() error {
 		return plugin.ErrNoControlsDefined
 	}
 
+	if sp.settings.MaxDHCPInterfaces < 0 {
+		return fmt.Errorf("%w: max_dhcp_interfaces must not be negative", plugin.ErrPluginValidation)
+	}
+
 	return nil
 }
 
+// Configure applies the settings block of the plugin.
+func (sp *Plugin) Configure(settings map[string]any) error {
+	return plugin.DecodeSettings(settings, &sp.settings)
+}
+
 // Helper methods for compliance checks
 
 func (sp *Plugin) hasDefaultDenyPolicy(config *model.OpnSenseDocument) bool {
@@ -271,7 +291,7 @@ func (sp *Plugin) hasUnnecessaryServices(config *model.OpnSenseDocument) bool {
 	dhcpInterfaces := config.Dhcpd.Names()
 	if len(dhcpInterfaces) > 0 {
 		// Multiple DHCP interfaces might indicate unnecessary services
-		if len(dhcpInterfaces) > MaxDHCPInterfaces {
+		if len(dhcpInterfaces) > sp.settings.MaxDHCPInterfaces {
 			return true
 		}
 	}
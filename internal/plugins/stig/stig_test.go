@@ -557,3 +557,38 @@ func TestPlugin_analyzeLoggingConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_Configure(t *testing.T) {
+	config := &model.OpnSenseDocument{
+		Dhcpd: model.Dhcpd{
+			Items: map[string]model.DhcpdInterface{
+				"lan": {Enable: "1", Range: model.Range{From: "192.168.1.100", To: "192.168.1.200"}},
+			},
+		},
+	}
+
+	plugin := NewPlugin()
+	if plugin.hasUnnecessaryServices(config) {
+		t.Fatal("hasUnnecessaryServices() = true with one DHCP interface and the default limit")
+	}
+
+	if err := plugin.Configure(map[string]any{"max_dhcp_interfaces": 0}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	if err := plugin.ValidateConfiguration(); err != nil {
+		t.Fatalf("ValidateConfiguration() error = %v", err)
+	}
+
+	if !plugin.hasUnnecessaryServices(config) {
+		t.Error("hasUnnecessaryServices() = false with max_dhcp_interfaces 0")
+	}
+
+	if err := plugin.Configure(map[string]any{"max_dhcp_interfaces": -1}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	if err := plugin.ValidateConfiguration(); err == nil {
+		t.Error("ValidateConfiguration() accepted a negative max_dhcp_interfaces")
+	}
+}
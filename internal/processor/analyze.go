@@ -1,15 +1,29 @@
 package processor
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/identity"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 )
 
+// managementPorts are destination ports treated as management services when exposed on WAN.
+var managementPorts = []string{"22", "80", "443", "8080"} //nolint:gochecknoglobals // security heuristic table
+
+// defaultAccountNames are account names treated as default or shared accounts.
+var defaultAccountNames = []string{"admin", "user"} //nolint:gochecknoglobals // security heuristic table
+
+// defaultSNMPCommunities are well-known SNMP community strings.
+var defaultSNMPCommunities = []string{"public", "private"} //nolint:gochecknoglobals // security heuristic table
+
 // interfaceListContains returns true if the interface list contains the given interface name exactly.
 func interfaceListContains(list model.InterfaceList, name string) bool {
 	return slices.Contains(list, name)
@@ -32,9 +46,15 @@ func (p *CoreProcessor) analyze(_ context.Context, cfg *model.OpnSenseDocument,
 		p.analyzeConsistency(cfg, report)
 	}
 
+	// Sysctl hardening baseline
+	if config.EnableSecurityAnalysis || config.EnableComplianceCheck {
+		p.analyzeTunables(cfg, config.TunableBaseline, report)
+	}
+
 	// Security analysis
 	if config.EnableSecurityAnalysis {
 		p.analyzeSecurityIssues(cfg, report)
+		p.analyzeIdentities(cfg, report)
 	}
 
 	// Performance analysis
@@ -43,6 +63,23 @@ func (p *CoreProcessor) analyze(_ context.Context, cfg *model.OpnSenseDocument,
 	}
 }
 
+// ScoringAnalyzer returns a scoring analyzer that runs the processor's security analysis, so
+// that scores computed outside the processor are derived from the same findings as the
+// processor report. A nil baseline selects the embedded tunables baseline.
+func ScoringAnalyzer(baseline *tunables.Baseline) scoring.Analyzer {
+	return func(cfg *model.OpnSenseDocument) []scoring.Finding {
+		p := &CoreProcessor{}
+		normalized := p.normalize(cfg)
+		report := NewReport(nil, Config{})
+
+		p.analyzeTunables(normalized, baseline, report)
+		p.analyzeSecurityIssues(normalized, report)
+		p.analyzeIdentities(normalized, report)
+
+		return report.ScoringFindings()
+	}
+}
+
 // analyzeDeadRules detects firewall rules that are never hit or are effectively dead.
 func (p *CoreProcessor) analyzeDeadRules(cfg *model.OpnSenseDocument, report *Report) {
 	rules := cfg.FilterRules()
@@ -63,6 +100,58 @@ func (p *CoreProcessor) analyzeDeadRules(cfg *model.OpnSenseDocument, report *Re
 	for iface, ifaceRules := range interfaceRules {
 		p.analyzeInterfaceRules(iface, ifaceRules, report)
 	}
+
+	// Rules enriched with filterlog data that never matched are removal candidates
+	p.analyzeUnusedRules(rules, report)
+}
+
+// analyzeUnusedRules reports enabled rules that matched no traffic in the attributed filterlog data.
+// Rules that were not enriched with log data are skipped. Rules that do not log never appear in
+// filterlog, so they are reported as having unknown coverage instead of as removal candidates.
+func (p *CoreProcessor) analyzeUnusedRules(rules []model.Rule, report *Report) {
+	for i, rule := range rules {
+		if rule.Disabled != "" || rule.Hits == nil || rule.Hits.Count > 0 {
+			continue
+		}
+
+		description := rule.Descr
+		if description == "" {
+			description = "no description"
+		}
+
+		if rule.Hits.Unlogged || !rule.Log.Bool() {
+			report.AddFinding(SeverityInfo, Finding{
+				Type:  "unlogged-rule",
+				Title: "Rule Usage Unknown",
+				Description: fmt.Sprintf(
+					"Rule at position %d on interface %s (%s) is not logged, so the filterlog data cannot show "+
+						"whether it matched traffic",
+					i+1,
+					rule.Interface.String(),
+					description,
+				),
+				Component:      fmt.Sprintf("filter.rule[%d]", i),
+				Object:         rule.ObjectID(),
+				Recommendation: "Enable logging on the rule for a representative window before judging whether it is needed",
+			})
+
+			continue
+		}
+
+		report.AddFinding(SeverityLow, Finding{
+			Type:  "unused-rule",
+			Title: "Rule Candidate for Removal",
+			Description: fmt.Sprintf(
+				"Rule at position %d on interface %s (%s) matched no traffic in the supplied filterlog data",
+				i+1,
+				rule.Interface.String(),
+				description,
+			),
+			Component:      fmt.Sprintf("filter.rule[%d]", i),
+			Object:         rule.ObjectID(),
+			Recommendation: "Confirm the rule is no longer required over a representative log window and remove it",
+		})
+	}
 }
 
 // analyzeInterfaceRules analyzes rules on a specific interface for dead rules.
@@ -81,6 +170,7 @@ func (p *CoreProcessor) analyzeInterfaceRules(iface string, rules []model.Rule,
 						iface,
 					),
 					Component:      fmt.Sprintf("filter.rule[%d+]", i+1),
+					Object:         rule.ObjectID(),
 					Recommendation: "Remove unreachable rules or reorder them before the block-all rule",
 				})
 			}
@@ -99,6 +189,7 @@ func (p *CoreProcessor) analyzeInterfaceRules(iface string, rules []model.Rule,
 						iface,
 					),
 					Component:      fmt.Sprintf("filter.rule[%d]", j),
+					Object:         rules[j].ObjectID(),
 					Recommendation: "Remove duplicate rule to simplify configuration",
 				})
 			}
@@ -115,6 +206,7 @@ func (p *CoreProcessor) analyzeInterfaceRules(iface string, rules []model.Rule,
 					iface,
 				),
 				Component:      fmt.Sprintf("filter.rule[%d]", i),
+				Object:         rule.ObjectID(),
 				Recommendation: "Add description and consider restricting source or destination",
 			})
 		}
@@ -125,13 +217,12 @@ func (p *CoreProcessor) analyzeInterfaceRules(iface string, rules []model.Rule,
 // This function compares all relevant fields that determine rule behavior.
 // Note: The current model.Rule struct is limited compared to actual OPNsense configurations.
 // Future model enhancements should include additional fields like statetype, direction,
-// quick, protocol, port, and more detailed source/destination specifications.
+// quick and protocol.
 //
 // TODO: Enhanced Rule Comparison - Expand model.Rule struct to include:
 //   - statetype (keep state, no state, etc.)
 //   - direction (in, out)
 //   - quick (quick rule processing)
-//   - port specifications for source/destination
 //   - more detailed protocol options
 //   - rule flags and advanced options
 //
@@ -145,29 +236,26 @@ func (p *CoreProcessor) rulesAreEquivalent(rule1, rule2 model.Rule) bool {
 	}
 
 	// Compare source configuration
-	if rule1.Source.Network != rule2.Source.Network {
+	if rule1.Source.String() != rule2.Source.String() ||
+		cmp.Or(rule1.Source.Port, rule1.SourcePort) != cmp.Or(rule2.Source.Port, rule2.SourcePort) {
 		return false
 	}
 
 	// Compare destination configuration
-	// Note: Current model only supports "any" destination via struct{} field
-	// This is a limitation of the current model structure
-	// In real OPNsense configs, destinations can have network, port, and other specifications
 	dest1 := p.getDestinationString(rule1.Destination)
 	dest2 := p.getDestinationString(rule2.Destination)
 
 	return dest1 == dest2
 }
 
-// getDestinationString converts the destination struct to a string for comparison.
-// This is a workaround for the current model limitation where Destination only has an Any field.
-// Future model enhancements should include proper destination fields like Network, Port, etc.
-func (p *CoreProcessor) getDestinationString(_ model.Destination) string {
-	// Check if the Any field is set (indicating "any" destination)
-	// Since struct{} is zero-sized, we can't distinguish between "not set" and "set to empty"
-	// This is a limitation of the current model structure
-	// For now, we'll assume all destinations are "any" since that's what the model supports
-	return NetworkAny
+// getDestinationString converts the destination to a string for comparison: its network or
+// address, or "any", with its inversion and port.
+func (p *CoreProcessor) getDestinationString(destination model.Destination) string {
+	if destination.Port == "" {
+		return destination.String()
+	}
+
+	return destination.String() + ":" + destination.Port
 }
 
 // analyzeUnusedInterfaces detects interfaces that are defined but not used in rules or services.
@@ -309,35 +397,40 @@ func (p *CoreProcessor) checkUserGroupConsistency(cfg *model.OpnSenseDocument, r
 					user.Groupname,
 				),
 				Component:      fmt.Sprintf("system.user[%d].groupname", i),
+				Object:         "system.user/" + user.Name,
 				Recommendation: "Create the referenced group or update user's group assignment",
 			})
 		}
 	}
 }
 
-// analyzeSecurityIssues performs security-focused analysis.
+// analyzeSecurityIssues performs security-focused analysis. The findings carry the stable
+// scoring check references the security score is derived from.
 func (p *CoreProcessor) analyzeSecurityIssues(cfg *model.OpnSenseDocument, report *Report) {
 	// WebGUI configuration
-	if cfg.System.WebGUI.Protocol != "" {
+	if protocol := cfg.System.WebGUI.Protocol; protocol != "" && !strings.EqualFold(protocol, ProtocolHTTPS) {
 		report.AddFinding(SeverityCritical, Finding{
 			Type:           FindingTypeSecurity,
 			Title:          "Insecure Web GUI Protocol",
-			Description:    "Web GUI is configured to use HTTP instead of HTTPS",
+			Description:    "Web GUI is configured to use HTTP instead of HTTPS; HTTPS provides encryption for administrative access",
 			Component:      "system.webgui.protocol",
 			Recommendation: "Change web GUI protocol to HTTPS for secure administration",
-			Reference:      "HTTPS provides encryption for administrative access",
+			Reference:      scoring.CheckInsecureWebGUI,
 		})
 	}
 
 	// Check for default SNMP community strings
-	if cfg.Snmpd.ROCommunity == "public" {
+	if community := cfg.Snmpd.ROCommunity; slices.Contains(defaultSNMPCommunities, strings.ToLower(community)) {
 		report.AddFinding(SeverityHigh, Finding{
-			Type:           FindingTypeSecurity,
-			Title:          "Default SNMP Community String",
-			Description:    "SNMP is using the default 'public' community string",
+			Type:  FindingTypeSecurity,
+			Title: "Default SNMP Community String",
+			Description: fmt.Sprintf(
+				"SNMP is using the default '%s' community string, which is well-known and poses security risks",
+				community,
+			),
 			Component:      "snmpd.rocommunity",
 			Recommendation: "Change SNMP community string to a secure, non-default value",
-			Reference:      "Default community strings are well-known and pose security risks",
+			Reference:      scoring.CheckSNMPCommunity,
 		})
 	}
 
@@ -350,11 +443,255 @@ func (p *CoreProcessor) analyzeSecurityIssues(cfg *model.OpnSenseDocument, repor
 				Title:          "Overly Permissive WAN Rule",
 				Description:    fmt.Sprintf("Rule %d allows any source to pass traffic on WAN interface", i+1),
 				Component:      fmt.Sprintf("filter.rule[%d]", i),
+				Object:         rule.ObjectID(),
 				Recommendation: "Restrict source networks or add specific destination restrictions",
 				Reference:      "WAN interfaces should have restrictive inbound rules",
 			})
 		}
 	}
+
+	p.analyzeRuleExposure(cfg, report)
+	p.analyzeManagementAccess(cfg, report)
+	p.analyzeNetworkHygiene(cfg, report)
+}
+
+// analyzeRuleExposure reports a missing rule set, enabled any-to-any pass rules and WAN
+// pass rules that expose management ports.
+func (p *CoreProcessor) analyzeRuleExposure(cfg *model.OpnSenseDocument, report *Report) {
+	rules := cfg.FilterRules()
+	if len(rules) == 0 {
+		report.AddFinding(SeverityHigh, Finding{
+			Type:           FindingTypeSecurity,
+			Title:          "No Firewall Rules Configured",
+			Description:    "The configuration contains no firewall filter rules",
+			Component:      "filter.rule",
+			Recommendation: "Define filter rules that allow only the traffic the network requires",
+			Reference:      scoring.CheckNoFirewallRules,
+		})
+
+		return
+	}
+
+	for i, rule := range rules {
+		if rule.Disabled != "" || rule.Type != RuleTypePass {
+			continue
+		}
+
+		if rule.Source.IsAny() && rule.Destination.IsAny() {
+			report.AddFinding(SeverityHigh, Finding{
+				Type:  FindingTypeSecurity,
+				Title: "Any-to-Any Pass Rule",
+				Description: fmt.Sprintf(
+					"Rule %d on interface %s passes traffic from any source to any destination",
+					i+1,
+					rule.Interface.String(),
+				),
+				Component:      fmt.Sprintf("filter.rule[%d]", i),
+				Object:         rule.ObjectID(),
+				Recommendation: "Restrict the source or destination of the rule to the networks that need it",
+				Reference:      scoring.CheckAnyAnyPass,
+			})
+		}
+	}
+
+	for i, rule := range rules {
+		port, exposed := exposedManagementPort(rule)
+		if !exposed {
+			continue
+		}
+
+		report.AddFinding(SeverityHigh, Finding{
+			Type:  FindingTypeSecurity,
+			Title: "Management Port Exposed on WAN",
+			Description: fmt.Sprintf(
+				"Rule %d allows inbound WAN traffic to management port %s",
+				i+1,
+				port,
+			),
+			Component:      fmt.Sprintf("filter.rule[%d]", i),
+			Object:         rule.ObjectID(),
+			Recommendation: "Remove WAN access to management services or restrict it to a VPN",
+			Reference:      scoring.CheckWANManagement,
+		})
+	}
+}
+
+// analyzeManagementAccess reports unrestricted SSH access and enabled default accounts.
+func (p *CoreProcessor) analyzeManagementAccess(cfg *model.OpnSenseDocument, report *Report) {
+	if cfg.System.SSH.Group == "" {
+		report.AddFinding(SeverityLow, Finding{
+			Type:           FindingTypeSecurity,
+			Title:          "SSH Access Not Restricted to a Group",
+			Description:    "SSH logins are not limited to members of a dedicated group",
+			Component:      "system.ssh.group",
+			Recommendation: "Restrict SSH logins to a dedicated administrators group",
+			Reference:      scoring.CheckSSHGroup,
+		})
+	}
+
+	for i, user := range cfg.System.User {
+		if user.Disabled.Bool() || !slices.Contains(defaultAccountNames, strings.ToLower(user.Name)) {
+			continue
+		}
+
+		report.AddFinding(SeverityMedium, Finding{
+			Type:           FindingTypeSecurity,
+			Title:          "Default Account Enabled",
+			Description:    fmt.Sprintf("User %s has a default or shared account name and is enabled", user.Name),
+			Component:      fmt.Sprintf("system.user[%d]", i),
+			Object:         "system.user/" + user.Name,
+			Recommendation: "Disable the account and use named personal accounts for administration",
+			Reference:      scoring.CheckDefaultAccount,
+		})
+	}
+}
+
+// analyzeNetworkHygiene reports missing WAN ingress filtering and missing remote logging.
+func (p *CoreProcessor) analyzeNetworkHygiene(cfg *model.OpnSenseDocument, report *Report) {
+	if wan, ok := cfg.Interfaces.Wan(); ok {
+		var missing []string
+		if wan.BlockPriv == "" {
+			missing = append(missing, "private")
+		}
+
+		if wan.BlockBogons == "" {
+			missing = append(missing, "bogon")
+		}
+
+		if len(missing) > 0 {
+			report.AddFinding(SeverityMedium, Finding{
+				Type:           FindingTypeSecurity,
+				Title:          "WAN Ingress Filtering Incomplete",
+				Description:    fmt.Sprintf("The WAN interface does not block %s networks", strings.Join(missing, " or ")),
+				Component:      "interfaces.wan",
+				Recommendation: "Enable blocking of private and bogon networks on the WAN interface",
+				Reference:      scoring.CheckWANIngressFilter,
+			})
+		}
+	}
+
+	if cfg.Syslog.Remoteserver == "" && cfg.Syslog.Remoteserver2 == "" && cfg.Syslog.Remoteserver3 == "" {
+		report.AddFinding(SeverityLow, Finding{
+			Type:           FindingTypeSecurity,
+			Title:          "Remote Logging Not Configured",
+			Description:    "Logs are not forwarded to a remote syslog server",
+			Component:      "syslog.remoteserver",
+			Recommendation: "Forward logs to at least one remote syslog server",
+			Reference:      scoring.CheckRemoteLogging,
+		})
+	}
+}
+
+// exposedManagementPort returns the management port an enabled inbound WAN pass rule
+// allows, if any.
+func exposedManagementPort(rule model.Rule) (string, bool) {
+	if rule.Disabled != "" || !interfaceListContains(rule.Interface, "wan") {
+		return "", false
+	}
+
+	if rule.Type == model.RuleTypeBlock || rule.Type == "reject" {
+		return "", false
+	}
+
+	if rule.Direction != "" && !strings.EqualFold(rule.Direction, "in") {
+		return "", false
+	}
+
+	for _, port := range managementPorts {
+		if portMatches(rule.Destination.Port, port) {
+			return port, true
+		}
+	}
+
+	return "", false
+}
+
+// portMatches reports whether a destination port specification includes port.
+// Single ports and ranges ("from-to" or "from:to") are supported.
+func portMatches(spec, port string) bool {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return false
+	}
+
+	if spec == port {
+		return true
+	}
+
+	for _, sep := range []string{"-", ":"} {
+		if from, to, found := strings.Cut(spec, sep); found {
+			return inRange(port, from, to)
+		}
+	}
+
+	return false
+}
+
+// inRange reports whether port lies within the inclusive numeric range from-to.
+func inRange(port, from, to string) bool {
+	p, errPort := strconv.Atoi(port)
+	lo, errFrom := strconv.Atoi(strings.TrimSpace(from))
+	hi, errTo := strconv.Atoi(strings.TrimSpace(to))
+
+	if errPort != nil || errFrom != nil || errTo != nil {
+		return false
+	}
+
+	return p >= lo && p <= hi
+}
+
+// analyzeIdentities reports user, privilege and credential hygiene issues and
+// records the per-user privilege matrix on the report.
+func (p *CoreProcessor) analyzeIdentities(cfg *model.OpnSenseDocument, report *Report) {
+	analysis := identity.Analyze(cfg)
+
+	report.PrivilegeMatrix = analysis.Users
+
+	for _, issue := range analysis.Issues {
+		report.AddFinding(Severity(issue.Severity), Finding{
+			Type:           FindingTypeIdentity,
+			Title:          issue.Title,
+			Description:    issue.Description,
+			Component:      issue.Component,
+			Object:         issue.Object,
+			Recommendation: issue.Recommendation,
+		})
+	}
+}
+
+// analyzeTunables compares sysctl tunables with the hardening baseline, records the
+// comparison on the report and reports every deviation under the tunable name.
+func (p *CoreProcessor) analyzeTunables(cfg *model.OpnSenseDocument, baseline *tunables.Baseline, report *Report) {
+	if baseline == nil {
+		baseline = tunables.Default()
+	}
+
+	report.TunableBaseline = tunables.Compare(baseline, cfg.Sysctl)
+
+	for _, check := range tunables.Deviations(report.TunableBaseline) {
+		actual := check.Actual
+		if !check.Configured() {
+			actual = check.Default + " (default)"
+		}
+
+		description := fmt.Sprintf("%s is %s, the baseline expects %s", check.Tunable, actual, check.Expected)
+		if check.Rationale != "" {
+			description += ": " + check.Rationale
+		}
+
+		report.AddFinding(Severity(check.Severity), Finding{
+			Type:        FindingTypeHardening,
+			Title:       "Tunable Deviates From Hardening Baseline",
+			Description: description,
+			Component:   "sysctl." + check.Tunable,
+			Recommendation: fmt.Sprintf(
+				"Set %s to %s under System > Settings > Tunables",
+				check.Tunable,
+				check.Expected,
+			),
+			Reference: check.Tunable,
+		})
+	}
 }
 
 // analyzePerformanceIssues performs performance-focused analysis.
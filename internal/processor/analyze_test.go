@@ -9,6 +9,7 @@ import (
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
 	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,6 +124,48 @@ func TestCoreProcessor_RulesAreEquivalent(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "different source addresses",
+			rule1: model.Rule{
+				Type:      "pass",
+				Interface: model.InterfaceList{"lan"},
+				Source:    model.Source{Address: "10.0.0.5"},
+			},
+			rule2: model.Rule{
+				Type:      "pass",
+				Interface: model.InterfaceList{"lan"},
+				Source:    model.Source{Address: "10.0.0.6"},
+			},
+			expected: false,
+		},
+		{
+			name: "different destination ports",
+			rule1: model.Rule{
+				Type:        "pass",
+				Interface:   model.InterfaceList{"lan"},
+				Destination: model.Destination{Address: "10.0.1.5", Port: "22"},
+			},
+			rule2: model.Rule{
+				Type:        "pass",
+				Interface:   model.InterfaceList{"lan"},
+				Destination: model.Destination{Address: "10.0.1.5", Port: "443"},
+			},
+			expected: false,
+		},
+		{
+			name: "inverted destination",
+			rule1: model.Rule{
+				Type:        "pass",
+				Interface:   model.InterfaceList{"lan"},
+				Destination: model.Destination{Network: "lan"},
+			},
+			rule2: model.Rule{
+				Type:        "pass",
+				Interface:   model.InterfaceList{"lan"},
+				Destination: model.Destination{Network: "lan", Not: true},
+			},
+			expected: false,
+		},
 		{
 			name: "complex rules with all fields",
 			rule1: model.Rule{
@@ -359,3 +402,130 @@ func TestCoreProcessor_EdgeCases(t *testing.T) {
 			"Rules should be case sensitive")
 	})
 }
+
+func TestExposedManagementPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.Rule
+		exposed bool
+	}{
+		{
+			name: "LAN rule",
+			rule: model.Rule{Interface: model.InterfaceList{"lan"}, Destination: model.Destination{Port: "22"}},
+		},
+		{
+			name:    "WAN rule with management port",
+			rule:    model.Rule{Interface: model.InterfaceList{"wan"}, Destination: model.Destination{Port: "22"}},
+			exposed: true,
+		},
+		{
+			name:    "WAN rule with management port range",
+			rule:    model.Rule{Interface: model.InterfaceList{"wan"}, Destination: model.Destination{Port: "8000-8100"}},
+			exposed: true,
+		},
+		{
+			name: "WAN rule with non-management port",
+			rule: model.Rule{Interface: model.InterfaceList{"wan"}, Destination: model.Destination{Port: "53"}},
+		},
+		{
+			name: "outbound WAN rule",
+			rule: model.Rule{
+				Interface:   model.InterfaceList{"wan"},
+				Direction:   "out",
+				Destination: model.Destination{Port: "22"},
+			},
+		},
+		{
+			name: "block rule on WAN",
+			rule: model.Rule{
+				Type:        "block",
+				Interface:   model.InterfaceList{"wan"},
+				Destination: model.Destination{Port: "443"},
+			},
+		},
+		{
+			name: "disabled WAN rule",
+			rule: model.Rule{
+				Interface:   model.InterfaceList{"wan"},
+				Disabled:    "1",
+				Destination: model.Destination{Port: "443"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, exposed := exposedManagementPort(tt.rule)
+			assert.Equal(t, tt.exposed, exposed)
+		})
+	}
+}
+
+func TestCoreProcessor_ScoredSecurityChecks(t *testing.T) {
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	references := func(cfg *model.OpnSenseDocument) map[string][]string {
+		report := NewReport(nil, Config{})
+		processor.analyzeSecurityIssues(cfg, report)
+
+		found := make(map[string][]string)
+		for _, finding := range report.ScoringFindings() {
+			found[finding.Reference] = append(found[finding.Reference], finding.Component)
+		}
+
+		return found
+	}
+
+	insecure := &model.OpnSenseDocument{
+		System: model.System{
+			WebGUI: model.WebGUIConfig{Protocol: "http"},
+			User:   []model.User{{Name: "root"}, {Name: "Admin"}, {Name: "user", Disabled: true}},
+		},
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{"wan": {BlockPriv: "1"}}},
+		Filter: model.Filter{Rule: []model.Rule{
+			{Type: "pass", Interface: model.InterfaceList{"lan"}, Source: model.Source{Network: "lan"}},
+			{Type: "pass", Interface: model.InterfaceList{"lan"}},
+			{
+				Type:        "pass",
+				Interface:   model.InterfaceList{"lan"},
+				Source:      model.Source{Network: "any", Not: true},
+				Destination: model.Destination{Network: "any"},
+			},
+			{Type: "pass", Interface: model.InterfaceList{"wan"}, Destination: model.Destination{Port: "22"}},
+		}},
+		Snmpd: model.Snmpd{ROCommunity: "Private"},
+	}
+
+	found := references(insecure)
+	assert.Equal(t, []string{"system.webgui.protocol"}, found[scoring.CheckInsecureWebGUI])
+	assert.Equal(t, []string{"snmpd.rocommunity"}, found[scoring.CheckSNMPCommunity])
+	assert.Equal(t, []string{"filter.rule[1]", "filter.rule[3]"}, found[scoring.CheckAnyAnyPass])
+	assert.Equal(t, []string{"filter.rule[3]"}, found[scoring.CheckWANManagement])
+	assert.Equal(t, []string{"system.ssh.group"}, found[scoring.CheckSSHGroup])
+	assert.Equal(t, []string{"system.user[1]"}, found[scoring.CheckDefaultAccount])
+	assert.Equal(t, []string{"interfaces.wan"}, found[scoring.CheckWANIngressFilter])
+	assert.Equal(t, []string{"syslog.remoteserver"}, found[scoring.CheckRemoteLogging])
+	assert.Empty(t, found[scoring.CheckNoFirewallRules])
+
+	hardened := &model.OpnSenseDocument{
+		System: model.System{
+			WebGUI: model.WebGUIConfig{Protocol: "https"},
+			SSH:    model.SSHConfig{Group: "admins"},
+			User:   []model.User{{Name: "root"}},
+		},
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{"wan": {BlockPriv: "1", BlockBogons: "1"}}},
+		Filter: model.Filter{Rule: []model.Rule{
+			{Type: "block", Interface: model.InterfaceList{"wan"}},
+		}},
+		Snmpd:  model.Snmpd{ROCommunity: "s3cret"},
+		Syslog: model.Syslog{Remoteserver2: "10.0.0.10"},
+	}
+
+	assert.Empty(t, references(hardened))
+	assert.Equal(t, map[string][]string{
+		scoring.CheckNoFirewallRules: {"filter.rule"},
+		scoring.CheckSSHGroup:        {"system.ssh.group"},
+		scoring.CheckRemoteLogging:   {"syslog.remoteserver"},
+	}, references(&model.OpnSenseDocument{}))
+}
@@ -1,5 +1,7 @@
 package processor
 
+import "github.com/EvilBit-Labs/opnDossier/internal/scoring"
+
 // Re-export constants from the constants package for backward compatibility.
 // This allows existing code to continue working while avoiding import cycles.
 const (
@@ -13,7 +15,9 @@ const (
 	RuleTypePass = "pass"
 
 	// Finding types.
-	FindingTypeSecurity = "security"
+	FindingTypeSecurity  = "security"
+	FindingTypeIdentity  = "identity"
+	FindingTypeHardening = scoring.FindingTypeHardening
 
 	// Theme constants.
 	ThemeLight = "light"
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -91,7 +92,7 @@ func TestCoreProcessor_Process(t *testing.T) {
 			}
 		}
 
-		assert.True(t, hasHTTPFinding, "Should have HTTP security finding")
+		assert.False(t, hasHTTPFinding, "HTTPS web GUI should not be reported as insecure")
 		assert.True(t, hasSNMPFinding, "Should have SNMP security finding")
 	})
 
@@ -454,3 +455,160 @@ func TestCoreProcessor_Analysis(t *testing.T) {
 		assert.True(t, hasConsistencyFinding, "Should detect user referencing non-existent group")
 	})
 }
+
+func TestCoreProcessor_UnusedRuleFindings(t *testing.T) {
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	cfg := &model.OpnSenseDocument{
+		System: model.System{Hostname: "test-host", Domain: "test.local"},
+		Filter: model.Filter{
+			Rule: []model.Rule{
+				{Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "used", Log: true, Hits: &model.RuleHits{Count: 4}},
+				{Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "stale", Log: true, Hits: &model.RuleHits{}},
+				{
+					Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "off", Disabled: "1", Log: true,
+					Hits: &model.RuleHits{},
+				},
+				{Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "unknown", Log: true},
+				{Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "quiet", Hits: &model.RuleHits{Unlogged: true}},
+			},
+		},
+	}
+
+	report, err := processor.Process(context.Background(), cfg, WithDeadRuleCheck())
+	require.NoError(t, err)
+
+	var unused []Finding
+
+	for _, finding := range report.Findings.Low {
+		if finding.Type == "unused-rule" {
+			unused = append(unused, finding)
+		}
+	}
+
+	// Normalization sorts the rules by description: off, quiet, stale, unknown, used
+	require.Len(t, unused, 1)
+	assert.Equal(t, "Rule Candidate for Removal", unused[0].Title)
+	assert.Equal(t, "filter.rule[2]", unused[0].Component)
+	assert.Contains(t, unused[0].Description, "stale")
+
+	var unlogged []Finding
+
+	for _, finding := range report.Findings.Info {
+		if finding.Type == "unlogged-rule" {
+			unlogged = append(unlogged, finding)
+		}
+	}
+
+	require.Len(t, unlogged, 1)
+	assert.Equal(t, "Rule Usage Unknown", unlogged[0].Title)
+	assert.Equal(t, "filter.rule[1]", unlogged[0].Component)
+	assert.Contains(t, unlogged[0].Description, "not logged")
+}
+
+func TestCoreProcessor_IdentityFindings(t *testing.T) {
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	cfg := &model.OpnSenseDocument{
+		System: model.System{
+			Hostname: "test-host",
+			Domain:   "test.local",
+			Group:    []model.Group{{Name: "admins", Gid: "1999", Member: "0", Priv: "page-all"}},
+			User: []model.User{
+				{Name: "root", UID: "0", Groupname: "admins", Password: "$2y$10$abcdefghijklmnopqrstuv"},
+				{Name: "legacy", UID: "2000", Password: "$1$salt$hash"},
+			},
+		},
+	}
+
+	report, err := processor.Process(context.Background(), cfg, WithSecurityAnalysis())
+	require.NoError(t, err)
+
+	require.Len(t, report.PrivilegeMatrix, 2)
+
+	admins := make(map[string]bool)
+	for _, user := range report.PrivilegeMatrix {
+		admins[user.Name] = user.Admin
+	}
+
+	assert.Equal(t, map[string]bool{"root": true, "legacy": false}, admins)
+
+	var weak []Finding
+
+	for _, finding := range report.Findings.High {
+		if finding.Type == FindingTypeIdentity {
+			weak = append(weak, finding)
+		}
+	}
+
+	require.Len(t, weak, 1)
+	assert.Equal(t, "Weak Password Hash", weak[0].Title)
+	assert.Contains(t, weak[0].Description, "legacy")
+	assert.Contains(t, report.ToMarkdown(), "## User Privilege Matrix")
+
+	// Without security analysis no matrix is recorded
+	report, err = processor.Process(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Empty(t, report.PrivilegeMatrix)
+}
+
+func TestCoreProcessor_TunableBaselineFindings(t *testing.T) {
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	cfg := &model.OpnSenseDocument{
+		System: model.System{Hostname: "test-host", Domain: "test.local"},
+		Sysctl: []model.SysctlItem{
+			{Tunable: "kern.randompid", Value: "1"},
+			{Tunable: "net.inet.tcp.blackhole", Value: "0"},
+		},
+	}
+
+	baseline := &tunables.Baseline{Name: "test", Tunables: []tunables.Expectation{
+		{Tunable: "kern.randompid", Expected: "1", Default: "0", Severity: "low"},
+		{Tunable: "net.inet.tcp.blackhole", Expected: "2", Default: "0", Severity: "high", Rationale: "drop scans"},
+		{Tunable: "net.inet.udp.blackhole", Expected: "1", Default: "0", Severity: "medium"},
+	}}
+
+	report, err := processor.Process(
+		context.Background(),
+		cfg,
+		WithComplianceCheck(),
+		WithTunableBaseline(baseline),
+	)
+	require.NoError(t, err)
+	require.Len(t, report.TunableBaseline, 3)
+
+	var hardening []Finding
+
+	for _, finding := range report.Findings.High {
+		if finding.Type == FindingTypeHardening {
+			hardening = append(hardening, finding)
+		}
+	}
+
+	require.Len(t, hardening, 1)
+	high := hardening[0]
+	assert.Equal(t, "sysctl.net.inet.tcp.blackhole", high.Component)
+	assert.Equal(t, "net.inet.tcp.blackhole is 0, the baseline expects 2: drop scans", high.Description)
+	assert.Equal(t, "net.inet.tcp.blackhole", high.Reference)
+
+	var medium []Finding
+
+	for _, finding := range report.Findings.Medium {
+		if finding.Type == FindingTypeHardening {
+			medium = append(medium, finding)
+		}
+	}
+
+	require.Len(t, medium, 1)
+	assert.Equal(t, "net.inet.udp.blackhole is 0 (default), the baseline expects 1", medium[0].Description)
+	assert.Contains(t, report.ToMarkdown(), "## Sysctl Hardening Baseline")
+
+	// The built-in baseline is used when none is configured
+	report, err = processor.Process(context.Background(), cfg, WithSecurityAnalysis())
+	require.NoError(t, err)
+	assert.Len(t, report.TunableBaseline, len(tunables.Default().Tunables))
+}
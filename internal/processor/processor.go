@@ -12,6 +12,8 @@ import (
 
 	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -108,6 +110,11 @@ func (p *CoreProcessor) Process(ctx context.Context, cfg *model.OpnSenseDocument
 	// Phase 3: Analyze the configuration
 	p.analyze(ctx, normalizedCfg, config, report)
 
+	// Phase 4: Derive the security score from the security findings
+	if config.EnableSecurityAnalysis {
+		report.ApplySecurityScore(normalizedCfg, config.ScoringEngine)
+	}
+
 	// Check for context cancellation
 	select {
 	case <-ctx.Done():
@@ -148,6 +155,10 @@ type Config struct {
 	EnablePerformanceAnalysis bool
 	// EnableComplianceCheck controls whether to check compliance with best practices
 	EnableComplianceCheck bool
+	// ScoringEngine scores the security analysis findings. The default catalogue is used when nil.
+	ScoringEngine *scoring.Engine `json:"-" yaml:"-"`
+	// TunableBaseline is the sysctl hardening baseline. The embedded baseline is used when nil.
+	TunableBaseline *tunables.Baseline `json:"-" yaml:"-"`
 }
 
 // WithStats enables statistics generation in the processor.
@@ -185,6 +196,20 @@ func WithComplianceCheck() Option {
 	}
 }
 
+// WithScoringEngine sets the engine used to compute the security score.
+func WithScoringEngine(engine *scoring.Engine) Option {
+	return func(config *Config) {
+		config.ScoringEngine = engine
+	}
+}
+
+// WithTunableBaseline sets the sysctl hardening baseline used by compliance and security analysis.
+func WithTunableBaseline(baseline *tunables.Baseline) Option {
+	return func(config *Config) {
+		config.TunableBaseline = baseline
+	}
+}
+
 // WithAllFeatures enables all available analysis features.
 func WithAllFeatures() Option {
 	return func(config *Config) {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,9 +46,7 @@ func TestExampleProcessor_Process_BasicAnalysis(t *testing.T) {
 			Hostname: "test-firewall",
 			Domain:   "example.com",
 			WebGUI:   model.WebGUIConfig{Protocol: "https"},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admins"},
+			SSH:      model.SSHConfig{Group: "admins"},
 			Bogons: struct {
 				Interval string `xml:"interval" json:"interval,omitempty" yaml:"interval,omitempty" validate:"omitempty,oneof=monthly weekly daily never"`
 			}{Interval: "monthly"},
@@ -75,9 +74,7 @@ func TestExampleProcessor_Process_WithOptions(t *testing.T) {
 			Hostname: "test-firewall",
 			Domain:   "example.com",
 			WebGUI:   model.WebGUIConfig{Protocol: "http"}, // Insecure protocol
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admins"}, // SSH enabled
+			SSH:      model.SSHConfig{Group: "admins"},     // SSH enabled
 		},
 		Snmpd: model.Snmpd{
 			ROCommunity: "public", // Default community string
@@ -211,6 +208,70 @@ func TestReport_ToMarkdown(t *testing.T) {
 	assert.Contains(t, markdown, "High (1)")
 }
 
+func TestReport_SecurityScore(t *testing.T) {
+	cfg := &model.OpnSenseDocument{
+		System: model.System{
+			Hostname: "test-firewall",
+			WebGUI:   model.WebGUIConfig{Protocol: "http"},
+		},
+	}
+
+	enabled := false
+	engine, err := scoring.NewEngine(scoring.WithOverrides(scoring.Override{
+		ID:      scoring.ControlRemoteLogging,
+		Enabled: &enabled,
+	}))
+	require.NoError(t, err)
+
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	report, err := processor.Process(context.Background(), cfg, WithSecurityAnalysis(), WithScoringEngine(engine))
+	require.NoError(t, err)
+	require.NotNil(t, report.SecurityScore)
+	assert.Len(t, report.SecurityScore.Items, len(scoring.DefaultControls())-1)
+	assert.Equal(t, report.SecurityScore.Score, report.Statistics.Summary.SecurityScore)
+
+	assert.Contains(t, report.SecurityScore.Failed(), model.ScoreItem{
+		ControlID: scoring.ControlHTTPSWebGUI,
+		Title:     "Web GUI uses HTTPS",
+		Category:  "Management",
+		Weight:    15,
+		Status:    model.ScoreStatusFail,
+		Evidence:  "Insecure Web GUI Protocol (system.webgui.protocol)",
+	})
+
+	markdown := report.ToMarkdown()
+	assert.Contains(t, markdown, "## Security Score")
+	assert.Contains(t, markdown, scoring.ControlHTTPSWebGUI)
+	assert.NotContains(t, markdown, scoring.ControlRemoteLogging)
+
+	// Without security analysis there are no findings to derive a score from
+	report, err = processor.Process(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Nil(t, report.SecurityScore)
+}
+
+func TestScoringAnalyzer(t *testing.T) {
+	cfg := &model.OpnSenseDocument{
+		System: model.System{
+			Hostname: "test-firewall",
+			WebGUI:   model.WebGUIConfig{Protocol: "http"},
+		},
+	}
+
+	processor, err := NewCoreProcessor()
+	require.NoError(t, err)
+
+	report, err := processor.Process(context.Background(), cfg, WithSecurityAnalysis())
+	require.NoError(t, err)
+
+	engine, err := scoring.NewEngine(scoring.WithAnalyzer(ScoringAnalyzer(nil)))
+	require.NoError(t, err)
+
+	assert.Equal(t, report.SecurityScore, engine.Evaluate(cfg))
+}
+
 func TestReport_Summary(t *testing.T) {
 	cfg := &model.OpnSenseDocument{
 		System: model.System{
@@ -741,9 +802,7 @@ func generateSmallConfig() *model.OpnSenseDocument {
 			Hostname: "small-config",
 			Domain:   "example.com",
 			WebGUI:   model.WebGUIConfig{Protocol: "https"},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admins"},
+			SSH:      model.SSHConfig{Group: "admins"},
 			Bogons: struct {
 				Interval string `xml:"interval" json:"interval,omitempty" yaml:"interval,omitempty" validate:"omitempty,oneof=monthly weekly daily never"`
 			}{Interval: "monthly"},
@@ -819,9 +878,7 @@ func generateLargeConfig() *model.OpnSenseDocument {
 			Hostname: "large-config",
 			Domain:   "example.com",
 			WebGUI:   model.WebGUIConfig{Protocol: "https"},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admins"},
+			SSH:      model.SSHConfig{Group: "admins"},
 			Bogons: struct {
 				Interval string `xml:"interval" json:"interval,omitempty" yaml:"interval,omitempty" validate:"omitempty,oneof=monthly weekly daily never"`
 			}{Interval: "monthly"},
@@ -1274,9 +1331,7 @@ func TestCoreProcessor_StatisticsAccuracy(t *testing.T) {
 						{Name: "users", Scope: "system", Gid: "1001"},
 					},
 					WebGUI: model.WebGUIConfig{Protocol: "https"},
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{Group: "admins"},
+					SSH:    model.SSHConfig{Group: "admins"},
 					Bogons: struct {
 						Interval string `xml:"interval" json:"interval,omitempty" yaml:"interval,omitempty" validate:"omitempty,oneof=monthly weekly daily never"`
 					}{Interval: "monthly"},
@@ -1583,9 +1638,7 @@ func TestCoreProcessor_ValidationErrors(t *testing.T) {
 				System: model.System{
 					Hostname: "", // Empty hostname should trigger validation error
 					Domain:   "example.com",
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{Group: ""}, // Empty required field
+					SSH:      model.SSHConfig{Group: ""}, // Empty required field
 				},
 				Interfaces: model.Interfaces{
 					Items: map[string]model.Interface{
@@ -1602,9 +1655,7 @@ func TestCoreProcessor_ValidationErrors(t *testing.T) {
 				System: model.System{
 					Hostname: "valid-host",
 					Domain:   "example.com",
-					SSH: struct {
-						Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-					}{Group: "admins"},
+					SSH:      model.SSHConfig{Group: "admins"},
 				},
 				Interfaces: model.Interfaces{
 					Items: map[string]model.Interface{
@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/identity"
 	"github.com/EvilBit-Labs/opnDossier/internal/metrics"
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/scoring"
+	"github.com/EvilBit-Labs/opnDossier/internal/tunables"
 	"github.com/nao1215/markdown"
 	"gopkg.in/yaml.v3"
 )
@@ -31,6 +34,15 @@ type Report struct {
 	// Statistics contains various statistics about the configuration
 	Statistics *Statistics `json:"statistics,omitempty"`
 
+	// SecurityScore contains the security score with its per-control breakdown
+	SecurityScore *model.ScoreBreakdown `json:"securityScore,omitempty"`
+
+	// PrivilegeMatrix lists the effective privileges of every user (security analysis only)
+	PrivilegeMatrix []model.UserPrivileges `json:"privilegeMatrix,omitempty"`
+
+	// TunableBaseline compares sysctl tunables with the hardening baseline (security or compliance analysis only)
+	TunableBaseline []model.TunableCheck `json:"tunableBaseline,omitempty"`
+
 	// Findings contains analysis findings categorized by type
 	Findings Findings `json:"findings"`
 
@@ -153,6 +165,8 @@ type Finding struct {
 	Recommendation string `json:"recommendation,omitempty"`
 	// Component identifies the configuration component involved
 	Component string `json:"component,omitempty"`
+	// Object identifies the configuration object involved independently of its position, such as a rule UUID
+	Object string `json:"object,omitempty"`
 	// Reference provides additional information or documentation links
 	Reference string `json:"reference,omitempty"`
 }
@@ -203,6 +217,43 @@ func NewReport(cfg *model.OpnSenseDocument, processorConfig Config) *Report {
 	return report
 }
 
+// ApplySecurityScore scores the configuration on the report's findings and records the
+// breakdown on the report and its statistics. The default catalogue is used when engine is nil.
+func (r *Report) ApplySecurityScore(cfg *model.OpnSenseDocument, engine *scoring.Engine) {
+	if engine == nil {
+		engine = scoring.Default()
+	}
+
+	r.SecurityScore = engine.EvaluateFindings(cfg, r.ScoringFindings())
+
+	if r.Statistics != nil {
+		r.Statistics.Summary.SecurityScore = r.SecurityScore.Score
+	}
+}
+
+// ScoringFindings returns the report's findings in the form the scoring engine consumes.
+func (r *Report) ScoringFindings() []scoring.Finding {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := [][]Finding{r.Findings.Critical, r.Findings.High, r.Findings.Medium, r.Findings.Low, r.Findings.Info}
+
+	var findings []scoring.Finding
+
+	for _, group := range groups {
+		for _, finding := range group {
+			findings = append(findings, scoring.Finding{
+				Type:      finding.Type,
+				Reference: finding.Reference,
+				Title:     finding.Title,
+				Component: finding.Component,
+			})
+		}
+	}
+
+	return findings
+}
+
 // AddFinding adds a finding to the report with the specified severity.
 func (r *Report) AddFinding(severity Severity, finding Finding) {
 	r.mu.Lock()
@@ -262,7 +313,12 @@ func (r *Report) ToFormat(format OutputFormat) (string, error) {
 func (r *Report) ToJSON() (string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	data, err := json.MarshalIndent(r, "", "  ") //nolint:musttag // Report has proper json tags
+	versioned := struct {
+		SchemaVersion string `json:"schemaVersion"`
+		*Report
+	}{constants.SchemaVersion, r}
+
+	data, err := json.MarshalIndent(versioned, "", "  ") //nolint:musttag // Report has proper json tags
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal report to JSON: %w", err)
 	}
@@ -292,6 +348,18 @@ func (r *Report) ToMarkdown() string {
 		r.addStatistics(md)
 	}
 
+	if r.SecurityScore != nil {
+		r.addSecurityScore(md)
+	}
+
+	if len(r.PrivilegeMatrix) > 0 {
+		r.addPrivilegeMatrix(md)
+	}
+
+	if len(r.TunableBaseline) > 0 {
+		r.addTunableBaseline(md)
+	}
+
 	r.addFindings(md)
 
 	if err := md.Build(); err != nil {
@@ -325,6 +393,26 @@ func (r *Report) addConfigInfo(md *markdown.Markdown) {
 	md.LF()
 }
 
+func (r *Report) addSecurityScore(md *markdown.Markdown) {
+	md.H2("Security Score")
+	md.PlainTextf("%s: %s", markdown.Bold("Score"), scoring.Summary(r.SecurityScore))
+	md.LF()
+	md.Table(scoring.BreakdownTable(r.SecurityScore))
+	md.LF()
+}
+
+func (r *Report) addPrivilegeMatrix(md *markdown.Markdown) {
+	md.H2("User Privilege Matrix")
+	md.Table(identity.MatrixTable(r.PrivilegeMatrix))
+	md.LF()
+}
+
+func (r *Report) addTunableBaseline(md *markdown.Markdown) {
+	md.H2("Sysctl Hardening Baseline")
+	md.Table(tunables.ComplianceTable(r.TunableBaseline))
+	md.LF()
+}
+
 func (r *Report) addStatistics(md *markdown.Markdown) {
 	md.H2("Configuration Statistics")
 	md.H3("Overview")
@@ -577,6 +665,7 @@ func (r *Report) addFindingsSection(md *markdown.Markdown, title string, finding
 // generateStatistics analyzes an OPNsense configuration and returns aggregated statistics.
 //
 // The returned Statistics struct includes interface details, firewall and NAT rule counts, DHCP scopes, user and group counts, enabled services, system settings, detected security features, and summary metrics such as total configuration items, security score, and complexity.
+// The security score is filled in by ApplySecurityScore once the report's findings have been scored.
 func generateStatistics(cfg *model.OpnSenseDocument) *Statistics {
 	stats := &Statistics{
 		InterfacesByType: make(map[string]int),
@@ -795,7 +884,6 @@ func generateStatistics(cfg *model.OpnSenseDocument) *Statistics {
 	}
 
 	// Calculate summary statistics
-	securityScore := calculateSecurityScore(cfg, stats)
 	configComplexity := calculateConfigComplexity(stats)
 
 	stats.Summary = StatisticsSummary{
@@ -811,7 +899,6 @@ func generateStatistics(cfg *model.OpnSenseDocument) *Statistics {
 			stats.DHCPScopes,
 			stats.LoadBalancerMonitors,
 		),
-		SecurityScore:       securityScore,
 		ConfigComplexity:    configComplexity,
 		HasSecurityFeatures: len(stats.SecurityFeatures) > 0,
 	}
@@ -819,36 +906,6 @@ func generateStatistics(cfg *model.OpnSenseDocument) *Statistics {
 	return stats
 }
 
-// calculateSecurityScore returns a security score for the given OPNsense configuration based on detected security features, firewall rules, HTTPS Web GUI usage, and SSH group configuration. The score is capped at a defined maximum.
-func calculateSecurityScore(cfg *model.OpnSenseDocument, stats *Statistics) int {
-	score := 0
-
-	// Security features contribute to score
-	score += len(stats.SecurityFeatures) * constants.SecurityFeatureMultiplier
-
-	// Firewall rules indicate active security configuration
-	if stats.TotalFirewallRules > 0 {
-		score += 20
-	}
-
-	// HTTPS web interface
-	if cfg.System.WebGUI.Protocol == constants.ProtocolHTTPS {
-		score += 15
-	}
-
-	// SSH configuration
-	if cfg.System.SSH.Group != "" {
-		score += 10
-	}
-
-	// Cap at MaxSecurityScore
-	if score > constants.MaxSecurityScore {
-		score = constants.MaxSecurityScore
-	}
-
-	return score
-}
-
 // calculateConfigComplexity returns a normalized complexity score for the configuration based on weighted counts of interfaces, firewall rules, users, groups, sysctl settings, services, DHCP scopes, and load balancer monitors. The score is scaled to a maximum defined value.
 func calculateConfigComplexity(stats *Statistics) int {
 	complexity := 0
@@ -0,0 +1,483 @@
+package ruleset
+
+import (
+	"fmt"
+	"maps"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Address types of aliases.
+const (
+	aliasHost         = "host"
+	aliasNetwork      = "network"
+	aliasNetworkGroup = "networkgroup"
+	aliasPort         = "port"
+)
+
+// maxPort is the highest TCP and UDP port.
+const maxPort = 65535
+
+// protocolNames are the protocols that nft and iptables know by name.
+var protocolNames = []string{ //nolint:gochecknoglobals // Lookup table
+	"tcp", "udp", "icmp", "ipv6-icmp", "igmp", "gre", "esp", "ah", "sctp",
+}
+
+// protocolNumbers maps protocols without a portable name onto their protocol numbers.
+var protocolNumbers = map[string]string{ //nolint:gochecknoglobals // Lookup table
+	"carp":   "112",
+	"pfsync": "240",
+	"ospf":   "89",
+	"pim":    "103",
+}
+
+// serviceName matches service names such as https, which nft and iptables resolve.
+var serviceName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// endpoint is the source or destination of a rule.
+type endpoint struct {
+	any, network, address string
+	not                   bool
+}
+
+// sourceEndpoint returns the endpoint of a rule source.
+func sourceEndpoint(s model.Source) endpoint {
+	return endpoint{any: s.Any, network: s.Network, address: s.Address, not: bool(s.Not)}
+}
+
+// destinationEndpoint returns the endpoint of a rule destination.
+func destinationEndpoint(d model.Destination) endpoint {
+	return endpoint{any: d.Any, network: d.Network, address: d.Address, not: bool(d.Not)}
+}
+
+// value returns the network or address of the endpoint, or an empty string for any.
+func (e endpoint) value() string {
+	value := firstNonEmpty(e.network, e.address)
+	if value == "any" {
+		return ""
+	}
+
+	return value
+}
+
+// needsFamily reports whether the endpoint matches addresses of a single family.
+func (e endpoint) needsFamily() bool {
+	value := e.value()
+	return value != "" && value != "(self)"
+}
+
+// address translates an endpoint for an address family. skip reports that the endpoint has no
+// addresses in the family; reason reports why the endpoint cannot be translated.
+func (t *translator) address(e endpoint, fam family) (address, bool, string) {
+	value := e.value()
+	if value == "" {
+		return address{}, false, ""
+	}
+
+	a := address{not: e.not}
+
+	switch {
+	case value == "(self)":
+		a.local = true
+		return a, false, ""
+	case t.aliases[value] != nil:
+		name, skip, reason := t.aliasSet(value, fam)
+		a.set = name
+
+		return a, skip, reason
+	case t.isInterfaceAddress(value):
+		prefix, skip, reason := t.interfaceAddress(strings.TrimSuffix(value, "ip"), fam, false)
+		a.prefix = prefix
+
+		return a, skip, reason
+	}
+
+	if _, ok := t.doc.Interfaces.Items[value]; ok {
+		prefix, skip, reason := t.interfaceAddress(value, fam, true)
+		a.prefix = prefix
+
+		return a, skip, reason
+	}
+
+	prefix, ok := parsePrefix(value)
+	if !ok {
+		return a, false, fmt.Sprintf("%q is neither an address, an interface network nor an alias", value)
+	}
+
+	a.prefix = prefix.String()
+	if prefix.Addr().Is4() {
+		a.prefix = strings.TrimSuffix(a.prefix, "/32")
+	} else {
+		a.prefix = strings.TrimSuffix(a.prefix, "/128")
+	}
+
+	return a, prefixFamily(prefix) != fam, ""
+}
+
+// isInterfaceAddress reports whether a value names the address of an interface, such as wanip.
+func (t *translator) isInterfaceAddress(value string) bool {
+	name, ok := strings.CutSuffix(value, "ip")
+	if !ok {
+		return false
+	}
+
+	_, ok = t.doc.Interfaces.Items[name]
+
+	return ok
+}
+
+// interfaceAddress returns the address or, with network, the network of an interface in an
+// address family. Interfaces with a dynamic address cannot be translated.
+func (t *translator) interfaceAddress(name string, fam family, network bool) (string, bool, string) {
+	iface := t.doc.Interfaces.Items[name]
+
+	addr, bits := iface.IPAddr, iface.Subnet
+	if fam == familyIPv6 {
+		addr, bits = iface.IPAddrv6, iface.Subnetv6
+	}
+
+	if addr == "" {
+		return "", true, ""
+	}
+
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return "", false, fmt.Sprintf("interface %s has a dynamic address (%s)", name, addr)
+	}
+
+	if !network {
+		return ip.String(), false, ""
+	}
+
+	length, err := strconv.Atoi(bits)
+	if err != nil {
+		return "", false, fmt.Sprintf("interface %s has no subnet", name)
+	}
+
+	prefix, err := ip.Prefix(length)
+	if err != nil {
+		return "", false, fmt.Sprintf("interface %s has an invalid subnet %q", name, bits)
+	}
+
+	return prefix.String(), false, ""
+}
+
+// ports translates a port, a port range or a port alias.
+func (t *translator) ports(value string) (portMatch, string) {
+	if value == "" || value == "any" {
+		return portMatch{}, ""
+	}
+
+	if a := t.aliases[value]; a != nil {
+		if a.Type != aliasPort {
+			return portMatch{}, fmt.Sprintf("alias %s is used as a port but is a %s alias", value, a.Type)
+		}
+
+		if a.reason != "" {
+			return portMatch{}, fmt.Sprintf("alias %s is not translated: %s", value, a.reason)
+		}
+
+		return portMatch{set: value}, ""
+	}
+
+	spec, ok := portSpec(value)
+	if !ok {
+		return portMatch{}, fmt.Sprintf("port %q is neither a port, a port range nor an alias", value)
+	}
+
+	return portMatch{value: spec}, ""
+}
+
+// portValue returns the translated port of a NAT target, or an empty string if the port is not
+// valid.
+func portValue(value string) string {
+	spec, _ := portSpec(value)
+	return spec
+}
+
+// portSpec normalizes a port, a service name or a port range such as 8000:8080 to the 8000-8080
+// notation.
+func portSpec(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	low, high, isRange := strings.Cut(strings.ReplaceAll(value, ":", "-"), "-")
+	if !isRange {
+		if validPort(value) || serviceName.MatchString(value) {
+			return value, true
+		}
+
+		return "", false
+	}
+
+	if !validPort(low) || !validPort(high) {
+		return "", false
+	}
+
+	return low + "-" + high, true
+}
+
+// validPort reports whether a value is a port number.
+func validPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port >= 0 && port <= maxPort
+}
+
+// parseProtocols translates the protocol of a rule. tcp/udp matches both protocols.
+func parseProtocols(value string) ([]string, string) {
+	value = strings.ToLower(value)
+
+	switch {
+	case value == "" || value == "any":
+		return nil, ""
+	case value == "tcp/udp":
+		return []string{"tcp", "udp"}, ""
+	case slices.Contains(protocolNames, value):
+		return []string{value}, ""
+	case protocolNumbers[value] != "":
+		return []string{protocolNumbers[value]}, ""
+	}
+
+	if number, err := strconv.Atoi(value); err == nil && number >= 0 && number <= 255 {
+		return []string{value}, ""
+	}
+
+	return nil, fmt.Sprintf("protocol %q has no netfilter equivalent", value)
+}
+
+// familyProtocols returns the protocols of a rule for an address family, which differ for ICMP.
+func familyProtocols(protocols []string, fam family) []string {
+	var translated []string
+
+	for _, protocol := range protocols {
+		switch {
+		case protocol == "icmp" && fam == familyIPv6:
+			translated = append(translated, "ipv6-icmp")
+		case protocol == "ipv6-icmp" && fam == familyIPv4:
+			continue
+		default:
+			translated = append(translated, protocol)
+		}
+	}
+
+	return translated
+}
+
+// portProtocols reports whether the protocols of a rule all have ports.
+func portProtocols(protocols []string) bool {
+	if len(protocols) == 0 {
+		return false
+	}
+
+	for _, protocol := range protocols {
+		if protocol != "tcp" && protocol != "udp" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parsePrefix parses an address or a network in CIDR notation.
+func parsePrefix(value string) (netip.Prefix, bool) {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), true
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
+
+// prefixFamily returns the address family of a prefix.
+func prefixFamily(prefix netip.Prefix) family {
+	if prefix.Addr().Is4() {
+		return familyIPv4
+	}
+
+	return familyIPv6
+}
+
+// alias is a firewall alias with its entries resolved, including the entries of nested aliases.
+type alias struct {
+	model.FirewallAlias
+
+	v4, v6, ports []string
+	// reason reports why the alias cannot be translated.
+	reason   string
+	resolved bool
+}
+
+// loadAliases resolves the firewall aliases and adds the sets of the aliases that can be
+// translated. Aliases that cannot be translated are noted in the ruleset.
+func (t *translator) loadAliases() {
+	for _, a := range t.doc.FirewallAliases() {
+		t.aliases[a.Name] = &alias{FirewallAlias: a}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(t.aliases)) {
+		a := t.aliases[name]
+		t.resolveAlias(a, map[string]bool{})
+
+		if a.reason != "" {
+			object := fmt.Sprintf("alias %q", name)
+			t.rs.notes = append(t.rs.notes, fmt.Sprintf("not translated: %s (%s): %s", object, a.Type, a.reason))
+			t.warn(object, "not translated: %s", a.reason)
+
+			continue
+		}
+
+		if a.Type == aliasPort {
+			t.rs.sets = append(t.rs.sets, set{name: name, kind: setPort, description: a.Description, elements: a.ports})
+			continue
+		}
+
+		for _, fam := range []family{familyIPv4, familyIPv6} {
+			if setName, _, _ := t.aliasSet(name, fam); setName != "" {
+				kind := setIPv4
+				elements := a.v4
+
+				if fam == familyIPv6 {
+					kind = setIPv6
+					elements = a.v6
+				}
+
+				t.rs.sets = append(t.rs.sets, set{name: setName, kind: kind, description: a.Description, elements: elements})
+			}
+		}
+	}
+}
+
+// resolveAlias resolves the entries of an alias. visiting holds the aliases being resolved, to
+// detect aliases that contain themselves.
+func (t *translator) resolveAlias(a *alias, visiting map[string]bool) {
+	if a.resolved {
+		return
+	}
+
+	if visiting[a.Name] {
+		a.reason = "the alias contains itself"
+		return
+	}
+
+	visiting[a.Name] = true
+	defer func() { a.resolved = true }()
+
+	if a.Enabled == "0" {
+		a.reason = "the alias is disabled"
+		return
+	}
+
+	switch a.Type {
+	case aliasHost, aliasNetwork, aliasNetworkGroup, aliasPort:
+	default:
+		a.reason = fmt.Sprintf("%s aliases are resolved at runtime", a.Type)
+		return
+	}
+
+	for _, entry := range a.Entries() {
+		if nested := t.aliases[entry]; nested != nil {
+			t.resolveAlias(nested, visiting)
+
+			switch {
+			case nested.reason != "":
+				a.reason = fmt.Sprintf("it contains alias %s, which is not translated", entry)
+			case (nested.Type == aliasPort) != (a.Type == aliasPort):
+				a.reason = fmt.Sprintf("it contains alias %s of type %s", entry, nested.Type)
+			default:
+				a.v4 = append(a.v4, nested.v4...)
+				a.v6 = append(a.v6, nested.v6...)
+				a.ports = append(a.ports, nested.ports...)
+
+				continue
+			}
+
+			return
+		}
+
+		if a.Type == aliasPort {
+			spec, ok := portSpec(entry)
+			if !ok {
+				a.reason = fmt.Sprintf("entry %q is not a port", entry)
+				return
+			}
+
+			a.ports = append(a.ports, spec)
+
+			continue
+		}
+
+		fam, ok := entryFamily(entry)
+		if !ok {
+			a.reason = fmt.Sprintf("entry %q needs DNS resolution", entry)
+			return
+		}
+
+		if fam == familyIPv4 {
+			a.v4 = append(a.v4, entry)
+		} else {
+			a.v6 = append(a.v6, entry)
+		}
+	}
+}
+
+// aliasSet returns the set of an address alias for an address family. Aliases with entries of
+// both families have a set for each family with the family as suffix.
+func (t *translator) aliasSet(name string, fam family) (string, bool, string) {
+	a := t.aliases[name]
+
+	if a.reason != "" {
+		return "", false, fmt.Sprintf("alias %s is not translated: %s", name, a.reason)
+	}
+
+	if a.Type == aliasPort {
+		return "", false, fmt.Sprintf("port alias %s is used as an address", name)
+	}
+
+	entries := a.v4
+	suffix := "_v4"
+
+	if fam == familyIPv6 {
+		entries = a.v6
+		suffix = "_v6"
+	}
+
+	if len(entries) == 0 {
+		return "", true, ""
+	}
+
+	if len(a.v4) > 0 && len(a.v6) > 0 {
+		return name + suffix, false, ""
+	}
+
+	return name, false, ""
+}
+
+// entryFamily returns the address family of an alias entry, which is an address, a network or an
+// address range.
+func entryFamily(entry string) (family, bool) {
+	if low, high, ok := strings.Cut(entry, "-"); ok {
+		first, errLow := netip.ParseAddr(low)
+		last, errHigh := netip.ParseAddr(high)
+
+		if errLow != nil || errHigh != nil || first.Is4() != last.Is4() {
+			return familyAny, false
+		}
+
+		return prefixFamily(netip.PrefixFrom(first, first.BitLen())), true
+	}
+
+	prefix, ok := parsePrefix(entry)
+	if !ok {
+		return familyAny, false
+	}
+
+	return prefixFamily(prefix), true
+}
@@ -0,0 +1,259 @@
+package ruleset
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// iptablesCommentLimit is the maximum length of iptables rule comments.
+const iptablesCommentLimit = 256
+
+// ipsetTypes maps the kinds of sets onto the ipset types that hold them.
+var ipsetTypes = map[setKind]string{ //nolint:gochecknoglobals // Lookup table
+	setIPv4: "hash:net",
+	setIPv6: "hash:net family inet6",
+	setPort: "bitmap:port range 0-65535",
+}
+
+// renderIptables renders a ruleset as an iptables-restore file for IPv4. IPv6 rules are written as
+// comments. The sets of aliases are listed as ipset commands, since iptables-restore cannot create
+// them.
+func renderIptables(rs *ruleset) *Result {
+	var b strings.Builder
+
+	writeHeader(&b, rs)
+
+	r := &iptablesRenderer{rs: rs, b: &b, warned: make(map[string]bool)}
+
+	if len(rs.devices) > 0 {
+		b.WriteString("# The interface devices are the devices of the configuration; rename them to\n")
+		b.WriteString("# the Linux devices before loading the ruleset:\n")
+
+		for _, name := range slices.Sorted(maps.Keys(rs.devices)) {
+			b.WriteString("#   " + name + ": " + rs.devices[name] + "\n")
+		}
+	}
+
+	for _, note := range rs.notes {
+		b.WriteString("# " + note + "\n")
+	}
+
+	if len(rs.sets) > 0 {
+		b.WriteString("# Create the sets of the aliases before loading the ruleset:\n")
+
+		for _, s := range rs.sets {
+			b.WriteString("#   ipset create " + s.name + " " + ipsetTypes[s.kind] + "\n")
+
+			for _, element := range s.elements {
+				b.WriteString("#   ipset add " + s.name + " " + element + "\n")
+			}
+		}
+	}
+
+	r.table("filter", rs.filter)
+
+	if len(rs.nat) > 0 {
+		r.table("nat", rs.nat)
+	}
+
+	return &Result{Content: b.String(), Warnings: r.warnings}
+}
+
+// iptablesRenderer renders the tables of an iptables-restore file.
+type iptablesRenderer struct {
+	rs       *ruleset
+	b        *strings.Builder
+	warnings []Warning
+	// warned records the rules with a warning about IPv6, which are placed in several chains.
+	warned map[string]bool
+}
+
+// table writes a table with its chains.
+func (r *iptablesRenderer) table(name string, chains []chain) {
+	if r.warnings == nil {
+		r.warnings = slices.Clone(r.rs.warnings)
+	}
+
+	r.b.WriteString("*" + name + "\n")
+
+	for _, c := range chains {
+		policy := "-"
+		if c.hook != "" {
+			policy = strings.ToUpper(string(c.policy))
+		}
+
+		r.b.WriteString(":" + iptablesChain(c) + " " + policy + " [0:0]\n")
+	}
+
+	for _, c := range chains {
+		for _, rl := range c.rules {
+			for _, line := range r.rule(iptablesChain(c), rl) {
+				r.b.WriteString(line + "\n")
+			}
+		}
+	}
+
+	r.b.WriteString("COMMIT\n")
+}
+
+// rule returns the lines of a rule: its notes followed by a line for each protocol, preceded by a
+// LOG line for logged rules.
+func (r *iptablesRenderer) rule(chainName string, rl rule) []string {
+	if rl.skipped != "" {
+		return []string{"# not translated: " + rl.object + ": " + rl.skipped}
+	}
+
+	if rl.family == familyIPv6 {
+		if !r.warned[rl.object] {
+			r.warned[rl.object] = true
+			r.warnings = append(r.warnings, Warning{
+				Object:  rl.object,
+				Message: "not translated: IPv6 rules need an ip6tables-restore file",
+			})
+		}
+
+		return []string{"# not translated: " + rl.object + ": IPv6 rule"}
+	}
+
+	var lines []string
+	for _, note := range rl.notes {
+		lines = append(lines, "# note: "+note)
+	}
+
+	protocols := rl.protocols
+	if len(protocols) == 0 {
+		protocols = []string{""}
+	}
+
+	for _, protocol := range protocols {
+		matches := r.matches(rl, protocol)
+
+		if rl.log {
+			lines = append(lines, strings.Join(slices.Concat([]string{"-A", chainName}, matches,
+				[]string{"-j", "LOG", "--log-prefix", `"` + chainName + `: "`}), " "))
+		}
+
+		if comment := commentText(rl.comment, iptablesCommentLimit); comment != "" {
+			matches = append(matches, "-m", "comment", "--comment", `"`+comment+`"`)
+		}
+
+		lines = append(lines, strings.Join(slices.Concat([]string{"-A", chainName}, matches, iptablesTarget(rl)), " "))
+	}
+
+	return lines
+}
+
+// matches returns the match arguments of a rule for a protocol.
+func (r *iptablesRenderer) matches(rl rule, protocol string) []string {
+	var args []string
+
+	if rl.in != "" {
+		args = append(args, "-i", r.device(rl.in))
+	}
+
+	if rl.out != "" {
+		args = append(args, "-o", r.device(rl.out))
+	}
+
+	if rl.state != "" {
+		args = append(args, "-m", "conntrack", "--ctstate", strings.ToUpper(rl.state))
+	}
+
+	args = append(args, iptablesAddress("-s", "src", rl.source)...)
+	args = append(args, iptablesAddress("-d", "dst", rl.destination)...)
+
+	if protocol != "" {
+		args = append(args, "-p", protocol)
+	}
+
+	args = append(args, iptablesPort("--sport", "src", rl.sourcePort)...)
+	args = append(args, iptablesPort("--dport", "dst", rl.destinationPort)...)
+
+	return args
+}
+
+// device returns the device of an interface.
+func (r *iptablesRenderer) device(name string) string {
+	if name == loopback {
+		return loopback
+	}
+
+	return r.rs.devices[name]
+}
+
+// iptablesAddress returns the match arguments of an address.
+func iptablesAddress(flag, direction string, a address) []string {
+	var args []string
+
+	switch {
+	case a.local:
+		args = append(args, "-m", "addrtype")
+		if a.not {
+			args = append(args, "!")
+		}
+
+		return append(args, "--"+direction+"-type", "LOCAL")
+	case a.set != "":
+		args = append(args, "-m", "set")
+		if a.not {
+			args = append(args, "!")
+		}
+
+		return append(args, "--match-set", a.set, direction)
+	case a.prefix != "":
+		if a.not {
+			args = append(args, "!")
+		}
+
+		return append(args, flag, a.prefix)
+	default:
+		return nil
+	}
+}
+
+// iptablesPort returns the match arguments of a port.
+func iptablesPort(flag, direction string, p portMatch) []string {
+	var args []string
+
+	switch {
+	case p.set != "":
+		args = append(args, "-m", "set")
+		if p.not {
+			args = append(args, "!")
+		}
+
+		return append(args, "--match-set", p.set, direction)
+	case p.value != "":
+		if p.not {
+			args = append(args, "!")
+		}
+
+		return append(args, flag, strings.ReplaceAll(p.value, "-", ":"))
+	default:
+		return nil
+	}
+}
+
+// iptablesTarget returns the target arguments of a rule.
+func iptablesTarget(rl rule) []string {
+	switch rl.verdict {
+	case verdictJump:
+		return []string{"-j", rl.target}
+	case verdictDNAT:
+		return []string{"-j", "DNAT", "--to-destination", hostPort(rl.target, rl.targetPort)}
+	case verdictSNAT:
+		return []string{"-j", "SNAT", "--to-source", rl.target}
+	default:
+		return []string{"-j", strings.ToUpper(string(rl.verdict))}
+	}
+}
+
+// iptablesChain returns the name of a chain: the built-in chain of base chains.
+func iptablesChain(c chain) string {
+	if c.hook != "" {
+		return strings.ToUpper(c.hook)
+	}
+
+	return c.name
+}
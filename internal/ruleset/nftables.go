@@ -0,0 +1,277 @@
+package ruleset
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// nftTable is the name of the inet table of the generated nftables ruleset.
+const nftTable = "opndossier"
+
+// nftCommentLimit is the maximum length of nftables rule comments.
+const nftCommentLimit = 128
+
+// renderNftables renders a ruleset as an nftables script. The devices of the interfaces are
+// defined as variables at the top of the script, so they can be renamed to the Linux devices.
+func renderNftables(rs *ruleset) *Result {
+	var b strings.Builder
+
+	b.WriteString("#!/usr/sbin/nft -f\n")
+	writeHeader(&b, rs)
+	b.WriteString("# The interface devices are the devices of the configuration; change the\n")
+	b.WriteString("# definitions below to the Linux devices before loading the ruleset.\n")
+
+	if len(rs.devices) > 0 {
+		b.WriteString("\n")
+
+		for _, name := range slices.Sorted(maps.Keys(rs.devices)) {
+			b.WriteString("define " + nftVariable(name) + ` = "` + rs.devices[name] + `"` + "\n")
+		}
+	}
+
+	b.WriteString("\ntable inet " + nftTable + "\n")
+	b.WriteString("delete table inet " + nftTable + "\n")
+	b.WriteString("\ntable inet " + nftTable + " {\n")
+
+	for _, note := range rs.notes {
+		b.WriteString("\t# " + note + "\n")
+	}
+
+	if len(rs.notes) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, s := range rs.sets {
+		writeNftSet(&b, s)
+	}
+
+	for i, c := range slices.Concat(rs.filter, rs.nat) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		writeNftChain(&b, c)
+	}
+
+	b.WriteString("}\n")
+
+	return &Result{Content: b.String(), Warnings: rs.warnings}
+}
+
+// writeHeader writes the comments at the top of a generated ruleset.
+func writeHeader(b *strings.Builder, rs *ruleset) {
+	source := "an OPNsense configuration"
+	if rs.hostname != "" {
+		source = rs.hostname
+	}
+
+	b.WriteString("# Generated by opnDossier from " + source + ".\n")
+	b.WriteString("# Review the ruleset before loading it: rules marked \"not translated\" have no\n")
+	b.WriteString("# netfilter equivalent and are left out.\n")
+}
+
+// writeNftSet writes the named set of an alias.
+func writeNftSet(b *strings.Builder, s set) {
+	if s.description != "" {
+		b.WriteString("\t# " + commentText(s.description, nftCommentLimit) + "\n")
+	}
+
+	b.WriteString("\tset " + s.name + " {\n")
+	b.WriteString("\t\ttype " + string(s.kind) + "\n")
+	b.WriteString("\t\tflags interval\n")
+	b.WriteString("\t\tauto-merge\n")
+
+	if len(s.elements) > 0 {
+		b.WriteString("\t\telements = { " + strings.Join(s.elements, ", ") + " }\n")
+	}
+
+	b.WriteString("\t}\n\n")
+}
+
+// writeNftChain writes a chain with its rules.
+func writeNftChain(b *strings.Builder, c chain) {
+	b.WriteString("\tchain " + c.name + " {\n")
+
+	if c.hook != "" {
+		kind, priority := "filter", "filter"
+
+		if c.nat {
+			kind, priority = "nat", "srcnat"
+			if c.hook == hookPrerouting {
+				priority = "dstnat"
+			}
+		}
+
+		b.WriteString("\t\ttype " + kind + " hook " + c.hook + " priority " + priority +
+			"; policy " + string(c.policy) + ";\n")
+	}
+
+	for _, r := range c.rules {
+		for _, line := range nftRule(r) {
+			b.WriteString("\t\t" + line + "\n")
+		}
+	}
+
+	b.WriteString("\t}\n")
+}
+
+// nftRule returns the lines of a rule: its notes followed by the rule, or a comment for rules that
+// are not translated.
+func nftRule(r rule) []string {
+	if r.skipped != "" {
+		return []string{"# not translated: " + r.object + ": " + r.skipped}
+	}
+
+	var lines []string
+	for _, note := range r.notes {
+		lines = append(lines, "# note: "+note)
+	}
+
+	var parts []string
+
+	if r.in != "" {
+		parts = append(parts, "iifname "+nftInterface(r.in))
+	}
+
+	if r.out != "" {
+		parts = append(parts, "oifname "+nftInterface(r.out))
+	}
+
+	if r.state != "" {
+		parts = append(parts, "ct state "+r.state)
+	}
+
+	ip, nfproto := "ip", "ipv4"
+	if r.family == familyIPv6 {
+		ip, nfproto = "ip6", "ipv6"
+	}
+
+	// Address matches imply the family
+	usesFamily := r.source.prefix != "" || r.source.set != "" ||
+		r.destination.prefix != "" || r.destination.set != ""
+	if r.family != familyAny && !usesFamily {
+		parts = append(parts, "meta nfproto "+nfproto)
+	}
+
+	parts = appendNonEmpty(parts, nftAddress(ip, "saddr", r.source), nftAddress(ip, "daddr", r.destination))
+
+	// Port matches imply the protocol; several protocols are matched with the transport header
+	header := ""
+	if !r.sourcePort.isAny() || !r.destinationPort.isAny() {
+		header = "th"
+		if len(r.protocols) == 1 {
+			header = r.protocols[0]
+		}
+	}
+
+	switch {
+	case len(r.protocols) > 1:
+		parts = append(parts, "meta l4proto { "+strings.Join(r.protocols, ", ")+" }")
+	case len(r.protocols) == 1 && header == "":
+		parts = append(parts, "meta l4proto "+r.protocols[0])
+	}
+
+	parts = appendNonEmpty(parts, nftPort(header, "sport", r.sourcePort), nftPort(header, "dport", r.destinationPort))
+
+	if r.log {
+		parts = append(parts, "log")
+	}
+
+	parts = append(parts, nftVerdict(r, ip))
+
+	if comment := commentText(r.comment, nftCommentLimit); comment != "" {
+		parts = append(parts, `comment "`+comment+`"`)
+	}
+
+	return append(lines, strings.Join(parts, " "))
+}
+
+// nftAddress returns the match of an address, or an empty string for any address.
+func nftAddress(ip, field string, a address) string {
+	operator := " "
+	if a.not {
+		operator = " != "
+	}
+
+	switch {
+	case a.local:
+		return "fib " + field + " type" + operator + "local"
+	case a.set != "":
+		return ip + " " + field + operator + "@" + a.set
+	case a.prefix != "":
+		return ip + " " + field + operator + a.prefix
+	default:
+		return ""
+	}
+}
+
+// nftPort returns the match of a port, or an empty string for any port.
+func nftPort(header, field string, p portMatch) string {
+	operator := " "
+	if p.not {
+		operator = " != "
+	}
+
+	switch {
+	case p.set != "":
+		return header + " " + field + operator + "@" + p.set
+	case p.value != "":
+		return header + " " + field + operator + p.value
+	default:
+		return ""
+	}
+}
+
+// nftVerdict returns the statement that ends a rule.
+func nftVerdict(r rule, ip string) string {
+	switch r.verdict {
+	case verdictJump:
+		return "jump " + r.target
+	case verdictDNAT:
+		return "dnat " + ip + " to " + hostPort(r.target, r.targetPort)
+	case verdictSNAT:
+		return "snat " + ip + " to " + r.target
+	default:
+		return string(r.verdict)
+	}
+}
+
+// nftInterface returns the device of an interface: the loopback device or the variable that
+// defines the device.
+func nftInterface(name string) string {
+	if name == loopback {
+		return `"` + loopback + `"`
+	}
+
+	return "$" + nftVariable(name)
+}
+
+// nftVariable returns the name of the variable that defines the device of an interface.
+func nftVariable(name string) string {
+	return "if_" + name
+}
+
+// hostPort returns an address with an optional port, with IPv6 addresses in brackets.
+func hostPort(host, port string) string {
+	if port == "" {
+		return host
+	}
+
+	if strings.Contains(host, ":") {
+		return "[" + host + "]:" + port
+	}
+
+	return host + ":" + port
+}
+
+// appendNonEmpty appends the non-empty values.
+func appendNonEmpty(parts []string, values ...string) []string {
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,218 @@
+// Package ruleset translates the firewall filter and NAT rules of a configuration into a Linux
+// nftables ruleset or an iptables-restore file.
+//
+// The pf semantics of OPNsense are mapped onto netfilter chains. The inbound rules of each
+// interface are collected in a chain that the input and forward chains jump to by input interface,
+// so the first matching rule decides as with quick rules. The outbound rules of each interface are
+// collected in a chain that the output and forward chains jump to by output interface; passing
+// packets return to the calling chain, so that forwarded packets are still checked by the inbound
+// rules. Quick floating rules are placed before the interface rules and non-quick floating rules
+// after them in reverse order, which gives the last matching non-quick rule precedence as in pf.
+// Established connections are accepted before any rule, like the state table of pf.
+//
+// Aliases become named sets (ipsets for iptables), port forwards become DNAT rules and outbound NAT
+// rules become SNAT or masquerade rules. Constructs without an equivalent, such as URL and GeoIP
+// aliases or dynamic interface addresses, are written as comments and reported as warnings.
+package ruleset
+
+import (
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Warning describes a construct of the configuration that is not translated faithfully.
+type Warning struct {
+	// Object identifies the rule, alias or interface, such as `filter rule 3 on lan "Allow DNS"`.
+	Object  string
+	Message string
+}
+
+// String returns the warning as a single line.
+func (w Warning) String() string {
+	return w.Object + ": " + w.Message
+}
+
+// Result is a generated ruleset with the warnings of its translation.
+type Result struct {
+	Content  string
+	Warnings []Warning
+}
+
+// Nftables translates the filter and NAT rules of a configuration into an nftables ruleset that
+// replaces the inet opndossier table when loaded with nft -f.
+func Nftables(doc *model.OpnSenseDocument) (*Result, error) {
+	rs, err := build(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderNftables(rs), nil
+}
+
+// Iptables translates the filter and NAT rules of a configuration into an iptables-restore file.
+// The file covers IPv4; IPv6 rules are written as comments and reported as warnings.
+func Iptables(doc *model.OpnSenseDocument) (*Result, error) {
+	rs, err := build(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderIptables(rs), nil
+}
+
+// family is the address family of a translated rule.
+type family int
+
+const (
+	familyAny family = iota
+	familyIPv4
+	familyIPv6
+)
+
+// verdict is the statement that ends a translated rule.
+type verdict string
+
+const (
+	verdictAccept     verdict = "accept"
+	verdictDrop       verdict = "drop"
+	verdictReject     verdict = "reject"
+	verdictReturn     verdict = "return"
+	verdictJump       verdict = "jump"
+	verdictDNAT       verdict = "dnat"
+	verdictSNAT       verdict = "snat"
+	verdictMasquerade verdict = "masquerade"
+)
+
+// Hooks of the base chains.
+const (
+	hookInput       = "input"
+	hookForward     = "forward"
+	hookOutput      = "output"
+	hookPrerouting  = "prerouting"
+	hookPostrouting = "postrouting"
+)
+
+// loopback is the interface name of the loopback device, which is not an assigned interface.
+const loopback = "lo"
+
+// address matches the source or destination address of packets. The zero value matches any
+// address.
+type address struct {
+	not bool
+	// prefix is an address or a network in CIDR notation.
+	prefix string
+	// set is the name of the set of an alias.
+	set string
+	// local matches the addresses of the firewall itself.
+	local bool
+}
+
+// isAny reports whether the address matches any address.
+func (a address) isAny() bool {
+	return a.prefix == "" && a.set == "" && !a.local
+}
+
+// portMatch matches the source or destination port of packets. The zero value matches any port.
+type portMatch struct {
+	not bool
+	// value is a port, a service name or a range such as 8000-8080.
+	value string
+	// set is the name of the set of a port alias.
+	set string
+}
+
+// isAny reports whether the match matches any port.
+func (p portMatch) isAny() bool {
+	return p.value == "" && p.set == ""
+}
+
+// rule is a translated rule. Rules that could not be translated carry the reason in skipped and
+// are rendered as comments.
+type rule struct {
+	family family
+	// in and out are the names of the input and output interfaces.
+	in, out         string
+	state           string
+	protocols       []string
+	source          address
+	destination     address
+	sourcePort      portMatch
+	destinationPort portMatch
+	log             bool
+	verdict         verdict
+	// target is the chain of jumps or the translated address of NAT rules.
+	target     string
+	targetPort string
+	comment    string
+	// object identifies the rule of the configuration that the rule was translated from.
+	object string
+	// notes are rendered as comments above the rule.
+	notes   []string
+	skipped string
+}
+
+// chain is a chain of translated rules. Base chains are attached to a hook.
+type chain struct {
+	name   string
+	nat    bool
+	hook   string
+	policy verdict
+	rules  []rule
+}
+
+// setKind is the nftables data type of the elements of a set.
+type setKind string
+
+const (
+	setIPv4 setKind = "ipv4_addr"
+	setIPv6 setKind = "ipv6_addr"
+	setPort setKind = "inet_service"
+)
+
+// set is the named set of an alias.
+type set struct {
+	name        string
+	kind        setKind
+	description string
+	elements    []string
+}
+
+// ruleset is a translated configuration.
+type ruleset struct {
+	hostname string
+	// devices maps the interfaces that rules refer to onto their devices.
+	devices map[string]string
+	sets    []set
+	// notes are rendered as comments before the sets, such as the aliases that are not translated.
+	notes    []string
+	filter   []chain
+	nat      []chain
+	warnings []Warning
+}
+
+// build translates the filter and NAT rules of a configuration.
+func build(doc *model.OpnSenseDocument) (*ruleset, error) {
+	if doc == nil {
+		return nil, converter.ErrNilOpnSenseDocument
+	}
+
+	t := newTranslator(doc)
+	t.loadAliases()
+	t.filter()
+	t.nat()
+
+	return t.rs, nil
+}
+
+// commentText returns text that is safe to embed in a quoted comment of nft and iptables-restore.
+func commentText(text string, limit int) string {
+	text = strings.Join(strings.Fields(strings.ReplaceAll(text, `"`, "'")), " ")
+
+	if runes := []rune(text); len(runes) > limit {
+		text = string(runes[:limit])
+	}
+
+	return text
+}
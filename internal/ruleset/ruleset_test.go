@@ -0,0 +1,163 @@
+package ruleset
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the generated rulesets.
+var update = flag.Bool("update", false, "update the golden files") //nolint:gochecknoglobals // Test flag
+
+// load parses a configuration.
+func load(t *testing.T, path string) *model.OpnSenseDocument {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	doc, err := parser.NewXMLParser().Parse(t.Context(), file)
+	require.NoError(t, err)
+
+	return doc
+}
+
+// assertGolden compares generated content with a golden file in the testdata directory.
+func assertGolden(t *testing.T, name, content string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test with -update to create the golden file")
+	assert.Equal(t, string(want), content)
+}
+
+func TestGolden(t *testing.T) {
+	configs := map[string]string{
+		"rules":    filepath.Join("testdata", "rules.xml"),
+		"sample.2": filepath.Join("..", "..", "testdata", "sample.config.2.xml"),
+		"sample.7": filepath.Join("..", "..", "testdata", "sample.config.7.xml"),
+	}
+
+	for name, path := range configs {
+		t.Run(name, func(t *testing.T) {
+			doc := load(t, path)
+
+			nft, err := Nftables(doc)
+			require.NoError(t, err)
+			assertGolden(t, name+".nft", nft.Content)
+
+			iptables, err := Iptables(doc)
+			require.NoError(t, err)
+			assertGolden(t, name+".iptables", iptables.Content)
+		})
+	}
+}
+
+func TestNftables_Warnings(t *testing.T) {
+	result, err := Nftables(load(t, filepath.Join("testdata", "rules.xml")))
+	require.NoError(t, err)
+
+	warnings := make([]string, 0, len(result.Warnings))
+	for _, warning := range result.Warnings {
+		warnings = append(warnings, warning.String())
+	}
+
+	assert.Contains(t, warnings, `alias "blocklist": not translated: urltable aliases are resolved at runtime`)
+	assert.Contains(t, warnings,
+		`filter rule 10 on opt1 "DMZ web access": not translated: interface opt1 has a dynamic address (dhcp)`)
+	assert.Contains(t, warnings,
+		`port forward 1 on wan "HTTPS to the web server": NAT reflection is not translated`)
+
+	// Every untranslated rule is annotated in the ruleset
+	for _, warning := range result.Warnings {
+		if strings.HasPrefix(warning.Message, "not translated: ") {
+			assert.Contains(t, result.Content, "# not translated: "+warning.Object)
+		}
+	}
+}
+
+func TestIptables_IPv6Warnings(t *testing.T) {
+	result, err := Iptables(load(t, filepath.Join("testdata", "rules.xml")))
+	require.NoError(t, err)
+
+	var ipv6 []string
+
+	for _, warning := range result.Warnings {
+		if strings.Contains(warning.Message, "ip6tables") {
+			ipv6 = append(ipv6, warning.Object)
+		}
+	}
+
+	// Rules in several chains are reported once
+	assert.ElementsMatch(t, []string{
+		`filter rule 3 "Default allow ICMP to the firewall"`,
+		`filter rule 5 on wan "Allow web traffic"`,
+		`filter rule 8 on lan "Default allow LAN to any rule"`,
+	}, ipv6)
+}
+
+func TestFloatingRuleOrder(t *testing.T) {
+	doc := &model.OpnSenseDocument{}
+	doc.Interfaces.Items = map[string]model.Interface{"lan": {If: "em1"}}
+	doc.Filter.Rule = []model.Rule{
+		{Type: "pass", Floating: "yes", Descr: "first non-quick"},
+		{Type: "block", Floating: "yes", Descr: "second non-quick"},
+		{Type: "pass", Interface: model.InterfaceList{"lan"}, Descr: "interface"},
+		{Type: "block", Floating: "yes", Quick: "1", Descr: "quick"},
+	}
+
+	rs, err := build(doc)
+	require.NoError(t, err)
+
+	var lan chain
+	for _, c := range rs.filter {
+		if c.name == "lan_in" {
+			lan = c
+		}
+	}
+
+	comments := make([]string, 0, len(lan.rules))
+	for _, r := range lan.rules {
+		comments = append(comments, r.comment)
+	}
+
+	// Quick floating rules come first and the last matching non-quick rule wins
+	assert.Equal(t, []string{"quick", "interface", "second non-quick", "first non-quick"}, comments)
+}
+
+func TestNftables_Nil(t *testing.T) {
+	_, err := Nftables(nil)
+	require.ErrorIs(t, err, converter.ErrNilOpnSenseDocument)
+
+	_, err = Iptables(nil)
+	require.ErrorIs(t, err, converter.ErrNilOpnSenseDocument)
+}
+
+func TestPortSpec(t *testing.T) {
+	for value, want := range map[string]string{"443": "443", "8000:8080": "8000-8080", "1-2": "1-2", "HTTPS": "https"} {
+		got, ok := portSpec(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, want, got)
+	}
+
+	for _, value := range []string{"70000", "1-x", "a b"} {
+		_, ok := portSpec(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,540 @@
+package ruleset
+
+import (
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Directions of filter rules.
+const (
+	directionIn  = "in"
+	directionOut = "out"
+	directionAny = "any"
+)
+
+// filterVerdicts maps the types of filter rules onto the verdicts of inbound rules.
+var filterVerdicts = map[string]verdict{ //nolint:gochecknoglobals // Lookup table
+	"pass":   verdictAccept,
+	"block":  verdictDrop,
+	"reject": verdictReject,
+}
+
+// translator translates a configuration into a ruleset.
+type translator struct {
+	doc     *model.OpnSenseDocument
+	rs      *ruleset
+	aliases map[string]*alias
+	// interfaces are the assigned interfaces without the loopback, sorted by name. Floating rules
+	// without interfaces apply to all of them.
+	interfaces []string
+}
+
+// newTranslator returns a translator for a configuration.
+func newTranslator(doc *model.OpnSenseDocument) *translator {
+	t := &translator{
+		doc:     doc,
+		rs:      &ruleset{devices: make(map[string]string)},
+		aliases: make(map[string]*alias),
+	}
+
+	t.rs.hostname = strings.Trim(doc.System.Hostname+"."+doc.System.Domain, ".")
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Interfaces.Items)) {
+		if doc.Interfaces.Items[name].If != "lo0" {
+			t.interfaces = append(t.interfaces, name)
+		}
+	}
+
+	return t
+}
+
+// warn records a warning.
+func (t *translator) warn(object, format string, args ...any) {
+	t.rs.warnings = append(t.rs.warnings, Warning{Object: object, Message: fmt.Sprintf(format, args...)})
+}
+
+// skip returns a rule that is rendered as a comment and records a warning.
+func (t *translator) skip(object, reason string) rule {
+	t.warn(object, "not translated: %s", reason)
+	return rule{object: object, skipped: reason}
+}
+
+// useInterface records the device of an interface that a rule refers to. Interfaces that are not
+// assigned keep their name as device name.
+func (t *translator) useInterface(name string) {
+	if _, ok := t.rs.devices[name]; ok {
+		return
+	}
+
+	iface, ok := t.doc.Interfaces.Items[name]
+	if !ok || iface.If == "" {
+		t.rs.devices[name] = name
+		t.warn(fmt.Sprintf("interface %q", name), "no device is assigned; the interface name is used as device")
+
+		return
+	}
+
+	t.rs.devices[name] = iface.If
+}
+
+// ruleBucket collects the rules of an interface chain in evaluation order.
+type ruleBucket struct {
+	iface, direction string
+	// quick are the quick floating rules, which are evaluated first.
+	quick []rule
+	rules []rule
+	// last are the non-quick floating rules in reverse order, so the last match takes precedence.
+	last []rule
+}
+
+// filter translates the filter rules into the filter chains.
+func (t *translator) filter() {
+	buckets := make(map[string]*ruleBucket)
+
+	for i, r := range t.doc.FilterRules() {
+		if r.Disabled != "" {
+			continue
+		}
+
+		object := ruleObject("filter rule", i, r.Interface, r.Descr)
+		translated := t.filterRule(r, object)
+
+		floating := r.Floating == "yes" || r.Interface.IsEmpty()
+		quick := !floating || isTrue(r.Quick)
+
+		interfaces := []string(r.Interface)
+		if r.Interface.IsEmpty() {
+			interfaces = t.interfaces
+		}
+
+		for _, direction := range directions(r.Direction) {
+			for _, name := range interfaces {
+				key := name + "_" + direction
+
+				bucket, ok := buckets[key]
+				if !ok {
+					bucket = &ruleBucket{iface: name, direction: direction}
+					buckets[key] = bucket
+				}
+
+				rules := slices.Clone(translated)
+				if direction == directionOut {
+					for j := range rules {
+						// Passing outbound packets still have to pass the inbound rules of the
+						// forward chain
+						if rules[j].verdict == verdictAccept {
+							rules[j].verdict = verdictReturn
+						}
+					}
+				}
+
+				switch {
+				case !quick:
+					bucket.last = append(rules, bucket.last...)
+				case floating:
+					bucket.quick = append(bucket.quick, rules...)
+				default:
+					bucket.rules = append(bucket.rules, rules...)
+				}
+			}
+		}
+	}
+
+	input := chain{name: hookInput, hook: hookInput, policy: verdictDrop, rules: []rule{
+		{state: "established,related", verdict: verdictAccept},
+		{state: "invalid", verdict: verdictDrop},
+		{in: loopback, verdict: verdictAccept},
+	}}
+	forward := chain{name: hookForward, hook: hookForward, policy: verdictDrop, rules: []rule{
+		{state: "established,related", verdict: verdictAccept},
+		{state: "invalid", verdict: verdictDrop},
+	}}
+	output := chain{name: hookOutput, hook: hookOutput, policy: verdictAccept, rules: []rule{
+		{state: "established,related", verdict: verdictAccept},
+	}}
+
+	var chains []chain
+
+	// Outbound jumps come first in the forward chain, since passing outbound rules return
+	for _, direction := range []string{directionOut, directionIn} {
+		for _, key := range slices.Sorted(maps.Keys(buckets)) {
+			bucket := buckets[key]
+			if bucket.direction != direction {
+				continue
+			}
+
+			t.useInterface(bucket.iface)
+
+			rules := slices.Concat(bucket.quick, bucket.rules, bucket.last)
+			chains = append(chains, chain{name: key, rules: rules})
+
+			if direction == directionOut {
+				jump := rule{out: bucket.iface, verdict: verdictJump, target: key}
+				forward.rules = append(forward.rules, jump)
+				output.rules = append(output.rules, jump)
+
+				continue
+			}
+
+			jump := rule{in: bucket.iface, verdict: verdictJump, target: key}
+			input.rules = append(input.rules, jump)
+			forward.rules = append(forward.rules, jump)
+		}
+	}
+
+	t.rs.filter = append([]chain{input, forward, output}, chains...)
+}
+
+// filterRule translates a filter rule without its interface.
+func (t *translator) filterRule(r model.Rule, object string) []rule {
+	verdict, ok := filterVerdicts[r.Type]
+	if !ok {
+		return []rule{t.skip(object, fmt.Sprintf("rule type %q has no equivalent", r.Type))}
+	}
+
+	base := rule{verdict: verdict, log: bool(r.Log), comment: r.Descr, object: object}
+
+	if r.StateType != "" && r.StateType != "keep state" {
+		note := fmt.Sprintf("state type %q is not translated; connection tracking applies", r.StateType)
+		base.notes = append(base.notes, note)
+		t.warn(object, "%s", note)
+	}
+
+	return t.expand(base, r.IPProtocol, r.Protocol,
+		sourceEndpoint(r.Source), destinationEndpoint(r.Destination),
+		firstNonEmpty(r.Source.Port, r.SourcePort), r.Destination.Port, object)
+}
+
+// nat translates the port forwards and the outbound NAT rules into the NAT chains.
+func (t *translator) nat() {
+	prerouting := chain{name: hookPrerouting, nat: true, hook: hookPrerouting, policy: verdictAccept}
+	postrouting := chain{name: hookPostrouting, nat: true, hook: hookPostrouting, policy: verdictAccept}
+
+	for i, r := range t.doc.Nat.Inbound {
+		if r.Disabled != "" {
+			continue
+		}
+
+		prerouting.rules = append(prerouting.rules, t.portForward(i, r)...)
+	}
+
+	mode := t.doc.Nat.Outbound.Mode
+
+	if mode == "advanced" || mode == "hybrid" {
+		for i, r := range t.doc.Nat.Outbound.Rule {
+			if r.Disabled != "" {
+				continue
+			}
+
+			postrouting.rules = append(postrouting.rules, t.outboundRule(i, r)...)
+		}
+	}
+
+	if mode == "" || mode == "automatic" || mode == "hybrid" {
+		postrouting.rules = append(postrouting.rules, t.automaticOutbound()...)
+	}
+
+	if len(prerouting.rules)+len(postrouting.rules) > 0 {
+		t.rs.nat = []chain{prerouting, postrouting}
+	}
+}
+
+// portForward translates a port forward into DNAT rules on each of its interfaces.
+func (t *translator) portForward(i int, r model.InboundRule) []rule {
+	object := ruleObject("port forward", i, r.Interface, r.Descr)
+
+	if r.Interface.IsEmpty() {
+		return []rule{t.skip(object, "the port forward has no interface")}
+	}
+
+	target, err := netip.ParseAddr(r.InternalIP)
+	if err != nil {
+		return []rule{t.skip(object, fmt.Sprintf("target %q is not an address", r.InternalIP))}
+	}
+
+	ipProtocol, reason := targetProtocol(r.IPProtocol, target)
+	if reason != "" {
+		return []rule{t.skip(object, reason)}
+	}
+
+	port := firstNonEmpty(r.Destination.Port, r.ExternalPort)
+	if r.InternalPort != "" && r.InternalPort != port && strings.ContainsAny(port, "-:") {
+		return []rule{t.skip(object, "pf shifts port ranges onto the target ports, which DNAT does not")}
+	}
+
+	base := rule{
+		verdict:    verdictDNAT,
+		target:     target.String(),
+		targetPort: portValue(r.InternalPort),
+		comment:    r.Descr,
+		object:     object,
+	}
+
+	if r.Reflection == "enable" {
+		note := "NAT reflection is not translated"
+		base.notes = append(base.notes, note)
+		t.warn(object, "%s", note)
+	}
+
+	translated := t.expand(base, ipProtocol, r.Protocol,
+		sourceEndpoint(r.Source), destinationEndpoint(r.Destination), r.Source.Port, port, object)
+
+	return t.onInterfaces(translated, r.Interface, true)
+}
+
+// outboundRule translates an outbound NAT rule into SNAT or masquerade rules on each of its
+// interfaces.
+func (t *translator) outboundRule(i int, r model.NATRule) []rule {
+	object := ruleObject("outbound NAT rule", i, r.Interface, r.Descr)
+
+	if r.Interface.IsEmpty() {
+		return []rule{t.skip(object, "the outbound NAT rule has no interface")}
+	}
+
+	base := rule{verdict: verdictMasquerade, comment: r.Descr, object: object}
+	ipProtocol := r.IPProtocol
+
+	if target, err := netip.ParseAddr(r.Target); err == nil {
+		var reason string
+
+		ipProtocol, reason = targetProtocol(r.IPProtocol, target)
+		if reason != "" {
+			return []rule{t.skip(object, reason)}
+		}
+
+		base.verdict = verdictSNAT
+		base.target = target.String()
+	} else if r.Target != "" && !t.isInterfaceAddress(r.Target) {
+		return []rule{t.skip(object, fmt.Sprintf("translation target %q is not an address", r.Target))}
+	}
+
+	translated := t.expand(base, ipProtocol, r.Protocol,
+		sourceEndpoint(r.Source), destinationEndpoint(r.Destination),
+		firstNonEmpty(r.SourcePort, r.Source.Port), r.Destination.Port, object)
+
+	return t.onInterfaces(translated, r.Interface, false)
+}
+
+// automaticOutbound returns the masquerade rules of automatic outbound NAT, which translates the
+// networks of the interfaces without a gateway on the interfaces with a gateway.
+func (t *translator) automaticOutbound() []rule {
+	var rules []rule
+
+	for _, wan := range t.interfaces {
+		if t.doc.Interfaces.Items[wan].Gateway == "" {
+			continue
+		}
+
+		for _, lan := range t.interfaces {
+			if t.doc.Interfaces.Items[lan].Gateway != "" {
+				continue
+			}
+
+			network, skip, reason := t.interfaceAddress(lan, familyIPv4, true)
+			if skip || reason != "" {
+				continue
+			}
+
+			t.useInterface(wan)
+
+			rules = append(rules, rule{
+				family:  familyIPv4,
+				out:     wan,
+				source:  address{prefix: network},
+				verdict: verdictMasquerade,
+				comment: "automatic outbound NAT for " + lan,
+			})
+		}
+	}
+
+	return rules
+}
+
+// onInterfaces returns the translated rules for each interface, as input or output interface.
+func (t *translator) onInterfaces(translated []rule, interfaces model.InterfaceList, input bool) []rule {
+	var rules []rule
+
+	for _, name := range interfaces {
+		for _, r := range translated {
+			if r.skipped == "" {
+				t.useInterface(name)
+
+				if input {
+					r.in = name
+				} else {
+					r.out = name
+				}
+			}
+
+			rules = append(rules, r)
+		}
+
+		// Rules that are not translated are commented once
+		if len(translated) > 0 && translated[0].skipped != "" {
+			break
+		}
+	}
+
+	return rules
+}
+
+// expand translates the protocol, addresses and ports of a rule for the address families of the
+// rule. A rule that cannot be translated for one of its families is not translated at all, so that
+// the ruleset does not silently match less than the configuration.
+func (t *translator) expand(
+	base rule,
+	ipProtocol, protocol string,
+	source, destination endpoint,
+	sourcePort, destinationPort string,
+	object string,
+) []rule {
+	protocols, reason := parseProtocols(protocol)
+	if reason != "" {
+		return []rule{t.skip(object, reason)}
+	}
+
+	sport, reason := t.ports(sourcePort)
+	if reason != "" {
+		return []rule{t.skip(object, reason)}
+	}
+
+	dport, reason := t.ports(destinationPort)
+	if reason != "" {
+		return []rule{t.skip(object, reason)}
+	}
+
+	if (!sport.isAny() || !dport.isAny()) && !portProtocols(protocols) {
+		return []rule{t.skip(object, "ports require the TCP or UDP protocol")}
+	}
+
+	families := ruleFamilies(ipProtocol, protocols, source, destination)
+
+	var rules []rule
+
+	for _, fam := range families {
+		r := base
+		r.family = fam
+		r.protocols = familyProtocols(protocols, fam)
+		r.sourcePort = sport
+		r.destinationPort = dport
+
+		if len(protocols) > 0 && len(r.protocols) == 0 {
+			continue
+		}
+
+		var skipSource, skipDestination bool
+
+		r.source, skipSource, reason = t.address(source, fam)
+		if reason != "" {
+			return []rule{t.skip(object, reason)}
+		}
+
+		r.destination, skipDestination, reason = t.address(destination, fam)
+		if reason != "" {
+			return []rule{t.skip(object, reason)}
+		}
+
+		if skipSource || skipDestination {
+			continue
+		}
+
+		// Notes are shown once, above the first translation
+		if len(rules) > 0 {
+			r.notes = nil
+		}
+
+		rules = append(rules, r)
+	}
+
+	if len(rules) == 0 {
+		return []rule{t.skip(object, "the source or destination has no address in the address family of the rule")}
+	}
+
+	return rules
+}
+
+// ruleFamilies returns the address families to translate a rule for. Rules for both families
+// are translated once for each family when they match addresses or ICMP.
+func ruleFamilies(ipProtocol string, protocols []string, source, destination endpoint) []family {
+	switch ipProtocol {
+	case "inet6":
+		return []family{familyIPv6}
+	case "inet46":
+		if source.needsFamily() || destination.needsFamily() || slices.Contains(protocols, "icmp") {
+			return []family{familyIPv4, familyIPv6}
+		}
+
+		return []family{familyAny}
+	default:
+		return []family{familyIPv4}
+	}
+}
+
+// targetProtocol returns the IP protocol of a NAT rule with a translation address, which must
+// match the family of the address.
+func targetProtocol(ipProtocol string, target netip.Addr) (string, string) {
+	want := "inet"
+	if target.Is6() {
+		want = "inet6"
+	}
+
+	if ipProtocol != "" && ipProtocol != "inet46" && ipProtocol != want {
+		return "", fmt.Sprintf("translation address %s does not match the IP protocol %s", target, ipProtocol)
+	}
+
+	return want, ""
+}
+
+// directions returns the directions that a filter rule applies to.
+func directions(direction string) []string {
+	switch direction {
+	case directionOut:
+		return []string{directionOut}
+	case directionAny:
+		return []string{directionIn, directionOut}
+	default:
+		return []string{directionIn}
+	}
+}
+
+// ruleObject identifies a rule by its position, interfaces and description.
+func ruleObject(kind string, i int, interfaces model.InterfaceList, descr string) string {
+	object := kind + " " + strconv.Itoa(i+1)
+
+	if !interfaces.IsEmpty() {
+		object += " on " + interfaces.String()
+	}
+
+	if descr != "" {
+		object += " " + strconv.Quote(descr)
+	}
+
+	return object
+}
+
+// isTrue reports whether a flag of the configuration is set.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "yes", "on", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,518 @@
+package sanitize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Kinds of pseudonymised values.
+const (
+	KindIP       = "ip"
+	KindHostname = "hostname"
+	KindDomain   = "domain"
+	KindUsername = "username"
+)
+
+// keyLength is the length of generated anonymization keys in bytes.
+const keyLength = 32
+
+// Characters that continue the tokens around address matches.
+const (
+	ipv4Token = "0123456789."
+	ipv6Token = "0123456789abcdefABCDEF:"
+)
+
+// maxAddressAttempts bounds the attempts to map a public address to a public pseudonym.
+const maxAddressAttempts = 16
+
+var (
+	// ipv4Pattern matches IPv4 addresses with an optional prefix length.
+	ipv4Pattern = regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}(?:/\d{1,2})?`)
+	// ipv6Pattern matches IPv6 address candidates with an optional prefix length.
+	ipv6Pattern = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}(?:/\d{1,3})?`)
+
+	// keptUsernames are default account names. They identify no one and the checks for default
+	// and root accounts rely on them.
+	keptUsernames = []string{"root", "admin", "user"} //nolint:gochecknoglobals // lookup table
+	// keptDomains are default local domains that identify no one.
+	keptDomains = []string{"localdomain", "local", "lan", "home.arpa", "internal"} //nolint:gochecknoglobals // lookup table
+	// keptHostnames are default hostnames that identify no one.
+	keptHostnames = []string{"opnsense", "localhost"} //nolint:gochecknoglobals // lookup table
+
+	// nonPublicPrefixes are global unicast ranges that are not publicly routed.
+	nonPublicPrefixes = []netip.Prefix{ //nolint:gochecknoglobals // lookup table
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+	}
+
+	// globalUnicastV6 is the IPv6 address space allocated for global unicast addresses.
+	globalUnicastV6 = netip.MustParsePrefix("2000::/3") //nolint:gochecknoglobals // constant prefix
+
+	xmlNameType = reflect.TypeFor[xml.Name]() //nolint:gochecknoglobals // constant type
+)
+
+// Anonymizer deterministically replaces public IP addresses, hostnames, domains and usernames with
+// pseudonyms derived from a key. The same key always gives the same pseudonyms, so reports of
+// several configurations anonymized in one run, or in several runs with the same key, stay
+// comparable. Private addresses are kept.
+//
+// Addresses are mapped prefix-preservingly: two addresses that share a prefix have pseudonyms that
+// share a prefix of the same length, so subnets stay subnets. Only the first octet of IPv4 addresses
+// and the first 16 bits of IPv6 addresses are kept, which keeps pseudonyms in public address space;
+// all bits below them, including the host part and the IPv6 interface identifier, are mapped.
+//
+// An Anonymizer is safe for concurrent use.
+type Anonymizer struct {
+	key []byte
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// NewAnonymizer returns an Anonymizer with the given key. Without a key, a random key is generated,
+// so that pseudonyms are only consistent within the run.
+func NewAnonymizer(key []byte) (*Anonymizer, error) {
+	if len(key) == 0 {
+		key = make([]byte, keyLength)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate anonymization key: %w", err)
+		}
+	}
+
+	return &Anonymizer{key: slices.Clone(key), entries: map[string]Entry{}}, nil
+}
+
+// Document anonymizes a configuration in place. The hostnames, domains and usernames of the
+// configuration are replaced wherever they occur, including descriptions, as are all public
+// addresses.
+func (a *Anonymizer) Document(doc *model.OpnSenseDocument) {
+	if doc == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names := a.names(doc)
+	walkStrings(reflect.ValueOf(doc).Elem(), func(s string) string {
+		return names.replace(a.addresses(s))
+	})
+}
+
+// Mapping returns the pseudonyms that were assigned so far.
+func (a *Anonymizer) Mapping() *Mapping {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := &Mapping{Entries: make([]Entry, 0, len(a.entries))}
+	for _, entry := range a.entries {
+		m.Entries = append(m.Entries, entry)
+	}
+
+	m.sort()
+
+	return m
+}
+
+// record remembers a pseudonym and returns it.
+func (a *Anonymizer) record(kind, original, pseudonym string) string {
+	a.entries[kind+"\x00"+original] = Entry{Kind: kind, Original: original, Pseudonym: pseudonym}
+
+	return pseudonym
+}
+
+// mac returns the keyed MAC of the given parts.
+func (a *Anonymizer) mac(parts ...[]byte) []byte {
+	h := hmac.New(sha256.New, a.key)
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+
+	return h.Sum(nil)
+}
+
+// label returns the pseudonym label of a name, such as "host-1a2b3c4d".
+func (a *Anonymizer) label(kind, prefix, name string) string {
+	return prefix + hex.EncodeToString(a.mac([]byte(kind), []byte(strings.ToLower(name)))[:4])
+}
+
+// names collects the hostnames, domains and usernames of a configuration and assigns their
+// pseudonyms.
+func (a *Anonymizer) names(doc *model.OpnSenseDocument) *replacer {
+	r := &replacer{pseudonyms: map[string]string{}}
+
+	hostname := func(name string) {
+		name = strings.TrimSpace(name)
+		if isName(name) && !strings.Contains(name, ".") && !slices.Contains(keptHostnames, strings.ToLower(name)) {
+			r.add(name, a.record(KindHostname, name, a.label(KindHostname, "host-", name)))
+		}
+	}
+
+	domain := func(name string) {
+		name = strings.Trim(strings.TrimSpace(name), ".")
+		if !isName(name) || slices.Contains(keptDomains, strings.ToLower(name)) {
+			return
+		}
+
+		// Labels are keyed with their parent domains, so that equal labels of different domains
+		// get different pseudonyms
+		parts := strings.Split(name, ".")
+		labels := make([]string, len(parts))
+
+		for i := range parts {
+			labels[i] = a.label(KindDomain, "d", strings.Join(parts[i:], "."))
+		}
+
+		// The top-level domain is replaced by the reserved "example" domain
+		if len(labels) > 1 {
+			labels[len(labels)-1] = "example"
+		}
+
+		r.add(name, a.record(KindDomain, name, strings.Join(labels, ".")))
+	}
+
+	username := func(name string) {
+		if name = strings.TrimSpace(name); isName(name) && !slices.Contains(keptUsernames, strings.ToLower(name)) {
+			r.add(name, a.record(KindUsername, name, a.label(KindUsername, "user-", name)))
+		}
+	}
+
+	hostname(doc.System.Hostname)
+	domain(doc.System.Domain)
+
+	for _, user := range doc.System.User {
+		username(user.Name)
+	}
+
+	username(doc.HighAvailabilitySync.Username)
+
+	if monit := doc.OPNsense.Monit; monit != nil {
+		username(monit.General.Username)
+		username(monit.General.HttpdUsername)
+	}
+
+	for _, host := range doc.DNSMasquerade.Hosts {
+		hostname(host.Host)
+		domain(host.Domain)
+
+		for _, alias := range host.Aliases {
+			hostname(alias)
+		}
+	}
+
+	for _, override := range doc.DNSMasquerade.DomainOverrides {
+		domain(override.Domain)
+	}
+
+	for _, name := range doc.Interfaces.Names() {
+		hostname(doc.Interfaces.Items[name].DHCPHostname)
+	}
+
+	for _, name := range doc.Dhcpd.Names() {
+		iface, _ := doc.Dhcpd.Get(name)
+		for _, lease := range iface.Staticmap {
+			hostname(lease.Hostname)
+		}
+	}
+
+	r.sort()
+
+	return r
+}
+
+// addresses replaces the public IPv4 and IPv6 addresses in s.
+func (a *Anonymizer) addresses(s string) string {
+	if !strings.ContainsAny(s, ".:") {
+		return s
+	}
+
+	s = replaceMatches(s, ipv4Pattern, ipv4Token, a.address)
+
+	return replaceMatches(s, ipv6Pattern, ipv6Token, a.address)
+}
+
+// address returns the pseudonym of an address in plain or CIDR notation, or the match itself if it
+// is not a public address.
+func (a *Anonymizer) address(match string) string {
+	text, bits, hasBits := strings.Cut(match, "/")
+
+	addr, err := netip.ParseAddr(text)
+	if err != nil || !isPublic(addr) {
+		return match
+	}
+
+	pseudonym := a.mapAddr(addr)
+	a.record(KindIP, addr.String(), pseudonym.String())
+
+	if !hasBits {
+		return pseudonym.String()
+	}
+
+	length, err := strconv.Atoi(bits)
+	if err != nil || length > addr.BitLen() {
+		return match
+	}
+
+	// Network addresses stay network addresses of the same length
+	if netip.PrefixFrom(addr, length).Masked().Addr() == addr {
+		if network := netip.PrefixFrom(pseudonym, length).Masked().Addr(); network != pseudonym {
+			a.record(KindIP, match, network.String()+"/"+bits)
+			return network.String() + "/" + bits
+		}
+	}
+
+	return pseudonym.String() + "/" + bits
+}
+
+// mapAddr maps a public address prefix-preservingly: every bit after the kept leading octets is
+// flipped depending on the keyed MAC of the bits before it. Pseudonyms outside public address space
+// are mapped again with a different tweak.
+func (a *Anonymizer) mapAddr(addr netip.Addr) netip.Addr {
+	in := addr.AsSlice()
+	first, last := 8, addr.BitLen()
+
+	if addr.Is6() {
+		first = 16
+	}
+
+	var out netip.Addr
+
+	for attempt := range maxAddressAttempts {
+		bytes := slices.Clone(in)
+
+		for bit := first; bit < last; bit++ {
+			if a.mac([]byte(KindIP), binary.BigEndian.AppendUint32(nil, uint32(attempt)), prefixBits(in, bit))[0]&1 == 1 {
+				bytes[bit/8] ^= 0x80 >> (bit % 8)
+			}
+		}
+
+		out, _ = netip.AddrFromSlice(bytes)
+		if isPublic(out) {
+			break
+		}
+	}
+
+	return out
+}
+
+// prefixBits returns the first n bits of an address followed by n, so that prefixes of different
+// lengths never collide.
+func prefixBits(addr []byte, n int) []byte {
+	prefix := make([]byte, len(addr)+1)
+	copy(prefix, addr[:n/8])
+
+	if n%8 != 0 {
+		prefix[n/8] = addr[n/8] & ^byte(0xff>>(n%8))
+	}
+
+	prefix[len(addr)] = byte(n)
+
+	return prefix
+}
+
+// isPublic reports whether an address is a publicly routed unicast address.
+func isPublic(addr netip.Addr) bool {
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.Is4In6() {
+		return false
+	}
+
+	if addr.Is6() && !globalUnicastV6.Contains(addr) {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// replaceMatches replaces the matches of a pattern that are not part of a longer token made of the
+// token characters.
+func replaceMatches(s string, pattern *regexp.Regexp, token string, replace func(string) string) string {
+	matches := pattern.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+
+	last := 0
+
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if start > 0 && strings.IndexByte(token, s[start-1]) >= 0 || end < len(s) && strings.IndexByte(token, s[end]) >= 0 && !isTrailingDot(s, end) {
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		b.WriteString(replace(s[start:end]))
+		last = end
+	}
+
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// isTrailingDot reports whether s has a dot at i that ends a sentence rather than continuing a
+// token.
+func isTrailingDot(s string, i int) bool {
+	return s[i] == '.' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\n')
+}
+
+// isName reports whether s looks like a hostname, domain or username.
+func isName(s string) bool {
+	if s == "" || s == "*" {
+		return false
+	}
+
+	for _, r := range s {
+		if !isNameRune(byte(r)) && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNameRune reports whether c can be part of a name.
+func isNameRune(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// replacer replaces names that are delimited by characters that cannot be part of a name.
+type replacer struct {
+	pseudonyms map[string]string
+	// names are the lower-case names, longest first.
+	names []string
+}
+
+// add adds a name and its pseudonym.
+func (r *replacer) add(name, pseudonym string) {
+	name = strings.ToLower(name)
+	if _, ok := r.pseudonyms[name]; !ok {
+		r.names = append(r.names, name)
+	}
+
+	r.pseudonyms[name] = pseudonym
+}
+
+// sort orders the names longest first, so that "fw.example.com" is replaced before "example.com".
+func (r *replacer) sort() {
+	slices.SortFunc(r.names, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+
+		return strings.Compare(a, b)
+	})
+}
+
+// replace replaces every delimited occurrence of the names in s, ignoring case, in a single pass.
+func (r *replacer) replace(s string) string {
+	if len(r.names) == 0 || s == "" {
+		return s
+	}
+
+	lower := strings.ToLower(s)
+
+	var b strings.Builder
+
+	last := 0
+
+	for i := 0; i < len(s); {
+		if i > 0 && isNameRune(s[i-1]) {
+			i++
+			continue
+		}
+
+		name := r.match(lower, i)
+		if name == "" {
+			i++
+			continue
+		}
+
+		b.WriteString(s[last:i])
+		b.WriteString(r.pseudonyms[name])
+		i += len(name)
+		last = i
+	}
+
+	if last == 0 {
+		return s
+	}
+
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// match returns the longest name that starts at i and ends at a delimiter.
+func (r *replacer) match(lower string, i int) string {
+	for _, name := range r.names {
+		end := i + len(name)
+		if strings.HasPrefix(lower[i:], name) && (end == len(lower) || !isNameRune(lower[end])) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// walkStrings replaces every settable string reachable from v, including the values of maps.
+// XML element names are skipped.
+func walkStrings(v reflect.Value, fn func(string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(fn(v.String()))
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkStrings(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		if v.Type() == xmlNameType {
+			return
+		}
+
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				walkStrings(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			walkStrings(v.Index(i), fn)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values are not addressable, so they are updated on a copy
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			walkStrings(value, fn)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	default:
+	}
+}
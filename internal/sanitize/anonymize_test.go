@@ -0,0 +1,168 @@
+package sanitize
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSample parses a configuration from the testdata directory.
+func loadSample(t *testing.T, name string) *model.OpnSenseDocument {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("..", "..", "testdata", name))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	doc, err := parser.NewXMLParser().Parse(t.Context(), file)
+	require.NoError(t, err)
+
+	return doc
+}
+
+// newTestDocument returns a small configuration with public and private addresses and names.
+func newTestDocument() *model.OpnSenseDocument {
+	doc := &model.OpnSenseDocument{}
+	doc.System.Hostname = "fw01"
+	doc.System.Domain = "corp.example.com"
+	doc.System.User = []model.User{{Name: "root"}, {Name: "alice", Descr: "alice admin of fw01.corp.example.com"}}
+	doc.Gateways.Gateway = []model.Gateway{
+		{Name: "WAN_GW", Gateway: "203.0.113.1", Descr: "Uplink 203.0.113.1 via 10.0.0.1"},
+		{Name: "WAN_GW6", Gateway: "2001:db8:1:2::1"},
+	}
+	doc.Filter.Rule = []model.Rule{
+		{Type: "pass", Descr: "Allow 203.0.113.0/24 and 203.0.113.77 to 192.168.1.10."},
+	}
+
+	return doc
+}
+
+func TestAnonymizer_Document(t *testing.T) {
+	anonymizer, err := NewAnonymizer([]byte("test-key"))
+	require.NoError(t, err)
+
+	doc := newTestDocument()
+	anonymizer.Document(doc)
+
+	assert.True(t, strings.HasPrefix(doc.System.Hostname, "host-"), doc.System.Hostname)
+	assert.True(t, strings.HasSuffix(doc.System.Domain, ".example"), doc.System.Domain)
+	assert.NotContains(t, doc.System.Domain, "corp")
+	assert.Equal(t, "root", doc.System.User[0].Name, "default usernames are kept")
+	assert.True(t, strings.HasPrefix(doc.System.User[1].Name, "user-"), doc.System.User[1].Name)
+	assert.Equal(t,
+		doc.System.User[1].Name+" admin of "+doc.System.Hostname+"."+doc.System.Domain,
+		doc.System.User[1].Descr,
+		"names are replaced in free text")
+
+	gateway := netip.MustParseAddr(doc.Gateways.Gateway[0].Gateway)
+	assert.NotEqual(t, "203.0.113.1", gateway.String())
+	assert.Equal(t, byte(203), gateway.As4()[0], "the first octet is kept")
+	assert.NotEqual(t, byte(1), gateway.As4()[3], "the host octet is mapped")
+	assert.Equal(t, "Uplink "+gateway.String()+" via 10.0.0.1", doc.Gateways.Gateway[0].Descr)
+
+	gateway6 := netip.MustParseAddr(doc.Gateways.Gateway[1].Gateway)
+	assert.NotEqual(t, "2001:db8:1:2::1", gateway6.String())
+	assert.True(t, isPublic(gateway6))
+	assert.Equal(t, []byte{0x20, 0x01}, gateway6.AsSlice()[:2], "the first 16 bits are kept")
+	assert.NotEqual(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, gateway6.AsSlice()[8:], "the interface identifier is mapped")
+
+	// Addresses of one subnet stay in one subnet, and network addresses stay network addresses
+	network := netip.PrefixFrom(gateway, 24).Masked()
+	words := strings.Fields(doc.Filter.Rule[0].Descr)
+	require.Len(t, words, 6)
+	assert.Equal(t, network.String(), words[1])
+
+	host := netip.MustParseAddr(words[3])
+	assert.True(t, network.Contains(host), host.String())
+	assert.NotEqual(t, byte(77), host.As4()[3], "the host octet is mapped")
+	assert.Equal(t, "192.168.1.10.", words[5])
+}
+
+func TestAnonymizer_Deterministic(t *testing.T) {
+	first, err := NewAnonymizer([]byte("key"))
+	require.NoError(t, err)
+	second, err := NewAnonymizer([]byte("key"))
+	require.NoError(t, err)
+	other, err := NewAnonymizer([]byte("other"))
+	require.NoError(t, err)
+
+	a, b, c := newTestDocument(), newTestDocument(), newTestDocument()
+	first.Document(a)
+	second.Document(b)
+	other.Document(c)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a.System.Hostname, c.System.Hostname)
+	assert.NotEqual(t, a.Gateways.Gateway[0].Gateway, c.Gateways.Gateway[0].Gateway)
+}
+
+func TestAnonymizer_KeepsPrivateAddresses(t *testing.T) {
+	anonymizer, err := NewAnonymizer(nil)
+	require.NoError(t, err)
+
+	for _, addr := range []string{"10.1.2.3", "192.168.1.1/24", "172.16.0.1", "100.64.0.1", "fe80::1", "fd00::1", "127.0.0.1"} {
+		assert.Equal(t, addr, anonymizer.addresses(addr))
+	}
+
+	assert.Empty(t, anonymizer.Mapping().Entries)
+}
+
+func TestMapping_Reverse(t *testing.T) {
+	anonymizer, err := NewAnonymizer([]byte("key"))
+	require.NoError(t, err)
+
+	doc := newTestDocument()
+	original := doc.Filter.Rule[0].Descr + " " + doc.System.User[1].Descr + " " + doc.Gateways.Gateway[0].Descr
+	anonymizer.Document(doc)
+	anonymized := doc.Filter.Rule[0].Descr + " " + doc.System.User[1].Descr + " " + doc.Gateways.Gateway[0].Descr
+
+	require.NotEqual(t, original, anonymized)
+
+	content, err := anonymizer.Mapping().JSON()
+	require.NoError(t, err)
+
+	mapping, err := ParseMapping([]byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, original, mapping.Reverse(anonymized))
+}
+
+func TestMapping_Sorted(t *testing.T) {
+	anonymizer, err := NewAnonymizer([]byte("key"))
+	require.NoError(t, err)
+
+	anonymizer.Document(newTestDocument())
+
+	entries := anonymizer.Mapping().Entries
+	require.NotEmpty(t, entries)
+
+	for i := 1; i < len(entries); i++ {
+		assert.LessOrEqual(t, entries[i-1].Kind+entries[i-1].Original, entries[i].Kind+entries[i].Original)
+	}
+}
+
+func TestParseMapping_Invalid(t *testing.T) {
+	_, err := ParseMapping([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestAnonymizer_Sample(t *testing.T) {
+	anonymizer, err := NewAnonymizer([]byte("key"))
+	require.NoError(t, err)
+
+	doc := loadSample(t, "sample.config.2.xml")
+	hostname, domain := doc.System.Hostname, doc.System.Domain
+
+	anonymizer.Document(doc)
+
+	assert.NotEqual(t, hostname, doc.System.Hostname)
+	assert.NotEqual(t, domain, doc.System.Domain)
+}
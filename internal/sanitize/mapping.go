@@ -0,0 +1,86 @@
+package sanitize
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Entry is a pseudonym and the value that it replaces.
+type Entry struct {
+	Kind      string `json:"kind"`
+	Original  string `json:"original"`
+	Pseudonym string `json:"pseudonym"`
+}
+
+// Mapping lists the pseudonyms of an anonymization run. It is not needed to anonymize again with
+// the same key, but allows anonymized reports to be mapped back to the original values.
+type Mapping struct {
+	Entries []Entry `json:"entries"`
+}
+
+// ParseMapping parses a mapping saved as JSON.
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse anonymization mapping: %w", err)
+	}
+
+	return &m, nil
+}
+
+// JSON returns the mapping as indented JSON.
+func (m *Mapping) JSON() (string, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal anonymization mapping: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
+
+// Reverse replaces the pseudonyms in s with their original values.
+func (m *Mapping) Reverse(s string) string {
+	addresses := make(map[string]string)
+	names := &replacer{pseudonyms: map[string]string{}}
+
+	for _, entry := range m.Entries {
+		if entry.Kind == KindIP {
+			addresses[entry.Pseudonym] = entry.Original
+		} else if entry.Pseudonym != "" {
+			names.add(entry.Pseudonym, entry.Original)
+		}
+	}
+
+	names.sort()
+
+	address := func(match string) string {
+		if original, ok := addresses[match]; ok {
+			return original
+		}
+
+		text, bits, hasBits := strings.Cut(match, "/")
+		if original, ok := addresses[text]; ok && hasBits {
+			return original + "/" + bits
+		}
+
+		return match
+	}
+
+	s = replaceMatches(s, ipv4Pattern, ipv4Token, address)
+	s = replaceMatches(s, ipv6Pattern, ipv6Token, address)
+
+	return names.replace(s)
+}
+
+// sort orders the entries by kind and original value.
+func (m *Mapping) sort() {
+	slices.SortFunc(m.Entries, func(a, b Entry) int {
+		if c := strings.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Original, b.Original)
+	})
+}
@@ -0,0 +1,124 @@
+// Package sanitize prepares configurations for sharing. Redact removes secrets such as password
+// hashes and private keys, and an Anonymizer replaces public IP addresses, hostnames, domains and
+// usernames with keyed pseudonyms. Both work on the parsed document, so that every report, export
+// and finding generated from it is sanitized.
+package sanitize
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Redacted replaces the secrets of redacted documents.
+const Redacted = "[REDACTED]"
+
+// defaultCommunities are the well-known SNMP communities. They are not secret and are kept so that
+// the checks for default communities still report them.
+var defaultCommunities = []string{"public", "private"} //nolint:gochecknoglobals // lookup table
+
+// Redact replaces the secrets of a configuration with Redacted and returns the number of redacted
+// values. It covers password hashes, TOTP seeds, API key secrets, WireGuard private and pre-shared
+// keys, OpenVPN TLS keys, DHCPv6 authentication secrets, certificate private keys, SNMP
+// communities, the HA sync password, CARP passwords and the Monit passwords.
+//
+// Empty secrets stay empty and password hashes keep their crypt scheme prefix, such as "$6$", so
+// that the checks for missing secrets and weak hashes give the same results on a redacted
+// configuration.
+func Redact(doc *model.OpnSenseDocument) int {
+	if doc == nil {
+		return 0
+	}
+
+	var r redactor
+
+	for i := range doc.System.User {
+		user := &doc.System.User[i]
+		r.hash(&user.Password)
+		r.secret(&user.OTPSeed)
+
+		for j := range user.APIKeys {
+			r.secret(&user.APIKeys[j].Secret)
+		}
+	}
+
+	r.community(&doc.Snmpd.ROCommunity)
+	r.community(&doc.System.SNMPD.ROCommunity)
+	r.secret(&doc.HighAvailabilitySync.Password)
+	r.secret(&doc.Cert.Prv)
+
+	for i := range doc.VirtualIP.Vip {
+		r.secret(&doc.VirtualIP.Vip[i].Password)
+	}
+
+	for i := range doc.OpenVPN.Servers {
+		r.secret(&doc.OpenVPN.Servers[i].TLS)
+	}
+
+	for name, dhcp := range doc.Dhcpd.Items {
+		r.secret(&dhcp.AdvDHCP6KeyInfoStatementSecret)
+		doc.Dhcpd.Items[name] = dhcp
+	}
+
+	if wg := doc.OPNsense.Wireguard; wg != nil {
+		for i := range wg.Server.Servers.Server {
+			r.secret(&wg.Server.Servers.Server[i].Privkey)
+		}
+
+		for i := range wg.Client.Clients.Client {
+			r.secret(&wg.Client.Clients.Client[i].PSK)
+		}
+	}
+
+	if monit := doc.OPNsense.Monit; monit != nil {
+		r.secret(&monit.General.Password)
+		r.secret(&monit.General.HttpdPassword)
+	}
+
+	return r.count
+}
+
+// redactor replaces secrets and counts the replaced values.
+type redactor struct {
+	count int
+}
+
+// secret replaces a non-empty secret.
+func (r *redactor) secret(value *string) {
+	if strings.TrimSpace(*value) == "" {
+		return
+	}
+
+	*value = Redacted
+	r.count++
+}
+
+// hash replaces a password hash and keeps its crypt scheme prefix.
+func (r *redactor) hash(value *string) {
+	hash := strings.TrimSpace(*value)
+	if hash == "" {
+		return
+	}
+
+	*value = Redacted
+	r.count++
+
+	// Modular crypt hashes start with "$<scheme>$"
+	if !strings.HasPrefix(hash, "$") {
+		return
+	}
+
+	if end := strings.Index(hash[1:], "$"); end > 0 {
+		*value = hash[:end+2] + Redacted
+	}
+}
+
+// community replaces an SNMP community unless it is a well-known default.
+func (r *redactor) community(value *string) {
+	if slices.Contains(defaultCommunities, strings.ToLower(strings.TrimSpace(*value))) {
+		return
+	}
+
+	r.secret(value)
+}
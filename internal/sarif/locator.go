@@ -0,0 +1,227 @@
+package sarif
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rootElement is the root element of OPNsense configurations.
+const rootElement = "opnsense"
+
+// componentStep matches a step of a component path, such as "rule[3]".
+//
+//nolint:gochecknoglobals // compiled once
+var componentStep = regexp.MustCompile(`^([A-Za-z0-9_-]+)(?:\[(\d+)\])?$`)
+
+// element is a located configuration element.
+type element struct {
+	xpath string
+	line  int
+}
+
+// Locator locates configuration paths in a config.xml file. Components are dotted paths with
+// zero-based list indexes, such as "filter.rule[3].type", and objects are the object IDs of
+// findings, such as "filter.rule/<uuid>". Locations are best effort: components that do not name
+// an element are located at their nearest enclosing element, and a file with a syntax error is
+// only indexed up to the error.
+type Locator struct {
+	uri      string
+	elements map[string]int
+	uuids    map[string]element
+}
+
+// LoadLocator indexes a configuration file. The path is reported as the artifact URI.
+func LoadLocator(path string) (*Locator, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to open configuration: %w", err)
+	}
+	defer f.Close()
+
+	return NewLocator(ArtifactURI(path), f), nil
+}
+
+// NewLocator indexes the elements of a configuration read from r.
+func NewLocator(uri string, r io.Reader) *Locator {
+	l := &Locator{uri: uri, elements: make(map[string]int), uuids: make(map[string]element)}
+
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	dec.Entity = map[string]string{}
+
+	type frame struct {
+		path     string
+		names    string
+		children map[string]int
+	}
+
+	stack := []frame{{children: make(map[string]int)}}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := &stack[len(stack)-1]
+			parent.children[t.Name.Local]++
+
+			current := frame{
+				path:     fmt.Sprintf("%s/%s[%d]", parent.path, t.Name.Local, parent.children[t.Name.Local]),
+				names:    parent.names + "/" + t.Name.Local,
+				children: make(map[string]int),
+			}
+
+			line, _ := dec.InputPos()
+			l.elements[current.path] = line
+
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "uuid" && attr.Value != "" {
+					l.uuids[attr.Value] = element{
+						xpath: fmt.Sprintf("%s[@uuid='%s']", current.names, attr.Value),
+						line:  line,
+					}
+				}
+			}
+
+			stack = append(stack, current)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return l
+}
+
+// ArtifactURI returns the artifact URI of a file path: relative paths are kept relative so that
+// dashboards resolve them against the repository root.
+func ArtifactURI(path string) string {
+	if filepath.IsAbs(path) {
+		return "file://" + filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// URI returns the artifact URI of the configuration.
+func (l *Locator) URI() string {
+	return l.uri
+}
+
+// Locate returns the location of a finding about an object or component. The object is tried
+// first; the location always names the configuration file.
+func (l *Locator) Locate(object, component string) Location {
+	if i := strings.LastIndex(object, "/"); i >= 0 {
+		if el, ok := l.uuids[object[i+1:]]; ok {
+			return l.location(el)
+		}
+	}
+
+	if el, ok := l.lookup(component); ok {
+		return l.location(el)
+	}
+
+	return Location{PhysicalLocation: &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: l.uri}}}
+}
+
+// LocateLine returns a location by line and column, such as the position of a syntax error.
+func (l *Locator) LocateLine(line, column int) Location {
+	location := Location{PhysicalLocation: &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: l.uri}}}
+	if line > 0 {
+		location.PhysicalLocation.Region = &Region{StartLine: line, StartColumn: max(column, 0)}
+	}
+
+	return location
+}
+
+// location returns the location of an element.
+func (l *Locator) location(el element) Location {
+	return Location{
+		PhysicalLocation: &PhysicalLocation{
+			ArtifactLocation: ArtifactLocation{URI: l.uri},
+			Region:           &Region{StartLine: el.line},
+		},
+		LogicalLocations: []LogicalLocation{{FullyQualifiedName: el.xpath, Kind: "element"}},
+	}
+}
+
+// lookup finds the element a component names or, failing that, its nearest enclosing element
+// below the root.
+func (l *Locator) lookup(component string) (element, bool) {
+	steps, err := parseComponent(component)
+	if err != nil {
+		return element{}, false
+	}
+
+	for n := len(steps); n > 0; n-- {
+		key := "/" + rootElement + "[1]"
+		xpath := "/" + rootElement
+
+		for _, step := range steps[:n] {
+			key += fmt.Sprintf("/%s[%d]", step.name, step.index+1)
+			xpath += "/" + step.name
+
+			if step.indexed {
+				xpath += fmt.Sprintf("[%d]", step.index+1)
+			}
+		}
+
+		if line, ok := l.elements[key]; ok {
+			return element{xpath: xpath, line: line}, true
+		}
+	}
+
+	return element{}, false
+}
+
+// pathStep is a step of a component path.
+type pathStep struct {
+	name    string
+	index   int
+	indexed bool
+}
+
+// errInvalidComponent indicates a component that is not a configuration path.
+var errInvalidComponent = errors.New("invalid component path")
+
+// parseComponent splits a component path into steps.
+func parseComponent(component string) ([]pathStep, error) {
+	if component == "" {
+		return nil, errInvalidComponent
+	}
+
+	parts := strings.Split(strings.TrimPrefix(component, rootElement+"."), ".")
+	steps := make([]pathStep, 0, len(parts))
+
+	for _, part := range parts {
+		match := componentStep.FindStringSubmatch(part)
+		if match == nil {
+			return nil, errInvalidComponent
+		}
+
+		step := pathStep{name: match[1]}
+		if match[2] != "" {
+			index, err := strconv.Atoi(match[2])
+			if err != nil {
+				return nil, errInvalidComponent
+			}
+
+			step.index, step.indexed = index, true
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,100 @@
+package sarif_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const locatorConfig = `<?xml version="1.0"?>
+<opnsense>
+  <system>
+    <hostname>fw</hostname>
+  </system>
+  <filter>
+    <rule uuid="1f0e">
+      <type>pass</type>
+    </rule>
+    <rule>
+      <type>block</type>
+    </rule>
+  </filter>
+</opnsense>
+`
+
+func TestLocator_Locate(t *testing.T) {
+	locator := sarif.NewLocator("config.xml", strings.NewReader(locatorConfig))
+	assert.Equal(t, "config.xml", locator.URI())
+
+	tests := []struct {
+		name      string
+		object    string
+		component string
+		line      int
+		xpath     string
+	}{
+		{"element", "", "system.hostname", 4, "/opnsense/system/hostname"},
+		{"root prefix", "", "opnsense.system", 3, "/opnsense/system"},
+		{"list index", "", "filter.rule[1].type", 11, "/opnsense/filter/rule[2]/type"},
+		{"nearest ancestor", "", "filter.rule[0].descr", 7, "/opnsense/filter/rule[1]"},
+		{"uuid", "filter.rule/1f0e", "filter.rule[5]", 7, "/opnsense/filter/rule[@uuid='1f0e']"},
+		{"unknown uuid", "filter.rule/none", "filter", 6, "/opnsense/filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := locator.Locate(tt.object, tt.component)
+
+			require.NotNil(t, location.PhysicalLocation.Region)
+			assert.Equal(t, "config.xml", location.PhysicalLocation.ArtifactLocation.URI)
+			assert.Equal(t, tt.line, location.PhysicalLocation.Region.StartLine)
+			require.Len(t, location.LogicalLocations, 1)
+			assert.Equal(t, tt.xpath, location.LogicalLocations[0].FullyQualifiedName)
+		})
+	}
+
+	t.Run("unlocated", func(t *testing.T) {
+		for _, component := range []string{"", "interfaces.wan", "not a path"} {
+			location := locator.Locate("", component)
+
+			assert.Equal(t, "config.xml", location.PhysicalLocation.ArtifactLocation.URI)
+			assert.Nil(t, location.PhysicalLocation.Region, component)
+			assert.Empty(t, location.LogicalLocations, component)
+		}
+	})
+}
+
+func TestLocator_SyntaxError(t *testing.T) {
+	config := "<opnsense>\n  <system>\n    <hostname>fw</hostname\n  </system>\n</opnsense>\n"
+	locator := sarif.NewLocator("config.xml", strings.NewReader(config))
+
+	location := locator.Locate("", "system")
+	require.NotNil(t, location.PhysicalLocation.Region, "elements before the error are indexed")
+	assert.Equal(t, 2, location.PhysicalLocation.Region.StartLine)
+
+	location = locator.LocateLine(3, 0)
+	assert.Equal(t, 3, location.PhysicalLocation.Region.StartLine)
+	assert.Nil(t, locator.LocateLine(0, 0).PhysicalLocation.Region)
+}
+
+func TestLoadLocator(t *testing.T) {
+	locator, err := sarif.LoadLocator(filepath.Join("..", "..", "testdata", "sample.config.1.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, "../../testdata/sample.config.1.xml", locator.URI())
+
+	location := locator.Locate("", "system.hostname")
+	require.NotNil(t, location.PhysicalLocation.Region)
+	assert.Positive(t, location.PhysicalLocation.Region.StartLine)
+
+	_, err = sarif.LoadLocator(filepath.Join(t.TempDir(), "missing.xml"))
+	require.Error(t, err)
+}
+
+func TestArtifactURI(t *testing.T) {
+	assert.Equal(t, "configs/fw.xml", sarif.ArtifactURI("./configs//fw.xml"))
+	assert.Equal(t, "file:///etc/config.xml", sarif.ArtifactURI("/etc/config.xml"))
+}
@@ -0,0 +1,227 @@
+// Package sarif writes audit and validation results in the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0, which code scanning dashboards such as GitHub and GitLab consume.
+//
+// Every check or control becomes a rule of the tool driver and every finding a result that
+// references its rule. Results are located in the configuration file by line and XPath where the
+// finding names a configuration element; see Locator.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+)
+
+// SARIF format version and schema of the logs written by this package.
+const (
+	Version   = "2.1.0"
+	SchemaURI = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+)
+
+// Result levels.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// FingerprintKey names the finding fingerprint in the partial fingerprints of results.
+const FingerprintKey = "opnDossier/v1"
+
+// informationURI is the home page of the tool reported in the driver.
+const informationURI = "https://github.com/EvilBit-Labs/opnDossier"
+
+// Log is a SARIF log file.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a single invocation of the tool.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the tool that produced the results.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool component that defines the rules.
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule describes a check or control.
+type Rule struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name,omitempty"`
+	ShortDescription     *Message       `json:"shortDescription,omitempty"`
+	FullDescription      *Message       `json:"fullDescription,omitempty"`
+	Help                 *Message       `json:"help,omitempty"`
+	DefaultConfiguration *Configuration `json:"defaultConfiguration,omitempty"`
+	Properties           *Properties    `json:"properties,omitempty"`
+}
+
+// Message is a plain text message with an optional markdown variant.
+type Message struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// Configuration is the default configuration of a rule.
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// Properties is the property bag of a rule. SecuritySeverity is the 0.0 to 10.0 score GitHub uses
+// to rank security results.
+type Properties struct {
+	Tags             []string `json:"tags,omitempty"`
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+}
+
+// Result is a finding of a rule.
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Suppressions        []Suppression     `json:"suppressions,omitempty"`
+}
+
+// Location is where a result was found.
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// PhysicalLocation is a file and an optional region in it.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation identifies a file.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a position in a file.
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// LogicalLocation is a configuration element, identified by its XPath.
+type LogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// Suppression marks a result as accepted, such as by a waiver.
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// Builder collects the rules and results of a run.
+type Builder struct {
+	run   Run
+	rules map[string]int
+}
+
+// NewBuilder returns a builder for a run of opnDossier.
+func NewBuilder() *Builder {
+	return &Builder{
+		run: Run{
+			Tool: Tool{Driver: Driver{
+				Name:           constants.AppName,
+				Version:        constants.Version,
+				InformationURI: informationURI,
+				Rules:          []Rule{},
+			}},
+			Results: []Result{},
+		},
+		rules: make(map[string]int),
+	}
+}
+
+// AddRule adds a rule unless a rule with the same ID exists and returns the index of the rule.
+func (b *Builder) AddRule(rule Rule) int {
+	if index, ok := b.rules[rule.ID]; ok {
+		return index
+	}
+
+	index := len(b.run.Tool.Driver.Rules)
+	b.run.Tool.Driver.Rules = append(b.run.Tool.Driver.Rules, rule)
+	b.rules[rule.ID] = index
+
+	return index
+}
+
+// AddResult adds a result. Its rule index is set from its rule ID; results of unknown rules get a
+// rule with only an ID.
+func (b *Builder) AddResult(result Result) {
+	result.RuleIndex = b.AddRule(Rule{ID: result.RuleID})
+	b.run.Results = append(b.run.Results, result)
+}
+
+// Log returns the log of the run.
+func (b *Builder) Log() *Log {
+	return &Log{
+		Schema:  SchemaURI,
+		Version: Version,
+		Runs:    []Run{b.run},
+	}
+}
+
+// ToJSON returns the log as an indented JSON string.
+func (l *Log) ToJSON() (string, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF log: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Level returns the result level of a finding severity: critical and high findings are errors,
+// medium findings warnings and low and informational findings notes.
+func Level(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return LevelError
+	case "medium":
+		return LevelWarning
+	default:
+		return LevelNote
+	}
+}
+
+// SecuritySeverity returns the security-severity score of a finding severity, which places it in
+// the same severity band on GitHub.
+func SecuritySeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "9.5"
+	case "high":
+		return "8.0"
+	case "medium":
+		return "5.5"
+	case "low":
+		return "3.0"
+	default:
+		return "0.0"
+	}
+}
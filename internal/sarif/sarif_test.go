@@ -0,0 +1,70 @@
+package sarif_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif/sariftest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	builder := sarif.NewBuilder()
+
+	assert.Equal(t, 0, builder.AddRule(sarif.Rule{ID: "FW-001", ShortDescription: &sarif.Message{Text: "Default deny"}}))
+	assert.Equal(t, 0, builder.AddRule(sarif.Rule{ID: "FW-001"}), "rules are deduplicated by ID")
+
+	builder.AddResult(sarif.Result{RuleID: "FW-001", Level: sarif.LevelError, Message: sarif.Message{Text: "missing"}})
+	builder.AddResult(sarif.Result{RuleID: "FW-002", Level: sarif.LevelNote, Message: sarif.Message{Text: "unknown rule"}})
+
+	log := builder.Log()
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "Default deny", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Equal(t, "FW-002", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, 0, run.Results[0].RuleIndex)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+
+	output, err := log.ToJSON()
+	require.NoError(t, err)
+	sariftest.RequireValid(t, output)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	assert.Equal(t, sarif.SchemaURI, decoded["$schema"])
+	assert.Equal(t, sarif.Version, decoded["version"])
+}
+
+func TestBuilder_Empty(t *testing.T) {
+	output, err := sarif.NewBuilder().Log().ToJSON()
+	require.NoError(t, err)
+
+	assert.Contains(t, output, `"results": []`, "runs without findings still report an empty result list")
+	sariftest.RequireValid(t, output)
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		severity string
+		level    string
+		score    string
+	}{
+		{"critical", sarif.LevelError, "9.5"},
+		{"High", sarif.LevelError, "8.0"},
+		{"medium", sarif.LevelWarning, "5.5"},
+		{"low", sarif.LevelNote, "3.0"},
+		{"info", sarif.LevelNote, "0.0"},
+		{"", sarif.LevelNote, "0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			assert.Equal(t, tt.level, sarif.Level(tt.severity))
+			assert.Equal(t, tt.score, sarif.SecuritySeverity(tt.severity))
+		})
+	}
+}
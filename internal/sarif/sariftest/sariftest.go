@@ -0,0 +1,36 @@
+// Package sariftest validates SARIF logs against the official SARIF 2.1.0 JSON schema in tests.
+package sariftest
+
+import (
+	"bytes"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/sarif"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaDocument is the OASIS SARIF 2.1.0 errata 01 schema.
+//
+//go:embed sarif-schema-2.1.0.json
+var schemaDocument []byte
+
+// RequireValid fails the test unless the log conforms to the SARIF 2.1.0 schema.
+func RequireValid(t testing.TB, log string) {
+	t.Helper()
+
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaDocument))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource(sarif.SchemaURI, schemaDoc))
+
+	schema, err := compiler.Compile(sarif.SchemaURI)
+	require.NoError(t, err)
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(log))
+	require.NoError(t, err)
+	require.NoError(t, schema.Validate(doc), "log does not conform to the SARIF 2.1.0 schema")
+}
@@ -0,0 +1,243 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Reflected types with a fixed schema.
+var (
+	timeType          = reflect.TypeFor[time.Time]()              //nolint:gochecknoglobals // Reflected type
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()         //nolint:gochecknoglobals // Reflected type
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]() //nolint:gochecknoglobals // Reflected type
+)
+
+// reflector builds schemas of Go types and collects the schemas of named structs.
+type reflector struct {
+	docs map[string]string
+	defs map[string]*Schema
+	// described records the keys of the docs that were looked up and packages the import paths of
+	// the structs, which tell the tests which doc comments to extract.
+	described map[string]bool
+	packages  map[string]bool
+}
+
+// newReflector returns a reflector that takes descriptions from docs.
+func newReflector(docs map[string]string) *reflector {
+	return &reflector{
+		docs:      docs,
+		defs:      make(map[string]*Schema),
+		described: make(map[string]bool),
+		packages:  make(map[string]bool),
+	}
+}
+
+// description returns the doc comment of a type or field.
+func (r *reflector) description(key string) string {
+	r.described[key] = true
+
+	return r.docs[key]
+}
+
+// schema returns the schema of a type. Named structs are referenced from $defs.
+func (r *reflector) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return marshalerSchema(t)
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json writes byte slices as base64 strings
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t, "")
+		}
+
+		name := typeName(t)
+		if _, ok := r.defs[name]; !ok {
+			// Reserve the definition before building it, for recursive types
+			r.defs[name] = nil
+			r.defs[name] = r.object(t, name)
+		}
+
+		return &Schema{Ref: "#/$defs/" + name}
+	default:
+		// Interfaces hold any value
+		return &Schema{}
+	}
+}
+
+// object returns the schema of a struct. The docs of its fields are looked up under path, the name
+// of the type or the path of the field that holds an anonymous struct.
+func (r *reflector) object(t reflect.Type, path string) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Description:          r.description(path),
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	r.fields(s, t, path, true)
+
+	return s
+}
+
+// fields adds the properties of the fields of a struct to a schema. The fields of embedded
+// structs are promoted unless a shallower field has the same name; the promoted fields of
+// embedded pointers are not required, since a nil pointer omits them.
+func (r *reflector) fields(s *Schema, t reflect.Type, path string, required bool) {
+	if t.PkgPath() != "" {
+		r.packages[t.PkgPath()] = true
+	}
+
+	var embedded []reflect.StructField
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, omitEmpty, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			embedded = append(embedded, field)
+			continue
+		}
+
+		if _, exists := s.Properties[name]; exists {
+			continue
+		}
+
+		fieldPath := path + "." + field.Name
+
+		var property *Schema
+		if field.Type.Kind() == reflect.Struct && field.Type.Name() == "" {
+			property = r.object(field.Type, fieldPath)
+		} else {
+			property = r.schema(field.Type)
+		}
+
+		if !omitEmpty && nullable(field.Type) {
+			property = nullableSchema(property)
+		}
+
+		if description := r.description(fieldPath); description != "" {
+			property.Description = description
+		}
+
+		s.Properties[name] = property
+
+		if required && !omitEmpty {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	for _, field := range embedded {
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		r.fields(s, ft, typeName(ft), required && field.Type.Kind() != reflect.Pointer)
+	}
+}
+
+// jsonName returns the property name and the omitempty option of a field, an empty name for
+// embedded structs whose fields are promoted, and false for fields that encoding/json skips.
+func jsonName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	omitEmpty := strings.Contains(","+options+",", ",omitempty,")
+
+	if field.Anonymous && name == "" {
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct {
+			return "", omitEmpty, true
+		}
+	}
+
+	if !field.IsExported() {
+		return "", false, false
+	}
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, omitEmpty, true
+}
+
+// nullable reports whether encoding/json writes null for the zero value of a type.
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// nullableSchema returns a schema that also allows null.
+func nullableSchema(s *Schema) *Schema {
+	if typ, ok := s.Type.(string); ok && s.Ref == "" {
+		s.Type = []string{typ, "null"}
+		return s
+	}
+
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// marshalerSchema returns the schema of a type with its own JSON encoding: a string when the zero
+// value encodes as a string, any value otherwise.
+func marshalerSchema(t reflect.Type) *Schema {
+	data, err := json.Marshal(reflect.New(t).Interface())
+	if err == nil && len(data) > 0 && data[0] == '"' {
+		return &Schema{Type: "string"}
+	}
+
+	return &Schema{}
+}
+
+// typeName returns the name of a type qualified by its package name, such as model.Rule.
+func typeName(t reflect.Type) string {
+	return t.String()
+}
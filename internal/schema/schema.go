@@ -0,0 +1,139 @@
+// Package schema generates JSON Schemas (draft 2020-12) of the JSON outputs by reflection over
+// the Go types that are marshaled.
+//
+// The schemas follow the rules of encoding/json: properties are named by their json tags, fields
+// of embedded structs are promoted, properties without omitempty are required, and nil pointers,
+// slices and maps without omitempty are written as null. Named structs are collected in $defs.
+// Descriptions come from the doc comments of the types and fields, which are extracted from the
+// source into docs.json; run go test ./internal/schema -update after changing them.
+//
+// Every schema and every JSON output carries constants.SchemaVersion. The tests of this package
+// fail when the shape of a schema changes without a change of the version.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/hasync"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// versionProperty is the property of the JSON outputs that holds the schema version.
+const versionProperty = "schemaVersion"
+
+// ErrUnknownSchema is returned for a schema name that is not one of Names.
+var ErrUnknownSchema = errors.New("unknown schema")
+
+// docs maps type names, such as model.Rule, and field paths, such as model.Rule.Descr, to their
+// doc comments.
+//
+//go:embed docs.json
+var docsJSON []byte
+
+// root is a JSON output with the Go type that is marshaled into it.
+type root struct {
+	name  string
+	title string
+	typ   reflect.Type
+}
+
+// roots lists the JSON outputs in the order of Names.
+var roots = []root{ //nolint:gochecknoglobals // Lookup table
+	{"document", "opnDossier configuration document", reflect.TypeFor[model.EnrichedOpnSenseDocument]()},
+	{"report", "opnDossier processor report", reflect.TypeFor[processor.Report]()},
+	{"audit", "opnDossier audit report", reflect.TypeFor[audit.Report]()},
+	{"hacheck", "opnDossier HA check result", reflect.TypeFor[hasync.Result]()},
+}
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type is a type name or a list of type names.
+	Type            any    `json:"type,omitempty"`
+	Format          string `json:"format,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	Const           string `json:"const,omitempty"`
+	Minimum         *int   `json:"minimum,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false or the schema of the values of a map.
+	AdditionalProperties any       `json:"additionalProperties,omitempty"`
+	Items                *Schema   `json:"items,omitempty"`
+	AnyOf                []*Schema `json:"anyOf,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Names returns the names of the schemas: document for convert --format json, report for the
+// processor report, audit for audit reports and hacheck for HA check results.
+func Names() []string {
+	names := make([]string, 0, len(roots))
+	for _, r := range roots {
+		names = append(names, r.name)
+	}
+
+	return names
+}
+
+// Generate returns the schema of a JSON output.
+func Generate(name string) (*Schema, error) {
+	var docs map[string]string
+	if err := json.Unmarshal(docsJSON, &docs); err != nil {
+		return nil, fmt.Errorf("failed to read the field documentation: %w", err)
+	}
+
+	return generate(name, docs, constants.SchemaVersion)
+}
+
+// Marshal returns a schema as indented JSON.
+func (s *Schema) Marshal() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal schema to JSON: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
+
+// generate returns the schema of a JSON output with the given documentation and version.
+func generate(name string, docs map[string]string, version string) (*Schema, error) {
+	i := slices.IndexFunc(roots, func(r root) bool { return r.name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, name)
+	}
+
+	r := newReflector(docs)
+
+	s := r.object(roots[i].typ, typeName(roots[i].typ))
+	s.Schema = Draft
+	s.ID = "urn:opndossier:schema:" + name + ":" + version
+	s.Title = roots[i].title
+	s.Properties[versionProperty] = &Schema{
+		Description: "Version of the schema that the output follows.",
+		Type:        "string",
+		Const:       version,
+	}
+	s.Required = append([]string{versionProperty}, s.Required...)
+
+	if len(r.defs) > 0 {
+		s.Defs = r.defs
+	}
+
+	return s, nil
+}
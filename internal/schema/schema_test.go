@@ -0,0 +1,366 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/audit"
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/markdown"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/EvilBit-Labs/opnDossier/internal/processor"
+	"github.com/charmbracelet/log"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites docs.json and the schema lock file.
+var update = flag.Bool("update", false, "update docs.json and the schema lock file") //nolint:gochecknoglobals // Test flag
+
+// modulePath is the import path of the module, which maps packages onto directories.
+const modulePath = "github.com/EvilBit-Labs/opnDossier/"
+
+// lockPath is the file that records the schema version with the fingerprints of the schemas.
+var lockPath = filepath.Join("testdata", "schema.lock.json") //nolint:gochecknoglobals // Test fixture path
+
+// schemaLock records the fingerprints of the schemas of a schema version.
+type schemaLock struct {
+	Version      string            `json:"version"`
+	Fingerprints map[string]string `json:"fingerprints"`
+}
+
+// fingerprints returns a hash of each schema without its descriptions and version, so that only
+// changes of the shape of the outputs change it.
+func fingerprints(t *testing.T) map[string]string {
+	t.Helper()
+
+	result := make(map[string]string)
+
+	for _, name := range Names() {
+		s, err := generate(name, nil, "")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(s)
+		require.NoError(t, err)
+
+		sum := sha256.Sum256(data)
+		result[name] = hex.EncodeToString(sum[:])
+	}
+
+	return result
+}
+
+// TestVersionBump fails when a schema changes while constants.SchemaVersion stays the same.
+func TestVersionBump(t *testing.T) {
+	current := fingerprints(t)
+
+	var lock schemaLock
+
+	data, err := os.ReadFile(lockPath)
+	if err == nil {
+		require.NoError(t, json.Unmarshal(data, &lock))
+	} else if !*update {
+		require.NoError(t, err, "run go test with -update to create the lock file")
+	}
+
+	changed := !maps.Equal(lock.Fingerprints, current)
+
+	if changed && lock.Version == constants.SchemaVersion {
+		t.Fatalf("the schemas changed without a version bump: raise constants.SchemaVersion from %s "+
+			"and run go test ./internal/schema -update", lock.Version)
+	}
+
+	if !changed && lock.Version == constants.SchemaVersion {
+		return
+	}
+
+	if !*update {
+		t.Fatalf("the lock file records schema version %s instead of %s: run go test ./internal/schema -update",
+			lock.Version, constants.SchemaVersion)
+	}
+
+	data, err = json.MarshalIndent(schemaLock{Version: constants.SchemaVersion, Fingerprints: current}, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(lockPath, append(data, '\n'), 0o600))
+}
+
+// TestDocs fails when docs.json differs from the doc comments in the source.
+func TestDocs(t *testing.T) {
+	// Reflect the outputs once to learn which packages and doc comments they use
+	r := newReflector(nil)
+	for _, root := range roots {
+		r.object(root.typ, typeName(root.typ))
+	}
+
+	extracted := make(map[string]string)
+	for pkg := range r.packages {
+		// Types of the standard library, such as xml.Name, keep their schemas undocumented
+		if !strings.HasPrefix(pkg, modulePath) {
+			continue
+		}
+
+		extractDocs(t, filepath.Join("..", "..", filepath.FromSlash(strings.TrimPrefix(pkg, modulePath))), extracted)
+	}
+
+	want := make(map[string]string)
+	for key := range r.described {
+		if doc := extracted[key]; doc != "" {
+			want[key] = doc
+		}
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(want, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile("docs.json", append(data, '\n'), 0o600))
+
+		return
+	}
+
+	var embedded map[string]string
+	require.NoError(t, json.Unmarshal(docsJSON, &embedded))
+	assert.Equal(t, want, embedded, "docs.json is out of date: run go test ./internal/schema -update")
+}
+
+// extractDocs adds the doc comments of the types and struct fields of a package directory.
+func extractDocs(t *testing.T, dir string, docs map[string]string) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := goparser.ParseFile(fset, path, nil, goparser.ParseComments)
+		require.NoError(t, err)
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+
+				group := typeSpec.Doc
+				if group == nil && len(gen.Specs) == 1 {
+					group = gen.Doc
+				}
+
+				key := file.Name.Name + "." + typeSpec.Name.Name
+				addDoc(docs, key, group)
+
+				if st, ok := typeSpec.Type.(*ast.StructType); ok {
+					extractFieldDocs(docs, key, st)
+				}
+			}
+		}
+	}
+}
+
+// extractFieldDocs adds the doc comments of the fields of a struct, including the fields of
+// anonymous structs.
+func extractFieldDocs(docs map[string]string, prefix string, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		group := field.Doc
+		if group == nil {
+			group = field.Comment
+		}
+
+		for _, name := range field.Names {
+			key := prefix + "." + name.Name
+			addDoc(docs, key, group)
+
+			if inner, ok := field.Type.(*ast.StructType); ok {
+				extractFieldDocs(docs, key, inner)
+			}
+		}
+	}
+}
+
+// addDoc adds a doc comment as a single line.
+func addDoc(docs map[string]string, key string, group *ast.CommentGroup) {
+	if group == nil {
+		return
+	}
+
+	if text := strings.Join(strings.Fields(group.Text()), " "); text != "" {
+		docs[key] = text
+	}
+}
+
+// compile compiles a generated schema.
+func compile(t *testing.T, name string) *jsonschema.Schema {
+	t.Helper()
+
+	s, err := Generate(name)
+	require.NoError(t, err)
+
+	content, err := s.Marshal()
+	require.NoError(t, err)
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(content))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource(s.ID, doc))
+
+	compiled, err := compiler.Compile(s.ID)
+	require.NoError(t, err)
+
+	return compiled
+}
+
+// requireValid fails the test unless a JSON output conforms to a schema.
+func requireValid(t *testing.T, compiled *jsonschema.Schema, output string) {
+	t.Helper()
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(output))
+	require.NoError(t, err)
+	require.NoError(t, compiled.Validate(doc))
+}
+
+// load parses a configuration.
+func load(t *testing.T, path string) *model.OpnSenseDocument {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	doc, err := parser.NewXMLParser().Parse(t.Context(), file)
+	require.NoError(t, err)
+
+	return doc
+}
+
+// samples lists the configurations whose outputs are validated against the schemas.
+var samples = []string{ //nolint:gochecknoglobals // Test fixture paths
+	filepath.Join("..", "..", "testdata", "sample.config.2.xml"),
+	filepath.Join("..", "..", "testdata", "sample.config.7.xml"),
+}
+
+func TestDocumentOutput(t *testing.T) {
+	compiled := compile(t, "document")
+
+	generator, err := markdown.NewMarkdownGenerator(log.New(io.Discard))
+	require.NoError(t, err)
+
+	for _, path := range samples {
+		doc := load(t, path)
+
+		output, err := generator.Generate(context.Background(), doc, markdown.Options{Format: markdown.FormatJSON})
+		require.NoError(t, err)
+		requireValid(t, compiled, output)
+
+		output, err = converter.NewJSONConverter().ToJSON(context.Background(), doc)
+		require.NoError(t, err)
+		requireValid(t, compiled, output)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	compiled := compile(t, "report")
+
+	for _, path := range samples {
+		report := processor.NewReport(load(t, path), processor.Config{EnableStats: true})
+		report.AddFinding(processor.SeverityHigh, processor.Finding{
+			Type:      "security",
+			Title:     "Finding",
+			Component: "filter",
+		})
+
+		output, err := report.ToJSON()
+		require.NoError(t, err)
+		requireValid(t, compiled, output)
+	}
+}
+
+func TestAuditOutput(t *testing.T) {
+	compiled := compile(t, "audit")
+
+	controller := audit.NewModeController(audit.NewPluginRegistry(), log.New(io.Discard))
+
+	for _, path := range samples {
+		for _, mode := range []audit.ReportMode{audit.ModeStandard, audit.ModeBlue, audit.ModeRed} {
+			report, err := controller.GenerateReport(context.Background(), load(t, path), &audit.ModeConfig{
+				Mode:          mode,
+				Comprehensive: true,
+			})
+			require.NoError(t, err)
+
+			output, err := report.ToJSON()
+			require.NoError(t, err)
+			requireValid(t, compiled, output)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, name := range Names() {
+		s, err := Generate(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, Draft, s.Schema)
+		assert.Equal(t, "urn:opndossier:schema:"+name+":"+constants.SchemaVersion, s.ID)
+		assert.Equal(t, constants.SchemaVersion, s.Properties["schemaVersion"].Const)
+		assert.Equal(t, "schemaVersion", s.Required[0])
+	}
+
+	_, err := Generate("unknown")
+	require.ErrorIs(t, err, ErrUnknownSchema)
+}
+
+func TestGenerate_Descriptions(t *testing.T) {
+	s, err := Generate("audit")
+	require.NoError(t, err)
+
+	finding := s.Defs["audit.Finding"]
+	require.NotNil(t, finding)
+	assert.Equal(t, "Finding represents a security finding or audit result.", finding.Description)
+	assert.Equal(t, "Fingerprint identifies the finding across runs; waivers and baselines match it.",
+		finding.Properties["fingerprint"].Description)
+}
+
+func TestGenerate_EncodingRules(t *testing.T) {
+	s, err := Generate("document")
+	require.NoError(t, err)
+
+	// Fields of the embedded document are promoted but not required, since the pointer may be nil
+	assert.Contains(t, s.Properties, "system")
+	assert.NotContains(t, s.Required, "system")
+	assert.NotContains(t, s.Properties, "XMLName")
+
+	// Properties without omitempty are required; properties with omitempty are not
+	report, err := Generate("report")
+	require.NoError(t, err)
+	assert.Contains(t, report.Required, "findings")
+	assert.NotContains(t, report.Required, "statistics")
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time", Description: report.Properties["generatedAt"].Description},
+		report.Properties["generatedAt"])
+}
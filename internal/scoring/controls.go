@@ -0,0 +1,137 @@
+package scoring
+
+import "github.com/EvilBit-Labs/opnDossier/internal/model"
+
+// Default catalogue control identifiers.
+const (
+	ControlFirewallRules     = "SCORE-FW-001"
+	ControlNoAnyAnyPass      = "SCORE-FW-002"
+	ControlHTTPSWebGUI       = "SCORE-MGMT-001"
+	ControlNoWANManagement   = "SCORE-MGMT-002"
+	ControlSSHGroup          = "SCORE-MGMT-003"
+	ControlNoDefaultAccounts = "SCORE-ACC-001"
+	ControlHardenedTunables  = "SCORE-SYS-001"
+	ControlWANIngressFilter  = "SCORE-NET-001"
+	ControlSNMPCommunity     = "SCORE-SVC-001"
+	ControlRemoteLogging     = "SCORE-LOG-001"
+)
+
+// Stable references of the processor security checks the default catalogue is keyed on.
+// The processor sets them as the Reference of the findings each check reports.
+const (
+	CheckNoFirewallRules  = "OPN-FW-001"
+	CheckAnyAnyPass       = "OPN-FW-002"
+	CheckInsecureWebGUI   = "OPN-MGMT-001"
+	CheckWANManagement    = "OPN-MGMT-002"
+	CheckSSHGroup         = "OPN-MGMT-003"
+	CheckDefaultAccount   = "OPN-ACC-001"
+	CheckWANIngressFilter = "OPN-NET-001"
+	CheckSNMPCommunity    = "OPN-SVC-001"
+	CheckRemoteLogging    = "OPN-LOG-001"
+)
+
+// FindingTypeHardening is the type of the findings reported for tunables that deviate
+// from the sysctl hardening baseline.
+const FindingTypeHardening = "hardening"
+
+// DefaultControls returns the built-in scoring catalogue. The default weights sum to 100.
+func DefaultControls() []Control {
+	return []Control{
+		{
+			ID:          ControlFirewallRules,
+			Title:       "Firewall rules defined",
+			Category:    "Firewall",
+			Description: "At least one firewall filter rule is configured",
+			Weight:      10,
+			References:  []string{CheckNoFirewallRules},
+		},
+		{
+			ID:          ControlNoAnyAnyPass,
+			Title:       "No any-to-any pass rules",
+			Category:    "Firewall",
+			Description: "Enabled pass rules restrict source or destination",
+			Weight:      15,
+			References:  []string{CheckAnyAnyPass},
+			Applies:     hasFirewallRules,
+		},
+		{
+			ID:          ControlHTTPSWebGUI,
+			Title:       "Web GUI uses HTTPS",
+			Category:    "Management",
+			Description: "The web administration interface is served over HTTPS",
+			Weight:      15,
+			References:  []string{CheckInsecureWebGUI},
+		},
+		{
+			ID:          ControlNoWANManagement,
+			Title:       "No management services exposed on WAN",
+			Category:    "Management",
+			Description: "No WAN rule allows inbound traffic to common management ports",
+			Weight:      15,
+			References:  []string{CheckWANManagement},
+		},
+		{
+			ID:          ControlSSHGroup,
+			Title:       "SSH access restricted to a group",
+			Category:    "Management",
+			Description: "SSH logins are limited to members of a dedicated group",
+			Weight:      5,
+			References:  []string{CheckSSHGroup},
+		},
+		{
+			ID:          ControlNoDefaultAccounts,
+			Title:       "No enabled default accounts",
+			Category:    "Accounts",
+			Description: "No enabled user account uses a default or shared name",
+			Weight:      10,
+			References:  []string{CheckDefaultAccount},
+		},
+		{
+			ID:          ControlHardenedTunables,
+			Title:       "Network tunables hardened",
+			Category:    "System",
+			Description: "Sysctl tunables match the hardening baseline",
+			Weight:      10,
+			Types:       []string{FindingTypeHardening},
+		},
+		{
+			ID:          ControlWANIngressFilter,
+			Title:       "WAN blocks private and bogon networks",
+			Category:    "Network",
+			Description: "The WAN interface blocks RFC 1918 and bogon source addresses",
+			Weight:      10,
+			References:  []string{CheckWANIngressFilter},
+			Applies:     hasWAN,
+		},
+		{
+			ID:          ControlSNMPCommunity,
+			Title:       "SNMP community is not a default",
+			Category:    "Services",
+			Description: "SNMP does not use a well-known read-only community string",
+			Weight:      5,
+			References:  []string{CheckSNMPCommunity},
+			Applies:     hasSNMPCommunity,
+		},
+		{
+			ID:          ControlRemoteLogging,
+			Title:       "Remote logging configured",
+			Category:    "Logging",
+			Description: "Logs are forwarded to at least one remote syslog server",
+			Weight:      5,
+			References:  []string{CheckRemoteLogging},
+		},
+	}
+}
+
+func hasFirewallRules(cfg *model.OpnSenseDocument) (bool, string) {
+	return len(cfg.FilterRules()) > 0, "no firewall rules configured"
+}
+
+func hasWAN(cfg *model.OpnSenseDocument) (bool, string) {
+	_, ok := cfg.Interfaces.Wan()
+	return ok, "no WAN interface configured"
+}
+
+func hasSNMPCommunity(cfg *model.OpnSenseDocument) (bool, string) {
+	return cfg.Snmpd.ROCommunity != "", "SNMP community not configured"
+}
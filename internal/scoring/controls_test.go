@@ -0,0 +1,67 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultControls_KeyedOnFindings(t *testing.T) {
+	cfg := &model.OpnSenseDocument{
+		Filter:     model.Filter{Rule: []model.Rule{{Type: "block"}}},
+		Interfaces: model.Interfaces{Items: map[string]model.Interface{"wan": {}}},
+		Snmpd:      model.Snmpd{ROCommunity: "public"},
+	}
+
+	tests := []struct {
+		control string
+		finding Finding
+	}{
+		{ControlFirewallRules, Finding{Reference: CheckNoFirewallRules}},
+		{ControlNoAnyAnyPass, Finding{Reference: CheckAnyAnyPass}},
+		{ControlHTTPSWebGUI, Finding{Reference: CheckInsecureWebGUI}},
+		{ControlNoWANManagement, Finding{Reference: CheckWANManagement}},
+		{ControlSSHGroup, Finding{Reference: CheckSSHGroup}},
+		{ControlNoDefaultAccounts, Finding{Reference: CheckDefaultAccount}},
+		{ControlHardenedTunables, Finding{Type: FindingTypeHardening, Reference: "net.inet.tcp.blackhole"}},
+		{ControlWANIngressFilter, Finding{Reference: CheckWANIngressFilter}},
+		{ControlSNMPCommunity, Finding{Reference: CheckSNMPCommunity}},
+		{ControlRemoteLogging, Finding{Reference: CheckRemoteLogging}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.control, func(t *testing.T) {
+			breakdown := Default().EvaluateFindings(cfg, []Finding{tt.finding})
+
+			for _, item := range breakdown.Items {
+				want := model.ScoreStatusPass
+				if item.ControlID == tt.control {
+					want = model.ScoreStatusFail
+				}
+
+				assert.Equal(t, want, item.Status, item.ControlID)
+			}
+		})
+	}
+
+	clean := Default().EvaluateFindings(cfg, nil)
+	assert.Equal(t, 100, clean.Score)
+}
+
+func TestDefaultControls_Applicability(t *testing.T) {
+	breakdown := Default().EvaluateFindings(&model.OpnSenseDocument{}, []Finding{{Reference: CheckNoFirewallRules}})
+
+	status := make(map[string]model.ScoreStatus, len(breakdown.Items))
+	for _, item := range breakdown.Items {
+		status[item.ControlID] = item.Status
+	}
+
+	require.Len(t, status, len(DefaultControls()))
+	assert.Equal(t, model.ScoreStatusFail, status[ControlFirewallRules])
+	assert.Equal(t, model.ScoreStatusNotApplicable, status[ControlNoAnyAnyPass])
+	assert.Equal(t, model.ScoreStatusNotApplicable, status[ControlWANIngressFilter])
+	assert.Equal(t, model.ScoreStatusNotApplicable, status[ControlSNMPCommunity])
+	assert.Equal(t, model.ScoreStatusPass, status[ControlRemoteLogging])
+}
@@ -0,0 +1,307 @@
+// Package scoring implements the explainable security score for OPNsense configurations.
+//
+// The score is driven by a catalogue of weighted controls and derived from analysis
+// findings. Each control is keyed on the finding references or types that violate it:
+// a control with matching findings fails (losing its weight), one without passes
+// (earning its weight) and one that does not apply to the configuration is excluded
+// from the total. The final score is the percentage of earned weight over the
+// applicable weight, so every point can be traced back to a control and the findings
+// behind it. Control weights can be overridden and controls disabled through the
+// opnDossier configuration file.
+package scoring
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Error definitions for the scoring engine.
+var (
+	// ErrUnknownControl indicates that an override references a control that is not in the catalogue.
+	ErrUnknownControl = errors.New("unknown scoring control")
+	// ErrInvalidWeight indicates that a control weight is negative.
+	ErrInvalidWeight = errors.New("invalid scoring weight")
+)
+
+// Outcome is the result of evaluating a single control.
+type Outcome struct {
+	Status   model.ScoreStatus
+	Evidence string
+}
+
+// Pass returns a passing outcome with the given evidence.
+func Pass(evidence string) Outcome {
+	return Outcome{Status: model.ScoreStatusPass, Evidence: evidence}
+}
+
+// Fail returns a failing outcome with the given evidence.
+func Fail(evidence string) Outcome {
+	return Outcome{Status: model.ScoreStatusFail, Evidence: evidence}
+}
+
+// NotApplicable returns an outcome excluding the control from the score.
+func NotApplicable(reason string) Outcome {
+	return Outcome{Status: model.ScoreStatusNotApplicable, Evidence: reason}
+}
+
+// Finding is an analysis finding the score is derived from.
+type Finding struct {
+	// Type categorizes the finding, such as "security" or "hardening".
+	Type string
+	// Reference is the stable identifier of the check that reported the finding.
+	Reference string
+	// Title is a brief description of the finding.
+	Title string
+	// Component identifies the configuration component involved.
+	Component string
+}
+
+// Analyzer produces the findings a configuration is scored on.
+type Analyzer func(cfg *model.OpnSenseDocument) []Finding
+
+// ApplyFunc reports whether a control applies to a configuration. When it does not, the
+// returned reason is recorded as the evidence.
+type ApplyFunc func(cfg *model.OpnSenseDocument) (bool, string)
+
+// Control is a weighted scoring control. It fails when any finding carries one of its
+// references or types and passes otherwise.
+type Control struct {
+	ID          string
+	Title       string
+	Category    string
+	Description string
+	Weight      int
+	// References lists the finding references that violate the control.
+	References []string
+	// Types lists the finding types that violate the control.
+	Types []string
+	// Applies limits the control to matching configurations. Nil applies it to all.
+	Applies ApplyFunc
+}
+
+// matches reports whether the finding violates the control.
+func (c Control) matches(finding Finding) bool {
+	return slices.Contains(c.References, finding.Reference) || slices.Contains(c.Types, finding.Type)
+}
+
+// evaluate derives the control outcome from the findings.
+func (c Control) evaluate(cfg *model.OpnSenseDocument, findings []Finding) Outcome {
+	if c.Applies != nil {
+		if ok, reason := c.Applies(cfg); !ok {
+			return NotApplicable(reason)
+		}
+	}
+
+	var evidence []string
+
+	for _, finding := range findings {
+		if !c.matches(finding) {
+			continue
+		}
+
+		item := cmp.Or(finding.Title, finding.Reference, finding.Type)
+		if finding.Component != "" {
+			item += " (" + finding.Component + ")"
+		}
+
+		if !slices.Contains(evidence, item) {
+			evidence = append(evidence, item)
+		}
+	}
+
+	if len(evidence) > 0 {
+		return Fail(strings.Join(evidence, ", "))
+	}
+
+	return Pass("no findings")
+}
+
+// Override adjusts a catalogue control. Nil fields keep the catalogue defaults.
+type Override struct {
+	// ID identifies the control to override (case-insensitive).
+	ID string
+	// Weight replaces the control weight when set.
+	Weight *int
+	// Enabled removes the control from scoring when set to false.
+	Enabled *bool
+}
+
+// Engine evaluates a control catalogue and produces score breakdowns.
+type Engine struct {
+	controls []Control
+	analyzer Analyzer
+}
+
+// Option configures an Engine.
+type Option func(*engineConfig)
+
+type engineConfig struct {
+	controls  []Control
+	overrides []Override
+	analyzer  Analyzer
+}
+
+// WithControls replaces the default catalogue with the given controls.
+func WithControls(controls []Control) Option {
+	return func(c *engineConfig) {
+		c.controls = controls
+	}
+}
+
+// WithOverrides applies weight and enablement overrides to the catalogue.
+func WithOverrides(overrides ...Override) Option {
+	return func(c *engineConfig) {
+		c.overrides = append(c.overrides, overrides...)
+	}
+}
+
+// WithAnalyzer sets the analyzer that produces the findings Evaluate scores a
+// configuration on.
+func WithAnalyzer(analyzer Analyzer) Option {
+	return func(c *engineConfig) {
+		c.analyzer = analyzer
+	}
+}
+
+// NewEngine creates a scoring engine using the default catalogue unless replaced by options.
+// It returns an error if an override references an unknown control or sets a negative weight.
+func NewEngine(opts ...Option) (*Engine, error) {
+	cfg := &engineConfig{controls: DefaultControls()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	controls := make([]Control, len(cfg.controls))
+	copy(controls, cfg.controls)
+
+	for _, control := range controls {
+		if control.Weight < 0 {
+			return nil, fmt.Errorf("%w: control %s has weight %d", ErrInvalidWeight, control.ID, control.Weight)
+		}
+	}
+
+	disabled := make(map[int]bool)
+
+	for _, override := range cfg.overrides {
+		idx := indexOf(controls, override.ID)
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownControl, override.ID)
+		}
+
+		if override.Weight != nil {
+			if *override.Weight < 0 {
+				return nil, fmt.Errorf("%w: control %s has weight %d", ErrInvalidWeight, override.ID, *override.Weight)
+			}
+
+			controls[idx].Weight = *override.Weight
+		}
+
+		if override.Enabled != nil {
+			disabled[idx] = !*override.Enabled
+		}
+	}
+
+	enabled := make([]Control, 0, len(controls))
+
+	for i, control := range controls {
+		if !disabled[i] {
+			enabled = append(enabled, control)
+		}
+	}
+
+	return &Engine{controls: enabled, analyzer: cfg.analyzer}, nil
+}
+
+// Default returns an engine using the default catalogue without overrides or analyzer.
+func Default() *Engine {
+	return &Engine{controls: DefaultControls()}
+}
+
+// Controls returns the controls evaluated by the engine.
+func (e *Engine) Controls() []Control {
+	return e.controls
+}
+
+// Evaluate scores the configuration on the findings produced by the engine's analyzer.
+// Without an analyzer no findings are available and every control is reported as not
+// applicable. A nil configuration yields an empty breakdown with a score of zero.
+func (e *Engine) Evaluate(cfg *model.OpnSenseDocument) *model.ScoreBreakdown {
+	if cfg != nil && e.analyzer == nil {
+		return e.evaluate(func(Control) Outcome { return NotApplicable("no analysis findings available") })
+	}
+
+	var findings []Finding
+	if cfg != nil {
+		findings = e.analyzer(cfg)
+	}
+
+	return e.EvaluateFindings(cfg, findings)
+}
+
+// EvaluateFindings scores the configuration on the given findings and returns the full
+// breakdown. A nil configuration yields an empty breakdown with a score of zero.
+func (e *Engine) EvaluateFindings(cfg *model.OpnSenseDocument, findings []Finding) *model.ScoreBreakdown {
+	if cfg == nil {
+		return &model.ScoreBreakdown{Items: []model.ScoreItem{}}
+	}
+
+	return e.evaluate(func(control Control) Outcome { return control.evaluate(cfg, findings) })
+}
+
+// evaluate builds the breakdown from the outcome of every control.
+func (e *Engine) evaluate(outcomeOf func(Control) Outcome) *model.ScoreBreakdown {
+	breakdown := &model.ScoreBreakdown{Items: make([]model.ScoreItem, 0, len(e.controls))}
+
+	for _, control := range e.controls {
+		outcome := outcomeOf(control)
+
+		item := model.ScoreItem{
+			ControlID: control.ID,
+			Title:     control.Title,
+			Category:  control.Category,
+			Weight:    control.Weight,
+			Status:    outcome.Status,
+			Evidence:  outcome.Evidence,
+		}
+
+		switch outcome.Status {
+		case model.ScoreStatusPass:
+			item.Points = control.Weight
+			breakdown.Earned += control.Weight
+			breakdown.Possible += control.Weight
+		case model.ScoreStatusFail:
+			breakdown.Possible += control.Weight
+		case model.ScoreStatusNotApplicable:
+		}
+
+		breakdown.Items = append(breakdown.Items, item)
+	}
+
+	if breakdown.Possible > 0 {
+		breakdown.Score = (breakdown.Earned*constants.MaxSecurityScore + breakdown.Possible/2) / breakdown.Possible
+	}
+
+	return breakdown
+}
+
+// Score is a convenience wrapper returning only the normalized score.
+func (e *Engine) Score(cfg *model.OpnSenseDocument) int {
+	return e.Evaluate(cfg).Score
+}
+
+// indexOf returns the index of the control with the given ID, or -1.
+func indexOf(controls []Control, id string) int {
+	for i, control := range controls {
+		if strings.EqualFold(control.ID, strings.TrimSpace(id)) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,165 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testControls() []Control {
+	return []Control{
+		{ID: "T-1", Title: "passes", Weight: 30, References: []string{"REF-1"}},
+		{ID: "T-2", Title: "fails", Weight: 10, Types: []string{"bad"}},
+		{ID: "T-3", Title: "skipped", Weight: 60, References: []string{"REF-3"}, Applies: func(*model.OpnSenseDocument) (bool, string) {
+			return false, "n/a"
+		}},
+	}
+}
+
+func testAnalyzer(*model.OpnSenseDocument) []Finding {
+	return []Finding{
+		{Type: "bad", Title: "bad"},
+		{Type: "other", Reference: "REF-3", Title: "ignored while not applicable"},
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestDefaultControls(t *testing.T) {
+	total := 0
+	seen := make(map[string]bool)
+
+	for _, control := range DefaultControls() {
+		assert.False(t, seen[control.ID], "duplicate control %s", control.ID)
+		seen[control.ID] = true
+
+		assert.NotEmpty(t, control.Title)
+		assert.NotEmpty(t, control.Category)
+		assert.True(t, len(control.References) > 0 || len(control.Types) > 0, "control %s matches no findings", control.ID)
+
+		total += control.Weight
+	}
+
+	assert.Equal(t, 100, total, "default weights should sum to 100")
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	engine, err := NewEngine(WithControls(testControls()), WithAnalyzer(testAnalyzer))
+	require.NoError(t, err)
+
+	breakdown := engine.Evaluate(&model.OpnSenseDocument{})
+
+	assert.Equal(t, 30, breakdown.Earned)
+	assert.Equal(t, 40, breakdown.Possible)
+	assert.Equal(t, 75, breakdown.Score)
+	require.Len(t, breakdown.Items, 3)
+
+	assert.Equal(t, model.ScoreItem{
+		ControlID: "T-2",
+		Title:     "fails",
+		Weight:    10,
+		Points:    0,
+		Status:    model.ScoreStatusFail,
+		Evidence:  "bad",
+	}, breakdown.Items[1])
+	assert.Equal(t, "no findings", breakdown.Items[0].Evidence)
+	assert.Equal(t, model.ScoreStatusNotApplicable, breakdown.Items[2].Status)
+}
+
+func TestEngine_EvaluateFindings(t *testing.T) {
+	engine, err := NewEngine(WithControls(testControls()))
+	require.NoError(t, err)
+
+	breakdown := engine.EvaluateFindings(&model.OpnSenseDocument{}, []Finding{
+		{Reference: "REF-1", Title: "First", Component: "filter.rule[0]"},
+		{Reference: "REF-1", Title: "First", Component: "filter.rule[2]"},
+		{Reference: "REF-1", Title: "First", Component: "filter.rule[2]"},
+	})
+
+	assert.Equal(t, model.ScoreStatusFail, breakdown.Items[0].Status)
+	assert.Equal(t, "First (filter.rule[0]), First (filter.rule[2])", breakdown.Items[0].Evidence)
+	assert.Equal(t, model.ScoreStatusPass, breakdown.Items[1].Status)
+	assert.Equal(t, 25, breakdown.Score)
+}
+
+func TestEngine_EvaluateWithoutAnalyzer(t *testing.T) {
+	breakdown := Default().Evaluate(&model.OpnSenseDocument{})
+
+	require.Len(t, breakdown.Items, len(DefaultControls()))
+	assert.Equal(t, 0, breakdown.Possible)
+	assert.Equal(t, 0, breakdown.Score)
+
+	for _, item := range breakdown.Items {
+		assert.Equal(t, model.ScoreStatusNotApplicable, item.Status, item.ControlID)
+	}
+}
+
+func TestEngine_EvaluateNil(t *testing.T) {
+	breakdown := Default().Evaluate(nil)
+	assert.Equal(t, 0, breakdown.Score)
+	assert.Empty(t, breakdown.Items)
+}
+
+func TestEngine_EvaluateNoApplicableControls(t *testing.T) {
+	engine, err := NewEngine(WithControls(testControls()[2:]), WithAnalyzer(testAnalyzer))
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, engine.Score(&model.OpnSenseDocument{}))
+}
+
+func TestNewEngine_Overrides(t *testing.T) {
+	engine, err := NewEngine(
+		WithControls(testControls()),
+		WithOverrides(
+			Override{ID: "t-2", Weight: intPtr(90)},
+			Override{ID: "T-3", Enabled: boolPtr(false)},
+		),
+		WithAnalyzer(testAnalyzer),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, engine.Controls(), 2)
+
+	breakdown := engine.Evaluate(&model.OpnSenseDocument{})
+	assert.Equal(t, 30, breakdown.Earned)
+	assert.Equal(t, 120, breakdown.Possible)
+	assert.Equal(t, 25, breakdown.Score)
+
+	// Overrides must not leak into the default catalogue
+	assert.Equal(t, 10, testControls()[1].Weight)
+}
+
+func TestNewEngine_Errors(t *testing.T) {
+	_, err := NewEngine(WithOverrides(Override{ID: "SCORE-DOES-NOT-EXIST", Weight: intPtr(1)}))
+	require.ErrorIs(t, err, ErrUnknownControl)
+
+	_, err = NewEngine(WithOverrides(Override{ID: ControlHTTPSWebGUI, Weight: intPtr(-1)}))
+	require.ErrorIs(t, err, ErrInvalidWeight)
+
+	_, err = NewEngine(WithControls([]Control{{ID: "X", Weight: -5}}))
+	require.ErrorIs(t, err, ErrInvalidWeight)
+}
+
+func TestBreakdownTable(t *testing.T) {
+	engine, err := NewEngine(WithControls(testControls()), WithAnalyzer(testAnalyzer))
+	require.NoError(t, err)
+
+	table := BreakdownTable(engine.Evaluate(&model.OpnSenseDocument{}))
+	assert.Equal(t, []string{"Control", "Title", "Category", "Status", "Points", "Evidence"}, table.Header)
+	require.Len(t, table.Rows, 3)
+	assert.Equal(t, []string{"T-1", "passes", "", "Pass", "30/30", "no findings"}, table.Rows[0])
+	assert.Equal(t, []string{"T-2", "fails", "", "Fail", "0/10", "bad"}, table.Rows[1])
+	assert.Equal(t, []string{"T-3", "skipped", "", "N/A", "-", "n/a"}, table.Rows[2])
+
+	assert.Empty(t, BreakdownTable(nil).Rows)
+	assert.Equal(t, "0/100", Summary(nil))
+	assert.Equal(t, "75/100 (30 of 40 points)", Summary(engine.Evaluate(&model.OpnSenseDocument{})))
+}
@@ -0,0 +1,75 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/nao1215/markdown"
+)
+
+// BreakdownTable returns a table listing the contribution of every control to the score.
+func BreakdownTable(breakdown *model.ScoreBreakdown) markdown.TableSet {
+	table := markdown.TableSet{
+		Header: []string{"Control", "Title", "Category", "Status", "Points", "Evidence"},
+		Rows:   [][]string{},
+	}
+
+	if breakdown == nil {
+		return table
+	}
+
+	for _, item := range breakdown.Items {
+		points := fmt.Sprintf("%d/%d", item.Points, item.Weight)
+		if item.Status == model.ScoreStatusNotApplicable {
+			points = "-"
+		}
+
+		table.Rows = append(table.Rows, []string{
+			item.ControlID,
+			item.Title,
+			item.Category,
+			StatusLabel(item.Status),
+			points,
+			escapeCell(item.Evidence),
+		})
+	}
+
+	return table
+}
+
+// StatusLabel returns the display label for a score status.
+func StatusLabel(status model.ScoreStatus) string {
+	switch status {
+	case model.ScoreStatusPass:
+		return "Pass"
+	case model.ScoreStatusFail:
+		return "Fail"
+	case model.ScoreStatusNotApplicable:
+		return "N/A"
+	default:
+		return string(status)
+	}
+}
+
+// Summary returns a one-line description of the score, e.g. "72/100 (65 of 90 points)".
+func Summary(breakdown *model.ScoreBreakdown) string {
+	if breakdown == nil {
+		return fmt.Sprintf("0/%d", constants.MaxSecurityScore)
+	}
+
+	return fmt.Sprintf(
+		"%d/%d (%d of %d points)",
+		breakdown.Score,
+		constants.MaxSecurityScore,
+		breakdown.Earned,
+		breakdown.Possible,
+	)
+}
+
+// escapeCell escapes characters that would break a markdown table cell.
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,356 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/constants"
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/nao1215/markdown"
+)
+
+// Relative links from the rule pages to the top-level pages.
+const parentDir = "../"
+
+// index renders the index page with the system summary and links to every page.
+func (g *generator) index(name string) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	system := g.doc.System
+
+	md.H1(name)
+	md.PlainTextf("- **Hostname**: %s", system.Hostname)
+	md.PlainTextf("- **Domain**: %s", system.Domain)
+	md.PlainTextf("- **Platform**: OPNsense %s", system.Firmware.Version)
+	md.PlainTextf("- **Parsed By**: opnDossier v%s", constants.Version)
+
+	md.H2("Contents")
+	md.PlainText("- " + markdown.Link("System", PageSystem) + ": settings, users, groups and tunables")
+	md.PlainText("- " + markdown.Link("Interfaces", PageInterfaces) + ": interfaces, addressing and topology")
+	md.PlainText("- " + markdown.Link("NAT", PageNAT) + ": port forwards and outbound NAT")
+	md.PlainText("- " + markdown.Link("VPN", PageVPN) + ": OpenVPN and WireGuard")
+	md.PlainText("- " + markdown.Link("Services", PageServices) + ": DHCP, DNS, SNMP and NTP")
+	md.PlainText("- " + markdown.Link("Findings", PageFindings) + ": security findings")
+
+	g.ruleIndex(md)
+
+	return md.String()
+}
+
+// ruleIndex lists the rule pages with the number of rules on each interface.
+func (g *generator) ruleIndex(md *markdown.Markdown) {
+	md.H2("Firewall Rules by Interface")
+
+	names := g.ruleInterfaces()
+	if len(names) == 0 {
+		md.PlainText("No firewall rules configured.")
+		return
+	}
+
+	for _, name := range names {
+		count := len(g.rules[name])
+
+		noun := "rules"
+		if count == 1 {
+			noun = "rule"
+		}
+
+		md.PlainTextf("- %s: %d %s", markdown.Link(g.interfaceLabel(name), rulePage(name)), count, noun)
+	}
+}
+
+// system renders the system settings, users, groups and tunables.
+func (g *generator) system() string {
+	return page("System", g.builder.BuildSystemSection(g.doc))
+}
+
+// interfaces renders the interfaces with a section per interface that rule tables link to.
+func (g *generator) interfaces() string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.H1("Interfaces")
+	md.PlainText(g.builder.BuildNetworkSection(g.doc))
+	g.ruleIndex(md)
+
+	return md.String()
+}
+
+// rulePage renders the firewall rules of an interface in evaluation order.
+func (g *generator) rulePage(name string) string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.H1("Firewall Rules: " + g.interfaceLabel(name))
+
+	if name == floatingRules {
+		md.PlainText("Rules that are not bound to an interface, in evaluation order.")
+	} else {
+		md.PlainTextf("Rules of the %s interface, in evaluation order.",
+			converter.FormatInterfaceLinks(model.InterfaceList{name}, parentDir+PageInterfaces))
+	}
+
+	md.Table(*g.builder.BuildLinkedFirewallRulesTable(g.rules[name], parentDir+PageInterfaces))
+	md.PlainText(markdown.Link("Back to the overview", parentDir+PageIndex))
+
+	return md.String()
+}
+
+// nat renders the NAT mode, port forwards and outbound NAT rules.
+func (g *generator) nat() string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	summary := g.doc.NATSummary()
+
+	md.H1("NAT")
+
+	if summary.Mode != "" {
+		md.PlainTextf("%s: %s", markdown.Bold("Outbound NAT Mode"), summary.Mode)
+	}
+
+	md.PlainTextf("%s: %s", markdown.Bold("NAT Reflection"), enabled(!summary.ReflectionDisabled))
+
+	md.H2("Port Forwards")
+
+	if len(g.doc.Nat.Inbound) == 0 {
+		md.PlainText("No port forwards configured.")
+	} else {
+		table := markdown.TableSet{Header: []string{
+			"#", "Interface", "Proto", "Source", "Destination", "External Port", "Target", "Target Port",
+			"Enabled", "Description",
+		}}
+
+		for i, rule := range g.doc.Nat.Inbound {
+			table.Rows = append(table.Rows, []string{
+				strconv.Itoa(i + 1),
+				converter.FormatInterfaceLinks(rule.Interface, PageInterfaces),
+				rule.Protocol,
+				rule.Source.String(),
+				rule.Destination.String(),
+				firstNonEmpty(rule.ExternalPort, rule.Destination.Port),
+				rule.InternalIP,
+				rule.InternalPort,
+				enabled(rule.Disabled == ""),
+				g.builder.EscapeTableContent(rule.Descr),
+			})
+		}
+
+		md.Table(table)
+	}
+
+	md.H2("Outbound NAT Rules")
+
+	if len(g.doc.Nat.Outbound.Rule) == 0 {
+		md.PlainText("No outbound NAT rules configured.")
+	} else {
+		table := markdown.TableSet{Header: []string{
+			"#", "Interface", "Proto", "Source", "Source Port", "Destination", "Destination Port",
+			"Translation", "Enabled", "Description",
+		}}
+
+		for i, rule := range g.doc.Nat.Outbound.Rule {
+			table.Rows = append(table.Rows, []string{
+				strconv.Itoa(i + 1),
+				converter.FormatInterfaceLinks(rule.Interface, PageInterfaces),
+				rule.Protocol,
+				rule.Source.String(),
+				firstNonEmpty(rule.Source.Port, rule.SourcePort),
+				rule.Destination.String(),
+				rule.Destination.Port,
+				firstNonEmpty(rule.Target, "interface address"),
+				enabled(rule.Disabled == ""),
+				g.builder.EscapeTableContent(rule.Descr),
+			})
+		}
+
+		md.Table(table)
+	}
+
+	return md.String()
+}
+
+// vpn renders the OpenVPN instances and the WireGuard instances and peers.
+func (g *generator) vpn() string {
+	var buf bytes.Buffer
+	md := markdown.NewMarkdown(&buf)
+
+	md.H1("VPN")
+	g.openVPN(md)
+	g.wireGuard(md)
+
+	return md.String()
+}
+
+// openVPN renders the OpenVPN servers and clients.
+func (g *generator) openVPN(md *markdown.Markdown) {
+	md.H2("OpenVPN Servers")
+
+	if len(g.doc.OpenVPN.Servers) == 0 {
+		md.PlainText("No OpenVPN servers configured.")
+	} else {
+		table := markdown.TableSet{Header: []string{
+			"Description", "Mode", "Protocol", "Interface", "Port", "Tunnel Network", "Local Network",
+		}}
+
+		for _, server := range g.doc.OpenVPN.Servers {
+			table.Rows = append(table.Rows, []string{
+				g.builder.EscapeTableContent(server.Description),
+				server.Mode,
+				server.Protocol,
+				g.interfaceLink(server.Interface),
+				server.Local_port,
+				server.Tunnel_network,
+				server.Local_network,
+			})
+		}
+
+		md.Table(table)
+	}
+
+	md.H2("OpenVPN Clients")
+
+	if len(g.doc.OpenVPN.Clients) == 0 {
+		md.PlainText("No OpenVPN clients configured.")
+		return
+	}
+
+	table := markdown.TableSet{Header: []string{"Description", "Mode", "Protocol", "Interface", "Server"}}
+
+	for _, client := range g.doc.OpenVPN.Clients {
+		table.Rows = append(table.Rows, []string{
+			g.builder.EscapeTableContent(client.Description),
+			client.Mode,
+			client.Protocol,
+			g.interfaceLink(client.Interface),
+			endpoint(client.Server_addr, client.Server_port),
+		})
+	}
+
+	md.Table(table)
+}
+
+// wireGuard renders the WireGuard instances and peers.
+func (g *generator) wireGuard(md *markdown.Markdown) {
+	md.H2("WireGuard")
+
+	wg := g.doc.OPNsense.Wireguard
+	if wg == nil || len(wg.Server.Servers.Server)+len(wg.Client.Clients.Client) == 0 {
+		md.PlainText("No WireGuard instances configured.")
+		return
+	}
+
+	md.PlainTextf("%s: %s", markdown.Bold("Enabled"), enabled(wg.General.Enabled == "1"))
+
+	md.H3("Instances")
+
+	table := markdown.TableSet{Header: []string{"Name", "Enabled", "Port", "Tunnel Address", "Peers"}}
+
+	for _, server := range wg.Server.Servers.Server {
+		table.Rows = append(table.Rows, []string{
+			g.builder.EscapeTableContent(server.Name),
+			enabled(server.Enabled == "1"),
+			server.Port,
+			server.Tunneladdress,
+			strconv.Itoa(countList(server.Peers)),
+		})
+	}
+
+	md.Table(table)
+
+	md.H3("Peers")
+
+	table = markdown.TableSet{Header: []string{"Name", "Enabled", "Tunnel Address", "Endpoint", "Keepalive"}}
+
+	for _, client := range wg.Client.Clients.Client {
+		table.Rows = append(table.Rows, []string{
+			g.builder.EscapeTableContent(client.Name),
+			enabled(client.Enabled == "1"),
+			client.Tunneladdress,
+			endpoint(client.Serveraddress, client.Serverport),
+			client.Keepalive,
+		})
+	}
+
+	md.Table(table)
+}
+
+// services renders the DHCP, DNS, SNMP and NTP services.
+func (g *generator) services() string {
+	return page("Services", g.builder.BuildServicesSection(g.doc))
+}
+
+// findings renders the audit findings or, without them, the security score and tunable baseline.
+func (g *generator) findings(findings string) string {
+	if findings != "" {
+		return page("Findings", findings)
+	}
+
+	return page("Findings",
+		g.builder.BuildSecurityScoreSection(g.doc)+"\n"+g.builder.BuildTunableBaselineSection(g.doc)+
+			"\nRun with an audit mode for the full list of findings.\n")
+}
+
+// interfaceLink returns a link to the section of an interface on the interfaces page.
+func (g *generator) interfaceLink(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return converter.FormatInterfaceLinks(model.InterfaceList{name}, PageInterfaces)
+}
+
+// page returns a page with a title followed by rendered sections.
+func page(title, content string) string {
+	return "# " + title + "\n\n" + strings.TrimLeft(content, "\n")
+}
+
+// enabled formats a boolean setting.
+func enabled(value bool) string {
+	if value {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+// endpoint formats an address and an optional port.
+func endpoint(address, port string) string {
+	if address == "" || port == "" {
+		return address
+	}
+
+	if strings.Contains(address, ":") {
+		return fmt.Sprintf("[%s]:%s", address, port)
+	}
+
+	return address + ":" + port
+}
+
+// countList returns the number of items in a comma-separated list.
+func countList(list string) int {
+	count := 0
+
+	for item := range strings.SplitSeq(list, ",") {
+		if strings.TrimSpace(item) != "" {
+			count++
+		}
+	}
+
+	return count
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
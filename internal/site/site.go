@@ -0,0 +1,248 @@
+// Package site generates a documentation site from a configuration: a directory of markdown pages
+// for the system, interfaces, per-interface firewall rules, NAT, VPN, services and findings, with an
+// index page and relative links between the pages. An optional mkdocs.yml with the navigation turns
+// the directory into an MkDocs project.
+package site
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// Paths of the pages, relative to the directory of the pages.
+const (
+	PageIndex      = "index.md"
+	PageSystem     = "system.md"
+	PageInterfaces = "interfaces.md"
+	PageNAT        = "nat.md"
+	PageVPN        = "vpn.md"
+	PageServices   = "services.md"
+	PageFindings   = "findings.md"
+)
+
+const (
+	// MkDocsConfig is the name of the MkDocs configuration file.
+	MkDocsConfig = "mkdocs.yml"
+	// mkDocsDir is the directory of the pages in MkDocs projects.
+	mkDocsDir = "docs"
+	// rulesDir is the directory of the per-interface rule pages.
+	rulesDir = "rules"
+	// rulesSection is the navigation section of the rule pages.
+	rulesSection = "Firewall Rules"
+	// floatingRules names the page of the rules without an interface.
+	floatingRules = "floating"
+)
+
+// Page is a markdown page of a site.
+type Page struct {
+	// Path is the slash-separated path of the page relative to the directory of the pages.
+	Path string
+	// Title is the title of the page in the navigation.
+	Title string
+	// Section groups pages in the navigation, such as the rule pages.
+	Section string
+	Content string
+}
+
+// File is a file of a site.
+type File struct {
+	// Path is the slash-separated path of the file relative to the site directory.
+	Path    string
+	Content string
+}
+
+// Options configures the generated site.
+type Options struct {
+	// Findings is the markdown of the audit findings. Without findings, the findings page shows the
+	// security score and the sysctl hardening baseline.
+	Findings string
+	// MkDocs places the pages in a docs directory next to an mkdocs.yml with the navigation.
+	MkDocs bool
+}
+
+// Site is a generated documentation site.
+type Site struct {
+	// Name is the name of the site, the fully qualified hostname of the firewall.
+	Name  string
+	Pages []Page
+
+	mkdocs bool
+}
+
+// Build generates the documentation site of a configuration. The pages are rendered with the
+// programmatic markdown builder, so they match the sections of the single-file report.
+func Build(doc *model.OpnSenseDocument, builder *converter.MarkdownBuilder, opts Options) (*Site, error) {
+	if doc == nil {
+		return nil, converter.ErrNilOpnSenseDocument
+	}
+
+	if builder == nil {
+		builder = converter.NewMarkdownBuilder()
+	}
+
+	g := &generator{doc: doc, builder: builder, rules: groupRules(doc.FilterRules())}
+
+	site := &Site{Name: siteName(doc), mkdocs: opts.MkDocs}
+	site.Pages = append(site.Pages,
+		Page{Path: PageIndex, Title: "Overview", Content: g.index(site.Name)},
+		Page{Path: PageSystem, Title: "System", Content: g.system()},
+		Page{Path: PageInterfaces, Title: "Interfaces", Content: g.interfaces()},
+	)
+
+	for _, name := range g.ruleInterfaces() {
+		site.Pages = append(site.Pages, Page{
+			Path:    rulePage(name),
+			Title:   g.interfaceLabel(name),
+			Section: rulesSection,
+			Content: g.rulePage(name),
+		})
+	}
+
+	site.Pages = append(site.Pages,
+		Page{Path: PageNAT, Title: "NAT", Content: g.nat()},
+		Page{Path: PageVPN, Title: "VPN", Content: g.vpn()},
+		Page{Path: PageServices, Title: "Services", Content: g.services()},
+		Page{Path: PageFindings, Title: "Findings", Content: g.findings(opts.Findings)},
+	)
+
+	return site, nil
+}
+
+// Files returns the files of the site: the pages and, for MkDocs projects, the configuration.
+func (s *Site) Files() []File {
+	files := make([]File, 0, len(s.Pages)+1)
+
+	dir := ""
+	if s.mkdocs {
+		dir = mkDocsDir
+		files = append(files, File{Path: MkDocsConfig, Content: s.MkDocs()})
+	}
+
+	for _, page := range s.Pages {
+		files = append(files, File{Path: path.Join(dir, page.Path), Content: page.Content})
+	}
+
+	return files
+}
+
+// MkDocs returns an mkdocs.yml with the navigation of the site.
+func (s *Site) MkDocs() string {
+	var b strings.Builder
+
+	b.WriteString("site_name: " + strconv.Quote(s.Name) + "\n")
+	b.WriteString("docs_dir: " + mkDocsDir + "\n")
+	b.WriteString("nav:\n")
+
+	section := ""
+
+	for _, page := range s.Pages {
+		if page.Section == "" {
+			section = ""
+			b.WriteString("  - " + strconv.Quote(page.Title) + ": " + page.Path + "\n")
+
+			continue
+		}
+
+		if page.Section != section {
+			section = page.Section
+			b.WriteString("  - " + strconv.Quote(section) + ":\n")
+		}
+
+		b.WriteString("      - " + strconv.Quote(page.Title) + ": " + page.Path + "\n")
+	}
+
+	return b.String()
+}
+
+// siteName returns the fully qualified hostname of the firewall.
+func siteName(doc *model.OpnSenseDocument) string {
+	name := strings.Trim(doc.System.Hostname+"."+doc.System.Domain, ".")
+	if name == "" {
+		return "OPNsense"
+	}
+
+	return name
+}
+
+// groupRules groups firewall rules by interface in evaluation order. Rules on several interfaces
+// are listed for each of them; rules without an interface are grouped as floating rules.
+func groupRules(rules []model.Rule) map[string][]model.Rule {
+	groups := make(map[string][]model.Rule)
+
+	for _, rule := range rules {
+		if rule.Interface.IsEmpty() {
+			groups[floatingRules] = append(groups[floatingRules], rule)
+			continue
+		}
+
+		for _, name := range rule.Interface {
+			groups[name] = append(groups[name], rule)
+		}
+	}
+
+	return groups
+}
+
+// rulePage returns the path of the rule page of an interface.
+func rulePage(name string) string {
+	return rulesDir + "/" + slug(name) + ".md"
+}
+
+// slug returns a file name for an interface name, keeping only lower-case letters, digits, dashes
+// and underscores.
+func slug(name string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	if s := strings.Trim(b.String(), "-"); s != "" {
+		return s
+	}
+
+	return "interface"
+}
+
+// generator renders the pages of a site.
+type generator struct {
+	doc     *model.OpnSenseDocument
+	builder *converter.MarkdownBuilder
+	rules   map[string][]model.Rule
+}
+
+// ruleInterfaces returns the interfaces with rule pages, sorted by name with the floating rules
+// last.
+func (g *generator) ruleInterfaces() []string {
+	names := slices.Sorted(maps.Keys(g.rules))
+
+	if i := slices.Index(names, floatingRules); i >= 0 {
+		names = append(slices.Delete(names, i, i+1), floatingRules)
+	}
+
+	return names
+}
+
+// interfaceLabel returns the description and name of an interface, such as "LAN (lan)".
+func (g *generator) interfaceLabel(name string) string {
+	if name == floatingRules {
+		return "Floating"
+	}
+
+	if iface, ok := g.doc.Interfaces.Items[name]; ok && iface.Descr != "" && !strings.EqualFold(iface.Descr, name) {
+		return iface.Descr + " (" + name + ")"
+	}
+
+	return name
+}
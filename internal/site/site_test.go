@@ -0,0 +1,154 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/converter"
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSample parses a configuration from the testdata directory.
+func loadSample(t *testing.T, name string) *model.OpnSenseDocument {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("..", "..", "testdata", name))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	doc, err := parser.NewXMLParser().Parse(t.Context(), file)
+	require.NoError(t, err)
+
+	return doc
+}
+
+// pages returns the pages of a site by path.
+func pages(s *Site) map[string]Page {
+	byPath := make(map[string]Page, len(s.Pages))
+	for _, page := range s.Pages {
+		byPath[page.Path] = page
+	}
+
+	return byPath
+}
+
+func TestBuild(t *testing.T) {
+	doc := loadSample(t, "sample.config.2.xml")
+
+	s, err := Build(doc, converter.NewMarkdownBuilder(), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "firewall.example.com", s.Name)
+
+	byPath := pages(s)
+	for _, path := range []string{
+		PageIndex, PageSystem, PageInterfaces, PageNAT, PageVPN, PageServices, PageFindings,
+		"rules/lan.md", "rules/wan.md", "rules/opt0.md",
+	} {
+		assert.Contains(t, byPath, path)
+	}
+
+	index := byPath[PageIndex].Content
+	assert.Contains(t, index, "# firewall.example.com")
+	assert.Contains(t, index, "[Interfaces](interfaces.md)")
+	assert.Contains(t, index, "[Workstations (lan)](rules/lan.md): 2 rules")
+	assert.Contains(t, index, "[wan](rules/wan.md): 1 rule")
+	assert.NotContains(t, index, "[wan](rules/wan.md): 1 rules")
+
+	// Interface names in rule tables link to the interface sections
+	lan := byPath["rules/lan.md"]
+	assert.Equal(t, rulesSection, lan.Section)
+	assert.Contains(t, lan.Content, "[lan](../interfaces.md#lan-interface)")
+	assert.Contains(t, lan.Content, "Default allow LAN to any rule")
+	assert.Contains(t, byPath[PageInterfaces].Content, "### Lan Interface")
+
+	assert.Contains(t, byPath[PageVPN].Content, "WGBootstrap")
+	assert.Contains(t, byPath[PageFindings].Content, "Security Score")
+}
+
+func TestBuild_Findings(t *testing.T) {
+	doc := loadSample(t, "sample.config.2.xml")
+
+	s, err := Build(doc, nil, Options{Findings: "## Audit Findings Summary\n"})
+	require.NoError(t, err)
+
+	findings := pages(s)[PageFindings].Content
+	assert.Contains(t, findings, "## Audit Findings Summary")
+	assert.NotContains(t, findings, "Security Score")
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	doc := loadSample(t, "sample.config.2.xml")
+
+	first, err := Build(doc, nil, Options{MkDocs: true})
+	require.NoError(t, err)
+	second, err := Build(doc, nil, Options{MkDocs: true})
+	require.NoError(t, err)
+
+	// The interfaces page includes the interface sections in name order
+	assert.Equal(t, first.Files(), second.Files())
+}
+
+func TestBuild_FloatingRules(t *testing.T) {
+	doc := &model.OpnSenseDocument{}
+	doc.Filter.Rule = []model.Rule{
+		{Type: "pass", Interface: model.InterfaceList{"lan", "opt1"}, Descr: "shared"},
+		{Type: "block", Descr: "floating"},
+	}
+
+	s, err := Build(doc, nil, Options{})
+	require.NoError(t, err)
+
+	byPath := pages(s)
+	assert.Contains(t, byPath["rules/lan.md"].Content, "shared")
+	assert.Contains(t, byPath["rules/opt1.md"].Content, "shared")
+	assert.Contains(t, byPath["rules/floating.md"].Content, "Rules that are not bound to an interface")
+	assert.Equal(t, "OPNsense", s.Name)
+
+	// Floating rules are listed after the interfaces
+	assert.Equal(t, "rules/floating.md", s.Pages[len(s.Pages)-5].Path)
+}
+
+func TestBuild_Nil(t *testing.T) {
+	_, err := Build(nil, nil, Options{})
+	require.ErrorIs(t, err, converter.ErrNilOpnSenseDocument)
+}
+
+func TestFiles_MkDocs(t *testing.T) {
+	doc := loadSample(t, "sample.config.2.xml")
+
+	s, err := Build(doc, nil, Options{})
+	require.NoError(t, err)
+
+	for _, file := range s.Files() {
+		assert.NotEqual(t, MkDocsConfig, file.Path)
+		assert.False(t, strings.HasPrefix(file.Path, "docs/"), file.Path)
+	}
+
+	s, err = Build(doc, nil, Options{MkDocs: true})
+	require.NoError(t, err)
+
+	files := s.Files()
+	assert.Equal(t, MkDocsConfig, files[0].Path)
+	assert.Equal(t, "docs/index.md", files[1].Path)
+
+	config := files[0].Content
+	assert.Contains(t, config, `site_name: "firewall.example.com"`)
+	assert.Contains(t, config, "docs_dir: docs\n")
+	assert.Contains(t, config, `  - "Overview": index.md`)
+	assert.Contains(t, config, "  - \"Firewall Rules\":\n      - \"Workstations (lan)\": rules/lan.md\n")
+	assert.Contains(t, config, `  - "NAT": nat.md`)
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "lan", slug("LAN"))
+	assert.Equal(t, "opt1", slug("opt1"))
+	assert.Equal(t, "etc-passwd", slug("../etc/passwd"))
+	assert.Equal(t, "interface", slug("../"))
+}
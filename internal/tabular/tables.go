@@ -0,0 +1,281 @@
+package tabular
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// FromDocument returns the configuration tables of a document in export order: rules, nat,
+// interfaces, users, dhcp-leases and sysctl. The findings table is built from an audit report.
+func FromDocument(doc *model.OpnSenseDocument) []Table {
+	if doc == nil {
+		return nil
+	}
+
+	return []Table{
+		FirewallRules(doc.FilterRules()),
+		NAT(doc.Nat),
+		Interfaces(doc.Interfaces),
+		Users(doc.System.User),
+		DHCPLeases(doc.Dhcpd),
+		Sysctl(doc.Sysctl),
+	}
+}
+
+// FirewallRules returns the rules table. Rules keep their evaluation order; the hit columns are
+// empty unless the configuration was enriched with filterlog data.
+func FirewallRules(rules []model.Rule) Table {
+	table := Table{
+		Name: TableRules,
+		Columns: []string{
+			"Index", "UUID", "Interface", "Direction", "Action", "Quick", "IP Version", "Protocol",
+			"Source", "Source Port", "Destination", "Destination Port", "Target", "Log", "Enabled",
+			"Description", "Hits", "First Seen", "Last Seen", "Top Talkers",
+		},
+		Rows: make([][]string, 0, len(rules)),
+	}
+
+	for i, rule := range rules {
+		row := []string{
+			strconv.Itoa(i + 1),
+			rule.UUID,
+			rule.Interface.String(),
+			rule.Direction,
+			rule.Type,
+			formatBool(rule.Quick != ""),
+			rule.IPProtocol,
+			rule.Protocol,
+			rule.Source.String(),
+			firstNonEmpty(rule.Source.Port, rule.SourcePort),
+			rule.Destination.String(),
+			rule.Destination.Port,
+			rule.Target,
+			formatBool(bool(rule.Log)),
+			formatBool(rule.Disabled == ""),
+			rule.Descr,
+		}
+
+		if rule.Hits != nil {
+			row = append(row,
+				strconv.Itoa(rule.Hits.Count),
+				FormatTime(rule.Hits.FirstSeen),
+				FormatTime(rule.Hits.LastSeen),
+				FormatTalkers(rule.Hits.TopTalkers),
+			)
+		} else {
+			row = append(row, "", "", "", "")
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+// NAT returns the NAT table with the port forwards followed by the outbound rules. The Target and
+// Target Port columns hold the redirect target of port forwards and the translation address of
+// outbound rules.
+func NAT(nat model.Nat) Table {
+	table := Table{
+		Name: TableNAT,
+		Columns: []string{
+			"Type", "Index", "UUID", "Interface", "IP Version", "Protocol", "Source", "Source Port",
+			"Destination", "Destination Port", "Target", "Target Port", "Enabled", "Description",
+		},
+		Rows: make([][]string, 0, len(nat.Inbound)+len(nat.Outbound.Rule)),
+	}
+
+	for i, rule := range nat.Inbound {
+		table.Rows = append(table.Rows, []string{
+			"port-forward",
+			strconv.Itoa(i + 1),
+			rule.UUID,
+			rule.Interface.String(),
+			rule.IPProtocol,
+			rule.Protocol,
+			rule.Source.String(),
+			"",
+			rule.Destination.String(),
+			firstNonEmpty(rule.ExternalPort, rule.Destination.Port),
+			rule.InternalIP,
+			rule.InternalPort,
+			formatBool(rule.Disabled == ""),
+			rule.Descr,
+		})
+	}
+
+	for i, rule := range nat.Outbound.Rule {
+		table.Rows = append(table.Rows, []string{
+			"outbound",
+			strconv.Itoa(i + 1),
+			rule.UUID,
+			rule.Interface.String(),
+			rule.IPProtocol,
+			rule.Protocol,
+			rule.Source.String(),
+			firstNonEmpty(rule.Source.Port, rule.SourcePort),
+			rule.Destination.String(),
+			rule.Destination.Port,
+			rule.Target,
+			"",
+			formatBool(rule.Disabled == ""),
+			rule.Descr,
+		})
+	}
+
+	return table
+}
+
+// Interfaces returns the interfaces table, sorted by interface name.
+func Interfaces(interfaces model.Interfaces) Table {
+	names := interfaces.Names()
+	slices.Sort(names)
+
+	table := Table{
+		Name: TableInterfaces,
+		Columns: []string{
+			"Name", "Device", "Description", "Enabled", "IPv4 Address", "IPv4 Subnet", "IPv6 Address",
+			"IPv6 Subnet", "Gateway", "IPv6 Gateway", "MTU", "Block Private", "Block Bogons",
+		},
+		Rows: make([][]string, 0, len(names)),
+	}
+
+	for _, name := range names {
+		iface := interfaces.Items[name]
+		table.Rows = append(table.Rows, []string{
+			name,
+			iface.If,
+			iface.Descr,
+			formatBool(isSet(iface.Enable)),
+			iface.IPAddr,
+			iface.Subnet,
+			iface.IPAddrv6,
+			iface.Subnetv6,
+			iface.Gateway,
+			iface.Gatewayv6,
+			iface.MTU,
+			formatBool(isSet(iface.BlockPriv)),
+			formatBool(isSet(iface.BlockBogons)),
+		})
+	}
+
+	return table
+}
+
+// Users returns the users table. Password hashes and API secrets are never exported; the API Keys
+// column holds the number of keys.
+func Users(users []model.User) Table {
+	table := Table{
+		Name: TableUsers,
+		Columns: []string{
+			"Name", "UID", "Description", "Group", "Scope", "Enabled", "Shell", "API Keys", "OTP",
+			"Privileges",
+		},
+		Rows: make([][]string, 0, len(users)),
+	}
+
+	for _, user := range users {
+		table.Rows = append(table.Rows, []string{
+			user.Name,
+			user.UID,
+			user.Descr,
+			user.Groupname,
+			user.Scope,
+			formatBool(!bool(user.Disabled)),
+			user.Shell,
+			strconv.Itoa(len(user.APIKeys)),
+			formatBool(user.HasOTP()),
+			strings.Join(user.Priv, ";"),
+		})
+	}
+
+	return table
+}
+
+// DHCPLeases returns the static DHCP leases table, sorted by interface name and then in
+// configuration order.
+func DHCPLeases(dhcpd model.Dhcpd) Table {
+	names := dhcpd.Names()
+	slices.Sort(names)
+
+	table := Table{
+		Name:    TableDHCPLeases,
+		Columns: []string{"Interface", "MAC Address", "IP Address", "Hostname", "Client ID", "Description"},
+		Rows:    [][]string{},
+	}
+
+	for _, name := range names {
+		for _, lease := range dhcpd.Items[name].Staticmap {
+			table.Rows = append(table.Rows, []string{
+				name,
+				lease.Mac,
+				lease.IPAddr,
+				lease.Hostname,
+				lease.Cid,
+				lease.Descr,
+			})
+		}
+	}
+
+	return table
+}
+
+// Sysctl returns the system tunables table.
+func Sysctl(items []model.SysctlItem) Table {
+	table := Table{
+		Name:    TableSysctl,
+		Columns: []string{"Tunable", "Value", "Description"},
+		Rows:    make([][]string, 0, len(items)),
+	}
+
+	for _, item := range items {
+		table.Rows = append(table.Rows, []string{item.Tunable, item.Value, item.Descr})
+	}
+
+	return table
+}
+
+// FormatTime formats a timestamp as RFC 3339 in UTC, returning an empty string for the zero time.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// FormatTalkers renders top talkers as a comma-separated "address (count)" list.
+func FormatTalkers(talkers []model.Talker) string {
+	parts := make([]string, 0, len(talkers))
+	for _, talker := range talkers {
+		parts = append(parts, fmt.Sprintf("%s (%d)", talker.Address, talker.Count))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// formatBool formats a boolean cell.
+func formatBool(value bool) string {
+	return strconv.FormatBool(value)
+}
+
+// isSet reports whether an OPNsense flag value is set.
+func isSet(value string) bool {
+	return value == "1" || value == "true" || value == "on" || value == "yes"
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
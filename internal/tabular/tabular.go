@@ -0,0 +1,122 @@
+// Package tabular exports configuration objects and audit findings as tables for spreadsheets:
+// CSV with one file per table and XLSX workbooks with one sheet per table.
+//
+// The columns of every table are part of the output contract. New columns are only ever appended
+// so that spreadsheet macros and import scripts that address columns by position keep working.
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Table names, in the order in which tables are exported.
+const (
+	TableRules      = "rules"
+	TableNAT        = "nat"
+	TableInterfaces = "interfaces"
+	TableUsers      = "users"
+	TableDHCPLeases = "dhcp-leases"
+	TableSysctl     = "sysctl"
+	TableFindings   = "findings"
+)
+
+var (
+	// ErrUnknownTable is returned for table names that are not a table of this package.
+	ErrUnknownTable = errors.New("unknown table")
+	// ErrTableUnavailable is returned when a known table was not built, such as the findings
+	// table of a configuration that was not audited.
+	ErrTableUnavailable = errors.New("table not available")
+)
+
+// Table is a named table with a header row.
+type Table struct {
+	// Name identifies the table; it names the CSV file and the XLSX sheet.
+	Name    string
+	Columns []string
+	Rows    [][]string
+}
+
+// Names returns the names of all tables in export order.
+func Names() []string {
+	return []string{TableRules, TableNAT, TableInterfaces, TableUsers, TableDHCPLeases, TableSysctl, TableFindings}
+}
+
+// Select returns the named tables in the order given. It returns all tables when no names are
+// given.
+func Select(tables []Table, names []string) ([]Table, error) {
+	if len(names) == 0 {
+		return tables, nil
+	}
+
+	selected := make([]Table, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Names(), name) {
+			return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownTable, name, strings.Join(Names(), ", "))
+		}
+
+		index := slices.IndexFunc(tables, func(t Table) bool { return t.Name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTableUnavailable, name)
+		}
+
+		selected = append(selected, tables[index])
+	}
+
+	return selected, nil
+}
+
+// ToCSV returns the table as RFC 4180 CSV with a header row. Cells that a spreadsheet would
+// evaluate as a formula are prefixed with an apostrophe.
+func (t Table) ToCSV() (string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Columns); err != nil {
+		return "", fmt.Errorf("failed to write CSV header of %s: %w", t.Name, err)
+	}
+
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = neutralizeFormula(cell)
+		}
+
+		if err := w.Write(cells); err != nil {
+			return "", fmt.Errorf("failed to write CSV row of %s: %w", t.Name, err)
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV table %s: %w", t.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// neutralizeFormula prefixes cells that start like a spreadsheet formula with an apostrophe so that
+// configuration descriptions cannot inject formulas into the auditor's spreadsheet.
+func neutralizeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+
+	switch cell[0] {
+	case '=', '+', '@', '\t', '\r':
+		return "'" + cell
+	case '-':
+		if len(cell) > 1 {
+			return "'" + cell
+		}
+	}
+
+	return cell
+}
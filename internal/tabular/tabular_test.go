@@ -0,0 +1,155 @@
+package tabular
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSample parses a configuration from the repository test data.
+func loadSample(t *testing.T, name string) *model.OpnSenseDocument {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", name))
+	require.NoError(t, err)
+
+	doc, err := parser.NewXMLParser().Parse(context.Background(), strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestFromDocument(t *testing.T) {
+	doc := loadSample(t, "sample.config.6.xml")
+
+	tables := FromDocument(doc)
+
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.Name)
+
+		for i, row := range table.Rows {
+			assert.Len(t, row, len(table.Columns), "row %d of %s", i, table.Name)
+		}
+	}
+
+	assert.Equal(t, Names()[:len(Names())-1], names, "every configuration table in export order")
+
+	leases, err := Select(tables, []string{TableDHCPLeases})
+	require.NoError(t, err)
+	assert.Contains(t, leases[0].Rows, []string{"lan", "aa:bb:cc:dd:ee:ff", "10.1.1.100", "myLaptop", "", ""})
+
+	assert.Nil(t, FromDocument(nil))
+}
+
+// TestColumns pins the column headers, which downstream spreadsheets rely on.
+func TestColumns(t *testing.T) {
+	assert.Equal(t, []string{
+		"Index", "UUID", "Interface", "Direction", "Action", "Quick", "IP Version", "Protocol",
+		"Source", "Source Port", "Destination", "Destination Port", "Target", "Log", "Enabled",
+		"Description", "Hits", "First Seen", "Last Seen", "Top Talkers",
+	}, FirewallRules(nil).Columns)
+	assert.Equal(t, []string{
+		"Type", "Index", "UUID", "Interface", "IP Version", "Protocol", "Source", "Source Port",
+		"Destination", "Destination Port", "Target", "Target Port", "Enabled", "Description",
+	}, NAT(model.Nat{}).Columns)
+	assert.Equal(t, []string{
+		"Name", "Device", "Description", "Enabled", "IPv4 Address", "IPv4 Subnet", "IPv6 Address",
+		"IPv6 Subnet", "Gateway", "IPv6 Gateway", "MTU", "Block Private", "Block Bogons",
+	}, Interfaces(model.Interfaces{}).Columns)
+	assert.Equal(t, []string{
+		"Name", "UID", "Description", "Group", "Scope", "Enabled", "Shell", "API Keys", "OTP", "Privileges",
+	}, Users(nil).Columns)
+	assert.Equal(t, []string{"Interface", "MAC Address", "IP Address", "Hostname", "Client ID", "Description"},
+		DHCPLeases(model.Dhcpd{}).Columns)
+	assert.Equal(t, []string{"Tunable", "Value", "Description"}, Sysctl(nil).Columns)
+}
+
+func TestFirewallRules(t *testing.T) {
+	seen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rules := []model.Rule{
+		{
+			Type:        "pass",
+			Interface:   model.InterfaceList{"lan", "opt1"},
+			Protocol:    "tcp",
+			Destination: model.Destination{Network: "wanip", Port: "443"},
+			Descr:       "Allow HTTPS",
+			Hits: &model.RuleHits{
+				Count: 3, FirstSeen: seen, LastSeen: seen,
+				TopTalkers: []model.Talker{{Address: "10.0.0.1", Count: 3}},
+			},
+		},
+		{Type: "block", Interface: model.InterfaceList{"wan"}, Disabled: "1", Quick: "1", Log: true},
+		{
+			Type:        "pass",
+			Interface:   model.InterfaceList{"lan"},
+			Source:      model.Source{Address: "10.0.0.5", Port: "1024-65535"},
+			Destination: model.Destination{Network: "lan", Not: true, Port: "22"},
+		},
+	}
+
+	table := FirewallRules(rules)
+	require.Len(t, table.Rows, 3)
+
+	assert.Equal(t, []string{
+		"1", "", "lan,opt1", "", "pass", "false", "", "tcp", "any", "", "wanip", "443", "", "false", "true",
+		"Allow HTTPS", "3", "2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z", "10.0.0.1 (3)",
+	}, table.Rows[0])
+	assert.Equal(t, []string{
+		"2", "", "wan", "", "block", "true", "", "", "any", "", "any", "", "", "true", "false", "", "", "", "", "",
+	}, table.Rows[1])
+	assert.Equal(t, []string{"10.0.0.5", "1024-65535", "!lan", "22"}, table.Rows[2][8:12])
+}
+
+func TestSelect(t *testing.T) {
+	tables := []Table{Sysctl(nil), FirewallRules(nil)}
+
+	selected, err := Select(tables, nil)
+	require.NoError(t, err)
+	assert.Equal(t, tables, selected)
+
+	selected, err = Select(tables, []string{"RULES", " sysctl"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, TableRules, selected[0].Name)
+	assert.Equal(t, TableSysctl, selected[1].Name)
+
+	_, err = Select(tables, []string{"leases"})
+	require.ErrorIs(t, err, ErrUnknownTable)
+
+	_, err = Select(tables, []string{TableFindings})
+	require.ErrorIs(t, err, ErrTableUnavailable)
+}
+
+func TestToCSV(t *testing.T) {
+	table := Table{
+		Name:    "test",
+		Columns: []string{"Name", "Description"},
+		Rows: [][]string{
+			{"a", "plain, with \"quotes\"\nand a line break"},
+			{"=HYPERLINK(\"http://evil\")", "-"},
+			{"+1", "-cmd"},
+		},
+	}
+
+	output, err := table.ToCSV()
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Description"},
+		{"a", "plain, with \"quotes\"\nand a line break"},
+		{"'=HYPERLINK(\"http://evil\")", "-"},
+		{"'+1", "'-cmd"},
+	}, records)
+}
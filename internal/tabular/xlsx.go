@@ -0,0 +1,202 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxSheetNameLength is the longest sheet name that spreadsheet applications accept.
+const maxSheetNameLength = 31
+
+// xlsxModified is the modification time of every workbook part. A fixed time keeps the workbook
+// of a configuration byte-for-byte identical across runs.
+var xlsxModified = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // constant time value
+
+// xlsxPart is a file of the workbook package.
+type xlsxPart struct {
+	name    string
+	content string
+}
+
+// ToXLSX returns the tables as an Office Open XML workbook with one sheet per table. The header
+// row of every sheet is bold, frozen and has an autofilter; all cells are text.
+func ToXLSX(tables []Table) ([]byte, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%w: no tables to export", ErrTableUnavailable)
+	}
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	parts := []xlsxPart{
+		{"[Content_Types].xml", xlsxContentTypes(len(tables))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(tables)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(tables))},
+		{"xl/styles.xml", xlsxStyles},
+	}
+
+	for i, table := range tables {
+		parts = append(parts, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxSheet(table)})
+	}
+
+	for _, part := range parts {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate, Modified: xlsxModified})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create workbook part %s: %w", part.name, err)
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write workbook part %s: %w", part.name, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write workbook: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xlsxRootRels points the package at the workbook.
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles defines the default cell style (0) and the bold header style (1).
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// xlsxContentTypes declares the content types of the workbook parts.
+func xlsxContentTypes(sheets int) string {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	b.WriteString(`</Types>`)
+
+	return b.String()
+}
+
+// xlsxWorkbook lists the sheets of the workbook.
+func xlsxWorkbook(tables []Table) string {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+
+	for i, table := range tables {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheetName(table.Name)), i+1, i+1)
+	}
+
+	b.WriteString(`</sheets></workbook>`)
+
+	return b.String()
+}
+
+// xlsxWorkbookRels relates the workbook to its sheets and styles. Sheet relationships come first
+// so that their IDs match the sheet numbers.
+func xlsxWorkbookRels(sheets int) string {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+
+	return b.String()
+}
+
+// xlsxSheet renders a table as a worksheet with inline string cells.
+func xlsxSheet(table Table) string {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetData>`)
+
+	writeRow := func(index int, cells []string, style string) {
+		fmt.Fprintf(&b, `<row r="%d">`, index)
+
+		for col, cell := range cells {
+			fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`,
+				columnName(col), index, style, escapeXML(cell))
+		}
+
+		b.WriteString(`</row>`)
+	}
+
+	writeRow(1, table.Columns, ` s="1"`)
+
+	for i, row := range table.Rows {
+		writeRow(i+2, row, "")
+	}
+
+	b.WriteString(`</sheetData>`)
+
+	if len(table.Columns) > 0 {
+		fmt.Fprintf(&b, `<autoFilter ref="A1:%s%d"/>`, columnName(len(table.Columns)-1), len(table.Rows)+1)
+	}
+
+	b.WriteString(`</worksheet>`)
+
+	return b.String()
+}
+
+// columnName returns the spreadsheet column letters of a zero-based column index (A, B, ..., AA).
+func columnName(index int) string {
+	name := ""
+
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+
+	return name
+}
+
+// sheetName returns a table name shortened to the longest sheet name spreadsheets accept.
+func sheetName(name string) string {
+	if len(name) > maxSheetNameLength {
+		return name[:maxSheetNameLength]
+	}
+
+	return name
+}
+
+// escapeXML escapes text for an XML element or attribute, replacing characters that XML cannot
+// represent.
+func escapeXML(text string) string {
+	var b strings.Builder
+
+	_ = xml.EscapeText(&b, []byte(text)) // writes to a strings.Builder never fail
+
+	return b.String()
+}
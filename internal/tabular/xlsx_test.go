@@ -0,0 +1,95 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xlsxTestSheet is the part of a worksheet that the tests inspect.
+type xlsxTestSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref  string `xml:"r,attr"`
+			Text string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	AutoFilter struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"autoFilter"`
+}
+
+// readPart returns the content of a workbook part.
+func readPart(t *testing.T, workbook []byte, name string) []byte {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(workbook), int64(len(workbook)))
+	require.NoError(t, err)
+
+	f, err := r.Open(name)
+	require.NoError(t, err, "workbook part %s", name)
+
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestToXLSX(t *testing.T) {
+	tables := []Table{
+		{Name: TableSysctl, Columns: []string{"Tunable", "Value"}, Rows: [][]string{{"net.inet.ip.forwarding", "1"}}},
+		{Name: TableUsers, Columns: []string{"Name"}, Rows: [][]string{{"<admin> & \"root\"\x01"}}},
+	}
+
+	workbook, err := ToXLSX(tables)
+	require.NoError(t, err)
+
+	again, err := ToXLSX(tables)
+	require.NoError(t, err)
+	assert.Equal(t, workbook, again, "workbooks are deterministic")
+
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	require.NoError(t, xml.Unmarshal(readPart(t, workbook, "xl/workbook.xml"), &book))
+	require.Len(t, book.Sheets, 2)
+	assert.Equal(t, TableSysctl, book.Sheets[0].Name)
+	assert.Equal(t, TableUsers, book.Sheets[1].Name)
+
+	var sheet xlsxTestSheet
+	require.NoError(t, xml.Unmarshal(readPart(t, workbook, "xl/worksheets/sheet1.xml"), &sheet))
+	require.Len(t, sheet.Rows, 2)
+	assert.Equal(t, "A1", sheet.Rows[0].Cells[0].Ref)
+	assert.Equal(t, "Tunable", sheet.Rows[0].Cells[0].Text)
+	assert.Equal(t, "B2", sheet.Rows[1].Cells[1].Ref)
+	assert.Equal(t, "1", sheet.Rows[1].Cells[1].Text)
+	assert.Equal(t, "A1:B2", sheet.AutoFilter.Ref)
+
+	sheet = xlsxTestSheet{}
+	require.NoError(t, xml.Unmarshal(readPart(t, workbook, "xl/worksheets/sheet2.xml"), &sheet))
+	assert.Equal(t, "<admin> & \"root\"�", sheet.Rows[1].Cells[0].Text)
+
+	for _, part := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.NotEmpty(t, readPart(t, workbook, part))
+	}
+
+	_, err = ToXLSX(nil)
+	require.ErrorIs(t, err, ErrTableUnavailable)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,110 @@
+package topology
+
+import (
+	"strings"
+)
+
+// mermaidShapes maps node kinds to the opening and closing brackets of their Mermaid shape.
+var mermaidShapes = map[NodeKind][2]string{ //nolint:gochecknoglobals // Lookup table
+	KindInterface: {"[", "]"},
+	KindDevice:    {"[/", "/]"},
+	KindVLAN:      {"(", ")"},
+	KindGateway:   {"{{", "}}"},
+	KindNetwork:   {"[(", ")]"},
+	KindTunnel:    {"([", "])"},
+	KindPeer:      {"((", "))"},
+}
+
+// mermaidArrows maps edge kinds to their Mermaid link style.
+var mermaidArrows = map[EdgeKind]string{ //nolint:gochecknoglobals // Lookup table
+	EdgeVLAN:    "-.->",
+	EdgeGateway: "-->",
+	EdgeRoute:   "-->",
+	EdgeTunnel:  "==>",
+}
+
+// dotShapes maps node kinds to their Graphviz node attributes.
+var dotShapes = map[NodeKind]string{ //nolint:gochecknoglobals // Lookup table
+	KindInterface: `shape=box`,
+	KindDevice:    `shape=box, style=dashed`,
+	KindVLAN:      `shape=box, style=rounded`,
+	KindGateway:   `shape=hexagon`,
+	KindNetwork:   `shape=cylinder`,
+	KindTunnel:    `shape=ellipse, style=bold`,
+	KindPeer:      `shape=doublecircle`,
+}
+
+// dotStyles maps edge kinds to their Graphviz edge style.
+var dotStyles = map[EdgeKind]string{ //nolint:gochecknoglobals // Lookup table
+	EdgeVLAN:    "dashed",
+	EdgeGateway: "solid",
+	EdgeRoute:   "solid",
+	EdgeTunnel:  "bold",
+}
+
+// Mermaid renders the topology as a Mermaid flowchart, without the surrounding code fence.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	for _, node := range g.Nodes {
+		shape := mermaidShapes[node.Kind]
+		b.WriteString("    " + node.ID + shape[0] + `"` + mermaidText(node.text("<br/>")) + `"` + shape[1] + "\n")
+	}
+
+	for _, edge := range g.Edges {
+		b.WriteString("    " + edge.From + " " + mermaidArrows[edge.Kind])
+
+		if edge.Label != "" {
+			b.WriteString(`|"` + mermaidText(edge.Label) + `"|`)
+		}
+
+		b.WriteString(" " + edge.To + "\n")
+	}
+
+	return b.String()
+}
+
+// DOT renders the topology as a Graphviz digraph.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph topology {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=9];\n")
+
+	for _, node := range g.Nodes {
+		b.WriteString("    " + dotQuote(node.ID) + " [label=" + dotQuote(node.text("\n")) + ", " + dotShapes[node.Kind] + "];\n")
+	}
+
+	for _, edge := range g.Edges {
+		b.WriteString("    " + dotQuote(edge.From) + " -> " + dotQuote(edge.To) + " [style=" + dotStyles[edge.Kind])
+
+		if edge.Label != "" {
+			b.WriteString(", label=" + dotQuote(edge.Label))
+		}
+
+		b.WriteString("];\n")
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// text returns the label and lines of a node joined by a line break.
+func (n Node) text(lineBreak string) string {
+	return strings.Join(append([]string{n.Label}, n.Lines...), lineBreak)
+}
+
+// mermaidText escapes the characters that end a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// dotQuote returns s as a quoted DOT string. Line breaks become centered DOT line breaks.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
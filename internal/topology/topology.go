@@ -0,0 +1,519 @@
+// Package topology builds a network topology graph from an OPNsense configuration and renders it
+// as a Mermaid flowchart or a Graphviz DOT digraph.
+//
+// The graph contains the assigned interfaces with their subnets and roles, VLANs and the devices
+// they are tagged on, gateways and gateway groups, static routes, and the OpenVPN and WireGuard
+// tunnels with their peers. Bridge and LAGG members and IPsec tunnels are not parsed by the model,
+// so bridges and LAGGs only appear as interface roles and IPsec tunnels are not drawn.
+package topology
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+)
+
+// NodeKind is the kind of object that a node represents.
+type NodeKind string
+
+// Node kinds.
+const (
+	// KindInterface is an assigned interface.
+	KindInterface NodeKind = "interface"
+	// KindDevice is a network device that is not assigned to an interface, such as a VLAN parent.
+	KindDevice NodeKind = "device"
+	// KindVLAN is a VLAN device that is not assigned to an interface.
+	KindVLAN NodeKind = "vlan"
+	// KindGateway is a gateway or gateway group.
+	KindGateway NodeKind = "gateway"
+	// KindNetwork is a remote network reached through a static route or a tunnel.
+	KindNetwork NodeKind = "network"
+	// KindTunnel is an OpenVPN or WireGuard instance.
+	KindTunnel NodeKind = "tunnel"
+	// KindPeer is the remote end of a tunnel.
+	KindPeer NodeKind = "peer"
+)
+
+// EdgeKind is the kind of relation that an edge represents.
+type EdgeKind string
+
+// Edge kinds.
+const (
+	// EdgeVLAN links a VLAN to the device it is tagged on.
+	EdgeVLAN EdgeKind = "vlan"
+	// EdgeGateway links an interface to its gateways and a gateway group to its members.
+	EdgeGateway EdgeKind = "gateway"
+	// EdgeRoute links a gateway to the networks routed through it.
+	EdgeRoute EdgeKind = "route"
+	// EdgeTunnel links a tunnel to its interface, its peers and its remote networks.
+	EdgeTunnel EdgeKind = "tunnel"
+)
+
+// Interface roles.
+const (
+	roleWAN       = "WAN"
+	roleLAN       = "LAN"
+	roleOptional  = "OPT"
+	roleVLAN      = "VLAN"
+	roleBridge    = "Bridge"
+	roleLAGG      = "LAGG"
+	roleOpenVPN   = "OpenVPN"
+	roleWireGuard = "WireGuard"
+	roleGroup     = "Group"
+)
+
+// Node is an object of the topology. Lines are shown below the label.
+type Node struct {
+	ID    string
+	Kind  NodeKind
+	Label string
+	Lines []string
+}
+
+// Edge is a relation between two nodes.
+type Edge struct {
+	From  string
+	To    string
+	Kind  EdgeKind
+	Label string
+}
+
+// Graph is the network topology of a configuration. Nodes and edges are in a stable order.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+
+	index map[string]int
+	edges map[Edge]bool
+	// devices maps network devices to the node that represents them.
+	devices map[string]string
+}
+
+// Build returns the network topology of a configuration. A nil document has an empty topology.
+func Build(doc *model.OpnSenseDocument) *Graph {
+	g := &Graph{index: map[string]int{}, edges: map[Edge]bool{}, devices: map[string]string{}}
+	if doc == nil {
+		return g
+	}
+
+	vlans := make(map[string]model.VLAN, len(doc.VLANs.VLAN))
+	for _, vlan := range doc.VLANs.VLAN {
+		vlans[vlan.Vlanif] = vlan
+	}
+
+	g.addInterfaces(doc, vlans)
+	g.addVLANs(doc.VLANs.VLAN)
+	g.addGateways(doc.Gateways)
+	g.addStaticRoutes(doc.StaticRoutes.Route)
+	g.addOpenVPN(doc.OpenVPN)
+
+	if doc.OPNsense.Wireguard != nil {
+		g.addWireGuard(doc.OPNsense.Wireguard)
+	}
+
+	return g
+}
+
+// Empty reports whether the topology has no nodes.
+func (g *Graph) Empty() bool {
+	return len(g.Nodes) == 0
+}
+
+// Node returns the node with the given ID.
+func (g *Graph) Node(id string) (Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return Node{}, false
+	}
+
+	return g.Nodes[i], true
+}
+
+// addNode adds a node unless a node with the same ID exists.
+func (g *Graph) addNode(node Node) {
+	if _, ok := g.index[node.ID]; ok {
+		return
+	}
+
+	g.index[node.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+}
+
+// addEdge adds an edge unless the same edge exists.
+func (g *Graph) addEdge(edge Edge) {
+	if g.edges[edge] {
+		return
+	}
+
+	g.edges[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// addInterfaces adds the assigned interfaces, sorted by name, with their virtual IPs.
+func (g *Graph) addInterfaces(doc *model.OpnSenseDocument, vlans map[string]model.VLAN) {
+	names := doc.Interfaces.Names()
+	slices.Sort(names)
+
+	vips := make(map[string][]string)
+	for _, vip := range doc.VirtualIP.Vip {
+		vips[vip.Interface] = append(vips[vip.Interface], fmt.Sprintf("VIP %s (%s)", prefix(vip.Subnet, vip.SubnetBits), vip.Mode))
+	}
+
+	for _, name := range names {
+		iface := doc.Interfaces.Items[name]
+		if iface.If == "lo0" {
+			continue
+		}
+
+		role := interfaceRole(name, iface, vlans)
+		label := name
+		if iface.Descr != "" && !strings.EqualFold(iface.Descr, name) {
+			label = fmt.Sprintf("%s (%s)", iface.Descr, name)
+		}
+
+		lines := []string{role}
+		if iface.If != "" {
+			lines = append(lines, iface.If)
+		}
+
+		if vlan, ok := vlans[iface.If]; ok {
+			lines = append(lines, "tag "+vlan.Tag)
+		}
+
+		lines = append(lines, interfaceAddresses(iface)...)
+		lines = append(lines, vips[name]...)
+
+		if iface.Enable == "" {
+			lines = append(lines, "disabled")
+		}
+
+		id := "if_" + sanitizeID(name)
+		g.addNode(Node{ID: id, Kind: KindInterface, Label: label, Lines: lines})
+
+		if iface.If != "" {
+			g.devices[iface.If] = id
+		}
+	}
+}
+
+// interfaceRole returns the role of an assigned interface.
+func interfaceRole(name string, iface model.Interface, vlans map[string]model.VLAN) string {
+	if _, ok := vlans[iface.If]; ok {
+		return roleVLAN
+	}
+
+	switch {
+	case name == "wan":
+		return roleWAN
+	case name == "lan":
+		return roleLAN
+	case iface.Type == "group":
+		return roleGroup
+	case strings.HasPrefix(iface.If, "bridge"):
+		return roleBridge
+	case strings.HasPrefix(iface.If, "lagg"):
+		return roleLAGG
+	case strings.HasPrefix(iface.If, "ovpn"):
+		return roleOpenVPN
+	case strings.HasPrefix(iface.If, "wg"):
+		return roleWireGuard
+	case strings.Contains(iface.If, "_vlan") || strings.HasPrefix(iface.If, "vlan"):
+		return roleVLAN
+	default:
+		return roleOptional
+	}
+}
+
+// interfaceAddresses returns the IPv4 and IPv6 addresses of an interface in CIDR notation.
+func interfaceAddresses(iface model.Interface) []string {
+	var lines []string
+
+	if iface.IPAddr != "" {
+		lines = append(lines, prefix(iface.IPAddr, iface.Subnet))
+	}
+
+	if iface.IPAddrv6 != "" {
+		lines = append(lines, prefix(iface.IPAddrv6, iface.Subnetv6))
+	}
+
+	return lines
+}
+
+// prefix returns an address in CIDR notation. Dynamic addresses such as "dhcp" are shown in upper
+// case without a prefix length.
+func prefix(address, bits string) string {
+	if address == "" {
+		return ""
+	}
+
+	if !strings.ContainsAny(address, ".:") {
+		return strings.ToUpper(address)
+	}
+
+	if bits == "" {
+		return address
+	}
+
+	return address + "/" + bits
+}
+
+// interfaceNode returns the node of an assigned interface.
+func (g *Graph) interfaceNode(name string) (string, bool) {
+	id := "if_" + sanitizeID(name)
+	_, ok := g.index[id]
+
+	return id, ok
+}
+
+// device returns the node of a network device, adding a node for devices that are not assigned to
+// an interface.
+func (g *Graph) device(name string, kind NodeKind, lines ...string) string {
+	if id, ok := g.devices[name]; ok {
+		return id
+	}
+
+	id := "dev_" + sanitizeID(name)
+	g.addNode(Node{ID: id, Kind: kind, Label: name, Lines: lines})
+	g.devices[name] = id
+
+	return id
+}
+
+// addVLANs links every VLAN to the device that it is tagged on.
+func (g *Graph) addVLANs(vlans []model.VLAN) {
+	for _, vlan := range vlans {
+		if vlan.Vlanif == "" || vlan.If == "" {
+			continue
+		}
+
+		lines := []string{"VLAN " + vlan.Tag}
+		if vlan.Descr != "" {
+			lines = append(lines, vlan.Descr)
+		}
+
+		child := g.device(vlan.Vlanif, KindVLAN, lines...)
+		parent := g.device(vlan.If, KindDevice)
+		g.addEdge(Edge{From: child, To: parent, Kind: EdgeVLAN, Label: "VLAN " + vlan.Tag})
+	}
+}
+
+// gatewayID returns the node ID of a gateway or gateway group.
+func gatewayID(name string) string {
+	return "gw_" + sanitizeID(name)
+}
+
+// addGateways adds the gateways with an edge from their interface, and the gateway groups with an
+// edge to each member.
+func (g *Graph) addGateways(gateways model.Gateways) {
+	for _, gw := range gateways.Gateway {
+		lines := make([]string, 0, 3)
+		if gw.Gateway != "" {
+			lines = append(lines, gw.Gateway)
+		}
+
+		if gw.IPProtocol != "" {
+			lines = append(lines, gw.IPProtocol)
+		}
+
+		if gw.Disabled {
+			lines = append(lines, "disabled")
+		}
+
+		g.addNode(Node{ID: gatewayID(gw.Name), Kind: KindGateway, Label: gw.Name, Lines: lines})
+
+		iface, ok := g.interfaceNode(gw.Interface)
+		if !ok {
+			continue
+		}
+
+		label := ""
+		if gw.DefaultGW == "1" {
+			label = "default"
+		}
+
+		g.addEdge(Edge{From: iface, To: gatewayID(gw.Name), Kind: EdgeGateway, Label: label})
+	}
+
+	for _, group := range gateways.Groups {
+		g.addNode(Node{ID: gatewayID(group.Name), Kind: KindGateway, Label: group.Name, Lines: []string{"gateway group"}})
+
+		for _, item := range group.Item {
+			// Members are stored as "name|tier|address" in newer configurations
+			member, _, _ := strings.Cut(item, "|")
+			if _, ok := g.Node(gatewayID(member)); !ok {
+				continue
+			}
+
+			g.addEdge(Edge{From: gatewayID(group.Name), To: gatewayID(member), Kind: EdgeGateway})
+		}
+	}
+}
+
+// networkID returns the node ID of a remote network.
+func networkID(network string) string {
+	return "net_" + sanitizeID(network)
+}
+
+// addStaticRoutes adds the routed networks with an edge from their gateway.
+func (g *Graph) addStaticRoutes(routes []model.StaticRoute) {
+	for _, route := range routes {
+		if route.Network == "" || route.Gateway == "" {
+			continue
+		}
+
+		var lines []string
+		if route.Descr != "" {
+			lines = append(lines, route.Descr)
+		}
+
+		g.addNode(Node{ID: networkID(route.Network), Kind: KindNetwork, Label: route.Network, Lines: lines})
+		g.addNode(Node{ID: gatewayID(route.Gateway), Kind: KindGateway, Label: route.Gateway})
+
+		label := "route"
+		if route.Disabled {
+			label = "route (disabled)"
+		}
+
+		g.addEdge(Edge{From: gatewayID(route.Gateway), To: networkID(route.Network), Kind: EdgeRoute, Label: label})
+	}
+}
+
+// addOpenVPN adds the OpenVPN servers with their remote networks and the OpenVPN clients with
+// their servers.
+func (g *Graph) addOpenVPN(openvpn model.OpenVPN) {
+	for _, server := range openvpn.Servers {
+		lines := []string{strings.TrimSpace(server.Protocol + " " + server.Local_port)}
+		if server.Tunnel_network != "" {
+			lines = append(lines, "tunnel "+server.Tunnel_network)
+		}
+
+		id := g.addTunnel("ovpns"+server.VPN_ID, "OpenVPN server "+nonEmpty(server.Description, server.VPN_ID), server.Interface, lines)
+
+		for _, network := range splitList(server.Remote_network) {
+			g.addNode(Node{ID: networkID(network), Kind: KindNetwork, Label: network})
+			g.addEdge(Edge{From: id, To: networkID(network), Kind: EdgeTunnel, Label: "remote network"})
+		}
+	}
+
+	for _, client := range openvpn.Clients {
+		lines := []string{client.Protocol}
+		id := g.addTunnel("ovpnc"+client.VPN_ID, "OpenVPN client "+nonEmpty(client.Description, client.VPN_ID), client.Interface, lines)
+
+		if client.Server_addr == "" {
+			continue
+		}
+
+		endpoint := joinHostPort(client.Server_addr, client.Server_port)
+		peer := "peer_" + sanitizeID(endpoint)
+		g.addNode(Node{ID: peer, Kind: KindPeer, Label: endpoint, Lines: []string{"OpenVPN server"}})
+		g.addEdge(Edge{From: id, To: peer, Kind: EdgeTunnel, Label: "peer"})
+	}
+}
+
+// addWireGuard adds the WireGuard instances with an edge to each of their peers.
+func (g *Graph) addWireGuard(wg *model.WireGuard) {
+	peers := make(map[string]model.WireGuardClientItem, len(wg.Client.Clients.Client))
+	for _, peer := range wg.Client.Clients.Client {
+		peers[peer.UUID] = peer
+	}
+
+	for _, server := range wg.Server.Servers.Server {
+		lines := make([]string, 0, 2)
+		if server.Tunneladdress != "" {
+			lines = append(lines, server.Tunneladdress)
+		}
+
+		if server.Port != "" {
+			lines = append(lines, "udp "+server.Port)
+		}
+
+		id := g.addTunnel("wg"+server.Instance, "WireGuard "+server.Name, "", lines)
+
+		for _, uuid := range splitList(server.Peers) {
+			peer, ok := peers[uuid]
+			if !ok {
+				continue
+			}
+
+			peerLines := make([]string, 0, 2)
+			if peer.Tunneladdress != "" {
+				peerLines = append(peerLines, peer.Tunneladdress)
+			}
+
+			if peer.Serveraddress != "" {
+				peerLines = append(peerLines, "endpoint "+joinHostPort(peer.Serveraddress, peer.Serverport))
+			}
+
+			peerID := "peer_" + sanitizeID(nonEmpty(uuid, peer.Name))
+			g.addNode(Node{ID: peerID, Kind: KindPeer, Label: peer.Name, Lines: peerLines})
+			g.addEdge(Edge{From: id, To: peerID, Kind: EdgeTunnel, Label: "peer"})
+		}
+	}
+}
+
+// addTunnel adds a tunnel instance and links it to the interface it listens on and to the
+// interface its device is assigned to.
+func (g *Graph) addTunnel(device, label, listen string, lines []string) string {
+	id := "tun_" + sanitizeID(device)
+	g.addNode(Node{ID: id, Kind: KindTunnel, Label: label, Lines: slices.DeleteFunc(lines, isBlank)})
+
+	if iface, ok := g.interfaceNode(listen); ok {
+		g.addEdge(Edge{From: iface, To: id, Kind: EdgeTunnel, Label: "listens"})
+	}
+
+	if assigned, ok := g.devices[device]; ok {
+		g.addEdge(Edge{From: id, To: assigned, Kind: EdgeTunnel, Label: "assigned"})
+	}
+
+	return id
+}
+
+// sanitizeID replaces every character that is not valid in Mermaid and DOT identifiers.
+func sanitizeID(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, s)
+}
+
+// splitList splits a comma-separated list and drops empty items.
+func splitList(s string) []string {
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return slices.DeleteFunc(items, isBlank)
+}
+
+// joinHostPort joins a host and an optional port.
+func joinHostPort(host, port string) string {
+	if port == "" {
+		return host
+	}
+
+	if strings.Contains(host, ":") {
+		return "[" + host + "]:" + port
+	}
+
+	return host + ":" + port
+}
+
+// nonEmpty returns value, or fallback if value is empty.
+func nonEmpty(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// isBlank reports whether s is empty or only whitespace.
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
@@ -0,0 +1,163 @@
+package topology
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSample parses a configuration from the repository test data.
+func loadSample(t *testing.T, name string) *model.OpnSenseDocument {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", name))
+	require.NoError(t, err)
+
+	doc, err := parser.NewXMLParser().Parse(context.Background(), strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	return doc
+}
+
+// networkDocument returns a configuration with VLANs, static routes and OpenVPN tunnels.
+func networkDocument() *model.OpnSenseDocument {
+	doc := &model.OpnSenseDocument{}
+	doc.Interfaces.Items = map[string]model.Interface{
+		"wan":  {Enable: "1", If: "igb0", IPAddr: "dhcp"},
+		"lan":  {Enable: "1", If: "igb1", IPAddr: "10.0.0.1", Subnet: "24"},
+		"opt1": {Enable: "1", If: "igb1_vlan20", Descr: "Guests", IPAddr: "10.0.20.1", Subnet: "24"},
+		"lo0":  {Enable: "1", If: "lo0", IPAddr: "127.0.0.1", Subnet: "8"},
+	}
+	doc.VLANs.VLAN = []model.VLAN{
+		{If: "igb1", Tag: "20", Vlanif: "igb1_vlan20"},
+		{If: "igb2", Tag: "30", Vlanif: "igb2_vlan30", Descr: "Cameras"},
+	}
+	doc.Gateways.Gateway = []model.Gateway{
+		{Name: "WAN_DHCP", Interface: "wan", Gateway: "dynamic", DefaultGW: "1"},
+		{Name: "LAN_ROUTER", Interface: "lan", Gateway: "10.0.0.254"},
+	}
+	doc.StaticRoutes.Route = []model.StaticRoute{{Network: "192.168.50.0/24", Gateway: "LAN_ROUTER", Descr: "Lab"}}
+	doc.OpenVPN.Servers = []model.OpenVPNServer{{
+		VPN_ID: "1", Protocol: "UDP", Interface: "wan", Local_port: "1194", Description: "Road warriors",
+		Tunnel_network: "10.8.0.0/24", Remote_network: "10.9.0.0/24, 10.10.0.0/24",
+	}}
+	doc.OpenVPN.Clients = []model.OpenVPNClient{{
+		VPN_ID: "2", Protocol: "TCP", Interface: "wan", Server_addr: "vpn.example.com", Server_port: "443",
+	}}
+
+	return doc
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(networkDocument())
+
+	lan, ok := g.Node("if_lan")
+	require.True(t, ok)
+	assert.Equal(t, KindInterface, lan.Kind)
+	assert.Equal(t, []string{"LAN", "igb1", "10.0.0.1/24"}, lan.Lines)
+
+	wan, ok := g.Node("if_wan")
+	require.True(t, ok)
+	assert.Equal(t, []string{"WAN", "igb0", "DHCP"}, wan.Lines)
+
+	guests, ok := g.Node("if_opt1")
+	require.True(t, ok)
+	assert.Equal(t, "Guests (opt1)", guests.Label)
+	assert.Equal(t, []string{"VLAN", "igb1_vlan20", "tag 20", "10.0.20.1/24"}, guests.Lines)
+
+	_, ok = g.Node("if_lo0")
+	assert.False(t, ok, "the loopback interface is not drawn")
+
+	cameras, ok := g.Node("dev_igb2_vlan30")
+	require.True(t, ok)
+	assert.Equal(t, KindVLAN, cameras.Kind)
+
+	parent, ok := g.Node("dev_igb2")
+	require.True(t, ok)
+	assert.Equal(t, KindDevice, parent.Kind)
+
+	assert.Subset(t, g.Edges, []Edge{
+		{From: "if_opt1", To: "if_lan", Kind: EdgeVLAN, Label: "VLAN 20"},
+		{From: "dev_igb2_vlan30", To: "dev_igb2", Kind: EdgeVLAN, Label: "VLAN 30"},
+		{From: "if_wan", To: "gw_WAN_DHCP", Kind: EdgeGateway, Label: "default"},
+		{From: "if_lan", To: "gw_LAN_ROUTER", Kind: EdgeGateway},
+		{From: "gw_LAN_ROUTER", To: "net_192_168_50_0_24", Kind: EdgeRoute, Label: "route"},
+		{From: "if_wan", To: "tun_ovpns1", Kind: EdgeTunnel, Label: "listens"},
+		{From: "tun_ovpns1", To: "net_10_9_0_0_24", Kind: EdgeTunnel, Label: "remote network"},
+		{From: "tun_ovpns1", To: "net_10_10_0_0_24", Kind: EdgeTunnel, Label: "remote network"},
+		{From: "tun_ovpnc2", To: "peer_vpn_example_com_443", Kind: EdgeTunnel, Label: "peer"},
+	})
+
+	for _, edge := range g.Edges {
+		_, from := g.Node(edge.From)
+		_, to := g.Node(edge.To)
+		assert.True(t, from && to, "edge %s -> %s links existing nodes", edge.From, edge.To)
+	}
+
+	assert.Equal(t, g, Build(networkDocument()), "topologies are deterministic")
+}
+
+func TestBuild_WireGuard(t *testing.T) {
+	g := Build(loadSample(t, "sample.config.2.xml"))
+
+	tunnel, ok := g.Node("tun_wg1")
+	require.True(t, ok)
+	assert.Equal(t, "WireGuard WGBootstrap", tunnel.Label)
+	assert.Equal(t, []string{"172.19.0.1/24", "udp 51821"}, tunnel.Lines)
+
+	assert.Subset(t, g.Edges, []Edge{
+		{From: "tun_wg1", To: "if_opt0", Kind: EdgeTunnel, Label: "assigned"},
+		{From: "tun_wg1", To: "peer_9aa359e8_e831_4a55_afb4_d402c81885d5", Kind: EdgeTunnel, Label: "peer"},
+	})
+}
+
+func TestBuild_GatewayGroups(t *testing.T) {
+	g := Build(loadSample(t, "gateway_groups_test.xml"))
+
+	assert.Subset(t, g.Edges, []Edge{
+		{From: "gw_WAN_FAILOVER", To: "gw_WAN_GW", Kind: EdgeGateway},
+		{From: "gw_WAN_FAILOVER", To: "gw_WAN_GW2", Kind: EdgeGateway},
+	})
+}
+
+func TestBuild_Nil(t *testing.T) {
+	g := Build(nil)
+	assert.True(t, g.Empty())
+	assert.Equal(t, "flowchart LR\n", g.Mermaid())
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	out := Build(networkDocument()).Mermaid()
+
+	assert.True(t, strings.HasPrefix(out, "flowchart LR\n"))
+	assert.Contains(t, out, `    if_lan["lan<br/>LAN<br/>igb1<br/>10.0.0.1/24"]`)
+	assert.Contains(t, out, `    gw_WAN_DHCP{{"WAN_DHCP<br/>dynamic"}}`)
+	assert.Contains(t, out, `    net_192_168_50_0_24[("192.168.50.0/24<br/>Lab")]`)
+	assert.Contains(t, out, `    if_opt1 -.->|"VLAN 20"| if_lan`)
+	assert.Contains(t, out, `    if_lan --> gw_LAN_ROUTER`)
+	assert.Contains(t, out, `    tun_ovpnc2 ==>|"peer"| peer_vpn_example_com_443`)
+
+	doc := networkDocument()
+	doc.Interfaces.Items["lan"] = model.Interface{Enable: "1", If: "igb1", Descr: `Office "HQ"`}
+	assert.Contains(t, Build(doc).Mermaid(), `if_lan["Office #quot;HQ#quot; (lan)<br/>LAN<br/>igb1"]`)
+}
+
+func TestGraph_DOT(t *testing.T) {
+	out := Build(networkDocument()).DOT()
+
+	assert.True(t, strings.HasPrefix(out, "digraph topology {\n"))
+	assert.True(t, strings.HasSuffix(out, "}\n"))
+	assert.Contains(t, out, `    "if_lan" [label="lan\nLAN\nigb1\n10.0.0.1/24", shape=box];`)
+	assert.Contains(t, out, `    "dev_igb2" [label="igb2", shape=box, style=dashed];`)
+	assert.Contains(t, out, `    "if_opt1" -> "if_lan" [style=dashed, label="VLAN 20"];`)
+	assert.Contains(t, out, `    "if_lan" -> "gw_LAN_ROUTER" [style=solid];`)
+
+	assert.Equal(t, `"a \"b\"\\c\nd"`, dotQuote("a \"b\"\\c\nd"))
+}
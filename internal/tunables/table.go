@@ -0,0 +1,66 @@
+package tunables
+
+import (
+	"strings"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/nao1215/markdown"
+)
+
+// notSet is displayed for tunables that are not present in the configuration.
+const notSet = "(not set)"
+
+// ComplianceTable returns the expected vs actual vs default comparison as a markdown table.
+func ComplianceTable(checks []model.TunableCheck) markdown.TableSet {
+	table := markdown.TableSet{
+		Header: []string{"Tunable", "Expected", "Actual", "Default", "Status", "Severity", "Rationale"},
+		Rows:   make([][]string, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		actual := check.Actual
+		if actual == "" {
+			actual = notSet
+		}
+
+		table.Rows = append(table.Rows, []string{
+			check.Tunable,
+			escapeCell(check.Expected),
+			escapeCell(actual),
+			escapeCell(dashIfEmpty(check.Default)),
+			StatusLabel(check.Status),
+			check.Severity,
+			escapeCell(check.Rationale),
+		})
+	}
+
+	return table
+}
+
+// StatusLabel returns the display label for a tunable status.
+func StatusLabel(status model.TunableStatus) string {
+	switch status {
+	case model.TunableCompliant:
+		return "Compliant"
+	case model.TunableDeviation:
+		return "Deviation"
+	case model.TunableUnknown:
+		return "Unknown"
+	default:
+		return string(status)
+	}
+}
+
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// escapeCell escapes characters that would break a markdown table cell.
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,178 @@
+// Package tunables compares sysctl tunables with a hardening baseline.
+//
+// opnDossier ships an embedded baseline listing hardened values, the FreeBSD
+// defaults that apply when a tunable is not set, and the rationale for each
+// recommendation. Users can supply their own baseline file in the same YAML format.
+package tunables
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Error definitions for baseline loading.
+var (
+	// ErrInvalidBaseline indicates that a baseline file is malformed.
+	ErrInvalidBaseline = errors.New("invalid sysctl baseline")
+)
+
+// Severities accepted in baseline files.
+var severities = []string{"critical", "high", "medium", "low", "info"} //nolint:gochecknoglobals // validation table
+
+//go:embed baseline.yaml
+var defaultBaselineYAML []byte
+
+// defaultBaseline parses the embedded baseline once.
+var defaultBaseline = sync.OnceValue(func() *Baseline { //nolint:gochecknoglobals // lazily parsed embedded data
+	baseline, err := Parse(bytes.NewReader(defaultBaselineYAML))
+	if err != nil {
+		panic(fmt.Sprintf("embedded sysctl baseline is invalid: %v", err))
+	}
+
+	return baseline
+})
+
+// Expectation is the hardened value expected for a single tunable.
+type Expectation struct {
+	Tunable   string `yaml:"tunable"`
+	Expected  string `yaml:"expected"`
+	Default   string `yaml:"default"`
+	Severity  string `yaml:"severity"`
+	Rationale string `yaml:"rationale"`
+}
+
+// Baseline is a named set of tunable expectations.
+type Baseline struct {
+	Name     string        `yaml:"name"`
+	Tunables []Expectation `yaml:"tunables"`
+}
+
+// Default returns the embedded opnDossier hardening baseline.
+// The returned baseline is shared and must not be modified.
+func Default() *Baseline {
+	return defaultBaseline()
+}
+
+// Load reads a baseline from a YAML file.
+func Load(path string) (*Baseline, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sysctl baseline: %w", err)
+	}
+	defer f.Close()
+
+	baseline, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return baseline, nil
+}
+
+// Parse decodes and validates a baseline in YAML format.
+// Severities default to "medium" and are normalized to lower case.
+func Parse(r io.Reader) (*Baseline, error) {
+	var baseline Baseline
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBaseline, err)
+	}
+
+	if len(baseline.Tunables) == 0 {
+		return nil, fmt.Errorf("%w: no tunables defined", ErrInvalidBaseline)
+	}
+
+	seen := make(map[string]bool, len(baseline.Tunables))
+
+	for i := range baseline.Tunables {
+		exp := &baseline.Tunables[i]
+		exp.Tunable = strings.TrimSpace(exp.Tunable)
+		exp.Severity = strings.ToLower(strings.TrimSpace(exp.Severity))
+
+		switch {
+		case exp.Tunable == "":
+			return nil, fmt.Errorf("%w: tunables[%d] has no name", ErrInvalidBaseline, i)
+		case exp.Expected == "":
+			return nil, fmt.Errorf("%w: %s has no expected value", ErrInvalidBaseline, exp.Tunable)
+		case seen[exp.Tunable]:
+			return nil, fmt.Errorf("%w: %s is listed more than once", ErrInvalidBaseline, exp.Tunable)
+		}
+
+		seen[exp.Tunable] = true
+
+		if exp.Severity == "" {
+			exp.Severity = "medium"
+		}
+
+		if !slices.Contains(severities, exp.Severity) {
+			return nil, fmt.Errorf("%w: %s has unknown severity %q", ErrInvalidBaseline, exp.Tunable, exp.Severity)
+		}
+	}
+
+	return &baseline, nil
+}
+
+// Compare checks the configured tunables against the baseline, returning one
+// result per baseline entry in baseline order. A nil baseline yields no results.
+func Compare(baseline *Baseline, items []model.SysctlItem) []model.TunableCheck {
+	if baseline == nil {
+		return nil
+	}
+
+	configured := make(map[string]string, len(items))
+	for _, item := range items {
+		configured[strings.TrimSpace(item.Tunable)] = strings.TrimSpace(item.Value)
+	}
+
+	checks := make([]model.TunableCheck, 0, len(baseline.Tunables))
+
+	for _, exp := range baseline.Tunables {
+		check := model.TunableCheck{
+			Tunable:   exp.Tunable,
+			Expected:  exp.Expected,
+			Actual:    configured[exp.Tunable],
+			Default:   exp.Default,
+			Severity:  exp.Severity,
+			Rationale: exp.Rationale,
+		}
+
+		switch effective := check.Effective(); {
+		case effective == "":
+			check.Status = model.TunableUnknown
+		case effective == exp.Expected:
+			check.Status = model.TunableCompliant
+		default:
+			check.Status = model.TunableDeviation
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// Deviations returns the checks whose effective value differs from the baseline.
+func Deviations(checks []model.TunableCheck) []model.TunableCheck {
+	var deviations []model.TunableCheck
+
+	for _, check := range checks {
+		if check.Status == model.TunableDeviation {
+			deviations = append(deviations, check)
+		}
+	}
+
+	return deviations
+}
@@ -0,0 +1,146 @@
+package tunables
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	baseline := Default()
+	require.NotNil(t, baseline)
+	assert.NotEmpty(t, baseline.Name)
+
+	byName := make(map[string]Expectation)
+	for _, exp := range baseline.Tunables {
+		assert.NotEmpty(t, exp.Rationale, "%s needs a rationale", exp.Tunable)
+		byName[exp.Tunable] = exp
+	}
+
+	for _, name := range []string{
+		"net.inet.tcp.blackhole",
+		"net.inet.udp.blackhole",
+		"net.inet.ip.random_id",
+		"security.bsd.see_other_uids",
+	} {
+		assert.Contains(t, byName, name)
+	}
+
+	assert.Equal(t, "2", byName["net.inet.tcp.blackhole"].Expected)
+	assert.Same(t, baseline, Default(), "the embedded baseline is parsed once")
+}
+
+func TestParse(t *testing.T) {
+	baseline, err := Parse(strings.NewReader(`
+name: custom
+tunables:
+  - tunable: " kern.randompid "
+    expected: "1"
+    severity: HIGH
+  - tunable: net.inet.tcp.blackhole
+    expected: "2"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "custom", baseline.Name)
+	assert.Equal(t, "kern.randompid", baseline.Tunables[0].Tunable)
+	assert.Equal(t, "high", baseline.Tunables[0].Severity)
+	assert.Equal(t, "medium", baseline.Tunables[1].Severity)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errMsg string
+	}{
+		{"empty", "", "EOF"},
+		{"no tunables", "name: empty\ntunables: []\n", "no tunables defined"},
+		{"unknown field", "tunables:\n  - tunable: a\n    expected: \"1\"\n    value: \"2\"\n", "field value not found"},
+		{"missing name", "tunables:\n  - expected: \"1\"\n", "tunables[0] has no name"},
+		{"missing expected", "tunables:\n  - tunable: a\n", "a has no expected value"},
+		{"duplicate", "tunables:\n  - {tunable: a, expected: \"1\"}\n  - {tunable: a, expected: \"2\"}\n", "listed more than once"},
+		{"bad severity", "tunables:\n  - {tunable: a, expected: \"1\", severity: urgent}\n", "unknown severity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			require.ErrorIs(t, err, ErrInvalidBaseline)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("tunables:\n  - {tunable: a, expected: \"1\"}\n"), 0o600))
+
+	baseline, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, baseline.Tunables, 1)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Baseline{Tunables: []Expectation{
+		{Tunable: "a.configured.ok", Expected: "1", Default: "0", Severity: "low"},
+		{Tunable: "b.configured.bad", Expected: "1", Default: "1", Severity: "high"},
+		{Tunable: "c.default.ok", Expected: "0", Default: "0"},
+		{Tunable: "d.default.bad", Expected: "2", Default: "0"},
+		{Tunable: "e.literal.default", Expected: "2", Default: "0"},
+		{Tunable: "f.unknown", Expected: "1"},
+	}}
+
+	checks := Compare(baseline, []model.SysctlItem{
+		{Tunable: "a.configured.ok", Value: "1"},
+		{Tunable: "b.configured.bad", Value: " 0 "},
+		{Tunable: "e.literal.default", Value: "default"},
+		{Tunable: "unrelated", Value: "7"},
+	})
+	require.Len(t, checks, 6)
+
+	statuses := make([]model.TunableStatus, 0, len(checks))
+	for _, check := range checks {
+		statuses = append(statuses, check.Status)
+	}
+
+	assert.Equal(t, []model.TunableStatus{
+		model.TunableCompliant,
+		model.TunableDeviation,
+		model.TunableCompliant,
+		model.TunableDeviation,
+		model.TunableDeviation,
+		model.TunableUnknown,
+	}, statuses)
+
+	assert.Equal(t, "0", checks[1].Actual)
+	assert.True(t, checks[1].Configured())
+	assert.False(t, checks[4].Configured())
+	assert.Equal(t, "0", checks[4].Effective())
+
+	deviations := Deviations(checks)
+	require.Len(t, deviations, 3)
+	assert.Equal(t, "b.configured.bad", deviations[0].Tunable)
+
+	assert.Nil(t, Compare(nil, nil))
+}
+
+func TestComplianceTable(t *testing.T) {
+	checks := Compare(&Baseline{Tunables: []Expectation{
+		{Tunable: "a", Expected: "1", Default: "0", Severity: "low", Rationale: "pipes | escaped"},
+		{Tunable: "b", Expected: "1", Severity: "medium"},
+	}}, []model.SysctlItem{{Tunable: "a", Value: "0"}})
+
+	table := ComplianceTable(checks)
+	assert.Equal(t, []string{"Tunable", "Expected", "Actual", "Default", "Status", "Severity", "Rationale"}, table.Header)
+	assert.Equal(t, []string{"a", "1", "0", "0", "Deviation", "low", "pipes \\| escaped"}, table.Rows[0])
+	assert.Equal(t, []string{"b", "1", "(not set)", "-", "Unknown", "medium", ""}, table.Rows[1])
+}
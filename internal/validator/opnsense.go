@@ -13,8 +13,17 @@ import (
 	"strings"
 
 	"github.com/EvilBit-Labs/opnDossier/internal/model"
+	"github.com/EvilBit-Labs/opnDossier/internal/waiver"
 )
 
+// Source names the validator as the source of findings, for example in waivers.
+const Source = "validator"
+
+// listIndex matches the list indexes of a field path.
+//
+//nolint:gochecknoglobals // compiled once
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
 // ValidationError represents a configuration validation error.
 type ValidationError struct {
 	Field   string
@@ -25,6 +34,68 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error for field '%s': %s", e.Field, e.Message)
 }
 
+// Subject returns the values a waiver matches a validation error on. The field is the component.
+func (e ValidationError) Subject() waiver.Subject {
+	return waiver.Subject{
+		Fingerprint: waiver.Fingerprint(Source, "", e.Field, e.Message),
+		Source:      Source,
+		Component:   e.Field,
+	}
+}
+
+// Check identifies the check that reported the error: the field with list indexes removed and
+// interface names replaced by "*", such as "filter.rule[].type" or "interfaces.*.ipaddr". Errors
+// of the same check on different elements share it.
+func (e ValidationError) Check() string {
+	check := listIndex.ReplaceAllString(e.Field, "[]")
+
+	parts := strings.SplitN(check, ".", 3)
+	if len(parts) >= 2 && (parts[0] == "interfaces" || parts[0] == "dhcpd") {
+		parts[1] = "*"
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Configuration sections checked by ValidateOpnSenseDocument.
+const (
+	SectionSystem         = "system"
+	SectionInterfaces     = "interfaces"
+	SectionDhcpd          = "dhcpd"
+	SectionFilter         = "filter"
+	SectionNat            = "nat"
+	SectionUsersAndGroups = "users-groups"
+	SectionSysctl         = "sysctl"
+)
+
+// Sections returns the configuration sections ValidateOpnSenseDocument checks, in the order it
+// checks them.
+func Sections() []string {
+	return []string{
+		SectionSystem,
+		SectionInterfaces,
+		SectionDhcpd,
+		SectionFilter,
+		SectionNat,
+		SectionUsersAndGroups,
+		SectionSysctl,
+	}
+}
+
+// Section returns the configuration section the error belongs to, such as "filter" for
+// "filter.rule[3].type". System users and groups are checked as a section of their own.
+func (e ValidationError) Section() string {
+	if strings.HasPrefix(e.Field, "system.user[") || strings.HasPrefix(e.Field, "system.group[") {
+		return SectionUsersAndGroups
+	}
+
+	if i := strings.IndexAny(e.Field, ".["); i >= 0 {
+		return e.Field[:i]
+	}
+
+	return e.Field
+}
+
 // ValidateOpnSenseDocument validates an entire OPNsense configuration document and returns all detected validation errors.
 // It checks system settings, network interfaces, DHCP server, firewall rules, NAT rules, users and groups, and sysctl tunables for correctness and consistency.
 func ValidateOpnSenseDocument(o *model.OpnSenseDocument) []ValidationError {
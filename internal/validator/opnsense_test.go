@@ -136,6 +136,50 @@ func TestStripIPSuffix(t *testing.T) {
 	}
 }
 
+func TestValidationError_Check(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"system.hostname", "system.hostname"},
+		{"filter.rule[3].type", "filter.rule[].type"},
+		{"nat.outbound.rule[12].target", "nat.outbound.rule[].target"},
+		{"interfaces.wan.ipaddr", "interfaces.*.ipaddr"},
+		{"dhcpd.opt2", "dhcpd.*"},
+		{"dhcpd.lan.range.from", "dhcpd.*.range.from"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ValidationError{Field: tt.field}.Check())
+		})
+	}
+}
+
+func TestValidationError_Section(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"system.hostname", SectionSystem},
+		{"system.user[2].uid", SectionUsersAndGroups},
+		{"system.group[0].name", SectionUsersAndGroups},
+		{"interfaces.wan.ipaddr", SectionInterfaces},
+		{"dhcpd.opt2", SectionDhcpd},
+		{"filter.rule[3].type", SectionFilter},
+		{"nat.outbound.mode", SectionNat},
+		{"sysctl[1].tunable", SectionSysctl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			section := ValidationError{Field: tt.field}.Section()
+			assert.Equal(t, tt.expected, section)
+			assert.Contains(t, Sections(), section)
+		})
+	}
+}
+
 func TestValidateFilter_NetworkValidation(t *testing.T) {
 	interfaces := &model.Interfaces{
 		Items: map[string]model.Interface{
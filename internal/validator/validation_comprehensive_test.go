@@ -503,9 +503,7 @@ func TestSampleConfig2_ZeroValidationErrors(t *testing.T) {
 			Hostname: "TestHost2",
 			Domain:   "test.local",
 			WebGUI:   model.WebGUIConfig{Protocol: "https"},
-			SSH: struct {
-				Group string `xml:"group" json:"group" yaml:"group" validate:"required"`
-			}{Group: "admins"},
+			SSH:      model.SSHConfig{Group: "admins"},
 		},
 		Interfaces: model.Interfaces{
 			Items: map[string]model.Interface{
@@ -0,0 +1,276 @@
+// Package waiver suppresses accepted findings.
+//
+// A waiver file lists accepted risks in YAML. Each waiver matches findings by fingerprint,
+// source (the core processor, the validator or a compliance plugin), control ID or component
+// pattern and records why the risk is accepted, who owns it and when the acceptance expires:
+//
+//	waivers:
+//	  - id: WVR-001
+//	    source: sans
+//	    control: SANS-FW-002
+//	    component: firewall-*
+//	    justification: Legacy rules are documented in the change log
+//	    owner: network-team
+//	    expires: 2026-12-31
+//
+// Waived findings are reported separately rather than dropped. Expired waivers no longer match
+// and are reported as findings of their own.
+package waiver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidWaivers indicates a malformed waiver file.
+var ErrInvalidWaivers = errors.New("invalid waiver file")
+
+// dateLayout is the format of expiry dates.
+const dateLayout = "2006-01-02"
+
+// fingerprintLength is the number of hex digits of a fingerprint.
+const fingerprintLength = 16
+
+// Date is a calendar day in UTC.
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a date in YYYY-MM-DD format.
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(dateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+
+	return Date{Time: t}, nil
+}
+
+// String formats the date as YYYY-MM-DD.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return d.Format(dateLayout)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Date) UnmarshalYAML(node *yaml.Node) error {
+	date, err := ParseDate(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	*d = date
+
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Date) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Waiver accepts the risk of the findings it matches until it expires. Every matcher that is set
+// must match; at least one is required.
+type Waiver struct {
+	ID string `json:"id" yaml:"id"`
+
+	// Fingerprint matches the fingerprint of a single finding.
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+	// Source matches the origin of a finding: "processor", "validator", "recon" or a plugin name.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Control matches the control ID a finding refers to.
+	Control string `json:"control,omitempty" yaml:"control,omitempty"`
+	// Component matches the component of a finding exactly or as a pattern in which * matches any
+	// sequence of characters and ? any single character. All other characters, such as the
+	// brackets of filter.rule[3], match themselves.
+	Component string `json:"component,omitempty" yaml:"component,omitempty"`
+
+	Justification string `json:"justification" yaml:"justification"`
+	Owner         string `json:"owner"         yaml:"owner"`
+	Expires       Date   `json:"expires"       yaml:"expires"`
+}
+
+// Subject identifies a finding for matching.
+type Subject struct {
+	Fingerprint string
+	Source      string
+	Control     string
+	Component   string
+}
+
+// Expired reports whether the waiver has expired at the given time. A waiver is valid through
+// its expiry date.
+func (w *Waiver) Expired(now time.Time) bool {
+	return !now.UTC().Before(w.Expires.AddDate(0, 0, 1))
+}
+
+// Matches reports whether the waiver matches a finding, regardless of its expiry.
+func (w *Waiver) Matches(subject Subject) bool {
+	if w.Fingerprint != "" && !strings.EqualFold(w.Fingerprint, subject.Fingerprint) {
+		return false
+	}
+
+	if w.Source != "" && !strings.EqualFold(w.Source, subject.Source) {
+		return false
+	}
+
+	if w.Control != "" && !strings.EqualFold(w.Control, subject.Control) {
+		return false
+	}
+
+	if w.Component != "" && !matchComponent(w.Component, subject.Component) {
+		return false
+	}
+
+	return true
+}
+
+// componentEscaper escapes the characters that path.Match treats specially, other than the *
+// and ? wildcards.
+var componentEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`) //nolint:gochecknoglobals // Stateless replacer
+
+// matchComponent reports whether a component matches a waiver component pattern.
+func matchComponent(pattern, component string) bool {
+	if pattern == component {
+		return true
+	}
+
+	matched, err := path.Match(componentEscaper.Replace(pattern), component)
+
+	return err == nil && matched
+}
+
+// problem describes what is wrong with the waiver, or returns "" for a valid waiver.
+func (w *Waiver) problem() string {
+	switch {
+	case w.Fingerprint == "" && w.Source == "" && w.Control == "" && w.Component == "":
+		return "at least one of fingerprint, source, control or component is required"
+	case strings.TrimSpace(w.Justification) == "":
+		return "justification is required"
+	case strings.TrimSpace(w.Owner) == "":
+		return "owner is required"
+	case w.Expires.IsZero():
+		return "expires is required"
+	}
+
+	return ""
+}
+
+// Set is the content of a waiver file. A nil set waives nothing.
+type Set struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// Load reads a waiver file.
+func Load(path string) (*Set, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to open waiver file: %w", err)
+	}
+	defer f.Close()
+
+	set, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return set, nil
+}
+
+// Parse decodes and validates a waiver file in YAML format. Waivers without an ID are numbered.
+func Parse(r io.Reader) (*Set, error) {
+	var set Set
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&set); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWaivers, err)
+	}
+
+	ids := make(map[string]bool, len(set.Waivers))
+
+	for i := range set.Waivers {
+		w := &set.Waivers[i]
+		if w.ID = strings.TrimSpace(w.ID); w.ID == "" {
+			w.ID = fmt.Sprintf("waiver-%d", i+1)
+		}
+
+		if ids[w.ID] {
+			return nil, fmt.Errorf("%w: waiver %s is defined more than once", ErrInvalidWaivers, w.ID)
+		}
+
+		ids[w.ID] = true
+
+		if problem := w.problem(); problem != "" {
+			return nil, fmt.Errorf("%w: waiver %s: %s", ErrInvalidWaivers, w.ID, problem)
+		}
+	}
+
+	return &set, nil
+}
+
+// Match returns the first waiver that matches the finding and has not expired, or nil.
+func (s *Set) Match(subject Subject, now time.Time) *Waiver {
+	if s == nil {
+		return nil
+	}
+
+	for i := range s.Waivers {
+		w := &s.Waivers[i]
+		if !w.Expired(now) && w.Matches(subject) {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// Expired returns the waivers that have expired at the given time.
+func (s *Set) Expired(now time.Time) []Waiver {
+	if s == nil {
+		return nil
+	}
+
+	var expired []Waiver
+
+	for _, w := range s.Waivers {
+		if w.Expired(now) {
+			expired = append(expired, w)
+		}
+	}
+
+	return expired
+}
+
+// Fingerprint derives an identifier for a finding from the values that identify it, such as its
+// source, control and component. The fingerprint stays the same across runs as long as these
+// values do.
+func Fingerprint(parts ...string) string {
+	hash := sha256.New()
+
+	for _, part := range parts {
+		hash.Write([]byte(strings.TrimSpace(part)))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:fingerprintLength]
+}
@@ -0,0 +1,198 @@
+package waiver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWaivers = `
+waivers:
+  - id: WVR-001
+    source: sans
+    control: SANS-FW-002
+    justification: Legacy rules are documented elsewhere
+    owner: network-team
+    expires: 2030-06-30
+  - component: sysctl.net.inet.*
+    justification: Tunables are managed by the provider
+    owner: provider
+    expires: "2024-01-31"
+  - fingerprint: 0123456789ABCDEF
+    justification: Known
+    owner: alice
+    expires: 2030-01-01
+`
+
+func mustParse(t *testing.T, doc string) *Set {
+	t.Helper()
+
+	set, err := Parse(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	return set
+}
+
+func TestParse(t *testing.T) {
+	set := mustParse(t, testWaivers)
+	require.Len(t, set.Waivers, 3)
+
+	assert.Equal(t, "WVR-001", set.Waivers[0].ID)
+	assert.Equal(t, "waiver-2", set.Waivers[1].ID, "waivers without an ID are numbered")
+	assert.Equal(t, "2024-01-31", set.Waivers[1].Expires.String())
+
+	empty := mustParse(t, "")
+	assert.Empty(t, empty.Waivers)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		errMsg string
+	}{
+		{
+			name:   "no matcher",
+			doc:    "waivers: [{justification: j, owner: o, expires: 2030-01-01}]",
+			errMsg: "at least one of fingerprint, source, control or component is required",
+		},
+		{
+			name:   "no justification",
+			doc:    "waivers: [{source: sans, owner: o, expires: 2030-01-01}]",
+			errMsg: "justification is required",
+		},
+		{
+			name:   "no owner",
+			doc:    "waivers: [{source: sans, justification: j, expires: 2030-01-01}]",
+			errMsg: "owner is required",
+		},
+		{
+			name:   "no expiry",
+			doc:    "waivers: [{source: sans, justification: j, owner: o}]",
+			errMsg: "expires is required",
+		},
+		{
+			name:   "invalid expiry",
+			doc:    "waivers: [{source: sans, justification: j, owner: o, expires: next year}]",
+			errMsg: `invalid date "next year"`,
+		},
+		{
+			name: "duplicate ID",
+			doc: "waivers:\n  - {id: A, source: x, justification: j, owner: o, expires: 2030-01-01}\n" +
+				"  - {id: A, source: y, justification: j, owner: o, expires: 2030-01-01}",
+			errMsg: "waiver A is defined more than once",
+		},
+		{
+			name:   "unknown field",
+			doc:    "waivers: [{source: sans, reason: j, owner: o, expires: 2030-01-01}]",
+			errMsg: "field reason not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.doc))
+			require.ErrorIs(t, err, ErrInvalidWaivers)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestSet_Match(t *testing.T) {
+	set := mustParse(t, testWaivers)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    string
+	}{
+		{name: "source and control", subject: Subject{Source: "SANS", Control: "sans-fw-002"}, want: "WVR-001"},
+		{name: "partial match", subject: Subject{Source: "sans", Control: "SANS-FW-001"}},
+		{name: "fingerprint", subject: Subject{Fingerprint: "0123456789abcdef"}, want: "waiver-3"},
+		{name: "expired glob", subject: Subject{Component: "sysctl.net.inet.tcp.blackhole"}},
+		{name: "no match", subject: Subject{Source: "processor", Component: "system.webgui"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := set.Match(tt.subject, now)
+			if tt.want == "" {
+				assert.Nil(t, w)
+			} else {
+				require.NotNil(t, w)
+				assert.Equal(t, tt.want, w.ID)
+			}
+		})
+	}
+
+	var none *Set
+	assert.Nil(t, none.Match(Subject{Source: "sans"}, now))
+	assert.Empty(t, none.Expired(now))
+}
+
+func TestWaiver_MatchesComponent(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		component string
+		want      bool
+	}{
+		{pattern: "filter.rule[3]", component: "filter.rule[3]", want: true},
+		{pattern: "filter.rule[3]", component: "filter.rule[4]"},
+		{pattern: "filter.rule[3]", component: "filter.rule3"},
+		{pattern: "filter.rule[*]", component: "filter.rule[3]", want: true},
+		{pattern: "filter.rule[*]", component: "filter.rule[12+]", want: true},
+		{pattern: "filter.rule[?]", component: "filter.rule[3]", want: true},
+		{pattern: "filter.rule[?]", component: "filter.rule[12]"},
+		{pattern: "nat.inbound[*]", component: "filter.rule[3]"},
+		{pattern: "sysctl.*", component: "sysctl.net.inet.tcp.blackhole", want: true},
+		{pattern: `dhcpd\lan`, component: `dhcpd\lan`, want: true},
+		{pattern: "[", component: "[", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.component, func(t *testing.T) {
+			w := Waiver{Component: tt.pattern}
+			assert.Equal(t, tt.want, w.Matches(Subject{Component: tt.component}))
+		})
+	}
+}
+
+func TestWaiver_Expired(t *testing.T) {
+	expires, err := ParseDate("2025-03-01")
+	require.NoError(t, err)
+
+	w := Waiver{Expires: expires}
+	assert.False(t, w.Expired(time.Date(2025, 3, 1, 23, 59, 0, 0, time.UTC)), "valid through the expiry date")
+	assert.True(t, w.Expired(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)))
+
+	set := mustParse(t, testWaivers)
+	expired := set.Expired(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, expired, 1)
+	assert.Equal(t, "waiver-2", expired[0].ID)
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := Fingerprint("sans", "SANS-FW-001", "firewall-rules")
+	assert.Len(t, fingerprint, fingerprintLength)
+	assert.Equal(t, fingerprint, Fingerprint("sans", "SANS-FW-001", " firewall-rules "))
+	assert.NotEqual(t, fingerprint, Fingerprint("sans", "SANS-FW-001firewall-rules"))
+	assert.NotEqual(t, fingerprint, Fingerprint("sans", "SANS-FW-002", "firewall-rules"))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "waivers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testWaivers), 0o600))
+
+	set, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, set.Waivers, 3)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}